@@ -17,6 +17,8 @@ limitations under the License.
 package v1
 
 import (
+	"golang.org/x/exp/slices"
+
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 )
@@ -38,6 +40,14 @@ func (cluster *Cluster) GetSyncReplicasData() (syncReplicas int, electableSyncRe
 		syncReplicas = cluster.Spec.MinSyncReplicas
 	}
 
+	// When the user prefers durability over availability, the commits
+	// wait for min sync replicas even if they are not ready, including
+	// the case where every replica is down
+	if readyReplicas < cluster.Spec.MinSyncReplicas &&
+		cluster.GetReplicasUnavailablePolicy() == ReplicasUnavailablePolicyEnforceSync {
+		return cluster.Spec.MinSyncReplicas, cluster.getSyncReplicasCandidates()
+	}
+
 	// Lower to ready replicas if min sync replicas is too high
 	// (this is a self-healing procedure that prevents from a
 	// temporarily unresponsive system)
@@ -63,11 +73,26 @@ func (cluster *Cluster) GetSyncReplicasData() (syncReplicas int, electableSyncRe
 	return syncReplicas, electableSyncReplicas
 }
 
+// getSyncReplicasCandidates gets the names of all the instances that could
+// be synchronous replicas, regardless of their health and of the election
+// constraints, so that the primary waits for them to be back
+func (cluster *Cluster) getSyncReplicasCandidates() []string {
+	var candidates []string
+	for _, instance := range cluster.Status.InstanceNames {
+		if cluster.Status.CurrentPrimary != instance && !slices.Contains(cluster.Status.ReadReplicas, instance) {
+			candidates = append(candidates, instance)
+		}
+	}
+
+	return candidates
+}
+
 // getElectableSyncReplicas computes the names of the instances that can be elected to sync replicas
 func (cluster *Cluster) getElectableSyncReplicas() []string {
 	var nonPrimaryInstances []string
 	for _, instance := range cluster.Status.InstancesStatus[utils.PodHealthy] {
-		if cluster.Status.CurrentPrimary != instance {
+		// Read replicas can lag freely, so they are never synchronous
+		if cluster.Status.CurrentPrimary != instance && !slices.Contains(cluster.Status.ReadReplicas, instance) {
 			nonPrimaryInstances = append(nonPrimaryInstances, instance)
 		}
 	}
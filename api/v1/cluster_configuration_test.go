@@ -31,6 +31,14 @@ var _ = Describe("ensuring the correctness of synchronous replica data calculati
 		Expect(names).To(Equal([]string{"example-2", "example-3"}))
 	})
 
+	It("should never elect the read replicas", func() {
+		cluster := createFakeCluster("example")
+		cluster.Status.ReadReplicas = []string{"example-3"}
+		number, names := cluster.GetSyncReplicasData()
+		Expect(number).To(Equal(1))
+		Expect(names).To(Equal([]string{"example-2"}))
+	})
+
 	It("should return only the pod in the different AZ", func() {
 		const (
 			primaryPod     = "example-1"
@@ -79,4 +87,34 @@ var _ = Describe("ensuring the correctness of synchronous replica data calculati
 		Expect(names).To(HaveLen(0))
 		Expect(cluster.Spec.MinSyncReplicas).To(Equal(1))
 	})
+	It("should keep waiting for the synchronous replicas when the policy enforces them", func() {
+		cluster := createFakeCluster("example")
+		cluster.Spec.ReplicasUnavailablePolicy = ReplicasUnavailablePolicyEnforceSync
+		cluster.Status = ClusterStatus{
+			CurrentPrimary: "example-1",
+			InstanceNames:  []string{"example-1", "example-2", "example-3"},
+			InstancesStatus: map[utils.PodStatus][]string{
+				utils.PodHealthy: {"example-1"},
+				utils.PodFailed:  {"example-2", "example-3"},
+			},
+		}
+		number, names := cluster.GetSyncReplicasData()
+
+		Expect(number).To(Equal(1))
+		Expect(names).To(Equal([]string{"example-2", "example-3"}))
+	})
+
+	It("should use the ready replicas when enough of them are available", func() {
+		cluster := createFakeCluster("example")
+		cluster.Spec.ReplicasUnavailablePolicy = ReplicasUnavailablePolicyEnforceSync
+		cluster.Status.InstanceNames = []string{"example-1", "example-2", "example-3"}
+		cluster.Status.InstancesStatus = map[utils.PodStatus][]string{
+			utils.PodHealthy: {"example-1", "example-2"},
+			utils.PodFailed:  {"example-3"},
+		}
+		number, names := cluster.GetSyncReplicasData()
+
+		Expect(number).To(Equal(1))
+		Expect(names).To(Equal([]string{"example-2"}))
+	})
 })
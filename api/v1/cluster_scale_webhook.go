@@ -0,0 +1,108 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// clusterScaleWebhookPath is the path of the webhook validating the
+// changes made through the scale subresource of a cluster
+const clusterScaleWebhookPath = "/validate-postgresql-cnpg-io-v1-cluster-scale"
+
+// +kubebuilder:webhook:webhookVersions={v1},admissionReviewVersions={v1},verbs=update,path=/validate-postgresql-cnpg-io-v1-cluster-scale,mutating=false,failurePolicy=fail,groups=postgresql.cnpg.io,resources=clusters/scale,versions=v1,name=vclusterscale.kb.io,sideEffects=None
+
+// clusterScaleValidator validates the number of instances set through
+// the scale subresource, i.e. by `kubectl scale`, as those requests
+// don't reach the validating webhook of the Cluster resource
+type clusterScaleValidator struct {
+	client client.Client
+}
+
+// SetupClusterScaleWebhookWithManager registers the webhook validating
+// the scale subresource of the clusters
+func SetupClusterScaleWebhookWithManager(mgr ctrl.Manager) {
+	mgr.GetWebhookServer().Register(
+		clusterScaleWebhookPath,
+		&webhook.Admission{Handler: &clusterScaleValidator{client: mgr.GetClient()}},
+	)
+}
+
+// Handle implements the admission.Handler interface
+func (v *clusterScaleValidator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	var scale autoscalingv1.Scale
+	if err := json.Unmarshal(req.Object.Raw, &scale); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	var cluster Cluster
+	if err := v.client.Get(ctx, types.NamespacedName{Namespace: req.Namespace, Name: req.Name}, &cluster); err != nil {
+		return admission.Errored(http.StatusInternalServerError,
+			fmt.Errorf("while getting the cluster to be scaled: %w", err))
+	}
+
+	clusterLog.Info("validate scale", "name", cluster.Name, "namespace", cluster.Namespace,
+		"instances", scale.Spec.Replicas)
+
+	cluster.Spec.Instances = int(scale.Spec.Replicas)
+	allErrs := cluster.validateScale()
+	if len(allErrs) == 0 {
+		return admission.Allowed("")
+	}
+
+	statusErr := apierrors.NewInvalid(
+		schema.GroupKind{Group: "cluster.cnpg.io", Kind: "Cluster"},
+		cluster.Name, allErrs)
+	return admission.Response{
+		AdmissionResponse: admissionv1.AdmissionResponse{
+			Allowed: false,
+			Result:  &statusErr.ErrStatus,
+		},
+	}
+}
+
+// validateScale groups the validation rules depending on the
+// number of instances of the cluster
+func (r *Cluster) validateScale() (allErrs field.ErrorList) {
+	validations := []func() field.ErrorList{
+		r.validateMaxSyncReplicas,
+		r.validateSyncReplicasInstances,
+		r.validateQuorumInstances,
+		r.validateDelayedReplicas,
+		r.validateReadReplicas,
+	}
+
+	for _, validate := range validations {
+		allErrs = append(allErrs, validate()...)
+	}
+
+	return allErrs
+}
@@ -0,0 +1,117 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"encoding/json"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("cluster scale webhook", func() {
+	var validator *clusterScaleValidator
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(AddToScheme(scheme)).To(Succeed())
+
+		cluster := &Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+			Spec: ClusterSpec{
+				Instances:       3,
+				MinSyncReplicas: 1,
+				MaxSyncReplicas: 1,
+			},
+		}
+		validator = &clusterScaleValidator{
+			client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(cluster).Build(),
+		}
+	})
+
+	newScaleRequest := func(replicas int32) admission.Request {
+		scale := autoscalingv1.Scale{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+			Spec:       autoscalingv1.ScaleSpec{Replicas: replicas},
+		}
+		raw, err := json.Marshal(scale)
+		Expect(err).ToNot(HaveOccurred())
+
+		return admission.Request{
+			AdmissionRequest: admissionv1.AdmissionRequest{
+				Name:        "cluster-example",
+				Namespace:   "default",
+				SubResource: "scale",
+				Object:      runtime.RawExtension{Raw: raw},
+			},
+		}
+	}
+
+	It("allows the instances to be increased", func() {
+		Expect(validator.Handle(context.Background(), newScaleRequest(5)).Allowed).To(BeTrue())
+	})
+
+	It("rejects the instances that can't satisfy the synchronous replicas", func() {
+		response := validator.Handle(context.Background(), newScaleRequest(1))
+		Expect(response.Allowed).To(BeFalse())
+		Expect(response.Result.Message).To(ContainSubstring("minSyncReplicas is 1"))
+	})
+})
+
+var _ = Describe("instances needed by the synchronous replicas", func() {
+	It("complains if the standbys are less than minSyncReplicas", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Instances:       2,
+				MinSyncReplicas: 2,
+				MaxSyncReplicas: 2,
+			},
+		}
+		errors := cluster.validateSyncReplicasInstances()
+		Expect(errors).To(HaveLen(1))
+		Expect(errors[0].Detail).To(ContainSubstring("at least 3 instances are needed"))
+	})
+
+	It("accepts enough standbys", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Instances:       3,
+				MinSyncReplicas: 2,
+				MaxSyncReplicas: 2,
+			},
+		}
+		Expect(cluster.validateSyncReplicasInstances()).To(BeEmpty())
+	})
+
+	It("doesn't check the clusters without a minimum of synchronous replicas", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Instances:       1,
+				MaxSyncReplicas: 1,
+			},
+		}
+		Expect(cluster.validateSyncReplicasInstances()).To(BeEmpty())
+	})
+})
@@ -0,0 +1,225 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// clusterStorageWebhookPath is the path of the webhook validating that
+// the storage of a cluster can't be shared with other clusters
+const clusterStorageWebhookPath = "/validate-postgresql-cnpg-io-v1-cluster-storage"
+
+// +kubebuilder:webhook:webhookVersions={v1},admissionReviewVersions={v1},verbs=create;update,path=/validate-postgresql-cnpg-io-v1-cluster-storage,mutating=false,failurePolicy=fail,groups=postgresql.cnpg.io,resources=clusters,versions=v1,name=vclusterstorage.kb.io,sideEffects=None
+
+// clusterStorageValidator validates that the PVCs of a cluster can't be
+// used by the other clusters in the same namespace. This requires the
+// list of the existing clusters, which isn't available to the validating
+// webhook of the Cluster resource
+type clusterStorageValidator struct {
+	client client.Client
+}
+
+// SetupClusterStorageWebhookWithManager registers the webhook validating
+// that the storage of the clusters is not shared
+func SetupClusterStorageWebhookWithManager(mgr ctrl.Manager) {
+	mgr.GetWebhookServer().Register(
+		clusterStorageWebhookPath,
+		&webhook.Admission{Handler: &clusterStorageValidator{client: mgr.GetClient()}},
+	)
+}
+
+// Handle implements the admission.Handler interface
+func (v *clusterStorageValidator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	var cluster Cluster
+	if err := json.Unmarshal(req.Object.Raw, &cluster); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+
+	// A cluster being deleted must be free to have its finalizers removed
+	if !cluster.DeletionTimestamp.IsZero() {
+		return admission.Allowed("")
+	}
+
+	var clusters ClusterList
+	if err := v.client.List(ctx, &clusters, client.InNamespace(req.Namespace)); err != nil {
+		return admission.Errored(http.StatusInternalServerError,
+			fmt.Errorf("while listing the clusters: %w", err))
+	}
+
+	clusterLog.Info("validate storage", "name", cluster.Name, "namespace", req.Namespace)
+
+	allErrs := cluster.ValidateStorageOverlap(clusters.Items)
+
+	// The clusters which are already sharing their storage can still be
+	// updated, as long as the update doesn't add any new overlap
+	if req.Operation == admissionv1.Update && len(allErrs) > 0 {
+		var oldCluster Cluster
+		if err := json.Unmarshal(req.OldObject.Raw, &oldCluster); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		if len(allErrs) <= len(oldCluster.ValidateStorageOverlap(clusters.Items)) {
+			return admission.Allowed("")
+		}
+	}
+
+	if len(allErrs) == 0 {
+		return admission.Allowed("")
+	}
+
+	statusErr := apierrors.NewInvalid(
+		schema.GroupKind{Group: "cluster.cnpg.io", Kind: "Cluster"},
+		cluster.Name, allErrs)
+	return admission.Response{
+		AdmissionResponse: admissionv1.AdmissionResponse{
+			Allowed: false,
+			Result:  &statusErr.ErrStatus,
+		},
+	}
+}
+
+// ValidateStorageOverlap checks that the PVCs of the cluster can't be
+// used by any of the passed clusters, either because they could have
+// the same names or because they are bound to the same persistent volume
+func (r *Cluster) ValidateStorageOverlap(clusters []Cluster) field.ErrorList {
+	var result field.ErrorList
+
+	for idx := range clusters {
+		other := &clusters[idx]
+		if other.Name == r.Name {
+			continue
+		}
+
+		if pvcNamesOverlap(r, other) {
+			result = append(result, field.Invalid(
+				field.NewPath("metadata", "name"),
+				r.Name,
+				fmt.Sprintf("the PVCs of this cluster could have the same names "+
+					"of the ones of cluster %q, because of their tablespaces", other.Name)))
+		}
+
+		otherVolumes := make(map[string]bool)
+		for _, volume := range other.getPinnedVolumes() {
+			otherVolumes[volume.name] = true
+		}
+		for _, volume := range r.getPinnedVolumes() {
+			if otherVolumes[volume.name] {
+				result = append(result, field.Invalid(
+					volume.path,
+					volume.name,
+					fmt.Sprintf("this persistent volume is already used by cluster %q", other.Name)))
+			}
+		}
+	}
+
+	return result
+}
+
+// pinnedVolume is a persistent volume that the PVCs of a cluster
+// are required to be bound to
+type pinnedVolume struct {
+	path *field.Path
+	name string
+}
+
+// getPinnedVolumes gets the persistent volumes which the PVCs
+// templates of the cluster require to be bound to
+func (r *Cluster) getPinnedVolumes() []pinnedVolume {
+	var result []pinnedVolume
+
+	addVolume := func(path *field.Path, storage *StorageConfiguration) {
+		if storage == nil || storage.PersistentVolumeClaimTemplate == nil ||
+			storage.PersistentVolumeClaimTemplate.VolumeName == "" {
+			return
+		}
+		result = append(result, pinnedVolume{
+			path: path.Child("pvcTemplate", "volumeName"),
+			name: storage.PersistentVolumeClaimTemplate.VolumeName,
+		})
+	}
+
+	addVolume(field.NewPath("spec", "storage"), &r.Spec.StorageConfiguration)
+	addVolume(field.NewPath("spec", "walStorage"), r.Spec.WalStorage)
+	for idx := range r.Spec.Tablespaces {
+		addVolume(field.NewPath("spec", "tablespaces").Index(idx).Child("storage"),
+			&r.Spec.Tablespaces[idx].Storage)
+	}
+
+	return result
+}
+
+// pvcNamesOverlap checks whether the PVCs of two clusters could have
+// the same names. The PVCs are named after the instances, i.e.
+// `<cluster>-<serial>`, followed by a suffix depending on their role,
+// so this can only happen when the name of a cluster looks like
+// `<other cluster>-<serial>-tbs-<prefix>` and the other cluster has a
+// tablespace whose name starts with `<prefix>-<serial>`
+func pvcNamesOverlap(first, second *Cluster) bool {
+	if len(first.Name) > len(second.Name) {
+		first, second = second, first
+	}
+
+	if !strings.HasPrefix(second.Name, first.Name+"-") {
+		return false
+	}
+
+	rest := strings.TrimPrefix(second.Name, first.Name+"-")
+	serialLength := countLeadingDigits(rest)
+	if serialLength == 0 {
+		return false
+	}
+	rest = rest[serialLength:]
+
+	if !strings.HasPrefix(rest, TablespaceVolumeInfix) {
+		return false
+	}
+	tablespacePrefix := strings.TrimPrefix(rest, TablespaceVolumeInfix) + "-"
+
+	for _, tablespace := range first.Spec.Tablespaces {
+		tablespaceName := strings.TrimPrefix(tablespace.GetVolumeSuffix(), TablespaceVolumeInfix)
+		if strings.HasPrefix(tablespaceName, tablespacePrefix) &&
+			countLeadingDigits(strings.TrimPrefix(tablespaceName, tablespacePrefix)) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// countLeadingDigits counts the decimal digits at the start of a string
+func countLeadingDigits(value string) int {
+	for idx, char := range value {
+		if char < '0' || char > '9' {
+			return idx
+		}
+	}
+
+	return len(value)
+}
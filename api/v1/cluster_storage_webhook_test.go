@@ -0,0 +1,158 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"encoding/json"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("cluster storage webhook", func() {
+	var validator *clusterStorageValidator
+
+	pinnedStorage := func(volumeName string) StorageConfiguration {
+		return StorageConfiguration{
+			Size: "1Gi",
+			PersistentVolumeClaimTemplate: &corev1.PersistentVolumeClaimSpec{
+				VolumeName: volumeName,
+			},
+		}
+	}
+
+	BeforeEach(func() {
+		scheme := runtime.NewScheme()
+		Expect(AddToScheme(scheme)).To(Succeed())
+
+		cluster := &Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+			Spec: ClusterSpec{
+				Instances:            1,
+				StorageConfiguration: pinnedStorage("pv-data"),
+			},
+		}
+		validator = &clusterStorageValidator{
+			client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(cluster).Build(),
+		}
+	})
+
+	newClusterRequest := func(operation admissionv1.Operation, cluster, oldCluster *Cluster) admission.Request {
+		raw, err := json.Marshal(cluster)
+		Expect(err).ToNot(HaveOccurred())
+
+		request := admission.Request{
+			AdmissionRequest: admissionv1.AdmissionRequest{
+				Name:      cluster.Name,
+				Namespace: "default",
+				Operation: operation,
+				Object:    runtime.RawExtension{Raw: raw},
+			},
+		}
+		if oldCluster != nil {
+			oldRaw, err := json.Marshal(oldCluster)
+			Expect(err).ToNot(HaveOccurred())
+			request.OldObject = runtime.RawExtension{Raw: oldRaw}
+		}
+
+		return request
+	}
+
+	It("allows a cluster using its own persistent volumes", func() {
+		cluster := &Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-other", Namespace: "default"},
+			Spec: ClusterSpec{
+				Instances:            1,
+				StorageConfiguration: pinnedStorage("pv-other"),
+			},
+		}
+		response := validator.Handle(context.Background(), newClusterRequest(admissionv1.Create, cluster, nil))
+		Expect(response.Allowed).To(BeTrue())
+	})
+
+	It("rejects a cluster using the persistent volume of another cluster", func() {
+		cluster := &Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-other", Namespace: "default"},
+			Spec: ClusterSpec{
+				Instances:            1,
+				StorageConfiguration: pinnedStorage("pv-other"),
+				WalStorage:           &StorageConfiguration{},
+			},
+		}
+		*cluster.Spec.WalStorage = pinnedStorage("pv-data")
+
+		response := validator.Handle(context.Background(), newClusterRequest(admissionv1.Create, cluster, nil))
+		Expect(response.Allowed).To(BeFalse())
+		Expect(response.Result.Message).To(ContainSubstring("spec.walStorage.pvcTemplate.volumeName"))
+		Expect(response.Result.Message).To(ContainSubstring(`cluster "cluster-example"`))
+	})
+
+	It("allows updating a cluster which was already sharing its storage", func() {
+		cluster := &Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-other", Namespace: "default"},
+			Spec: ClusterSpec{
+				Instances:            1,
+				StorageConfiguration: pinnedStorage("pv-data"),
+			},
+		}
+		updatedCluster := cluster.DeepCopy()
+		updatedCluster.Spec.Instances = 2
+
+		response := validator.Handle(context.Background(),
+			newClusterRequest(admissionv1.Update, updatedCluster, cluster))
+		Expect(response.Allowed).To(BeTrue())
+	})
+})
+
+var _ = Describe("PVC names overlap", func() {
+	It("detects the clusters whose PVCs could have the same names of the ones of a tablespace", func() {
+		cluster := Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "app"},
+			Spec: ClusterSpec{
+				Tablespaces: []TablespaceConfiguration{{Name: "idx_1"}},
+			},
+		}
+		other := Cluster{ObjectMeta: metav1.ObjectMeta{Name: "app-1-tbs-idx"}}
+
+		errors := other.ValidateStorageOverlap([]Cluster{cluster})
+		Expect(errors).To(HaveLen(1))
+		Expect(errors[0].Detail).To(ContainSubstring(`cluster "app"`))
+		Expect(cluster.ValidateStorageOverlap([]Cluster{other})).To(HaveLen(1))
+	})
+
+	It("accepts clusters with similar names which can't clash", func() {
+		cluster := Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "app"},
+			Spec: ClusterSpec{
+				Tablespaces: []TablespaceConfiguration{{Name: "idx"}},
+			},
+		}
+		Expect(cluster.ValidateStorageOverlap([]Cluster{
+			{ObjectMeta: metav1.ObjectMeta{Name: "app-1-tbs-idx"}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "app-2"}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "app-test"}},
+		})).To(BeEmpty())
+	})
+})
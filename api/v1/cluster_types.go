@@ -20,12 +20,14 @@ import (
 	"context"
 	"fmt"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/strings/slices"
 
 	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
@@ -51,6 +53,10 @@ const (
 	// get the name of the application user secret
 	ApplicationUserSecretSuffix = "-app"
 
+	// MonitoringUserSecretSuffix is the suffix appended to the cluster name to
+	// get the name of the monitoring role secret
+	MonitoringUserSecretSuffix = "-monitoring"
+
 	// DefaultServerCaSecretSuffix is the suffix appended to the secret containing
 	// the generated CA for the cluster
 	DefaultServerCaSecretSuffix = "-ca"
@@ -88,6 +94,10 @@ const (
 	// get the name of the PVC dedicated to WAL files.
 	WalArchiveVolumeSuffix = "-wal"
 
+	// TablespaceVolumeInfix is the infix between the instance name and the
+	// tablespace name in the name of the PVCs dedicated to tablespaces
+	TablespaceVolumeInfix = "-tbs-"
+
 	// StreamingReplicationUser is the name of the user we'll use for
 	// streaming replication purposes
 	StreamingReplicationUser = "streaming_replica"
@@ -113,6 +123,10 @@ const (
 	// PGBouncerPoolerUserName is the name of the role to be used for
 	PGBouncerPoolerUserName = "cnpg_pooler_pgbouncer"
 
+	// MonitoringUserName is the name of the role, member of `pg_monitor`,
+	// used by the metrics endpoint when the monitoring role is enabled
+	MonitoringUserName = "monitoring"
+
 	// NewWalReason is the reason that is set when we do a rolling upgrade to add WAL volumes to a cluster
 	NewWalReason = "the instance has unattached WAL volumes"
 )
@@ -130,9 +144,18 @@ type ClusterSpec struct {
 	// (`<image>:<tag>@sha256:<digestValue>`)
 	ImageName string `json:"imageName,omitempty"`
 
+	// Allow the operator to upgrade the data directory in place via
+	// pg_upgrade when the image is changed to a newer PostgreSQL major
+	// version. The instances are stopped during the upgrade, which
+	// requires as much free space in the volume of the data directory
+	// as the one already in use (default: false)
+	// +optional
+	EnableMajorUpgrade bool `json:"enableMajorUpgrade,omitempty"`
+
 	// Image pull policy.
 	// One of `Always`, `Never` or `IfNotPresent`.
-	// If not defined, it defaults to `IfNotPresent`.
+	// If not defined, it defaults to the `IMAGE_PULL_POLICY` option of the
+	// operator, or to the Kubernetes default when the option is not set.
 	// Cannot be updated.
 	// More info: https://kubernetes.io/docs/concepts/containers/images#updating-images
 	// +optional
@@ -165,6 +188,69 @@ type ClusterSpec struct {
 	// +kubebuilder:validation:Minimum=0
 	MaxSyncReplicas int `json:"maxSyncReplicas,omitempty"`
 
+	// The `synchronous_commit` level of the transactions, which can be
+	// `on` (PostgreSQL default), `off`, `local`, `remote_write` or
+	// `remote_apply`. The `remote_write` and `remote_apply` levels
+	// require synchronous replication (`maxSyncReplicas` > 0)
+	// +kubebuilder:validation:Enum=on;off;local;remote_write;remote_apply
+	// +optional
+	SynchronousCommit SynchronousCommitLevel `json:"synchronousCommit,omitempty"`
+
+	// How the synchronous replication is handled when fewer standbys than
+	// `minSyncReplicas` are ready, i.e. when every replica is down while
+	// the primary is healthy. With `selfHeal` (default) the number of
+	// synchronous standbys is lowered, down to disabling the synchronous
+	// replication, so that the primary keeps accepting writes.
+	// With `enforceSync` the commits wait for `minSyncReplicas`
+	// standbys to be back, preferring durability over availability
+	// +kubebuilder:validation:Enum=selfHeal;enforceSync
+	// +kubebuilder:default:=selfHeal
+	// +optional
+	ReplicasUnavailablePolicy ReplicasUnavailablePolicy `json:"replicasUnavailablePolicy,omitempty"`
+
+	// When enabled, the standby instances are configured with
+	// `default_transaction_read_only = on`, which is removed before
+	// promoting them. The primary never inherits it.
+	// +kubebuilder:default:=false
+	// +optional
+	ReadOnlyStandbys bool `json:"readOnlyStandbys,omitempty"`
+
+	// Temporarily makes the whole cluster read-only, i.e. during a
+	// migration, by configuring the primary with
+	// `default_transaction_read_only = on` without restarting it.
+	// Unlike fencing and hibernation, every instance keeps running
+	// and serving reads
+	// +optional
+	ReadOnly *ReadOnlyConfiguration `json:"readOnly,omitempty"`
+
+	// Configuration of the replicas applying the changes received from
+	// the primary with a delay, as a protection against logical errors.
+	// Delayed replicas are never promoted by an automatic failover
+	// +optional
+	DelayedReplicas *DelayedReplicasConfiguration `json:"delayedReplicas,omitempty"`
+
+	// Configuration of the read replicas, which are added on top of
+	// `.spec.instances`, are served by the `-ro` service and are never
+	// promoted to primary, neither by a failover nor by a switchover
+	// +optional
+	ReadReplicas *ReadReplicasConfiguration `json:"readReplicas,omitempty"`
+
+	// How new replicas get their data directory: `pg_basebackup` (default)
+	// streams a copy from the primary, while `objectStore` restores the
+	// latest base backup from the object store configured in
+	// `.spec.backup.barmanObjectStore`, and then catches up with the primary
+	// through the WAL archive. `objectStore` falls back to `pg_basebackup`
+	// when no base backup is available yet
+	// +kubebuilder:validation:Enum=pg_basebackup;objectStore
+	// +kubebuilder:default:=pg_basebackup
+	// +optional
+	ReplicaJoinMethod ReplicaJoinMethod `json:"replicaJoinMethod,omitempty"`
+
+	// Settings of the streaming replication connection the standbys
+	// open to the primary, through the `primary_conninfo` parameter
+	// +optional
+	ReplicationConnection *ReplicationConnectionConfiguration `json:"replicationConnection,omitempty"`
+
 	// Configuration of the PostgreSQL server
 	// +optional
 	PostgresConfiguration PostgresConfiguration `json:"postgresql,omitempty"`
@@ -172,6 +258,15 @@ type ClusterSpec struct {
 	// Replication slots management configuration
 	ReplicationSlots *ReplicationSlotsConfiguration `json:"replicationSlots,omitempty"`
 
+	// The minimum size of the past WAL files kept by the primary in the
+	// `pg_wal` directory for the standbys, i.e. after a brief
+	// disconnection, even without replication slots. It sets
+	// `wal_keep_size`, rounded up to the megabyte, and must be smaller
+	// than the volume storing the WAL files. Requires PostgreSQL 13 or
+	// above. By default, `512MB` are kept
+	// +optional
+	WalKeepSize *resource.Quantity `json:"walKeepSize,omitempty"`
+
 	// Instructions to bootstrap this cluster
 	// +optional
 	Bootstrap *BootstrapConfiguration `json:"bootstrap,omitempty"`
@@ -198,6 +293,19 @@ type ClusterSpec struct {
 	// +optional
 	Certificates *CertificatesConfiguration `json:"certificates,omitempty"`
 
+	// The namespace containing the secrets referenced by the credentials
+	// of the backup object store and by the certificates, which the
+	// operator copies into the namespace of the cluster and keeps in
+	// sync. It must be allowed in the `CREDENTIALS_NAMESPACES` option of
+	// the operator configuration
+	// +optional
+	CredentialsNamespace string `json:"credentialsNamespace,omitempty"`
+
+	// The naming scheme of the `-rw`, `-ro` and `-r` services of the
+	// cluster. Changing it renames the services
+	// +optional
+	ServiceNaming *ServiceNamingConfiguration `json:"serviceNaming,omitempty"`
+
 	// The list of pull secrets to be used to pull the images
 	ImagePullSecrets []LocalObjectReference `json:"imagePullSecrets,omitempty"`
 
@@ -209,9 +317,36 @@ type ClusterSpec struct {
 	// +optional
 	ServiceAccountTemplate *ServiceAccountTemplate `json:"serviceAccountTemplate,omitempty"`
 
+	// The name of an existing service account to be used by the Pods of
+	// the cluster, instead of the one generated by the operator. The
+	// operator binds it to the role required by the instance manager but
+	// never changes it, so the pull secrets need to be configured in it.
+	// It can't be used together with `serviceAccountTemplate` and can't
+	// be changed after the cluster creation
+	// +optional
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+
 	// Configuration of the storage for PostgreSQL WAL (Write-Ahead Log)
 	WalStorage *StorageConfiguration `json:"walStorage,omitempty"`
 
+	// The tablespaces to be created, each one stored in a dedicated
+	// volume of every instance. The list can't be changed after the
+	// cluster has been created
+	// +optional
+	Tablespaces []TablespaceConfiguration `json:"tablespaces,omitempty"`
+
+	// The logical replication publications managed on the primary.
+	// Publications removed from this list are dropped
+	// +optional
+	Publications []PublicationConfiguration `json:"publications,omitempty"`
+
+	// The PostgreSQL extensions managed on the primary, which are created
+	// in the listed databases once the libraries they need have been
+	// added to `shared_preload_libraries` and loaded. Extensions removed
+	// from this list are dropped
+	// +optional
+	Extensions []ExtensionConfiguration `json:"extensions,omitempty"`
+
 	// The time in seconds that is allowed for a PostgreSQL instance to
 	// successfully start up (default 30)
 	// +kubebuilder:default:=30
@@ -222,6 +357,21 @@ type ClusterSpec struct {
 	// +kubebuilder:default:=30
 	MaxStopDelay int32 `json:"stopDelay,omitempty"`
 
+	// The time in seconds that is allowed for the `CHECKPOINT` requested
+	// by the primary instance when its Pod is deleted, before shutting
+	// PostgreSQL down. It is added to `stopDelay` to get the termination
+	// grace period of the Pods (default 30)
+	// +kubebuilder:default:=30
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	ShutdownCheckpointTimeout int32 `json:"shutdownCheckpointTimeout,omitempty"`
+
+	// Configuration of the orderly shutdown of the instances, shutting
+	// the primary instance down after the replicas, when the cluster is
+	// hibernated or deleted
+	// +optional
+	OrderlyShutdown *OrderlyShutdownConfiguration `json:"orderlyShutdown,omitempty"`
+
 	// The time in seconds that is allowed for a primary PostgreSQL instance
 	// to gracefully shutdown during a switchover.
 	// Default value is 40000000, greater than one year in seconds,
@@ -229,12 +379,47 @@ type ClusterSpec struct {
 	// +kubebuilder:default:=40000000
 	MaxSwitchoverDelay int32 `json:"switchoverDelay,omitempty"`
 
+	// Configuration of the `CHECKPOINT` that is requested on the current
+	// primary before demoting it during a switchover
+	// +optional
+	SwitchoverCheckpoint *SwitchoverCheckpointConfiguration `json:"switchoverCheckpoint,omitempty"`
+
 	// The amount of time (in seconds) to wait before triggering a failover
 	// after the primary PostgreSQL instance in the cluster was detected
 	// to be unhealthy
 	// +kubebuilder:default:=0
 	FailoverDelay int32 `json:"failoverDelay,omitempty"`
 
+	// The amount of time (in seconds) after the promotion of a new primary
+	// during which the operator doesn't trigger another automatic failover,
+	// avoiding cascading failovers, i.e. while the nodes are lost one after
+	// the other. Manual promotions are still honored. Disabled when 0
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	FailoverCooldown int32 `json:"failoverCooldown,omitempty"`
+
+	// If true, the operator never promotes a replica on its own when the
+	// primary is unhealthy, and waits for a replica to be manually promoted
+	// instead. The cluster is not available for writes until then
+	// +optional
+	DisableAutomaticFailover bool `json:"disableAutomaticFailover,omitempty"`
+
+	// How long a switchover or a failover can take before being reported
+	// as stuck, and how the operator reacts to it
+	// +optional
+	PrimaryTransition *PrimaryTransitionConfiguration `json:"primaryTransition,omitempty"`
+
+	// How the operator handles the replicas reporting checksum failures
+	// on their data pages. They are removed from the services, but only
+	// rebuilt from the primary when explicitly requested
+	// +optional
+	CorruptedReplicas *CorruptedReplicasConfiguration `json:"corruptedReplicas,omitempty"`
+
+	// How the operator behaves when some instances are not ready while
+	// it needs to scale up the cluster
+	// +optional
+	ReadinessTolerance *ReadinessToleranceConfiguration `json:"readinessTolerance,omitempty"`
+
 	// Affinity/Anti-affinity rules for Pods
 	// +optional
 	Affinity AffinityConfiguration `json:"affinity,omitempty"`
@@ -259,12 +444,28 @@ type ClusterSpec struct {
 	// +kubebuilder:validation:Enum:=switchover;restart
 	PrimaryUpdateMethod PrimaryUpdateMethod `json:"primaryUpdateMethod,omitempty"`
 
+	// Restart the instances with a rolling update, the primary being the
+	// last one, when the configuration managed by the operator changes,
+	// instead of only reloading it. The configuration includes the
+	// PostgreSQL parameters, the `pg_hba` rules, the shared preload
+	// libraries, the LDAP settings and the certificates. Only the
+	// instances running with an outdated configuration are restarted
+	// +optional
+	RestartOnConfigurationChange bool `json:"restartOnConfigurationChange,omitempty"`
+
 	// The configuration to be used for backups
 	Backup *BackupConfiguration `json:"backup,omitempty"`
 
 	// Define a maintenance window for the Kubernetes nodes
 	NodeMaintenanceWindow *NodeMaintenanceWindow `json:"nodeMaintenanceWindow,omitempty"`
 
+	// The time ranges when the operator is allowed to restart the instances
+	// to apply an upgrade or a configuration change. Outside of them, the
+	// rolling updates are deferred and the `PendingMaintenance` condition is
+	// set. When not specified, the rolling updates are applied immediately
+	// +optional
+	MaintenanceWindow *MaintenanceWindowConfiguration `json:"maintenanceWindow,omitempty"`
+
 	// The configuration of the monitoring infrastructure of this cluster
 	Monitoring *MonitoringConfiguration `json:"monitoring,omitempty"`
 
@@ -292,6 +493,96 @@ type ClusterSpec struct {
 	// sources to the pods to be used by Env
 	// +optional
 	EnvFrom []corev1.EnvFromSource `json:"envFrom,omitempty"`
+
+	// Additional containers running in the instance pods alongside the
+	// `postgres` one, for example a logging or a monitoring agent.
+	// Changing them triggers a rolling update of the instances
+	// +optional
+	// +patchMergeKey=name
+	// +patchStrategy=merge
+	Sidecars []corev1.Container `json:"sidecars,omitempty"`
+
+	// Additional volumes of the instance pods, to be mounted by the sidecars
+	// +optional
+	// +patchMergeKey=name
+	// +patchStrategy=merge
+	SidecarVolumes []corev1.Volume `json:"sidecarVolumes,omitempty"`
+
+	// The secrets and config maps mounted in the instances, through the
+	// projected volume or the sidecar volumes, whose content changes are
+	// tracked by the operator. Those which can't be reloaded cause the
+	// instances to be restarted with a rolling update, the primary being
+	// the last one
+	// +optional
+	MountedObjects []MountedObjectReference `json:"mountedObjects,omitempty"`
+
+	// The number of entries retained in the history lists of the status,
+	// like the changes of the primary instance. The oldest entries are
+	// discarded, to keep the size of the Cluster object under control
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +kubebuilder:default:=10
+	// +optional
+	StatusHistoryLength int `json:"statusHistoryLength,omitempty"`
+
+	// The schedule of a rolling restart of the instances, i.e. to reclaim
+	// the memory leaked by an extension. When not specified, the instances
+	// are never restarted on a schedule
+	// +optional
+	ScheduledRestart *ScheduledRestartConfiguration `json:"scheduledRestart,omitempty"`
+}
+
+// ScheduledRestartConfiguration is the schedule of a rolling restart of
+// the instances, requested by the operator without using a CronJob. The
+// primary is restarted last and, when a maintenance window is configured,
+// the restart is deferred to it
+type ScheduledRestartConfiguration struct {
+	// The schedule of the restart, in the Cron format used by the
+	// Kubernetes CronJobs, evaluated in UTC
+	Schedule string `json:"schedule"`
+
+	// Suspend the scheduled restarts
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+}
+
+// MountedObjectChangePolicy is how a change in the content of a
+// mounted secret or config map is applied to the instances
+type MountedObjectChangePolicy string
+
+const (
+	// MountedObjectChangeRestart restarts the instances with a rolling
+	// update when the content of the object changes
+	MountedObjectChangeRestart = MountedObjectChangePolicy("Restart")
+
+	// MountedObjectChangeReload keeps the instances running, as the
+	// content of the object is reloaded by the process using it once the
+	// kubelet refreshes the mounted files
+	MountedObjectChangeReload = MountedObjectChangePolicy("Reload")
+)
+
+// MountedObjectReference is a secret or a config map mounted in the instances
+type MountedObjectReference struct {
+	// The kind of the object, `Secret` or `ConfigMap`
+	// +kubebuilder:validation:Enum=Secret;ConfigMap
+	Kind string `json:"kind"`
+
+	// The name of the object
+	Name string `json:"name"`
+
+	// How a change in the content of the object is applied: `Restart`
+	// (default) restarts the instances, `Reload` keeps them running as
+	// the process using the object reloads it
+	// +kubebuilder:validation:Enum=Restart;Reload
+	// +kubebuilder:default:=Restart
+	// +optional
+	OnChange MountedObjectChangePolicy `json:"onChange,omitempty"`
+}
+
+// IsRestartRequired checks whether a change in the content
+// of the object requires the instances to be restarted
+func (reference MountedObjectReference) IsRestartRequired() bool {
+	return reference.OnChange != MountedObjectChangeReload
 }
 
 const (
@@ -304,6 +595,10 @@ const (
 	// PhaseFirstPrimary for an starting cluster
 	PhaseFirstPrimary = "Setting up primary"
 
+	// PhaseRestoring for a starting cluster whose primary instance is
+	// being restored from a backup
+	PhaseRestoring = "Restoring primary from a backup"
+
 	// PhaseCreatingReplica everytime we add a new replica
 	PhaseCreatingReplica = "Creating a new replica"
 
@@ -331,6 +626,18 @@ const (
 	// PhaseApplyingConfiguration is set by the instance manager when a configuration
 	// change is being detected
 	PhaseApplyingConfiguration = "Applying configuration"
+
+	// PhaseMajorUpgrade for a cluster whose data is being upgraded
+	// to a new PostgreSQL major version
+	PhaseMajorUpgrade = "Upgrading Postgres major version"
+
+	// PhaseMajorUpgradeFailed for a cluster whose upgrade to a new
+	// PostgreSQL major version failed, keeping the previous data
+	PhaseMajorUpgradeFailed = "Postgres major version upgrade failed"
+
+	// PhaseIncompatibleImage for a cluster whose image is running an older
+	// PostgreSQL major version than the one of the data directory
+	PhaseIncompatibleImage = "Incompatible Postgres major version"
 )
 
 // ServiceAccountTemplate contains the template needed to generate the service accounts
@@ -391,6 +698,9 @@ type ClusterStatus struct {
 	// Total number of ready instances in the cluster
 	ReadyInstances int `json:"readyInstances,omitempty"`
 
+	// The names of the read replicas, which are never promoted
+	ReadReplicas []string `json:"readReplicas,omitempty"`
+
 	// InstancesStatus indicates in which status the instances are
 	InstancesStatus map[utils.PodStatus][]string `json:"instancesStatus,omitempty"`
 
@@ -400,6 +710,22 @@ type ClusterStatus struct {
 	// The timeline of the Postgres cluster
 	TimelineID int `json:"timelineID,omitempty"`
 
+	// The replicas which have been found stuck on an old timeline and
+	// are going to be resynchronized with the primary when restarted
+	// +optional
+	ResynchronizingInstances []string `json:"resynchronizingInstances,omitempty"`
+
+	// The settings chosen when the data directory was initialized,
+	// as reported by the primary instance
+	// +optional
+	InitDBSettings *InitDBSettings `json:"initDBSettings,omitempty"`
+
+	// The image and the PostgreSQL major version the data directory of
+	// the instances has been created with, used to detect the changes of
+	// the major version requiring an upgrade of the data
+	// +optional
+	PGDataImageInfo *ImageInfo `json:"pgDataImageInfo,omitempty"`
+
 	// Instances topology.
 	Topology Topology `json:"topology,omitempty"`
 
@@ -435,6 +761,11 @@ type ClusterStatus struct {
 	// List of all the PVCs that are unusable because another PVC is missing
 	UnusablePVC []string `json:"unusablePVC,omitempty"`
 
+	// The storage classes of the PVCs of this cluster, as set by
+	// Kubernetes when they have been created
+	// +optional
+	StorageClasses []string `json:"storageClasses,omitempty"`
+
 	// Current write pod
 	WriteService string `json:"writeService,omitempty"`
 
@@ -447,6 +778,12 @@ type ClusterStatus struct {
 	// Reason for the current phase
 	PhaseReason string `json:"phaseReason,omitempty"`
 
+	// The `metadata.generation` of the cluster the last time its spec
+	// has been fully applied. When lower than the current generation,
+	// the operator is still working on the latest changes
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
 	// The list of resource versions of the secrets
 	// managed by the operator. Every change here is done in the
 	// interest of the instance manager, which will refresh the
@@ -459,6 +796,21 @@ type ClusterStatus struct {
 	// configmap data
 	ConfigMapResourceVersion ConfigMapResourceVersion `json:"configMapResourceVersion,omitempty"`
 
+	// The hash of the content of the mounted secrets and config maps whose
+	// changes require the instances to be restarted
+	// +optional
+	MountedObjectsHash string `json:"mountedObjectsHash,omitempty"`
+
+	// The time when the latest scheduled restart has been requested,
+	// stored as a date in RFC3339 format
+	// +optional
+	LastScheduledRestart string `json:"lastScheduledRestart,omitempty"`
+
+	// The time of the next scheduled restart, stored as a date in RFC3339
+	// format
+	// +optional
+	NextScheduledRestart string `json:"nextScheduledRestart,omitempty"`
+
 	// The configuration for the CA and related certificates, initialized with defaults.
 	Certificates CertificatesStatus `json:"certificates,omitempty"`
 
@@ -471,6 +823,11 @@ type ClusterStatus struct {
 	// The timestamp when the last actual promotion to primary has occurred
 	CurrentPrimaryTimestamp string `json:"currentPrimaryTimestamp,omitempty"`
 
+	// The latest changes of the primary instance, oldest first. Only the
+	// last changes are kept, up to `.spec.statusHistoryLength`
+	// +optional
+	PrimaryHistory []PrimaryChange `json:"primaryHistory,omitempty"`
+
 	// The timestamp when the primary was detected to be unhealthy
 	// This field is reported only when spec.failoverDelay is populated
 	CurrentPrimaryFailingSinceTimestamp string `json:"currentPrimaryFailingSinceTimestamp,omitempty"`
@@ -495,6 +852,153 @@ type ClusterStatus struct {
 
 	// List of instance names in the cluster
 	InstanceNames []string `json:"instanceNames,omitempty"`
+
+	// The state of the publications managed by the operator, as
+	// reported by the primary instance
+	// +optional
+	Publications []PublicationStatus `json:"publications,omitempty"`
+
+	// The state of the extensions managed by the operator in each
+	// database, as reported by the primary instance
+	// +optional
+	Extensions []ExtensionStatus `json:"extensions,omitempty"`
+
+	// The progress of the WAL replay while the primary instance is being
+	// restored from a backup, as reported by the recovery job
+	// +optional
+	RecoveryProgress *RecoveryProgress `json:"recoveryProgress,omitempty"`
+
+	// The progress of the asynchronous mirroring of the WAL archive
+	// to the secondary object store, as reported by the mirroring job
+	// +optional
+	WalMirror *WalMirrorStatus `json:"walMirror,omitempty"`
+
+	// The location of the shutdown checkpoint written by the primary
+	// instance when the cluster has been hibernated, used to verify the
+	// consistency of the data when the cluster is woken up
+	// +optional
+	CleanShutdownLSN string `json:"cleanShutdownLSN,omitempty"`
+}
+
+// PrimaryChangeReason is why the primary instance changed
+type PrimaryChangeReason string
+
+const (
+	// PrimaryChangeSwitchover is a planned change of the primary instance,
+	// requested by the operator or by the user
+	PrimaryChangeSwitchover = PrimaryChangeReason("Switchover")
+
+	// PrimaryChangeFailover is the promotion of a replica replacing
+	// a primary instance which isn't healthy
+	PrimaryChangeFailover = PrimaryChangeReason("Failover")
+)
+
+// DefaultStatusHistoryLength is the default number of entries
+// retained in the history lists of the status of the cluster
+const DefaultStatusHistoryLength = 10
+
+// PrimaryChange describes a change of the primary instance, as
+// reported by the promoted instance
+type PrimaryChange struct {
+	// When the new primary has been promoted, in RFC3339 format
+	Timestamp string `json:"timestamp"`
+
+	// The former primary instance
+	OldPrimary string `json:"oldPrimary"`
+
+	// The promoted instance
+	NewPrimary string `json:"newPrimary"`
+
+	// Why the primary changed, `Switchover` or `Failover`
+	Reason PrimaryChangeReason `json:"reason"`
+
+	// The timeline of the new primary after the promotion
+	// +optional
+	TimelineID int `json:"timelineID,omitempty"`
+
+	// The WAL position of the new primary after the promotion
+	// +optional
+	LSN string `json:"lsn,omitempty"`
+}
+
+// WalMirrorStatus describes how far the mirroring of the WAL archive
+// to the secondary object store has gone
+type WalMirrorStatus struct {
+	// The name of the last WAL file copied to the secondary object store
+	// +optional
+	LastMirroredWAL string `json:"lastMirroredWAL,omitempty"`
+
+	// The timestamp when the last WAL file has been copied to the
+	// secondary object store
+	// +optional
+	LastMirrorTime string `json:"lastMirrorTime,omitempty"`
+
+	// The message of the error which stopped the last execution of
+	// the mirroring job, empty if it completed successfully
+	// +optional
+	LastError string `json:"lastError,omitempty"`
+}
+
+// RecoveryProgress describes how far the WAL replay of a primary
+// instance being restored from a backup has gone
+type RecoveryProgress struct {
+	// The name of the WAL file being replayed
+	// +optional
+	CurrentWAL string `json:"currentWAL,omitempty"`
+
+	// The last LSN which has been replayed
+	// +optional
+	ReplayedLSN string `json:"replayedLSN,omitempty"`
+
+	// The commit timestamp of the last replayed transaction,
+	// in RFC3339 format
+	// +optional
+	ReplayedTransactionTime string `json:"replayedTransactionTime,omitempty"`
+
+	// The point where the recovery will stop
+	// +optional
+	Target string `json:"target,omitempty"`
+
+	// When the progress has been reported, in RFC3339 format
+	// +optional
+	LastUpdateTime string `json:"lastUpdateTime,omitempty"`
+}
+
+// PrimaryTransitionConfiguration bounds the time a switchover or a
+// failover can take before the operator reports it as stuck
+type PrimaryTransitionConfiguration struct {
+	// The time in seconds after which a switchover or a failover which
+	// didn't complete is reported as stuck, through the
+	// `PrimaryTransitionStuck` condition and a warning event (default 600)
+	// +kubebuilder:default:=600
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	StuckTimeout int32 `json:"stuckTimeout,omitempty"`
+
+	// When true, the Pod of the target primary is deleted once the
+	// switchover or the failover is reported as stuck, for its instance
+	// manager to retry the promotion after being restarted
+	// +optional
+	RestartTargetPrimary bool `json:"restartTargetPrimary,omitempty"`
+}
+
+// CorruptedReplicasConfiguration configures the automatic rebuild of the
+// replicas reporting checksum failures on their data pages
+type CorruptedReplicasConfiguration struct {
+	// When true, the Pod and the PVCs of a replica which has been
+	// reporting checksum failures for longer than the grace period are
+	// deleted, and the replica is cloned again from the primary. A primary
+	// reporting checksum failures is switched over to a healthy replica first
+	// +optional
+	Rebuild bool `json:"rebuild,omitempty"`
+
+	// The time in seconds a replica reporting checksum failures is kept
+	// out of the services, and available to be inspected, before being
+	// rebuilt (default 600)
+	// +kubebuilder:default:=600
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	RebuildGracePeriod int32 `json:"rebuildGracePeriod,omitempty"`
 }
 
 // InstanceReportedState describes the last reported state of an instance during a reconciliation loop
@@ -503,6 +1007,90 @@ type InstanceReportedState struct {
 	IsPrimary bool `json:"isPrimary"`
 	// indicates on which TimelineId the instance is
 	TimeLineID int `json:"timeLineID,omitempty"`
+	// the version of barman-cloud detected in the instance image, empty
+	// when barman-cloud is not installed
+	BarmanCloudVersion string `json:"barmanCloudVersion,omitempty"`
+	// the number of WAL files waiting to be archived, i.e. the `.ready`
+	// files in the `archive_status` directory
+	PendingWALFiles int `json:"pendingWALFiles,omitempty"`
+	// the resource usage of the PostgreSQL container, to guide the tuning
+	// of the resources. It's advisory, and refreshed at most once a minute
+	// +optional
+	ResourceUsage *InstanceResourceUsage `json:"resourceUsage,omitempty"`
+	// when the replica has been found on a timeline older than the primary
+	// one while not streaming from it, in RFC3339 format. Such a replica is
+	// resynchronized when it doesn't recover within a grace period
+	// +optional
+	TimelineDivergedSince string `json:"timelineDivergedSince,omitempty"`
+	// the number of checksum failures the instance already reported when
+	// it has been first seen, or when its statistics have been reset. Only
+	// the failures exceeding it mark the instance as corrupted
+	// +optional
+	ChecksumFailuresBaseline *int64 `json:"checksumFailuresBaseline,omitempty"`
+	// when the replica has been found reporting new checksum failures on
+	// its data pages, in RFC3339 format. Such a replica is removed from
+	// the services, and rebuilt from the primary after a grace period
+	// when requested in `.spec.corruptedReplicas`
+	// +optional
+	CorruptedSince string `json:"corruptedSince,omitempty"`
+	// the method used the last time this instance, as a former primary,
+	// was resynchronized with the new primary: `pg_rewind` or
+	// `pg_basebackup`. Empty when it never had to be resynchronized
+	// +optional
+	LastResyncMethod string `json:"lastResyncMethod,omitempty"`
+	// the synchronous state of the replica as reported by the primary in
+	// `pg_stat_replication`: `sync`, `potential`, `quorum` or `async`.
+	// Empty for the primary and for the replicas not streaming from it
+	// +optional
+	SyncState string `json:"syncState,omitempty"`
+}
+
+// InstanceResourceUsage describes the resource usage of the PostgreSQL
+// container of an instance, as read from its control group
+type InstanceResourceUsage struct {
+	// The CPU usage, averaged on a short interval. Empty until
+	// the instance manager has collected enough samples
+	// +optional
+	CPU string `json:"cpu,omitempty"`
+
+	// The memory working set, that is the used memory excluding
+	// the page cache which can be reclaimed
+	Memory string `json:"memory"`
+
+	// When the resource usage has been collected, in RFC3339 format
+	LastUpdateTime string `json:"lastUpdateTime"`
+}
+
+// InitDBSettings contains the settings which are chosen when the data
+// directory is initialized and can't be changed later
+type InitDBSettings struct {
+	// Whether checksums are enabled on data pages
+	DataChecksums bool `json:"dataChecksums"`
+
+	// The encoding of the `template1` database
+	// +optional
+	Encoding string `json:"encoding,omitempty"`
+
+	// The collation of the `template1` database
+	// +optional
+	LocaleCollate string `json:"localeCollate,omitempty"`
+
+	// The character classification of the `template1` database
+	// +optional
+	LocaleCType string `json:"localeCType,omitempty"`
+
+	// The size of a WAL segment, in megabytes
+	// +optional
+	WalSegmentSize int `json:"walSegmentSize,omitempty"`
+}
+
+// ImageInfo contains the information about a PostgreSQL image
+type ImageInfo struct {
+	// The name of the image
+	Image string `json:"image"`
+
+	// The PostgreSQL major version of the image
+	MajorVersion int `json:"majorVersion"`
 }
 
 // ClusterConditionType defines types of cluster conditions
@@ -517,6 +1105,56 @@ const (
 	ConditionBackup ClusterConditionType = "LastBackupSucceeded"
 	// ConditionClusterReady represents whether a cluster is Ready
 	ConditionClusterReady ClusterConditionType = "Ready"
+	// ConditionPrimaryAvailable represents whether the primary is healthy,
+	// and is set only when the automatic failover is disabled
+	ConditionPrimaryAvailable ClusterConditionType = "PrimaryAvailable"
+	// ConditionConfigurationDrift represents whether some parameters managed
+	// by the operator are overridden in `postgresql.auto.conf`
+	ConditionConfigurationDrift ClusterConditionType = "ConfigurationDrift"
+	// ConditionWALArchivingBacklog represents whether the number of WAL
+	// files waiting to be archived exceeds the configured threshold in
+	// some instances
+	ConditionWALArchivingBacklog ClusterConditionType = "WALArchivingBacklog"
+	// ConditionPendingMaintenance represents whether some instances need
+	// a rolling update which is deferred to the next maintenance window
+	ConditionPendingMaintenance ClusterConditionType = "PendingMaintenance"
+	// ConditionCloneCompleted represents whether the data directory of a
+	// cluster bootstrapped via pg_basebackup has been cloned from the source
+	ConditionCloneCompleted ClusterConditionType = "CloneCompleted"
+	// ConditionMajorVersionMismatch represents whether the instances are
+	// running different PostgreSQL major versions, which blocks the
+	// reconciliation of the cluster
+	ConditionMajorVersionMismatch ClusterConditionType = "MajorVersionMismatch"
+	// ConditionIncompatibleImage represents whether the PostgreSQL major
+	// version of the image is older than the one of the data directory,
+	// which blocks the rollout of the image
+	ConditionIncompatibleImage ClusterConditionType = "IncompatibleImage"
+	// ConditionReplicasUnavailable represents whether the primary is
+	// healthy while none of the replicas is ready, leaving the cluster
+	// without a failover candidate
+	ConditionReplicasUnavailable ClusterConditionType = "ReplicasUnavailable"
+
+	// ConditionReadOnly is true when the primary is rejecting the writes
+	// because the cluster has been set to read-only
+	ConditionReadOnly ClusterConditionType = "ReadOnly"
+
+	// ConditionWALKeepSizeAtRisk is true when the WAL files kept for the
+	// standbys may fill the volume storing the WAL files
+	ConditionWALKeepSizeAtRisk ClusterConditionType = "WALKeepSizeAtRisk"
+
+	// ConditionPrimaryTransitionStuck is true when a switchover or a
+	// failover has been taking longer than the configured timeout
+	ConditionPrimaryTransitionStuck ClusterConditionType = "PrimaryTransitionStuck"
+
+	// ConditionFailoverCooldown is true when the automatic failovers are
+	// suspended, as a new primary has been promoted less than
+	// `failoverCooldown` seconds ago
+	ConditionFailoverCooldown ClusterConditionType = "FailoverCooldown"
+
+	// ConditionQuorumAtRisk is true when synchronous replication is
+	// enabled with an even number of instances, which doesn't give a
+	// clear majority to the quorum
+	ConditionQuorumAtRisk ClusterConditionType = "QuorumAtRisk"
 )
 
 // ConditionStatus defines conditions of resources
@@ -557,11 +1195,151 @@ const (
 	// the WAL archiving is not working correctly
 	ConditionReasonContinuousArchivingFailing ConditionReason = "ContinuousArchivingFailing"
 
+	// ConditionReasonContinuousArchivingSuspended means that the condition has
+	// changed because the WAL archiving has been suspended by the user, and
+	// the WAL files are not being archived
+	ConditionReasonContinuousArchivingSuspended ConditionReason = "ContinuousArchivingSuspended"
+
+	// ConditionReasonObjectStoreUnreachable means that the condition has changed because
+	// the WAL archiving failed and the object store can't be reached with the configured
+	// endpoint and credentials
+	ConditionReasonObjectStoreUnreachable ConditionReason = "ObjectStoreUnreachable"
+
 	// ClusterReady means that the condition changed because the cluster is ready and working properly
 	ClusterReady ConditionReason = "ClusterIsReady"
 
 	// ClusterIsNotReady means that the condition changed because the cluster is not ready
 	ClusterIsNotReady ConditionReason = "ClusterIsNotReady"
+
+	// ConditionReasonPrimaryAvailable means that the condition changed because
+	// the primary is healthy again
+	ConditionReasonPrimaryAvailable ConditionReason = "PrimaryAvailable"
+
+	// ConditionReasonManualFailoverRequired means that the condition changed
+	// because the primary is unhealthy, and a replica needs to be manually
+	// promoted as the automatic failover is disabled
+	ConditionReasonManualFailoverRequired ConditionReason = "ManualFailoverRequired"
+
+	// ConditionReasonPrimaryPodMissing means that the condition changed
+	// because the pod of the current primary doesn't exist anymore
+	ConditionReasonPrimaryPodMissing ConditionReason = "PrimaryPodMissing"
+
+	// ConditionReasonConfigurationDriftDetected means that the condition changed
+	// because some instances override parameters managed by the operator
+	ConditionReasonConfigurationDriftDetected ConditionReason = "ConfigurationDriftDetected"
+
+	// ConditionReasonConfigurationAligned means that the condition changed
+	// because no instance overrides parameters managed by the operator
+	ConditionReasonConfigurationAligned ConditionReason = "ConfigurationAligned"
+
+	// ConditionReasonWALArchivingBacklogDetected means that the condition
+	// changed because some instances have too many WAL files waiting to
+	// be archived
+	ConditionReasonWALArchivingBacklogDetected ConditionReason = "WALArchivingBacklogDetected"
+
+	// ConditionReasonWALArchivingCaughtUp means that the condition changed
+	// because the number of WAL files waiting to be archived is below the
+	// threshold in every instance
+	ConditionReasonWALArchivingCaughtUp ConditionReason = "WALArchivingCaughtUp"
+
+	// ConditionReasonRolloutDeferred means that the condition changed
+	// because a rolling update is waiting for the next maintenance window
+	ConditionReasonRolloutDeferred ConditionReason = "RolloutDeferred"
+
+	// ConditionReasonNoPendingMaintenance means that the condition changed
+	// because no rolling update is waiting for a maintenance window
+	ConditionReasonNoPendingMaintenance ConditionReason = "NoPendingMaintenance"
+
+	// ConditionReasonCloneInProgress means that the condition changed
+	// because pg_basebackup reported a new completion percentage
+	ConditionReasonCloneInProgress ConditionReason = "CloneInProgress"
+
+	// ConditionReasonCloneSucceeded means that the condition changed
+	// because the data directory has been successfully cloned
+	ConditionReasonCloneSucceeded ConditionReason = "CloneSucceeded"
+
+	// ConditionReasonCloneFailed means that the condition changed because
+	// the data directory couldn't be cloned from the source
+	ConditionReasonCloneFailed ConditionReason = "CloneFailed"
+
+	// ConditionReasonSourceClusterUnavailable means that the condition
+	// changed because the cluster to be cloned doesn't exist or has no
+	// primary instance
+	ConditionReasonSourceClusterUnavailable ConditionReason = "SourceClusterUnavailable"
+
+	// ConditionReasonMajorVersionMismatchDetected means that the condition
+	// changed because some instances are running a different PostgreSQL
+	// major version
+	ConditionReasonMajorVersionMismatchDetected ConditionReason = "MajorVersionMismatchDetected"
+
+	// ConditionReasonMajorVersionsAligned means that the condition changed
+	// because every instance is running the same PostgreSQL major version
+	ConditionReasonMajorVersionsAligned ConditionReason = "MajorVersionsAligned"
+
+	// ConditionReasonMajorVersionDowngradeDetected means that the condition
+	// changed because the image is running an older PostgreSQL major
+	// version than the one of the data directory
+	ConditionReasonMajorVersionDowngradeDetected ConditionReason = "MajorVersionDowngradeDetected"
+
+	// ConditionReasonImageCompatible means that the condition changed
+	// because the image can run the data directory of the cluster
+	ConditionReasonImageCompatible ConditionReason = "ImageCompatible"
+
+	// ConditionReasonAllReplicasUnavailable means that the primary is
+	// healthy, but none of the replicas is ready
+	ConditionReasonAllReplicasUnavailable ConditionReason = "AllReplicasUnavailable"
+
+	// ConditionReasonReplicasAvailable means that at least one replica is
+	// ready, or that the cluster has no replicas
+	ConditionReasonReplicasAvailable ConditionReason = "ReplicasAvailable"
+
+	// ConditionReasonReadOnlyEnabled means that the cluster has been set
+	// to read-only, and the primary is rejecting the writes
+	ConditionReasonReadOnlyEnabled ConditionReason = "ReadOnlyEnabled"
+
+	// ConditionReasonReadOnlyRequested means that the cluster has been set
+	// to read-only, but the primary didn't apply the change yet
+	ConditionReasonReadOnlyRequested ConditionReason = "ReadOnlyRequested"
+
+	// ConditionReasonReadWriteRequested means that the read-only mode of
+	// the cluster has been disabled, but the primary didn't apply the
+	// change yet
+	ConditionReasonReadWriteRequested ConditionReason = "ReadWriteRequested"
+
+	// ConditionReasonReadWrite means that the primary is accepting writes
+	ConditionReasonReadWrite ConditionReason = "ReadWrite"
+
+	// ConditionReasonWALKeepSizeTooLarge means that the WAL files kept
+	// for the standbys take more than half of the WAL volume
+	ConditionReasonWALKeepSizeTooLarge ConditionReason = "WALKeepSizeTooLarge"
+
+	// ConditionReasonWALKeepSizeFits means that the WAL files kept for
+	// the standbys take at most half of the WAL volume
+	ConditionReasonWALKeepSizeFits ConditionReason = "WALKeepSizeFits"
+
+	// ConditionReasonPrimaryTransitionTimedOut means that a switchover or
+	// a failover didn't complete within the configured timeout
+	ConditionReasonPrimaryTransitionTimedOut ConditionReason = "PrimaryTransitionTimedOut"
+
+	// ConditionReasonPrimaryTransitionCompleted means that the switchover
+	// or the failover which was reported as stuck has completed
+	ConditionReasonPrimaryTransitionCompleted ConditionReason = "PrimaryTransitionCompleted"
+
+	// ConditionReasonFailoverCooldownActive means that a new primary has
+	// been promoted less than `failoverCooldown` seconds ago
+	ConditionReasonFailoverCooldownActive ConditionReason = "FailoverCooldownActive"
+
+	// ConditionReasonFailoverCooldownExpired means that the cooldown after
+	// the last promotion expired, and the automatic failovers are allowed
+	ConditionReasonFailoverCooldownExpired ConditionReason = "FailoverCooldownExpired"
+
+	// ConditionReasonEvenInstances means that synchronous replication is
+	// enabled with an even number of instances
+	ConditionReasonEvenInstances ConditionReason = "EvenInstances"
+
+	// ConditionReasonOddInstances means that synchronous replication is
+	// enabled with an odd number of instances
+	ConditionReasonOddInstances ConditionReason = "OddInstances"
 )
 
 // EmbeddedObjectMetadata contains metadata to be inherited by all resources related to a Cluster
@@ -612,6 +1390,16 @@ type ReplicationSlotsConfiguration struct {
 	//+kubebuilder:default:=30
 	//+kubebuilder:validation:Minimum=1
 	UpdateInterval int `json:"updateInterval,omitempty"`
+
+	// Maximum size of the WAL files that replication slots are allowed
+	// to retain in the `pg_wal` directory of the primary, using the
+	// PostgreSQL units (e.g. `10GB`). It sets `max_slot_wal_keep_size`
+	// and protects the disk of the primary from a standby that stays
+	// disconnected for too long. Requires PostgreSQL 13 or above.
+	// By default, the WAL files retained by slots are not limited.
+	//+kubebuilder:validation:Pattern=^[0-9]+(kB|MB|GB|TB)?$
+	// +optional
+	MaxSlotWalKeepSize string `json:"maxSlotWalKeepSize,omitempty"`
 }
 
 // GetUpdateInterval returns the update interval, defaulting to DefaultReplicationSlotsUpdateInterval if empty
@@ -622,6 +1410,15 @@ func (r *ReplicationSlotsConfiguration) GetUpdateInterval() time.Duration {
 	return time.Duration(r.UpdateInterval) * time.Second
 }
 
+// GetMaxSlotWalKeepSize returns the configured cap for the WAL files
+// retained by replication slots, or an empty string if unlimited
+func (r *ReplicationSlotsConfiguration) GetMaxSlotWalKeepSize() string {
+	if r == nil {
+		return ""
+	}
+	return r.MaxSlotWalKeepSize
+}
+
 // ReplicationSlotsHAConfiguration encapsulates the configuration
 // of the replication slots that are automatically managed by
 // the operator to control the streaming replication connections
@@ -709,56 +1506,367 @@ type NodeMaintenanceWindow struct {
 	ReusePVC *bool `json:"reusePVC"`
 }
 
-// PrimaryUpdateStrategy contains the strategy to follow when upgrading
-// the primary server of the cluster as part of rolling updates
-type PrimaryUpdateStrategy string
-
-// PrimaryUpdateMethod contains the method to use when upgrading
-// the primary server of the cluster as part of rolling updates
-type PrimaryUpdateMethod string
+// MaintenanceWeekday is a day of the week of a maintenance window
+// +kubebuilder:validation:Enum=Monday;Tuesday;Wednesday;Thursday;Friday;Saturday;Sunday
+type MaintenanceWeekday string
 
-const (
-	// PrimaryUpdateStrategySupervised means that the operator need to wait for the
-	// user to manually issue a switchover request before updating the primary
-	// server (`supervised`)
-	PrimaryUpdateStrategySupervised PrimaryUpdateStrategy = "supervised"
+// MaintenanceWindowConfiguration contains the time ranges when the
+// operator is allowed to restart the instances of a cluster
+type MaintenanceWindowConfiguration struct {
+	// The time ranges when the rolling updates are allowed
+	// +kubebuilder:validation:MinItems=1
+	Windows []MaintenanceTimeRange `json:"windows"`
 
-	// PrimaryUpdateStrategyUnsupervised means that the operator will proceed with the
-	// selected PrimaryUpdateMethod to another updated replica and then automatically update
-	// the primary server (`unsupervised`, default)
-	PrimaryUpdateStrategyUnsupervised PrimaryUpdateStrategy = "unsupervised"
+	// The time zone of the time ranges, as a name of the IANA Time Zone
+	// database, like `Europe/Rome` (default: `UTC`)
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+}
 
-	// PrimaryUpdateMethodSwitchover means that the operator will switchover to another updated
-	// replica when it needs to upgrade the primary instance
-	PrimaryUpdateMethodSwitchover PrimaryUpdateMethod = "switchover"
+// MaintenanceTimeRange is a daily time range when the rolling updates
+// are allowed
+type MaintenanceTimeRange struct {
+	// The days of the week when the time range starts, every day if empty
+	// +optional
+	Days []MaintenanceWeekday `json:"days,omitempty"`
 
-	// PrimaryUpdateMethodRestart means that the operator will restart the primary instance in-place
-	// when it needs to upgrade it
-	PrimaryUpdateMethodRestart PrimaryUpdateMethod = "restart"
+	// The start of the time range, in the `HH:MM` format
+	// +kubebuilder:validation:Pattern=`^([01][0-9]|2[0-3]):[0-5][0-9]$`
+	Start string `json:"start"`
 
-	// DefaultPgCtlTimeoutForPromotion is the default for the pg_ctl timeout when a promotion is performed.
-	// It is greater than one year in seconds, big enough to simulate an infinite timeout
-	DefaultPgCtlTimeoutForPromotion = 40000000
+	// The end of the time range, in the `HH:MM` format. When it is not
+	// later than the start, the time range ends on the following day
+	// +kubebuilder:validation:Pattern=`^([01][0-9]|2[0-3]):[0-5][0-9]$`
+	End string `json:"end"`
+}
 
-	// DefaultMaxSwitchoverDelay is the default for the pg_ctl timeout in seconds when a primary PostgreSQL instance
-	// is gracefully shutdown during a switchover.
-	// It is greater than one year in seconds, big enough to simulate an infinite timeout
-	DefaultMaxSwitchoverDelay = 40000000
-)
+// FinalBackupFinalizerName is the finalizer delaying the deletion of a
+// cluster until its final base backup is completed
+const FinalBackupFinalizerName = "cnpg.io/finalBackup"
+
+// DefaultFinalBackupTimeout is the default time in seconds allowed for
+// the final base backup of a cluster being deleted
+const DefaultFinalBackupTimeout = 3600
+
+// OrderlyShutdownFinalizerName is the finalizer delaying the deletion of
+// a cluster until its instances have been shut down, the primary last
+const OrderlyShutdownFinalizerName = "cnpg.io/orderlyShutdown"
+
+// DefaultPrimaryShutdownTimeout is the default time in seconds allowed
+// for the shutdown of the primary instance, after the replicas, when the
+// cluster is hibernated or deleted
+const DefaultPrimaryShutdownTimeout = 300
+
+// DefaultReplicasShutdownTimeout is the default time in seconds allowed
+// for the shutdown of each replica when the cluster is deleted
+const DefaultReplicasShutdownTimeout = 300
+
+// DefaultShutdownCheckpointTimeout is the default time in seconds allowed
+// for the checkpoint issued by the primary when its Pod is deleted
+const DefaultShutdownCheckpointTimeout = 30
+
+// DefaultSwitchoverCheckpointTimeout is the default time in seconds allowed
+// for the checkpoint issued on the former primary during a switchover
+const DefaultSwitchoverCheckpointTimeout = 300
+
+// SwitchoverCheckpointConfiguration controls the `CHECKPOINT` that the
+// former primary issues during a planned switchover, before being shut down.
+// Checkpointing reduces the amount of WAL that both the promoted standby and
+// the demoted primary need to replay when they come back. The checkpoint is
+// never requested during a failover.
+type SwitchoverCheckpointConfiguration struct {
+	// If enabled (default), the current primary will request a `CHECKPOINT`
+	// before being demoted during a switchover
+	// +kubebuilder:default:=true
+	// +optional
+	Enabled *bool `json:"enabled,omitempty"`
 
-// PostgresConfiguration defines the PostgreSQL configuration
-type PostgresConfiguration struct {
-	// PostgreSQL configuration options (postgresql.conf)
-	Parameters map[string]string `json:"parameters,omitempty"`
+	// The time in seconds that is allowed for the checkpoint to complete.
+	// When the timeout expires the checkpoint is cancelled, and the
+	// switchover proceeds anyway (default 300)
+	// +kubebuilder:default:=300
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Timeout int32 `json:"timeout,omitempty"`
+}
 
-	// PostgreSQL Host Based Authentication rules (lines to be appended
-	// to the pg_hba.conf file)
+// OrderlyShutdownConfiguration is the configuration of the shutdown of
+// the instances when the cluster is hibernated or deleted
+type OrderlyShutdownConfiguration struct {
+	// If enabled, the deletion of the cluster is delayed until the
+	// replicas have been shut down, and then the primary instance. The
+	// hibernation always shuts the primary instance down last
 	// +optional
-	PgHBA []string `json:"pg_hba,omitempty"`
+	Enabled bool `json:"enabled,omitempty"`
 
-	// Requirements to be met by sync replicas. This will affect how the "synchronous_standby_names" parameter will be
-	// set up.
-	SyncReplicaElectionConstraint SyncReplicaElectionConstraints `json:"syncReplicaElectionConstraint,omitempty"`
+	// The time in seconds that is allowed for the smart shutdown of the
+	// primary instance, after the replicas have been shut down. When the
+	// timeout expires during the deletion, the cluster is deleted anyway,
+	// while the hibernation is rolled back (default 300)
+	// +kubebuilder:default:=300
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	PrimaryTimeout int32 `json:"primaryTimeout,omitempty"`
+
+	// The time in seconds that is allowed for the shutdown of each replica
+	// during the deletion of the cluster, from when its Pod is deleted.
+	// When the timeout expires, i.e. because the node of the replica is
+	// unreachable, the primary instance is shut down anyway (default 300)
+	// +kubebuilder:default:=300
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	ReplicasTimeout int32 `json:"replicasTimeout,omitempty"`
+}
+
+// ReplicaJoinMethod is the method used to create the data directory
+// of a new replica
+type ReplicaJoinMethod string
+
+const (
+	// ReplicaJoinMethodPgBaseBackup means the data directory is copied
+	// from the primary via pg_basebackup
+	ReplicaJoinMethodPgBaseBackup ReplicaJoinMethod = "pg_basebackup"
+
+	// ReplicaJoinMethodObjectStore means the data directory is restored
+	// from the latest base backup in the object store
+	ReplicaJoinMethodObjectStore ReplicaJoinMethod = "objectStore"
+)
+
+// DelayedReplicasConfiguration designates the replicas applying the
+// changes with a delay, through the `recovery_min_apply_delay`
+// PostgreSQL parameter
+type DelayedReplicasConfiguration struct {
+	// The names of the instances applying the changes with a delay,
+	// for example `cluster-example-3`. The primary ignores this setting
+	// +kubebuilder:validation:MinItems=1
+	Instances []string `json:"instances"`
+
+	// The time in seconds the delayed replicas wait before applying
+	// the changes committed on the primary
+	// +kubebuilder:validation:Minimum=1
+	MinApplyDelay int32 `json:"minApplyDelay"`
+}
+
+// ReadOnlyConfiguration is the configuration of the read-only mode of
+// the cluster, which rejects the writes while keeping every instance up
+type ReadOnlyConfiguration struct {
+	// When enabled, the primary is configured with
+	// `default_transaction_read_only = on`, which is removed as soon
+	// as this is disabled. The change is applied via a reload
+	// +kubebuilder:default:=false
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+}
+
+// ReplicationConnectionConfiguration contains the settings of the
+// connection a standby opens to stream the changes from the primary
+type ReplicationConnectionConfiguration struct {
+	// The prefix of the `application_name` used by each standby, which
+	// is followed by the instance name, for example `reporting-` makes
+	// `cluster-example-2` connect as `reporting-cluster-example-2`. The
+	// names in `synchronous_standby_names` follow the same rule
+	// +kubebuilder:validation:Pattern=`^[a-zA-Z0-9_-]*$`
+	// +kubebuilder:validation:MaxLength=16
+	// +optional
+	ApplicationNamePrefix string `json:"applicationNamePrefix,omitempty"`
+
+	// The number of seconds of inactivity after which TCP should send
+	// a keepalive message to the primary (`keepalives_idle`)
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	KeepalivesIdle int32 `json:"keepalivesIdle,omitempty"`
+
+	// The number of seconds after which a TCP keepalive message that
+	// is not acknowledged by the primary is retransmitted
+	// (`keepalives_interval`)
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	KeepalivesInterval int32 `json:"keepalivesInterval,omitempty"`
+
+	// The number of TCP keepalives that can be lost before the
+	// connection to the primary is considered dead (`keepalives_count`)
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	KeepalivesCount int32 `json:"keepalivesCount,omitempty"`
+
+	// The number of milliseconds transmitted data may remain
+	// unacknowledged before the connection to the primary is
+	// forcibly closed (`tcp_user_timeout`)
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	TCPUserTimeout int32 `json:"tcpUserTimeout,omitempty"`
+}
+
+// GetConnectionOptions gets the libpq options, in the `key=value` format,
+// to be added to the connection string used to reach the primary
+func (configuration *ReplicationConnectionConfiguration) GetConnectionOptions() []string {
+	if configuration == nil {
+		return nil
+	}
+
+	var options []string
+	for _, option := range []struct {
+		name  string
+		value int32
+	}{
+		{name: "keepalives_idle", value: configuration.KeepalivesIdle},
+		{name: "keepalives_interval", value: configuration.KeepalivesInterval},
+		{name: "keepalives_count", value: configuration.KeepalivesCount},
+		{name: "tcp_user_timeout", value: configuration.TCPUserTimeout},
+	} {
+		if option.value > 0 {
+			options = append(options, fmt.Sprintf("%s=%d", option.name, option.value))
+		}
+	}
+
+	return options
+}
+
+// ReadReplicasConfiguration configures the read replicas, the instances
+// which are scaled independently of `.spec.instances` and never participate
+// in the election of a new primary
+type ReadReplicasConfiguration struct {
+	// The number of read replicas
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:default:=0
+	// +optional
+	Instances int `json:"instances,omitempty"`
+}
+
+// SynchronousCommitLevel is the value of the `synchronous_commit`
+// PostgreSQL parameter
+type SynchronousCommitLevel string
+
+const (
+	// SynchronousCommitOn waits for the WAL to be flushed locally and,
+	// with synchronous replication, on the synchronous standbys
+	SynchronousCommitOn SynchronousCommitLevel = "on"
+
+	// SynchronousCommitOff doesn't wait for the WAL to be flushed
+	SynchronousCommitOff SynchronousCommitLevel = "off"
+
+	// SynchronousCommitLocal waits only for the WAL to be flushed locally
+	SynchronousCommitLocal SynchronousCommitLevel = "local"
+
+	// SynchronousCommitRemoteWrite waits for the synchronous standbys to
+	// have written the WAL
+	SynchronousCommitRemoteWrite SynchronousCommitLevel = "remote_write"
+
+	// SynchronousCommitRemoteApply waits for the synchronous standbys to
+	// have applied the WAL, making the changes visible to their queries
+	SynchronousCommitRemoteApply SynchronousCommitLevel = "remote_apply"
+)
+
+// RequiresSynchronousStandby returns true if the level has an effect
+// only when the primary has some synchronous standbys
+func (level SynchronousCommitLevel) RequiresSynchronousStandby() bool {
+	return level == SynchronousCommitRemoteWrite || level == SynchronousCommitRemoteApply
+}
+
+// ReadinessTolerancePolicy is the behavior of the operator when some
+// instances are not ready
+type ReadinessTolerancePolicy string
+
+const (
+	// ReadinessTolerancePolicyWait means that the operator waits for every
+	// instance to be ready before scaling up the cluster
+	ReadinessTolerancePolicyWait ReadinessTolerancePolicy = "Wait"
+
+	// ReadinessTolerancePolicyTolerate means that the operator doesn't
+	// wait for the instances that are being replaced or that have
+	// been not ready for less than the tolerance period
+	ReadinessTolerancePolicyTolerate ReadinessTolerancePolicy = "Tolerate"
+)
+
+// DefaultReadinessTolerancePeriod is the default time in seconds an instance
+// can be not ready before being considered unavailable
+const DefaultReadinessTolerancePeriod = 30
+
+// ReadinessToleranceConfiguration controls whether transient readiness
+// dips of the instances block the scale up of the cluster. An instance
+// running on a node being drained is being intentionally replaced, and
+// never blocks when the dips are tolerated, while an instance not ready
+// for more than the tolerance period is considered an outage.
+type ReadinessToleranceConfiguration struct {
+	// The behavior of the operator when some instances are not ready:
+	// `Wait` (default) or `Tolerate`
+	// +kubebuilder:validation:Enum:=Wait;Tolerate
+	// +kubebuilder:default:=Wait
+	// +optional
+	Policy ReadinessTolerancePolicy `json:"policy,omitempty"`
+
+	// The time in seconds an instance can be not ready before being
+	// considered unavailable (default 30)
+	// +kubebuilder:default:=30
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Period int32 `json:"period,omitempty"`
+}
+
+// PrimaryUpdateStrategy contains the strategy to follow when upgrading
+// the primary server of the cluster as part of rolling updates
+type PrimaryUpdateStrategy string
+
+// PrimaryUpdateMethod contains the method to use when upgrading
+// the primary server of the cluster as part of rolling updates
+type PrimaryUpdateMethod string
+
+const (
+	// PrimaryUpdateStrategySupervised means that the operator need to wait for the
+	// user to manually issue a switchover request before updating the primary
+	// server (`supervised`)
+	PrimaryUpdateStrategySupervised PrimaryUpdateStrategy = "supervised"
+
+	// PrimaryUpdateStrategyUnsupervised means that the operator will proceed with the
+	// selected PrimaryUpdateMethod to another updated replica and then automatically update
+	// the primary server (`unsupervised`, default)
+	PrimaryUpdateStrategyUnsupervised PrimaryUpdateStrategy = "unsupervised"
+
+	// PrimaryUpdateMethodSwitchover means that the operator will switchover to another updated
+	// replica when it needs to upgrade the primary instance
+	PrimaryUpdateMethodSwitchover PrimaryUpdateMethod = "switchover"
+
+	// PrimaryUpdateMethodRestart means that the operator will restart the primary instance in-place
+	// when it needs to upgrade it
+	PrimaryUpdateMethodRestart PrimaryUpdateMethod = "restart"
+
+	// DefaultPgCtlTimeoutForPromotion is the default for the pg_ctl timeout when a promotion is performed.
+	// It is greater than one year in seconds, big enough to simulate an infinite timeout
+	DefaultPgCtlTimeoutForPromotion = 40000000
+
+	// DefaultPrimaryTransitionStuckTimeout is the default time in seconds
+	// after which a switchover or a failover is reported as stuck
+	DefaultPrimaryTransitionStuckTimeout = 600
+
+	// DefaultCorruptedReplicaRebuildGracePeriod is the default time in
+	// seconds a replica reporting checksum failures is kept before being
+	// rebuilt
+	DefaultCorruptedReplicaRebuildGracePeriod = 600
+
+	// DefaultMaxSwitchoverDelay is the default for the pg_ctl timeout in seconds when a primary PostgreSQL instance
+	// is gracefully shutdown during a switchover.
+	// It is greater than one year in seconds, big enough to simulate an infinite timeout
+	DefaultMaxSwitchoverDelay = 40000000
+)
+
+// PostgresConfiguration defines the PostgreSQL configuration
+type PostgresConfiguration struct {
+	// PostgreSQL configuration options (postgresql.conf)
+	Parameters map[string]string `json:"parameters,omitempty"`
+
+	// PostgreSQL Host Based Authentication rules (lines to be appended
+	// to the pg_hba.conf file)
+	// +optional
+	PgHBA []string `json:"pg_hba,omitempty"`
+
+	// PostgreSQL User Name Maps rules (lines to be appended
+	// to the pg_ident.conf file)
+	// +optional
+	PgIdent []string `json:"pg_ident,omitempty"`
+
+	// Requirements to be met by sync replicas. This will affect how the "synchronous_standby_names" parameter will be
+	// set up.
+	SyncReplicaElectionConstraint SyncReplicaElectionConstraints `json:"syncReplicaElectionConstraint,omitempty"`
 
 	// Specifies the maximum number of seconds to wait when promoting an instance to primary.
 	// Default value is 40000000, greater than one year in seconds,
@@ -773,8 +1881,291 @@ type PostgresConfiguration struct {
 	// Options to specify LDAP configuration
 	// +optional
 	LDAP *LDAPConfig `json:"ldap,omitempty"`
+
+	// What to do when a parameter managed by the operator is overridden
+	// in `postgresql.auto.conf`, for example via `ALTER SYSTEM`:
+	// `report` (default) only sets the `ConfigurationDrift` condition,
+	// `enforce` also resets the overridden parameters
+	// +kubebuilder:validation:Enum=report;enforce
+	// +kubebuilder:default:=report
+	// +optional
+	ConfigurationDriftPolicy ConfigurationDriftPolicy `json:"configurationDriftPolicy,omitempty"`
+
+	// The archive module, available in the PostgreSQL image, used by the
+	// archiver process to archive the WAL files in the background instead
+	// of invoking the `archive_command` of the operator for every file.
+	// The settings of the module are specified in `parameters`.
+	// Requires PostgreSQL 15 or newer: with older versions the
+	// `archive_command` of the operator is used
+	// +optional
+	ArchiveLibrary string `json:"archiveLibrary,omitempty"`
+
+	// The configuration of autovacuum, for the whole cluster and for
+	// specific tables
+	// +optional
+	Autovacuum *AutovacuumConfiguration `json:"autovacuum,omitempty"`
+
+	// Load some relations in the buffer cache via `pg_prewarm` after
+	// PostgreSQL has been started or promoted, to avoid the latency
+	// spikes caused by a cold cache
+	// +optional
+	Prewarm *PrewarmConfiguration `json:"prewarm,omitempty"`
+
+	// The checkpoint settings of the cluster, which control how often
+	// the checkpoints happen and how much WAL is kept in `pg_wal`
+	// +optional
+	Checkpoint *CheckpointConfiguration `json:"checkpoint,omitempty"`
+}
+
+// PrewarmConfiguration contains the relations which the instance manager
+// loads in the buffer cache after PostgreSQL has been started or promoted.
+// The relations are loaded one at a time, and only up to a maximum size,
+// not to saturate the storage
+type PrewarmConfiguration struct {
+	// The relations to be loaded, in the given order
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:MaxItems=64
+	Relations []PrewarmRelation `json:"relations"`
+
+	// The maximum amount of data loaded from every relation (default: `1Gi`).
+	// Only the first blocks of the larger relations are loaded
+	// +optional
+	MaxRelationSize *resource.Quantity `json:"maxRelationSize,omitempty"`
+}
+
+// PrewarmRelation is a relation to be loaded in the buffer cache
+type PrewarmRelation struct {
+	// The name of the database containing the relation
+	// +kubebuilder:validation:MinLength=1
+	DBName string `json:"dbname"`
+
+	// The name of the table or of the index, optionally qualified with
+	// its schema, as accepted by the `regclass` type
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+}
+
+// defaultPrewarmMaxRelationSize is the maximum amount of data loaded
+// from every relation when not specified
+var defaultPrewarmMaxRelationSize = resource.MustParse("1Gi")
+
+// GetMaxRelationSize gets the maximum amount of data, in bytes, loaded
+// from every relation
+func (configuration *PrewarmConfiguration) GetMaxRelationSize() int64 {
+	if configuration.MaxRelationSize == nil {
+		return defaultPrewarmMaxRelationSize.Value()
+	}
+	return configuration.MaxRelationSize.Value()
+}
+
+// AutovacuumConfiguration contains the autovacuum settings of the
+// cluster, which are written in `postgresql.conf` and can't be set in
+// `parameters` too, and the autovacuum storage parameters of single tables
+type AutovacuumConfiguration struct {
+	// Whether the autovacuum launcher is started (`autovacuum`)
+	// +optional
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// The maximum number of autovacuum processes running at any one time
+	// (`autovacuum_max_workers`). Changing it requires a restart
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=262143
+	// +optional
+	MaxWorkers *int32 `json:"maxWorkers,omitempty"`
+
+	// The minimum delay in seconds between autovacuum runs on any
+	// given database (`autovacuum_naptime`)
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=2147483
+	// +optional
+	Naptime *int32 `json:"naptime,omitempty"`
+
+	// The minimum number of updated or deleted tuples needed to trigger
+	// a vacuum in any one table (`autovacuum_vacuum_threshold`)
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	VacuumThreshold *int32 `json:"vacuumThreshold,omitempty"`
+
+	// The fraction of the table size to add to `vacuumThreshold` when
+	// deciding whether to trigger a vacuum (`autovacuum_vacuum_scale_factor`),
+	// between 0 and 100
+	// +optional
+	VacuumScaleFactor string `json:"vacuumScaleFactor,omitempty"`
+
+	// The minimum number of inserted, updated or deleted tuples needed to
+	// trigger an analyze in any one table (`autovacuum_analyze_threshold`)
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	AnalyzeThreshold *int32 `json:"analyzeThreshold,omitempty"`
+
+	// The fraction of the table size to add to `analyzeThreshold` when
+	// deciding whether to trigger an analyze (`autovacuum_analyze_scale_factor`),
+	// between 0 and 100
+	// +optional
+	AnalyzeScaleFactor string `json:"analyzeScaleFactor,omitempty"`
+
+	// The cost delay in milliseconds used in automatic vacuum operations
+	// (`autovacuum_vacuum_cost_delay`), -1 to use `vacuum_cost_delay`
+	// +kubebuilder:validation:Minimum=-1
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	VacuumCostDelay *int32 `json:"vacuumCostDelay,omitempty"`
+
+	// The cost limit used in automatic vacuum operations
+	// (`autovacuum_vacuum_cost_limit`), -1 to use `vacuum_cost_limit`
+	// +kubebuilder:validation:Minimum=-1
+	// +kubebuilder:validation:Maximum=10000
+	// +optional
+	VacuumCostLimit *int32 `json:"vacuumCostLimit,omitempty"`
+
+	// The maximum age in transactions of the `pg_class.relfrozenxid` of a
+	// table before a vacuum is forced to prevent the transaction ID
+	// wraparound (`autovacuum_freeze_max_age`). Changing it requires a restart
+	// +kubebuilder:validation:Minimum=100000
+	// +kubebuilder:validation:Maximum=2000000000
+	// +optional
+	FreezeMaxAge *int32 `json:"freezeMaxAge,omitempty"`
+
+	// The autovacuum storage parameters of single tables, which are
+	// applied on the primary
+	// +optional
+	Tables []AutovacuumTableConfiguration `json:"tables,omitempty"`
+}
+
+// AutovacuumTableConfiguration contains the autovacuum storage parameters
+// of a table
+type AutovacuumTableConfiguration struct {
+	// The name of the database containing the table
+	// +kubebuilder:validation:MinLength=1
+	DBName string `json:"dbname"`
+
+	// The name of the table, in the `schema.table` format. Tables
+	// without a schema are looked up in the `public` schema
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// The autovacuum storage parameters of the table, such as
+	// `autovacuum_vacuum_scale_factor`, and of its TOAST table, with
+	// the `toast.` prefix. The autovacuum storage parameters which are
+	// not listed are reset
+	Parameters map[string]string `json:"parameters"`
+}
+
+// GetParameters gets the PostgreSQL parameters corresponding to the
+// autovacuum settings of the cluster
+func (configuration *AutovacuumConfiguration) GetParameters() map[string]string {
+	if configuration == nil {
+		return nil
+	}
+
+	parameters := make(map[string]string)
+	if configuration.Enabled != nil {
+		parameters["autovacuum"] = strconv.FormatBool(*configuration.Enabled)
+	}
+	setInt32Parameter := func(name string, value *int32) {
+		if value != nil {
+			parameters[name] = strconv.Itoa(int(*value))
+		}
+	}
+	setInt32Parameter("autovacuum_max_workers", configuration.MaxWorkers)
+	setInt32Parameter("autovacuum_naptime", configuration.Naptime)
+	setInt32Parameter("autovacuum_vacuum_threshold", configuration.VacuumThreshold)
+	setInt32Parameter("autovacuum_analyze_threshold", configuration.AnalyzeThreshold)
+	setInt32Parameter("autovacuum_vacuum_cost_delay", configuration.VacuumCostDelay)
+	setInt32Parameter("autovacuum_vacuum_cost_limit", configuration.VacuumCostLimit)
+	setInt32Parameter("autovacuum_freeze_max_age", configuration.FreezeMaxAge)
+	if configuration.VacuumScaleFactor != "" {
+		parameters["autovacuum_vacuum_scale_factor"] = configuration.VacuumScaleFactor
+	}
+	if configuration.AnalyzeScaleFactor != "" {
+		parameters["autovacuum_analyze_scale_factor"] = configuration.AnalyzeScaleFactor
+	}
+
+	return parameters
 }
 
+// CheckpointConfiguration contains the checkpoint settings of the cluster,
+// which are written in `postgresql.conf` and can't be set in `parameters`
+// too. All of them are applied with a reload
+type CheckpointConfiguration struct {
+	// The size the WAL is allowed to grow to between automatic checkpoints
+	// (`max_wal_size`), rounded down to megabytes. It is a soft limit: the
+	// WAL files which are not archived yet are kept in `pg_wal` anyway
+	// +optional
+	MaxWalSize *resource.Quantity `json:"maxWalSize,omitempty"`
+
+	// The size below which the WAL files are recycled instead of being
+	// removed at checkpoint time (`min_wal_size`), rounded down to megabytes
+	// +optional
+	MinWalSize *resource.Quantity `json:"minWalSize,omitempty"`
+
+	// The maximum time in seconds between automatic checkpoints
+	// (`checkpoint_timeout`)
+	// +kubebuilder:validation:Minimum=30
+	// +kubebuilder:validation:Maximum=86400
+	// +optional
+	Timeout *int32 `json:"timeout,omitempty"`
+
+	// The fraction of the time between checkpoints over which the writes
+	// of a checkpoint are spread (`checkpoint_completion_target`),
+	// between 0 and 1
+	// +optional
+	CompletionTarget string `json:"completionTarget,omitempty"`
+}
+
+// GetParameters gets the PostgreSQL parameters corresponding to the
+// checkpoint settings of the cluster
+func (configuration *CheckpointConfiguration) GetParameters() map[string]string {
+	if configuration == nil {
+		return nil
+	}
+
+	parameters := make(map[string]string)
+	setSizeParameter := func(name string, value *resource.Quantity) {
+		if value != nil {
+			parameters[name] = fmt.Sprintf("%dMB", value.Value()/(1024*1024))
+		}
+	}
+	setSizeParameter("max_wal_size", configuration.MaxWalSize)
+	setSizeParameter("min_wal_size", configuration.MinWalSize)
+	if configuration.Timeout != nil {
+		parameters["checkpoint_timeout"] = strconv.Itoa(int(*configuration.Timeout))
+	}
+	if configuration.CompletionTarget != "" {
+		parameters["checkpoint_completion_target"] = configuration.CompletionTarget
+	}
+
+	return parameters
+}
+
+// ReplicasUnavailablePolicy is the policy applied to the synchronous
+// replication when fewer standbys than `minSyncReplicas` are ready
+type ReplicasUnavailablePolicy string
+
+const (
+	// ReplicasUnavailablePolicySelfHeal means the number of synchronous
+	// standbys is lowered to the ready ones, keeping the primary writable
+	ReplicasUnavailablePolicySelfHeal ReplicasUnavailablePolicy = "selfHeal"
+
+	// ReplicasUnavailablePolicyEnforceSync means the commits wait for
+	// `minSyncReplicas` standbys even when they are not ready
+	ReplicasUnavailablePolicyEnforceSync ReplicasUnavailablePolicy = "enforceSync"
+)
+
+// ConfigurationDriftPolicy is the policy applied when a parameter managed
+// by the operator is overridden in `postgresql.auto.conf`
+type ConfigurationDriftPolicy string
+
+const (
+	// ConfigurationDriftPolicyReport means the drift is only reported
+	// in the cluster conditions
+	ConfigurationDriftPolicyReport ConfigurationDriftPolicy = "report"
+
+	// ConfigurationDriftPolicyEnforce means the overridden parameters
+	// are reset, restoring the configuration chosen by the operator
+	ConfigurationDriftPolicyEnforce ConfigurationDriftPolicy = "enforce"
+)
+
 // BootstrapConfiguration contains information about how to create the PostgreSQL
 // cluster. Only a single bootstrap method can be defined among the supported
 // ones. `initdb` will be used as the bootstrap method if left
@@ -847,6 +2238,20 @@ type LDAPBindSearchAuth struct {
 	SearchFilter string `json:"searchFilter,omitempty"`
 }
 
+// ServiceNamingConfiguration is the naming scheme of the services of the
+// cluster, which are named `<prefix><cluster name><suffix>-rw`, and the
+// same for the `-ro` and `-r` ones
+type ServiceNamingConfiguration struct {
+	// The prefix of the name of the services
+	// +optional
+	Prefix string `json:"prefix,omitempty"`
+
+	// The suffix of the name of the services, placed
+	// before the `-rw`, `-ro` and `-r` ones
+	// +optional
+	Suffix string `json:"suffix,omitempty"`
+}
+
 // CertificatesConfiguration contains the needed configurations to handle server certificates.
 type CertificatesConfiguration struct {
 	// The secret containing the Server CA certificate. If not defined, a new secret will be created
@@ -885,6 +2290,45 @@ type CertificatesConfiguration struct {
 
 	// The list of the server alternative DNS names to be added to the generated server TLS certificates, when required.
 	ServerAltDNSNames []string `json:"serverAltDNSNames,omitempty"`
+
+	// The cert-manager issuer signing the server TLS certificate, instead
+	// of the self-signed CA of the operator. The operator requests the
+	// certificate through a cert-manager `Certificate`, which stores it
+	// together with the issuer CA in the `<cluster>-server` secret, and
+	// cert-manager takes care of its renewal.
+	// Cannot be used together with ServerCASecret and ServerTLSSecret.
+	// +optional
+	ServerIssuerRef *CertificateIssuerReference `json:"serverIssuerRef,omitempty"`
+}
+
+const (
+	// CertificateIssuerKind is the kind of the namespaced cert-manager issuers
+	CertificateIssuerKind = "Issuer"
+
+	// CertificateClusterIssuerKind is the kind of the cluster-wide cert-manager issuers
+	CertificateClusterIssuerKind = "ClusterIssuer"
+)
+
+// CertificateIssuerReference is a reference to a cert-manager issuer
+type CertificateIssuerReference struct {
+	// The name of the issuer
+	Name string `json:"name"`
+
+	// The kind of the issuer, either `Issuer` (default), which must be
+	// in the namespace of the cluster, or `ClusterIssuer`
+	// +kubebuilder:validation:Enum=Issuer;ClusterIssuer
+	// +kubebuilder:default:=Issuer
+	// +optional
+	Kind string `json:"kind,omitempty"`
+}
+
+// GetKind gets the kind of the issuer, defaulting to `Issuer`
+func (ref *CertificateIssuerReference) GetKind() string {
+	if ref.Kind == "" {
+		return CertificateIssuerKind
+	}
+
+	return ref.Kind
 }
 
 // CertificatesStatus contains configuration certificates and related expiration dates.
@@ -1068,9 +2512,19 @@ type BackupSource struct {
 // BootstrapPgBaseBackup contains the configuration required to take
 // a physical backup of an existing PostgreSQL cluster
 type BootstrapPgBaseBackup struct {
-	// The name of the server of which we need to take a physical backup
-	// +kubebuilder:validation:MinLength=1
-	Source string `json:"source"`
+	// The name of the server of which we need to take a physical backup,
+	// among the ones defined in `externalClusters`. Cannot be used together
+	// with `sourceCluster`
+	// +optional
+	Source string `json:"source,omitempty"`
+
+	// A running cluster, managed by the operator in the same namespace,
+	// to be cloned. The data is copied from its primary, through its `-rw`
+	// service, using the `streaming_replica` user and the certificates
+	// generated by the operator for the source cluster. Cannot be used
+	// together with `source`
+	// +optional
+	SourceCluster *LocalObjectReference `json:"sourceCluster,omitempty"`
 
 	// Name of the database used by the application. Default: `app`.
 	// +optional
@@ -1125,11 +2579,13 @@ type StorageConfiguration struct {
 	// StorageClass to use for database data (`PGDATA`). Applied after
 	// evaluating the PVC template, if available.
 	// If not specified, generated PVCs will be satisfied by the
-	// default storage class
+	// default storage class. Changes are applied only to the PVCs
+	// created afterwards, as the storage class of a PVC is immutable
 	// +optional
 	StorageClass *string `json:"storageClass,omitempty"`
 
-	// Size of the storage. Required if not already specified in the PVC template.
+	// Size of the storage. When not specified in the PVC template either,
+	// it is set by the operator to the configured default size.
 	// Changes to this field are automatically reapplied to the created PVCs.
 	// Size cannot be decreased.
 	Size string `json:"size,omitempty"`
@@ -1139,6 +2595,14 @@ type StorageConfiguration struct {
 	// +kubebuilder:default:=true
 	ResizeInUseVolumes *bool `json:"resizeInUseVolumes,omitempty"`
 
+	// The path inside the volume where the data is stored, instead of its
+	// root, for the storage backends provisioning a shared root volume or
+	// requiring a subdirectory. It must be a relative path which doesn't
+	// leave the volume, and it can't be changed after the volumes have
+	// been created
+	// +optional
+	SubPath string `json:"subPath,omitempty"`
+
 	// Template to be used to generate the Persistent Volume Claim
 	// +optional
 	PersistentVolumeClaimTemplate *corev1.PersistentVolumeClaimSpec `json:"pvcTemplate,omitempty"`
@@ -1166,6 +2630,24 @@ func (s *StorageConfiguration) GetSizeOrNil() *resource.Quantity {
 	return nil
 }
 
+// GetStorageClassName returns the storage class requested for the PVCs,
+// which is the one in the PVC template unless overridden
+func (s *StorageConfiguration) GetStorageClassName() *string {
+	if s == nil {
+		return nil
+	}
+
+	if s.StorageClass != nil {
+		return s.StorageClass
+	}
+
+	if s.PersistentVolumeClaimTemplate != nil {
+		return s.PersistentVolumeClaimTemplate.StorageClassName
+	}
+
+	return nil
+}
+
 // SyncReplicaElectionConstraints contains the constraints for sync replicas election.
 //
 // For anti-affinity parameters two instances are considered in the same location
@@ -1253,6 +2735,26 @@ const (
 	DefaultBackupTarget = BackupTargetPrimary
 )
 
+// WalArchiveBackend is the tool archiving the WAL files
+type WalArchiveBackend string
+
+const (
+	// WalArchiveBackendBarman means the WAL files are archived by
+	// barman-cloud-wal-archive in the object store of the cluster
+	WalArchiveBackendBarman = WalArchiveBackend("barman")
+
+	// WalArchiveBackendCustom means the WAL files are archived by the
+	// command configured in the cluster
+	WalArchiveBackendCustom = WalArchiveBackend("custom")
+
+	// WalArchiveBackendWalG means the WAL files are archived by wal-g
+	// in the storage configured in the cluster
+	WalArchiveBackendWalG = WalArchiveBackend("walg")
+
+	// DefaultWalArchiveBackend is the default WalArchiveBackend
+	DefaultWalArchiveBackend = WalArchiveBackendBarman
+)
+
 // CompressionType encapsulates the available types of compression
 type CompressionType string
 
@@ -1268,8 +2770,26 @@ const (
 
 	// CompressionTypeSnappy means snappy compression is performed
 	CompressionTypeSnappy = CompressionType("snappy")
+
+	// CompressionTypeZstd means zstd compression is performed
+	CompressionTypeZstd = CompressionType("zstd")
 )
 
+const (
+	// ZstdMinLevel is the lowest zstd compression level
+	ZstdMinLevel = 1
+
+	// ZstdMaxLevel is the highest zstd compression level, including
+	// the "ultra" ones
+	ZstdMaxLevel = 22
+)
+
+// MaxWalRestoreParallel is the highest number of WAL files that can be
+// restored in parallel. Every WAL file prefetched ahead of the one
+// requested by PostgreSQL is stored in the spool directory, so this
+// limits the space used in the scratch volume of the Pods
+const MaxWalRestoreParallel = 64
+
 // EncryptionType encapsulated the available types of encryption
 type EncryptionType string
 
@@ -1308,6 +2828,15 @@ type BarmanObjectStoreConfiguration struct {
 	// The potential credentials for each cloud provider
 	BarmanCredentials `json:",inline"`
 
+	// The CSI volume, i.e. provided by the Secrets Store CSI driver, containing
+	// the credentials to access the object store, which are read from it
+	// instead of from the secrets. The section of the cloud provider
+	// is still required, but must not reference any secret. The volume
+	// is mounted in the instances, and is only supported by the backup
+	// object store
+	// +optional
+	CredentialsVolume *corev1.CSIVolumeSource `json:"credentialsVolume,omitempty"`
+
 	// Endpoint to be used to upload data to the cloud,
 	// overriding the automatic endpoint discovery
 	EndpointURL string `json:"endpointURL,omitempty"`
@@ -1347,6 +2876,233 @@ type BarmanObjectStoreConfiguration struct {
 	HistoryTags map[string]string `json:"historyTags,omitempty"`
 }
 
+// MergeWith creates a new object store configuration where the fields
+// set in the passed overrides replace the ones of this configuration,
+// and the tags are merged. The server name is taken only from the
+// overrides, so that each cluster defaults to its own
+func (objectStore *BarmanObjectStoreConfiguration) MergeWith(
+	overrides *BarmanObjectStoreConfiguration,
+) *BarmanObjectStoreConfiguration {
+	result := objectStore.DeepCopy()
+	result.ServerName = ""
+	if overrides == nil {
+		return result
+	}
+
+	if overrides.BarmanCredentials.ArePopulated() {
+		result.BarmanCredentials = *overrides.BarmanCredentials.DeepCopy()
+		result.CredentialsVolume = overrides.CredentialsVolume.DeepCopy()
+	}
+	if overrides.EndpointURL != "" {
+		result.EndpointURL = overrides.EndpointURL
+	}
+	if overrides.EndpointCA != nil {
+		result.EndpointCA = overrides.EndpointCA.DeepCopy()
+	}
+	if overrides.DestinationPath != "" {
+		result.DestinationPath = overrides.DestinationPath
+	}
+	if overrides.ServerName != "" {
+		result.ServerName = overrides.ServerName
+	}
+	if overrides.Wal != nil {
+		result.Wal = overrides.Wal.DeepCopy()
+	}
+	if overrides.Data != nil {
+		result.Data = overrides.Data.DeepCopy()
+	}
+	result.Tags = mergeTags(result.Tags, overrides.Tags)
+	result.HistoryTags = mergeTags(result.HistoryTags, overrides.HistoryTags)
+
+	return result
+}
+
+// mergeTags merges two sets of tags, giving precedence to the overrides
+func mergeTags(tags, overrides map[string]string) map[string]string {
+	if len(overrides) == 0 {
+		return tags
+	}
+
+	result := make(map[string]string, len(tags)+len(overrides))
+	for key, value := range tags {
+		result[key] = value
+	}
+	for key, value := range overrides {
+		result[key] = value
+	}
+	return result
+}
+
+// TablespaceConfiguration is the configuration of a tablespace
+// stored in a dedicated volume
+type TablespaceConfiguration struct {
+	// The name of the tablespace, which can only contain lowercase letters,
+	// digits and underscores. Names starting with `pg_` are reserved
+	// +kubebuilder:validation:Pattern=`^[a-z]([a-z0-9_]*[a-z0-9])?$`
+	// +kubebuilder:validation:MaxLength=59
+	Name string `json:"name"`
+
+	// The configuration of the storage of the tablespace
+	Storage StorageConfiguration `json:"storage"`
+}
+
+// GetVolumeSuffix gets the suffix appended to the instance name to get
+// the name of the PVC dedicated to this tablespace
+func (tablespace TablespaceConfiguration) GetVolumeSuffix() string {
+	return TablespaceVolumeInfix + strings.ReplaceAll(tablespace.Name, "_", "-")
+}
+
+// GetVolumeName gets the name of the Pod volume containing this
+// tablespace. Underscores are not allowed in the names of the
+// Kubernetes objects, and are replaced with dashes
+func (tablespace TablespaceConfiguration) GetVolumeName() string {
+	return "tbs-" + strings.ReplaceAll(tablespace.Name, "_", "-")
+}
+
+// PublicationOperation is a kind of change published by a publication
+// +kubebuilder:validation:Enum=insert;update;delete;truncate
+type PublicationOperation string
+
+const (
+	// PublicationOperationInsert publishes the inserted rows
+	PublicationOperationInsert PublicationOperation = "insert"
+
+	// PublicationOperationUpdate publishes the updated rows
+	PublicationOperationUpdate PublicationOperation = "update"
+
+	// PublicationOperationDelete publishes the deleted rows
+	PublicationOperationDelete PublicationOperation = "delete"
+
+	// PublicationOperationTruncate publishes the truncated tables
+	PublicationOperationTruncate PublicationOperation = "truncate"
+)
+
+// PublicationConfiguration is the configuration of a logical replication
+// publication managed by the operator
+type PublicationConfiguration struct {
+	// The name of the publication
+	// +kubebuilder:validation:Pattern=`^[a-z_][a-z0-9_]*$`
+	// +kubebuilder:validation:MaxLength=63
+	Name string `json:"name"`
+
+	// The name of the database containing the publication
+	// +kubebuilder:validation:MinLength=1
+	DBName string `json:"dbname"`
+
+	// Publish the changes of every table in the database, including
+	// the ones created in the future. Can't be used together with `tables`
+	// +optional
+	AllTables bool `json:"allTables,omitempty"`
+
+	// The tables whose changes are published, in the `schema.table`
+	// format. Tables without a schema are looked up in the `public` schema
+	// +optional
+	Tables []string `json:"tables,omitempty"`
+
+	// The kinds of changes to be published, every kind when empty
+	// +optional
+	Publish []PublicationOperation `json:"publish,omitempty"`
+}
+
+// GetPublish gets the kinds of changes published by this publication
+func (publication PublicationConfiguration) GetPublish() []PublicationOperation {
+	if len(publication.Publish) == 0 {
+		return []PublicationOperation{
+			PublicationOperationInsert,
+			PublicationOperationUpdate,
+			PublicationOperationDelete,
+			PublicationOperationTruncate,
+		}
+	}
+
+	return publication.Publish
+}
+
+// PublicationStatus is the state of a publication managed by the operator
+type PublicationStatus struct {
+	// The name of the publication
+	Name string `json:"name"`
+
+	// The name of the database containing the publication
+	DBName string `json:"dbname"`
+
+	// Whether the publication matches its configuration
+	Applied bool `json:"applied"`
+
+	// The error raised while applying the configuration, if any
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// ExtensionConfiguration is the configuration of a PostgreSQL extension
+// managed by the operator
+type ExtensionConfiguration struct {
+	// The name of the extension
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=63
+	Name string `json:"name"`
+
+	// The databases where the extension is created. When empty, the
+	// extension is only loaded via `shared_preload_libraries`
+	// +optional
+	DBNames []string `json:"dbnames,omitempty"`
+
+	// The version of the extension, which is updated when changed. When
+	// not specified, the default version is created and never updated
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// The schema where the objects of the extension are created
+	// +optional
+	Schema string `json:"schema,omitempty"`
+
+	// The libraries needed by the extension, which are added to
+	// `shared_preload_libraries`. The ones needed by `pgaudit`,
+	// `pg_stat_statements` and `auto_explain` are added automatically
+	// +optional
+	SharedPreloadLibraries []string `json:"sharedPreloadLibraries,omitempty"`
+}
+
+// GetSharedPreloadLibraries gets the libraries needed by the extension,
+// including the ones of the extensions known by the operator
+func (extension ExtensionConfiguration) GetSharedPreloadLibraries() []string {
+	libraries := append([]string(nil), extension.SharedPreloadLibraries...)
+	for _, managedExtension := range postgres.ManagedExtensions {
+		if managedExtension.Name != extension.Name {
+			continue
+		}
+		for _, library := range managedExtension.SharedPreloadLibraries {
+			if !slices.Contains(libraries, library) {
+				libraries = append(libraries, library)
+			}
+		}
+	}
+
+	return libraries
+}
+
+// ExtensionStatus is the state of an extension managed by the operator
+// in a database
+type ExtensionStatus struct {
+	// The name of the extension
+	Name string `json:"name"`
+
+	// The name of the database containing the extension
+	DBName string `json:"dbname"`
+
+	// The installed version of the extension
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// Whether the extension matches its configuration
+	Applied bool `json:"applied"`
+
+	// The error raised while applying the configuration, or the
+	// libraries which still need to be loaded, if any
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
 // BackupConfiguration defines how the backup of the cluster are taken.
 // Currently the only supported backup method is barmanObjectStore.
 // For details and examples refer to the Backup and Recovery section of the
@@ -1355,6 +3111,13 @@ type BackupConfiguration struct {
 	// The configuration for the barman-cloud tool suite
 	BarmanObjectStore *BarmanObjectStoreConfiguration `json:"barmanObjectStore,omitempty"`
 
+	// The name of an object store defined in the operator configuration,
+	// used as the base for `barmanObjectStore`. The fields set in
+	// `barmanObjectStore` override the ones of the named object store,
+	// with the exception of `serverName` which is never inherited
+	// +optional
+	ObjectStoreName string `json:"objectStoreName,omitempty"`
+
 	// RetentionPolicy is the retention policy to be used for backups
 	// and WALs (i.e. '60d'). The retention policy is expressed in the form
 	// of `XXu` where `XX` is a positive integer and `u` is in `[dwm]` -
@@ -1370,31 +3133,240 @@ type BackupConfiguration struct {
 	// +kubebuilder:validation:Enum=primary;prefer-standby
 	// +kubebuilder:default:=primary
 	Target BackupTarget `json:"target,omitempty"`
+
+	// The final base backup taken before the cluster is deleted
+	// +optional
+	FinalBackup *FinalBackupConfiguration `json:"finalBackup,omitempty"`
+
+	// The asynchronous mirroring of the WAL archive to a secondary
+	// object store, i.e. in a different region. The base backups are
+	// not mirrored
+	// +optional
+	WalMirror *WalMirrorConfiguration `json:"walMirror,omitempty"`
+
+	// The tool archiving the WAL files: `barman` (default) uses
+	// `barman-cloud-wal-archive` with the `barmanObjectStore`
+	// configuration, `custom` runs the command configured in
+	// `customWalArchive` and `walg` uses wal-g with the `walG`
+	// configuration
+	// +kubebuilder:validation:Enum=barman;custom;walg
+	// +optional
+	WalArchiveBackend WalArchiveBackend `json:"walArchiveBackend,omitempty"`
+
+	// The command archiving the WAL files, required when
+	// `walArchiveBackend` is `custom`
+	// +optional
+	CustomWalArchive *CustomWalArchiveConfiguration `json:"customWalArchive,omitempty"`
+
+	// The storage where wal-g archives the WAL files, required when
+	// `walArchiveBackend` is `walg`
+	// +optional
+	WalG *WalGConfiguration `json:"walG,omitempty"`
+}
+
+// WalGConfiguration is the storage where wal-g archives the WAL files,
+// and restores them from
+type WalGConfiguration struct {
+	// The path where the WAL files are stored, starting with `s3://`,
+	// `gs://` or `azure://` depending on the cloud provider,
+	// i.e. `s3://bucket/path/to/folder`
+	// +kubebuilder:validation:MinLength=1
+	DestinationPath string `json:"destinationPath"`
+
+	// Endpoint to be used to access the S3 compatible storage,
+	// overriding the automatic endpoint discovery
+	// +optional
+	EndpointURL string `json:"endpointURL,omitempty"`
+
+	// The credentials to use to access the storage
+	BarmanCredentials `json:",inline"`
+
+	// The compression method of the WAL files, among `lz4` (default),
+	// `lzma`, `zstd` and `brotli`
+	// +kubebuilder:validation:Enum=lz4;lzma;zstd;brotli
+	// +optional
+	Compression string `json:"compression,omitempty"`
+}
+
+// CustomWalArchiveConfiguration is the configuration of a command,
+// available in the image of the cluster, archiving the WAL files
+// in place of barman-cloud-wal-archive
+type CustomWalArchiveConfiguration struct {
+	// The command archiving a WAL file, run with `/bin/sh -c` from
+	// the PGDATA directory. As in the `archive_command` of PostgreSQL,
+	// `%p` is replaced by the path of the WAL file, `%f` by its name and
+	// `%%` by a `%` character. The command must exit with a zero status
+	// only when the WAL file has been safely archived
+	// +kubebuilder:validation:MinLength=1
+	Command string `json:"command"`
+
+	// The environment variables passed to the command, i.e. the
+	// credentials of the storage. Only the `value` and the
+	// `valueFrom.secretKeyRef` sources are supported
+	// +optional
+	Env []corev1.EnvVar `json:"env,omitempty"`
+}
+
+// WalMirrorConfiguration is the configuration of the asynchronous
+// mirroring of the WAL archive. A job, periodically started by the
+// operator, copies the WAL files archived in the object store of the
+// cluster since its previous execution to the secondary object store,
+// without involving the PostgreSQL instances. Only the WAL files are
+// copied: the base backups need to be taken in the secondary object
+// store separately
+type WalMirrorConfiguration struct {
+	// The secondary object store where the WAL files are copied
+	BarmanObjectStore BarmanObjectStoreConfiguration `json:"barmanObjectStore"`
+
+	// The schedule of the mirroring job, in the Cron format used by the
+	// Kubernetes CronJobs (default: every five minutes)
+	// +kubebuilder:default:="*/5 * * * *"
+	// +optional
+	Schedule string `json:"schedule,omitempty"`
+
+	// Suspend the execution of the mirroring job, without losing track
+	// of the WAL files already copied
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+}
+
+// GetSchedule gets the schedule of the mirroring job
+func (mirror *WalMirrorConfiguration) GetSchedule() string {
+	if mirror.Schedule == "" {
+		return "*/5 * * * *"
+	}
+	return mirror.Schedule
+}
+
+// FinalBackupConfiguration is the configuration of the base backup taken
+// when a cluster is deleted. The deletion is delayed by a finalizer until
+// the backup is completed or the timeout expires
+type FinalBackupConfiguration struct {
+	// Take a final base backup before deleting the cluster
+	Enabled bool `json:"enabled"`
+
+	// The maximum time in seconds to wait for the final backup to be
+	// completed before deleting the cluster anyway (default 3600)
+	// +kubebuilder:default:=3600
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Timeout int32 `json:"timeout,omitempty"`
 }
 
 // WalBackupConfiguration is the configuration of the backup of the
 // WAL stream
 type WalBackupConfiguration struct {
 	// Compress a WAL file before sending it to the object store. Available
-	// options are empty string (no compression, default), `gzip`, `bzip2` or `snappy`.
-	// +kubebuilder:validation:Enum=gzip;bzip2;snappy
+	// options are empty string (no compression, default), `gzip`, `bzip2`,
+	// `snappy` or `zstd`.
+	// +kubebuilder:validation:Enum=gzip;bzip2;snappy;zstd
 	Compression CompressionType `json:"compression,omitempty"`
 
-	// Whenever to force the encryption of files (if the bucket is
-	// not already configured for that).
-	// Allowed options are empty string (use the bucket policy, default),
-	// `AES256` and `aws:kms`
-	// +kubebuilder:validation:Enum=AES256;"aws:kms"
-	Encryption EncryptionType `json:"encryption,omitempty"`
+	// The tuning of the `zstd` compression, only allowed when the
+	// `compression` is `zstd`
+	// +optional
+	Zstd *ZstdCompressionConfiguration `json:"zstd,omitempty"`
+
+	// Whenever to force the encryption of files (if the bucket is
+	// not already configured for that).
+	// Allowed options are empty string (use the bucket policy, default),
+	// `AES256` and `aws:kms`
+	// +kubebuilder:validation:Enum=AES256;"aws:kms"
+	Encryption EncryptionType `json:"encryption,omitempty"`
+
+	// Number of WAL files to be either archived in parallel (when the
+	// PostgreSQL instance is archiving to a backup object store) or
+	// restored in parallel (when a PostgreSQL standby is fetching WAL
+	// files from a recovery object store). If not specified, WAL files
+	// will be processed one at a time. It accepts a positive integer as a
+	// value - with 1 being the minimum accepted value.
+	// +kubebuilder:validation:Minimum=1
+	MaxParallel int `json:"maxParallel,omitempty"`
+
+	// Number of WAL files to be restored in parallel when PostgreSQL
+	// requests a WAL file from the object store, overriding `maxParallel`
+	// for the restore only. The WAL files following the requested one are
+	// prefetched in a spool directory of the scratch volume, so every
+	// unit above 1 uses up to a WAL segment of disk space. It accepts a
+	// value between 1 and 64
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=64
+	// +optional
+	RestoreMaxParallel int `json:"restoreMaxParallel,omitempty"`
+
+	// Archive the WAL files through a staging directory, on the same volume
+	// of `pg_wal`: the `archive_command` only copies the WAL file in the
+	// staging directory, and the instance manager archives the staged files
+	// in the background, in batches of up to `maxParallel` files. Only
+	// supported by the backup object store
+	// +optional
+	Staging *WalStagingConfiguration `json:"staging,omitempty"`
+
+	// Maximum number of `barman-cloud-wal-archive` processes running at the
+	// same time in an instance Pod, across the invocations of the
+	// `archive_command` and the archiving of the staged WAL files. When the
+	// limit is reached, archiving waits for a running process to complete,
+	// so that it can't starve PostgreSQL of CPU. If not specified, the
+	// number of processes is not limited. Only used by the backup object store
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxConcurrentArchives int `json:"maxConcurrentArchives,omitempty"`
+}
+
+// WalStagingConfiguration is the configuration of the archiving of the
+// WAL files through a local staging directory
+type WalStagingConfiguration struct {
+	// The maximum number of WAL files waiting in the staging directory to
+	// be archived (default: 256). When it is reached, the `archive_command`
+	// fails, and PostgreSQL keeps the WAL files in `pg_wal` retrying later
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxPendingFiles int `json:"maxPendingFiles,omitempty"`
+}
+
+// DefaultWalStagingMaxPendingFiles is the default maximum number of WAL
+// files waiting in the staging directory to be archived
+const DefaultWalStagingMaxPendingFiles = 256
+
+// IsStagingEnabled checks if the WAL files are archived through
+// a local staging directory
+func (wal *WalBackupConfiguration) IsStagingEnabled() bool {
+	return wal != nil && wal.Staging != nil
+}
+
+// GetMaxPendingFiles gets the maximum number of WAL files waiting in
+// the staging directory to be archived
+func (staging *WalStagingConfiguration) GetMaxPendingFiles() int {
+	if staging == nil || staging.MaxPendingFiles <= 0 {
+		return DefaultWalStagingMaxPendingFiles
+	}
+	return staging.MaxPendingFiles
+}
+
+// GetRestoreMaxParallel gets the number of WAL files to be restored in
+// parallel, including the one requested by PostgreSQL. When falling back
+// to maxParallel, the value is capped to MaxWalRestoreParallel
+func (wal *WalBackupConfiguration) GetRestoreMaxParallel() int {
+	switch {
+	case wal == nil:
+		return 1
+	case wal.RestoreMaxParallel > 0:
+		return wal.RestoreMaxParallel
+	case wal.MaxParallel > MaxWalRestoreParallel:
+		return MaxWalRestoreParallel
+	case wal.MaxParallel > 1:
+		return wal.MaxParallel
+	default:
+		return 1
+	}
+}
 
-	// Number of WAL files to be either archived in parallel (when the
-	// PostgreSQL instance is archiving to a backup object store) or
-	// restored in parallel (when a PostgreSQL standby is fetching WAL
-	// files from a recovery object store). If not specified, WAL files
-	// will be processed one at a time. It accepts a positive integer as a
-	// value - with 1 being the minimum accepted value.
-	// +kubebuilder:validation:Minimum=1
-	MaxParallel int `json:"maxParallel,omitempty"`
+// ZstdCompressionConfiguration is the tuning of the zstd compression
+type ZstdCompressionConfiguration struct {
+	// The compression level, from 1 (fastest) to 22 (smallest output).
+	// If not specified, the default level of Barman is used
+	// +optional
+	Level *int `json:"level,omitempty"`
 }
 
 // DataBackupConfiguration is the configuration of the backup of
@@ -1402,10 +3374,17 @@ type WalBackupConfiguration struct {
 type DataBackupConfiguration struct {
 	// Compress a backup file (a tar file per tablespace) while streaming it
 	// to the object store. Available options are empty string (no
-	// compression, default), `gzip`, `bzip2` or `snappy`.
-	// +kubebuilder:validation:Enum=gzip;bzip2;snappy
+	// compression, default), `gzip`, `bzip2`, `snappy` or `zstd`.
+	// The `zstd` compression requires Barman 3.12 or later.
+	// The base backup compression is independent of the WAL one.
+	// +kubebuilder:validation:Enum=gzip;bzip2;snappy;zstd
 	Compression CompressionType `json:"compression,omitempty"`
 
+	// The tuning of the `zstd` compression of the backup files, only
+	// allowed when the `compression` is `zstd`
+	// +optional
+	Zstd *ZstdCompressionConfiguration `json:"zstd,omitempty"`
+
 	// Whenever to force the encryption of files (if the bucket is
 	// not already configured for that).
 	// Allowed options are empty string (use the bucket policy, default),
@@ -1509,6 +3488,13 @@ type MonitoringConfiguration struct {
 	// Enable or disable the `PodMonitor`
 	// +kubebuilder:default:=false
 	EnablePodMonitor bool `json:"enablePodMonitor,omitempty"`
+
+	// If enabled, the operator creates the `monitoring` role, member of
+	// `pg_monitor`, with the password stored in the `<cluster>-monitoring`
+	// secret, and the metrics endpoint connects as this role instead of
+	// the superuser. The password is changed when the secret changes
+	// +optional
+	EnableMonitoringRole bool `json:"enableMonitoringRole,omitempty"`
 }
 
 // AreDefaultQueriesDisabled checks whether default monitoring queries should be disabled
@@ -1542,6 +3528,11 @@ type ExternalCluster struct {
 
 	// The configuration for the barman-cloud tool suite
 	BarmanObjectStore *BarmanObjectStoreConfiguration `json:"barmanObjectStore,omitempty"`
+
+	// The storage where wal-g archived the WAL files and the base
+	// backups of the external cluster
+	// +optional
+	WalG *WalGConfiguration `json:"walG,omitempty"`
 }
 
 // GetServerName returns the server name, defaulting to the name of the external cluster or using the one specified
@@ -1601,6 +3592,9 @@ type SecretsResourceVersion struct {
 	// The resource version of the "app" user secret
 	ApplicationSecretVersion string `json:"applicationSecretVersion,omitempty"`
 
+	// The resource version of the "monitoring" user secret
+	MonitoringSecretVersion string `json:"monitoringSecretVersion,omitempty"`
+
 	// Unused. Retained for compatibility with old versions.
 	CASecretVersion string `json:"caSecretVersion,omitempty"`
 
@@ -1636,7 +3630,7 @@ func (cluster *Cluster) GetImageName() string {
 		return cluster.Spec.ImageName
 	}
 
-	return configuration.Current.PostgresImageName
+	return configuration.Current.GetPostgresImageName()
 }
 
 // GetPostgresqlVersion gets the PostgreSQL image version detecting it from the
@@ -1652,12 +3646,30 @@ func (cluster *Cluster) GetPostgresqlVersion() (int, error) {
 	return postgres.GetPostgresVersionFromTag(tag)
 }
 
+// GetPostgresqlMajorVersion gets the PostgreSQL major version
+// of the image used by the cluster
+func (cluster *Cluster) GetPostgresqlMajorVersion() (int, error) {
+	tag := utils.GetImageTag(cluster.GetImageName())
+	return postgres.GetPostgresMajorVersionFromTag(tag)
+}
+
 // GetImagePullSecret get the name of the pull secret to use
 // to download the PostgreSQL image
 func (cluster *Cluster) GetImagePullSecret() string {
 	return cluster.Name + ClusterSecretSuffix
 }
 
+// GetServiceAccountName gets the name of the service account used by the
+// Pods of the cluster, which is generated by the operator unless an
+// existing one is specified
+func (cluster *Cluster) GetServiceAccountName() string {
+	if cluster.Spec.ServiceAccountName != "" {
+		return cluster.Spec.ServiceAccountName
+	}
+
+	return cluster.Name
+}
+
 // GetSuperuserSecretName get the secret name of the PostgreSQL superuser
 func (cluster *Cluster) GetSuperuserSecretName() string {
 	if cluster.Spec.SuperuserSecret != nil &&
@@ -1689,6 +3701,62 @@ func (cluster *Cluster) GetLDAPSecretName() string {
 	return ""
 }
 
+// GetConfigurationDriftPolicy gets the policy applied when a parameter
+// managed by the operator is overridden in `postgresql.auto.conf`
+func (cluster *Cluster) GetConfigurationDriftPolicy() ConfigurationDriftPolicy {
+	if cluster.Spec.PostgresConfiguration.ConfigurationDriftPolicy == "" {
+		return ConfigurationDriftPolicyReport
+	}
+
+	return cluster.Spec.PostgresConfiguration.ConfigurationDriftPolicy
+}
+
+// GetReplicasUnavailablePolicy gets the policy applied to the synchronous
+// replication when fewer standbys than `minSyncReplicas` are ready
+func (cluster *Cluster) GetReplicasUnavailablePolicy() ReplicasUnavailablePolicy {
+	if cluster.Spec.ReplicasUnavailablePolicy == "" {
+		return ReplicasUnavailablePolicySelfHeal
+	}
+
+	return cluster.Spec.ReplicasUnavailablePolicy
+}
+
+// IsReadOnly checks if the cluster has been set to read-only
+func (cluster *Cluster) IsReadOnly() bool {
+	return cluster.Spec.ReadOnly != nil && cluster.Spec.ReadOnly.Enabled
+}
+
+// GetWalKeepSize gets the value of `wal_keep_size`, using the
+// PostgreSQL units, or an empty string if the default is used
+func (cluster *Cluster) GetWalKeepSize() string {
+	if cluster.Spec.WalKeepSize == nil {
+		return ""
+	}
+
+	const megabyte = 1024 * 1024
+	return fmt.Sprintf("%dMB", (cluster.Spec.WalKeepSize.Value()+megabyte-1)/megabyte)
+}
+
+// GetWalVolumeSize gets the size of the volume storing the WAL files,
+// which is the dedicated WAL volume when configured
+func (cluster *Cluster) GetWalVolumeSize() *resource.Quantity {
+	if cluster.ShouldCreateWalArchiveVolume() {
+		return cluster.Spec.WalStorage.GetSizeOrNil()
+	}
+
+	return cluster.Spec.StorageConfiguration.GetSizeOrNil()
+}
+
+// GetReplicaJoinMethod gets the method used to create the data
+// directory of new replicas
+func (cluster *Cluster) GetReplicaJoinMethod() ReplicaJoinMethod {
+	if cluster.Spec.ReplicaJoinMethod == "" {
+		return ReplicaJoinMethodPgBaseBackup
+	}
+
+	return cluster.Spec.ReplicaJoinMethod
+}
+
 // GetEnableSuperuserAccess returns if the superuser access is enabled or not
 func (cluster *Cluster) GetEnableSuperuserAccess() bool {
 	if cluster.Spec.EnableSuperuserAccess != nil {
@@ -1698,6 +3766,17 @@ func (cluster *Cluster) GetEnableSuperuserAccess() bool {
 	return true
 }
 
+// IsMonitoringRoleEnabled checks whether the metrics endpoint should
+// connect as the monitoring role instead of the superuser
+func (cluster *Cluster) IsMonitoringRoleEnabled() bool {
+	return cluster.Spec.Monitoring != nil && cluster.Spec.Monitoring.EnableMonitoringRole
+}
+
+// GetMonitoringSecretName get the name of the secret of the monitoring role
+func (cluster *Cluster) GetMonitoringSecretName() string {
+	return fmt.Sprintf("%v%v", cluster.Name, MonitoringUserSecretSuffix)
+}
+
 // GetApplicationSecretName get the name of the application secret for any bootstrap type
 func (cluster *Cluster) GetApplicationSecretName() string {
 	bootstrap := cluster.Spec.Bootstrap
@@ -1772,6 +3851,10 @@ func (cluster *Cluster) GetServerCASecretName() string {
 	if cluster.Spec.Certificates != nil && cluster.Spec.Certificates.ServerCASecret != "" {
 		return cluster.Spec.Certificates.ServerCASecret
 	}
+	// cert-manager stores the issuer CA together with the certificate
+	if cluster.GetServerIssuerRef() != nil {
+		return cluster.GetServerTLSSecretName()
+	}
 	return fmt.Sprintf("%v%v", cluster.Name, DefaultServerCaSecretSuffix)
 }
 
@@ -1784,6 +3867,15 @@ func (cluster *Cluster) GetServerTLSSecretName() string {
 	return fmt.Sprintf("%v%v", cluster.Name, ServerSecretSuffix)
 }
 
+// GetServerIssuerRef get the cert-manager issuer signing the server
+// certificate, if any
+func (cluster *Cluster) GetServerIssuerRef() *CertificateIssuerReference {
+	if cluster.Spec.Certificates == nil {
+		return nil
+	}
+	return cluster.Spec.Certificates.ServerIssuerRef
+}
+
 // GetClientCASecretName get the name of the secret containing the CA
 // of the cluster
 func (cluster *Cluster) GetClientCASecretName() string {
@@ -1825,22 +3917,31 @@ func (cluster *Cluster) GetServiceAnyName() string {
 	return fmt.Sprintf("%v%v", cluster.Name, ServiceAnySuffix)
 }
 
+// getServiceBaseName gets the name of the services of the cluster, before
+// the suffix of their role, according to the configured naming scheme
+func (cluster *Cluster) getServiceBaseName() string {
+	if cluster.Spec.ServiceNaming == nil {
+		return cluster.Name
+	}
+	return cluster.Spec.ServiceNaming.Prefix + cluster.Name + cluster.Spec.ServiceNaming.Suffix
+}
+
 // GetServiceReadName return the name of the service that is used for
 // read transactions (including the primary)
 func (cluster *Cluster) GetServiceReadName() string {
-	return fmt.Sprintf("%v%v", cluster.Name, ServiceReadSuffix)
+	return fmt.Sprintf("%v%v", cluster.getServiceBaseName(), ServiceReadSuffix)
 }
 
 // GetServiceReadOnlyName return the name of the service that is used for
 // read-only transactions (excluding the primary)
 func (cluster *Cluster) GetServiceReadOnlyName() string {
-	return fmt.Sprintf("%v%v", cluster.Name, ServiceReadOnlySuffix)
+	return fmt.Sprintf("%v%v", cluster.getServiceBaseName(), ServiceReadOnlySuffix)
 }
 
 // GetServiceReadWriteName return the name of the service that is used for
 // read-write transactions
 func (cluster *Cluster) GetServiceReadWriteName() string {
-	return fmt.Sprintf("%v%v", cluster.Name, ServiceReadWriteSuffix)
+	return fmt.Sprintf("%v%v", cluster.getServiceBaseName(), ServiceReadWriteSuffix)
 }
 
 // GetMaxStartDelay get the amount of time of startDelay config option
@@ -1859,6 +3960,110 @@ func (cluster *Cluster) GetMaxStopDelay() int32 {
 	return 30
 }
 
+// IsQuorumInstancesCheckApplicable checks whether the number of instances
+// needs to be odd, as synchronous replication is enabled and the check
+// hasn't been skipped for the cluster
+func (cluster *Cluster) IsQuorumInstancesCheckApplicable() bool {
+	return cluster.Spec.MaxSyncReplicas > 0 && utils.IsQuorumInstancesCheckEnabled(&cluster.ObjectMeta)
+}
+
+// GetShutdownCheckpointTimeout get the amount of time the primary has to
+// complete the checkpoint requested when its Pod is deleted
+func (cluster *Cluster) GetShutdownCheckpointTimeout() int32 {
+	if cluster.Spec.ShutdownCheckpointTimeout > 0 {
+		return cluster.Spec.ShutdownCheckpointTimeout
+	}
+	return DefaultShutdownCheckpointTimeout
+}
+
+// IsFinalBackupEnabled checks if a final base backup should be taken
+// before deleting the cluster
+func (cluster *Cluster) IsFinalBackupEnabled() bool {
+	return cluster.Spec.Backup != nil &&
+		cluster.Spec.Backup.BarmanObjectStore != nil &&
+		cluster.Spec.Backup.FinalBackup != nil &&
+		cluster.Spec.Backup.FinalBackup.Enabled
+}
+
+// GetFinalBackupTimeout gets the maximum time the deletion of the cluster
+// is delayed waiting for the final base backup
+func (cluster *Cluster) GetFinalBackupTimeout() time.Duration {
+	if cluster.Spec.Backup != nil && cluster.Spec.Backup.FinalBackup != nil &&
+		cluster.Spec.Backup.FinalBackup.Timeout > 0 {
+		return time.Duration(cluster.Spec.Backup.FinalBackup.Timeout) * time.Second
+	}
+	return DefaultFinalBackupTimeout * time.Second
+}
+
+// IsOrderlyShutdownEnabled checks if the deletion of the cluster should
+// be delayed to shut the primary instance down after the replicas
+func (cluster *Cluster) IsOrderlyShutdownEnabled() bool {
+	return cluster.Spec.OrderlyShutdown != nil && cluster.Spec.OrderlyShutdown.Enabled
+}
+
+// GetPrimaryShutdownTimeout gets the time allowed for the shutdown of the
+// primary instance, after the replicas, when the cluster is hibernated
+// or deleted
+func (cluster *Cluster) GetPrimaryShutdownTimeout() time.Duration {
+	if cluster.Spec.OrderlyShutdown != nil && cluster.Spec.OrderlyShutdown.PrimaryTimeout > 0 {
+		return time.Duration(cluster.Spec.OrderlyShutdown.PrimaryTimeout) * time.Second
+	}
+	return DefaultPrimaryShutdownTimeout * time.Second
+}
+
+// GetReplicasShutdownTimeout gets the time allowed for the shutdown of
+// each replica when the cluster is deleted, before shutting the primary
+// instance down anyway
+func (cluster *Cluster) GetReplicasShutdownTimeout() time.Duration {
+	if cluster.Spec.OrderlyShutdown != nil && cluster.Spec.OrderlyShutdown.ReplicasTimeout > 0 {
+		return time.Duration(cluster.Spec.OrderlyShutdown.ReplicasTimeout) * time.Second
+	}
+	return DefaultReplicasShutdownTimeout * time.Second
+}
+
+// GetTerminationGracePeriod get the amount of time the kubelet waits for
+// an instance to terminate, including the checkpoint requested before
+// the shutdown
+func (cluster *Cluster) GetTerminationGracePeriod() int64 {
+	return int64(cluster.GetMaxStopDelay()) + int64(cluster.GetShutdownCheckpointTimeout())
+}
+
+// GetPrimaryTransitionStuckTimeout gets the time after which a switchover
+// or a failover which didn't complete is reported as stuck
+func (cluster *Cluster) GetPrimaryTransitionStuckTimeout() time.Duration {
+	if cluster.Spec.PrimaryTransition != nil && cluster.Spec.PrimaryTransition.StuckTimeout > 0 {
+		return time.Duration(cluster.Spec.PrimaryTransition.StuckTimeout) * time.Second
+	}
+	return DefaultPrimaryTransitionStuckTimeout * time.Second
+}
+
+// ShouldRebuildCorruptedReplicas checks if the replicas reporting
+// checksum failures should be rebuilt from the primary
+func (cluster *Cluster) ShouldRebuildCorruptedReplicas() bool {
+	return cluster.Spec.CorruptedReplicas != nil && cluster.Spec.CorruptedReplicas.Rebuild
+}
+
+// GetCorruptedReplicaRebuildGracePeriod gets the time a replica reporting
+// checksum failures is kept before being rebuilt
+func (cluster *Cluster) GetCorruptedReplicaRebuildGracePeriod() time.Duration {
+	if cluster.Spec.CorruptedReplicas != nil && cluster.Spec.CorruptedReplicas.RebuildGracePeriod > 0 {
+		return time.Duration(cluster.Spec.CorruptedReplicas.RebuildGracePeriod) * time.Second
+	}
+	return DefaultCorruptedReplicaRebuildGracePeriod * time.Second
+}
+
+// GetFailoverCooldown gets the time after the promotion of a new primary
+// during which no automatic failover is triggered
+func (cluster *Cluster) GetFailoverCooldown() time.Duration {
+	return time.Duration(cluster.Spec.FailoverCooldown) * time.Second
+}
+
+// ShouldRestartStuckTargetPrimary checks if the Pod of the target primary
+// should be restarted when a switchover or a failover is stuck
+func (cluster *Cluster) ShouldRestartStuckTargetPrimary() bool {
+	return cluster.Spec.PrimaryTransition != nil && cluster.Spec.PrimaryTransition.RestartTargetPrimary
+}
+
 // GetMaxSwitchoverDelay get the amount of time PostgreSQL has to stop before switchover
 func (cluster *Cluster) GetMaxSwitchoverDelay() int32 {
 	if cluster.Spec.MaxSwitchoverDelay > 0 {
@@ -1867,6 +4072,108 @@ func (cluster *Cluster) GetMaxSwitchoverDelay() int32 {
 	return DefaultMaxSwitchoverDelay
 }
 
+// IsSwitchoverCheckpointEnabled returns true if the former primary should
+// request a checkpoint before being demoted during a switchover
+func (cluster *Cluster) IsSwitchoverCheckpointEnabled() bool {
+	checkpoint := cluster.Spec.SwitchoverCheckpoint
+	if checkpoint == nil || checkpoint.Enabled == nil {
+		return true
+	}
+
+	return *checkpoint.Enabled
+}
+
+// IsDelayedReplica checks if the passed instance is designated to
+// apply the changes with a delay
+func (cluster *Cluster) IsDelayedReplica(instanceName string) bool {
+	if cluster.Spec.DelayedReplicas == nil {
+		return false
+	}
+
+	for _, name := range cluster.Spec.DelayedReplicas.Instances {
+		if name == instanceName {
+			return true
+		}
+	}
+
+	return false
+}
+
+// GetBackupServerName gets the name of the server which the backups and
+// the WAL files of the cluster are stored under in the object store
+func (cluster *Cluster) GetBackupServerName() string {
+	if cluster.Spec.Backup != nil && cluster.Spec.Backup.BarmanObjectStore != nil &&
+		cluster.Spec.Backup.BarmanObjectStore.ServerName != "" {
+		return cluster.Spec.Backup.BarmanObjectStore.ServerName
+	}
+
+	return cluster.Name
+}
+
+// GetReplicationApplicationName gets the `application_name` the passed
+// instance uses when streaming the changes from the primary
+func (cluster *Cluster) GetReplicationApplicationName(instanceName string) string {
+	if cluster.Spec.ReplicationConnection == nil {
+		return instanceName
+	}
+
+	return cluster.Spec.ReplicationConnection.ApplicationNamePrefix + instanceName
+}
+
+// GetReadReplicas gets the number of requested read replicas
+func (cluster *Cluster) GetReadReplicas() int {
+	if cluster.Spec.ReadReplicas == nil {
+		return 0
+	}
+
+	return cluster.Spec.ReadReplicas.Instances
+}
+
+// GetDesiredInstances gets the total number of instances of the cluster,
+// including the read replicas requested on top of `.spec.instances`
+func (cluster *Cluster) GetDesiredInstances() int {
+	return cluster.Spec.Instances + cluster.GetReadReplicas()
+}
+
+// GetMinApplyDelay gets the delay the passed instance should use when
+// applying the changes, which is zero when it isn't a delayed replica
+func (cluster *Cluster) GetMinApplyDelay(instanceName string) time.Duration {
+	if !cluster.IsDelayedReplica(instanceName) {
+		return 0
+	}
+
+	return time.Duration(cluster.Spec.DelayedReplicas.MinApplyDelay) * time.Second
+}
+
+// GetSwitchoverCheckpointTimeout get the amount of time the former primary
+// has to complete the checkpoint requested during a switchover
+func (cluster *Cluster) GetSwitchoverCheckpointTimeout() time.Duration {
+	checkpoint := cluster.Spec.SwitchoverCheckpoint
+	if checkpoint == nil || checkpoint.Timeout <= 0 {
+		return DefaultSwitchoverCheckpointTimeout * time.Second
+	}
+
+	return time.Duration(checkpoint.Timeout) * time.Second
+}
+
+// IsReadinessDipTolerated returns true if the transient readiness dips
+// of the instances should not block the scale up of the cluster
+func (cluster *Cluster) IsReadinessDipTolerated() bool {
+	tolerance := cluster.Spec.ReadinessTolerance
+	return tolerance != nil && tolerance.Policy == ReadinessTolerancePolicyTolerate
+}
+
+// GetReadinessTolerancePeriod get the amount of time an instance can be
+// not ready before being considered unavailable
+func (cluster *Cluster) GetReadinessTolerancePeriod() time.Duration {
+	tolerance := cluster.Spec.ReadinessTolerance
+	if tolerance == nil || tolerance.Period <= 0 {
+		return DefaultReadinessTolerancePeriod * time.Second
+	}
+
+	return time.Duration(tolerance.Period) * time.Second
+}
+
 // GetPrimaryUpdateStrategy get the cluster primary update strategy,
 // defaulting to unsupervised
 func (cluster *Cluster) GetPrimaryUpdateStrategy() PrimaryUpdateStrategy {
@@ -1894,6 +4201,111 @@ func (cluster *Cluster) IsNodeMaintenanceWindowInProgress() bool {
 	return cluster.Spec.NodeMaintenanceWindow != nil && cluster.Spec.NodeMaintenanceWindow.InProgress
 }
 
+// GetLocation gets the time zone of the maintenance window
+func (window *MaintenanceWindowConfiguration) GetLocation() (*time.Location, error) {
+	if window.TimeZone == "" {
+		return time.UTC, nil
+	}
+	return time.LoadLocation(window.TimeZone)
+}
+
+// IsOpen checks whether the given time is inside one of the time ranges
+// of the maintenance window
+func (window *MaintenanceWindowConfiguration) IsOpen(now time.Time) (bool, error) {
+	location, err := window.GetLocation()
+	if err != nil {
+		return false, err
+	}
+
+	now = now.In(location)
+	minute := now.Hour()*60 + now.Minute()
+	yesterday := now.AddDate(0, 0, -1).Weekday()
+	for _, timeRange := range window.Windows {
+		start, end, err := timeRange.parse()
+		if err != nil {
+			return false, err
+		}
+
+		if start < end {
+			if timeRange.includesDay(now.Weekday()) && minute >= start && minute < end {
+				return true, nil
+			}
+			continue
+		}
+
+		// The time range ends on the following day
+		if (timeRange.includesDay(now.Weekday()) && minute >= start) ||
+			(timeRange.includesDay(yesterday) && minute < end) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// GetNextOpening gets the first time, after the given one, when a time
+// range of the maintenance window starts
+func (window *MaintenanceWindowConfiguration) GetNextOpening(now time.Time) (time.Time, error) {
+	location, err := window.GetLocation()
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	now = now.In(location)
+	var next time.Time
+	// A week and a day are enough to find the next opening of a weekly schedule
+	for days := 0; days <= 7; days++ {
+		day := now.AddDate(0, 0, days)
+		for _, timeRange := range window.Windows {
+			if !timeRange.includesDay(day.Weekday()) {
+				continue
+			}
+
+			start, _, err := timeRange.parse()
+			if err != nil {
+				return time.Time{}, err
+			}
+
+			opening := time.Date(day.Year(), day.Month(), day.Day(), start/60, start%60, 0, 0, location)
+			if opening.After(now) && (next.IsZero() || opening.Before(next)) {
+				next = opening
+			}
+		}
+	}
+
+	return next, nil
+}
+
+// parse gets the start and the end of the time range, as minutes
+// since midnight
+func (timeRange MaintenanceTimeRange) parse() (start, end int, err error) {
+	startTime, err := time.Parse("15:04", timeRange.Start)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid start of maintenance window %q: %w", timeRange.Start, err)
+	}
+	endTime, err := time.Parse("15:04", timeRange.End)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid end of maintenance window %q: %w", timeRange.End, err)
+	}
+
+	return startTime.Hour()*60 + startTime.Minute(), endTime.Hour()*60 + endTime.Minute(), nil
+}
+
+// includesDay checks whether the time range starts in the given day
+func (timeRange MaintenanceTimeRange) includesDay(day time.Weekday) bool {
+	if len(timeRange.Days) == 0 {
+		return true
+	}
+
+	for _, item := range timeRange.Days {
+		if string(item) == day.String() {
+			return true
+		}
+	}
+
+	return false
+}
+
 // GetPgCtlTimeoutForPromotion returns the timeout that should be waited for an instance to be promoted
 // to primary. As default, DefaultPgCtlTimeoutForPromotion is big enough to simulate an infinite timeout
 func (cluster *Cluster) GetPgCtlTimeoutForPromotion() int32 {
@@ -1926,6 +4338,18 @@ func (cluster *Cluster) IsInstanceFenced(instance string) bool {
 	return fencedInstances.Has(instance)
 }
 
+// IsInstanceCordoned checks if a given instance has been found with
+// corrupted data and should be removed from the services until it is
+// rebuilt. The current primary is never cordoned, as it is switched
+// over to a healthy replica first
+func (cluster *Cluster) IsInstanceCordoned(instance string) bool {
+	if instance == cluster.Status.CurrentPrimary {
+		return false
+	}
+
+	return cluster.Status.InstancesReportedState[PodName(instance)].CorruptedSince != ""
+}
+
 // ShouldResizeInUseVolumes is true when we should resize PVC we already
 // created
 func (cluster *Cluster) ShouldResizeInUseVolumes() bool {
@@ -2055,6 +4479,32 @@ func (cluster *Cluster) ShouldCreateProjectedVolume() bool {
 	return cluster.Spec.ProjectedVolumeTemplate != nil
 }
 
+// GetObjectStoreCredentialsVolume gets the CSI volume containing the
+// credentials of the backup object store, if any
+func (cluster *Cluster) GetObjectStoreCredentialsVolume() *corev1.CSIVolumeSource {
+	if cluster.Spec.Backup == nil || cluster.Spec.Backup.BarmanObjectStore == nil {
+		return nil
+	}
+
+	return cluster.Spec.Backup.BarmanObjectStore.CredentialsVolume
+}
+
+// GetAdditionalSharedPreloadLibraries gets the libraries to be added to
+// `shared_preload_libraries`, requested either directly or by the
+// extensions managed by the operator
+func (cluster *Cluster) GetAdditionalSharedPreloadLibraries() []string {
+	libraries := append([]string(nil), cluster.Spec.PostgresConfiguration.AdditionalLibraries...)
+	for _, extension := range cluster.Spec.Extensions {
+		for _, library := range extension.GetSharedPreloadLibraries() {
+			if !slices.Contains(libraries, library) {
+				libraries = append(libraries, library)
+			}
+		}
+	}
+
+	return libraries
+}
+
 // ShouldCreateWalArchiveVolume returns whether we should create the wal archive volume
 func (cluster *Cluster) ShouldCreateWalArchiveVolume() bool {
 	return cluster.Spec.WalStorage != nil
@@ -2065,6 +4515,18 @@ func (cluster *Cluster) GetWalArchiveVolumeSuffix() string {
 	return WalArchiveVolumeSuffix
 }
 
+// GetTablespace gets the configuration of the tablespace with the passed
+// name, or nil if it is not defined
+func (cluster *Cluster) GetTablespace(name string) *TablespaceConfiguration {
+	for idx := range cluster.Spec.Tablespaces {
+		if cluster.Spec.Tablespaces[idx].Name == name {
+			return &cluster.Spec.Tablespaces[idx]
+		}
+	}
+
+	return nil
+}
+
 // GetPostgresUID returns the UID that is being used for the "postgres"
 // user
 func (cluster Cluster) GetPostgresUID() int64 {
@@ -2095,6 +4557,48 @@ func (cluster Cluster) ExternalCluster(name string) (ExternalCluster, bool) {
 	return ExternalCluster{}, false
 }
 
+// GetPgBaseBackupSource gets the server to be cloned by the pg_basebackup
+// bootstrap method. When a source cluster is specified, its connection
+// parameters are derived from the names of the service and of the
+// secrets generated by the operator
+func (cluster Cluster) GetPgBaseBackupSource() (ExternalCluster, bool) {
+	if cluster.Spec.Bootstrap == nil || cluster.Spec.Bootstrap.PgBaseBackup == nil {
+		return ExternalCluster{}, false
+	}
+
+	pgBaseBackup := cluster.Spec.Bootstrap.PgBaseBackup
+	if pgBaseBackup.SourceCluster == nil {
+		return cluster.ExternalCluster(pgBaseBackup.Source)
+	}
+
+	source := Cluster{ObjectMeta: metav1.ObjectMeta{
+		Name:      pgBaseBackup.SourceCluster.Name,
+		Namespace: cluster.Namespace,
+	}}
+	replicationSecret := corev1.LocalObjectReference{Name: source.GetReplicationSecretName()}
+	return ExternalCluster{
+		Name: source.Name,
+		ConnectionParameters: map[string]string{
+			"host":    source.GetServiceReadWriteName(),
+			"user":    StreamingReplicationUser,
+			"dbname":  "postgres",
+			"sslmode": "verify-full",
+		},
+		SSLCert: &corev1.SecretKeySelector{
+			LocalObjectReference: replicationSecret,
+			Key:                  corev1.TLSCertKey,
+		},
+		SSLKey: &corev1.SecretKeySelector{
+			LocalObjectReference: replicationSecret,
+			Key:                  corev1.TLSPrivateKeyKey,
+		},
+		SSLRootCert: &corev1.SecretKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: source.GetServerCASecretName()},
+			Key:                  "ca.crt",
+		},
+	}, true
+}
+
 // IsReplica checks if this is a replica cluster or not
 func (cluster Cluster) IsReplica() bool {
 	return cluster.Spec.ReplicaCluster != nil && cluster.Spec.ReplicaCluster.Enabled
@@ -2171,6 +4675,10 @@ func (cluster *Cluster) UsesSecret(secret string) bool {
 		return true
 	}
 
+	if cluster.IsMonitoringRoleEnabled() && cluster.GetMonitoringSecretName() == secret {
+		return true
+	}
+
 	if endpointCA := cluster.GetBarmanEndpointCAForReplicaCluster(); endpointCA != nil && endpointCA.Name == secret {
 		return true
 	}
@@ -2183,7 +4691,7 @@ func (cluster *Cluster) UsesSecret(secret string) bool {
 		}
 	}
 
-	return false
+	return cluster.isMountedObject("Secret", secret)
 }
 
 // UsesConfigMap checks whether a given secret is used by a Cluster
@@ -2191,6 +4699,17 @@ func (cluster *Cluster) UsesConfigMap(config string) (ok bool) {
 	if _, ok := cluster.Status.ConfigMapResourceVersion.Metrics[config]; ok {
 		return true
 	}
+	return cluster.isMountedObject("ConfigMap", config)
+}
+
+// isMountedObject checks whether an object is one of the mounted
+// objects whose changes are tracked by the operator
+func (cluster *Cluster) isMountedObject(kind string, name string) bool {
+	for _, reference := range cluster.Spec.MountedObjects {
+		if reference.Kind == kind && reference.Name == name {
+			return true
+		}
+	}
 	return false
 }
 
@@ -2203,6 +4722,38 @@ func (cluster *Cluster) IsPodMonitorEnabled() bool {
 	return false
 }
 
+// GetStatusHistoryLength gets the number of entries retained
+// in the history lists of the status
+func (cluster *Cluster) GetStatusHistoryLength() int {
+	if cluster.Spec.StatusHistoryLength <= 0 {
+		return DefaultStatusHistoryLength
+	}
+
+	return cluster.Spec.StatusHistoryLength
+}
+
+// AddPrimaryChange records a change of the primary instance in the status,
+// discarding the oldest ones beyond the retained history length
+func (cluster *Cluster) AddPrimaryChange(change PrimaryChange) {
+	cluster.Status.PrimaryHistory = append(cluster.Status.PrimaryHistory, change)
+	cluster.TruncateStatusHistory()
+}
+
+// TruncateStatusHistory discards the oldest entries of the history
+// lists of the status beyond the retained history length
+func (cluster *Cluster) TruncateStatusHistory() {
+	length := cluster.GetStatusHistoryLength()
+	if len(cluster.Status.PrimaryHistory) > length {
+		cluster.Status.PrimaryHistory = cluster.Status.PrimaryHistory[len(cluster.Status.PrimaryHistory)-length:]
+	}
+}
+
+// IsScheduledRestartEnabled checks whether the instances of the
+// cluster are restarted on a schedule
+func (cluster *Cluster) IsScheduledRestartEnabled() bool {
+	return cluster.Spec.ScheduledRestart != nil && !cluster.Spec.ScheduledRestart.Suspend
+}
+
 // LogTimestampsWithMessage prints useful information about timestamps in stdout
 func (cluster *Cluster) LogTimestampsWithMessage(ctx context.Context, logMessage string) {
 	contextLogger := log.FromContext(ctx)
@@ -2286,6 +4837,35 @@ func (backupConfiguration *BackupConfiguration) IsBarmanBackupConfigured() bool
 		backupConfiguration.BarmanObjectStore.BarmanCredentials.ArePopulated()
 }
 
+// IsWalMirrorEnabled returns true if the WAL archive is mirrored to
+// a secondary object store, false otherwise
+func (backupConfiguration *BackupConfiguration) IsWalMirrorEnabled() bool {
+	return backupConfiguration != nil && backupConfiguration.BarmanObjectStore != nil &&
+		backupConfiguration.WalMirror != nil
+}
+
+// GetWalArchiveBackend gets the tool archiving the WAL files
+func (backupConfiguration *BackupConfiguration) GetWalArchiveBackend() WalArchiveBackend {
+	if backupConfiguration == nil || backupConfiguration.WalArchiveBackend == "" {
+		return DefaultWalArchiveBackend
+	}
+	return backupConfiguration.WalArchiveBackend
+}
+
+// IsCustomWalArchiveEnabled returns true if the WAL files are archived
+// by a custom command, false otherwise
+func (backupConfiguration *BackupConfiguration) IsCustomWalArchiveEnabled() bool {
+	return backupConfiguration.GetWalArchiveBackend() == WalArchiveBackendCustom &&
+		backupConfiguration.CustomWalArchive != nil
+}
+
+// IsWalGEnabled returns true if the WAL files are archived by wal-g,
+// false otherwise
+func (backupConfiguration *BackupConfiguration) IsWalGEnabled() bool {
+	return backupConfiguration.GetWalArchiveBackend() == WalArchiveBackendWalG &&
+		backupConfiguration.WalG != nil
+}
+
 // IsBarmanEndpointCASet returns true if we have a CA bundle for the endpoint
 // false otherwise
 func (backupConfiguration *BackupConfiguration) IsBarmanEndpointCASet() bool {
@@ -2296,6 +4876,29 @@ func (backupConfiguration *BackupConfiguration) IsBarmanEndpointCASet() bool {
 		backupConfiguration.BarmanObjectStore.EndpointCA.Key != ""
 }
 
+// Describe returns a human-readable description of the point
+// where the recovery given a certain target will stop
+func (target *RecoveryTarget) Describe() string {
+	if target == nil {
+		return "end of the WAL archive"
+	}
+
+	switch {
+	case target.TargetImmediate != nil && *target.TargetImmediate:
+		return "end of the base backup"
+	case target.TargetTime != "":
+		return fmt.Sprintf("time %v", target.TargetTime)
+	case target.TargetLSN != "":
+		return fmt.Sprintf("LSN %v", target.TargetLSN)
+	case target.TargetXID != "":
+		return fmt.Sprintf("transaction %v", target.TargetXID)
+	case target.TargetName != "":
+		return fmt.Sprintf("restore point %v", target.TargetName)
+	default:
+		return "end of the WAL archive"
+	}
+}
+
 // BuildPostgresOptions create the list of options that
 // should be added to the PostgreSQL configuration to
 // recover given a certain target
@@ -17,6 +17,9 @@ limitations under the License.
 package v1
 
 import (
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/resource"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
@@ -99,6 +102,89 @@ var _ = Describe("Primary update strategy", func() {
 	})
 })
 
+var _ = Describe("Switchover checkpoint", func() {
+	It("is enabled by default with the default timeout", func() {
+		cluster := Cluster{}
+		Expect(cluster.IsSwitchoverCheckpointEnabled()).To(BeTrue())
+		Expect(cluster.GetSwitchoverCheckpointTimeout()).To(Equal(DefaultSwitchoverCheckpointTimeout * time.Second))
+	})
+
+	It("respects the preference of the user", func() {
+		falseVal := false
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				SwitchoverCheckpoint: &SwitchoverCheckpointConfiguration{
+					Enabled: &falseVal,
+					Timeout: 10,
+				},
+			},
+		}
+		Expect(cluster.IsSwitchoverCheckpointEnabled()).To(BeFalse())
+		Expect(cluster.GetSwitchoverCheckpointTimeout()).To(Equal(10 * time.Second))
+	})
+})
+
+var _ = Describe("Primary transition", func() {
+	It("is reported as stuck after the default timeout, without restarting the target primary", func() {
+		cluster := Cluster{}
+		Expect(cluster.GetPrimaryTransitionStuckTimeout()).
+			To(Equal(DefaultPrimaryTransitionStuckTimeout * time.Second))
+		Expect(cluster.ShouldRestartStuckTargetPrimary()).To(BeFalse())
+	})
+
+	It("respects the preference of the user", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				PrimaryTransition: &PrimaryTransitionConfiguration{
+					StuckTimeout:         120,
+					RestartTargetPrimary: true,
+				},
+			},
+		}
+		Expect(cluster.GetPrimaryTransitionStuckTimeout()).To(Equal(2 * time.Minute))
+		Expect(cluster.ShouldRestartStuckTargetPrimary()).To(BeTrue())
+	})
+})
+
+var _ = Describe("Termination grace period", func() {
+	It("includes the default stop delay and shutdown checkpoint timeout", func() {
+		cluster := Cluster{}
+		Expect(cluster.GetShutdownCheckpointTimeout()).To(BeEquivalentTo(DefaultShutdownCheckpointTimeout))
+		Expect(cluster.GetTerminationGracePeriod()).To(BeEquivalentTo(30 + DefaultShutdownCheckpointTimeout))
+	})
+
+	It("respects the preference of the user", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				MaxStopDelay:              120,
+				ShutdownCheckpointTimeout: 60,
+			},
+		}
+		Expect(cluster.GetTerminationGracePeriod()).To(BeEquivalentTo(180))
+	})
+})
+
+var _ = Describe("Readiness tolerance", func() {
+	It("waits for every instance by default", func() {
+		cluster := Cluster{}
+		Expect(cluster.IsReadinessDipTolerated()).To(BeFalse())
+		Expect(cluster.GetReadinessTolerancePeriod()).To(Equal(DefaultReadinessTolerancePeriod * time.Second))
+	})
+
+	It("respects the preference of the user", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				ReadinessTolerance: &ReadinessToleranceConfiguration{
+					Policy: ReadinessTolerancePolicyTolerate,
+					Period: 60,
+				},
+			},
+		}
+		Expect(cluster.IsReadinessDipTolerated()).To(BeTrue())
+		Expect(cluster.GetReadinessTolerancePeriod()).To(Equal(time.Minute))
+	})
+})
+
 var _ = Describe("Node maintenance window", func() {
 	It("default maintenance not in progress", func() {
 		cluster := Cluster{}
@@ -461,6 +547,19 @@ var _ = Describe("A secret resource version", func() {
 		Expect(found).To(BeTrue())
 	})
 
+	It("contains the monitoring secret only when the monitoring role is enabled", func() {
+		cluster := Cluster{
+			ObjectMeta: v1.ObjectMeta{
+				Name: "clustername",
+			},
+		}
+		Expect(cluster.UsesSecret("clustername-monitoring")).To(BeFalse())
+
+		cluster.Spec.Monitoring = &MonitoringConfiguration{EnableMonitoringRole: true}
+		Expect(cluster.GetMonitoringSecretName()).To(Equal("clustername-monitoring"))
+		Expect(cluster.UsesSecret("clustername-monitoring")).To(BeTrue())
+	})
+
 	It("contains the client ca secret", func() {
 		cluster := Cluster{
 			ObjectMeta: v1.ObjectMeta{
@@ -568,6 +667,23 @@ var _ = Describe("A secret resource version", func() {
 		Expect(found).To(BeTrue())
 	})
 
+	It("contains the mounted objects whose changes are tracked", func() {
+		cluster := Cluster{
+			ObjectMeta: v1.ObjectMeta{
+				Name: "clustername",
+			},
+			Spec: ClusterSpec{
+				MountedObjects: []MountedObjectReference{
+					{Kind: "Secret", Name: "ldap-ca"},
+					{Kind: "ConfigMap", Name: "agent-config"},
+				},
+			},
+		}
+		Expect(cluster.UsesSecret("ldap-ca")).To(BeTrue())
+		Expect(cluster.UsesConfigMap("agent-config")).To(BeTrue())
+		Expect(cluster.UsesSecret("agent-config")).To(BeFalse())
+	})
+
 	It("contains the secret generated by the PgBouncer integration", func() {
 		cluster := Cluster{
 			ObjectMeta: v1.ObjectMeta{
@@ -843,3 +959,359 @@ var _ = Describe("Replication slots names for instances", func() {
 			"_232_test_cluster_example_1"))
 	})
 })
+
+var _ = Describe("final backup", func() {
+	It("is disabled without an object store", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Backup: &BackupConfiguration{
+					FinalBackup: &FinalBackupConfiguration{Enabled: true},
+				},
+			},
+		}
+		Expect(cluster.IsFinalBackupEnabled()).To(BeFalse())
+	})
+
+	It("is enabled with an object store", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Backup: &BackupConfiguration{
+					BarmanObjectStore: &BarmanObjectStoreConfiguration{},
+					FinalBackup:       &FinalBackupConfiguration{Enabled: true},
+				},
+			},
+		}
+		Expect(cluster.IsFinalBackupEnabled()).To(BeTrue())
+	})
+
+	It("has a default timeout", func() {
+		cluster := Cluster{}
+		Expect(cluster.GetFinalBackupTimeout()).To(Equal(DefaultFinalBackupTimeout * time.Second))
+
+		cluster.Spec.Backup = &BackupConfiguration{
+			FinalBackup: &FinalBackupConfiguration{Enabled: true, Timeout: 600},
+		}
+		Expect(cluster.GetFinalBackupTimeout()).To(Equal(600 * time.Second))
+	})
+})
+
+var _ = Describe("orderly shutdown", func() {
+	It("is disabled by default", func() {
+		cluster := Cluster{}
+		Expect(cluster.IsOrderlyShutdownEnabled()).To(BeFalse())
+
+		cluster.Spec.OrderlyShutdown = &OrderlyShutdownConfiguration{Enabled: true}
+		Expect(cluster.IsOrderlyShutdownEnabled()).To(BeTrue())
+	})
+
+	It("has a default timeout for the primary instance", func() {
+		cluster := Cluster{}
+		Expect(cluster.GetPrimaryShutdownTimeout()).To(Equal(DefaultPrimaryShutdownTimeout * time.Second))
+
+		cluster.Spec.OrderlyShutdown = &OrderlyShutdownConfiguration{PrimaryTimeout: 60}
+		Expect(cluster.GetPrimaryShutdownTimeout()).To(Equal(60 * time.Second))
+	})
+
+	It("has a default timeout for the replicas", func() {
+		cluster := Cluster{}
+		Expect(cluster.GetReplicasShutdownTimeout()).To(Equal(DefaultReplicasShutdownTimeout * time.Second))
+
+		cluster.Spec.OrderlyShutdown = &OrderlyShutdownConfiguration{ReplicasTimeout: 120}
+		Expect(cluster.GetReplicasShutdownTimeout()).To(Equal(120 * time.Second))
+	})
+})
+
+var _ = Describe("delayed replicas", func() {
+	cluster := Cluster{
+		Spec: ClusterSpec{
+			DelayedReplicas: &DelayedReplicasConfiguration{
+				Instances:     []string{"cluster-example-3"},
+				MinApplyDelay: 3600,
+			},
+		},
+	}
+
+	It("uses the delay only for the designated instances", func() {
+		Expect(cluster.IsDelayedReplica("cluster-example-3")).To(BeTrue())
+		Expect(cluster.GetMinApplyDelay("cluster-example-3")).To(Equal(time.Hour))
+		Expect(cluster.IsDelayedReplica("cluster-example-2")).To(BeFalse())
+		Expect(cluster.GetMinApplyDelay("cluster-example-2")).To(BeZero())
+	})
+
+	It("doesn't delay any instance when not configured", func() {
+		emptyCluster := Cluster{}
+		Expect(emptyCluster.IsDelayedReplica("cluster-example-3")).To(BeFalse())
+	})
+})
+
+var _ = Describe("replication connection", func() {
+	It("prefixes the application_name of the standbys", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				ReplicationConnection: &ReplicationConnectionConfiguration{ApplicationNamePrefix: "reporting-"},
+			},
+		}
+		Expect(cluster.GetReplicationApplicationName("cluster-example-2")).To(Equal("reporting-cluster-example-2"))
+
+		cluster.Spec.ReplicationConnection = nil
+		Expect(cluster.GetReplicationApplicationName("cluster-example-2")).To(Equal("cluster-example-2"))
+	})
+
+	It("builds the keepalive options which are set", func() {
+		configuration := &ReplicationConnectionConfiguration{
+			KeepalivesIdle:     60,
+			KeepalivesInterval: 10,
+		}
+		Expect(configuration.GetConnectionOptions()).To(Equal([]string{
+			"keepalives_idle=60",
+			"keepalives_interval=10",
+		}))
+
+		var emptyConfiguration *ReplicationConnectionConfiguration
+		Expect(emptyConfiguration.GetConnectionOptions()).To(BeEmpty())
+	})
+})
+
+var _ = Describe("read replicas", func() {
+	It("adds the read replicas to the desired instances", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Instances:    3,
+				ReadReplicas: &ReadReplicasConfiguration{Instances: 2},
+			},
+		}
+		Expect(cluster.GetReadReplicas()).To(Equal(2))
+		Expect(cluster.GetDesiredInstances()).To(Equal(5))
+	})
+
+	It("has no read replicas when not configured", func() {
+		cluster := Cluster{Spec: ClusterSpec{Instances: 3}}
+		Expect(cluster.GetReadReplicas()).To(BeZero())
+		Expect(cluster.GetDesiredInstances()).To(Equal(3))
+	})
+})
+
+var _ = Describe("maintenance window", func() {
+	// 2023-03-06 is a Monday
+	monday := func(hour, minute int) time.Time {
+		return time.Date(2023, time.March, 6, hour, minute, 0, 0, time.UTC)
+	}
+
+	It("checks daily time ranges", func() {
+		window := MaintenanceWindowConfiguration{
+			Windows: []MaintenanceTimeRange{{Start: "02:00", End: "04:00"}},
+		}
+		Expect(window.IsOpen(monday(3, 0))).To(BeTrue())
+		Expect(window.IsOpen(monday(4, 0))).To(BeFalse())
+		Expect(window.GetNextOpening(monday(1, 0))).To(Equal(monday(2, 0)))
+		Expect(window.GetNextOpening(monday(5, 0))).To(Equal(monday(2, 0).AddDate(0, 0, 1)))
+	})
+
+	It("checks time ranges ending on the following day", func() {
+		window := MaintenanceWindowConfiguration{
+			Windows: []MaintenanceTimeRange{{Days: []MaintenanceWeekday{"Sunday"}, Start: "22:00", End: "02:00"}},
+		}
+		Expect(window.IsOpen(monday(1, 0))).To(BeTrue())
+		Expect(window.IsOpen(monday(23, 0))).To(BeFalse())
+		Expect(window.GetNextOpening(monday(1, 0))).To(Equal(monday(22, 0).AddDate(0, 0, 6)))
+	})
+
+	It("uses the time zone of the window", func() {
+		window := MaintenanceWindowConfiguration{
+			TimeZone: "Europe/Rome",
+			Windows:  []MaintenanceTimeRange{{Days: []MaintenanceWeekday{"Monday"}, Start: "02:00", End: "04:00"}},
+		}
+		// Rome is one hour ahead of UTC in March
+		Expect(window.IsOpen(monday(1, 30))).To(BeTrue())
+		Expect(window.IsOpen(monday(3, 30))).To(BeFalse())
+	})
+})
+
+var _ = Describe("pg_basebackup source", func() {
+	It("uses the external cluster specified as source", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					PgBaseBackup: &BootstrapPgBaseBackup{Source: "origin"},
+				},
+				ExternalClusters: []ExternalCluster{
+					{Name: "origin", ConnectionParameters: map[string]string{"host": "origin.example.com"}},
+				},
+			},
+		}
+		server, ok := cluster.GetPgBaseBackupSource()
+		Expect(ok).To(BeTrue())
+		Expect(server.ConnectionParameters).To(HaveKeyWithValue("host", "origin.example.com"))
+	})
+
+	It("derives the connection to the source cluster", func() {
+		cluster := Cluster{
+			ObjectMeta: v1.ObjectMeta{Name: "clone", Namespace: "default"},
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					PgBaseBackup: &BootstrapPgBaseBackup{
+						SourceCluster: &LocalObjectReference{Name: "origin"},
+					},
+				},
+			},
+		}
+		server, ok := cluster.GetPgBaseBackupSource()
+		Expect(ok).To(BeTrue())
+		Expect(server.ConnectionParameters).To(HaveKeyWithValue("host", "origin-rw"))
+		Expect(server.ConnectionParameters).To(HaveKeyWithValue("user", StreamingReplicationUser))
+		Expect(server.SSLCert.Name).To(Equal("origin-replication"))
+		Expect(server.SSLKey.Name).To(Equal("origin-replication"))
+		Expect(server.SSLRootCert.Name).To(Equal("origin-ca"))
+	})
+
+	It("has no source without the pg_basebackup bootstrap", func() {
+		_, ok := Cluster{}.GetPgBaseBackupSource()
+		Expect(ok).To(BeFalse())
+	})
+})
+
+var _ = Describe("autovacuum configuration", func() {
+	It("has no parameters when not configured", func() {
+		var configuration *AutovacuumConfiguration
+		Expect(configuration.GetParameters()).To(BeNil())
+	})
+
+	It("maps the settings to the PostgreSQL parameters", func() {
+		enabled := true
+		naptime := int32(30)
+		costDelay := int32(-1)
+		configuration := &AutovacuumConfiguration{
+			Enabled:            &enabled,
+			Naptime:            &naptime,
+			VacuumCostDelay:    &costDelay,
+			AnalyzeScaleFactor: "0.02",
+		}
+		Expect(configuration.GetParameters()).To(Equal(map[string]string{
+			"autovacuum":                      "true",
+			"autovacuum_naptime":              "30",
+			"autovacuum_vacuum_cost_delay":    "-1",
+			"autovacuum_analyze_scale_factor": "0.02",
+		}))
+	})
+})
+
+var _ = Describe("checkpoint configuration", func() {
+	It("has no parameters when not configured", func() {
+		var configuration *CheckpointConfiguration
+		Expect(configuration.GetParameters()).To(BeNil())
+	})
+
+	It("maps the settings to the PostgreSQL parameters", func() {
+		maxWalSize := resource.MustParse("4Gi")
+		minWalSize := resource.MustParse("1500Mi")
+		timeout := int32(900)
+		configuration := &CheckpointConfiguration{
+			MaxWalSize:       &maxWalSize,
+			MinWalSize:       &minWalSize,
+			Timeout:          &timeout,
+			CompletionTarget: "0.9",
+		}
+		Expect(configuration.GetParameters()).To(Equal(map[string]string{
+			"max_wal_size":                 "4096MB",
+			"min_wal_size":                 "1500MB",
+			"checkpoint_timeout":           "900",
+			"checkpoint_completion_target": "0.9",
+		}))
+	})
+})
+
+var _ = Describe("recovery target description", func() {
+	It("replays the whole WAL archive without a target", func() {
+		var target *RecoveryTarget
+		Expect(target.Describe()).To(Equal("end of the WAL archive"))
+		Expect((&RecoveryTarget{TargetTLI: "latest"}).Describe()).To(Equal("end of the WAL archive"))
+	})
+
+	It("describes the point where the recovery stops", func() {
+		immediate := true
+		Expect((&RecoveryTarget{TargetImmediate: &immediate}).Describe()).To(Equal("end of the base backup"))
+		Expect((&RecoveryTarget{TargetTime: "2023-01-02 15:04:05"}).Describe()).
+			To(Equal("time 2023-01-02 15:04:05"))
+		Expect((&RecoveryTarget{TargetLSN: "0/3000000"}).Describe()).To(Equal("LSN 0/3000000"))
+		Expect((&RecoveryTarget{TargetXID: "1234"}).Describe()).To(Equal("transaction 1234"))
+		Expect((&RecoveryTarget{TargetName: "before_upgrade"}).Describe()).To(Equal("restore point before_upgrade"))
+	})
+})
+
+var _ = Describe("primary history", func() {
+	It("records the primary changes, oldest first", func() {
+		cluster := &Cluster{}
+		cluster.AddPrimaryChange(PrimaryChange{OldPrimary: "cluster-1", NewPrimary: "cluster-2"})
+		cluster.AddPrimaryChange(PrimaryChange{OldPrimary: "cluster-2", NewPrimary: "cluster-3"})
+		Expect(cluster.Status.PrimaryHistory).To(HaveLen(2))
+		Expect(cluster.Status.PrimaryHistory[0].NewPrimary).To(Equal("cluster-2"))
+		Expect(cluster.Status.PrimaryHistory[1].NewPrimary).To(Equal("cluster-3"))
+	})
+
+	It("discards the oldest changes beyond the default length", func() {
+		cluster := &Cluster{}
+		for idx := 0; idx < DefaultStatusHistoryLength+3; idx++ {
+			cluster.AddPrimaryChange(PrimaryChange{TimelineID: idx + 2})
+		}
+		Expect(cluster.Status.PrimaryHistory).To(HaveLen(DefaultStatusHistoryLength))
+		Expect(cluster.Status.PrimaryHistory[0].TimelineID).To(Equal(5))
+		Expect(cluster.Status.PrimaryHistory[DefaultStatusHistoryLength-1].TimelineID).
+			To(Equal(DefaultStatusHistoryLength + 4))
+	})
+
+	It("retains the configured number of changes", func() {
+		cluster := &Cluster{Spec: ClusterSpec{StatusHistoryLength: 2}}
+		for idx := 0; idx < 4; idx++ {
+			cluster.AddPrimaryChange(PrimaryChange{TimelineID: idx + 2})
+		}
+		Expect(cluster.Status.PrimaryHistory).To(HaveLen(2))
+		Expect(cluster.Status.PrimaryHistory[0].TimelineID).To(Equal(4))
+	})
+
+	It("truncates the history when the retained length is reduced", func() {
+		cluster := &Cluster{}
+		for idx := 0; idx < 5; idx++ {
+			cluster.AddPrimaryChange(PrimaryChange{TimelineID: idx + 2})
+		}
+		Expect(cluster.Status.PrimaryHistory).To(HaveLen(5))
+
+		cluster.Spec.StatusHistoryLength = 3
+		cluster.TruncateStatusHistory()
+		Expect(cluster.Status.PrimaryHistory).To(HaveLen(3))
+		Expect(cluster.Status.PrimaryHistory[0].TimelineID).To(Equal(4))
+	})
+})
+
+var _ = Describe("shared preload libraries of the extensions", func() {
+	It("includes the libraries of the extensions known by the operator", func() {
+		Expect(ExtensionConfiguration{Name: "pg_stat_statements"}.GetSharedPreloadLibraries()).
+			To(Equal([]string{"pg_stat_statements"}))
+		Expect(ExtensionConfiguration{
+			Name:                   "pgaudit",
+			SharedPreloadLibraries: []string{"pgaudit"},
+		}.GetSharedPreloadLibraries()).To(Equal([]string{"pgaudit"}))
+		Expect(ExtensionConfiguration{Name: "hstore"}.GetSharedPreloadLibraries()).To(BeEmpty())
+	})
+
+	It("adds the libraries of the extensions to the requested ones", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					AdditionalLibraries: []string{"pg_cron", "auto_explain"},
+				},
+				Extensions: []ExtensionConfiguration{
+					{Name: "auto_explain"},
+					{Name: "pg_stat_statements"},
+					{Name: "timescaledb", SharedPreloadLibraries: []string{"timescaledb"}},
+				},
+			},
+		}
+		Expect(cluster.GetAdditionalSharedPreloadLibraries()).To(Equal(
+			[]string{"pg_cron", "auto_explain", "pg_stat_statements", "timescaledb"}))
+		Expect(cluster.Spec.PostgresConfiguration.AdditionalLibraries).To(HaveLen(2))
+	})
+
+	It("is empty when no library is requested", func() {
+		Expect((&Cluster{}).GetAdditionalSharedPreloadLibraries()).To(BeNil())
+	})
+})
@@ -18,10 +18,16 @@ package v1
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"path"
+	"reflect"
+	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 
+	"github.com/robfig/cron"
 	v1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -59,6 +65,22 @@ const (
 // clusterLog is for logging in this package.
 var clusterLog = log.WithName("cluster-resource").WithValues("version", "v1")
 
+const (
+	// maxBarmanTags is the maximum number of tags which can be
+	// attached to an object, as allowed by every object store
+	maxBarmanTags = 10
+
+	// maxBarmanTagKeyLength is the maximum length of the key of a tag
+	maxBarmanTagKeyLength = 128
+
+	// maxBarmanTagValueLength is the maximum length of the value of a tag
+	maxBarmanTagValueLength = 256
+)
+
+// archiveLibraryRegex matches the names accepted for the archive_library
+// GUC, which PostgreSQL passes to the dynamic library loader
+var archiveLibraryRegex = regexp.MustCompile(`^[\w$./-]+$`)
+
 // SetupWebhookWithManager setup the webhook inside the controller manager
 func (r *Cluster) SetupWebhookWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewWebhookManagedBy(mgr).
@@ -74,7 +96,9 @@ var _ webhook.Defaulter = &Cluster{}
 func (r *Cluster) Default() {
 	clusterLog.Info("default", "name", r.Name, "namespace", r.Namespace)
 
+	original := r.DeepCopy()
 	r.setDefaults(true)
+	r.recordDefaultedFields(original)
 }
 
 // SetDefaults apply the defaults to undefined values in a Cluster
@@ -85,7 +109,22 @@ func (r *Cluster) SetDefaults() {
 func (r *Cluster) setDefaults(preserveUserSettings bool) {
 	// Defaulting the image name if not specified
 	if r.Spec.ImageName == "" {
-		r.Spec.ImageName = configuration.Current.PostgresImageName
+		r.Spec.ImageName = configuration.Current.GetPostgresImageName()
+	}
+
+	// Defaulting the pull policy if not specified
+	if r.Spec.ImagePullPolicy == "" {
+		r.Spec.ImagePullPolicy = v1.PullPolicy(configuration.Current.ImagePullPolicy)
+	}
+
+	r.defaultStorage()
+
+	// Defaulting the delays used by the probes if not specified
+	if r.Spec.MaxStartDelay == 0 {
+		r.Spec.MaxStartDelay = r.GetMaxStartDelay()
+	}
+	if r.Spec.MaxStopDelay == 0 {
+		r.Spec.MaxStopDelay = r.GetMaxStopDelay()
 	}
 
 	// Defaulting the bootstrap method if not specified
@@ -113,6 +152,19 @@ func (r *Cluster) setDefaults(preserveUserSettings bool) {
 		r.Spec.Backup.Target = DefaultBackupTarget
 	}
 
+	if r.Spec.Backup != nil && r.Spec.Backup.ObjectStoreName != "" {
+		var objectStore BarmanObjectStoreConfiguration
+		err := configuration.Current.GetObjectStore(r.Spec.Backup.ObjectStoreName, &objectStore)
+		if err == nil {
+			r.defaultObjectStore(&objectStore)
+		} else {
+			// The validation error will be raised by the
+			// validateObjectStoreName function
+			clusterLog.Info("Cannot use the referenced object store",
+				"name", r.Name, "namespace", r.Namespace, "error", err.Error())
+		}
+	}
+
 	psqlVersion, err := r.GetPostgresqlVersion()
 	if err == nil {
 		// The validation error will be already raised by the
@@ -139,6 +191,90 @@ func (r *Cluster) setDefaults(preserveUserSettings bool) {
 	}
 }
 
+// defaultStorage sets the size of the PGDATA volume, when it is specified
+// neither directly nor in the PVC template
+func (r *Cluster) defaultStorage() {
+	storage := &r.Spec.StorageConfiguration
+	if storage.Size != "" {
+		return
+	}
+
+	if storage.PersistentVolumeClaimTemplate != nil &&
+		!storage.PersistentVolumeClaimTemplate.Resources.Requests.Storage().IsZero() {
+		return
+	}
+
+	storage.Size = configuration.Current.DefaultStorageSize
+}
+
+// defaultedField is a field of the cluster specification that the
+// defaulting webhook reports when filling it
+type defaultedField struct {
+	path  string
+	isSet func(cluster *Cluster) bool
+}
+
+// defaultedFields is the list of the fields the defaulting webhook
+// reports in the DefaultedFieldsAnnotationName annotation
+var defaultedFields = []defaultedField{
+	{
+		path:  "spec.imageName",
+		isSet: func(cluster *Cluster) bool { return cluster.Spec.ImageName != "" },
+	},
+	{
+		path:  "spec.imagePullPolicy",
+		isSet: func(cluster *Cluster) bool { return cluster.Spec.ImagePullPolicy != "" },
+	},
+	{
+		path:  "spec.storage.size",
+		isSet: func(cluster *Cluster) bool { return cluster.Spec.StorageConfiguration.Size != "" },
+	},
+	{
+		path:  "spec.startDelay",
+		isSet: func(cluster *Cluster) bool { return cluster.Spec.MaxStartDelay != 0 },
+	},
+	{
+		path:  "spec.stopDelay",
+		isSet: func(cluster *Cluster) bool { return cluster.Spec.MaxStopDelay != 0 },
+	},
+	{
+		path:  "spec.bootstrap",
+		isSet: func(cluster *Cluster) bool { return cluster.Spec.Bootstrap != nil },
+	},
+	{
+		path:  "spec.logLevel",
+		isSet: func(cluster *Cluster) bool { return cluster.Spec.LogLevel != "" },
+	},
+}
+
+// recordDefaultedFields adds the fields filled by the defaulting webhook,
+// compared to the passed original cluster, to the ones already listed
+// in the DefaultedFieldsAnnotationName annotation
+func (r *Cluster) recordDefaultedFields(original *Cluster) {
+	fields := stringset.New()
+	if value := r.Annotations[utils.DefaultedFieldsAnnotationName]; value != "" {
+		fields = stringset.From(strings.Split(value, ","))
+	}
+
+	previousLen := fields.Len()
+	for _, defaulted := range defaultedFields {
+		if !defaulted.isSet(original) && defaulted.isSet(r) {
+			fields.Put(defaulted.path)
+		}
+	}
+
+	if fields.Len() == previousLen {
+		return
+	}
+
+	fieldList := fields.ToList()
+	sort.Strings(fieldList)
+	if r.Annotations == nil {
+		r.Annotations = make(map[string]string)
+	}
+	r.Annotations[utils.DefaultedFieldsAnnotationName] = strings.Join(fieldList, ",")
+}
+
 // defaultMonitoringQueries adds the default monitoring queries configMap
 // if not already present in CustomQueriesConfigMap
 func (r *Cluster) defaultMonitoringQueries(config *configuration.Data) {
@@ -248,6 +384,12 @@ func (r *Cluster) defaultPgBaseBackup() {
 	}
 }
 
+// defaultObjectStore merges the passed object store, defined in the
+// operator configuration, with the one of the cluster
+func (r *Cluster) defaultObjectStore(objectStore *BarmanObjectStoreConfiguration) {
+	r.Spec.Backup.BarmanObjectStore = objectStore.MergeWith(r.Spec.Backup.BarmanObjectStore)
+}
+
 // TODO(user): change verbs to "verbs=create;update;delete" if you want to enable deletion validation.
 // +kubebuilder:webhook:webhookVersions={v1},admissionReviewVersions={v1},verbs=create;update,path=/validate-postgresql-cnpg-io-v1-cluster,mutating=false,failurePolicy=fail,groups=postgresql.cnpg.io,resources=clusters,versions=v1,name=vcluster.kb.io,sideEffects=None
 
@@ -283,7 +425,12 @@ func (r *Cluster) Validate() (allErrs field.ErrorList) {
 		r.validatePrimaryUpdateStrategy,
 		r.validateMinSyncReplicas,
 		r.validateMaxSyncReplicas,
+		r.validateSyncReplicasInstances,
+		r.validateQuorumInstances,
 		r.validateStorageSize,
+		r.validateStorageClassesAndModes,
+		r.validateStorageSubPaths,
+		r.validateTablespaces,
 		r.validateWalStorageSize,
 		r.validateName,
 		r.validateBootstrapPgBaseBackupSource,
@@ -292,11 +439,39 @@ func (r *Cluster) Validate() (allErrs field.ErrorList) {
 		r.validateTolerations,
 		r.validateAntiAffinity,
 		r.validateReplicaMode,
+		r.validateObjectStoreName,
 		r.validateBackupConfiguration,
+		r.validateFinalBackup,
+		r.validateWalMirror,
+		r.validateWalArchiveBackend,
 		r.validateConfiguration,
 		r.validateLDAP,
+		r.validatePgIdent,
 		r.validateReplicationSlots,
+		r.validateMaxSlotWalKeepSize,
+		r.validateWalKeepSize,
+		r.validateCredentialsNamespace,
+		r.validateSynchronousCommit,
+		r.validateReplicasUnavailablePolicy,
+		r.validateReadOnlyStandbys,
+		r.validateReadOnly,
+		r.validateDelayedReplicas,
+		r.validateReadReplicas,
+		r.validateReplicaJoinMethod,
+		r.validateReplicationConnection,
 		r.validateEnv,
+		r.validateSidecars,
+		r.validateMountedObjects,
+		r.validateServiceAccount,
+		r.validateServiceNaming,
+		r.validateMaintenanceWindow,
+		r.validateScheduledRestart,
+		r.validateArchiveLibrary,
+		r.validatePublications,
+		r.validateExtensions,
+		r.validateAutovacuum,
+		r.validatePrewarm,
+		r.validateCheckpoint,
 	}
 
 	for _, validate := range validations {
@@ -340,9 +515,12 @@ func (r *Cluster) ValidateChanges(old *Cluster) (allErrs field.ErrorList) {
 	allErrs = append(allErrs, r.validateConfigurationChange(old)...)
 	allErrs = append(allErrs, r.validateStorageChange(old)...)
 	allErrs = append(allErrs, r.validateWalStorageChange(old)...)
+	allErrs = append(allErrs, r.validateTablespacesChange(old)...)
 	allErrs = append(allErrs, r.validateReplicaModeChange(old)...)
 	allErrs = append(allErrs, r.validateUnixPermissionIdentifierChange(old)...)
+	allErrs = append(allErrs, r.validateInitDBChange(old)...)
 	allErrs = append(allErrs, r.validateReplicationSlotsChange(old)...)
+	allErrs = append(allErrs, r.validateServiceAccountChange(old)...)
 	return allErrs
 }
 
@@ -354,6 +532,147 @@ func (r *Cluster) ValidateDelete() error {
 	return nil
 }
 
+// validateServiceNaming checks that the names of the services generated
+// with the configured naming scheme are valid. Kubernetes requires
+// the name of a service to be a DNS-1035 label, which is also
+// a valid DNS-1123 label
+func (r *Cluster) validateServiceNaming() field.ErrorList {
+	if r.Spec.ServiceNaming == nil {
+		return nil
+	}
+
+	var result field.ErrorList
+	path := field.NewPath("spec", "serviceNaming")
+	for _, serviceName := range []string{
+		r.GetServiceReadWriteName(),
+		r.GetServiceReadOnlyName(),
+		r.GetServiceReadName(),
+	} {
+		for _, msg := range validationutil.IsDNS1035Label(serviceName) {
+			result = append(result, field.Invalid(
+				path,
+				r.Spec.ServiceNaming,
+				fmt.Sprintf("the service name %q is not valid: %s", serviceName, msg)))
+		}
+	}
+
+	return result
+}
+
+// validateServiceAccount checks that an existing service account and the
+// template of the generated one are not specified together
+func (r *Cluster) validateServiceAccount() field.ErrorList {
+	if r.Spec.ServiceAccountName == "" {
+		return nil
+	}
+
+	var result field.ErrorList
+	path := field.NewPath("spec", "serviceAccountName")
+
+	for _, msg := range validationutil.IsDNS1123Subdomain(r.Spec.ServiceAccountName) {
+		result = append(result, field.Invalid(path, r.Spec.ServiceAccountName, msg))
+	}
+
+	if r.Spec.ServiceAccountTemplate != nil {
+		result = append(result, field.Invalid(
+			path,
+			r.Spec.ServiceAccountName,
+			"serviceAccountName and serviceAccountTemplate cannot be used together"))
+	}
+
+	return result
+}
+
+// validateMaintenanceWindow checks the time zone and the time ranges of
+// the maintenance window
+func (r *Cluster) validateMaintenanceWindow() field.ErrorList {
+	window := r.Spec.MaintenanceWindow
+	if window == nil {
+		return nil
+	}
+
+	var result field.ErrorList
+	path := field.NewPath("spec", "maintenanceWindow")
+
+	if _, err := window.GetLocation(); err != nil {
+		result = append(result, field.Invalid(
+			path.Child("timeZone"),
+			window.TimeZone,
+			fmt.Sprintf("unknown time zone: %v", err)))
+	}
+
+	for idx, timeRange := range window.Windows {
+		start, end, err := timeRange.parse()
+		if err != nil {
+			result = append(result, field.Invalid(
+				path.Child("windows").Index(idx),
+				timeRange,
+				err.Error()))
+			continue
+		}
+
+		if start == end {
+			result = append(result, field.Invalid(
+				path.Child("windows").Index(idx).Child("end"),
+				timeRange.End,
+				"the end of the time range must be different from its start"))
+		}
+	}
+
+	return result
+}
+
+// validateScheduledRestart checks the schedule of the restarts
+func (r *Cluster) validateScheduledRestart() field.ErrorList {
+	scheduledRestart := r.Spec.ScheduledRestart
+	if scheduledRestart == nil {
+		return nil
+	}
+
+	if _, err := cron.ParseStandard(scheduledRestart.Schedule); err != nil {
+		return field.ErrorList{
+			field.Invalid(
+				field.NewPath("spec", "scheduledRestart", "schedule"),
+				scheduledRestart.Schedule,
+				fmt.Sprintf("not a valid schedule: %v", err)),
+		}
+	}
+
+	return nil
+}
+
+// validateArchiveLibrary checks that the archive module is a plain
+// library name, which is written as is in the PostgreSQL configuration
+func (r *Cluster) validateArchiveLibrary() field.ErrorList {
+	archiveLibrary := r.Spec.PostgresConfiguration.ArchiveLibrary
+	if archiveLibrary == "" || archiveLibraryRegex.MatchString(archiveLibrary) {
+		return nil
+	}
+
+	return field.ErrorList{
+		field.Invalid(
+			field.NewPath("spec", "postgresql", "archiveLibrary"),
+			archiveLibrary,
+			"Invalid archive library name, only letters, digits, and the characters '_', '-', '.', '/' "+
+				"and '$' are allowed"),
+	}
+}
+
+// validateServiceAccountChange rejects changes to the service account
+// used by the Pods, which can't be updated in the existing Pods
+func (r *Cluster) validateServiceAccountChange(old *Cluster) field.ErrorList {
+	if r.Spec.ServiceAccountName == old.Spec.ServiceAccountName {
+		return nil
+	}
+
+	return field.ErrorList{
+		field.Invalid(
+			field.NewPath("spec", "serviceAccountName"),
+			r.Spec.ServiceAccountName,
+			"serviceAccountName cannot be changed once the cluster is created"),
+	}
+}
+
 // validateLDAP validates the ldap postgres configuration
 func (r *Cluster) validateLDAP() field.ErrorList {
 	// No validating if not specified
@@ -381,6 +700,23 @@ func (r *Cluster) validateLDAP() field.ErrorList {
 	return result
 }
 
+// validatePgIdent validates the syntax of the user name maps
+func (r *Cluster) validatePgIdent() field.ErrorList {
+	var result field.ErrorList
+
+	for i, rule := range r.Spec.PostgresConfiguration.PgIdent {
+		if err := postgres.ValidateIdentRule(rule); err != nil {
+			result = append(
+				result,
+				field.Invalid(field.NewPath("spec", "postgresql", "pg_ident").Index(i),
+					rule,
+					err.Error()))
+		}
+	}
+
+	return result
+}
+
 // validateEnv validate the environment variables settings proposed by the user
 func (r *Cluster) validateEnv() field.ErrorList {
 	var result field.ErrorList
@@ -399,6 +735,143 @@ func (r *Cluster) validateEnv() field.ErrorList {
 	return result
 }
 
+// reservedContainerNames are the names of the containers created
+// by the operator in the instance pods
+var reservedContainerNames = []string{"postgres", "bootstrap-controller"}
+
+// reservedVolumeNames are the names of the volumes created by the
+// operator in the instance pods. The volumes of the tablespaces
+// use the "tbs-" prefix
+var reservedVolumeNames = []string{
+	"pgdata",
+	"scratch-data",
+	"shm",
+	"superuser-secret",
+	"app-secret",
+	"pg-wal",
+	"projected",
+	"barman-endpoint-ca",
+}
+
+// isReservedVolumeName detects if a volume name is reserved for
+// the usage of the operator
+func isReservedVolumeName(name string) bool {
+	return slices.Contains(reservedVolumeNames, name) || strings.HasPrefix(name, "tbs-")
+}
+
+// validateMountedObjects checks that the secrets and config maps whose
+// changes are tracked are unique, and mounted in the instances
+func (r *Cluster) validateMountedObjects() field.ErrorList {
+	var result field.ErrorList
+
+	mounted := r.getMountedObjects()
+	references := stringset.New()
+	for idx, reference := range r.Spec.MountedObjects {
+		path := field.NewPath("spec", "mountedObjects").Index(idx)
+		key := reference.Kind + "/" + reference.Name
+		switch {
+		case references.Has(key):
+			result = append(result, field.Duplicate(path, key))
+		case !mounted.Has(key):
+			result = append(result, field.Invalid(path.Child("name"), reference.Name,
+				fmt.Sprintf("the %s is not mounted through the projected volume or the sidecar volumes",
+					reference.Kind)))
+		}
+		references.Put(key)
+	}
+
+	return result
+}
+
+// getMountedObjects gets the secrets and the config maps mounted in the
+// instances through the projected volume or the sidecar volumes, in the
+// `Kind/name` format
+func (r *Cluster) getMountedObjects() *stringset.Data {
+	result := stringset.New()
+	addProjections := func(projections []v1.VolumeProjection) {
+		for _, projection := range projections {
+			if projection.Secret != nil {
+				result.Put("Secret/" + projection.Secret.Name)
+			}
+			if projection.ConfigMap != nil {
+				result.Put("ConfigMap/" + projection.ConfigMap.Name)
+			}
+		}
+	}
+
+	if r.Spec.ProjectedVolumeTemplate != nil {
+		addProjections(r.Spec.ProjectedVolumeTemplate.Sources)
+	}
+	for _, volume := range r.Spec.SidecarVolumes {
+		switch {
+		case volume.Secret != nil:
+			result.Put("Secret/" + volume.Secret.SecretName)
+		case volume.ConfigMap != nil:
+			result.Put("ConfigMap/" + volume.ConfigMap.Name)
+		case volume.Projected != nil:
+			addProjections(volume.Projected.Sources)
+		}
+	}
+
+	return result
+}
+
+// validateSidecars checks that the sidecars and their volumes don't collide
+// with the containers and the volumes of the operator, and that the
+// sidecars mount the volumes of the operator in read-only mode
+func (r *Cluster) validateSidecars() field.ErrorList {
+	var result field.ErrorList
+
+	volumes := stringset.New()
+	for idx, volume := range r.Spec.SidecarVolumes {
+		path := field.NewPath("spec", "sidecarVolumes").Index(idx).Child("name")
+		switch {
+		case isReservedVolumeName(volume.Name):
+			result = append(result, field.Invalid(path, volume.Name,
+				"the usage of this volume name is reserved for the operator"))
+		case volumes.Has(volume.Name):
+			result = append(result, field.Duplicate(path, volume.Name))
+		}
+		volumes.Put(volume.Name)
+	}
+
+	containers := stringset.New()
+	for idx, sidecar := range r.Spec.Sidecars {
+		path := field.NewPath("spec", "sidecars").Index(idx)
+		switch {
+		case slices.Contains(reservedContainerNames, sidecar.Name):
+			result = append(result, field.Invalid(path.Child("name"), sidecar.Name,
+				"the usage of this container name is reserved for the operator"))
+		case containers.Has(sidecar.Name):
+			result = append(result, field.Duplicate(path.Child("name"), sidecar.Name))
+		default:
+			for _, msg := range validationutil.IsDNS1123Label(sidecar.Name) {
+				result = append(result, field.Invalid(path.Child("name"), sidecar.Name, msg))
+			}
+		}
+		containers.Put(sidecar.Name)
+
+		if sidecar.Image == "" {
+			result = append(result, field.Required(path.Child("image"), "sidecars need an image"))
+		}
+
+		for mountIdx, volumeMount := range sidecar.VolumeMounts {
+			mountPath := path.Child("volumeMounts").Index(mountIdx)
+			switch {
+			case isReservedVolumeName(volumeMount.Name):
+				if !volumeMount.ReadOnly {
+					result = append(result, field.Invalid(mountPath.Child("readOnly"), volumeMount.ReadOnly,
+						"the volumes of the operator can only be mounted in read-only mode"))
+				}
+			case !volumes.Has(volumeMount.Name):
+				result = append(result, field.NotFound(mountPath.Child("name"), volumeMount.Name))
+			}
+		}
+	}
+
+	return result
+}
+
 // isReservedEnvironmentVariable detects if a certain environment variable
 // is reserved for the usage of the operator
 func isReservedEnvironmentVariable(name string) bool {
@@ -683,6 +1156,17 @@ func (r *Cluster) validateCerts() field.ErrorList {
 		}
 	}
 
+	// The issuer generates both the server certificate and the CA secret
+	if certificates.ServerIssuerRef != nil &&
+		(certificates.ServerTLSSecret != "" || certificates.ServerCASecret != "") {
+		result = append(
+			result,
+			field.Invalid(
+				field.NewPath("spec", "certificates", "serverIssuerRef"),
+				certificates.ServerIssuerRef.Name,
+				"Server issuer can't be used together with server TLS secret or server CA secret"))
+	}
+
 	// If you provide the ReplicationTLSSecret we must provide the ClientCaSecret
 	if certificates.ReplicationTLSSecret != "" && certificates.ClientCASecret == "" {
 		result = append(
@@ -762,14 +1246,37 @@ func (r *Cluster) validateBootstrapPgBaseBackupSource() field.ErrorList {
 		return result
 	}
 
-	_, found := r.ExternalCluster(r.Spec.Bootstrap.PgBaseBackup.Source)
+	pgBaseBackup := r.Spec.Bootstrap.PgBaseBackup
+	path := field.NewPath("spec", "bootstrap", "pg_basebackup")
+	if pgBaseBackup.SourceCluster != nil {
+		switch {
+		case pgBaseBackup.Source != "":
+			result = append(result, field.Invalid(
+				path.Child("sourceCluster"),
+				pgBaseBackup.SourceCluster.Name,
+				"source and sourceCluster cannot be used together"))
+		case pgBaseBackup.SourceCluster.Name == "" || pgBaseBackup.SourceCluster.Name == r.Name:
+			result = append(result, field.Invalid(
+				path.Child("sourceCluster", "name"),
+				pgBaseBackup.SourceCluster.Name,
+				"the source cluster must be another cluster in the same namespace"))
+		case r.IsReplica():
+			result = append(result, field.Invalid(
+				path.Child("sourceCluster"),
+				pgBaseBackup.SourceCluster.Name,
+				"a replica cluster must be bootstrapped from a server defined in externalClusters"))
+		}
+		return result
+	}
+
+	_, found := r.ExternalCluster(pgBaseBackup.Source)
 	if !found {
 		result = append(
 			result,
 			field.Invalid(
-				field.NewPath("spec", "bootstrap", "pg_basebackup", "source"),
-				r.Spec.Bootstrap.PgBaseBackup.Source,
-				fmt.Sprintf("External cluster %v not found", r.Spec.Bootstrap.PgBaseBackup.Source)))
+				path.Child("source"),
+				pgBaseBackup.Source,
+				fmt.Sprintf("External cluster %v not found", pgBaseBackup.Source)))
 	}
 
 	return result
@@ -953,11 +1460,11 @@ func (r *Cluster) validateImageChange(old string) field.ErrorList {
 	newVersion := r.Spec.ImageName
 	if newVersion == "" {
 		// We'll use the default one
-		newVersion = configuration.Current.PostgresImageName
+		newVersion = configuration.Current.GetPostgresImageName()
 	}
 
 	if old == "" {
-		old = configuration.Current.PostgresImageName
+		old = configuration.Current.GetPostgresImageName()
 	}
 
 	status, err := postgres.CanUpgrade(old, newVersion)
@@ -968,19 +1475,58 @@ func (r *Cluster) validateImageChange(old string) field.ErrorList {
 				field.NewPath("spec", "imageName"),
 				r.Spec.ImageName,
 				fmt.Sprintf("wrong version: %v", err.Error())))
-	} else if !status {
+	} else if !status && !r.isMajorUpgradeAllowed(old, newVersion) {
+		message := fmt.Sprintf("can't upgrade between %v and %v", old, newVersion)
+		if !r.Spec.EnableMajorUpgrade && r.isMajorUpgradeSupported(old, newVersion) {
+			message += ": set `enableMajorUpgrade` to upgrade the data directory in place via pg_upgrade"
+		}
 		result = append(
 			result,
 			field.Invalid(
 				field.NewPath("spec", "imageName"),
 				r.Spec.ImageName,
-				fmt.Sprintf("can't upgrade between %v and %v",
-					old, newVersion)))
+				message))
 	}
 
 	return result
 }
 
+// isMajorUpgradeAllowed checks if the change from a certain image to a new
+// one is an upgrade of the PostgreSQL major version that the operator has
+// been allowed to handle via pg_upgrade, or the rollback of a failed one
+func (r *Cluster) isMajorUpgradeAllowed(oldImage, newImage string) bool {
+	newMajor, err := postgres.GetPostgresMajorVersionFromTag(utils.GetImageTag(newImage))
+	if err != nil {
+		return false
+	}
+
+	// The data directory of the instances is still at the previous major
+	// version, as the upgrade failed, and we can restore the original image
+	if r.Status.PGDataImageInfo != nil && r.Status.PGDataImageInfo.MajorVersion == newMajor {
+		return r.Status.Phase != PhaseMajorUpgrade
+	}
+
+	return r.Spec.EnableMajorUpgrade && r.isMajorUpgradeSupported(oldImage, newImage)
+}
+
+// isMajorUpgradeSupported checks if the change from a certain image to a
+// new one is an upgrade of the PostgreSQL major version that the operator
+// can handle via pg_upgrade
+func (r *Cluster) isMajorUpgradeSupported(oldImage, newImage string) bool {
+	oldMajor, err := postgres.GetPostgresMajorVersionFromTag(utils.GetImageTag(oldImage))
+	if err != nil {
+		return false
+	}
+
+	newMajor, err := postgres.GetPostgresMajorVersionFromTag(utils.GetImageTag(newImage))
+	if err != nil {
+		return false
+	}
+
+	// A replica cluster needs to follow the major version of its source
+	return newMajor > oldMajor && !r.IsReplica()
+}
+
 // Validate the recovery target to ensure that the mutual exclusivity
 // of options is respected and plus validating the format of targetTime
 // if specified
@@ -1142,6 +1688,55 @@ func (r *Cluster) validateMinSyncReplicas() field.ErrorList {
 	return result
 }
 
+// Validate that the number of instances is enough to host the
+// synchronous standbys required by minSyncReplicas, as the primary
+// would otherwise wait for them on every commit
+func (r *Cluster) validateSyncReplicasInstances() field.ErrorList {
+	if r.Spec.MinSyncReplicas <= 0 || r.Spec.Instances > r.Spec.MinSyncReplicas {
+		return nil
+	}
+
+	standbys := r.Spec.Instances - 1
+	if standbys < 0 {
+		standbys = 0
+	}
+
+	return field.ErrorList{
+		field.Invalid(
+			field.NewPath("spec", "instances"),
+			r.Spec.Instances,
+			fmt.Sprintf(
+				"minSyncReplicas is %d, so at least %d instances are needed "+
+					"(1 primary + %d synchronous standbys): with %d instances "+
+					"there would be only %d standbys and the writes would hang",
+				r.Spec.MinSyncReplicas,
+				r.Spec.MinSyncReplicas+1,
+				r.Spec.MinSyncReplicas,
+				r.Spec.Instances,
+				standbys)),
+	}
+}
+
+// Validate that the number of instances is odd when synchronous
+// replication is enabled, as an even number doesn't give a clear
+// majority. Unless the operator is configured to enforce it, this
+// check is only advisory, and reported by the `QuorumAtRisk` condition
+func (r *Cluster) validateQuorumInstances() field.ErrorList {
+	if !r.IsQuorumInstancesCheckApplicable() || r.Spec.Instances%2 != 0 ||
+		!configuration.Current.EnforceQuorumInstances {
+		return nil
+	}
+
+	return field.ErrorList{
+		field.Invalid(
+			field.NewPath("spec", "instances"),
+			r.Spec.Instances,
+			fmt.Sprintf(
+				"an even number of instances doesn't give a clear quorum with synchronous replication, "+
+					"%d instances are recommended", r.Spec.Instances+1)),
+	}
+}
+
 func (r *Cluster) validateStorageSize() field.ErrorList {
 	return validateStorageConfigurationSize("Storage", r.Spec.StorageConfiguration)
 }
@@ -1156,54 +1751,460 @@ func (r *Cluster) validateWalStorageSize() field.ErrorList {
 	return result
 }
 
-func validateStorageConfigurationSize(structPath string, storageConfiguration StorageConfiguration) field.ErrorList {
+// validateTablespaces checks the names of the tablespaces, which must be
+// unique and can't be reserved, and the size of their volumes
+func (r *Cluster) validateTablespaces() field.ErrorList {
 	var result field.ErrorList
 
-	if storageConfiguration.Size != "" {
-		if _, err := resource.ParseQuantity(storageConfiguration.Size); err != nil {
+	names := stringset.New()
+	for idx, tablespace := range r.Spec.Tablespaces {
+		path := field.NewPath("spec", "tablespaces").Index(idx)
+		if strings.HasPrefix(tablespace.Name, "pg_") {
 			result = append(result, field.Invalid(
-				field.NewPath("spec", structPath, "size"),
-				storageConfiguration.Size,
-				"Size value isn't valid"))
+				path.Child("name"),
+				tablespace.Name,
+				"the pg_ prefix is reserved for system tablespaces"))
 		}
-	}
+		if names.Has(tablespace.Name) {
+			result = append(result, field.Duplicate(path.Child("name"), tablespace.Name))
+		}
+		names.Put(tablespace.Name)
 
-	if storageConfiguration.Size == "" &&
-		(storageConfiguration.PersistentVolumeClaimTemplate == nil ||
-			storageConfiguration.PersistentVolumeClaimTemplate.Resources.Requests.Storage().IsZero()) {
-		result = append(result, field.Invalid(
-			field.NewPath("spec", structPath, "size"),
-			storageConfiguration.Size,
-			"Size not configured. Please add it, or a storage request in the pvcTemplate."))
+		result = append(result, validateStorageConfigurationSize(
+			fmt.Sprintf("tablespaces[%d].storage", idx), tablespace.Storage)...)
 	}
 
 	return result
 }
 
-// Validate a change in the storage
-func (r *Cluster) validateStorageChange(old *Cluster) field.ErrorList {
-	return validateStorageConfigurationChange(
-		"storage",
-		old.Spec.StorageConfiguration,
-		r.Spec.StorageConfiguration,
-	)
-}
-
-func (r *Cluster) validateWalStorageChange(old *Cluster) field.ErrorList {
-	if old.Spec.WalStorage == nil {
+// validateAutovacuum checks the autovacuum settings of the cluster, which
+// can't be set in the parameters too, and the storage parameters of the tables
+func (r *Cluster) validateAutovacuum() field.ErrorList {
+	autovacuum := r.Spec.PostgresConfiguration.Autovacuum
+	if autovacuum == nil {
 		return nil
 	}
 
-	if old.Spec.WalStorage != nil && r.Spec.WalStorage == nil {
-		return field.ErrorList{
-			field.Invalid(
-				field.NewPath("spec", "walStorage"),
-				r.Spec.WalStorage,
-				"walStorage cannot be disabled once the cluster is created"),
+	var result field.ErrorList
+	path := field.NewPath("spec", "postgresql", "autovacuum")
+
+	for name, scaleFactor := range map[string]string{
+		"vacuumScaleFactor":  autovacuum.VacuumScaleFactor,
+		"analyzeScaleFactor": autovacuum.AnalyzeScaleFactor,
+	} {
+		if scaleFactor == "" {
+			continue
+		}
+		if err := postgres.ValidateAutovacuumStorageParameter(
+			"autovacuum_vacuum_scale_factor", scaleFactor); err != nil {
+			result = append(result, field.Invalid(
+				path.Child(name), scaleFactor, "must be a number between 0 and 100"))
 		}
 	}
 
-	return validateStorageConfigurationChange("walStorage", *old.Spec.WalStorage, *r.Spec.WalStorage)
+	for key := range autovacuum.GetParameters() {
+		if _, ok := r.Spec.PostgresConfiguration.Parameters[key]; ok {
+			result = append(result, field.Invalid(
+				field.NewPath("spec", "postgresql", "parameters", key),
+				r.Spec.PostgresConfiguration.Parameters[key],
+				"this parameter is managed through the autovacuum section"))
+		}
+	}
+
+	tables := stringset.New()
+	for idx, table := range autovacuum.Tables {
+		tablePath := path.Child("tables").Index(idx)
+
+		key := table.DBName + "/" + table.Name
+		if tables.Has(key) {
+			result = append(result, field.Duplicate(tablePath.Child("name"), table.Name))
+		}
+		tables.Put(key)
+
+		for parameter, value := range table.Parameters {
+			if err := postgres.ValidateAutovacuumStorageParameter(parameter, value); err != nil {
+				result = append(result, field.Invalid(
+					tablePath.Child("parameters", parameter), value, err.Error()))
+			}
+		}
+	}
+
+	return result
+}
+
+var (
+	// defaultMaxWalSize is the PostgreSQL default of max_wal_size
+	defaultMaxWalSize = resource.MustParse("1Gi")
+
+	// defaultMinWalSize is the PostgreSQL default of min_wal_size
+	defaultMinWalSize = resource.MustParse("80Mi")
+)
+
+// validateCheckpoint checks the checkpoint settings of the cluster, which
+// can't be set in the parameters too. The WAL sizes must be at least two WAL
+// segments, consistent with each other, and fit in the volume holding `pg_wal`
+func (r *Cluster) validateCheckpoint() field.ErrorList {
+	checkpoint := r.Spec.PostgresConfiguration.Checkpoint
+	if checkpoint == nil {
+		return nil
+	}
+
+	var result field.ErrorList
+	path := field.NewPath("spec", "postgresql", "checkpoint")
+
+	if checkpoint.CompletionTarget != "" {
+		target, err := strconv.ParseFloat(checkpoint.CompletionTarget, 64)
+		if err != nil || target < 0 || target > 1 {
+			result = append(result, field.Invalid(
+				path.Child("completionTarget"), checkpoint.CompletionTarget, "must be a number between 0 and 1"))
+		}
+	}
+
+	walSegmentSize := int64(16)
+	if r.Spec.Bootstrap != nil && r.Spec.Bootstrap.InitDB != nil && r.Spec.Bootstrap.InitDB.WalSegmentSize != 0 {
+		walSegmentSize = int64(r.Spec.Bootstrap.InitDB.WalSegmentSize)
+	}
+	minimumSize := resource.NewQuantity(2*walSegmentSize*1024*1024, resource.BinarySI)
+
+	for name, size := range map[string]*resource.Quantity{
+		"maxWalSize": checkpoint.MaxWalSize,
+		"minWalSize": checkpoint.MinWalSize,
+	} {
+		if size != nil && size.Cmp(*minimumSize) < 0 {
+			result = append(result, field.Invalid(
+				path.Child(name), size.String(),
+				fmt.Sprintf("must be at least twice the WAL segment size (%s)", minimumSize.String())))
+		}
+	}
+
+	maxWalSize := defaultMaxWalSize
+	if checkpoint.MaxWalSize != nil {
+		maxWalSize = *checkpoint.MaxWalSize
+	}
+	minWalSize := defaultMinWalSize
+	if checkpoint.MinWalSize != nil {
+		minWalSize = *checkpoint.MinWalSize
+	}
+	if minWalSize.Cmp(maxWalSize) > 0 {
+		result = append(result, field.Invalid(
+			path.Child("minWalSize"), minWalSize.String(),
+			fmt.Sprintf("must not be greater than maxWalSize (%s)", maxWalSize.String())))
+	}
+
+	// The volume holding pg_wal needs room for the WAL files which are
+	// waiting to be archived, too
+	walStorage := &r.Spec.StorageConfiguration
+	if r.ShouldCreateWalArchiveVolume() {
+		walStorage = r.Spec.WalStorage
+	}
+	if walVolumeSize := walStorage.GetSizeOrNil(); checkpoint.MaxWalSize != nil &&
+		walVolumeSize != nil && !walVolumeSize.IsZero() && checkpoint.MaxWalSize.Cmp(*walVolumeSize) >= 0 {
+		result = append(result, field.Invalid(
+			path.Child("maxWalSize"), checkpoint.MaxWalSize.String(),
+			fmt.Sprintf("must be smaller than the size of the volume holding pg_wal (%s)", walVolumeSize.String())))
+	}
+
+	for key := range checkpoint.GetParameters() {
+		if _, ok := r.Spec.PostgresConfiguration.Parameters[key]; ok {
+			result = append(result, field.Invalid(
+				field.NewPath("spec", "postgresql", "parameters", key),
+				r.Spec.PostgresConfiguration.Parameters[key],
+				"this parameter is managed through the checkpoint section"))
+		}
+	}
+
+	return result
+}
+
+// validatePrewarm checks that every relation to be loaded in the buffer
+// cache is declared once, and that the maximum size is positive
+func (r *Cluster) validatePrewarm() field.ErrorList {
+	prewarm := r.Spec.PostgresConfiguration.Prewarm
+	if prewarm == nil {
+		return nil
+	}
+
+	var result field.ErrorList
+	path := field.NewPath("spec", "postgresql", "prewarm")
+
+	if prewarm.MaxRelationSize != nil && prewarm.MaxRelationSize.Sign() <= 0 {
+		result = append(result, field.Invalid(
+			path.Child("maxRelationSize"), prewarm.MaxRelationSize.String(), "must be greater than zero"))
+	}
+
+	relations := stringset.New()
+	for idx, relation := range prewarm.Relations {
+		key := relation.DBName + "/" + relation.Name
+		if relations.Has(key) {
+			result = append(result, field.Duplicate(path.Child("relations").Index(idx).Child("name"), relation.Name))
+		}
+		relations.Put(key)
+	}
+
+	return result
+}
+
+// validatePublications checks that every publication is declared once,
+// and publishes either every table or a list of tables
+func (r *Cluster) validatePublications() field.ErrorList {
+	var result field.ErrorList
+
+	publications := stringset.New()
+	for idx, publication := range r.Spec.Publications {
+		path := field.NewPath("spec", "publications").Index(idx)
+
+		key := publication.DBName + "/" + publication.Name
+		if publications.Has(key) {
+			result = append(result, field.Duplicate(path.Child("name"), publication.Name))
+		}
+		publications.Put(key)
+
+		switch {
+		case publication.AllTables && len(publication.Tables) > 0:
+			result = append(result, field.Invalid(
+				path.Child("tables"),
+				publication.Tables,
+				"tables can't be listed when allTables is set"))
+		case !publication.AllTables && len(publication.Tables) == 0:
+			result = append(result, field.Required(
+				path.Child("tables"),
+				"either allTables must be set or at least a table must be listed"))
+		}
+
+		tables := stringset.New()
+		for tableIdx, table := range publication.Tables {
+			if tables.Has(table) {
+				result = append(result, field.Duplicate(path.Child("tables").Index(tableIdx), table))
+			}
+			tables.Put(table)
+		}
+	}
+
+	return result
+}
+
+// validateExtensions checks that every extension is declared once, and
+// that only the extensions which can be created are created in databases
+func (r *Cluster) validateExtensions() field.ErrorList {
+	var result field.ErrorList
+
+	extensions := stringset.New()
+	for idx, extension := range r.Spec.Extensions {
+		path := field.NewPath("spec", "extensions").Index(idx)
+
+		if extensions.Has(extension.Name) {
+			result = append(result, field.Duplicate(path.Child("name"), extension.Name))
+		}
+		extensions.Put(extension.Name)
+
+		for _, managedExtension := range postgres.ManagedExtensions {
+			if managedExtension.Name == extension.Name && managedExtension.SkipCreateExtension &&
+				len(extension.DBNames) > 0 {
+				result = append(result, field.Invalid(
+					path.Child("dbnames"),
+					extension.DBNames,
+					fmt.Sprintf("%s can't be created in a database, as it's only loaded via "+
+						"shared_preload_libraries", extension.Name)))
+			}
+		}
+
+		dbNames := stringset.New()
+		for dbNameIdx, dbName := range extension.DBNames {
+			if dbNames.Has(dbName) {
+				result = append(result, field.Duplicate(path.Child("dbnames").Index(dbNameIdx), dbName))
+			}
+			dbNames.Put(dbName)
+		}
+
+		for libraryIdx, library := range extension.SharedPreloadLibraries {
+			if library == "" || strings.ContainsAny(library, ", '\"") {
+				result = append(result, field.Invalid(
+					path.Child("sharedPreloadLibraries").Index(libraryIdx),
+					library,
+					"not a valid library name"))
+			}
+		}
+	}
+
+	return result
+}
+
+func validateStorageConfigurationSize(structPath string, storageConfiguration StorageConfiguration) field.ErrorList {
+	var result field.ErrorList
+
+	if storageConfiguration.Size != "" {
+		if _, err := resource.ParseQuantity(storageConfiguration.Size); err != nil {
+			result = append(result, field.Invalid(
+				field.NewPath("spec", structPath, "size"),
+				storageConfiguration.Size,
+				"Size value isn't valid"))
+		}
+	}
+
+	if storageConfiguration.Size == "" &&
+		(storageConfiguration.PersistentVolumeClaimTemplate == nil ||
+			storageConfiguration.PersistentVolumeClaimTemplate.Resources.Requests.Storage().IsZero()) {
+		result = append(result, field.Invalid(
+			field.NewPath("spec", structPath, "size"),
+			storageConfiguration.Size,
+			"Size not configured. Please add it, or a storage request in the pvcTemplate."))
+	}
+
+	return result
+}
+
+// validateStorageClassesAndModes checks the storage classes and the volume
+// modes of the PVCs of the cluster
+func (r *Cluster) validateStorageClassesAndModes() field.ErrorList {
+	result := validateStorageConfigurationClassAndMode("storage", r.Spec.StorageConfiguration)
+	if r.Spec.WalStorage != nil {
+		result = append(result, validateStorageConfigurationClassAndMode("walStorage", *r.Spec.WalStorage)...)
+	}
+	for idx, tablespace := range r.Spec.Tablespaces {
+		result = append(result, validateStorageConfigurationClassAndMode(
+			fmt.Sprintf("tablespaces[%d].storage", idx), tablespace.Storage)...)
+	}
+
+	return result
+}
+
+// validateStorageConfigurationClassAndMode checks that the storage class is
+// a valid name, and that the volumes are formatted with a filesystem, as
+// PostgreSQL can't use a raw block device
+func validateStorageConfigurationClassAndMode(
+	structPath string,
+	storageConfiguration StorageConfiguration,
+) field.ErrorList {
+	var result field.ErrorList
+
+	// An empty storage class requests a volume without any class
+	if storageClass := storageConfiguration.StorageClass; storageClass != nil && *storageClass != "" {
+		for _, msg := range validationutil.IsDNS1123Subdomain(*storageClass) {
+			result = append(result, field.Invalid(
+				field.NewPath("spec", structPath, "storageClass"),
+				*storageClass,
+				msg))
+		}
+	}
+
+	if template := storageConfiguration.PersistentVolumeClaimTemplate; template != nil &&
+		template.VolumeMode != nil && *template.VolumeMode != v1.PersistentVolumeFilesystem {
+		result = append(result, field.NotSupported(
+			field.NewPath("spec", structPath, "pvcTemplate", "volumeMode"),
+			*template.VolumeMode,
+			[]string{string(v1.PersistentVolumeFilesystem)}))
+	}
+
+	return result
+}
+
+// validateStorageSubPaths checks that the subPath of the volumes of the
+// cluster is a relative path which doesn't leave the volume
+func (r *Cluster) validateStorageSubPaths() field.ErrorList {
+	result := validateStorageConfigurationSubPath("storage", r.Spec.StorageConfiguration)
+	if r.Spec.WalStorage != nil {
+		result = append(result, validateStorageConfigurationSubPath("walStorage", *r.Spec.WalStorage)...)
+	}
+	for idx, tablespace := range r.Spec.Tablespaces {
+		result = append(result, validateStorageConfigurationSubPath(
+			fmt.Sprintf("tablespaces[%d].storage", idx), tablespace.Storage)...)
+	}
+
+	return result
+}
+
+// validateStorageConfigurationSubPath checks that the subPath of a volume
+// is a relative path without any `..` element
+func validateStorageConfigurationSubPath(
+	structPath string,
+	storageConfiguration StorageConfiguration,
+) field.ErrorList {
+	subPath := storageConfiguration.SubPath
+	if subPath == "" {
+		return nil
+	}
+
+	if path.IsAbs(subPath) {
+		return field.ErrorList{
+			field.Invalid(
+				field.NewPath("spec", structPath, "subPath"),
+				subPath,
+				"subPath must be a relative path"),
+		}
+	}
+
+	for _, element := range strings.Split(subPath, "/") {
+		if element == ".." {
+			return field.ErrorList{
+				field.Invalid(
+					field.NewPath("spec", structPath, "subPath"),
+					subPath,
+					"subPath can't contain '..'"),
+			}
+		}
+	}
+
+	return nil
+}
+
+// Validate a change in the storage
+func (r *Cluster) validateStorageChange(old *Cluster) field.ErrorList {
+	return validateStorageConfigurationChange(
+		"storage",
+		old.Spec.StorageConfiguration,
+		r.Spec.StorageConfiguration,
+	)
+}
+
+func (r *Cluster) validateWalStorageChange(old *Cluster) field.ErrorList {
+	if old.Spec.WalStorage == nil {
+		return nil
+	}
+
+	if old.Spec.WalStorage != nil && r.Spec.WalStorage == nil {
+		return field.ErrorList{
+			field.Invalid(
+				field.NewPath("spec", "walStorage"),
+				r.Spec.WalStorage,
+				"walStorage cannot be disabled once the cluster is created"),
+		}
+	}
+
+	return validateStorageConfigurationChange("walStorage", *old.Spec.WalStorage, *r.Spec.WalStorage)
+}
+
+// validateTablespacesChange rejects adding or removing tablespaces, as
+// their volumes are only created together with the instances, while
+// allowing their storage to grow
+func (r *Cluster) validateTablespacesChange(old *Cluster) field.ErrorList {
+	oldNames := make([]string, len(old.Spec.Tablespaces))
+	for idx, tablespace := range old.Spec.Tablespaces {
+		oldNames[idx] = tablespace.Name
+	}
+	newNames := make([]string, len(r.Spec.Tablespaces))
+	for idx, tablespace := range r.Spec.Tablespaces {
+		newNames[idx] = tablespace.Name
+	}
+	sort.Strings(oldNames)
+	sort.Strings(newNames)
+	if !reflect.DeepEqual(oldNames, newNames) {
+		return field.ErrorList{
+			field.Invalid(
+				field.NewPath("spec", "tablespaces"),
+				newNames,
+				fmt.Sprintf("tablespaces cannot be added or removed once the cluster is created, "+
+					"the existing ones are %v", oldNames)),
+		}
+	}
+
+	var result field.ErrorList
+	for idx, tablespace := range r.Spec.Tablespaces {
+		oldTablespace := old.GetTablespace(tablespace.Name)
+		result = append(result, validateStorageConfigurationChange(
+			fmt.Sprintf("tablespaces[%d].storage", idx), oldTablespace.Storage, tablespace.Storage)...)
+	}
+
+	return result
 }
 
 // validateStorageConfigurationChange generates an error list by comparing two StorageConfiguration
@@ -1212,6 +2213,17 @@ func validateStorageConfigurationChange(
 	oldStorage StorageConfiguration,
 	newStorage StorageConfiguration,
 ) field.ErrorList {
+	// The data of the existing volumes is stored in the old subPath
+	if oldStorage.SubPath != newStorage.SubPath {
+		return field.ErrorList{
+			field.Invalid(
+				field.NewPath("spec", structPath, "subPath"),
+				newStorage.SubPath,
+				fmt.Sprintf("subPath can't be changed once the volumes have been created, the current one is %q",
+					oldStorage.SubPath)),
+		}
+	}
+
 	oldSize := oldStorage.GetSizeOrNil()
 	if oldSize == nil {
 		// Can't read the old size, so can't tell if the new size is greater
@@ -1287,12 +2299,34 @@ func (r *Cluster) validateExternalClusters() field.ErrorList {
 func (r *Cluster) validateExternalCluster(externalCluster *ExternalCluster, path *field.Path) field.ErrorList {
 	var result field.ErrorList
 
-	if externalCluster.ConnectionParameters == nil && externalCluster.BarmanObjectStore == nil {
+	if externalCluster.ConnectionParameters == nil && externalCluster.BarmanObjectStore == nil &&
+		externalCluster.WalG == nil {
 		result = append(result,
 			field.Invalid(
 				path,
 				externalCluster,
-				"one of connectionParameters and barmanObjectStore is required"))
+				"one of connectionParameters, barmanObjectStore and walG is required"))
+	}
+
+	if externalCluster.BarmanObjectStore != nil && externalCluster.WalG != nil {
+		result = append(result,
+			field.Invalid(
+				path.Child("walG"),
+				externalCluster.WalG,
+				"barmanObjectStore and walG are mutually exclusive"))
+	}
+
+	if externalCluster.WalG != nil {
+		result = append(result, externalCluster.WalG.validate(path.Child("walG"))...)
+	}
+
+	if externalCluster.BarmanObjectStore != nil {
+		result = append(result, externalCluster.BarmanObjectStore.Wal.validateRestoreMaxParallel(
+			path.Child("barmanObjectStore", "wal"))...)
+		result = append(result, externalCluster.BarmanObjectStore.Wal.validateStagingNotSupported(
+			path.Child("barmanObjectStore", "wal"))...)
+		result = append(result, externalCluster.BarmanObjectStore.validateCredentialsVolumeNotSupported(
+			path.Child("barmanObjectStore"))...)
 	}
 
 	return result
@@ -1337,6 +2371,55 @@ func (r *Cluster) validateUnixPermissionIdentifierChange(old *Cluster) field.Err
 	return result
 }
 
+// validateInitDBChange rejects any change to the options passed to initdb,
+// which has already created the data directory of the cluster
+func (r *Cluster) validateInitDBChange(old *Cluster) field.ErrorList {
+	if r.Spec.Bootstrap == nil || r.Spec.Bootstrap.InitDB == nil ||
+		old.Spec.Bootstrap == nil || old.Spec.Bootstrap.InitDB == nil {
+		return nil
+	}
+
+	var result field.ErrorList
+	newInitDB := r.Spec.Bootstrap.InitDB
+	oldInitDB := old.Spec.Bootstrap.InitDB
+	path := field.NewPath("spec", "bootstrap", "initdb")
+	immutableError := func(name string, value interface{}) *field.Error {
+		return field.Invalid(
+			path.Child(name),
+			value,
+			"cannot be changed, as initdb has already been executed")
+	}
+
+	isEnabled := func(value *bool) bool {
+		return value != nil && *value
+	}
+	if isEnabled(newInitDB.DataChecksums) != isEnabled(oldInitDB.DataChecksums) {
+		result = append(result, immutableError("dataChecksums", isEnabled(newInitDB.DataChecksums)))
+	}
+
+	// The previous values may be empty if the cluster was created
+	// before these fields were defaulted
+	if oldInitDB.Encoding != "" && newInitDB.Encoding != oldInitDB.Encoding {
+		result = append(result, immutableError("encoding", newInitDB.Encoding))
+	}
+	if oldInitDB.LocaleCollate != "" && newInitDB.LocaleCollate != oldInitDB.LocaleCollate {
+		result = append(result, immutableError("localeCollate", newInitDB.LocaleCollate))
+	}
+	if oldInitDB.LocaleCType != "" && newInitDB.LocaleCType != oldInitDB.LocaleCType {
+		result = append(result, immutableError("localeCType", newInitDB.LocaleCType))
+	}
+
+	if newInitDB.WalSegmentSize != oldInitDB.WalSegmentSize {
+		result = append(result, immutableError("walSegmentSize", newInitDB.WalSegmentSize))
+	}
+
+	if !reflect.DeepEqual(newInitDB.Options, oldInitDB.Options) { //nolint:staticcheck
+		result = append(result, immutableError("options", newInitDB.Options)) //nolint:staticcheck
+	}
+
+	return result
+}
+
 // Check if the replica mode is used with an incompatible bootstrap
 // method
 func (r *Cluster) validateReplicaMode() field.ErrorList {
@@ -1473,35 +2556,306 @@ func (r *Cluster) validateAntiAffinity() field.ErrorList {
 	return allErrors
 }
 
-// validateBackupConfiguration validates the backup configuration
-func (r *Cluster) validateBackupConfiguration() field.ErrorList {
-	allErrors := field.ErrorList{}
-
-	if r.Spec.Backup == nil || r.Spec.Backup.BarmanObjectStore == nil {
+// validateObjectStoreName checks that the object store referenced
+// by the cluster is defined in the operator configuration
+func (r *Cluster) validateObjectStoreName() field.ErrorList {
+	if r.Spec.Backup == nil || r.Spec.Backup.ObjectStoreName == "" {
 		return nil
 	}
 
-	credentialsCount := 0
-	if r.Spec.Backup.BarmanObjectStore.BarmanCredentials.Azure != nil {
-		credentialsCount++
-		allErrors = r.Spec.Backup.BarmanObjectStore.BarmanCredentials.Azure.validateAzureCredentials(
-			field.NewPath("spec", "backupConfiguration", "azureCredentials"))
+	var objectStore BarmanObjectStoreConfiguration
+	err := configuration.Current.GetObjectStore(r.Spec.Backup.ObjectStoreName, &objectStore)
+	switch {
+	case errors.Is(err, configuration.ErrObjectStoreNotFound):
+		return field.ErrorList{
+			field.NotFound(
+				field.NewPath("spec", "backup", "objectStoreName"),
+				r.Spec.Backup.ObjectStoreName),
+		}
+	case err != nil:
+		return field.ErrorList{
+			field.Invalid(
+				field.NewPath("spec", "backup", "objectStoreName"),
+				r.Spec.Backup.ObjectStoreName,
+				err.Error()),
+		}
 	}
-	if r.Spec.Backup.BarmanObjectStore.BarmanCredentials.AWS != nil {
-		credentialsCount++
-		allErrors = r.Spec.Backup.BarmanObjectStore.BarmanCredentials.AWS.validateAwsCredentials(
-			field.NewPath("spec", "backupConfiguration", "s3Credentials"))
+
+	return nil
+}
+
+// validateFinalBackup checks the final backup has an object store to
+// write to
+func (r *Cluster) validateFinalBackup() field.ErrorList {
+	if r.Spec.Backup == nil || r.Spec.Backup.FinalBackup == nil || !r.Spec.Backup.FinalBackup.Enabled {
+		return nil
 	}
-	if r.Spec.Backup.BarmanObjectStore.BarmanCredentials.Google != nil {
-		credentialsCount++
-		allErrors = r.Spec.Backup.BarmanObjectStore.BarmanCredentials.Google.validateGCSCredentials(
-			field.NewPath("spec", "backupConfiguration", "googleCredentials"))
+
+	if r.Spec.Backup.BarmanObjectStore == nil {
+		return field.ErrorList{
+			field.Invalid(
+				field.NewPath("spec", "backup", "finalBackup", "enabled"),
+				r.Spec.Backup.FinalBackup.Enabled,
+				"The final backup requires the barmanObjectStore to be configured"),
+		}
 	}
-	if credentialsCount == 0 {
-		allErrors = append(allErrors, field.Invalid(
-			field.NewPath("spec", "backupConfiguration"),
-			r.Spec.Backup.BarmanObjectStore,
-			"missing credentials. "+
+
+	return nil
+}
+
+// validateWalMirror checks the configuration of the mirroring of the
+// WAL archive to a secondary object store
+func (r *Cluster) validateWalMirror() field.ErrorList {
+	if r.Spec.Backup == nil || r.Spec.Backup.WalMirror == nil {
+		return nil
+	}
+
+	var result field.ErrorList
+	mirror := r.Spec.Backup.WalMirror
+	path := field.NewPath("spec", "backup", "mirror")
+
+	if r.Spec.Backup.BarmanObjectStore == nil {
+		result = append(result, field.Invalid(
+			path,
+			mirror,
+			"The mirroring of the WAL archive requires the barmanObjectStore to be configured"))
+	}
+
+	if _, err := cron.ParseStandard(mirror.GetSchedule()); err != nil {
+		result = append(result, field.Invalid(
+			path.Child("schedule"),
+			mirror.Schedule,
+			fmt.Sprintf("not a valid schedule: %v", err)))
+	}
+
+	credentials := mirror.BarmanObjectStore.BarmanCredentials
+	credentialsCount := 0
+	if credentials.Azure != nil {
+		credentialsCount++
+		result = append(result, credentials.Azure.validateAzureCredentials(
+			path.Child("barmanObjectStore", "azureCredentials"))...)
+	}
+	if credentials.AWS != nil {
+		credentialsCount++
+		result = append(result, credentials.AWS.validateAwsCredentials(
+			path.Child("barmanObjectStore", "s3Credentials"))...)
+	}
+	if credentials.Google != nil {
+		credentialsCount++
+		result = append(result, credentials.Google.validateGCSCredentials(
+			path.Child("barmanObjectStore", "googleCredentials"))...)
+	}
+	if credentialsCount != 1 {
+		result = append(result, field.Invalid(
+			path.Child("barmanObjectStore"),
+			mirror.BarmanObjectStore,
+			"One and only one of azureCredentials, s3Credentials and googleCredentials are required",
+		))
+	}
+
+	result = append(result, mirror.BarmanObjectStore.Wal.validateZstdCompression(
+		path.Child("barmanObjectStore", "wal"))...)
+	result = append(result, mirror.BarmanObjectStore.Wal.validateStagingNotSupported(
+		path.Child("barmanObjectStore", "wal"))...)
+	result = append(result, mirror.BarmanObjectStore.validateCredentialsVolumeNotSupported(
+		path.Child("barmanObjectStore"))...)
+	result = append(result, mirror.BarmanObjectStore.validateTags(path.Child("barmanObjectStore"))...)
+
+	if r.Spec.Backup.BarmanObjectStore != nil &&
+		mirror.BarmanObjectStore.DestinationPath == r.Spec.Backup.BarmanObjectStore.DestinationPath &&
+		mirror.BarmanObjectStore.ServerName == r.Spec.Backup.BarmanObjectStore.ServerName {
+		result = append(result, field.Invalid(
+			path.Child("barmanObjectStore", "destinationPath"),
+			mirror.BarmanObjectStore.DestinationPath,
+			"The WAL archive can't be mirrored to the object store where it's stored"))
+	}
+
+	return result
+}
+
+// validateWalArchiveBackend checks the tool archiving the WAL files has
+// the configuration it requires
+func (r *Cluster) validateWalArchiveBackend() field.ErrorList {
+	if r.Spec.Backup == nil {
+		return nil
+	}
+
+	var result field.ErrorList
+	backup := r.Spec.Backup
+	path := field.NewPath("spec", "backup")
+	backend := backup.GetWalArchiveBackend()
+
+	if backend != WalArchiveBackendCustom && backup.CustomWalArchive != nil {
+		result = append(result, field.Invalid(
+			path.Child("customWalArchive"),
+			backup.CustomWalArchive,
+			"The custom WAL archive command is only used by the custom walArchiveBackend"))
+	}
+	if backend != WalArchiveBackendWalG && backup.WalG != nil {
+		result = append(result, field.Invalid(
+			path.Child("walG"),
+			backup.WalG,
+			"The wal-g configuration is only used by the walg walArchiveBackend"))
+	}
+
+	switch backend {
+	case WalArchiveBackendBarman:
+		return result
+
+	case WalArchiveBackendCustom:
+		result = append(result, backup.validateCustomWalArchive(path)...)
+
+	case WalArchiveBackendWalG:
+		if backup.WalG == nil {
+			result = append(result, field.Required(
+				path.Child("walG"),
+				"The walg walArchiveBackend requires the wal-g configuration"))
+		} else {
+			result = append(result, backup.WalG.validate(path.Child("walG"))...)
+		}
+	}
+
+	if backup.WalMirror != nil {
+		result = append(result, field.Invalid(
+			path.Child("mirror"),
+			backup.WalMirror,
+			"The mirroring of the WAL archive requires the barman walArchiveBackend"))
+	}
+
+	if backup.BarmanObjectStore != nil && backup.BarmanObjectStore.Wal.IsStagingEnabled() {
+		result = append(result, field.Invalid(
+			path.Child("barmanObjectStore", "wal", "staging"),
+			backup.BarmanObjectStore.Wal.Staging,
+			"The staging of the WAL files requires the barman walArchiveBackend"))
+	}
+
+	return result
+}
+
+// validateCustomWalArchive checks the custom WAL archive command is
+// defined, and its environment only comes from values or secrets
+func (backupConfiguration *BackupConfiguration) validateCustomWalArchive(path *field.Path) field.ErrorList {
+	var result field.ErrorList
+
+	if backupConfiguration.CustomWalArchive == nil {
+		return append(result, field.Required(
+			path.Child("customWalArchive"),
+			"The custom walArchiveBackend requires the command archiving the WAL files"))
+	}
+
+	if strings.TrimSpace(backupConfiguration.CustomWalArchive.Command) == "" {
+		result = append(result, field.Required(
+			path.Child("customWalArchive", "command"),
+			"The command archiving the WAL files can't be empty"))
+	}
+
+	for idx, env := range backupConfiguration.CustomWalArchive.Env {
+		envPath := path.Child("customWalArchive", "env").Index(idx)
+		if env.Name == "" {
+			result = append(result, field.Required(envPath.Child("name"), "The name can't be empty"))
+		}
+		if env.ValueFrom != nil && (env.ValueFrom.SecretKeyRef == nil ||
+			env.ValueFrom.FieldRef != nil ||
+			env.ValueFrom.ResourceFieldRef != nil ||
+			env.ValueFrom.ConfigMapKeyRef != nil) {
+			result = append(result, field.Invalid(
+				envPath.Child("valueFrom"),
+				env.ValueFrom,
+				"Only the secretKeyRef source is supported"))
+		}
+	}
+
+	return result
+}
+
+// validate checks the wal-g configuration has one set of credentials,
+// matching the cloud provider of the destination path
+func (walG *WalGConfiguration) validate(path *field.Path) field.ErrorList {
+	var result field.ErrorList
+
+	credentials := walG.BarmanCredentials
+	credentialsCount := 0
+	expectedScheme := ""
+	if credentials.Azure != nil {
+		credentialsCount++
+		expectedScheme = "azure://"
+		result = append(result, credentials.Azure.validateAzureCredentials(path.Child("azureCredentials"))...)
+		if credentials.Azure.ConnectionString != nil {
+			result = append(result, field.Invalid(
+				path.Child("azureCredentials", "connectionString"),
+				credentials.Azure.ConnectionString,
+				"The connection string is not supported by wal-g"))
+		}
+	}
+	if credentials.AWS != nil {
+		credentialsCount++
+		expectedScheme = "s3://"
+		result = append(result, credentials.AWS.validateAwsCredentials(path.Child("s3Credentials"))...)
+	}
+	if credentials.Google != nil {
+		credentialsCount++
+		expectedScheme = "gs://"
+		result = append(result, credentials.Google.validateGCSCredentials(path.Child("googleCredentials"))...)
+	}
+	if credentialsCount != 1 {
+		return append(result, field.Invalid(
+			path,
+			walG,
+			"One and only one of azureCredentials, s3Credentials and googleCredentials are required"))
+	}
+
+	if !strings.HasPrefix(walG.DestinationPath, expectedScheme) {
+		result = append(result, field.Invalid(
+			path.Child("destinationPath"),
+			walG.DestinationPath,
+			fmt.Sprintf("The destination path must start with %s to match the credentials", expectedScheme)))
+	}
+
+	if walG.EndpointURL != "" && credentials.AWS == nil {
+		result = append(result, field.Invalid(
+			path.Child("endpointURL"),
+			walG.EndpointURL,
+			"The endpoint URL is only supported with the S3 credentials"))
+	}
+
+	return result
+}
+
+// validateBackupConfiguration validates the backup configuration
+func (r *Cluster) validateBackupConfiguration() field.ErrorList {
+	allErrors := field.ErrorList{}
+
+	if r.Spec.Backup == nil || r.Spec.Backup.BarmanObjectStore == nil {
+		return nil
+	}
+
+	credentialsCount := 0
+	if r.Spec.Backup.BarmanObjectStore.BarmanCredentials.Azure != nil {
+		credentialsCount++
+		allErrors = r.Spec.Backup.BarmanObjectStore.BarmanCredentials.Azure.validateAzureCredentials(
+			field.NewPath("spec", "backupConfiguration", "azureCredentials"))
+	}
+	if r.Spec.Backup.BarmanObjectStore.BarmanCredentials.AWS != nil {
+		credentialsCount++
+		allErrors = r.Spec.Backup.BarmanObjectStore.BarmanCredentials.AWS.validateAwsCredentials(
+			field.NewPath("spec", "backupConfiguration", "s3Credentials"))
+	}
+	if r.Spec.Backup.BarmanObjectStore.BarmanCredentials.Google != nil {
+		credentialsCount++
+		allErrors = r.Spec.Backup.BarmanObjectStore.BarmanCredentials.Google.validateGCSCredentials(
+			field.NewPath("spec", "backupConfiguration", "googleCredentials"))
+	}
+	if r.Spec.Backup.BarmanObjectStore.CredentialsVolume != nil {
+		// The credentials are read from the volume, and the section
+		// of the cloud provider must not reference any secret
+		allErrors = r.Spec.Backup.BarmanObjectStore.BarmanCredentials.validateCredentialsVolume(
+			field.NewPath("spec", "backup", "barmanObjectStore"))
+	}
+	if credentialsCount == 0 {
+		allErrors = append(allErrors, field.Invalid(
+			field.NewPath("spec", "backupConfiguration"),
+			r.Spec.Backup.BarmanObjectStore,
+			"missing credentials. "+
 				"One and only one of azureCredentials, s3Credentials and googleCredentials are required",
 		))
 	}
@@ -1514,6 +2868,17 @@ func (r *Cluster) validateBackupConfiguration() field.ErrorList {
 		))
 	}
 
+	allErrors = append(allErrors, r.Spec.Backup.BarmanObjectStore.Wal.validateZstdCompression(
+		field.NewPath("spec", "backup", "barmanObjectStore", "wal"))...)
+	allErrors = append(allErrors, r.Spec.Backup.BarmanObjectStore.Data.validateZstdCompression(
+		field.NewPath("spec", "backup", "barmanObjectStore", "data"))...)
+	allErrors = append(allErrors, r.Spec.Backup.BarmanObjectStore.Wal.validateRestoreMaxParallel(
+		field.NewPath("spec", "backup", "barmanObjectStore", "wal"))...)
+	allErrors = append(allErrors, r.validateWalStaging(
+		field.NewPath("spec", "backup", "barmanObjectStore", "wal"))...)
+	allErrors = append(allErrors, r.Spec.Backup.BarmanObjectStore.validateTags(
+		field.NewPath("spec", "backup", "barmanObjectStore"))...)
+
 	if r.Spec.Backup.RetentionPolicy != "" {
 		_, err := utils.ParsePolicy(r.Spec.Backup.RetentionPolicy)
 		if err != nil {
@@ -1528,6 +2893,211 @@ func (r *Cluster) validateBackupConfiguration() field.ErrorList {
 	return allErrors
 }
 
+// validateTags checks the tags attached to the objects written in the
+// object store, which are passed to barman-cloud as `key,value` pairs
+func (objectStore *BarmanObjectStoreConfiguration) validateTags(path *field.Path) field.ErrorList {
+	var result field.ErrorList
+	result = append(result, validateBarmanTags(path.Child("tags"), objectStore.Tags)...)
+	result = append(result, validateBarmanTags(path.Child("historyTags"), objectStore.HistoryTags)...)
+	return result
+}
+
+// validateBarmanTags checks a set of tags against the limits shared by
+// the supported object stores. Commas are rejected as they separate the
+// key from the value in the barman-cloud options
+func validateBarmanTags(path *field.Path, tags map[string]string) field.ErrorList {
+	var result field.ErrorList
+	if len(tags) > maxBarmanTags {
+		result = append(result, field.TooMany(path, len(tags), maxBarmanTags))
+	}
+
+	keys := make([]string, 0, len(tags))
+	for key := range tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value := tags[key]
+		switch {
+		case key == "":
+			result = append(result, field.Invalid(path, key, "the key of a tag can't be empty"))
+		case len(key) > maxBarmanTagKeyLength:
+			result = append(result, field.TooLong(path, key, maxBarmanTagKeyLength))
+		case strings.Contains(key, ","):
+			result = append(result, field.Invalid(path, key, "the key of a tag can't contain commas"))
+		}
+
+		switch {
+		case len(value) > maxBarmanTagValueLength:
+			result = append(result, field.TooLong(path.Key(key), value, maxBarmanTagValueLength))
+		case strings.Contains(value, ","):
+			result = append(result, field.Invalid(path.Key(key), value, "the value of a tag can't contain commas"))
+		}
+	}
+
+	return result
+}
+
+// validateRestoreMaxParallel checks the number of WAL files restored in
+// parallel, limiting the space used by the prefetched ones
+func (wal *WalBackupConfiguration) validateRestoreMaxParallel(path *field.Path) field.ErrorList {
+	if wal == nil || wal.RestoreMaxParallel == 0 {
+		return nil
+	}
+
+	if wal.RestoreMaxParallel < 1 || wal.RestoreMaxParallel > MaxWalRestoreParallel {
+		return field.ErrorList{
+			field.Invalid(
+				path.Child("restoreMaxParallel"),
+				wal.RestoreMaxParallel,
+				fmt.Sprintf("the number of WAL files restored in parallel must be between 1 and %d",
+					MaxWalRestoreParallel)),
+		}
+	}
+
+	return nil
+}
+
+// validateWalStaging checks the archiving of the WAL files through
+// a staging directory, which relies on the archive_command
+func (r *Cluster) validateWalStaging(path *field.Path) field.ErrorList {
+	wal := r.Spec.Backup.BarmanObjectStore.Wal
+	if !wal.IsStagingEnabled() {
+		return nil
+	}
+
+	var result field.ErrorList
+	if wal.Staging.MaxPendingFiles < 0 {
+		result = append(result, field.Invalid(
+			path.Child("staging", "maxPendingFiles"),
+			wal.Staging.MaxPendingFiles,
+			"the maximum number of staged WAL files must be a positive integer"))
+	}
+
+	if r.Spec.PostgresConfiguration.ArchiveLibrary != "" {
+		result = append(result, field.Invalid(
+			path.Child("staging"),
+			wal.Staging,
+			"the WAL staging directory can't be used together with an archive library"))
+	}
+
+	return result
+}
+
+// validateStagingNotSupported rejects the WAL staging directory in the object
+// stores which are not archived through the archive_command
+func (wal *WalBackupConfiguration) validateStagingNotSupported(path *field.Path) field.ErrorList {
+	if !wal.IsStagingEnabled() {
+		return nil
+	}
+
+	return field.ErrorList{
+		field.Forbidden(
+			path.Child("staging"),
+			"the WAL staging directory is supported only by the backup object store"),
+	}
+}
+
+// validateCredentialsVolume checks that the section of the cloud provider,
+// when the credentials are read from the credentials volume, only selects
+// the provider without referencing any secret
+func (credentials BarmanCredentials) validateCredentialsVolume(path *field.Path) field.ErrorList {
+	var result field.ErrorList
+
+	if azure := credentials.Azure; azure != nil && (azure.InheritFromAzureAD ||
+		azure.ConnectionString != nil || azure.StorageAccount != nil ||
+		azure.StorageKey != nil || azure.StorageSasToken != nil) {
+		result = append(result, field.Invalid(
+			path.Child("azureCredentials"),
+			azure,
+			"must not specify any credential when the credentialsVolume is used"))
+	}
+
+	if s3 := credentials.AWS; s3 != nil && (s3.InheritFromIAMRole ||
+		s3.AccessKeyIDReference != nil || s3.SecretAccessKeyReference != nil ||
+		s3.RegionReference != nil || s3.SessionToken != nil) {
+		result = append(result, field.Invalid(
+			path.Child("s3Credentials"),
+			s3,
+			"must not specify any credential when the credentialsVolume is used"))
+	}
+
+	if gcs := credentials.Google; gcs != nil && (gcs.GKEEnvironment || gcs.ApplicationCredentials != nil) {
+		result = append(result, field.Invalid(
+			path.Child("googleCredentials"),
+			gcs,
+			"must not specify any credential when the credentialsVolume is used"))
+	}
+
+	return result
+}
+
+// validateCredentialsVolumeNotSupported rejects the credentials volume in
+// the object stores other than the backup one, as it's not mounted
+func (objectStore *BarmanObjectStoreConfiguration) validateCredentialsVolumeNotSupported(
+	path *field.Path,
+) field.ErrorList {
+	if objectStore.CredentialsVolume == nil {
+		return nil
+	}
+
+	return field.ErrorList{
+		field.Forbidden(
+			path.Child("credentialsVolume"),
+			"the credentials volume is supported only by the backup object store"),
+	}
+}
+
+// validateZstdCompression validates the tuning of the zstd compression
+// of the WAL files
+func (wal *WalBackupConfiguration) validateZstdCompression(path *field.Path) field.ErrorList {
+	if wal == nil {
+		return nil
+	}
+
+	return validateZstdConfiguration(path, wal.Compression, wal.Zstd)
+}
+
+// validateZstdCompression validates the tuning of the zstd compression
+// of the base backups, independently of the WAL files one
+func (data *DataBackupConfiguration) validateZstdCompression(path *field.Path) field.ErrorList {
+	if data == nil {
+		return nil
+	}
+
+	return validateZstdConfiguration(path, data.Compression, data.Zstd)
+}
+
+// validateZstdConfiguration validates the tuning of the zstd compression,
+// which is allowed only when the zstd compression is being used
+func validateZstdConfiguration(
+	path *field.Path,
+	compression CompressionType,
+	zstd *ZstdCompressionConfiguration,
+) field.ErrorList {
+	if zstd == nil {
+		return nil
+	}
+
+	var result field.ErrorList
+	if compression != CompressionTypeZstd {
+		result = append(result, field.Invalid(
+			path.Child("zstd"),
+			zstd,
+			"zstd options can be set only when using the zstd compression"))
+	}
+
+	if level := zstd.Level; level != nil && (*level < ZstdMinLevel || *level > ZstdMaxLevel) {
+		result = append(result, field.Invalid(
+			path.Child("zstd", "level"),
+			*level,
+			fmt.Sprintf("the zstd compression level must be between %d and %d", ZstdMinLevel, ZstdMaxLevel)))
+	}
+
+	return result
+}
+
 func (r *Cluster) validateReplicationSlots() field.ErrorList {
 	replicationSlots := r.Spec.ReplicationSlots
 	if replicationSlots == nil ||
@@ -1555,6 +3125,337 @@ func (r *Cluster) validateReplicationSlots() field.ErrorList {
 	}
 }
 
+// validateMaxSlotWalKeepSize checks that the cap for the WAL files retained
+// by replication slots is supported and not set twice
+func (r *Cluster) validateMaxSlotWalKeepSize() field.ErrorList {
+	maxSlotWalKeepSize := r.Spec.ReplicationSlots.GetMaxSlotWalKeepSize()
+	if maxSlotWalKeepSize == "" {
+		return nil
+	}
+
+	fieldPath := field.NewPath("spec", "replicationSlots", "maxSlotWalKeepSize")
+
+	if _, ok := r.Spec.PostgresConfiguration.Parameters["max_slot_wal_keep_size"]; ok {
+		return field.ErrorList{
+			field.Invalid(
+				fieldPath,
+				maxSlotWalKeepSize,
+				"Cannot be set together with the max_slot_wal_keep_size PostgreSQL parameter"),
+		}
+	}
+
+	psqlVersion, err := r.GetPostgresqlVersion()
+	if err != nil {
+		// The validation error will be already raised by the
+		// validateImageName function
+		return nil
+	}
+
+	if psqlVersion >= 130000 {
+		return nil
+	}
+
+	return field.ErrorList{
+		field.Invalid(
+			fieldPath,
+			maxSlotWalKeepSize,
+			"Cannot limit the WAL retained by replication slots. It requires PostgreSQL 13 or above"),
+	}
+}
+
+// validateSynchronousCommit checks that the synchronous_commit level
+// is set only once and is consistent with the synchronous replication
+func (r *Cluster) validateSynchronousCommit() field.ErrorList {
+	level := r.Spec.SynchronousCommit
+	if level == "" {
+		return nil
+	}
+
+	fieldPath := field.NewPath("spec", "synchronousCommit")
+
+	if _, ok := r.Spec.PostgresConfiguration.Parameters["synchronous_commit"]; ok {
+		return field.ErrorList{
+			field.Invalid(
+				fieldPath,
+				level,
+				"Cannot be set together with the synchronous_commit PostgreSQL parameter"),
+		}
+	}
+
+	if level.RequiresSynchronousStandby() && r.Spec.MaxSyncReplicas == 0 {
+		return field.ErrorList{
+			field.Invalid(
+				fieldPath,
+				level,
+				"Requires synchronous replication, as without synchronous standbys it behaves like 'local'"),
+		}
+	}
+
+	return nil
+}
+
+// validateReplicasUnavailablePolicy checks that the synchronous replication
+// is only enforced when a minimum number of synchronous standbys is required
+func (r *Cluster) validateReplicasUnavailablePolicy() field.ErrorList {
+	policy := r.Spec.ReplicasUnavailablePolicy
+	if policy != ReplicasUnavailablePolicyEnforceSync || r.Spec.MinSyncReplicas > 0 {
+		return nil
+	}
+
+	return field.ErrorList{
+		field.Invalid(
+			field.NewPath("spec", "replicasUnavailablePolicy"),
+			policy,
+			"Requires minSyncReplicas to be greater than zero, as there are no synchronous standbys to wait for"),
+	}
+}
+
+// validateReadOnlyStandbys ensures the read-only default of the standbys
+// cannot be applied to the primary through the PostgreSQL parameters
+func (r *Cluster) validateReadOnlyStandbys() field.ErrorList {
+	if !r.Spec.ReadOnlyStandbys {
+		return nil
+	}
+
+	if _, ok := r.Spec.PostgresConfiguration.Parameters["default_transaction_read_only"]; ok {
+		return field.ErrorList{
+			field.Invalid(
+				field.NewPath("spec", "readOnlyStandbys"),
+				r.Spec.ReadOnlyStandbys,
+				"Cannot be set together with the default_transaction_read_only PostgreSQL parameter"),
+		}
+	}
+
+	return nil
+}
+
+// validateReadOnly ensures the read-only mode of the cluster is not
+// mixed with the default_transaction_read_only PostgreSQL parameter,
+// which would be overridden when the mode is disabled
+func (r *Cluster) validateReadOnly() field.ErrorList {
+	if !r.IsReadOnly() {
+		return nil
+	}
+
+	if _, ok := r.Spec.PostgresConfiguration.Parameters["default_transaction_read_only"]; ok {
+		return field.ErrorList{
+			field.Invalid(
+				field.NewPath("spec", "readOnly", "enabled"),
+				r.Spec.ReadOnly.Enabled,
+				"Cannot be set together with the default_transaction_read_only PostgreSQL parameter"),
+		}
+	}
+
+	return nil
+}
+
+// validateReplicaJoinMethod checks that an object store is available
+// when replicas are joined restoring a base backup
+func (r *Cluster) validateReplicaJoinMethod() field.ErrorList {
+	if r.GetReplicaJoinMethod() != ReplicaJoinMethodObjectStore {
+		return nil
+	}
+
+	if r.Spec.Backup == nil || r.Spec.Backup.BarmanObjectStore == nil {
+		return field.ErrorList{
+			field.Invalid(
+				field.NewPath("spec", "replicaJoinMethod"),
+				r.Spec.ReplicaJoinMethod,
+				"joining replicas from the object store requires the backup "+
+					"section to contain barmanObjectStore"),
+		}
+	}
+
+	return nil
+}
+
+// validateReplicationConnection checks that the application_name of
+// every standby fits in the 63 characters PostgreSQL keeps, as longer
+// names would be truncated and not match synchronous_standby_names
+func (r *Cluster) validateReplicationConnection() field.ErrorList {
+	const (
+		// The maximum length of an identifier in PostgreSQL
+		maxApplicationNameLength = 63
+
+		// The room left for the dash and the serial of the instances
+		instanceSuffixLength = 7
+	)
+
+	replicationConnection := r.Spec.ReplicationConnection
+	if replicationConnection == nil || replicationConnection.ApplicationNamePrefix == "" {
+		return nil
+	}
+
+	prefix := replicationConnection.ApplicationNamePrefix
+	if len(prefix)+len(r.Name)+instanceSuffixLength > maxApplicationNameLength {
+		return field.ErrorList{
+			field.Invalid(
+				field.NewPath("spec", "replicationConnection", "applicationNamePrefix"),
+				prefix,
+				fmt.Sprintf("the application_name of the standbys would exceed %d characters, "+
+					"please use a shorter prefix", maxApplicationNameLength)),
+		}
+	}
+
+	return nil
+}
+
+// validateDelayedReplicas checks that the delayed replicas are instances
+// of this cluster, and that at least another instance is left to be
+// promoted by an automatic failover
+func (r *Cluster) validateDelayedReplicas() field.ErrorList {
+	delayedReplicas := r.Spec.DelayedReplicas
+	if delayedReplicas == nil {
+		return nil
+	}
+
+	var result field.ErrorList
+	fieldPath := field.NewPath("spec", "delayedReplicas")
+
+	if len(delayedReplicas.Instances) >= r.Spec.Instances {
+		result = append(result, field.Invalid(
+			fieldPath.Child("instances"),
+			delayedReplicas.Instances,
+			"At least one instance must not be delayed, to be promoted in case of failover"))
+	}
+
+	instancePrefix := r.Name + "-"
+	seenInstances := make(map[string]bool, len(delayedReplicas.Instances))
+	for idx, name := range delayedReplicas.Instances {
+		serial, err := strconv.Atoi(strings.TrimPrefix(name, instancePrefix))
+		if !strings.HasPrefix(name, instancePrefix) || err != nil || serial <= 0 {
+			result = append(result, field.Invalid(
+				fieldPath.Child("instances").Index(idx),
+				name,
+				fmt.Sprintf("Not an instance of this cluster, the name must be like %s1", instancePrefix)))
+			continue
+		}
+
+		if seenInstances[name] {
+			result = append(result, field.Duplicate(
+				fieldPath.Child("instances").Index(idx),
+				name))
+		}
+		seenInstances[name] = true
+	}
+
+	psqlVersion, err := r.GetPostgresqlVersion()
+	if err != nil {
+		// The validation error will be already raised by the
+		// validateImageName function
+		return result
+	}
+
+	if psqlVersion < 120000 {
+		result = append(result, field.Invalid(
+			fieldPath,
+			delayedReplicas,
+			"Cannot configure delayed replicas. It requires PostgreSQL 12 or above"))
+	}
+
+	return result
+}
+
+// validateReadReplicas checks the number of read replicas requested
+// on top of the instances of the cluster
+func (r *Cluster) validateReadReplicas() field.ErrorList {
+	readReplicas := r.Spec.ReadReplicas
+	if readReplicas == nil || readReplicas.Instances >= 0 {
+		return nil
+	}
+
+	return field.ErrorList{
+		field.Invalid(
+			field.NewPath("spec", "readReplicas", "instances"),
+			readReplicas.Instances,
+			"The number of read replicas cannot be negative"),
+	}
+}
+
+// validateWalKeepSize checks that the WAL files kept for the standbys
+// are supported, not set twice and can fit in the WAL volume
+func (r *Cluster) validateWalKeepSize() field.ErrorList {
+	walKeepSize := r.Spec.WalKeepSize
+	if walKeepSize == nil {
+		return nil
+	}
+
+	fieldPath := field.NewPath("spec", "walKeepSize")
+
+	if walKeepSize.Sign() <= 0 {
+		return field.ErrorList{
+			field.Invalid(fieldPath, walKeepSize.String(), "Must be greater than zero"),
+		}
+	}
+
+	for _, parameter := range []string{"wal_keep_size", "wal_keep_segments"} {
+		if _, ok := r.Spec.PostgresConfiguration.Parameters[parameter]; ok {
+			return field.ErrorList{
+				field.Invalid(
+					fieldPath,
+					walKeepSize.String(),
+					fmt.Sprintf("Cannot be set together with the %s PostgreSQL parameter", parameter)),
+			}
+		}
+	}
+
+	if walVolumeSize := r.GetWalVolumeSize(); walVolumeSize != nil && walKeepSize.Cmp(*walVolumeSize) >= 0 {
+		return field.ErrorList{
+			field.Invalid(
+				fieldPath,
+				walKeepSize.String(),
+				fmt.Sprintf("Must be smaller than the volume storing the WAL files (%s)", walVolumeSize.String())),
+		}
+	}
+
+	psqlVersion, err := r.GetPostgresqlVersion()
+	if err != nil {
+		// The validation error will be already raised by the
+		// validateImageName function
+		return nil
+	}
+
+	if psqlVersion >= 130000 {
+		return nil
+	}
+
+	return field.ErrorList{
+		field.Invalid(
+			fieldPath,
+			walKeepSize.String(),
+			"Cannot set the size of the WAL files kept for the standbys. It requires PostgreSQL 13 or above"),
+	}
+}
+
+// validateCredentialsNamespace checks that the secrets are copied from
+// a namespace allowed by the operator configuration, preventing the
+// clusters from reading the secrets of any namespace
+func (r *Cluster) validateCredentialsNamespace() field.ErrorList {
+	namespace := r.Spec.CredentialsNamespace
+	if namespace == "" {
+		return nil
+	}
+
+	fieldPath := field.NewPath("spec", "credentialsNamespace")
+
+	if namespace == r.Namespace {
+		return field.ErrorList{
+			field.Invalid(fieldPath, namespace, "Must be different from the namespace of the cluster"),
+		}
+	}
+
+	if !configuration.Current.IsCredentialsNamespaceAllowed(namespace) {
+		return field.ErrorList{
+			field.Invalid(
+				fieldPath,
+				namespace,
+				"Not allowed by the CREDENTIALS_NAMESPACES option of the operator configuration"),
+		}
+	}
+
+	return nil
+}
+
 func (r *Cluster) validateReplicationSlotsChange(old *Cluster) field.ErrorList {
 	newReplicationSlots := r.Spec.ReplicationSlots
 	oldReplicationSlots := old.Spec.ReplicationSlots
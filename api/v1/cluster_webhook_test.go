@@ -17,14 +17,17 @@ limitations under the License.
 package v1
 
 import (
+	"strconv"
 	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/pointer"
 
 	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/versions"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -226,6 +229,30 @@ var _ = Describe("certificates options validation", func() {
 		result := cluster.validateCerts()
 		Expect(len(result)).To(Equal(1))
 	})
+	It("doesn't complain if you specify a server issuer", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Certificates: &CertificatesConfiguration{
+					ServerIssuerRef: &CertificateIssuerReference{Name: "ca-issuer"},
+				},
+			},
+		}
+		result := cluster.validateCerts()
+		Expect(result).To(BeEmpty())
+	})
+	It("does complain if you specify a server issuer together with the server secrets", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Certificates: &CertificatesConfiguration{
+					ServerCASecret:  "test-server-ca",
+					ServerTLSSecret: "test-server-tls",
+					ServerIssuerRef: &CertificateIssuerReference{Name: "ca-issuer"},
+				},
+			},
+		}
+		result := cluster.validateCerts()
+		Expect(len(result)).To(Equal(1))
+	})
 })
 
 var _ = Describe("initdb options validation", func() {
@@ -576,6 +603,85 @@ var _ = Describe("Defaulting webhook", func() {
 		Expect(cluster.Spec.ImageName).To(Equal("test:13"))
 	})
 
+	It("should use the pull policy of the operator configuration if not set", func() {
+		cluster := Cluster{}
+		cluster.Default()
+		Expect(cluster.Spec.ImagePullPolicy).To(BeEquivalentTo(configuration.Current.ImagePullPolicy))
+
+		cluster = Cluster{
+			Spec: ClusterSpec{
+				ImagePullPolicy: corev1.PullNever,
+			},
+		}
+		cluster.Default()
+		Expect(cluster.Spec.ImagePullPolicy).To(Equal(corev1.PullNever))
+	})
+
+	It("should fill the storage size if isn't already set", func() {
+		cluster := Cluster{}
+		cluster.Default()
+		Expect(cluster.Spec.StorageConfiguration.Size).To(Equal(configuration.Current.DefaultStorageSize))
+		Expect(cluster.validateStorageSize()).To(BeEmpty())
+	})
+
+	It("shouldn't set the storage size if already present in the PVC template", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				StorageConfiguration: StorageConfiguration{
+					PersistentVolumeClaimTemplate: &corev1.PersistentVolumeClaimSpec{
+						Resources: corev1.ResourceRequirements{
+							Requests: corev1.ResourceList{
+								corev1.ResourceStorage: resource.MustParse("5Gi"),
+							},
+						},
+					},
+				},
+			},
+		}
+		cluster.Default()
+		Expect(cluster.Spec.StorageConfiguration.Size).To(BeEmpty())
+	})
+
+	It("should fill the delays used by the probes", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				MaxStopDelay: 60,
+			},
+		}
+		cluster.Default()
+		Expect(cluster.Spec.MaxStartDelay).To(BeEquivalentTo(30))
+		Expect(cluster.Spec.MaxStopDelay).To(BeEquivalentTo(60))
+	})
+
+	It("should record the fields it filled", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				ImageName: "test:13",
+				LogLevel:  "debug",
+			},
+		}
+		cluster.Default()
+		Expect(cluster.Annotations).To(HaveKeyWithValue(
+			utils.DefaultedFieldsAnnotationName,
+			"spec.bootstrap,spec.startDelay,spec.stopDelay,spec.storage.size"))
+	})
+
+	It("should keep the fields recorded by a previous defaulting", func() {
+		cluster := Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					utils.DefaultedFieldsAnnotationName: "spec.imageName",
+				},
+			},
+			Spec: ClusterSpec{
+				ImageName: "test:13",
+			},
+		}
+		cluster.Default()
+		Expect(cluster.Annotations[utils.DefaultedFieldsAnnotationName]).To(ContainSubstring("spec.imageName"))
+		Expect(cluster.Annotations[utils.DefaultedFieldsAnnotationName]).To(ContainSubstring("spec.storage.size"))
+	})
+
 	It("should setup the application database name", func() {
 		cluster := Cluster{}
 		cluster.Default()
@@ -791,6 +897,103 @@ var _ = Describe("validate image name change", func() {
 		}
 		Expect(len(clusterNew.validateImageChange("postgres:12.1"))).To(Equal(0))
 	})
+
+	It("doesn't complain when upgrading to a new major version", func() {
+		clusterNew := Cluster{
+			Spec: ClusterSpec{
+				ImageName:          "postgres:15.1",
+				EnableMajorUpgrade: true,
+			},
+		}
+		Expect(clusterNew.validateImageChange("postgres:14.6")).To(BeEmpty())
+	})
+
+	It("complains when upgrading to a new major version without enabling it", func() {
+		clusterNew := Cluster{
+			Spec: ClusterSpec{
+				ImageName: "postgres:15.1",
+			},
+		}
+		result := clusterNew.validateImageChange("postgres:14.6")
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Detail).To(ContainSubstring("enableMajorUpgrade"))
+	})
+
+	It("complains when upgrading the major version of a replica cluster", func() {
+		clusterNew := Cluster{
+			Spec: ClusterSpec{
+				ImageName:          "postgres:15.1",
+				EnableMajorUpgrade: true,
+				ReplicaCluster: &ReplicaClusterConfiguration{
+					Enabled: true,
+					Source:  "origin",
+				},
+			},
+		}
+		Expect(clusterNew.validateImageChange("postgres:14.6")).To(HaveLen(1))
+	})
+
+	It("doesn't complain when reverting a failed major version upgrade", func() {
+		clusterNew := Cluster{
+			Spec: ClusterSpec{
+				ImageName: "postgres:14.6",
+			},
+			Status: ClusterStatus{
+				PGDataImageInfo: &ImageInfo{
+					Image:        "postgres:14.6",
+					MajorVersion: 14,
+				},
+			},
+		}
+		Expect(clusterNew.validateImageChange("postgres:15.1")).To(BeEmpty())
+	})
+
+	It("complains when reverting a major version upgrade in progress", func() {
+		clusterNew := Cluster{
+			Spec: ClusterSpec{
+				ImageName: "postgres:14.6",
+			},
+			Status: ClusterStatus{
+				Phase: PhaseMajorUpgrade,
+				PGDataImageInfo: &ImageInfo{
+					Image:        "postgres:14.6",
+					MajorVersion: 14,
+				},
+			},
+		}
+		Expect(clusterNew.validateImageChange("postgres:15.1")).To(HaveLen(1))
+	})
+
+	It("complains when downgrading the major version", func() {
+		clusterNew := Cluster{
+			Spec: ClusterSpec{
+				ImageName: "postgres:14.6",
+			},
+			Status: ClusterStatus{
+				PGDataImageInfo: &ImageInfo{
+					Image:        "postgres:15.1",
+					MajorVersion: 15,
+				},
+			},
+		}
+		Expect(clusterNew.validateImageChange("postgres:15.1")).To(HaveLen(1))
+	})
+
+	It("doesn't complain when restoring the image after a refused downgrade", func() {
+		clusterNew := Cluster{
+			Spec: ClusterSpec{
+				ImageName: "postgres:15.1",
+			},
+			Status: ClusterStatus{
+				Phase: PhaseIncompatibleImage,
+				PGDataImageInfo: &ImageInfo{
+					Image:        "postgres:15.1",
+					MajorVersion: 15,
+				},
+			},
+		}
+		Expect(clusterNew.validateImageChange("postgres:14.6")).To(BeEmpty())
+	})
 })
 
 var _ = Describe("recovery target", func() {
@@ -1170,488 +1373,695 @@ var _ = Describe("Number of synchronous replicas", func() {
 	})
 })
 
-var _ = Describe("storage configuration validation", func() {
-	It("complains if the size is being reduced", func() {
-		clusterOld := Cluster{
+var _ = Describe("Number of instances with synchronous replication", func() {
+	BeforeEach(func() {
+		configuration.Current.EnforceQuorumInstances = true
+		DeferCleanup(func() {
+			configuration.Current.EnforceQuorumInstances = false
+		})
+	})
+
+	It("complains about an even number of instances", func() {
+		cluster := Cluster{
 			Spec: ClusterSpec{
-				StorageConfiguration: StorageConfiguration{
-					Size: "1G",
-				},
+				Instances:       4,
+				MaxSyncReplicas: 2,
 			},
 		}
+		Expect(cluster.validateQuorumInstances()).To(HaveLen(1))
+	})
 
-		clusterNew := Cluster{
+	It("accepts an odd number of instances", func() {
+		cluster := Cluster{
 			Spec: ClusterSpec{
-				StorageConfiguration: StorageConfiguration{
-					Size: "512M",
-				},
+				Instances:       3,
+				MaxSyncReplicas: 1,
 			},
 		}
-
-		Expect(clusterNew.validateStorageChange(&clusterOld)).ToNot(BeEmpty())
+		Expect(cluster.validateQuorumInstances()).To(BeEmpty())
 	})
 
-	It("does not complain if nothing has been changed", func() {
-		one := "one"
-		clusterOld := Cluster{
+	It("doesn't check clusters without synchronous replication", func() {
+		cluster := Cluster{
 			Spec: ClusterSpec{
-				StorageConfiguration: StorageConfiguration{
-					Size:         "1G",
-					StorageClass: &one,
-				},
+				Instances: 2,
 			},
 		}
-
-		clusterNew := clusterOld.DeepCopy()
-
-		Expect(clusterNew.validateStorageChange(&clusterOld)).To(BeEmpty())
+		Expect(cluster.validateQuorumInstances()).To(BeEmpty())
 	})
 
-	It("works fine is the size is being enlarged", func() {
-		clusterOld := Cluster{
-			Spec: ClusterSpec{
-				StorageConfiguration: StorageConfiguration{
-					Size: "8G",
+	It("can be suppressed per cluster", func() {
+		cluster := Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					"cnpg.io/skipQuorumInstancesCheck": "enabled",
 				},
 			},
+			Spec: ClusterSpec{
+				Instances:       4,
+				MaxSyncReplicas: 2,
+			},
 		}
+		Expect(cluster.validateQuorumInstances()).To(BeEmpty())
+	})
 
-		clusterNew := Cluster{
+	It("is only advisory unless enforced by the operator", func() {
+		configuration.Current.EnforceQuorumInstances = false
+		cluster := Cluster{
 			Spec: ClusterSpec{
-				StorageConfiguration: StorageConfiguration{
-					Size: "10G",
-				},
+				Instances:       4,
+				MaxSyncReplicas: 2,
 			},
 		}
-
-		Expect(clusterNew.validateStorageChange(&clusterOld)).To(BeEmpty())
+		Expect(cluster.validateQuorumInstances()).To(BeEmpty())
 	})
 })
 
-var _ = Describe("Cluster name validation", func() {
-	It("should be a valid DNS label", func() {
+var _ = Describe("read-only standbys validation", func() {
+	It("accepts the read-only default of the standbys", func() {
 		cluster := Cluster{
-			ObjectMeta: metav1.ObjectMeta{
-				Name: "test.one",
+			Spec: ClusterSpec{
+				ReadOnlyStandbys: true,
 			},
 		}
-		Expect(cluster.validateName()).ToNot(BeEmpty())
+		Expect(cluster.validateReadOnlyStandbys()).To(BeEmpty())
 	})
 
-	It("should not be too long", func() {
+	It("complains if the read-only default is also set as a parameter", func() {
 		cluster := Cluster{
-			ObjectMeta: metav1.ObjectMeta{
-				Name: "abcdefghi" +
-					"abcdefghi" +
-					"abcdefghi" +
-					"abcdefghi" +
-					"abcdefghi" +
-					"abcdefghi" +
-					"abcdefghi" +
-					"abcdefghi" +
-					"abcdefghi",
+			Spec: ClusterSpec{
+				ReadOnlyStandbys: true,
+				PostgresConfiguration: PostgresConfiguration{
+					Parameters: map[string]string{
+						"default_transaction_read_only": "on",
+					},
+				},
 			},
 		}
-		Expect(cluster.validateName()).ToNot(BeEmpty())
+		Expect(cluster.validateReadOnlyStandbys()).To(HaveLen(1))
 	})
+})
 
-	It("should not raise errors when the name is ok", func() {
+var _ = Describe("read-only cluster validation", func() {
+	It("accepts a read-only cluster", func() {
 		cluster := Cluster{
-			ObjectMeta: metav1.ObjectMeta{
-				Name: "abcdefghi" +
-					"abcdefghi" +
-					"abcdefghi" +
-					"abcdefghi",
+			Spec: ClusterSpec{
+				ReadOnly: &ReadOnlyConfiguration{
+					Enabled: true,
+				},
 			},
 		}
-		Expect(cluster.validateName()).To(BeEmpty())
+		Expect(cluster.validateReadOnly()).To(BeEmpty())
 	})
 
-	It("should return errors when the name is not DNS-1035 compliant", func() {
+	It("complains if the read-only default is also set as a parameter", func() {
 		cluster := Cluster{
-			ObjectMeta: metav1.ObjectMeta{
-				Name: "4b96d026-a956-47eb-bae8-a99b840805c3",
+			Spec: ClusterSpec{
+				ReadOnly: &ReadOnlyConfiguration{
+					Enabled: true,
+				},
+				PostgresConfiguration: PostgresConfiguration{
+					Parameters: map[string]string{
+						"default_transaction_read_only": "on",
+					},
+				},
 			},
 		}
-		Expect(cluster.validateName()).NotTo(BeEmpty())
+		Expect(cluster.validateReadOnly()).To(HaveLen(1))
 	})
 
-	It("should return errors when the name length is greater than 50", func() {
+	It("ignores the parameter while the read-only mode is disabled", func() {
 		cluster := Cluster{
-			ObjectMeta: metav1.ObjectMeta{
-				Name: strings.Repeat("toomuchlong", 4) + "-" + "after4times",
+			Spec: ClusterSpec{
+				ReadOnly: &ReadOnlyConfiguration{},
+				PostgresConfiguration: PostgresConfiguration{
+					Parameters: map[string]string{
+						"default_transaction_read_only": "on",
+					},
+				},
 			},
 		}
-		Expect(cluster.validateName()).NotTo(BeEmpty())
+		Expect(cluster.validateReadOnly()).To(BeEmpty())
 	})
+})
 
-	It("should return errors when having a name with dots", func() {
+var _ = Describe("final backup validation", func() {
+	It("complains if there's no object store", func() {
 		cluster := Cluster{
-			ObjectMeta: metav1.ObjectMeta{
-				Name: "wrong.name",
+			Spec: ClusterSpec{
+				Backup: &BackupConfiguration{
+					FinalBackup: &FinalBackupConfiguration{Enabled: true},
+				},
 			},
 		}
-		Expect(cluster.validateName()).NotTo(BeEmpty())
+		Expect(cluster.validateFinalBackup()).To(HaveLen(1))
 	})
-})
 
-var _ = Describe("validation of the list of external clusters", func() {
-	It("is correct when it's empty", func() {
-		cluster := Cluster{}
-		Expect(cluster.validateExternalClusters()).To(BeEmpty())
+	It("accepts a final backup with an object store", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Backup: &BackupConfiguration{
+					BarmanObjectStore: &BarmanObjectStoreConfiguration{},
+					FinalBackup:       &FinalBackupConfiguration{Enabled: true},
+				},
+			},
+		}
+		Expect(cluster.validateFinalBackup()).To(BeEmpty())
 	})
+})
 
-	It("complains when the list of clusters contains duplicates", func() {
+var _ = Describe("prewarm validation", func() {
+	It("accepts a list of relations", func() {
 		cluster := Cluster{
 			Spec: ClusterSpec{
-				ExternalClusters: []ExternalCluster{
-					{
-						Name: "one",
-						ConnectionParameters: map[string]string{
-							"dbname": "postgres",
-						},
-					},
-					{
-						Name: "one",
-						ConnectionParameters: map[string]string{
-							"dbname": "postgres",
+				PostgresConfiguration: PostgresConfiguration{
+					Prewarm: &PrewarmConfiguration{
+						Relations: []PrewarmRelation{
+							{DBName: "app", Name: "sales.orders"},
+							{DBName: "app", Name: "sales.orders_pkey"},
 						},
 					},
 				},
 			},
 		}
-		Expect(cluster.validateExternalClusters()).ToNot(BeEmpty())
+		Expect(cluster.validatePrewarm()).To(BeEmpty())
 	})
 
-	It("should not raise errors is the cluster name is unique", func() {
+	It("complains about duplicated relations and a non positive size", func() {
+		maxRelationSize := resource.MustParse("0")
 		cluster := Cluster{
 			Spec: ClusterSpec{
-				ExternalClusters: []ExternalCluster{
-					{
-						Name: "one",
-						ConnectionParameters: map[string]string{
-							"dbname": "postgres",
-						},
-					},
-					{
-						Name: "two",
-						ConnectionParameters: map[string]string{
-							"dbname": "postgres",
+				PostgresConfiguration: PostgresConfiguration{
+					Prewarm: &PrewarmConfiguration{
+						MaxRelationSize: &maxRelationSize,
+						Relations: []PrewarmRelation{
+							{DBName: "app", Name: "sales.orders"},
+							{DBName: "app", Name: "sales.orders"},
 						},
 					},
 				},
 			},
 		}
-		Expect(cluster.validateExternalClusters()).To(BeEmpty())
+		Expect(cluster.validatePrewarm()).To(HaveLen(2))
 	})
 })
 
-var _ = Describe("validation of an external cluster", func() {
-	It("ensure that one of connectionParameters and barmanObjectStore is set", func() {
-		cluster := Cluster{
+var _ = Describe("WAL mirror validation", func() {
+	newCluster := func(mirrorPath string) Cluster {
+		return Cluster{
 			Spec: ClusterSpec{
-				ExternalClusters: []ExternalCluster{
-					{},
+				Backup: &BackupConfiguration{
+					BarmanObjectStore: &BarmanObjectStoreConfiguration{
+						DestinationPath: "s3://primary/",
+						BarmanCredentials: BarmanCredentials{
+							AWS: &S3Credentials{InheritFromIAMRole: true},
+						},
+					},
+					WalMirror: &WalMirrorConfiguration{
+						BarmanObjectStore: BarmanObjectStoreConfiguration{
+							DestinationPath: mirrorPath,
+							BarmanCredentials: BarmanCredentials{
+								AWS: &S3Credentials{InheritFromIAMRole: true},
+							},
+						},
+					},
 				},
 			},
 		}
-		Expect(cluster.validateExternalClusters()).To(Not(BeEmpty()))
+	}
 
-		cluster.Spec.ExternalClusters[0].ConnectionParameters = map[string]string{
-			"dbname": "postgres",
-		}
-		cluster.Spec.ExternalClusters[0].BarmanObjectStore = nil
-		Expect(cluster.validateExternalClusters()).To(BeEmpty())
+	It("accepts a mirror to a different object store", func() {
+		cluster := newCluster("s3://secondary/")
+		Expect(cluster.validateWalMirror()).To(BeEmpty())
+	})
 
-		cluster.Spec.ExternalClusters[0].ConnectionParameters = nil
-		cluster.Spec.ExternalClusters[0].BarmanObjectStore = &BarmanObjectStoreConfiguration{}
-		Expect(cluster.validateExternalClusters()).To(BeEmpty())
+	It("complains if the mirror is the object store of the cluster", func() {
+		cluster := newCluster("s3://primary/")
+		Expect(cluster.validateWalMirror()).To(HaveLen(1))
+	})
+
+	It("complains if the schedule is not valid", func() {
+		cluster := newCluster("s3://secondary/")
+		cluster.Spec.Backup.WalMirror.Schedule = "every minute"
+		Expect(cluster.validateWalMirror()).To(HaveLen(1))
+	})
+
+	It("complains if there are no credentials", func() {
+		cluster := newCluster("s3://secondary/")
+		cluster.Spec.Backup.WalMirror.BarmanObjectStore.BarmanCredentials = BarmanCredentials{}
+		Expect(cluster.validateWalMirror()).To(HaveLen(1))
+	})
+
+	It("complains if the cluster has no object store", func() {
+		cluster := newCluster("s3://secondary/")
+		cluster.Spec.Backup.BarmanObjectStore = nil
+		Expect(cluster.validateWalMirror()).To(HaveLen(1))
 	})
 })
 
-var _ = Describe("bootstrap base backup validation", func() {
-	It("complains if you specify the database name but not the owner for pg_basebackup", func() {
-		cluster := Cluster{
+var _ = Describe("WAL archive backend validation", func() {
+	newCluster := func() Cluster {
+		return Cluster{
 			Spec: ClusterSpec{
-				Bootstrap: &BootstrapConfiguration{
-					PgBaseBackup: &BootstrapPgBaseBackup{
-						Database: "app",
+				Backup: &BackupConfiguration{
+					WalArchiveBackend: WalArchiveBackendCustom,
+					CustomWalArchive: &CustomWalArchiveConfiguration{
+						Command: "wal-g wal-push %p",
+						Env: []corev1.EnvVar{
+							{Name: "WALG_S3_PREFIX", Value: "s3://bucket/cluster-example"},
+							{
+								Name: "AWS_SECRET_ACCESS_KEY",
+								ValueFrom: &corev1.EnvVarSource{
+									SecretKeyRef: &corev1.SecretKeySelector{
+										LocalObjectReference: corev1.LocalObjectReference{Name: "aws-creds"},
+										Key:                  "ACCESS_SECRET_KEY",
+									},
+								},
+							},
+						},
 					},
 				},
 			},
 		}
+	}
 
-		result := cluster.validatePgBaseBackupApplicationDatabase()
-		Expect(len(result)).To(Equal(1))
+	It("accepts the barman backend by default", func() {
+		cluster := Cluster{Spec: ClusterSpec{Backup: &BackupConfiguration{}}}
+		Expect(cluster.validateWalArchiveBackend()).To(BeEmpty())
+		Expect(cluster.Spec.Backup.GetWalArchiveBackend()).To(Equal(WalArchiveBackendBarman))
 	})
 
-	It("complains if you specify the owner but not the database name for pg_basebackup", func() {
+	It("accepts a custom command", func() {
+		cluster := newCluster()
+		Expect(cluster.validateWalArchiveBackend()).To(BeEmpty())
+		Expect(cluster.Spec.Backup.IsCustomWalArchiveEnabled()).To(BeTrue())
+	})
+
+	It("complains if the custom backend has no command", func() {
+		cluster := newCluster()
+		cluster.Spec.Backup.CustomWalArchive = nil
+		Expect(cluster.validateWalArchiveBackend()).To(HaveLen(1))
+
+		cluster = newCluster()
+		cluster.Spec.Backup.CustomWalArchive.Command = "  "
+		Expect(cluster.validateWalArchiveBackend()).To(HaveLen(1))
+	})
+
+	It("complains about a custom command with the barman backend", func() {
+		cluster := newCluster()
+		cluster.Spec.Backup.WalArchiveBackend = WalArchiveBackendBarman
+		Expect(cluster.validateWalArchiveBackend()).To(HaveLen(1))
+	})
+
+	It("complains about environment variables not coming from values or secrets", func() {
+		cluster := newCluster()
+		cluster.Spec.Backup.CustomWalArchive.Env = append(cluster.Spec.Backup.CustomWalArchive.Env,
+			corev1.EnvVar{
+				Name: "NODE_NAME",
+				ValueFrom: &corev1.EnvVarSource{
+					FieldRef: &corev1.ObjectFieldSelector{FieldPath: "spec.nodeName"},
+				},
+			})
+		Expect(cluster.validateWalArchiveBackend()).To(HaveLen(1))
+	})
+
+	It("accepts wal-g with credentials matching the destination path", func() {
 		cluster := Cluster{
 			Spec: ClusterSpec{
-				Bootstrap: &BootstrapConfiguration{
-					PgBaseBackup: &BootstrapPgBaseBackup{
-						Owner: "app",
+				Backup: &BackupConfiguration{
+					WalArchiveBackend: WalArchiveBackendWalG,
+					WalG: &WalGConfiguration{
+						DestinationPath: "s3://bucket/cluster-example",
+						EndpointURL:     "https://minio:9000",
+						BarmanCredentials: BarmanCredentials{
+							AWS: &S3Credentials{InheritFromIAMRole: true},
+						},
 					},
 				},
 			},
 		}
+		Expect(cluster.validateWalArchiveBackend()).To(BeEmpty())
+		Expect(cluster.Spec.Backup.IsWalGEnabled()).To(BeTrue())
 
-		result := cluster.validatePgBaseBackupApplicationDatabase()
-		Expect(len(result)).To(Equal(1))
+		cluster.Spec.Backup.WalG.DestinationPath = "gs://bucket/cluster-example"
+		Expect(cluster.validateWalArchiveBackend()).To(HaveLen(1))
+
+		cluster.Spec.Backup.WalG = nil
+		Expect(cluster.validateWalArchiveBackend()).To(HaveLen(1))
 	})
 
-	It("doesn't complain if you specify both database name and owner user for pg_basebackup", func() {
+	It("complains about wal-g settings not supported by the cloud provider", func() {
 		cluster := Cluster{
 			Spec: ClusterSpec{
-				Bootstrap: &BootstrapConfiguration{
-					PgBaseBackup: &BootstrapPgBaseBackup{
-						Database: "app",
-						Owner:    "app",
+				Backup: &BackupConfiguration{
+					WalArchiveBackend: WalArchiveBackendWalG,
+					WalG: &WalGConfiguration{
+						DestinationPath: "azure://container/cluster-example",
+						EndpointURL:     "https://storage:9000",
+						BarmanCredentials: BarmanCredentials{
+							Azure: &AzureCredentials{
+								ConnectionString: &SecretKeySelector{
+									LocalObjectReference: LocalObjectReference{Name: "azure-creds"},
+									Key:                  "AZURE_CONNECTION_STRING",
+								},
+							},
+						},
 					},
 				},
 			},
 		}
-
-		result := cluster.validatePgBaseBackupApplicationDatabase()
-		Expect(result).To(BeEmpty())
+		Expect(cluster.validateWalArchiveBackend()).To(HaveLen(2))
 	})
 
-	It("doesn't complain if we are not bootstrapping using pg_basebackup", func() {
-		recoveryCluster := &Cluster{
-			Spec: ClusterSpec{
-				Bootstrap: &BootstrapConfiguration{},
-			},
+	It("complains about the features requiring the barman backend", func() {
+		cluster := newCluster()
+		cluster.Spec.Backup.WalMirror = &WalMirrorConfiguration{}
+		cluster.Spec.Backup.BarmanObjectStore = &BarmanObjectStoreConfiguration{
+			Wal: &WalBackupConfiguration{Staging: &WalStagingConfiguration{}},
 		}
-		result := recoveryCluster.validateBootstrapPgBaseBackupSource()
-		Expect(result).To(BeEmpty())
+		Expect(cluster.validateWalArchiveBackend()).To(HaveLen(2))
 	})
+})
 
-	It("complain when the source cluster doesn't exist", func() {
-		recoveryCluster := &Cluster{
+var _ = Describe("delayed replicas validation", func() {
+	newCluster := func(instances ...string) Cluster {
+		return Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"},
 			Spec: ClusterSpec{
-				Bootstrap: &BootstrapConfiguration{
-					PgBaseBackup: &BootstrapPgBaseBackup{
-						Source: "test",
-					},
+				ImageName: "postgres:15",
+				Instances: 3,
+				DelayedReplicas: &DelayedReplicasConfiguration{
+					Instances:     instances,
+					MinApplyDelay: 3600,
 				},
 			},
 		}
-		result := recoveryCluster.validateBootstrapPgBaseBackupSource()
-		Expect(result).ToNot(BeEmpty())
+	}
+
+	It("accepts instances of the cluster", func() {
+		cluster := newCluster("cluster-example-3")
+		Expect(cluster.validateDelayedReplicas()).To(BeEmpty())
+	})
+
+	It("complains about instances of other clusters", func() {
+		cluster := newCluster("another-cluster-3", "cluster-example-x")
+		Expect(cluster.validateDelayedReplicas()).To(HaveLen(2))
+	})
+
+	It("complains about duplicated instances", func() {
+		cluster := newCluster("cluster-example-2", "cluster-example-2")
+		Expect(cluster.validateDelayedReplicas()).To(HaveLen(1))
+	})
+
+	It("complains if every instance is delayed", func() {
+		cluster := newCluster("cluster-example-1", "cluster-example-2", "cluster-example-3")
+		Expect(cluster.validateDelayedReplicas()).To(HaveLen(1))
+	})
+
+	It("complains if PostgreSQL doesn't support the apply delay in postgresql.conf", func() {
+		cluster := newCluster("cluster-example-3")
+		cluster.Spec.ImageName = "postgres:11"
+		Expect(cluster.validateDelayedReplicas()).To(HaveLen(1))
 	})
 })
 
-var _ = Describe("bootstrap recovery validation", func() {
-	It("complains if you specify the database name but not the owner for recovery", func() {
+var _ = Describe("replication connection validation", func() {
+	It("accepts a prefix of the application_name which fits", func() {
 		cluster := Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"},
 			Spec: ClusterSpec{
-				Bootstrap: &BootstrapConfiguration{
-					Recovery: &BootstrapRecovery{
-						Database: "app",
-					},
-				},
+				ReplicationConnection: &ReplicationConnectionConfiguration{ApplicationNamePrefix: "reporting-"},
 			},
 		}
-
-		result := cluster.validateRecoveryApplicationDatabase()
-		Expect(len(result)).To(Equal(1))
+		Expect(cluster.validateReplicationConnection()).To(BeEmpty())
 	})
 
-	It("complains if you specify the owner but not the database name for recovery", func() {
+	It("complains when the application_name would be truncated", func() {
 		cluster := Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: strings.Repeat("a", 50)},
 			Spec: ClusterSpec{
-				Bootstrap: &BootstrapConfiguration{
-					Recovery: &BootstrapRecovery{
-						Owner: "app",
-					},
-				},
+				ReplicationConnection: &ReplicationConnectionConfiguration{ApplicationNamePrefix: "reporting-"},
 			},
 		}
+		Expect(cluster.validateReplicationConnection()).To(HaveLen(1))
+	})
+})
+
+var _ = Describe("read replicas validation", func() {
+	It("accepts any number of read replicas", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{ReadReplicas: &ReadReplicasConfiguration{Instances: 2}},
+		}
+		Expect(cluster.validateReadReplicas()).To(BeEmpty())
 
-		result := cluster.validateRecoveryApplicationDatabase()
-		Expect(len(result)).To(Equal(1))
+		cluster.Spec.ReadReplicas = nil
+		Expect(cluster.validateReadReplicas()).To(BeEmpty())
 	})
 
-	It("doesn't complain if you specify both database name and owner user for recovery", func() {
+	It("complains about a negative number of read replicas", func() {
 		cluster := Cluster{
-			Spec: ClusterSpec{
-				Bootstrap: &BootstrapConfiguration{
-					Recovery: &BootstrapRecovery{
-						Database: "app",
-						Owner:    "app",
-					},
-				},
-			},
+			Spec: ClusterSpec{ReadReplicas: &ReadReplicasConfiguration{Instances: -1}},
 		}
+		Expect(cluster.validateReadReplicas()).To(HaveLen(1))
+	})
+})
 
-		result := cluster.validateRecoveryApplicationDatabase()
-		Expect(result).To(BeEmpty())
+var _ = Describe("replica join method validation", func() {
+	It("accepts joining replicas via pg_basebackup without an object store", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{ReplicaJoinMethod: ReplicaJoinMethodPgBaseBackup},
+		}
+		Expect(cluster.validateReplicaJoinMethod()).To(BeEmpty())
 	})
 
-	It("does not complain when bootstrap recovery source matches one of the names of external clusters", func() {
-		recoveryCluster := &Cluster{
-			Spec: ClusterSpec{
-				Bootstrap: &BootstrapConfiguration{
-					Recovery: &BootstrapRecovery{
-						Source: "test",
-					},
-				},
-				ExternalClusters: []ExternalCluster{
-					{
-						Name: "test",
-					},
-				},
-			},
+	It("complains about joining replicas from the object store without an object store", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{ReplicaJoinMethod: ReplicaJoinMethodObjectStore},
 		}
-		errorsList := recoveryCluster.validateBootstrapRecoverySource()
-		Expect(errorsList).To(BeEmpty())
+		Expect(cluster.validateReplicaJoinMethod()).To(HaveLen(1))
+
+		cluster.Spec.Backup = &BackupConfiguration{}
+		Expect(cluster.validateReplicaJoinMethod()).To(HaveLen(1))
 	})
 
-	It("complains when bootstrap recovery source does not match one of the names of external clusters", func() {
-		recoveryCluster := &Cluster{
+	It("accepts joining replicas from the configured object store", func() {
+		cluster := Cluster{
 			Spec: ClusterSpec{
-				Bootstrap: &BootstrapConfiguration{
-					Recovery: &BootstrapRecovery{
-						Source: "test",
-					},
-				},
-				ExternalClusters: []ExternalCluster{
-					{
-						Name: "another-test",
+				ReplicaJoinMethod: ReplicaJoinMethodObjectStore,
+				Backup: &BackupConfiguration{
+					BarmanObjectStore: &BarmanObjectStoreConfiguration{
+						DestinationPath: "s3://bucket/path",
 					},
 				},
 			},
 		}
-		errorsList := recoveryCluster.validateBootstrapRecoverySource()
-		Expect(errorsList).ToNot(BeEmpty())
+		Expect(cluster.validateReplicaJoinMethod()).To(BeEmpty())
 	})
 })
 
-var _ = Describe("toleration validation", func() {
-	It("doesn't complain if we provide a proper toleration", func() {
-		recoveryCluster := &Cluster{
+var _ = Describe("storage configuration validation", func() {
+	It("complains if the size is being reduced", func() {
+		clusterOld := Cluster{
 			Spec: ClusterSpec{
-				Affinity: AffinityConfiguration{
-					Tolerations: []corev1.Toleration{
-						{
-							Key:      "test",
-							Operator: "Exists",
-							Effect:   "NoSchedule",
-						},
-					},
+				StorageConfiguration: StorageConfiguration{
+					Size: "1G",
 				},
 			},
 		}
-		result := recoveryCluster.validateTolerations()
-		Expect(result).To(BeEmpty())
-	})
 
-	It("complain when the toleration ", func() {
-		recoveryCluster := &Cluster{
+		clusterNew := Cluster{
 			Spec: ClusterSpec{
-				Affinity: AffinityConfiguration{
-					Tolerations: []corev1.Toleration{
-						{
-							Key:      "",
-							Operator: "Equal",
-							Effect:   "NoSchedule",
-						},
-					},
+				StorageConfiguration: StorageConfiguration{
+					Size: "512M",
 				},
 			},
 		}
-		result := recoveryCluster.validateTolerations()
-		Expect(result).ToNot(BeEmpty())
+
+		Expect(clusterNew.validateStorageChange(&clusterOld)).ToNot(BeEmpty())
 	})
-})
 
-var _ = Describe("validate anti-affinity", func() {
-	t := true
-	f := false
-	It("doesn't complain if we provide an empty affinity section", func() {
-		cluster := &Cluster{
+	It("does not complain if nothing has been changed", func() {
+		one := "one"
+		clusterOld := Cluster{
 			Spec: ClusterSpec{
-				Affinity: AffinityConfiguration{},
+				StorageConfiguration: StorageConfiguration{
+					Size:         "1G",
+					StorageClass: &one,
+				},
 			},
 		}
-		result := cluster.validateAntiAffinity()
-		Expect(result).To(BeEmpty())
+
+		clusterNew := clusterOld.DeepCopy()
+
+		Expect(clusterNew.validateStorageChange(&clusterOld)).To(BeEmpty())
 	})
-	It("doesn't complain if we provide a proper PodAntiAffinity with anti-affinity enabled", func() {
-		cluster := &Cluster{
+
+	It("works fine is the size is being enlarged", func() {
+		clusterOld := Cluster{
 			Spec: ClusterSpec{
-				Affinity: AffinityConfiguration{
-					EnablePodAntiAffinity: &t,
-					PodAntiAffinityType:   "required",
+				StorageConfiguration: StorageConfiguration{
+					Size: "8G",
 				},
 			},
 		}
-		result := cluster.validateAntiAffinity()
-		Expect(result).To(BeEmpty())
-	})
 
-	It("doesn't complain if we provide a proper PodAntiAffinity with anti-affinity disabled", func() {
-		recoveryCluster := &Cluster{
+		clusterNew := Cluster{
 			Spec: ClusterSpec{
-				Affinity: AffinityConfiguration{
-					EnablePodAntiAffinity: &f,
-					PodAntiAffinityType:   "required",
+				StorageConfiguration: StorageConfiguration{
+					Size: "10G",
 				},
 			},
 		}
-		result := recoveryCluster.validateAntiAffinity()
-		Expect(result).To(BeEmpty())
+
+		Expect(clusterNew.validateStorageChange(&clusterOld)).To(BeEmpty())
 	})
+})
 
-	It("doesn't complain if we provide a proper PodAntiAffinity with anti-affinity enabled", func() {
-		recoveryCluster := &Cluster{
+var _ = Describe("tablespaces validation", func() {
+	newTablespace := func(name, size string) TablespaceConfiguration {
+		return TablespaceConfiguration{
+			Name:    name,
+			Storage: StorageConfiguration{Size: size},
+		}
+	}
+
+	It("accepts a list of valid tablespaces", func() {
+		cluster := Cluster{
 			Spec: ClusterSpec{
-				Affinity: AffinityConfiguration{
-					EnablePodAntiAffinity: &t,
-					PodAntiAffinityType:   "preferred",
+				Tablespaces: []TablespaceConfiguration{
+					newTablespace("indexes", "1Gi"),
+					newTablespace("cold_data", "10Gi"),
 				},
 			},
 		}
-		result := recoveryCluster.validateAntiAffinity()
-		Expect(result).To(BeEmpty())
+		Expect(cluster.validateTablespaces()).To(BeEmpty())
 	})
-	It("doesn't complain if we provide a proper PodAntiAffinity default with anti-affinity enabled", func() {
-		recoveryCluster := &Cluster{
+
+	It("complains about reserved names", func() {
+		cluster := Cluster{
 			Spec: ClusterSpec{
-				Affinity: AffinityConfiguration{
-					EnablePodAntiAffinity: &t,
-					PodAntiAffinityType:   "",
-				},
+				Tablespaces: []TablespaceConfiguration{newTablespace("pg_data", "1Gi")},
 			},
 		}
-		result := recoveryCluster.validateAntiAffinity()
-		Expect(result).To(BeEmpty())
+		Expect(cluster.validateTablespaces()).To(HaveLen(1))
 	})
 
-	It("complains if we provide a wrong PodAntiAffinity with anti-affinity disabled", func() {
-		recoveryCluster := &Cluster{
+	It("complains about duplicate names", func() {
+		cluster := Cluster{
 			Spec: ClusterSpec{
-				Affinity: AffinityConfiguration{
-					EnablePodAntiAffinity: &f,
-					PodAntiAffinityType:   "error",
+				Tablespaces: []TablespaceConfiguration{
+					newTablespace("indexes", "1Gi"),
+					newTablespace("indexes", "2Gi"),
 				},
 			},
 		}
-		result := recoveryCluster.validateAntiAffinity()
-		Expect(result).NotTo(BeEmpty())
+		Expect(cluster.validateTablespaces()).To(HaveLen(1))
 	})
 
-	It("complains if we provide a wrong PodAntiAffinity with anti-affinity enabled", func() {
-		recoveryCluster := &Cluster{
+	It("complains about tablespaces without a valid size", func() {
+		cluster := Cluster{
 			Spec: ClusterSpec{
-				Affinity: AffinityConfiguration{
-					EnablePodAntiAffinity: &t,
-					PodAntiAffinityType:   "error",
-				},
+				Tablespaces: []TablespaceConfiguration{newTablespace("indexes", "")},
 			},
 		}
-		result := recoveryCluster.validateAntiAffinity()
-		Expect(result).NotTo(BeEmpty())
+		Expect(cluster.validateTablespaces()).ToNot(BeEmpty())
+	})
+
+	It("complains if a tablespace is added or removed", func() {
+		clusterOld := Cluster{
+			Spec: ClusterSpec{
+				Tablespaces: []TablespaceConfiguration{newTablespace("indexes", "1Gi")},
+			},
+		}
+		clusterAdded := clusterOld.DeepCopy()
+		clusterAdded.Spec.Tablespaces = append(clusterAdded.Spec.Tablespaces, newTablespace("archive", "1Gi"))
+		clusterRemoved := clusterOld.DeepCopy()
+		clusterRemoved.Spec.Tablespaces = nil
+
+		Expect(clusterAdded.validateTablespacesChange(&clusterOld)).To(HaveLen(1))
+		Expect(clusterRemoved.validateTablespacesChange(&clusterOld)).To(HaveLen(1))
+	})
+
+	It("allows enlarging a tablespace but not reducing it", func() {
+		clusterOld := Cluster{
+			Spec: ClusterSpec{
+				Tablespaces: []TablespaceConfiguration{newTablespace("indexes", "2Gi")},
+			},
+		}
+		clusterEnlarged := clusterOld.DeepCopy()
+		clusterEnlarged.Spec.Tablespaces[0].Storage.Size = "4Gi"
+		clusterReduced := clusterOld.DeepCopy()
+		clusterReduced.Spec.Tablespaces[0].Storage.Size = "1Gi"
+
+		Expect(clusterEnlarged.validateTablespacesChange(&clusterOld)).To(BeEmpty())
+		Expect(clusterReduced.validateTablespacesChange(&clusterOld)).ToNot(BeEmpty())
+	})
+})
+
+var _ = Describe("Cluster name validation", func() {
+	It("should be a valid DNS label", func() {
+		cluster := Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "test.one",
+			},
+		}
+		Expect(cluster.validateName()).ToNot(BeEmpty())
+	})
+
+	It("should not be too long", func() {
+		cluster := Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "abcdefghi" +
+					"abcdefghi" +
+					"abcdefghi" +
+					"abcdefghi" +
+					"abcdefghi" +
+					"abcdefghi" +
+					"abcdefghi" +
+					"abcdefghi" +
+					"abcdefghi",
+			},
+		}
+		Expect(cluster.validateName()).ToNot(BeEmpty())
+	})
+
+	It("should not raise errors when the name is ok", func() {
+		cluster := Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "abcdefghi" +
+					"abcdefghi" +
+					"abcdefghi" +
+					"abcdefghi",
+			},
+		}
+		Expect(cluster.validateName()).To(BeEmpty())
+	})
+
+	It("should return errors when the name is not DNS-1035 compliant", func() {
+		cluster := Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "4b96d026-a956-47eb-bae8-a99b840805c3",
+			},
+		}
+		Expect(cluster.validateName()).NotTo(BeEmpty())
+	})
+
+	It("should return errors when the name length is greater than 50", func() {
+		cluster := Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: strings.Repeat("toomuchlong", 4) + "-" + "after4times",
+			},
+		}
+		Expect(cluster.validateName()).NotTo(BeEmpty())
+	})
+
+	It("should return errors when having a name with dots", func() {
+		cluster := Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "wrong.name",
+			},
+		}
+		Expect(cluster.validateName()).NotTo(BeEmpty())
 	})
 })
 
@@ -1661,17 +2071,21 @@ var _ = Describe("validation of the list of external clusters", func() {
 		Expect(cluster.validateExternalClusters()).To(BeEmpty())
 	})
 
-	It("complains when the list of servers contains duplicates", func() {
+	It("complains when the list of clusters contains duplicates", func() {
 		cluster := Cluster{
 			Spec: ClusterSpec{
 				ExternalClusters: []ExternalCluster{
 					{
-						Name:                 "one",
-						ConnectionParameters: map[string]string{},
+						Name: "one",
+						ConnectionParameters: map[string]string{
+							"dbname": "postgres",
+						},
 					},
 					{
-						Name:                 "one",
-						ConnectionParameters: map[string]string{},
+						Name: "one",
+						ConnectionParameters: map[string]string{
+							"dbname": "postgres",
+						},
 					},
 				},
 			},
@@ -1679,17 +2093,21 @@ var _ = Describe("validation of the list of external clusters", func() {
 		Expect(cluster.validateExternalClusters()).ToNot(BeEmpty())
 	})
 
-	It("should not raise errors is the server name is unique", func() {
+	It("should not raise errors is the cluster name is unique", func() {
 		cluster := Cluster{
 			Spec: ClusterSpec{
 				ExternalClusters: []ExternalCluster{
 					{
-						Name:                 "one",
-						ConnectionParameters: map[string]string{},
+						Name: "one",
+						ConnectionParameters: map[string]string{
+							"dbname": "postgres",
+						},
 					},
 					{
-						Name:                 "two",
-						ConnectionParameters: map[string]string{},
+						Name: "two",
+						ConnectionParameters: map[string]string{
+							"dbname": "postgres",
+						},
 					},
 				},
 			},
@@ -1698,291 +2116,787 @@ var _ = Describe("validation of the list of external clusters", func() {
 	})
 })
 
+var _ = Describe("validation of an external cluster", func() {
+	It("ensure that one of connectionParameters and barmanObjectStore is set", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				ExternalClusters: []ExternalCluster{
+					{},
+				},
+			},
+		}
+		Expect(cluster.validateExternalClusters()).To(Not(BeEmpty()))
+
+		cluster.Spec.ExternalClusters[0].ConnectionParameters = map[string]string{
+			"dbname": "postgres",
+		}
+		cluster.Spec.ExternalClusters[0].BarmanObjectStore = nil
+		Expect(cluster.validateExternalClusters()).To(BeEmpty())
+
+		cluster.Spec.ExternalClusters[0].ConnectionParameters = nil
+		cluster.Spec.ExternalClusters[0].BarmanObjectStore = &BarmanObjectStoreConfiguration{}
+		Expect(cluster.validateExternalClusters()).To(BeEmpty())
+
+		cluster.Spec.ExternalClusters[0].BarmanObjectStore = nil
+		cluster.Spec.ExternalClusters[0].WalG = &WalGConfiguration{
+			DestinationPath: "gs://bucket/cluster-example",
+			BarmanCredentials: BarmanCredentials{
+				Google: &GoogleCredentials{GKEEnvironment: true},
+			},
+		}
+		Expect(cluster.validateExternalClusters()).To(BeEmpty())
+	})
+})
+
 var _ = Describe("bootstrap base backup validation", func() {
-	It("complain when the source cluster doesn't exist", func() {
-		bootstrap := BootstrapConfiguration{}
-		bpb := BootstrapPgBaseBackup{Source: "test"}
-		bootstrap.PgBaseBackup = &bpb
-		recoveryCluster := &Cluster{
+	It("complains if you specify the database name but not the owner for pg_basebackup", func() {
+		cluster := Cluster{
 			Spec: ClusterSpec{
 				Bootstrap: &BootstrapConfiguration{
 					PgBaseBackup: &BootstrapPgBaseBackup{
-						Source: "test",
+						Database: "app",
 					},
 				},
 			},
 		}
-		result := recoveryCluster.validateBootstrapPgBaseBackupSource()
-		Expect(result).ToNot(BeEmpty())
+
+		result := cluster.validatePgBaseBackupApplicationDatabase()
+		Expect(len(result)).To(Equal(1))
 	})
-})
 
-var _ = Describe("unix permissions identifiers change validation", func() {
-	It("complains if the PostgresGID is changed", func() {
-		oldCluster := &Cluster{
+	It("complains if you specify the owner but not the database name for pg_basebackup", func() {
+		cluster := Cluster{
 			Spec: ClusterSpec{
-				PostgresGID: defaultPostgresGID,
+				Bootstrap: &BootstrapConfiguration{
+					PgBaseBackup: &BootstrapPgBaseBackup{
+						Owner: "app",
+					},
+				},
 			},
 		}
-		cluster := &Cluster{
+
+		result := cluster.validatePgBaseBackupApplicationDatabase()
+		Expect(len(result)).To(Equal(1))
+	})
+
+	It("doesn't complain if you specify both database name and owner user for pg_basebackup", func() {
+		cluster := Cluster{
 			Spec: ClusterSpec{
-				PostgresGID: 53,
+				Bootstrap: &BootstrapConfiguration{
+					PgBaseBackup: &BootstrapPgBaseBackup{
+						Database: "app",
+						Owner:    "app",
+					},
+				},
 			},
 		}
-		Expect(cluster.validateUnixPermissionIdentifierChange(oldCluster)).NotTo(BeEmpty())
+
+		result := cluster.validatePgBaseBackupApplicationDatabase()
+		Expect(result).To(BeEmpty())
 	})
 
-	It("complains if the PostgresUID is changed", func() {
-		oldCluster := &Cluster{
+	It("doesn't complain if we are not bootstrapping using pg_basebackup", func() {
+		recoveryCluster := &Cluster{
 			Spec: ClusterSpec{
-				PostgresUID: defaultPostgresUID,
+				Bootstrap: &BootstrapConfiguration{},
 			},
 		}
-		cluster := &Cluster{
+		result := recoveryCluster.validateBootstrapPgBaseBackupSource()
+		Expect(result).To(BeEmpty())
+	})
+
+	It("complain when the source cluster doesn't exist", func() {
+		recoveryCluster := &Cluster{
 			Spec: ClusterSpec{
-				PostgresGID: 74,
+				Bootstrap: &BootstrapConfiguration{
+					PgBaseBackup: &BootstrapPgBaseBackup{
+						Source: "test",
+					},
+				},
 			},
 		}
-		Expect(cluster.validateUnixPermissionIdentifierChange(oldCluster)).NotTo(BeEmpty())
+		result := recoveryCluster.validateBootstrapPgBaseBackupSource()
+		Expect(result).ToNot(BeEmpty())
 	})
 
-	It("should not complain if the values havn't been changed", func() {
-		oldCluster := &Cluster{
-			Spec: ClusterSpec{
-				PostgresUID: 74,
-				PostgresGID: 76,
-			},
-		}
-		cluster := &Cluster{
+	It("accepts a source cluster", func() {
+		cloneCluster := &Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "clone"},
 			Spec: ClusterSpec{
-				PostgresUID: 74,
-				PostgresGID: 76,
+				Bootstrap: &BootstrapConfiguration{
+					PgBaseBackup: &BootstrapPgBaseBackup{
+						SourceCluster: &LocalObjectReference{Name: "origin"},
+					},
+				},
 			},
 		}
-		Expect(cluster.validateUnixPermissionIdentifierChange(oldCluster)).To(BeEmpty())
+		Expect(cloneCluster.validateBootstrapPgBaseBackupSource()).To(BeEmpty())
 	})
-})
 
-var _ = Describe("replica mode validation", func() {
-	It("complains if the bootstrap method is not specified", func() {
-		cluster := &Cluster{
+	It("complains when the source cluster is the cluster itself", func() {
+		cloneCluster := &Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "clone"},
 			Spec: ClusterSpec{
-				ReplicaCluster: &ReplicaClusterConfiguration{
-					Enabled: true,
-					Source:  "test",
-				},
-				ExternalClusters: []ExternalCluster{
-					{
-						Name: "test",
+				Bootstrap: &BootstrapConfiguration{
+					PgBaseBackup: &BootstrapPgBaseBackup{
+						SourceCluster: &LocalObjectReference{Name: "clone"},
 					},
 				},
 			},
 		}
-		Expect(cluster.validateReplicaMode()).ToNot(BeEmpty())
+		Expect(cloneCluster.validateBootstrapPgBaseBackupSource()).To(HaveLen(1))
 	})
 
-	It("complains if the initdb bootstrap method is used", func() {
-		cluster := &Cluster{
+	It("complains when both source and sourceCluster are specified", func() {
+		cloneCluster := &Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "clone"},
 			Spec: ClusterSpec{
-				ReplicaCluster: &ReplicaClusterConfiguration{
-					Enabled: true,
-					Source:  "test",
-				},
 				Bootstrap: &BootstrapConfiguration{
-					InitDB: &BootstrapInitDB{},
-				},
-				ExternalClusters: []ExternalCluster{
-					{
-						Name: "test",
+					PgBaseBackup: &BootstrapPgBaseBackup{
+						Source:        "origin",
+						SourceCluster: &LocalObjectReference{Name: "origin"},
 					},
 				},
+				ExternalClusters: []ExternalCluster{{Name: "origin"}},
 			},
 		}
-		Expect(cluster.validateReplicaMode()).ToNot(BeEmpty())
+		Expect(cloneCluster.validateBootstrapPgBaseBackupSource()).To(HaveLen(1))
 	})
+})
 
-	It("is valid when the pg_basebackup bootstrap option is used", func() {
-		cluster := &Cluster{
+var _ = Describe("bootstrap recovery validation", func() {
+	It("complains if you specify the database name but not the owner for recovery", func() {
+		cluster := Cluster{
 			Spec: ClusterSpec{
-				ReplicaCluster: &ReplicaClusterConfiguration{
-					Enabled: true,
-					Source:  "test",
-				},
 				Bootstrap: &BootstrapConfiguration{
-					PgBaseBackup: &BootstrapPgBaseBackup{},
-				},
-				ExternalClusters: []ExternalCluster{
-					{
-						Name: "test",
+					Recovery: &BootstrapRecovery{
+						Database: "app",
 					},
 				},
 			},
 		}
-		result := cluster.validateReplicaMode()
-		Expect(result).To(BeEmpty())
+
+		result := cluster.validateRecoveryApplicationDatabase()
+		Expect(len(result)).To(Equal(1))
 	})
 
-	It("is valid when the restore bootstrap option is used", func() {
-		cluster := &Cluster{
+	It("complains if you specify the owner but not the database name for recovery", func() {
+		cluster := Cluster{
 			Spec: ClusterSpec{
-				ReplicaCluster: &ReplicaClusterConfiguration{
-					Enabled: true,
-					Source:  "test",
-				},
 				Bootstrap: &BootstrapConfiguration{
-					Recovery: &BootstrapRecovery{},
-				},
-				ExternalClusters: []ExternalCluster{
-					{
-						Name: "test",
+					Recovery: &BootstrapRecovery{
+						Owner: "app",
 					},
 				},
 			},
 		}
-		result := cluster.validateReplicaMode()
-		Expect(result).To(BeEmpty())
+
+		result := cluster.validateRecoveryApplicationDatabase()
+		Expect(len(result)).To(Equal(1))
 	})
 
-	It("complains when the external cluster doesn't exist", func() {
-		cluster := &Cluster{
+	It("doesn't complain if you specify both database name and owner user for recovery", func() {
+		cluster := Cluster{
 			Spec: ClusterSpec{
-				ReplicaCluster: &ReplicaClusterConfiguration{
-					Enabled: true,
-					Source:  "test",
-				},
 				Bootstrap: &BootstrapConfiguration{
-					PgBaseBackup: &BootstrapPgBaseBackup{},
+					Recovery: &BootstrapRecovery{
+						Database: "app",
+						Owner:    "app",
+					},
 				},
-				ExternalClusters: []ExternalCluster{},
 			},
 		}
 
-		cluster.Spec.Bootstrap.PgBaseBackup = nil
-		result := cluster.validateReplicaMode()
-		Expect(result).ToNot(BeEmpty())
+		result := cluster.validateRecoveryApplicationDatabase()
+		Expect(result).To(BeEmpty())
 	})
 
-	It("complains when enabled on an existing cluster with no replica mode configured", func() {
-		oldCluster := &Cluster{
-			Spec: ClusterSpec{},
-		}
-		cluster := &Cluster{
+	It("does not complain when bootstrap recovery source matches one of the names of external clusters", func() {
+		recoveryCluster := &Cluster{
 			Spec: ClusterSpec{
-				ReplicaCluster: &ReplicaClusterConfiguration{
-					Enabled: true,
-					Source:  "test",
-				},
 				Bootstrap: &BootstrapConfiguration{
-					PgBaseBackup: &BootstrapPgBaseBackup{},
+					Recovery: &BootstrapRecovery{
+						Source: "test",
+					},
 				},
 				ExternalClusters: []ExternalCluster{
-					{Name: "test"},
+					{
+						Name: "test",
+					},
 				},
 			},
 		}
-		Expect(cluster.validateReplicaMode()).To(BeEmpty())
-		Expect(cluster.validateReplicaModeChange(oldCluster)).ToNot(BeEmpty())
+		errorsList := recoveryCluster.validateBootstrapRecoverySource()
+		Expect(errorsList).To(BeEmpty())
 	})
 
-	It("complains when enabled on an existing cluster with replica mode disabled", func() {
-		oldCluster := &Cluster{
+	It("complains when bootstrap recovery source does not match one of the names of external clusters", func() {
+		recoveryCluster := &Cluster{
 			Spec: ClusterSpec{
-				ReplicaCluster: &ReplicaClusterConfiguration{
-					Enabled: false,
-					Source:  "test",
-				},
 				Bootstrap: &BootstrapConfiguration{
-					PgBaseBackup: &BootstrapPgBaseBackup{},
+					Recovery: &BootstrapRecovery{
+						Source: "test",
+					},
 				},
 				ExternalClusters: []ExternalCluster{
-					{Name: "test"},
+					{
+						Name: "another-test",
+					},
 				},
 			},
 		}
-		cluster := &Cluster{
+		errorsList := recoveryCluster.validateBootstrapRecoverySource()
+		Expect(errorsList).ToNot(BeEmpty())
+	})
+})
+
+var _ = Describe("toleration validation", func() {
+	It("doesn't complain if we provide a proper toleration", func() {
+		recoveryCluster := &Cluster{
 			Spec: ClusterSpec{
-				ReplicaCluster: &ReplicaClusterConfiguration{
-					Enabled: true,
-					Source:  "test",
-				},
-				Bootstrap: &BootstrapConfiguration{
-					PgBaseBackup: &BootstrapPgBaseBackup{},
-				},
-				ExternalClusters: []ExternalCluster{
-					{Name: "test"},
+				Affinity: AffinityConfiguration{
+					Tolerations: []corev1.Toleration{
+						{
+							Key:      "test",
+							Operator: "Exists",
+							Effect:   "NoSchedule",
+						},
+					},
 				},
 			},
 		}
-		Expect(cluster.validateReplicaMode()).To(BeEmpty())
-		Expect(cluster.validateReplicaModeChange(oldCluster)).ToNot(BeEmpty())
+		result := recoveryCluster.validateTolerations()
+		Expect(result).To(BeEmpty())
 	})
-})
 
-var _ = Describe("Validation changes", func() {
-	It("doesn't complain if given old cluster is nil", func() {
-		newCluster := &Cluster{}
-		err := newCluster.ValidateChanges(nil)
-		Expect(err).To(BeNil())
+	It("complain when the toleration ", func() {
+		recoveryCluster := &Cluster{
+			Spec: ClusterSpec{
+				Affinity: AffinityConfiguration{
+					Tolerations: []corev1.Toleration{
+						{
+							Key:      "",
+							Operator: "Equal",
+							Effect:   "NoSchedule",
+						},
+					},
+				},
+			},
+		}
+		result := recoveryCluster.validateTolerations()
+		Expect(result).ToNot(BeEmpty())
 	})
 })
 
-var _ = Describe("Backup validation", func() {
-	It("complain if there's no credentials", func() {
+var _ = Describe("validate anti-affinity", func() {
+	t := true
+	f := false
+	It("doesn't complain if we provide an empty affinity section", func() {
 		cluster := &Cluster{
 			Spec: ClusterSpec{
-				Backup: &BackupConfiguration{
-					BarmanObjectStore: &BarmanObjectStoreConfiguration{},
-				},
+				Affinity: AffinityConfiguration{},
 			},
 		}
-		err := cluster.validateBackupConfiguration()
-		Expect(len(err)).To(Equal(1))
+		result := cluster.validateAntiAffinity()
+		Expect(result).To(BeEmpty())
 	})
-
-	It("doesn't complain if given policy is not provided", func() {
+	It("doesn't complain if we provide a proper PodAntiAffinity with anti-affinity enabled", func() {
 		cluster := &Cluster{
 			Spec: ClusterSpec{
-				Backup: &BackupConfiguration{},
+				Affinity: AffinityConfiguration{
+					EnablePodAntiAffinity: &t,
+					PodAntiAffinityType:   "required",
+				},
 			},
 		}
-		err := cluster.validateBackupConfiguration()
-		Expect(err).To(BeNil())
+		result := cluster.validateAntiAffinity()
+		Expect(result).To(BeEmpty())
 	})
 
-	It("doesn't complain if given policy is valid", func() {
-		cluster := &Cluster{
+	It("doesn't complain if we provide a proper PodAntiAffinity with anti-affinity disabled", func() {
+		recoveryCluster := &Cluster{
 			Spec: ClusterSpec{
-				Backup: &BackupConfiguration{
-					RetentionPolicy: "90d",
+				Affinity: AffinityConfiguration{
+					EnablePodAntiAffinity: &f,
+					PodAntiAffinityType:   "required",
 				},
 			},
 		}
-		err := cluster.validateBackupConfiguration()
-		Expect(err).To(BeNil())
+		result := recoveryCluster.validateAntiAffinity()
+		Expect(result).To(BeEmpty())
 	})
 
-	It("complain if a given policy is not valid", func() {
-		cluster := &Cluster{
+	It("doesn't complain if we provide a proper PodAntiAffinity with anti-affinity enabled", func() {
+		recoveryCluster := &Cluster{
 			Spec: ClusterSpec{
-				Backup: &BackupConfiguration{
-					BarmanObjectStore: &BarmanObjectStoreConfiguration{},
-					RetentionPolicy:   "09",
+				Affinity: AffinityConfiguration{
+					EnablePodAntiAffinity: &t,
+					PodAntiAffinityType:   "preferred",
 				},
 			},
 		}
-		err := cluster.validateBackupConfiguration()
-		Expect(len(err)).To(Equal(2))
+		result := recoveryCluster.validateAntiAffinity()
+		Expect(result).To(BeEmpty())
 	})
-})
-
-var _ = Describe("Default monitoring queries", func() {
-	It("correctly set the default monitoring queries configmap and secret when none is already specified", func() {
-		cluster := &Cluster{}
-		cluster.defaultMonitoringQueries(&configuration.Data{
-			MonitoringQueriesSecret:    "test-secret",
-			MonitoringQueriesConfigmap: "test-configmap",
-		})
-		Expect(cluster.Spec.Monitoring).NotTo(BeNil())
+	It("doesn't complain if we provide a proper PodAntiAffinity default with anti-affinity enabled", func() {
+		recoveryCluster := &Cluster{
+			Spec: ClusterSpec{
+				Affinity: AffinityConfiguration{
+					EnablePodAntiAffinity: &t,
+					PodAntiAffinityType:   "",
+				},
+			},
+		}
+		result := recoveryCluster.validateAntiAffinity()
+		Expect(result).To(BeEmpty())
+	})
+
+	It("complains if we provide a wrong PodAntiAffinity with anti-affinity disabled", func() {
+		recoveryCluster := &Cluster{
+			Spec: ClusterSpec{
+				Affinity: AffinityConfiguration{
+					EnablePodAntiAffinity: &f,
+					PodAntiAffinityType:   "error",
+				},
+			},
+		}
+		result := recoveryCluster.validateAntiAffinity()
+		Expect(result).NotTo(BeEmpty())
+	})
+
+	It("complains if we provide a wrong PodAntiAffinity with anti-affinity enabled", func() {
+		recoveryCluster := &Cluster{
+			Spec: ClusterSpec{
+				Affinity: AffinityConfiguration{
+					EnablePodAntiAffinity: &t,
+					PodAntiAffinityType:   "error",
+				},
+			},
+		}
+		result := recoveryCluster.validateAntiAffinity()
+		Expect(result).NotTo(BeEmpty())
+	})
+})
+
+var _ = Describe("validation of the list of external clusters", func() {
+	It("is correct when it's empty", func() {
+		cluster := Cluster{}
+		Expect(cluster.validateExternalClusters()).To(BeEmpty())
+	})
+
+	It("complains when the list of servers contains duplicates", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				ExternalClusters: []ExternalCluster{
+					{
+						Name:                 "one",
+						ConnectionParameters: map[string]string{},
+					},
+					{
+						Name:                 "one",
+						ConnectionParameters: map[string]string{},
+					},
+				},
+			},
+		}
+		Expect(cluster.validateExternalClusters()).ToNot(BeEmpty())
+	})
+
+	It("should not raise errors is the server name is unique", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				ExternalClusters: []ExternalCluster{
+					{
+						Name:                 "one",
+						ConnectionParameters: map[string]string{},
+					},
+					{
+						Name:                 "two",
+						ConnectionParameters: map[string]string{},
+					},
+				},
+			},
+		}
+		Expect(cluster.validateExternalClusters()).To(BeEmpty())
+	})
+})
+
+var _ = Describe("bootstrap base backup validation", func() {
+	It("complain when the source cluster doesn't exist", func() {
+		bootstrap := BootstrapConfiguration{}
+		bpb := BootstrapPgBaseBackup{Source: "test"}
+		bootstrap.PgBaseBackup = &bpb
+		recoveryCluster := &Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					PgBaseBackup: &BootstrapPgBaseBackup{
+						Source: "test",
+					},
+				},
+			},
+		}
+		result := recoveryCluster.validateBootstrapPgBaseBackupSource()
+		Expect(result).ToNot(BeEmpty())
+	})
+})
+
+var _ = Describe("unix permissions identifiers change validation", func() {
+	It("complains if the PostgresGID is changed", func() {
+		oldCluster := &Cluster{
+			Spec: ClusterSpec{
+				PostgresGID: defaultPostgresGID,
+			},
+		}
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				PostgresGID: 53,
+			},
+		}
+		Expect(cluster.validateUnixPermissionIdentifierChange(oldCluster)).NotTo(BeEmpty())
+	})
+
+	It("complains if the PostgresUID is changed", func() {
+		oldCluster := &Cluster{
+			Spec: ClusterSpec{
+				PostgresUID: defaultPostgresUID,
+			},
+		}
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				PostgresGID: 74,
+			},
+		}
+		Expect(cluster.validateUnixPermissionIdentifierChange(oldCluster)).NotTo(BeEmpty())
+	})
+
+	It("should not complain if the values havn't been changed", func() {
+		oldCluster := &Cluster{
+			Spec: ClusterSpec{
+				PostgresUID: 74,
+				PostgresGID: 76,
+			},
+		}
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				PostgresUID: 74,
+				PostgresGID: 76,
+			},
+		}
+		Expect(cluster.validateUnixPermissionIdentifierChange(oldCluster)).To(BeEmpty())
+	})
+})
+
+var _ = Describe("initdb options change validation", func() {
+	newCluster := func(initDB BootstrapInitDB) *Cluster {
+		return &Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					InitDB: &initDB,
+				},
+			},
+		}
+	}
+
+	It("doesn't complain if the options haven't been changed", func() {
+		initDB := BootstrapInitDB{
+			DataChecksums:  pointer.Bool(true),
+			Encoding:       "UTF8",
+			LocaleCollate:  "en_US.UTF-8",
+			LocaleCType:    "en_US.UTF-8",
+			WalSegmentSize: 32,
+			Database:       "app",
+		}
+		cluster := newCluster(initDB)
+		initDB.Database = "other"
+		Expect(cluster.validateInitDBChange(newCluster(initDB))).To(BeEmpty())
+	})
+
+	It("complains if data checksums are enabled", func() {
+		oldCluster := newCluster(BootstrapInitDB{})
+		cluster := newCluster(BootstrapInitDB{DataChecksums: pointer.Bool(true)})
+		Expect(cluster.validateInitDBChange(oldCluster)).To(HaveLen(1))
+	})
+
+	It("complains if the encoding, the locale or the WAL segment size are changed", func() {
+		oldCluster := newCluster(BootstrapInitDB{
+			Encoding:      "UTF8",
+			LocaleCollate: "C",
+			LocaleCType:   "C",
+		})
+		cluster := newCluster(BootstrapInitDB{
+			Encoding:       "LATIN1",
+			LocaleCollate:  "en_US",
+			LocaleCType:    "en_US",
+			WalSegmentSize: 64,
+		})
+		Expect(cluster.validateInitDBChange(oldCluster)).To(HaveLen(4))
+	})
+
+	It("doesn't complain when the previous values were not defaulted", func() {
+		oldCluster := newCluster(BootstrapInitDB{})
+		cluster := newCluster(BootstrapInitDB{
+			Encoding:      "UTF8",
+			LocaleCollate: "C",
+			LocaleCType:   "C",
+		})
+		Expect(cluster.validateInitDBChange(oldCluster)).To(BeEmpty())
+	})
+})
+
+var _ = Describe("replica mode validation", func() {
+	It("complains if the bootstrap method is not specified", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				ReplicaCluster: &ReplicaClusterConfiguration{
+					Enabled: true,
+					Source:  "test",
+				},
+				ExternalClusters: []ExternalCluster{
+					{
+						Name: "test",
+					},
+				},
+			},
+		}
+		Expect(cluster.validateReplicaMode()).ToNot(BeEmpty())
+	})
+
+	It("complains if the initdb bootstrap method is used", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				ReplicaCluster: &ReplicaClusterConfiguration{
+					Enabled: true,
+					Source:  "test",
+				},
+				Bootstrap: &BootstrapConfiguration{
+					InitDB: &BootstrapInitDB{},
+				},
+				ExternalClusters: []ExternalCluster{
+					{
+						Name: "test",
+					},
+				},
+			},
+		}
+		Expect(cluster.validateReplicaMode()).ToNot(BeEmpty())
+	})
+
+	It("is valid when the pg_basebackup bootstrap option is used", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				ReplicaCluster: &ReplicaClusterConfiguration{
+					Enabled: true,
+					Source:  "test",
+				},
+				Bootstrap: &BootstrapConfiguration{
+					PgBaseBackup: &BootstrapPgBaseBackup{},
+				},
+				ExternalClusters: []ExternalCluster{
+					{
+						Name: "test",
+					},
+				},
+			},
+		}
+		result := cluster.validateReplicaMode()
+		Expect(result).To(BeEmpty())
+	})
+
+	It("is valid when the restore bootstrap option is used", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				ReplicaCluster: &ReplicaClusterConfiguration{
+					Enabled: true,
+					Source:  "test",
+				},
+				Bootstrap: &BootstrapConfiguration{
+					Recovery: &BootstrapRecovery{},
+				},
+				ExternalClusters: []ExternalCluster{
+					{
+						Name: "test",
+					},
+				},
+			},
+		}
+		result := cluster.validateReplicaMode()
+		Expect(result).To(BeEmpty())
+	})
+
+	It("complains when the external cluster doesn't exist", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				ReplicaCluster: &ReplicaClusterConfiguration{
+					Enabled: true,
+					Source:  "test",
+				},
+				Bootstrap: &BootstrapConfiguration{
+					PgBaseBackup: &BootstrapPgBaseBackup{},
+				},
+				ExternalClusters: []ExternalCluster{},
+			},
+		}
+
+		cluster.Spec.Bootstrap.PgBaseBackup = nil
+		result := cluster.validateReplicaMode()
+		Expect(result).ToNot(BeEmpty())
+	})
+
+	It("complains when enabled on an existing cluster with no replica mode configured", func() {
+		oldCluster := &Cluster{
+			Spec: ClusterSpec{},
+		}
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				ReplicaCluster: &ReplicaClusterConfiguration{
+					Enabled: true,
+					Source:  "test",
+				},
+				Bootstrap: &BootstrapConfiguration{
+					PgBaseBackup: &BootstrapPgBaseBackup{},
+				},
+				ExternalClusters: []ExternalCluster{
+					{Name: "test"},
+				},
+			},
+		}
+		Expect(cluster.validateReplicaMode()).To(BeEmpty())
+		Expect(cluster.validateReplicaModeChange(oldCluster)).ToNot(BeEmpty())
+	})
+
+	It("complains when enabled on an existing cluster with replica mode disabled", func() {
+		oldCluster := &Cluster{
+			Spec: ClusterSpec{
+				ReplicaCluster: &ReplicaClusterConfiguration{
+					Enabled: false,
+					Source:  "test",
+				},
+				Bootstrap: &BootstrapConfiguration{
+					PgBaseBackup: &BootstrapPgBaseBackup{},
+				},
+				ExternalClusters: []ExternalCluster{
+					{Name: "test"},
+				},
+			},
+		}
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				ReplicaCluster: &ReplicaClusterConfiguration{
+					Enabled: true,
+					Source:  "test",
+				},
+				Bootstrap: &BootstrapConfiguration{
+					PgBaseBackup: &BootstrapPgBaseBackup{},
+				},
+				ExternalClusters: []ExternalCluster{
+					{Name: "test"},
+				},
+			},
+		}
+		Expect(cluster.validateReplicaMode()).To(BeEmpty())
+		Expect(cluster.validateReplicaModeChange(oldCluster)).ToNot(BeEmpty())
+	})
+})
+
+var _ = Describe("Validation changes", func() {
+	It("doesn't complain if given old cluster is nil", func() {
+		newCluster := &Cluster{}
+		err := newCluster.ValidateChanges(nil)
+		Expect(err).To(BeNil())
+	})
+})
+
+var _ = Describe("Backup validation", func() {
+	It("complain if there's no credentials", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Backup: &BackupConfiguration{
+					BarmanObjectStore: &BarmanObjectStoreConfiguration{},
+				},
+			},
+		}
+		err := cluster.validateBackupConfiguration()
+		Expect(len(err)).To(Equal(1))
+	})
+
+	It("doesn't complain if given policy is not provided", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Backup: &BackupConfiguration{},
+			},
+		}
+		err := cluster.validateBackupConfiguration()
+		Expect(err).To(BeNil())
+	})
+
+	It("doesn't complain if given policy is valid", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Backup: &BackupConfiguration{
+					RetentionPolicy: "90d",
+				},
+			},
+		}
+		err := cluster.validateBackupConfiguration()
+		Expect(err).To(BeNil())
+	})
+
+	It("complain if a given policy is not valid", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Backup: &BackupConfiguration{
+					BarmanObjectStore: &BarmanObjectStoreConfiguration{},
+					RetentionPolicy:   "09",
+				},
+			},
+		}
+		err := cluster.validateBackupConfiguration()
+		Expect(len(err)).To(Equal(2))
+	})
+
+	It("accepts the credentials read from a CSI volume", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Backup: &BackupConfiguration{
+					BarmanObjectStore: &BarmanObjectStoreConfiguration{
+						BarmanCredentials: BarmanCredentials{
+							AWS: &S3Credentials{},
+						},
+						CredentialsVolume: &corev1.CSIVolumeSource{
+							Driver: "secrets-store.csi.k8s.io",
+						},
+					},
+				},
+			},
+		}
+		Expect(cluster.validateBackupConfiguration()).To(BeEmpty())
+
+		By("referencing a secret together with the credentials volume")
+		cluster.Spec.Backup.BarmanObjectStore.AWS.AccessKeyIDReference = &SecretKeySelector{
+			LocalObjectReference: LocalObjectReference{Name: "aws-creds"},
+			Key:                  "ACCESS_KEY_ID",
+		}
+		Expect(cluster.validateBackupConfiguration()).To(HaveLen(1))
+	})
+
+	It("rejects the credentials volume in the other object stores", func() {
+		path := field.NewPath("spec", "externalClusters").Index(0).Child("barmanObjectStore")
+		objectStore := &BarmanObjectStoreConfiguration{
+			CredentialsVolume: &corev1.CSIVolumeSource{Driver: "secrets-store.csi.k8s.io"},
+		}
+		Expect(objectStore.validateCredentialsVolumeNotSupported(path)).To(HaveLen(1))
+		Expect((&BarmanObjectStoreConfiguration{}).validateCredentialsVolumeNotSupported(path)).To(BeEmpty())
+	})
+})
+
+var _ = Describe("Default monitoring queries", func() {
+	It("correctly set the default monitoring queries configmap and secret when none is already specified", func() {
+		cluster := &Cluster{}
+		cluster.defaultMonitoringQueries(&configuration.Data{
+			MonitoringQueriesSecret:    "test-secret",
+			MonitoringQueriesConfigmap: "test-configmap",
+		})
+		Expect(cluster.Spec.Monitoring).NotTo(BeNil())
 		Expect(cluster.Spec.Monitoring.CustomQueriesConfigMap).NotTo(BeEmpty())
 		Expect(cluster.Spec.Monitoring.CustomQueriesConfigMap).
 			To(ContainElement(ConfigMapKeySelector{
@@ -1996,482 +2910,1651 @@ var _ = Describe("Default monitoring queries", func() {
 				Key:                  DefaultMonitoringKey,
 			}))
 	})
-	testCluster := &Cluster{Spec: ClusterSpec{Monitoring: &MonitoringConfiguration{
-		CustomQueriesConfigMap: []ConfigMapKeySelector{
-			{
-				LocalObjectReference: LocalObjectReference{Name: DefaultMonitoringConfigMapName},
-				Key:                  "test2",
+	testCluster := &Cluster{Spec: ClusterSpec{Monitoring: &MonitoringConfiguration{
+		CustomQueriesConfigMap: []ConfigMapKeySelector{
+			{
+				LocalObjectReference: LocalObjectReference{Name: DefaultMonitoringConfigMapName},
+				Key:                  "test2",
+			},
+		},
+		CustomQueriesSecret: []SecretKeySelector{
+			{
+				LocalObjectReference: LocalObjectReference{Name: DefaultMonitoringConfigMapName},
+				Key:                  "test3",
+			},
+		},
+	}}}
+	It("correctly set the default monitoring queries configmap when other metrics are already specified", func() {
+		modifiedCluster := testCluster.DeepCopy()
+		modifiedCluster.defaultMonitoringQueries(&configuration.Data{
+			MonitoringQueriesConfigmap: "test-configmap",
+		})
+
+		Expect(modifiedCluster.Spec.Monitoring).NotTo(BeNil())
+		Expect(modifiedCluster.Spec.Monitoring.CustomQueriesConfigMap).NotTo(BeEmpty())
+		Expect(modifiedCluster.Spec.Monitoring.CustomQueriesSecret).NotTo(BeEmpty())
+		Expect(modifiedCluster.Spec.Monitoring.CustomQueriesConfigMap).
+			To(ContainElement(ConfigMapKeySelector{
+				LocalObjectReference: LocalObjectReference{Name: DefaultMonitoringConfigMapName},
+				Key:                  "test2",
+			}))
+
+		Expect(modifiedCluster.Spec.Monitoring.CustomQueriesSecret).
+			To(BeEquivalentTo(testCluster.Spec.Monitoring.CustomQueriesSecret))
+		Expect(modifiedCluster.Spec.Monitoring.CustomQueriesConfigMap).
+			To(ContainElements(testCluster.Spec.Monitoring.CustomQueriesConfigMap))
+	})
+	It("correctly set the default monitoring queries secret when other metrics are already specified", func() {
+		modifiedCluster := testCluster.DeepCopy()
+		modifiedCluster.defaultMonitoringQueries(&configuration.Data{
+			MonitoringQueriesSecret: "test-secret",
+		})
+
+		Expect(modifiedCluster.Spec.Monitoring).NotTo(BeNil())
+		Expect(modifiedCluster.Spec.Monitoring.CustomQueriesSecret).NotTo(BeEmpty())
+		Expect(modifiedCluster.Spec.Monitoring.CustomQueriesConfigMap).NotTo(BeEmpty())
+		Expect(modifiedCluster.Spec.Monitoring.CustomQueriesSecret).
+			To(ContainElement(SecretKeySelector{
+				LocalObjectReference: LocalObjectReference{Name: DefaultMonitoringSecretName},
+				Key:                  "test3",
+			}))
+
+		Expect(modifiedCluster.Spec.Monitoring.CustomQueriesConfigMap).
+			To(BeEquivalentTo(testCluster.Spec.Monitoring.CustomQueriesConfigMap))
+		Expect(modifiedCluster.Spec.Monitoring.CustomQueriesSecret).
+			To(ContainElements(testCluster.Spec.Monitoring.CustomQueriesSecret))
+	})
+})
+
+var _ = Describe("validation of imports", func() {
+	It("rejects unrecognized import type", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					InitDB: &BootstrapInitDB{
+						Database: "app",
+						Owner:    "app",
+						Import: &Import{
+							Type: "fooBar",
+						},
+					},
+				},
+			},
+		}
+
+		result := cluster.validateImport()
+		Expect(result).To(HaveLen(1))
+	})
+
+	It("rejects microservice import with roles", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					InitDB: &BootstrapInitDB{
+						Database: "app",
+						Owner:    "app",
+						Import: &Import{
+							Type:      MicroserviceSnapshotType,
+							Databases: []string{"foo"},
+							Roles:     []string{"bar"},
+						},
+					},
+				},
+			},
+		}
+
+		result := cluster.validateImport()
+		Expect(result).To(HaveLen(1))
+	})
+
+	It("rejects microservice import without exactly one database", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					InitDB: &BootstrapInitDB{
+						Database: "app",
+						Owner:    "app",
+						Import: &Import{
+							Type:      MicroserviceSnapshotType,
+							Databases: []string{"foo", "bar"},
+						},
+					},
+				},
+			},
+		}
+
+		result := cluster.validateImport()
+		Expect(result).To(HaveLen(1))
+	})
+
+	It("rejects microservice import with a wildcard on the database name", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					InitDB: &BootstrapInitDB{
+						Database: "app",
+						Owner:    "app",
+						Import: &Import{
+							Type:      MicroserviceSnapshotType,
+							Databases: []string{"*foo"},
+						},
+					},
+				},
+			},
+		}
+
+		result := cluster.validateImport()
+		Expect(result).To(HaveLen(1))
+	})
+
+	It("accepts microservice import when well specified", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					InitDB: &BootstrapInitDB{
+						Database: "app",
+						Owner:    "app",
+						Import: &Import{
+							Type:      MicroserviceSnapshotType,
+							Databases: []string{"foo"},
+						},
+					},
+				},
+			},
+		}
+
+		result := cluster.validateImport()
+		Expect(result).To(BeEmpty())
+	})
+
+	It("rejects monolith import with no databases", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					InitDB: &BootstrapInitDB{
+						Database: "app",
+						Owner:    "app",
+						Import: &Import{
+							Type:      MonolithSnapshotType,
+							Databases: []string{},
+						},
+					},
+				},
+			},
+		}
+
+		result := cluster.validateImport()
+		Expect(result).To(HaveLen(1))
+	})
+
+	It("rejects monolith import with PostImport Application SQL", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					InitDB: &BootstrapInitDB{
+						Database: "app",
+						Owner:    "app",
+						Import: &Import{
+							Type:                     MonolithSnapshotType,
+							Databases:                []string{"foo"},
+							PostImportApplicationSQL: []string{"select * from bar"},
+						},
+					},
+				},
+			},
+		}
+
+		result := cluster.validateImport()
+		Expect(result).To(HaveLen(1))
+	})
+
+	It("rejects monolith import with wildcards alongside specific values", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					InitDB: &BootstrapInitDB{
+						Database: "app",
+						Owner:    "app",
+						Import: &Import{
+							Type:      MonolithSnapshotType,
+							Databases: []string{"bar", "*"},
+						},
+					},
+				},
+			},
+		}
+
+		result := cluster.validateImport()
+		Expect(result).To(HaveLen(1))
+
+		cluster = &Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					InitDB: &BootstrapInitDB{
+						Database: "app",
+						Owner:    "app",
+						Import: &Import{
+							Type:      MonolithSnapshotType,
+							Databases: []string{"foo"},
+							Roles:     []string{"baz", "*"},
+						},
+					},
+				},
+			},
+		}
+
+		result = cluster.validateImport()
+		Expect(result).To(HaveLen(1))
+	})
+
+	It("accepts monolith import with proper values", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					InitDB: &BootstrapInitDB{
+						Database: "app",
+						Owner:    "app",
+						Import: &Import{
+							Type:      MonolithSnapshotType,
+							Databases: []string{"foo"},
+						},
+					},
+				},
+			},
+		}
+
+		result := cluster.validateImport()
+		Expect(result).To(BeEmpty())
+	})
+
+	It("accepts monolith import with wildcards", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					InitDB: &BootstrapInitDB{
+						Database: "app",
+						Owner:    "app",
+						Import: &Import{
+							Type:      MonolithSnapshotType,
+							Databases: []string{"*"},
+							Roles:     []string{"*"},
+						},
+					},
+				},
+			},
+		}
+
+		result := cluster.validateImport()
+		Expect(result).To(BeEmpty())
+	})
+})
+
+var _ = Describe("validation of replication slots configuration", func() {
+	It("prevents using replication slots on PostgreSQL 10 and older", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				ImageName: "ghcr.io/cloudnative-pg/postgresql:10.5",
+				ReplicationSlots: &ReplicationSlotsConfiguration{
+					HighAvailability: &ReplicationSlotsHAConfiguration{
+						Enabled: true,
+					},
+					UpdateInterval: 0,
+				},
+			},
+		}
+		cluster.Default()
+
+		result := cluster.validateReplicationSlots()
+		Expect(result).To(HaveLen(1))
+	})
+
+	It("can be enabled on the default PostgreSQL image", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				ImageName: versions.DefaultImageName,
+				ReplicationSlots: &ReplicationSlotsConfiguration{
+					HighAvailability: &ReplicationSlotsHAConfiguration{
+						Enabled: true,
+					},
+					UpdateInterval: 0,
+				},
+			},
+		}
+		cluster.Default()
+
+		result := cluster.validateReplicationSlots()
+		Expect(result).To(BeEmpty())
+	})
+
+	It("prevents limiting the WAL retained by slots on PostgreSQL 12 and older", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				ImageName: "ghcr.io/cloudnative-pg/postgresql:12.8",
+				ReplicationSlots: &ReplicationSlotsConfiguration{
+					MaxSlotWalKeepSize: "10GB",
+				},
+			},
+		}
+		cluster.Default()
+
+		result := cluster.validateMaxSlotWalKeepSize()
+		Expect(result).To(HaveLen(1))
+	})
+
+	It("prevents limiting the WAL retained by slots twice", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				ImageName: versions.DefaultImageName,
+				ReplicationSlots: &ReplicationSlotsConfiguration{
+					MaxSlotWalKeepSize: "10GB",
+				},
+				PostgresConfiguration: PostgresConfiguration{
+					Parameters: map[string]string{
+						"max_slot_wal_keep_size": "1GB",
+					},
+				},
+			},
+		}
+		cluster.Default()
+
+		result := cluster.validateMaxSlotWalKeepSize()
+		Expect(result).To(HaveLen(1))
+	})
+
+	It("allows limiting the WAL retained by slots on the default PostgreSQL image", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				ImageName: versions.DefaultImageName,
+				ReplicationSlots: &ReplicationSlotsConfiguration{
+					MaxSlotWalKeepSize: "10GB",
+				},
+			},
+		}
+		cluster.Default()
+
+		result := cluster.validateMaxSlotWalKeepSize()
+		Expect(result).To(BeEmpty())
+	})
+
+	It("allows enabling replication slots on the fly", func() {
+		oldCluster := &Cluster{
+			Spec: ClusterSpec{
+				ImageName: versions.DefaultImageName,
+			},
+		}
+		oldCluster.Default()
+
+		newCluster := oldCluster.DeepCopy()
+		newCluster.Spec.ReplicationSlots = &ReplicationSlotsConfiguration{
+			HighAvailability: &ReplicationSlotsHAConfiguration{
+				Enabled:    true,
+				SlotPrefix: "_test_",
+			},
+		}
+
+		Expect(newCluster.validateReplicationSlotsChange(oldCluster)).To(BeEmpty())
+	})
+
+	It("prevents changing the slot prefix while replication slots are enabled", func() {
+		oldCluster := &Cluster{
+			Spec: ClusterSpec{
+				ImageName: versions.DefaultImageName,
+				ReplicationSlots: &ReplicationSlotsConfiguration{
+					HighAvailability: &ReplicationSlotsHAConfiguration{
+						Enabled:    true,
+						SlotPrefix: "_test_",
+					},
+				},
+			},
+		}
+		oldCluster.Default()
+
+		newCluster := oldCluster.DeepCopy()
+		newCluster.Spec.ReplicationSlots.HighAvailability.SlotPrefix = "_toast_"
+		Expect(newCluster.validateReplicationSlotsChange(oldCluster)).To(HaveLen(1))
+	})
+
+	It("prevents removing the replication slot section when replication slots are enabled", func() {
+		oldCluster := &Cluster{
+			Spec: ClusterSpec{
+				ImageName: versions.DefaultImageName,
+				ReplicationSlots: &ReplicationSlotsConfiguration{
+					HighAvailability: &ReplicationSlotsHAConfiguration{
+						Enabled:    true,
+						SlotPrefix: "_test_",
+					},
+				},
+			},
+		}
+		oldCluster.Default()
+
+		newCluster := oldCluster.DeepCopy()
+		newCluster.Spec.ReplicationSlots = nil
+		Expect(newCluster.validateReplicationSlotsChange(oldCluster)).To(HaveLen(1))
+	})
+
+	It("allows disabling the replication slots", func() {
+		oldCluster := &Cluster{
+			Spec: ClusterSpec{
+				ImageName: versions.DefaultImageName,
+				ReplicationSlots: &ReplicationSlotsConfiguration{
+					HighAvailability: &ReplicationSlotsHAConfiguration{
+						Enabled:    true,
+						SlotPrefix: "_test_",
+					},
+				},
+			},
+		}
+		oldCluster.Default()
+
+		newCluster := oldCluster.DeepCopy()
+		newCluster.Spec.ReplicationSlots.HighAvailability.Enabled = false
+		Expect(newCluster.validateReplicationSlotsChange(oldCluster)).To(BeEmpty())
+	})
+})
+
+var _ = Describe("Environment variables validation", func() {
+	When("an environment variable is given", func() {
+		It("detects if it is valid", func() {
+			Expect(isReservedEnvironmentVariable("PGDATA")).To(BeTrue())
+		})
+
+		It("detects if it is not valid", func() {
+			Expect(isReservedEnvironmentVariable("LC_ALL")).To(BeFalse())
+		})
+	})
+
+	When("a ClusterSpec is given", func() {
+		It("detects if the environment variable list is correct", func() {
+			cluster := Cluster{
+				Spec: ClusterSpec{
+					Env: []corev1.EnvVar{
+						{
+							Name:  "TZ",
+							Value: "Europe/Rome",
+						},
+					},
+				},
+			}
+
+			Expect(cluster.validateEnv()).To(BeEmpty())
+		})
+
+		It("detects if the environment variable list contains a reserved variable", func() {
+			cluster := Cluster{
+				Spec: ClusterSpec{
+					Env: []corev1.EnvVar{
+						{
+							Name:  "TZ",
+							Value: "Europe/Rome",
+						},
+						{
+							Name:  "PGDATA",
+							Value: "/tmp",
+						},
+					},
+				},
+			}
+
+			Expect(cluster.validateEnv()).To(HaveLen(1))
+		})
+	})
+})
+
+var _ = Describe("pg_ident validation", func() {
+	It("accepts valid user name maps", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					PgIdent: []string{
+						"# certificate mappings",
+						"certmap client.example.com app",
+					},
+				},
 			},
-		},
-		CustomQueriesSecret: []SecretKeySelector{
-			{
-				LocalObjectReference: LocalObjectReference{Name: DefaultMonitoringConfigMapName},
-				Key:                  "test3",
+		}
+		Expect(cluster.validatePgIdent()).To(BeEmpty())
+	})
+
+	It("complains about invalid user name maps", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					PgIdent: []string{
+						"certmap client.example.com",
+						"certmap client.example.com app",
+						"local root postgres",
+					},
+				},
 			},
-		},
-	}}}
-	It("correctly set the default monitoring queries configmap when other metrics are already specified", func() {
-		modifiedCluster := testCluster.DeepCopy()
-		modifiedCluster.defaultMonitoringQueries(&configuration.Data{
-			MonitoringQueriesConfigmap: "test-configmap",
+		}
+		result := cluster.validatePgIdent()
+		Expect(result).To(HaveLen(2))
+		Expect(result[0].Field).To(Equal("spec.postgresql.pg_ident[0]"))
+		Expect(result[1].Field).To(Equal("spec.postgresql.pg_ident[2]"))
+	})
+})
+
+var _ = Describe("Storage configuration validation", func() {
+	When("a ClusterSpec is given", func() {
+		It("produces one error if storage is not set at all", func() {
+			cluster := Cluster{
+				Spec: ClusterSpec{
+					StorageConfiguration: StorageConfiguration{},
+				},
+			}
+			Expect(cluster.validateStorageSize()).To(HaveLen(1))
 		})
 
-		Expect(modifiedCluster.Spec.Monitoring).NotTo(BeNil())
-		Expect(modifiedCluster.Spec.Monitoring.CustomQueriesConfigMap).NotTo(BeEmpty())
-		Expect(modifiedCluster.Spec.Monitoring.CustomQueriesSecret).NotTo(BeEmpty())
-		Expect(modifiedCluster.Spec.Monitoring.CustomQueriesConfigMap).
-			To(ContainElement(ConfigMapKeySelector{
-				LocalObjectReference: LocalObjectReference{Name: DefaultMonitoringConfigMapName},
-				Key:                  "test2",
-			}))
+		It("succeeds if storage size is set", func() {
+			cluster := Cluster{
+				Spec: ClusterSpec{
+					StorageConfiguration: StorageConfiguration{
+						Size: "1G",
+					},
+				},
+			}
+			Expect(cluster.validateStorageSize()).To(BeEmpty())
+		})
 
-		Expect(modifiedCluster.Spec.Monitoring.CustomQueriesSecret).
-			To(BeEquivalentTo(testCluster.Spec.Monitoring.CustomQueriesSecret))
-		Expect(modifiedCluster.Spec.Monitoring.CustomQueriesConfigMap).
-			To(ContainElements(testCluster.Spec.Monitoring.CustomQueriesConfigMap))
-	})
-	It("correctly set the default monitoring queries secret when other metrics are already specified", func() {
-		modifiedCluster := testCluster.DeepCopy()
-		modifiedCluster.defaultMonitoringQueries(&configuration.Data{
-			MonitoringQueriesSecret: "test-secret",
+		It("succeeds if storage is not set but a pvc template specifies storage", func() {
+			cluster := Cluster{
+				Spec: ClusterSpec{
+					StorageConfiguration: StorageConfiguration{
+						PersistentVolumeClaimTemplate: &corev1.PersistentVolumeClaimSpec{
+							Resources: corev1.ResourceRequirements{
+								Requests: corev1.ResourceList{"storage": resource.MustParse("1Gi")},
+							},
+						},
+					},
+				},
+			}
+			Expect(cluster.validateStorageSize()).To(BeEmpty())
 		})
 
-		Expect(modifiedCluster.Spec.Monitoring).NotTo(BeNil())
-		Expect(modifiedCluster.Spec.Monitoring.CustomQueriesSecret).NotTo(BeEmpty())
-		Expect(modifiedCluster.Spec.Monitoring.CustomQueriesConfigMap).NotTo(BeEmpty())
-		Expect(modifiedCluster.Spec.Monitoring.CustomQueriesSecret).
-			To(ContainElement(SecretKeySelector{
-				LocalObjectReference: LocalObjectReference{Name: DefaultMonitoringSecretName},
-				Key:                  "test3",
-			}))
+		It("accepts valid storage class names and the filesystem volume mode", func() {
+			filesystem := corev1.PersistentVolumeFilesystem
+			cluster := Cluster{
+				Spec: ClusterSpec{
+					StorageConfiguration: StorageConfiguration{
+						StorageClass: pointer.String("fast-ssd.example.com"),
+						PersistentVolumeClaimTemplate: &corev1.PersistentVolumeClaimSpec{
+							VolumeMode: &filesystem,
+						},
+					},
+					WalStorage: &StorageConfiguration{
+						StorageClass: pointer.String(""),
+					},
+				},
+			}
+			Expect(cluster.validateStorageClassesAndModes()).To(BeEmpty())
+		})
+
+		It("rejects invalid storage class names", func() {
+			cluster := Cluster{
+				Spec: ClusterSpec{
+					StorageConfiguration: StorageConfiguration{
+						StorageClass: pointer.String("Fast_SSD"),
+					},
+				},
+			}
+			Expect(cluster.validateStorageClassesAndModes()).To(HaveLen(1))
+		})
+
+		It("rejects the block volume mode", func() {
+			block := corev1.PersistentVolumeBlock
+			cluster := Cluster{
+				Spec: ClusterSpec{
+					StorageConfiguration: StorageConfiguration{Size: "1Gi"},
+					Tablespaces: []TablespaceConfiguration{
+						{
+							Name: "data",
+							Storage: StorageConfiguration{
+								PersistentVolumeClaimTemplate: &corev1.PersistentVolumeClaimSpec{
+									VolumeMode: &block,
+								},
+							},
+						},
+					},
+				},
+			}
+			errs := cluster.validateStorageClassesAndModes()
+			Expect(errs).To(HaveLen(1))
+			Expect(errs[0].Field).To(Equal("spec.tablespaces[0].storage.pvcTemplate.volumeMode"))
+		})
+
+		It("accepts a relative subPath", func() {
+			cluster := Cluster{
+				Spec: ClusterSpec{
+					StorageConfiguration: StorageConfiguration{SubPath: "pgdata/cluster-example"},
+					WalStorage:           &StorageConfiguration{SubPath: "wal"},
+				},
+			}
+			Expect(cluster.validateStorageSubPaths()).To(BeEmpty())
+		})
+
+		It("rejects a subPath which leaves the volume", func() {
+			cluster := Cluster{
+				Spec: ClusterSpec{
+					StorageConfiguration: StorageConfiguration{SubPath: "/pgdata"},
+					WalStorage:           &StorageConfiguration{SubPath: "wal/../../other"},
+				},
+			}
+			errs := cluster.validateStorageSubPaths()
+			Expect(errs).To(HaveLen(2))
+			Expect(errs[0].Field).To(Equal("spec.storage.subPath"))
+			Expect(errs[1].Field).To(Equal("spec.walStorage.subPath"))
+		})
+
+		It("rejects changes to the subPath", func() {
+			oldCluster := Cluster{
+				Spec: ClusterSpec{
+					StorageConfiguration: StorageConfiguration{Size: "1Gi", SubPath: "pgdata"},
+				},
+			}
+			cluster := oldCluster.DeepCopy()
+			Expect(cluster.validateStorageChange(&oldCluster)).To(BeEmpty())
+
+			cluster.Spec.StorageConfiguration.SubPath = "data"
+			Expect(cluster.validateStorageChange(&oldCluster)).To(HaveLen(1))
+		})
+	})
+})
+
+var _ = Describe("parallel WAL restore", func() {
+	path := field.NewPath("spec", "backup", "barmanObjectStore", "wal")
+
+	It("restores one WAL file at a time by default", func() {
+		var wal *WalBackupConfiguration
+		Expect(wal.GetRestoreMaxParallel()).To(Equal(1))
+		Expect((&WalBackupConfiguration{}).GetRestoreMaxParallel()).To(Equal(1))
+	})
+
+	It("falls back to maxParallel, capped to the maximum", func() {
+		Expect((&WalBackupConfiguration{MaxParallel: 8}).GetRestoreMaxParallel()).To(Equal(8))
+		Expect((&WalBackupConfiguration{MaxParallel: 100}).GetRestoreMaxParallel()).
+			To(Equal(MaxWalRestoreParallel))
+	})
+
+	It("prefers restoreMaxParallel to maxParallel", func() {
+		wal := &WalBackupConfiguration{MaxParallel: 8, RestoreMaxParallel: 4}
+		Expect(wal.GetRestoreMaxParallel()).To(Equal(4))
+		Expect(wal.validateRestoreMaxParallel(path)).To(BeEmpty())
+	})
+
+	It("rejects values outside the allowed range", func() {
+		Expect((&WalBackupConfiguration{RestoreMaxParallel: 65}).validateRestoreMaxParallel(path)).To(HaveLen(1))
+		Expect((&WalBackupConfiguration{RestoreMaxParallel: -1}).validateRestoreMaxParallel(path)).To(HaveLen(1))
+	})
+
+	It("doesn't limit the number of WAL files archived in parallel", func() {
+		Expect((&WalBackupConfiguration{MaxParallel: 100}).validateRestoreMaxParallel(path)).To(BeEmpty())
+	})
+})
+
+var _ = Describe("zstd compression validation", func() {
+	path := field.NewPath("spec", "backup", "barmanObjectStore", "wal")
+
+	It("accepts a configuration without zstd tuning", func() {
+		wal := &WalBackupConfiguration{Compression: CompressionTypeZstd}
+		Expect(wal.validateZstdCompression(path)).To(BeEmpty())
+	})
+
+	It("accepts levels inside their range", func() {
+		wal := &WalBackupConfiguration{
+			Compression: CompressionTypeZstd,
+			Zstd:        &ZstdCompressionConfiguration{Level: pointer.Int(ZstdMaxLevel)},
+		}
+		Expect(wal.validateZstdCompression(path)).To(BeEmpty())
+	})
+
+	It("rejects out of range levels", func() {
+		wal := &WalBackupConfiguration{
+			Compression: CompressionTypeZstd,
+			Zstd:        &ZstdCompressionConfiguration{Level: pointer.Int(0)},
+		}
+		Expect(wal.validateZstdCompression(path)).To(HaveLen(1))
+
+		wal.Zstd.Level = pointer.Int(23)
+		Expect(wal.validateZstdCompression(path)).To(HaveLen(1))
+	})
+
+	It("rejects the zstd tuning with a different compression", func() {
+		wal := &WalBackupConfiguration{
+			Compression: CompressionTypeGzip,
+			Zstd:        &ZstdCompressionConfiguration{Level: pointer.Int(3)},
+		}
+		Expect(wal.validateZstdCompression(path)).To(HaveLen(1))
+	})
+
+	It("validates the base backup compression independently of the WAL one", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Backup: &BackupConfiguration{
+					BarmanObjectStore: &BarmanObjectStoreConfiguration{
+						BarmanCredentials: BarmanCredentials{
+							AWS: &S3Credentials{InheritFromIAMRole: true},
+						},
+						Data: &DataBackupConfiguration{
+							Compression: CompressionTypeZstd,
+							Zstd:        &ZstdCompressionConfiguration{Level: pointer.Int(19)},
+						},
+						Wal: &WalBackupConfiguration{},
+					},
+				},
+			},
+		}
+		Expect(cluster.validateBackupConfiguration()).To(BeEmpty())
+
+		cluster.Spec.Backup.BarmanObjectStore.Data.Compression = CompressionTypeGzip
+		cluster.Spec.Backup.BarmanObjectStore.Wal.Compression = CompressionTypeZstd
+		errs := cluster.validateBackupConfiguration()
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Field).To(Equal("spec.backup.barmanObjectStore.data.zstd"))
+	})
+})
+
+var _ = Describe("object store tags validation", func() {
+	path := field.NewPath("spec", "backup", "barmanObjectStore")
+
+	It("accepts valid tags and history tags", func() {
+		objectStore := &BarmanObjectStoreConfiguration{
+			Tags:        map[string]string{"retention": "expire", "environment": ""},
+			HistoryTags: map[string]string{"retention": "keep"},
+		}
+		Expect(objectStore.validateTags(path)).To(BeEmpty())
+	})
+
+	It("rejects empty or too long keys and values", func() {
+		objectStore := &BarmanObjectStoreConfiguration{
+			Tags: map[string]string{
+				"":                       "value",
+				strings.Repeat("k", 129): "value",
+				"key":                    strings.Repeat("v", 257),
+			},
+		}
+		errs := objectStore.validateTags(path)
+		Expect(errs).To(HaveLen(3))
+		Expect(errs[0].Field).To(Equal("spec.backup.barmanObjectStore.tags"))
+		Expect(errs[1].Field).To(Equal("spec.backup.barmanObjectStore.tags[key]"))
+	})
+
+	It("rejects commas in keys and values", func() {
+		objectStore := &BarmanObjectStoreConfiguration{
+			HistoryTags: map[string]string{"a,b": "value", "key": "a,b"},
+		}
+		errs := objectStore.validateTags(path)
+		Expect(errs).To(HaveLen(2))
+		Expect(errs[0].Field).To(Equal("spec.backup.barmanObjectStore.historyTags"))
+	})
+
+	It("rejects too many tags", func() {
+		tags := make(map[string]string)
+		for idx := 0; idx <= maxBarmanTags; idx++ {
+			tags["key"+strconv.Itoa(idx)] = "value"
+		}
+		Expect((&BarmanObjectStoreConfiguration{Tags: tags}).validateTags(path)).To(HaveLen(1))
+	})
+})
+
+var _ = Describe("WAL staging validation", func() {
+	path := field.NewPath("spec", "backup", "barmanObjectStore", "wal")
+	newCluster := func(staging *WalStagingConfiguration) *Cluster {
+		return &Cluster{
+			Spec: ClusterSpec{
+				Backup: &BackupConfiguration{
+					BarmanObjectStore: &BarmanObjectStoreConfiguration{
+						DestinationPath: "s3://primary/",
+						BarmanCredentials: BarmanCredentials{
+							AWS: &S3Credentials{InheritFromIAMRole: true},
+						},
+						Wal: &WalBackupConfiguration{Staging: staging},
+					},
+				},
+			},
+		}
+	}
+
+	It("uses the default limit of pending files", func() {
+		var staging *WalStagingConfiguration
+		Expect(staging.GetMaxPendingFiles()).To(Equal(DefaultWalStagingMaxPendingFiles))
+		Expect((&WalStagingConfiguration{MaxPendingFiles: 10}).GetMaxPendingFiles()).To(Equal(10))
+	})
+
+	It("is disabled by default", func() {
+		var wal *WalBackupConfiguration
+		Expect(wal.IsStagingEnabled()).To(BeFalse())
+		Expect((&WalBackupConfiguration{}).IsStagingEnabled()).To(BeFalse())
+		Expect(newCluster(nil).validateWalStaging(path)).To(BeEmpty())
+	})
+
+	It("accepts the staging directory in the backup object store", func() {
+		cluster := newCluster(&WalStagingConfiguration{MaxPendingFiles: 64})
+		Expect(cluster.validateWalStaging(path)).To(BeEmpty())
+	})
+
+	It("rejects a negative limit of pending files", func() {
+		cluster := newCluster(&WalStagingConfiguration{MaxPendingFiles: -1})
+		Expect(cluster.validateWalStaging(path)).To(HaveLen(1))
+	})
+
+	It("rejects the staging directory together with an archive library", func() {
+		cluster := newCluster(&WalStagingConfiguration{})
+		cluster.Spec.PostgresConfiguration.ArchiveLibrary = "pgbackrest"
+		Expect(cluster.validateWalStaging(path)).To(HaveLen(1))
+	})
+
+	It("rejects the staging directory in the other object stores", func() {
+		wal := &WalBackupConfiguration{Staging: &WalStagingConfiguration{}}
+		Expect(wal.validateStagingNotSupported(path)).To(HaveLen(1))
+		Expect((&WalBackupConfiguration{}).validateStagingNotSupported(path)).To(BeEmpty())
+	})
+})
+
+var _ = Describe("synchronous_commit validation", func() {
+	It("allows a local level without synchronous replication", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				SynchronousCommit: SynchronousCommitLocal,
+			},
+		}
+		Expect(cluster.validateSynchronousCommit()).To(BeEmpty())
+	})
+
+	It("rejects remote levels without synchronous replication", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				SynchronousCommit: SynchronousCommitRemoteApply,
+			},
+		}
+		Expect(cluster.validateSynchronousCommit()).To(HaveLen(1))
+	})
+
+	It("allows remote levels with synchronous replication", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				MinSyncReplicas:   1,
+				MaxSyncReplicas:   1,
+				SynchronousCommit: SynchronousCommitRemoteWrite,
+			},
+		}
+		Expect(cluster.validateSynchronousCommit()).To(BeEmpty())
+	})
+
+	It("rejects the level when also set as a parameter", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				SynchronousCommit: SynchronousCommitOff,
+				PostgresConfiguration: PostgresConfiguration{
+					Parameters: map[string]string{
+						"synchronous_commit": "on",
+					},
+				},
+			},
+		}
+		Expect(cluster.validateSynchronousCommit()).To(HaveLen(1))
+	})
+})
+
+var _ = Describe("replicas unavailable policy validation", func() {
+	It("allows the self-healing policy without synchronous replication", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				ReplicasUnavailablePolicy: ReplicasUnavailablePolicySelfHeal,
+			},
+		}
+		Expect(cluster.validateReplicasUnavailablePolicy()).To(BeEmpty())
+	})
+
+	It("rejects enforcing the synchronous replication without minSyncReplicas", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				MaxSyncReplicas:           1,
+				ReplicasUnavailablePolicy: ReplicasUnavailablePolicyEnforceSync,
+			},
+		}
+		Expect(cluster.validateReplicasUnavailablePolicy()).To(HaveLen(1))
+	})
 
-		Expect(modifiedCluster.Spec.Monitoring.CustomQueriesConfigMap).
-			To(BeEquivalentTo(testCluster.Spec.Monitoring.CustomQueriesConfigMap))
-		Expect(modifiedCluster.Spec.Monitoring.CustomQueriesSecret).
-			To(ContainElements(testCluster.Spec.Monitoring.CustomQueriesSecret))
+	It("allows enforcing the synchronous replication with minSyncReplicas", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				MinSyncReplicas:           1,
+				MaxSyncReplicas:           1,
+				ReplicasUnavailablePolicy: ReplicasUnavailablePolicyEnforceSync,
+			},
+		}
+		Expect(cluster.validateReplicasUnavailablePolicy()).To(BeEmpty())
 	})
 })
 
-var _ = Describe("validation of imports", func() {
-	It("rejects unrecognized import type", func() {
+var _ = Describe("object stores defined in the operator configuration", func() {
+	const objectStores = `
+shared:
+  destinationPath: s3://backups/
+  serverName: should-not-be-inherited
+  s3Credentials:
+    inheritFromIAMRole: true
+  wal:
+    compression: gzip
+  tags:
+    team: dba
+    env: production
+`
+
+	BeforeEach(func() {
+		configuration.Current.ObjectStores = objectStores
+		DeferCleanup(func() {
+			configuration.Current.ObjectStores = ""
+		})
+	})
+
+	It("uses the referenced object store as the base of the cluster one", func() {
 		cluster := &Cluster{
 			Spec: ClusterSpec{
-				Bootstrap: &BootstrapConfiguration{
-					InitDB: &BootstrapInitDB{
-						Database: "app",
-						Owner:    "app",
-						Import: &Import{
-							Type: "fooBar",
+				Backup: &BackupConfiguration{
+					ObjectStoreName: "shared",
+					BarmanObjectStore: &BarmanObjectStoreConfiguration{
+						DestinationPath: "s3://other-backups/",
+						Tags: map[string]string{
+							"env": "staging",
 						},
 					},
 				},
 			},
 		}
+		var sharedObjectStore BarmanObjectStoreConfiguration
+		Expect(configuration.Current.GetObjectStore("shared", &sharedObjectStore)).To(Succeed())
+		cluster.defaultObjectStore(&sharedObjectStore)
 
-		result := cluster.validateImport()
-		Expect(result).To(HaveLen(1))
+		objectStore := cluster.Spec.Backup.BarmanObjectStore
+		Expect(objectStore.DestinationPath).To(Equal("s3://other-backups/"))
+		Expect(objectStore.ServerName).To(BeEmpty())
+		Expect(objectStore.AWS).ToNot(BeNil())
+		Expect(objectStore.AWS.InheritFromIAMRole).To(BeTrue())
+		Expect(objectStore.Wal.Compression).To(BeEquivalentTo("gzip"))
+		Expect(objectStore.Tags).To(Equal(map[string]string{
+			"team": "dba",
+			"env":  "staging",
+		}))
+		Expect(cluster.validateObjectStoreName()).To(BeEmpty())
+		Expect(cluster.validateBackupConfiguration()).To(BeEmpty())
 	})
 
-	It("rejects microservice import with roles", func() {
+	It("creates the cluster object store when it's not specified", func() {
 		cluster := &Cluster{
 			Spec: ClusterSpec{
-				Bootstrap: &BootstrapConfiguration{
-					InitDB: &BootstrapInitDB{
-						Database: "app",
-						Owner:    "app",
-						Import: &Import{
-							Type:      MicroserviceSnapshotType,
-							Databases: []string{"foo"},
-							Roles:     []string{"bar"},
-						},
-					},
+				Backup: &BackupConfiguration{
+					ObjectStoreName: "shared",
 				},
 			},
 		}
-
-		result := cluster.validateImport()
-		Expect(result).To(HaveLen(1))
+		cluster.Default()
+		Expect(cluster.Spec.Backup.BarmanObjectStore).ToNot(BeNil())
+		Expect(cluster.Spec.Backup.BarmanObjectStore.DestinationPath).To(Equal("s3://backups/"))
 	})
 
-	It("rejects microservice import without exactly one database", func() {
+	It("complains if the referenced object store doesn't exist", func() {
 		cluster := &Cluster{
 			Spec: ClusterSpec{
-				Bootstrap: &BootstrapConfiguration{
-					InitDB: &BootstrapInitDB{
-						Database: "app",
-						Owner:    "app",
-						Import: &Import{
-							Type:      MicroserviceSnapshotType,
-							Databases: []string{"foo", "bar"},
-						},
-					},
+				Backup: &BackupConfiguration{
+					ObjectStoreName: "unknown",
 				},
 			},
 		}
+		cluster.Default()
+		Expect(cluster.Spec.Backup.BarmanObjectStore).To(BeNil())
 
-		result := cluster.validateImport()
-		Expect(result).To(HaveLen(1))
+		errs := cluster.validateObjectStoreName()
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Type).To(Equal(field.ErrorTypeNotFound))
 	})
 
-	It("rejects microservice import with a wildcard on the database name", func() {
+	It("complains if the object stores of the operator configuration can't be parsed", func() {
+		configuration.Current.ObjectStores = "- shared"
 		cluster := &Cluster{
 			Spec: ClusterSpec{
-				Bootstrap: &BootstrapConfiguration{
-					InitDB: &BootstrapInitDB{
-						Database: "app",
-						Owner:    "app",
-						Import: &Import{
-							Type:      MicroserviceSnapshotType,
-							Databases: []string{"*foo"},
-						},
-					},
+				Backup: &BackupConfiguration{
+					ObjectStoreName: "shared",
 				},
 			},
 		}
+		cluster.Default()
+		Expect(cluster.Spec.Backup.BarmanObjectStore).To(BeNil())
 
-		result := cluster.validateImport()
-		Expect(result).To(HaveLen(1))
+		errs := cluster.validateObjectStoreName()
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Type).To(Equal(field.ErrorTypeInvalid))
 	})
+})
 
-	It("accepts microservice import when well specified", func() {
-		cluster := &Cluster{
+var _ = Describe("service account validation", func() {
+	It("accepts clusters using the generated service account", func() {
+		cluster := Cluster{}
+		Expect(cluster.validateServiceAccount()).To(BeEmpty())
+		Expect(cluster.GetServiceAccountName()).To(Equal(cluster.Name))
+	})
+
+	It("accepts an existing service account", func() {
+		cluster := Cluster{
 			Spec: ClusterSpec{
-				Bootstrap: &BootstrapConfiguration{
-					InitDB: &BootstrapInitDB{
-						Database: "app",
-						Owner:    "app",
-						Import: &Import{
-							Type:      MicroserviceSnapshotType,
-							Databases: []string{"foo"},
-						},
-					},
+				ServiceAccountName: "existing-sa",
+			},
+		}
+		Expect(cluster.validateServiceAccount()).To(BeEmpty())
+		Expect(cluster.GetServiceAccountName()).To(Equal("existing-sa"))
+	})
+
+	It("rejects an invalid service account name", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				ServiceAccountName: "Existing_SA",
+			},
+		}
+		Expect(cluster.validateServiceAccount()).ToNot(BeEmpty())
+	})
+
+	It("rejects an existing service account together with a template", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				ServiceAccountName:     "existing-sa",
+				ServiceAccountTemplate: &ServiceAccountTemplate{},
+			},
+		}
+		Expect(cluster.validateServiceAccount()).To(HaveLen(1))
+	})
+
+	It("rejects changes to the service account", func() {
+		oldCluster := Cluster{}
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				ServiceAccountName: "existing-sa",
+			},
+		}
+		Expect(cluster.validateServiceAccountChange(&oldCluster)).To(HaveLen(1))
+		Expect(oldCluster.validateServiceAccountChange(&oldCluster)).To(BeEmpty())
+	})
+})
+
+var _ = Describe("maintenance window validation", func() {
+	It("accepts a valid maintenance window", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				MaintenanceWindow: &MaintenanceWindowConfiguration{
+					TimeZone: "Europe/Rome",
+					Windows:  []MaintenanceTimeRange{{Days: []MaintenanceWeekday{"Saturday"}, Start: "22:00", End: "02:00"}},
 				},
 			},
 		}
+		Expect(cluster.validateMaintenanceWindow()).To(BeEmpty())
+	})
 
-		result := cluster.validateImport()
-		Expect(result).To(BeEmpty())
+	It("rejects unknown time zones", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				MaintenanceWindow: &MaintenanceWindowConfiguration{
+					TimeZone: "Europe/Atlantis",
+					Windows:  []MaintenanceTimeRange{{Start: "02:00", End: "04:00"}},
+				},
+			},
+		}
+		Expect(cluster.validateMaintenanceWindow()).To(HaveLen(1))
 	})
 
-	It("rejects monolith import with no databases", func() {
-		cluster := &Cluster{
+	It("rejects empty time ranges", func() {
+		cluster := Cluster{
 			Spec: ClusterSpec{
-				Bootstrap: &BootstrapConfiguration{
-					InitDB: &BootstrapInitDB{
-						Database: "app",
-						Owner:    "app",
-						Import: &Import{
-							Type:      MonolithSnapshotType,
-							Databases: []string{},
-						},
-					},
+				MaintenanceWindow: &MaintenanceWindowConfiguration{
+					Windows: []MaintenanceTimeRange{{Start: "02:00", End: "02:00"}},
 				},
 			},
 		}
+		Expect(cluster.validateMaintenanceWindow()).To(HaveLen(1))
+	})
+})
 
-		result := cluster.validateImport()
-		Expect(result).To(HaveLen(1))
+var _ = Describe("scheduled restart validation", func() {
+	It("accepts clusters without a scheduled restart", func() {
+		cluster := Cluster{}
+		Expect(cluster.validateScheduledRestart()).To(BeEmpty())
 	})
 
-	It("rejects monolith import with PostImport Application SQL", func() {
-		cluster := &Cluster{
+	It("accepts a valid schedule", func() {
+		cluster := Cluster{
 			Spec: ClusterSpec{
-				Bootstrap: &BootstrapConfiguration{
-					InitDB: &BootstrapInitDB{
-						Database: "app",
-						Owner:    "app",
-						Import: &Import{
-							Type:                     MonolithSnapshotType,
-							Databases:                []string{"foo"},
-							PostImportApplicationSQL: []string{"select * from bar"},
-						},
-					},
+				ScheduledRestart: &ScheduledRestartConfiguration{Schedule: "0 3 * * 0"},
+			},
+		}
+		Expect(cluster.validateScheduledRestart()).To(BeEmpty())
+	})
+
+	It("rejects an invalid schedule", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				ScheduledRestart: &ScheduledRestartConfiguration{Schedule: "every sunday"},
+			},
+		}
+		Expect(cluster.validateScheduledRestart()).To(HaveLen(1))
+	})
+})
+
+var _ = Describe("archive library validation", func() {
+	It("accepts an empty archive library", func() {
+		cluster := Cluster{}
+		Expect(cluster.validateArchiveLibrary()).To(BeEmpty())
+	})
+
+	It("accepts library names and paths", func() {
+		for _, library := range []string{"basic_archive", "$libdir/basic_archive", "/usr/lib/pg_backrest.so"} {
+			cluster := Cluster{
+				Spec: ClusterSpec{
+					PostgresConfiguration: PostgresConfiguration{ArchiveLibrary: library},
+				},
+			}
+			Expect(cluster.validateArchiveLibrary()).To(BeEmpty(), library)
+		}
+	})
+
+	It("rejects names containing quotes or spaces", func() {
+		for _, library := range []string{"basic archive", "basic'_archive", "lib;rm"} {
+			cluster := Cluster{
+				Spec: ClusterSpec{
+					PostgresConfiguration: PostgresConfiguration{ArchiveLibrary: library},
+				},
+			}
+			Expect(cluster.validateArchiveLibrary()).To(HaveLen(1), library)
+		}
+	})
+})
+
+var _ = Describe("publications validation", func() {
+	It("accepts publications of every table or of a list of tables", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Publications: []PublicationConfiguration{
+					{Name: "everything", DBName: "app", AllTables: true},
+					{Name: "orders", DBName: "app", Tables: []string{"sales.orders"}},
+					{Name: "orders", DBName: "other", Tables: []string{"sales.orders"}},
 				},
 			},
 		}
+		Expect(cluster.validatePublications()).To(BeEmpty())
+	})
 
-		result := cluster.validateImport()
-		Expect(result).To(HaveLen(1))
+	It("rejects publications declared twice in the same database", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Publications: []PublicationConfiguration{
+					{Name: "orders", DBName: "app", AllTables: true},
+					{Name: "orders", DBName: "app", Tables: []string{"sales.orders"}},
+				},
+			},
+		}
+		Expect(cluster.validatePublications()).To(HaveLen(1))
 	})
 
-	It("rejects monolith import with wildcards alongside specific values", func() {
-		cluster := &Cluster{
+	It("requires either allTables or a list of tables", func() {
+		cluster := Cluster{
 			Spec: ClusterSpec{
-				Bootstrap: &BootstrapConfiguration{
-					InitDB: &BootstrapInitDB{
-						Database: "app",
-						Owner:    "app",
-						Import: &Import{
-							Type:      MonolithSnapshotType,
-							Databases: []string{"bar", "*"},
-						},
-					},
+				Publications: []PublicationConfiguration{
+					{Name: "empty", DBName: "app"},
+					{Name: "both", DBName: "app", AllTables: true, Tables: []string{"sales.orders"}},
 				},
 			},
 		}
+		Expect(cluster.validatePublications()).To(HaveLen(2))
+	})
 
-		result := cluster.validateImport()
-		Expect(result).To(HaveLen(1))
+	It("rejects tables listed twice", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Publications: []PublicationConfiguration{
+					{Name: "orders", DBName: "app", Tables: []string{"sales.orders", "sales.orders"}},
+				},
+			},
+		}
+		Expect(cluster.validatePublications()).To(HaveLen(1))
+	})
+})
 
-		cluster = &Cluster{
+var _ = Describe("extensions validation", func() {
+	It("accepts extensions created in databases or only preloaded", func() {
+		cluster := Cluster{
 			Spec: ClusterSpec{
-				Bootstrap: &BootstrapConfiguration{
-					InitDB: &BootstrapInitDB{
-						Database: "app",
-						Owner:    "app",
-						Import: &Import{
-							Type:      MonolithSnapshotType,
-							Databases: []string{"foo"},
-							Roles:     []string{"baz", "*"},
+				Extensions: []ExtensionConfiguration{
+					{Name: "pg_stat_statements", DBNames: []string{"app", "postgres"}},
+					{Name: "auto_explain"},
+					{Name: "pg_cron", DBNames: []string{"postgres"}, SharedPreloadLibraries: []string{"pg_cron"}},
+				},
+			},
+		}
+		Expect(cluster.validateExtensions()).To(BeEmpty())
+	})
+
+	It("rejects extensions declared twice", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Extensions: []ExtensionConfiguration{
+					{Name: "hstore", DBNames: []string{"app"}},
+					{Name: "hstore", DBNames: []string{"postgres"}},
+				},
+			},
+		}
+		Expect(cluster.validateExtensions()).To(HaveLen(1))
+	})
+
+	It("rejects databases listed twice", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Extensions: []ExtensionConfiguration{
+					{Name: "hstore", DBNames: []string{"app", "app"}},
+				},
+			},
+		}
+		Expect(cluster.validateExtensions()).To(HaveLen(1))
+	})
+
+	It("rejects the creation of the extensions which can only be preloaded", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Extensions: []ExtensionConfiguration{
+					{Name: "auto_explain", DBNames: []string{"app"}},
+				},
+			},
+		}
+		Expect(cluster.validateExtensions()).To(HaveLen(1))
+	})
+
+	It("rejects invalid library names", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Extensions: []ExtensionConfiguration{
+					{Name: "pg_cron", SharedPreloadLibraries: []string{"", "pg_cron,pgaudit", "pg'cron"}},
+				},
+			},
+		}
+		Expect(cluster.validateExtensions()).To(HaveLen(3))
+	})
+})
+
+var _ = Describe("autovacuum validation", func() {
+	It("accepts valid settings", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					Autovacuum: &AutovacuumConfiguration{
+						VacuumScaleFactor: "0.05",
+						Tables: []AutovacuumTableConfiguration{
+							{
+								DBName: "app",
+								Name:   "sales.orders",
+								Parameters: map[string]string{
+									"autovacuum_vacuum_scale_factor":     "0.01",
+									"toast.autovacuum_vacuum_cost_limit": "2000",
+								},
+							},
 						},
 					},
 				},
 			},
 		}
-
-		result = cluster.validateImport()
-		Expect(result).To(HaveLen(1))
+		Expect(cluster.validateAutovacuum()).To(BeEmpty())
 	})
 
-	It("accepts monolith import with proper values", func() {
-		cluster := &Cluster{
+	It("rejects scale factors out of range", func() {
+		cluster := Cluster{
 			Spec: ClusterSpec{
-				Bootstrap: &BootstrapConfiguration{
-					InitDB: &BootstrapInitDB{
-						Database: "app",
-						Owner:    "app",
-						Import: &Import{
-							Type:      MonolithSnapshotType,
-							Databases: []string{"foo"},
-						},
+				PostgresConfiguration: PostgresConfiguration{
+					Autovacuum: &AutovacuumConfiguration{
+						VacuumScaleFactor:  "-1",
+						AnalyzeScaleFactor: "a lot",
 					},
 				},
 			},
 		}
+		Expect(cluster.validateAutovacuum()).To(HaveLen(2))
+	})
 
-		result := cluster.validateImport()
-		Expect(result).To(BeEmpty())
+	It("rejects settings also specified in the parameters", func() {
+		maxWorkers := int32(6)
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					Parameters: map[string]string{"autovacuum_max_workers": "4"},
+					Autovacuum: &AutovacuumConfiguration{MaxWorkers: &maxWorkers},
+				},
+			},
+		}
+		Expect(cluster.validateAutovacuum()).To(HaveLen(1))
 	})
 
-	It("accepts monolith import with wildcards", func() {
-		cluster := &Cluster{
+	It("rejects invalid storage parameters and duplicated tables", func() {
+		cluster := Cluster{
 			Spec: ClusterSpec{
-				Bootstrap: &BootstrapConfiguration{
-					InitDB: &BootstrapInitDB{
-						Database: "app",
-						Owner:    "app",
-						Import: &Import{
-							Type:      MonolithSnapshotType,
-							Databases: []string{"*"},
-							Roles:     []string{"*"},
+				PostgresConfiguration: PostgresConfiguration{
+					Autovacuum: &AutovacuumConfiguration{
+						Tables: []AutovacuumTableConfiguration{
+							{DBName: "app", Name: "orders", Parameters: map[string]string{"fillfactor": "70"}},
+							{DBName: "app", Name: "orders", Parameters: map[string]string{
+								"autovacuum_vacuum_cost_limit": "0",
+							}},
 						},
 					},
 				},
 			},
 		}
-
-		result := cluster.validateImport()
-		Expect(result).To(BeEmpty())
+		Expect(cluster.validateAutovacuum()).To(HaveLen(3))
 	})
 })
 
-var _ = Describe("validation of replication slots configuration", func() {
-	It("prevents using replication slots on PostgreSQL 10 and older", func() {
-		cluster := &Cluster{
+var _ = Describe("checkpoint validation", func() {
+	newCluster := func(checkpoint *CheckpointConfiguration) *Cluster {
+		return &Cluster{
 			Spec: ClusterSpec{
-				ImageName: "ghcr.io/cloudnative-pg/postgresql:10.5",
-				ReplicationSlots: &ReplicationSlotsConfiguration{
-					HighAvailability: &ReplicationSlotsHAConfiguration{
-						Enabled: true,
-					},
-					UpdateInterval: 0,
+				StorageConfiguration: StorageConfiguration{Size: "10Gi"},
+				PostgresConfiguration: PostgresConfiguration{
+					Checkpoint: checkpoint,
 				},
 			},
 		}
-		cluster.Default()
+	}
 
-		result := cluster.validateReplicationSlots()
-		Expect(result).To(HaveLen(1))
+	It("accepts valid settings", func() {
+		maxWalSize := resource.MustParse("4Gi")
+		minWalSize := resource.MustParse("1Gi")
+		cluster := newCluster(&CheckpointConfiguration{
+			MaxWalSize:       &maxWalSize,
+			MinWalSize:       &minWalSize,
+			CompletionTarget: "0.9",
+		})
+		Expect(cluster.validateCheckpoint()).To(BeEmpty())
 	})
 
-	It("can be enabled on the default PostgreSQL image", func() {
-		cluster := &Cluster{
+	It("rejects a completion target out of range", func() {
+		Expect(newCluster(&CheckpointConfiguration{CompletionTarget: "1.5"}).validateCheckpoint()).To(HaveLen(1))
+		Expect(newCluster(&CheckpointConfiguration{CompletionTarget: "soon"}).validateCheckpoint()).To(HaveLen(1))
+	})
+
+	It("rejects a minWalSize greater than maxWalSize", func() {
+		maxWalSize := resource.MustParse("2Gi")
+		minWalSize := resource.MustParse("3Gi")
+		cluster := newCluster(&CheckpointConfiguration{MaxWalSize: &maxWalSize, MinWalSize: &minWalSize})
+		Expect(cluster.validateCheckpoint()).To(HaveLen(1))
+	})
+
+	It("compares the sizes with the PostgreSQL defaults", func() {
+		maxWalSize := resource.MustParse("64Mi")
+		Expect(newCluster(&CheckpointConfiguration{MaxWalSize: &maxWalSize}).validateCheckpoint()).To(HaveLen(1))
+
+		minWalSize := resource.MustParse("2Gi")
+		Expect(newCluster(&CheckpointConfiguration{MinWalSize: &minWalSize}).validateCheckpoint()).To(HaveLen(1))
+	})
+
+	It("requires at least two WAL segments", func() {
+		minWalSize := resource.MustParse("32Mi")
+		cluster := newCluster(&CheckpointConfiguration{MinWalSize: &minWalSize})
+		Expect(cluster.validateCheckpoint()).To(BeEmpty())
+
+		cluster.Spec.Bootstrap = &BootstrapConfiguration{InitDB: &BootstrapInitDB{WalSegmentSize: 64}}
+		Expect(cluster.validateCheckpoint()).To(HaveLen(1))
+	})
+
+	It("requires maxWalSize to be smaller than the volume holding pg_wal", func() {
+		maxWalSize := resource.MustParse("20Gi")
+		cluster := newCluster(&CheckpointConfiguration{MaxWalSize: &maxWalSize})
+		Expect(cluster.validateCheckpoint()).To(HaveLen(1))
+
+		cluster.Spec.WalStorage = &StorageConfiguration{Size: "50Gi"}
+		Expect(cluster.validateCheckpoint()).To(BeEmpty())
+
+		cluster.Spec.WalStorage.Size = "20Gi"
+		Expect(cluster.validateCheckpoint()).To(HaveLen(1))
+	})
+
+	It("rejects settings also specified in the parameters", func() {
+		timeout := int32(600)
+		cluster := newCluster(&CheckpointConfiguration{Timeout: &timeout})
+		cluster.Spec.PostgresConfiguration.Parameters = map[string]string{"checkpoint_timeout": "300"}
+		Expect(cluster.validateCheckpoint()).To(HaveLen(1))
+	})
+})
+
+var _ = Describe("sidecars validation", func() {
+	configVolume := corev1.Volume{
+		Name: "agent-config",
+		VolumeSource: corev1.VolumeSource{
+			ConfigMap: &corev1.ConfigMapVolumeSource{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "agent-config"},
+			},
+		},
+	}
+
+	It("accepts sidecars mounting their volumes and the operator ones in read-only mode", func() {
+		cluster := Cluster{
 			Spec: ClusterSpec{
-				ImageName: versions.DefaultImageName,
-				ReplicationSlots: &ReplicationSlotsConfiguration{
-					HighAvailability: &ReplicationSlotsHAConfiguration{
-						Enabled: true,
+				Sidecars: []corev1.Container{
+					{
+						Name:  "log-agent",
+						Image: "fluent-bit:2.1",
+						VolumeMounts: []corev1.VolumeMount{
+							{Name: "agent-config", MountPath: "/etc/agent"},
+							{Name: "pgdata", MountPath: "/pgdata", ReadOnly: true},
+						},
 					},
-					UpdateInterval: 0,
 				},
+				SidecarVolumes: []corev1.Volume{configVolume},
 			},
 		}
-		cluster.Default()
-
-		result := cluster.validateReplicationSlots()
-		Expect(result).To(BeEmpty())
+		Expect(cluster.validateSidecars()).To(BeEmpty())
 	})
 
-	It("allows enabling replication slots on the fly", func() {
-		oldCluster := &Cluster{
+	It("rejects the names of the containers and of the volumes of the operator", func() {
+		cluster := Cluster{
 			Spec: ClusterSpec{
-				ImageName: versions.DefaultImageName,
+				Sidecars: []corev1.Container{{Name: "postgres", Image: "busybox"}},
+				SidecarVolumes: []corev1.Volume{
+					{Name: "pgdata"},
+					{Name: "tbs-indexes"},
+				},
 			},
 		}
-		oldCluster.Default()
+		Expect(cluster.validateSidecars()).To(HaveLen(3))
+	})
 
-		newCluster := oldCluster.DeepCopy()
-		newCluster.Spec.ReplicationSlots = &ReplicationSlotsConfiguration{
-			HighAvailability: &ReplicationSlotsHAConfiguration{
-				Enabled:    true,
-				SlotPrefix: "_test_",
+	It("rejects duplicated names and sidecars without an image", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Sidecars: []corev1.Container{
+					{Name: "log-agent", Image: "fluent-bit:2.1"},
+					{Name: "log-agent"},
+				},
+				SidecarVolumes: []corev1.Volume{configVolume, configVolume},
 			},
 		}
-
-		Expect(newCluster.validateReplicationSlotsChange(oldCluster)).To(BeEmpty())
+		Expect(cluster.validateSidecars()).To(HaveLen(3))
 	})
 
-	It("prevents changing the slot prefix while replication slots are enabled", func() {
-		oldCluster := &Cluster{
+	It("rejects mounts of unknown volumes and writable mounts of the operator volumes", func() {
+		cluster := Cluster{
 			Spec: ClusterSpec{
-				ImageName: versions.DefaultImageName,
-				ReplicationSlots: &ReplicationSlotsConfiguration{
-					HighAvailability: &ReplicationSlotsHAConfiguration{
-						Enabled:    true,
-						SlotPrefix: "_test_",
+				Sidecars: []corev1.Container{
+					{
+						Name:  "log-agent",
+						Image: "fluent-bit:2.1",
+						VolumeMounts: []corev1.VolumeMount{
+							{Name: "agent-config", MountPath: "/etc/agent"},
+							{Name: "pgdata", MountPath: "/pgdata"},
+						},
 					},
 				},
 			},
 		}
-		oldCluster.Default()
-
-		newCluster := oldCluster.DeepCopy()
-		newCluster.Spec.ReplicationSlots.HighAvailability.SlotPrefix = "_toast_"
-		Expect(newCluster.validateReplicationSlotsChange(oldCluster)).To(HaveLen(1))
+		Expect(cluster.validateSidecars()).To(HaveLen(2))
 	})
+})
 
-	It("prevents removing the replication slot section when replication slots are enabled", func() {
-		oldCluster := &Cluster{
+var _ = Describe("mounted objects validation", func() {
+	newCluster := func(references ...MountedObjectReference) Cluster {
+		return Cluster{
 			Spec: ClusterSpec{
-				ImageName: versions.DefaultImageName,
-				ReplicationSlots: &ReplicationSlotsConfiguration{
-					HighAvailability: &ReplicationSlotsHAConfiguration{
-						Enabled:    true,
-						SlotPrefix: "_test_",
+				ProjectedVolumeTemplate: &corev1.ProjectedVolumeSource{
+					Sources: []corev1.VolumeProjection{
+						{
+							Secret: &corev1.SecretProjection{
+								LocalObjectReference: corev1.LocalObjectReference{Name: "ldap-ca"},
+							},
+						},
+					},
+				},
+				SidecarVolumes: []corev1.Volume{
+					{
+						Name: "agent-config",
+						VolumeSource: corev1.VolumeSource{
+							ConfigMap: &corev1.ConfigMapVolumeSource{
+								LocalObjectReference: corev1.LocalObjectReference{Name: "agent-config"},
+							},
+						},
 					},
 				},
+				MountedObjects: references,
 			},
 		}
-		oldCluster.Default()
+	}
 
-		newCluster := oldCluster.DeepCopy()
-		newCluster.Spec.ReplicationSlots = nil
-		Expect(newCluster.validateReplicationSlotsChange(oldCluster)).To(HaveLen(1))
+	It("accepts the objects mounted through the projected volume or the sidecar volumes", func() {
+		cluster := newCluster(
+			MountedObjectReference{Kind: "Secret", Name: "ldap-ca"},
+			MountedObjectReference{Kind: "ConfigMap", Name: "agent-config", OnChange: MountedObjectChangeReload},
+		)
+		Expect(cluster.validateMountedObjects()).To(BeEmpty())
 	})
 
-	It("allows disabling the replication slots", func() {
-		oldCluster := &Cluster{
-			Spec: ClusterSpec{
-				ImageName: versions.DefaultImageName,
-				ReplicationSlots: &ReplicationSlotsConfiguration{
-					HighAvailability: &ReplicationSlotsHAConfiguration{
-						Enabled:    true,
-						SlotPrefix: "_test_",
-					},
-				},
-			},
+	It("rejects the objects which are not mounted", func() {
+		cluster := newCluster(
+			MountedObjectReference{Kind: "ConfigMap", Name: "ldap-ca"},
+			MountedObjectReference{Kind: "Secret", Name: "missing"},
+		)
+		Expect(cluster.validateMountedObjects()).To(HaveLen(2))
+	})
+
+	It("rejects duplicated references", func() {
+		cluster := newCluster(
+			MountedObjectReference{Kind: "Secret", Name: "ldap-ca"},
+			MountedObjectReference{Kind: "Secret", Name: "ldap-ca", OnChange: MountedObjectChangeReload},
+		)
+		Expect(cluster.validateMountedObjects()).To(HaveLen(1))
+	})
+})
+
+var _ = Describe("service naming validation", func() {
+	newCluster := func(naming *ServiceNamingConfiguration) *Cluster {
+		return &Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"},
+			Spec:       ClusterSpec{ServiceNaming: naming},
 		}
-		oldCluster.Default()
+	}
 
-		newCluster := oldCluster.DeepCopy()
-		newCluster.Spec.ReplicationSlots.HighAvailability.Enabled = false
-		Expect(newCluster.validateReplicationSlotsChange(oldCluster)).To(BeEmpty())
+	It("accepts the default naming scheme", func() {
+		Expect(newCluster(nil).validateServiceNaming()).To(BeEmpty())
+	})
+
+	It("accepts a valid prefix and suffix", func() {
+		cluster := newCluster(&ServiceNamingConfiguration{Prefix: "tenant-a-", Suffix: "-pg"})
+		Expect(cluster.validateServiceNaming()).To(BeEmpty())
+		Expect(cluster.GetServiceReadWriteName()).To(Equal("tenant-a-cluster-example-pg-rw"))
+		Expect(cluster.GetServiceReadOnlyName()).To(Equal("tenant-a-cluster-example-pg-ro"))
+		Expect(cluster.GetServiceReadName()).To(Equal("tenant-a-cluster-example-pg-r"))
+		Expect(cluster.GetServiceAnyName()).To(Equal("cluster-example-any"))
+	})
+
+	It("rejects names which are not valid DNS labels", func() {
+		Expect(newCluster(&ServiceNamingConfiguration{Suffix: ".tenant"}).validateServiceNaming()).
+			To(HaveLen(3))
+		Expect(newCluster(&ServiceNamingConfiguration{Prefix: "1-"}).validateServiceNaming()).
+			To(HaveLen(3))
+	})
+
+	It("rejects names which are too long", func() {
+		cluster := newCluster(&ServiceNamingConfiguration{Suffix: "-" + strings.Repeat("x", 50)})
+		Expect(cluster.validateServiceNaming()).To(HaveLen(3))
 	})
 })
 
-var _ = Describe("Environment variables validation", func() {
-	When("an environment variable is given", func() {
-		It("detects if it is valid", func() {
-			Expect(isReservedEnvironmentVariable("PGDATA")).To(BeTrue())
-		})
+var _ = Describe("wal_keep_size validation", func() {
+	newCluster := func(walKeepSize string) *Cluster {
+		size := resource.MustParse(walKeepSize)
+		return &Cluster{
+			Spec: ClusterSpec{
+				ImageName:            versions.DefaultImageName,
+				WalKeepSize:          &size,
+				StorageConfiguration: StorageConfiguration{Size: "10Gi"},
+			},
+		}
+	}
 
-		It("detects if it is not valid", func() {
-			Expect(isReservedEnvironmentVariable("LC_ALL")).To(BeFalse())
-		})
+	It("accepts a size smaller than the WAL volume", func() {
+		cluster := newCluster("2Gi")
+		Expect(cluster.validateWalKeepSize()).To(BeEmpty())
+		Expect(cluster.GetWalKeepSize()).To(Equal("2048MB"))
 	})
 
-	When("a ClusterSpec is given", func() {
-		It("detects if the environment variable list is correct", func() {
-			cluster := Cluster{
-				Spec: ClusterSpec{
-					Env: []corev1.EnvVar{
-						{
-							Name:  "TZ",
-							Value: "Europe/Rome",
-						},
-					},
-				},
-			}
+	It("rounds the size up to the megabyte", func() {
+		Expect(newCluster("1500k").GetWalKeepSize()).To(Equal("2MB"))
+	})
 
-			Expect(cluster.validateEnv()).To(BeEmpty())
-		})
+	It("rejects a size which doesn't fit in the WAL volume", func() {
+		cluster := newCluster("10Gi")
+		Expect(cluster.validateWalKeepSize()).To(HaveLen(1))
 
-		It("detects if the environment variable list contains a reserved variable", func() {
-			cluster := Cluster{
-				Spec: ClusterSpec{
-					Env: []corev1.EnvVar{
-						{
-							Name:  "TZ",
-							Value: "Europe/Rome",
-						},
-						{
-							Name:  "PGDATA",
-							Value: "/tmp",
-						},
-					},
-				},
-			}
+		cluster.Spec.WalStorage = &StorageConfiguration{Size: "20Gi"}
+		Expect(cluster.validateWalKeepSize()).To(BeEmpty())
+	})
 
-			Expect(cluster.validateEnv()).To(HaveLen(1))
-		})
+	It("rejects a size which is not positive", func() {
+		Expect(newCluster("0").validateWalKeepSize()).To(HaveLen(1))
+	})
+
+	It("prevents setting the size twice", func() {
+		cluster := newCluster("1Gi")
+		cluster.Spec.PostgresConfiguration.Parameters = map[string]string{"wal_keep_size": "1GB"}
+		Expect(cluster.validateWalKeepSize()).To(HaveLen(1))
+	})
+
+	It("rejects PostgreSQL 12 and older", func() {
+		cluster := newCluster("1Gi")
+		cluster.Spec.ImageName = "ghcr.io/cloudnative-pg/postgresql:12.8"
+		Expect(cluster.validateWalKeepSize()).To(HaveLen(1))
 	})
 })
 
-var _ = Describe("Storage configuration validation", func() {
-	When("a ClusterSpec is given", func() {
-		It("produces one error if storage is not set at all", func() {
-			cluster := Cluster{
-				Spec: ClusterSpec{
-					StorageConfiguration: StorageConfiguration{},
-				},
-			}
-			Expect(cluster.validateStorageSize()).To(HaveLen(1))
+var _ = Describe("credentials namespace validation", func() {
+	BeforeEach(func() {
+		configuration.Current.CredentialsNamespaces = "credentials"
+		DeferCleanup(func() {
+			configuration.Current.CredentialsNamespaces = ""
 		})
+	})
 
-		It("succeeds if storage size is set", func() {
-			cluster := Cluster{
-				Spec: ClusterSpec{
-					StorageConfiguration: StorageConfiguration{
-						Size: "1G",
-					},
-				},
-			}
-			Expect(cluster.validateStorageSize()).To(BeEmpty())
-		})
+	newCluster := func(credentialsNamespace string) *Cluster {
+		return &Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "tenant-a"},
+			Spec:       ClusterSpec{CredentialsNamespace: credentialsNamespace},
+		}
+	}
 
-		It("succeeds if storage is not set but a pvc template specifies storage", func() {
-			cluster := Cluster{
-				Spec: ClusterSpec{
-					StorageConfiguration: StorageConfiguration{
-						PersistentVolumeClaimTemplate: &corev1.PersistentVolumeClaimSpec{
-							Resources: corev1.ResourceRequirements{
-								Requests: corev1.ResourceList{"storage": resource.MustParse("1Gi")},
-							},
-						},
-					},
-				},
-			}
-			Expect(cluster.validateStorageSize()).To(BeEmpty())
-		})
+	It("accepts the clusters not copying the secrets", func() {
+		Expect(newCluster("").validateCredentialsNamespace()).To(BeEmpty())
+	})
+
+	It("accepts a namespace allowed by the operator configuration", func() {
+		Expect(newCluster("credentials").validateCredentialsNamespace()).To(BeEmpty())
+	})
+
+	It("rejects a namespace not allowed by the operator configuration", func() {
+		Expect(newCluster("kube-system").validateCredentialsNamespace()).To(HaveLen(1))
+	})
+
+	It("rejects the namespace of the cluster", func() {
+		Expect(newCluster("tenant-a").validateCredentialsNamespace()).To(HaveLen(1))
 	})
 })
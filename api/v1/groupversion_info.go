@@ -51,6 +51,9 @@ var (
 	// PoolerKind is the kind name of Poolers
 	PoolerKind = "Pooler"
 
+	// ScheduledMaintenanceKind is the kind name of ScheduledMaintenances
+	ScheduledMaintenanceKind = "ScheduledMaintenance"
+
 	// SchemeBuilder is used to add go types to the GroupVersionKind scheme
 	SchemeBuilder = &scheme.Builder{GroupVersion: GroupVersion}
 
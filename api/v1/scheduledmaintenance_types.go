@@ -0,0 +1,183 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// MaintenanceTarget describes which instance of the cluster should run
+// the statements of a scheduled maintenance
+type MaintenanceTarget string
+
+const (
+	// MaintenanceTargetPrimary means the statements are always executed
+	// on the primary instance
+	MaintenanceTargetPrimary MaintenanceTarget = "primary"
+
+	// MaintenanceTargetPreferStandby means the statements are executed on
+	// a standby instance, or on the primary if no standby is ready
+	MaintenanceTargetPreferStandby MaintenanceTarget = "prefer-standby"
+)
+
+// MaintenanceResult is the outcome of the latest execution of a
+// scheduled maintenance
+type MaintenanceResult string
+
+const (
+	// MaintenanceResultRunning means the latest execution is still in progress
+	MaintenanceResultRunning MaintenanceResult = "running"
+
+	// MaintenanceResultSucceeded means every statement of the latest
+	// execution has been successfully executed
+	MaintenanceResultSucceeded MaintenanceResult = "succeeded"
+
+	// MaintenanceResultFailed means the latest execution has been stopped
+	// by a failure
+	MaintenanceResultFailed MaintenanceResult = "failed"
+)
+
+// DefaultMaintenanceDatabase is the database where the statements of a
+// scheduled maintenance are executed when not specified
+const DefaultMaintenanceDatabase = "postgres"
+
+// ScheduledMaintenanceSpec defines the desired state of ScheduledMaintenance
+type ScheduledMaintenanceSpec struct {
+	// If this scheduled maintenance is suspended or not
+	Suspend *bool `json:"suspend,omitempty"`
+
+	// The schedule does not follow the same format used in Kubernetes CronJobs
+	// as it includes an additional seconds specifier,
+	// see https://pkg.go.dev/github.com/robfig/cron#hdr-CRON_Expression_Format
+	Schedule string `json:"schedule"`
+
+	// The cluster where the statements are executed
+	Cluster LocalObjectReference `json:"cluster"`
+
+	// The instance where the statements are executed, which can be
+	// `primary` (default) or `prefer-standby`. With `prefer-standby`
+	// the statements are executed on a standby through the read-only
+	// service, falling back to the primary if no standby is ready
+	// +kubebuilder:validation:Enum=primary;prefer-standby
+	// +kubebuilder:default:=primary
+	// +optional
+	Target MaintenanceTarget `json:"target,omitempty"`
+
+	// The database where the statements are executed (default: `postgres`)
+	// +optional
+	Database string `json:"database,omitempty"`
+
+	// The list of SQL statements to be executed, in order. Each statement
+	// runs in its own transaction, so commands like `VACUUM` are allowed,
+	// and the execution stops at the first failing statement
+	// +kubebuilder:validation:MinItems=1
+	SQL []string `json:"sql"`
+}
+
+// ScheduledMaintenanceStatus defines the observed state of ScheduledMaintenance
+type ScheduledMaintenanceStatus struct {
+	// The latest time the schedule
+	LastCheckTime *metav1.Time `json:"lastCheckTime,omitempty"`
+
+	// Information when was the last time that the maintenance was successfully scheduled.
+	// +optional
+	LastScheduleTime *metav1.Time `json:"lastScheduleTime,omitempty"`
+
+	// Next time we will run the maintenance
+	// +optional
+	NextScheduleTime *metav1.Time `json:"nextScheduleTime,omitempty"`
+
+	// The name of the Job running the latest execution
+	// +optional
+	LastJobName string `json:"lastJobName,omitempty"`
+
+	// The outcome of the latest execution
+	// +optional
+	LastResult MaintenanceResult `json:"lastResult,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:storageversion
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:printcolumn:name="Cluster",type="string",JSONPath=".spec.cluster.name"
+// +kubebuilder:printcolumn:name="Target",type="string",JSONPath=".spec.target"
+// +kubebuilder:printcolumn:name="Last Run",type="date",JSONPath=".status.lastScheduleTime"
+// +kubebuilder:printcolumn:name="Result",type="string",JSONPath=".status.lastResult"
+
+// ScheduledMaintenance is the Schema for the scheduledmaintenances API
+type ScheduledMaintenance struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Specification of the desired behavior of the ScheduledMaintenance.
+	// More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#spec-and-status
+	Spec ScheduledMaintenanceSpec `json:"spec,omitempty"`
+	// Most recently observed status of the ScheduledMaintenance. This data may not be up
+	// to date. Populated by the system. Read-only.
+	// More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#spec-and-status
+	Status ScheduledMaintenanceStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ScheduledMaintenanceList contains a list of ScheduledMaintenance
+type ScheduledMaintenanceList struct {
+	metav1.TypeMeta `json:",inline"`
+	// Standard list metadata.
+	// More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#types-kinds
+	metav1.ListMeta `json:"metadata,omitempty"`
+	// List of scheduled maintenances
+	Items []ScheduledMaintenance `json:"items"`
+}
+
+// IsSuspended check if a scheduled maintenance has been suspended or not
+func (scheduledMaintenance ScheduledMaintenance) IsSuspended() bool {
+	if scheduledMaintenance.Spec.Suspend == nil {
+		return false
+	}
+
+	return *scheduledMaintenance.Spec.Suspend
+}
+
+// GetSchedule get the cron-like schedule of this scheduled maintenance
+func (scheduledMaintenance *ScheduledMaintenance) GetSchedule() string {
+	return scheduledMaintenance.Spec.Schedule
+}
+
+// GetTarget gets the instance where the statements are executed,
+// defaulting to the primary
+func (scheduledMaintenance *ScheduledMaintenance) GetTarget() MaintenanceTarget {
+	if scheduledMaintenance.Spec.Target == "" {
+		return MaintenanceTargetPrimary
+	}
+
+	return scheduledMaintenance.Spec.Target
+}
+
+// GetDatabase gets the database where the statements are executed
+func (scheduledMaintenance *ScheduledMaintenance) GetDatabase() string {
+	if scheduledMaintenance.Spec.Database == "" {
+		return DefaultMaintenanceDatabase
+	}
+
+	return scheduledMaintenance.Spec.Database
+}
+
+func init() {
+	SchemeBuilder.Register(&ScheduledMaintenance{}, &ScheduledMaintenanceList{})
+}
@@ -0,0 +1,125 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"strings"
+
+	"github.com/robfig/cron"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// scheduledMaintenanceLog is for logging in this package.
+var scheduledMaintenanceLog = log.WithName("scheduledmaintenance-resource").WithValues("version", "v1")
+
+// SetupWebhookWithManager setup the webhook inside the controller manager
+func (r *ScheduledMaintenance) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		Complete()
+}
+
+// +kubebuilder:webhook:webhookVersions={v1},admissionReviewVersions={v1},path=/mutate-postgresql-cnpg-io-v1-scheduledmaintenance,mutating=true,failurePolicy=fail,groups=postgresql.cnpg.io,resources=scheduledmaintenances,verbs=create;update,versions=v1,name=mscheduledmaintenance.kb.io,sideEffects=None
+
+var _ webhook.Defaulter = &ScheduledMaintenance{}
+
+// Default implements webhook.Defaulter so a webhook will be registered for the type
+func (r *ScheduledMaintenance) Default() {
+	scheduledMaintenanceLog.Info("default", "name", r.Name, "namespace", r.Namespace)
+
+	r.Spec.Target = r.GetTarget()
+	r.Spec.Database = r.GetDatabase()
+}
+
+// +kubebuilder:webhook:webhookVersions={v1},admissionReviewVersions={v1},verbs=create;update,path=/validate-postgresql-cnpg-io-v1-scheduledmaintenance,mutating=false,failurePolicy=fail,groups=postgresql.cnpg.io,resources=scheduledmaintenances,versions=v1,name=vscheduledmaintenance.kb.io,sideEffects=None
+
+var _ webhook.Validator = &ScheduledMaintenance{}
+
+// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
+func (r *ScheduledMaintenance) ValidateCreate() error {
+	scheduledMaintenanceLog.Info("validate create", "name", r.Name, "namespace", r.Namespace)
+	return r.validate()
+}
+
+// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
+func (r *ScheduledMaintenance) ValidateUpdate(old runtime.Object) error {
+	scheduledMaintenanceLog.Info("validate update", "name", r.Name, "namespace", r.Namespace)
+	return r.validate()
+}
+
+// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
+func (r *ScheduledMaintenance) ValidateDelete() error {
+	scheduledMaintenanceLog.Info("validate delete", "name", r.Name, "namespace", r.Namespace)
+	return nil
+}
+
+func (r *ScheduledMaintenance) validate() error {
+	var allErrs field.ErrorList
+
+	allErrs = append(allErrs, r.validateSchedule()...)
+	allErrs = append(allErrs, r.validateSQL()...)
+
+	if len(allErrs) == 0 {
+		return nil
+	}
+
+	return apierrors.NewInvalid(
+		schema.GroupKind{Group: "postgresql.cnpg.io", Kind: ScheduledMaintenanceKind},
+		r.Name, allErrs)
+}
+
+func (r *ScheduledMaintenance) validateSchedule() field.ErrorList {
+	var result field.ErrorList
+
+	if _, err := cron.Parse(r.GetSchedule()); err != nil {
+		result = append(result,
+			field.Invalid(
+				field.NewPath("spec", "schedule"),
+				r.Spec.Schedule, err.Error()))
+	}
+
+	return result
+}
+
+func (r *ScheduledMaintenance) validateSQL() field.ErrorList {
+	var result field.ErrorList
+
+	if len(r.Spec.SQL) == 0 {
+		result = append(result,
+			field.Required(
+				field.NewPath("spec", "sql"),
+				"at least one statement is required"))
+	}
+
+	for idx, statement := range r.Spec.SQL {
+		if strings.TrimSpace(statement) == "" {
+			result = append(result,
+				field.Invalid(
+					field.NewPath("spec", "sql").Index(idx),
+					statement, "empty statements are not allowed"))
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,83 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Scheduled maintenance webhook", func() {
+	It("defaults the target and the database", func() {
+		maintenance := &ScheduledMaintenance{}
+		maintenance.Default()
+		Expect(maintenance.Spec.Target).To(Equal(MaintenanceTargetPrimary))
+		Expect(maintenance.Spec.Database).To(Equal(DefaultMaintenanceDatabase))
+	})
+
+	It("doesn't override the target and the database", func() {
+		maintenance := &ScheduledMaintenance{
+			Spec: ScheduledMaintenanceSpec{
+				Target:   MaintenanceTargetPreferStandby,
+				Database: "app",
+			},
+		}
+		maintenance.Default()
+		Expect(maintenance.Spec.Target).To(Equal(MaintenanceTargetPreferStandby))
+		Expect(maintenance.Spec.Database).To(Equal("app"))
+	})
+
+	It("complains with a wrong schedule", func() {
+		maintenance := &ScheduledMaintenance{
+			Spec: ScheduledMaintenanceSpec{
+				Schedule: "0 0 0 * * * 1996",
+				SQL:      []string{"VACUUM"},
+			},
+		}
+		Expect(maintenance.validateSchedule()).To(HaveLen(1))
+		Expect(maintenance.ValidateCreate()).ToNot(Succeed())
+	})
+
+	It("requires at least one statement", func() {
+		maintenance := &ScheduledMaintenance{
+			Spec: ScheduledMaintenanceSpec{
+				Schedule: "0 0 0 * * *",
+			},
+		}
+		Expect(maintenance.validateSQL()).To(HaveLen(1))
+	})
+
+	It("complains about empty statements", func() {
+		maintenance := &ScheduledMaintenance{
+			Spec: ScheduledMaintenanceSpec{
+				Schedule: "0 0 0 * * *",
+				SQL:      []string{"VACUUM", "  "},
+			},
+		}
+		Expect(maintenance.validateSQL()).To(HaveLen(1))
+	})
+
+	It("accepts a valid scheduled maintenance", func() {
+		maintenance := &ScheduledMaintenance{
+			Spec: ScheduledMaintenanceSpec{
+				Schedule: "0 0 0 * * *",
+				SQL:      []string{"VACUUM ANALYZE", "REINDEX DATABASE app"},
+			},
+		}
+		Expect(maintenance.ValidateCreate()).To(Succeed())
+	})
+})
@@ -77,6 +77,90 @@ func (in *AffinityConfiguration) DeepCopy() *AffinityConfiguration {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutovacuumConfiguration) DeepCopyInto(out *AutovacuumConfiguration) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.MaxWorkers != nil {
+		in, out := &in.MaxWorkers, &out.MaxWorkers
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Naptime != nil {
+		in, out := &in.Naptime, &out.Naptime
+		*out = new(int32)
+		**out = **in
+	}
+	if in.VacuumThreshold != nil {
+		in, out := &in.VacuumThreshold, &out.VacuumThreshold
+		*out = new(int32)
+		**out = **in
+	}
+	if in.AnalyzeThreshold != nil {
+		in, out := &in.AnalyzeThreshold, &out.AnalyzeThreshold
+		*out = new(int32)
+		**out = **in
+	}
+	if in.VacuumCostDelay != nil {
+		in, out := &in.VacuumCostDelay, &out.VacuumCostDelay
+		*out = new(int32)
+		**out = **in
+	}
+	if in.VacuumCostLimit != nil {
+		in, out := &in.VacuumCostLimit, &out.VacuumCostLimit
+		*out = new(int32)
+		**out = **in
+	}
+	if in.FreezeMaxAge != nil {
+		in, out := &in.FreezeMaxAge, &out.FreezeMaxAge
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Tables != nil {
+		in, out := &in.Tables, &out.Tables
+		*out = make([]AutovacuumTableConfiguration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutovacuumConfiguration.
+func (in *AutovacuumConfiguration) DeepCopy() *AutovacuumConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(AutovacuumConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutovacuumTableConfiguration) DeepCopyInto(out *AutovacuumTableConfiguration) {
+	*out = *in
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutovacuumTableConfiguration.
+func (in *AutovacuumTableConfiguration) DeepCopy() *AutovacuumTableConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(AutovacuumTableConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AzureCredentials) DeepCopyInto(out *AzureCredentials) {
 	*out = *in
@@ -147,6 +231,26 @@ func (in *BackupConfiguration) DeepCopyInto(out *BackupConfiguration) {
 		*out = new(BarmanObjectStoreConfiguration)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.FinalBackup != nil {
+		in, out := &in.FinalBackup, &out.FinalBackup
+		*out = new(FinalBackupConfiguration)
+		**out = **in
+	}
+	if in.WalMirror != nil {
+		in, out := &in.WalMirror, &out.WalMirror
+		*out = new(WalMirrorConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CustomWalArchive != nil {
+		in, out := &in.CustomWalArchive, &out.CustomWalArchive
+		*out = new(CustomWalArchiveConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.WalG != nil {
+		in, out := &in.WalG, &out.WalG
+		*out = new(WalGConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupConfiguration.
@@ -296,6 +400,11 @@ func (in *BarmanCredentials) DeepCopy() *BarmanCredentials {
 func (in *BarmanObjectStoreConfiguration) DeepCopyInto(out *BarmanObjectStoreConfiguration) {
 	*out = *in
 	in.BarmanCredentials.DeepCopyInto(&out.BarmanCredentials)
+	if in.CredentialsVolume != nil {
+		in, out := &in.CredentialsVolume, &out.CredentialsVolume
+		*out = new(corev1.CSIVolumeSource)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.EndpointCA != nil {
 		in, out := &in.EndpointCA, &out.EndpointCA
 		*out = new(SecretKeySelector)
@@ -304,7 +413,7 @@ func (in *BarmanObjectStoreConfiguration) DeepCopyInto(out *BarmanObjectStoreCon
 	if in.Wal != nil {
 		in, out := &in.Wal, &out.Wal
 		*out = new(WalBackupConfiguration)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.Data != nil {
 		in, out := &in.Data, &out.Data
@@ -425,6 +534,11 @@ func (in *BootstrapInitDB) DeepCopy() *BootstrapInitDB {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BootstrapPgBaseBackup) DeepCopyInto(out *BootstrapPgBaseBackup) {
 	*out = *in
+	if in.SourceCluster != nil {
+		in, out := &in.SourceCluster, &out.SourceCluster
+		*out = new(LocalObjectReference)
+		**out = **in
+	}
 	if in.Secret != nil {
 		in, out := &in.Secret, &out.Secret
 		*out = new(LocalObjectReference)
@@ -472,6 +586,21 @@ func (in *BootstrapRecovery) DeepCopy() *BootstrapRecovery {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificateIssuerReference) DeepCopyInto(out *CertificateIssuerReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateIssuerReference.
+func (in *CertificateIssuerReference) DeepCopy() *CertificateIssuerReference {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificateIssuerReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CertificatesConfiguration) DeepCopyInto(out *CertificatesConfiguration) {
 	*out = *in
@@ -480,6 +609,11 @@ func (in *CertificatesConfiguration) DeepCopyInto(out *CertificatesConfiguration
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.ServerIssuerRef != nil {
+		in, out := &in.ServerIssuerRef, &out.ServerIssuerRef
+		*out = new(CertificateIssuerReference)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificatesConfiguration.
@@ -515,6 +649,36 @@ func (in *CertificatesStatus) DeepCopy() *CertificatesStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CheckpointConfiguration) DeepCopyInto(out *CheckpointConfiguration) {
+	*out = *in
+	if in.MaxWalSize != nil {
+		in, out := &in.MaxWalSize, &out.MaxWalSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.MinWalSize != nil {
+		in, out := &in.MinWalSize, &out.MinWalSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CheckpointConfiguration.
+func (in *CheckpointConfiguration) DeepCopy() *CheckpointConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(CheckpointConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Cluster) DeepCopyInto(out *Cluster) {
 	*out = *in
@@ -582,12 +746,37 @@ func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
 		*out = new(EmbeddedObjectMetadata)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ReadOnly != nil {
+		in, out := &in.ReadOnly, &out.ReadOnly
+		*out = new(ReadOnlyConfiguration)
+		**out = **in
+	}
+	if in.DelayedReplicas != nil {
+		in, out := &in.DelayedReplicas, &out.DelayedReplicas
+		*out = new(DelayedReplicasConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ReadReplicas != nil {
+		in, out := &in.ReadReplicas, &out.ReadReplicas
+		*out = new(ReadReplicasConfiguration)
+		**out = **in
+	}
+	if in.ReplicationConnection != nil {
+		in, out := &in.ReplicationConnection, &out.ReplicationConnection
+		*out = new(ReplicationConnectionConfiguration)
+		**out = **in
+	}
 	in.PostgresConfiguration.DeepCopyInto(&out.PostgresConfiguration)
 	if in.ReplicationSlots != nil {
 		in, out := &in.ReplicationSlots, &out.ReplicationSlots
 		*out = new(ReplicationSlotsConfiguration)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.WalKeepSize != nil {
+		in, out := &in.WalKeepSize, &out.WalKeepSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
 	if in.Bootstrap != nil {
 		in, out := &in.Bootstrap, &out.Bootstrap
 		*out = new(BootstrapConfiguration)
@@ -613,6 +802,11 @@ func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
 		*out = new(CertificatesConfiguration)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ServiceNaming != nil {
+		in, out := &in.ServiceNaming, &out.ServiceNaming
+		*out = new(ServiceNamingConfiguration)
+		**out = **in
+	}
 	if in.ImagePullSecrets != nil {
 		in, out := &in.ImagePullSecrets, &out.ImagePullSecrets
 		*out = make([]LocalObjectReference, len(*in))
@@ -629,6 +823,52 @@ func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
 		*out = new(StorageConfiguration)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Tablespaces != nil {
+		in, out := &in.Tablespaces, &out.Tablespaces
+		*out = make([]TablespaceConfiguration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Publications != nil {
+		in, out := &in.Publications, &out.Publications
+		*out = make([]PublicationConfiguration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Extensions != nil {
+		in, out := &in.Extensions, &out.Extensions
+		*out = make([]ExtensionConfiguration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.OrderlyShutdown != nil {
+		in, out := &in.OrderlyShutdown, &out.OrderlyShutdown
+		*out = new(OrderlyShutdownConfiguration)
+		**out = **in
+	}
+	if in.SwitchoverCheckpoint != nil {
+		in, out := &in.SwitchoverCheckpoint, &out.SwitchoverCheckpoint
+		*out = new(SwitchoverCheckpointConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PrimaryTransition != nil {
+		in, out := &in.PrimaryTransition, &out.PrimaryTransition
+		*out = new(PrimaryTransitionConfiguration)
+		**out = **in
+	}
+	if in.CorruptedReplicas != nil {
+		in, out := &in.CorruptedReplicas, &out.CorruptedReplicas
+		*out = new(CorruptedReplicasConfiguration)
+		**out = **in
+	}
+	if in.ReadinessTolerance != nil {
+		in, out := &in.ReadinessTolerance, &out.ReadinessTolerance
+		*out = new(ReadinessToleranceConfiguration)
+		**out = **in
+	}
 	in.Affinity.DeepCopyInto(&out.Affinity)
 	in.Resources.DeepCopyInto(&out.Resources)
 	if in.Backup != nil {
@@ -641,6 +881,11 @@ func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
 		*out = new(NodeMaintenanceWindow)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.MaintenanceWindow != nil {
+		in, out := &in.MaintenanceWindow, &out.MaintenanceWindow
+		*out = new(MaintenanceWindowConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Monitoring != nil {
 		in, out := &in.Monitoring, &out.Monitoring
 		*out = new(MonitoringConfiguration)
@@ -672,6 +917,30 @@ func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Sidecars != nil {
+		in, out := &in.Sidecars, &out.Sidecars
+		*out = make([]corev1.Container, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SidecarVolumes != nil {
+		in, out := &in.SidecarVolumes, &out.SidecarVolumes
+		*out = make([]corev1.Volume, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.MountedObjects != nil {
+		in, out := &in.MountedObjects, &out.MountedObjects
+		*out = make([]MountedObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.ScheduledRestart != nil {
+		in, out := &in.ScheduledRestart, &out.ScheduledRestart
+		*out = new(ScheduledRestartConfiguration)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterSpec.
@@ -687,6 +956,11 @@ func (in *ClusterSpec) DeepCopy() *ClusterSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterStatus) DeepCopyInto(out *ClusterStatus) {
 	*out = *in
+	if in.ReadReplicas != nil {
+		in, out := &in.ReadReplicas, &out.ReadReplicas
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.InstancesStatus != nil {
 		in, out := &in.InstancesStatus, &out.InstancesStatus
 		*out = make(map[utils.PodStatus][]string, len(*in))
@@ -706,9 +980,24 @@ func (in *ClusterStatus) DeepCopyInto(out *ClusterStatus) {
 		in, out := &in.InstancesReportedState, &out.InstancesReportedState
 		*out = make(map[PodName]InstanceReportedState, len(*in))
 		for key, val := range *in {
-			(*out)[key] = val
+			(*out)[key] = *val.DeepCopy()
 		}
 	}
+	if in.ResynchronizingInstances != nil {
+		in, out := &in.ResynchronizingInstances, &out.ResynchronizingInstances
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.InitDBSettings != nil {
+		in, out := &in.InitDBSettings, &out.InitDBSettings
+		*out = new(InitDBSettings)
+		**out = **in
+	}
+	if in.PGDataImageInfo != nil {
+		in, out := &in.PGDataImageInfo, &out.PGDataImageInfo
+		*out = new(ImageInfo)
+		**out = **in
+	}
 	in.Topology.DeepCopyInto(&out.Topology)
 	if in.DanglingPVC != nil {
 		in, out := &in.DanglingPVC, &out.DanglingPVC
@@ -735,9 +1024,19 @@ func (in *ClusterStatus) DeepCopyInto(out *ClusterStatus) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.StorageClasses != nil {
+		in, out := &in.StorageClasses, &out.StorageClasses
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	in.SecretsResourceVersion.DeepCopyInto(&out.SecretsResourceVersion)
 	in.ConfigMapResourceVersion.DeepCopyInto(&out.ConfigMapResourceVersion)
 	in.Certificates.DeepCopyInto(&out.Certificates)
+	if in.PrimaryHistory != nil {
+		in, out := &in.PrimaryHistory, &out.PrimaryHistory
+		*out = make([]PrimaryChange, len(*in))
+		copy(*out, *in)
+	}
 	if in.PoolerIntegrations != nil {
 		in, out := &in.PoolerIntegrations, &out.PoolerIntegrations
 		*out = new(PoolerIntegrations)
@@ -755,6 +1054,26 @@ func (in *ClusterStatus) DeepCopyInto(out *ClusterStatus) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Publications != nil {
+		in, out := &in.Publications, &out.Publications
+		*out = make([]PublicationStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.Extensions != nil {
+		in, out := &in.Extensions, &out.Extensions
+		*out = make([]ExtensionStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.RecoveryProgress != nil {
+		in, out := &in.RecoveryProgress, &out.RecoveryProgress
+		*out = new(RecoveryProgress)
+		**out = **in
+	}
+	if in.WalMirror != nil {
+		in, out := &in.WalMirror, &out.WalMirror
+		*out = new(WalMirrorStatus)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterStatus.
@@ -805,9 +1124,51 @@ func (in *ConfigMapResourceVersion) DeepCopy() *ConfigMapResourceVersion {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CorruptedReplicasConfiguration) DeepCopyInto(out *CorruptedReplicasConfiguration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CorruptedReplicasConfiguration.
+func (in *CorruptedReplicasConfiguration) DeepCopy() *CorruptedReplicasConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(CorruptedReplicasConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CustomWalArchiveConfiguration) DeepCopyInto(out *CustomWalArchiveConfiguration) {
+	*out = *in
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]corev1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CustomWalArchiveConfiguration.
+func (in *CustomWalArchiveConfiguration) DeepCopy() *CustomWalArchiveConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(CustomWalArchiveConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DataBackupConfiguration) DeepCopyInto(out *DataBackupConfiguration) {
 	*out = *in
+	if in.Zstd != nil {
+		in, out := &in.Zstd, &out.Zstd
+		*out = new(ZstdCompressionConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Jobs != nil {
 		in, out := &in.Jobs, &out.Jobs
 		*out = new(int32)
@@ -825,6 +1186,26 @@ func (in *DataBackupConfiguration) DeepCopy() *DataBackupConfiguration {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DelayedReplicasConfiguration) DeepCopyInto(out *DelayedReplicasConfiguration) {
+	*out = *in
+	if in.Instances != nil {
+		in, out := &in.Instances, &out.Instances
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DelayedReplicasConfiguration.
+func (in *DelayedReplicasConfiguration) DeepCopy() *DelayedReplicasConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(DelayedReplicasConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EmbeddedObjectMetadata) DeepCopyInto(out *EmbeddedObjectMetadata) {
 	*out = *in
@@ -854,6 +1235,46 @@ func (in *EmbeddedObjectMetadata) DeepCopy() *EmbeddedObjectMetadata {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExtensionConfiguration) DeepCopyInto(out *ExtensionConfiguration) {
+	*out = *in
+	if in.DBNames != nil {
+		in, out := &in.DBNames, &out.DBNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SharedPreloadLibraries != nil {
+		in, out := &in.SharedPreloadLibraries, &out.SharedPreloadLibraries
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExtensionConfiguration.
+func (in *ExtensionConfiguration) DeepCopy() *ExtensionConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(ExtensionConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExtensionStatus) DeepCopyInto(out *ExtensionStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExtensionStatus.
+func (in *ExtensionStatus) DeepCopy() *ExtensionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ExtensionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ExternalCluster) DeepCopyInto(out *ExternalCluster) {
 	*out = *in
@@ -889,6 +1310,11 @@ func (in *ExternalCluster) DeepCopyInto(out *ExternalCluster) {
 		*out = new(BarmanObjectStoreConfiguration)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.WalG != nil {
+		in, out := &in.WalG, &out.WalG
+		*out = new(WalGConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExternalCluster.
@@ -902,16 +1328,31 @@ func (in *ExternalCluster) DeepCopy() *ExternalCluster {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *GoogleCredentials) DeepCopyInto(out *GoogleCredentials) {
+func (in *FinalBackupConfiguration) DeepCopyInto(out *FinalBackupConfiguration) {
 	*out = *in
-	if in.ApplicationCredentials != nil {
-		in, out := &in.ApplicationCredentials, &out.ApplicationCredentials
-		*out = new(SecretKeySelector)
-		**out = **in
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GoogleCredentials.
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FinalBackupConfiguration.
+func (in *FinalBackupConfiguration) DeepCopy() *FinalBackupConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(FinalBackupConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GoogleCredentials) DeepCopyInto(out *GoogleCredentials) {
+	*out = *in
+	if in.ApplicationCredentials != nil {
+		in, out := &in.ApplicationCredentials, &out.ApplicationCredentials
+		*out = new(SecretKeySelector)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GoogleCredentials.
 func (in *GoogleCredentials) DeepCopy() *GoogleCredentials {
 	if in == nil {
 		return nil
@@ -921,6 +1362,21 @@ func (in *GoogleCredentials) DeepCopy() *GoogleCredentials {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ImageInfo) DeepCopyInto(out *ImageInfo) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ImageInfo.
+func (in *ImageInfo) DeepCopy() *ImageInfo {
+	if in == nil {
+		return nil
+	}
+	out := new(ImageInfo)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Import) DeepCopyInto(out *Import) {
 	*out = *in
@@ -967,6 +1423,21 @@ func (in *ImportSource) DeepCopy() *ImportSource {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InitDBSettings) DeepCopyInto(out *InitDBSettings) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InitDBSettings.
+func (in *InitDBSettings) DeepCopy() *InitDBSettings {
+	if in == nil {
+		return nil
+	}
+	out := new(InitDBSettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *InstanceID) DeepCopyInto(out *InstanceID) {
 	*out = *in
@@ -985,6 +1456,16 @@ func (in *InstanceID) DeepCopy() *InstanceID {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *InstanceReportedState) DeepCopyInto(out *InstanceReportedState) {
 	*out = *in
+	if in.ResourceUsage != nil {
+		in, out := &in.ResourceUsage, &out.ResourceUsage
+		*out = new(InstanceResourceUsage)
+		**out = **in
+	}
+	if in.ChecksumFailuresBaseline != nil {
+		in, out := &in.ChecksumFailuresBaseline, &out.ChecksumFailuresBaseline
+		*out = new(int64)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstanceReportedState.
@@ -997,6 +1478,21 @@ func (in *InstanceReportedState) DeepCopy() *InstanceReportedState {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InstanceResourceUsage) DeepCopyInto(out *InstanceResourceUsage) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstanceResourceUsage.
+func (in *InstanceResourceUsage) DeepCopy() *InstanceResourceUsage {
+	if in == nil {
+		return nil
+	}
+	out := new(InstanceResourceUsage)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LDAPBindAsAuth) DeepCopyInto(out *LDAPBindAsAuth) {
 	*out = *in
@@ -1072,6 +1568,48 @@ func (in *LocalObjectReference) DeepCopy() *LocalObjectReference {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceTimeRange) DeepCopyInto(out *MaintenanceTimeRange) {
+	*out = *in
+	if in.Days != nil {
+		in, out := &in.Days, &out.Days
+		*out = make([]MaintenanceWeekday, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceTimeRange.
+func (in *MaintenanceTimeRange) DeepCopy() *MaintenanceTimeRange {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceTimeRange)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceWindowConfiguration) DeepCopyInto(out *MaintenanceWindowConfiguration) {
+	*out = *in
+	if in.Windows != nil {
+		in, out := &in.Windows, &out.Windows
+		*out = make([]MaintenanceTimeRange, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceWindowConfiguration.
+func (in *MaintenanceWindowConfiguration) DeepCopy() *MaintenanceWindowConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceWindowConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Metadata) DeepCopyInto(out *Metadata) {
 	*out = *in
@@ -1131,6 +1669,21 @@ func (in *MonitoringConfiguration) DeepCopy() *MonitoringConfiguration {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MountedObjectReference) DeepCopyInto(out *MountedObjectReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MountedObjectReference.
+func (in *MountedObjectReference) DeepCopy() *MountedObjectReference {
+	if in == nil {
+		return nil
+	}
+	out := new(MountedObjectReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NodeMaintenanceWindow) DeepCopyInto(out *NodeMaintenanceWindow) {
 	*out = *in
@@ -1151,6 +1704,21 @@ func (in *NodeMaintenanceWindow) DeepCopy() *NodeMaintenanceWindow {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OrderlyShutdownConfiguration) DeepCopyInto(out *OrderlyShutdownConfiguration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OrderlyShutdownConfiguration.
+func (in *OrderlyShutdownConfiguration) DeepCopy() *OrderlyShutdownConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(OrderlyShutdownConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PgBouncerIntegrationStatus) DeepCopyInto(out *PgBouncerIntegrationStatus) {
 	*out = *in
@@ -1441,6 +2009,11 @@ func (in *PostgresConfiguration) DeepCopyInto(out *PostgresConfiguration) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.PgIdent != nil {
+		in, out := &in.PgIdent, &out.PgIdent
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	in.SyncReplicaElectionConstraint.DeepCopyInto(&out.SyncReplicaElectionConstraint)
 	if in.AdditionalLibraries != nil {
 		in, out := &in.AdditionalLibraries, &out.AdditionalLibraries
@@ -1452,6 +2025,21 @@ func (in *PostgresConfiguration) DeepCopyInto(out *PostgresConfiguration) {
 		*out = new(LDAPConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Autovacuum != nil {
+		in, out := &in.Autovacuum, &out.Autovacuum
+		*out = new(AutovacuumConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Prewarm != nil {
+		in, out := &in.Prewarm, &out.Prewarm
+		*out = new(PrewarmConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Checkpoint != nil {
+		in, out := &in.Checkpoint, &out.Checkpoint
+		*out = new(CheckpointConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresConfiguration.
@@ -1464,6 +2052,176 @@ func (in *PostgresConfiguration) DeepCopy() *PostgresConfiguration {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrewarmConfiguration) DeepCopyInto(out *PrewarmConfiguration) {
+	*out = *in
+	if in.Relations != nil {
+		in, out := &in.Relations, &out.Relations
+		*out = make([]PrewarmRelation, len(*in))
+		copy(*out, *in)
+	}
+	if in.MaxRelationSize != nil {
+		in, out := &in.MaxRelationSize, &out.MaxRelationSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrewarmConfiguration.
+func (in *PrewarmConfiguration) DeepCopy() *PrewarmConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(PrewarmConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrewarmRelation) DeepCopyInto(out *PrewarmRelation) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrewarmRelation.
+func (in *PrewarmRelation) DeepCopy() *PrewarmRelation {
+	if in == nil {
+		return nil
+	}
+	out := new(PrewarmRelation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrimaryChange) DeepCopyInto(out *PrimaryChange) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrimaryChange.
+func (in *PrimaryChange) DeepCopy() *PrimaryChange {
+	if in == nil {
+		return nil
+	}
+	out := new(PrimaryChange)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrimaryTransitionConfiguration) DeepCopyInto(out *PrimaryTransitionConfiguration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrimaryTransitionConfiguration.
+func (in *PrimaryTransitionConfiguration) DeepCopy() *PrimaryTransitionConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(PrimaryTransitionConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PublicationConfiguration) DeepCopyInto(out *PublicationConfiguration) {
+	*out = *in
+	if in.Tables != nil {
+		in, out := &in.Tables, &out.Tables
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Publish != nil {
+		in, out := &in.Publish, &out.Publish
+		*out = make([]PublicationOperation, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PublicationConfiguration.
+func (in *PublicationConfiguration) DeepCopy() *PublicationConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(PublicationConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PublicationStatus) DeepCopyInto(out *PublicationStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PublicationStatus.
+func (in *PublicationStatus) DeepCopy() *PublicationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PublicationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReadOnlyConfiguration) DeepCopyInto(out *ReadOnlyConfiguration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReadOnlyConfiguration.
+func (in *ReadOnlyConfiguration) DeepCopy() *ReadOnlyConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(ReadOnlyConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReadReplicasConfiguration) DeepCopyInto(out *ReadReplicasConfiguration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReadReplicasConfiguration.
+func (in *ReadReplicasConfiguration) DeepCopy() *ReadReplicasConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(ReadReplicasConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReadinessToleranceConfiguration) DeepCopyInto(out *ReadinessToleranceConfiguration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReadinessToleranceConfiguration.
+func (in *ReadinessToleranceConfiguration) DeepCopy() *ReadinessToleranceConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(ReadinessToleranceConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RecoveryProgress) DeepCopyInto(out *RecoveryProgress) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RecoveryProgress.
+func (in *RecoveryProgress) DeepCopy() *RecoveryProgress {
+	if in == nil {
+		return nil
+	}
+	out := new(RecoveryProgress)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RecoveryTarget) DeepCopyInto(out *RecoveryTarget) {
 	*out = *in
@@ -1504,6 +2262,21 @@ func (in *ReplicaClusterConfiguration) DeepCopy() *ReplicaClusterConfiguration {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReplicationConnectionConfiguration) DeepCopyInto(out *ReplicationConnectionConfiguration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReplicationConnectionConfiguration.
+func (in *ReplicationConnectionConfiguration) DeepCopy() *ReplicationConnectionConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(ReplicationConnectionConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ReplicationSlotsConfiguration) DeepCopyInto(out *ReplicationSlotsConfiguration) {
 	*out = *in
@@ -1702,6 +2475,133 @@ func (in *ScheduledBackupStatus) DeepCopy() *ScheduledBackupStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScheduledMaintenance) DeepCopyInto(out *ScheduledMaintenance) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduledMaintenance.
+func (in *ScheduledMaintenance) DeepCopy() *ScheduledMaintenance {
+	if in == nil {
+		return nil
+	}
+	out := new(ScheduledMaintenance)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ScheduledMaintenance) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScheduledMaintenanceList) DeepCopyInto(out *ScheduledMaintenanceList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ScheduledMaintenance, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduledMaintenanceList.
+func (in *ScheduledMaintenanceList) DeepCopy() *ScheduledMaintenanceList {
+	if in == nil {
+		return nil
+	}
+	out := new(ScheduledMaintenanceList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ScheduledMaintenanceList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScheduledMaintenanceSpec) DeepCopyInto(out *ScheduledMaintenanceSpec) {
+	*out = *in
+	if in.Suspend != nil {
+		in, out := &in.Suspend, &out.Suspend
+		*out = new(bool)
+		**out = **in
+	}
+	out.Cluster = in.Cluster
+	if in.SQL != nil {
+		in, out := &in.SQL, &out.SQL
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduledMaintenanceSpec.
+func (in *ScheduledMaintenanceSpec) DeepCopy() *ScheduledMaintenanceSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ScheduledMaintenanceSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScheduledMaintenanceStatus) DeepCopyInto(out *ScheduledMaintenanceStatus) {
+	*out = *in
+	if in.LastCheckTime != nil {
+		in, out := &in.LastCheckTime, &out.LastCheckTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastScheduleTime != nil {
+		in, out := &in.LastScheduleTime, &out.LastScheduleTime
+		*out = (*in).DeepCopy()
+	}
+	if in.NextScheduleTime != nil {
+		in, out := &in.NextScheduleTime, &out.NextScheduleTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduledMaintenanceStatus.
+func (in *ScheduledMaintenanceStatus) DeepCopy() *ScheduledMaintenanceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ScheduledMaintenanceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScheduledRestartConfiguration) DeepCopyInto(out *ScheduledRestartConfiguration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduledRestartConfiguration.
+func (in *ScheduledRestartConfiguration) DeepCopy() *ScheduledRestartConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(ScheduledRestartConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SecretKeySelector) DeepCopyInto(out *SecretKeySelector) {
 	*out = *in
@@ -1771,6 +2671,21 @@ func (in *ServiceAccountTemplate) DeepCopy() *ServiceAccountTemplate {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceNamingConfiguration) DeepCopyInto(out *ServiceNamingConfiguration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceNamingConfiguration.
+func (in *ServiceNamingConfiguration) DeepCopy() *ServiceNamingConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceNamingConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StorageConfiguration) DeepCopyInto(out *StorageConfiguration) {
 	*out = *in
@@ -1801,6 +2716,26 @@ func (in *StorageConfiguration) DeepCopy() *StorageConfiguration {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SwitchoverCheckpointConfiguration) DeepCopyInto(out *SwitchoverCheckpointConfiguration) {
+	*out = *in
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SwitchoverCheckpointConfiguration.
+func (in *SwitchoverCheckpointConfiguration) DeepCopy() *SwitchoverCheckpointConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(SwitchoverCheckpointConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SyncReplicaElectionConstraints) DeepCopyInto(out *SyncReplicaElectionConstraints) {
 	*out = *in
@@ -1821,6 +2756,22 @@ func (in *SyncReplicaElectionConstraints) DeepCopy() *SyncReplicaElectionConstra
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TablespaceConfiguration) DeepCopyInto(out *TablespaceConfiguration) {
+	*out = *in
+	in.Storage.DeepCopyInto(&out.Storage)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TablespaceConfiguration.
+func (in *TablespaceConfiguration) DeepCopy() *TablespaceConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(TablespaceConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Topology) DeepCopyInto(out *Topology) {
 	*out = *in
@@ -1856,6 +2807,16 @@ func (in *Topology) DeepCopy() *Topology {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *WalBackupConfiguration) DeepCopyInto(out *WalBackupConfiguration) {
 	*out = *in
+	if in.Zstd != nil {
+		in, out := &in.Zstd, &out.Zstd
+		*out = new(ZstdCompressionConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Staging != nil {
+		in, out := &in.Staging, &out.Staging
+		*out = new(WalStagingConfiguration)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WalBackupConfiguration.
@@ -1867,3 +2828,85 @@ func (in *WalBackupConfiguration) DeepCopy() *WalBackupConfiguration {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WalGConfiguration) DeepCopyInto(out *WalGConfiguration) {
+	*out = *in
+	in.BarmanCredentials.DeepCopyInto(&out.BarmanCredentials)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WalGConfiguration.
+func (in *WalGConfiguration) DeepCopy() *WalGConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(WalGConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WalMirrorConfiguration) DeepCopyInto(out *WalMirrorConfiguration) {
+	*out = *in
+	in.BarmanObjectStore.DeepCopyInto(&out.BarmanObjectStore)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WalMirrorConfiguration.
+func (in *WalMirrorConfiguration) DeepCopy() *WalMirrorConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(WalMirrorConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WalMirrorStatus) DeepCopyInto(out *WalMirrorStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WalMirrorStatus.
+func (in *WalMirrorStatus) DeepCopy() *WalMirrorStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(WalMirrorStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WalStagingConfiguration) DeepCopyInto(out *WalStagingConfiguration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WalStagingConfiguration.
+func (in *WalStagingConfiguration) DeepCopy() *WalStagingConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(WalStagingConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ZstdCompressionConfiguration) DeepCopyInto(out *ZstdCompressionConfiguration) {
+	*out = *in
+	if in.Level != nil {
+		in, out := &in.Level, &out.Level
+		*out = new(int)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ZstdCompressionConfiguration.
+func (in *ZstdCompressionConfiguration) DeepCopy() *ZstdCompressionConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(ZstdCompressionConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
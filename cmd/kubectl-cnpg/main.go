@@ -28,7 +28,10 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/backup"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/certificate"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/checkobjectstore"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/decommission"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/destroy"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/export"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/fence"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/fio"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/hibernate"
@@ -36,9 +39,11 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/maintenance"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/pgbench"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/promote"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/readonly"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/reload"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/report"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/restart"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/rotatecertificates"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin/status"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/versions"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
@@ -64,7 +69,10 @@ func main() {
 	configFlags.AddFlags(rootCmd.PersistentFlags())
 
 	rootCmd.AddCommand(certificate.NewCmd())
+	rootCmd.AddCommand(checkobjectstore.NewCmd())
+	rootCmd.AddCommand(decommission.NewCmd())
 	rootCmd.AddCommand(destroy.NewCmd())
+	rootCmd.AddCommand(export.NewCmd())
 	rootCmd.AddCommand(fence.NewCmd())
 	rootCmd.AddCommand(fio.NewCmd())
 	rootCmd.AddCommand(hibernate.NewCmd())
@@ -72,9 +80,11 @@ func main() {
 	rootCmd.AddCommand(maintenance.NewCmd())
 	rootCmd.AddCommand(pgbench.NewCmd())
 	rootCmd.AddCommand(promote.NewCmd())
+	rootCmd.AddCommand(readonly.NewCmd())
 	rootCmd.AddCommand(reload.NewCmd())
 	rootCmd.AddCommand(report.NewCmd())
 	rootCmd.AddCommand(restart.NewCmd())
+	rootCmd.AddCommand(rotatecertificates.NewCmd())
 	rootCmd.AddCommand(status.NewCmd())
 	rootCmd.AddCommand(versions.NewCmd())
 	rootCmd.AddCommand(backup.NewCmd())
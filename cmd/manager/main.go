@@ -26,11 +26,14 @@ import (
 
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/backup"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/bootstrap"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/checkobjectstore"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/controller"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/instance"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/pgbouncer"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/purgeobjectstore"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/show"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/walarchive"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/walmirror"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/walrestore"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/versions"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
@@ -53,10 +56,13 @@ func main() {
 
 	cmd.AddCommand(backup.NewCmd())
 	cmd.AddCommand(bootstrap.NewCmd())
+	cmd.AddCommand(checkobjectstore.NewCmd())
 	cmd.AddCommand(controller.NewCmd())
 	cmd.AddCommand(instance.NewCmd())
+	cmd.AddCommand(purgeobjectstore.NewCmd())
 	cmd.AddCommand(show.NewCmd())
 	cmd.AddCommand(walarchive.NewCmd())
+	cmd.AddCommand(walmirror.NewCmd())
 	cmd.AddCommand(walrestore.NewCmd())
 	cmd.AddCommand(versions.NewCmd())
 	cmd.AddCommand(pgbouncer.NewCmd())
@@ -0,0 +1,135 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// reconcileOrphanResources adopts again the Pods, PVCs and Jobs of the
+// cluster which have lost their controller owner reference, i.e. because
+// someone edited them. Those objects would otherwise be invisible to the
+// field indexes used to find the managed resources, and would escape
+// the reconciliation loop and the garbage collection
+func (r *ClusterReconciler) reconcileOrphanResources(ctx context.Context, cluster *apiv1.Cluster) error {
+	// The orphan PVCs of a cluster which has never been deployed
+	// are adopted while restoring the cluster
+	if cluster.Status.LatestGeneratedNode == 0 {
+		return nil
+	}
+
+	var pods corev1.PodList
+	if err := r.List(
+		ctx,
+		&pods,
+		client.InNamespace(cluster.Namespace),
+		client.MatchingLabels{utils.ClusterLabelName: cluster.Name},
+	); err != nil {
+		return fmt.Errorf("while listing the pods: %w", err)
+	}
+	for idx := range pods.Items {
+		if _, isInstance := pods.Items[idx].Annotations[specs.ClusterSerialAnnotationName]; !isInstance {
+			continue
+		}
+		if err := r.adoptOrphanObject(ctx, cluster, "Pod", &pods.Items[idx]); err != nil {
+			return err
+		}
+	}
+
+	var pvcs corev1.PersistentVolumeClaimList
+	if err := r.List(
+		ctx,
+		&pvcs,
+		client.InNamespace(cluster.Namespace),
+		client.MatchingLabels{utils.ClusterLabelName: cluster.Name},
+	); err != nil {
+		return fmt.Errorf("while listing the PVCs: %w", err)
+	}
+	for idx := range pvcs.Items {
+		if _, isInstance := pvcs.Items[idx].Annotations[specs.ClusterSerialAnnotationName]; !isInstance {
+			continue
+		}
+		if err := r.adoptOrphanObject(ctx, cluster, "PersistentVolumeClaim", &pvcs.Items[idx]); err != nil {
+			return err
+		}
+	}
+
+	var jobs batchv1.JobList
+	if err := r.List(
+		ctx,
+		&jobs,
+		client.InNamespace(cluster.Namespace),
+		client.MatchingLabels{utils.ClusterLabelName: cluster.Name},
+		client.HasLabels{utils.InstanceNameLabelName},
+	); err != nil {
+		return fmt.Errorf("while listing the jobs: %w", err)
+	}
+	for idx := range jobs.Items {
+		if err := r.adoptOrphanObject(ctx, cluster, "Job", &jobs.Items[idx]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// adoptOrphanObject sets the cluster as the controller of the passed
+// object, if it has no controller. Objects controlled by something
+// else are left untouched
+func (r *ClusterReconciler) adoptOrphanObject(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	kind string,
+	object client.Object,
+) error {
+	if metav1.GetControllerOf(object) != nil {
+		return nil
+	}
+
+	contextLogger := log.FromContext(ctx)
+
+	origObject, ok := object.DeepCopyObject().(client.Object)
+	if !ok {
+		return fmt.Errorf("unexpected object type %T", object)
+	}
+
+	object.SetOwnerReferences(append(
+		object.GetOwnerReferences(),
+		*metav1.NewControllerRef(cluster, apiv1.GroupVersion.WithKind(apiv1.ClusterKind)),
+	))
+	if err := r.Patch(ctx, object, client.MergeFrom(origObject)); err != nil {
+		return fmt.Errorf("while adopting %s %s: %w", kind, object.GetName(), err)
+	}
+
+	contextLogger.Info("Adopted an object which lost its owner reference",
+		"kind", kind, "name", object.GetName())
+	r.Recorder.Eventf(cluster, "Normal", "AdoptedOrphanObject",
+		"Adopted %s %s, which lost its owner reference", kind, object.GetName())
+
+	return nil
+}
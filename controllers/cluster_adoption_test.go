@@ -0,0 +1,74 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Adopting the orphan resources", func() {
+	It("adopts again a pod which lost its owner reference", func() {
+		ctx := context.TODO()
+		namespace := newFakeNamespace()
+		cluster := newFakeCNPGCluster(namespace)
+		cluster.Status.LatestGeneratedNode = cluster.Spec.Instances
+
+		By("creating the pods of the cluster")
+		pods := generateFakeClusterPods(clusterReconciler.Client, cluster, true)
+
+		By("stripping the owner reference from a pod")
+		var pod corev1.Pod
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(&pods[0]), &pod)).To(Succeed())
+		origPod := pod.DeepCopy()
+		pod.OwnerReferences = nil
+		Expect(k8sClient.Patch(ctx, &pod, client.MergeFrom(origPod))).To(Succeed())
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(&pods[0]), &pod)).To(Succeed())
+		_, isOwned := IsOwnedByCluster(&pod)
+		Expect(isOwned).To(BeFalse())
+
+		By("adopting it again")
+		Expect(clusterReconciler.reconcileOrphanResources(ctx, cluster)).To(Succeed())
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(&pods[0]), &pod)).To(Succeed())
+		owner, isOwned := IsOwnedByCluster(&pod)
+		Expect(isOwned).To(BeTrue())
+		Expect(owner).To(Equal(cluster.Name))
+	})
+
+	It("leaves alone the objects of a cluster which has never been deployed", func() {
+		ctx := context.TODO()
+		namespace := newFakeNamespace()
+		cluster := newFakeCNPGCluster(namespace)
+
+		pods := generateFakeClusterPods(clusterReconciler.Client, cluster, true)
+		var pod corev1.Pod
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(&pods[0]), &pod)).To(Succeed())
+		origPod := pod.DeepCopy()
+		pod.OwnerReferences = nil
+		Expect(k8sClient.Patch(ctx, &pod, client.MergeFrom(origPod))).To(Succeed())
+
+		Expect(clusterReconciler.reconcileOrphanResources(ctx, cluster)).To(Succeed())
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(&pods[0]), &pod)).To(Succeed())
+		_, isOwned := IsOwnedByCluster(&pod)
+		Expect(isOwned).To(BeFalse())
+	})
+})
@@ -29,6 +29,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	policyv1 "k8s.io/api/policy/v1"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -37,6 +38,8 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -69,11 +72,18 @@ type ClusterReconciler struct {
 	Scheme          *runtime.Scheme
 	Recorder        record.EventRecorder
 
+	limits        ReconcileLimits
+	startupJitter *startupJitter
+
 	*instanceStatusClient
 }
 
 // NewClusterReconciler creates a new ClusterReconciler initializing it
-func NewClusterReconciler(mgr manager.Manager, discoveryClient *discovery.DiscoveryClient) *ClusterReconciler {
+func NewClusterReconciler(
+	mgr manager.Manager,
+	discoveryClient *discovery.DiscoveryClient,
+	limits ReconcileLimits,
+) *ClusterReconciler {
 	return &ClusterReconciler{
 		instanceStatusClient: newInstanceStatusClient(),
 
@@ -81,6 +91,8 @@ func NewClusterReconciler(mgr manager.Manager, discoveryClient *discovery.Discov
 		Client:          mgr.GetClient(),
 		Scheme:          mgr.GetScheme(),
 		Recorder:        mgr.GetEventRecorderFor("cloudnative-pg"),
+		limits:          limits,
+		startupJitter:   newStartupJitter(limits.StartupJitter),
 	}
 }
 
@@ -92,7 +104,10 @@ var ErrNextLoop = errors.New("stop this loop and return the associated Result ob
 // +kubebuilder:rbac:groups=admissionregistration.k8s.io,resources=mutatingwebhookconfigurations,verbs=get;update;list;patch
 // +kubebuilder:rbac:groups=admissionregistration.k8s.io,resources=validatingwebhookconfigurations,verbs=get;update;list;patch
 // +kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions,verbs=get;update;list
+// +kubebuilder:rbac:groups=batch,resources=cronjobs,verbs=get;list;delete;patch;create;watch
 // +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;delete;patch;create;watch
+// +kubebuilder:rbac:groups=cert-manager.io,resources=certificates,verbs=get;create;update
+// +kubebuilder:rbac:groups=cert-manager.io,resources=issuers;clusterissuers,verbs=get
 // +kubebuilder:rbac:groups=coordination.k8s.io,resources=leases,verbs=get;create;update
 // +kubebuilder:rbac:groups=monitoring.coreos.com,resources=podmonitors,verbs=get;create;list;watch;delete;patch
 // +kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=create;delete;get;list;watch;update;patch
@@ -101,6 +116,7 @@ var ErrNextLoop = errors.New("stop this loop and return the associated Result ob
 // +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=clusters/status,verbs=get;watch;update;patch
 // +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=rolebindings,verbs=create;patch;update;get;list;watch
 // +kubebuilder:rbac:groups=rbac.authorization.k8s.io,resources=roles,verbs=create;patch;update;get;list;watch
+// +kubebuilder:rbac:groups=storage.k8s.io,resources=storageclasses,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update;watch;delete;patch
 // +kubebuilder:rbac:groups="",resources=configmaps/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
@@ -110,7 +126,7 @@ var ErrNextLoop = errors.New("stop this loop and return the associated Result ob
 // +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;delete;patch;create;watch
 // +kubebuilder:rbac:groups="",resources=pods/exec,verbs=get;list;delete;patch;create;watch
 // +kubebuilder:rbac:groups="",resources=pods/status,verbs=get
-// +kubebuilder:rbac:groups="",resources=secrets,verbs=create;list;get;watch;delete
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=create;list;get;watch;update;patch;delete
 // +kubebuilder:rbac:groups="",resources=serviceaccounts,verbs=create;patch;update;list;watch;get
 // +kubebuilder:rbac:groups="",resources=services,verbs=get;create;delete;update;patch;list;watch
 
@@ -130,6 +146,7 @@ func (r *ClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 	}
 
 	if cluster == nil {
+		r.startupJitter.forget(req.NamespacedName)
 		if err := r.deleteDanglingMonitoringQueries(ctx, req.Namespace); err != nil {
 			contextLogger.Error(
 				err,
@@ -141,11 +158,23 @@ func (r *ClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 		return ctrl.Result{}, err
 	}
 
+	// Spread the reconciliations happening when the operator starts
+	if delay := r.startupJitter.delay(cluster); delay > 0 {
+		contextLogger.Debug("Postponing the first reconciliation", "delay", delay)
+		return ctrl.Result{RequeueAfter: delay}, nil
+	}
+
 	// Run the inner reconcile loop. Translate any ErrNextLoop to an errorless return
 	result, err := r.reconcile(ctx, cluster)
 	if errors.Is(err, ErrNextLoop) {
 		return result, nil
 	}
+	if delay := r.limits.transientErrorDelay(err); delay > 0 {
+		contextLogger.Info("Transient error while reconciling the cluster, retrying",
+			"error", err.Error(),
+			"delay", delay)
+		return ctrl.Result{RequeueAfter: delay}, nil
+	}
 	return result, err
 }
 
@@ -159,6 +188,25 @@ func (r *ClusterReconciler) reconcile(ctx context.Context, cluster *apiv1.Cluste
 		return ctrl.Result{}, nil
 	}
 
+	// A cluster being deleted is only reconciled to take its final backup,
+	// and then to shut its instances down in order, and no finalizer can
+	// be added to it anymore
+	if !cluster.DeletionTimestamp.IsZero() {
+		if controllerutil.ContainsFinalizer(cluster, apiv1.FinalBackupFinalizerName) {
+			return r.reconcileFinalBackup(ctx, cluster)
+		}
+		if controllerutil.ContainsFinalizer(cluster, apiv1.OrderlyShutdownFinalizerName) {
+			return r.reconcileOrderlyShutdown(ctx, cluster)
+		}
+	} else {
+		if err := r.reconcileFinalBackupFinalizer(ctx, cluster); err != nil {
+			return ctrl.Result{}, fmt.Errorf("cannot reconcile the final backup finalizer: %w", err)
+		}
+		if err := r.reconcileOrderlyShutdownFinalizer(ctx, cluster); err != nil {
+			return ctrl.Result{}, fmt.Errorf("cannot reconcile the orderly shutdown finalizer: %w", err)
+		}
+	}
+
 	// IMPORTANT: the following call will delete conditions using
 	// invalid condition reasons.
 	//
@@ -184,6 +232,11 @@ func (r *ClusterReconciler) reconcile(ctx context.Context, cluster *apiv1.Cluste
 		return ctrl.Result{}, fmt.Errorf("cannot reconcile restored Cluster: %w", err)
 	}
 
+	// Adopt again the managed objects which lost their owner reference
+	if err := r.reconcileOrphanResources(ctx, cluster); err != nil {
+		return ctrl.Result{}, fmt.Errorf("cannot adopt the orphan resources: %w", err)
+	}
+
 	// Ensure we have the required global objects
 	if err := r.createPostgresClusterObjects(ctx, cluster); err != nil {
 		return ctrl.Result{}, fmt.Errorf("cannot create Cluster auxiliary objects: %w", err)
@@ -215,9 +268,22 @@ func (r *ClusterReconciler) reconcile(ctx context.Context, cluster *apiv1.Cluste
 			"currentPrimary", cluster.Status.CurrentPrimary,
 			"targetPrimary", cluster.Status.TargetPrimary)
 
+		if err := r.reconcileStuckPrimaryTransition(ctx, cluster, resources); err != nil {
+			return ctrl.Result{}, fmt.Errorf("cannot report the stuck switchover or failover: %w", err)
+		}
+
 		return ctrl.Result{RequeueAfter: 1 * time.Second}, nil
 	}
 
+	// The instances must be stopped while upgrading the
+	// data directory to a new PostgreSQL major version
+	if result, err := r.reconcileMajorUpgrade(ctx, cluster, resources); err != nil || result != nil {
+		if result == nil {
+			result = &ctrl.Result{}
+		}
+		return *result, err
+	}
+
 	// Get the replication status
 	instancesStatus := r.instanceStatusClient.getStatusFromInstances(ctx, resources.instances)
 
@@ -231,6 +297,18 @@ func (r *ClusterReconciler) reconcile(ctx context.Context, cluster *apiv1.Cluste
 		return ctrl.Result{}, fmt.Errorf("cannot update the instances status on the cluster: %w", err)
 	}
 
+	// Only the checksum failures exceeding the baselines recorded in the
+	// status mark an instance as corrupted
+	setChecksumFailuresBaselines(cluster, &instancesStatus)
+
+	// Joining or replicating across PostgreSQL major versions is not possible:
+	// stop here until the instances are aligned by the user
+	if meta.IsStatusConditionTrue(cluster.Status.Conditions, string(apiv1.ConditionMajorVersionMismatch)) {
+		contextLogger.Warning("The instances are running different PostgreSQL major versions, " +
+			"halting the reconciliation until they are aligned")
+		return ctrl.Result{RequeueAfter: 30 * time.Second}, ErrNextLoop
+	}
+
 	// Verify the architecture of all the instances and update the OnlineUpdateEnabled
 	// field in the status
 	onlineUpdateEnabled := configuration.Current.EnableInstanceManagerInplaceUpdates
@@ -316,7 +394,7 @@ func (r *ClusterReconciler) handleSwitchover(
 		return nil, nil
 	}
 	if cluster.Status.Phase == apiv1.PhaseInplaceDeletePrimaryRestart {
-		if cluster.Status.ReadyInstances != cluster.Spec.Instances {
+		if cluster.Status.ReadyInstances != cluster.GetDesiredInstances() {
 			contextLogger.Info("Waiting for the primary to be restarted without triggering a switchover")
 			return nil, nil
 		}
@@ -329,6 +407,18 @@ func (r *ClusterReconciler) handleSwitchover(
 		return nil, nil
 	}
 
+	// The pod of the current primary doesn't exist anymore, i.e. because
+	// its node has been lost: unless the pod is recreated before the
+	// failover delay expires, a new primary needs to be elected. When the
+	// automatic failover is disabled this is reported as a manual failover
+	// request instead
+	primaryMissing := isCurrentPrimaryMissing(cluster, resources)
+	if primaryMissing && !cluster.Spec.DisableAutomaticFailover {
+		if err := r.reportMissingPrimary(ctx, cluster); err != nil {
+			return nil, err
+		}
+	}
+
 	// Update the target primary name from the Pods status.
 	// This means issuing a failover or switchover when needed.
 	selectedPrimary, err := r.updateTargetPrimaryFromPods(ctx, cluster, instancesStatus, resources)
@@ -337,10 +427,22 @@ func (r *ClusterReconciler) handleSwitchover(
 			contextLogger.Info("Waiting for the failover delay to expire")
 			return &ctrl.Result{RequeueAfter: 1 * time.Second}, nil
 		}
+		if err == ErrWaitingOnFailoverCooldown {
+			contextLogger.Info("Waiting for the failover cooldown to expire")
+			return &ctrl.Result{RequeueAfter: 1 * time.Second}, nil
+		}
 		if err == ErrWalReceiversRunning {
 			contextLogger.Info("Waiting for all WAL receivers to be down to elect a new primary")
 			return &ctrl.Result{RequeueAfter: 1 * time.Second}, nil
 		}
+		if err == ErrWaitingForManualFailover {
+			contextLogger.Info("Waiting for a replica to be manually promoted")
+			return &ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+		}
+		if err == ErrNoFailoverCandidate {
+			contextLogger.Info("Waiting for a replica that is not delayed to elect a new primary")
+			return &ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+		}
 		contextLogger.Info("Cannot update target primary: operation cannot be fulfilled. "+
 			"An immediate retry will be scheduled",
 			"cluster", cluster.Name)
@@ -355,8 +457,18 @@ func (r *ClusterReconciler) handleSwitchover(
 
 	// Primary is healthy, No switchover in progress.
 	// If we have a currentPrimaryFailingSince timestamp, let's unset it.
-	if cluster.Status.CurrentPrimaryFailingSinceTimestamp != "" {
+	// The same goes for a pending request of manual failover.
+	if !primaryMissing && (cluster.Status.CurrentPrimaryFailingSinceTimestamp != "" ||
+		meta.IsStatusConditionFalse(cluster.Status.Conditions, string(apiv1.ConditionPrimaryAvailable))) {
 		cluster.Status.CurrentPrimaryFailingSinceTimestamp = ""
+		if meta.IsStatusConditionFalse(cluster.Status.Conditions, string(apiv1.ConditionPrimaryAvailable)) {
+			meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
+				Type:    string(apiv1.ConditionPrimaryAvailable),
+				Status:  metav1.ConditionTrue,
+				Reason:  string(apiv1.ConditionReasonPrimaryAvailable),
+				Message: "The primary is healthy",
+			})
+		}
 		if err := r.Status().Update(ctx, cluster); err != nil {
 			return nil, err
 		}
@@ -393,6 +505,16 @@ func (r *ClusterReconciler) getCluster(
 	if !namespace.DeletionTimestamp.IsZero() {
 		// This happens when you delete a namespace containing a Cluster resource. If that's the case,
 		// let's just wait for the Kubernetes to remove all object in the namespace.
+		// The final backup can't be taken in a namespace which is going away,
+		// so we don't hold its deletion, nor we shut the instances down in order.
+		if controllerutil.ContainsFinalizer(cluster, apiv1.FinalBackupFinalizerName) {
+			if err := r.releaseFinalBackupFinalizer(ctx, cluster); err != nil {
+				return nil, err
+			}
+		}
+		if controllerutil.ContainsFinalizer(cluster, apiv1.OrderlyShutdownFinalizerName) {
+			return nil, r.releaseOrderlyShutdownFinalizer(ctx, cluster)
+		}
 		return nil, nil
 	}
 
@@ -425,9 +547,18 @@ func (r *ClusterReconciler) reconcileResources(
 	// TODO: We should generate a fake pod containing the expected labels and annotations and compare it to the living pod
 
 	// Update the labels for the -rw service to work correctly
-	if err := r.updateRoleLabelsOnPods(ctx, cluster, resources.instances); err != nil {
+	primaryLabelDeferred, err := r.updateRoleLabelsOnPods(ctx, cluster, resources.instances, instancesStatus)
+	if err != nil {
 		return ctrl.Result{}, fmt.Errorf("cannot update role labels on pods: %w", err)
 	}
+	if primaryLabelDeferred {
+		return ctrl.Result{RequeueAfter: 1 * time.Second}, nil
+	}
+
+	// Keep track of the timeline and the LSN of every instance
+	if err := r.updateTimelineOnPods(ctx, instancesStatus); err != nil {
+		return ctrl.Result{}, fmt.Errorf("cannot update timeline labels on pods: %w", err)
+	}
 
 	// updated any labels that are coming from the operator
 	if err := r.updateOperatorLabelsOnInstances(ctx, resources.instances); err != nil {
@@ -465,6 +596,26 @@ func (r *ClusterReconciler) reconcileResources(
 		return ctrl.Result{RequeueAfter: 1 * time.Second}, nil
 	}
 
+	// Restart the replicas stuck on an old timeline after a failover,
+	// for their instance manager to resynchronize them with the primary
+	divergedReplicas, divergedReplicasRequeue := getDivergedReplicasToRestart(cluster, time.Now())
+	if len(divergedReplicas) > 0 {
+		if err := r.restartDivergedReplicas(ctx, cluster, resources, divergedReplicas); err != nil {
+			return ctrl.Result{}, fmt.Errorf("cannot restart the replicas on a diverged timeline: %w", err)
+		}
+		return ctrl.Result{RequeueAfter: 1 * time.Second}, nil
+	}
+
+	// Rebuild the replicas which have been reporting corrupted data for
+	// longer than the grace period, cloning them again from the primary
+	corruptedReplicas, corruptedReplicasRequeue := getCorruptedReplicasToRebuild(cluster, time.Now())
+	if len(corruptedReplicas) > 0 {
+		if err := r.rebuildCorruptedReplicas(ctx, cluster, resources, corruptedReplicas); err != nil {
+			return ctrl.Result{}, fmt.Errorf("cannot rebuild the replicas with corrupted data: %w", err)
+		}
+		return ctrl.Result{RequeueAfter: 1 * time.Second}, nil
+	}
+
 	if res, err := persistentvolumeclaim.ReconcileExistingResources(
 		ctx,
 		r.Client,
@@ -476,7 +627,8 @@ func (r *ClusterReconciler) reconcileResources(
 	}
 
 	// Reconcile Pods
-	if res, err := r.ReconcilePods(ctx, cluster, resources, instancesStatus); err != nil {
+	res, err := r.ReconcilePods(ctx, cluster, resources, instancesStatus)
+	if err != nil {
 		return res, err
 	}
 
@@ -486,10 +638,28 @@ func (r *ClusterReconciler) reconcileResources(
 	}
 
 	// If we still need more instances, we need to wait before setting healthy status
-	if instancesStatus.InstancesReportingStatus() != cluster.Spec.Instances {
+	if instancesStatus.InstancesReportingStatus() != cluster.GetDesiredInstances() {
 		return ctrl.Result{RequeueAfter: 1 * time.Second}, ErrNextLoop
 	}
 
+	// The spec has been fully applied, unless a rolling update has been
+	// deferred to the next maintenance window. Tools like GitOps agents
+	// compare this field with the generation to detect pending changes
+	if res.IsZero() {
+		cluster.Status.ObservedGeneration = cluster.Generation
+	}
+
+	// The replicas stuck on an old timeline need the cluster to be
+	// reconciled again when their grace period expires
+	if divergedReplicasRequeue > 0 && (res.RequeueAfter == 0 || divergedReplicasRequeue < res.RequeueAfter) {
+		res.RequeueAfter = divergedReplicasRequeue
+	}
+
+	// The same applies to the replicas reporting corrupted data
+	if corruptedReplicasRequeue > 0 && (res.RequeueAfter == 0 || corruptedReplicasRequeue < res.RequeueAfter) {
+		res.RequeueAfter = corruptedReplicasRequeue
+	}
+
 	// When everything is reconciled, update the status
 	if err = r.RegisterPhase(ctx, cluster, apiv1.PhaseHealthy, ""); err != nil {
 		return ctrl.Result{}, err
@@ -497,7 +667,9 @@ func (r *ClusterReconciler) reconcileResources(
 
 	r.cleanupCompletedJobs(ctx, resources.jobs)
 
-	return ctrl.Result{}, nil
+	// A rolling update deferred to the next maintenance window needs
+	// the cluster to be reconciled again when the window opens
+	return res, nil
 }
 
 // deleteEvictedPods will delete the Pods that the Kubelet has evicted
@@ -595,6 +767,10 @@ func (r *ClusterReconciler) ReconcilePods(ctx context.Context, cluster *apiv1.Cl
 		return ctrl.Result{}, err
 	}
 
+	if err := r.ensureMountedObjectsHashAnnotation(ctx, cluster, resources); err != nil {
+		return ctrl.Result{}, err
+	}
+
 	// We have these cases now:
 	//
 	// 1 - There is no existent Pod for this PostgreSQL cluster ==> we need to create the
@@ -606,29 +782,63 @@ func (r *ClusterReconciler) ReconcilePods(ctx context.Context, cluster *apiv1.Cl
 	// 3 - We have already some Pods, all they all ready ==> we can create the other
 	// pods joining the node that we already have.
 	if cluster.Status.Instances == 0 {
+		if err := r.ensureStorageClassesExist(ctx, cluster); err != nil {
+			return ctrl.Result{}, err
+		}
 		return r.createPrimaryInstance(ctx, cluster)
 	}
 
+	// When the user allows it, the instances which are not ready because
+	// of a transient readiness dip don't stop the scale up
+	instancesNotReady := instancesStatus.InstancesReportingStatus() < cluster.Status.Instances
+	readinessDipTolerated := false
+	if instancesNotReady && cluster.IsReadinessDipTolerated() {
+		tolerated, err := r.areReadinessDipsTolerable(ctx, cluster, instancesStatus)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		readinessDipTolerated = tolerated
+	}
+
 	// Stop acting here if there are non-ready Pods unless in maintenance reusing PVCs.
 	// The user have chosen to wait for the missing nodes to come up
 	if !(cluster.IsNodeMaintenanceWindowInProgress() && cluster.IsReusePVCEnabled()) &&
-		instancesStatus.InstancesReportingStatus() < cluster.Status.Instances {
+		instancesNotReady && !readinessDipTolerated {
 		contextLogger.Debug("Waiting for Pods to be ready")
 		return ctrl.Result{RequeueAfter: 1 * time.Second}, ErrNextLoop
 	}
 
 	// Are there missing nodes? Let's create one
-	if cluster.Status.Instances < cluster.Spec.Instances &&
-		instancesStatus.InstancesReportingStatus() == cluster.Status.Instances {
+	if cluster.Status.Instances < cluster.GetDesiredInstances() &&
+		(instancesStatus.InstancesReportingStatus() == cluster.Status.Instances || readinessDipTolerated) {
+		// The status may lag behind the real number of Pods and Jobs, so we
+		// never join a new instance when the ones existing or being created
+		// are already enough
+		if expectedInstances := resources.countInstancesAndPendingJoins(); expectedInstances >=
+			cluster.GetDesiredInstances() {
+			contextLogger.Info("Not joining a new instance, the desired ones already exist or are being created",
+				"statusInstances", cluster.Status.Instances,
+				"expectedInstances", expectedInstances,
+				"desiredInstances", cluster.GetDesiredInstances())
+			return ctrl.Result{RequeueAfter: 1 * time.Second}, ErrNextLoop
+		}
+		if err := r.ensureStorageClassesExist(ctx, cluster); err != nil {
+			return ctrl.Result{}, err
+		}
 		newNodeSerial, err := r.generateNodeSerial(ctx, cluster)
 		if err != nil {
 			return ctrl.Result{}, fmt.Errorf("cannot generate node serial: %w", err)
 		}
-		return r.joinReplicaInstance(ctx, newNodeSerial, cluster)
+		// The instances which can be promoted are created first,
+		// the read replicas are added on top of them
+		readReplica := cluster.Status.Instances-len(cluster.Status.ReadReplicas) >= cluster.Spec.Instances
+		return r.joinReplicaInstance(ctx, newNodeSerial, cluster, readReplica)
 	}
 
-	// Are there nodes to be removed? Remove one of them
-	if cluster.Status.Instances > cluster.Spec.Instances {
+	// Are there nodes to be removed? Remove one of them. The read replicas
+	// and the other instances are scaled independently
+	if len(cluster.Status.ReadReplicas) > cluster.GetReadReplicas() ||
+		cluster.Status.Instances-len(cluster.Status.ReadReplicas) > cluster.Spec.Instances {
 		if err := r.scaleDownCluster(ctx, cluster, resources); err != nil {
 			return ctrl.Result{}, fmt.Errorf("cannot scale down cluster: %w", err)
 		}
@@ -636,7 +846,7 @@ func (r *ClusterReconciler) ReconcilePods(ctx context.Context, cluster *apiv1.Cl
 
 	// Stop acting here if there are non-ready Pods
 	// In the rest of the function we are sure that
-	// cluster.Status.Instances == cluster.Spec.Instances and
+	// cluster.Status.Instances == cluster.GetDesiredInstances() and
 	// we don't need to modify the cluster topology
 	if cluster.Status.ReadyInstances != cluster.Status.Instances ||
 		cluster.Status.ReadyInstances != len(instancesStatus.Items) ||
@@ -645,7 +855,75 @@ func (r *ClusterReconciler) ReconcilePods(ctx context.Context, cluster *apiv1.Cl
 		return ctrl.Result{RequeueAfter: 1 * time.Second}, ErrNextLoop
 	}
 
-	return r.handleRollingUpdate(ctx, cluster, instancesStatus)
+	// Request the scheduled restart of the instances, if its time has come,
+	// to have it applied by the rolling update
+	scheduledRestartWait, err := r.reconcileScheduledRestart(ctx, cluster, instancesStatus)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("cannot reconcile the scheduled restart: %w", err)
+	}
+
+	result, err := r.handleRollingUpdate(ctx, cluster, instancesStatus)
+	if err == nil && scheduledRestartWait > 0 &&
+		(result.RequeueAfter == 0 || scheduledRestartWait < result.RequeueAfter) {
+		result.RequeueAfter = scheduledRestartWait
+	}
+	return result, err
+}
+
+// areReadinessDipsTolerable checks whether every instance which is not
+// ready is either being intentionally replaced, because its node is being
+// drained, or has been not ready for less than the tolerance period.
+// Any other not ready instance is considered an unexpected outage
+func (r *ClusterReconciler) areReadinessDipsTolerable(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	instancesStatus postgres.PostgresqlStatusList,
+) (bool, error) {
+	contextLogger := log.FromContext(ctx)
+
+	now := time.Now()
+	tolerancePeriod := cluster.GetReadinessTolerancePeriod()
+	for _, item := range instancesStatus.Items {
+		if utils.IsPodReady(item.Pod) || item.MightBeUnavailable {
+			continue
+		}
+
+		// Instances with corrupted data are intentionally not ready
+		// until they are rebuilt
+		if cluster.IsInstanceCordoned(item.Pod.Name) {
+			continue
+		}
+
+		if item.Pod.Spec.NodeName != "" {
+			unschedulable, err := r.isNodeUnschedulable(ctx, item.Pod.Spec.NodeName)
+			switch {
+			case apierrs.IsNotFound(err):
+				// The node has already been removed from the cluster
+				unschedulable = true
+			case err != nil:
+				return false, err
+			}
+			if unschedulable {
+				contextLogger.Debug("Tolerating a not ready instance being replaced",
+					"pod", item.Pod.Name, "node", item.Pod.Spec.NodeName)
+				continue
+			}
+		}
+
+		notReadyDuration := utils.GetPodNotReadyDuration(item.Pod, now)
+		if notReadyDuration >= tolerancePeriod {
+			contextLogger.Info("Instance not ready for longer than the tolerance period",
+				"pod", item.Pod.Name,
+				"notReadyDuration", notReadyDuration,
+				"tolerancePeriod", tolerancePeriod)
+			return false, nil
+		}
+
+		contextLogger.Debug("Tolerating a transient readiness dip",
+			"pod", item.Pod.Name, "notReadyDuration", notReadyDuration)
+	}
+
+	return true, nil
 }
 
 func (r *ClusterReconciler) ensureHealthyPVCsAnnotation(
@@ -687,6 +965,16 @@ func (r *ClusterReconciler) handleRollingUpdate(
 ) (ctrl.Result, error) {
 	contextLogger := log.FromContext(ctx)
 
+	// Rolling updates are only applied inside the maintenance window, if any
+	wait, err := r.deferRolloutToMaintenanceWindow(ctx, cluster, instancesStatus)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if wait > 0 {
+		contextLogger.Info("Deferring the rolling update to the next maintenance window", "wait", wait)
+		return ctrl.Result{RequeueAfter: wait}, nil
+	}
+
 	// If we need to roll out a restart of any instance, this is the right moment
 	// Do I have to roll out a new image?
 	done, err := r.rolloutDueToCondition(ctx, cluster, &instancesStatus, IsPodNeedingRollout)
@@ -734,8 +1022,9 @@ func (r *ClusterReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manag
 
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&apiv1.Cluster{}).
-		Owns(&corev1.Pod{}).
+		Owns(&corev1.Pod{}, builder.WithPredicates(podsPredicate)).
 		Owns(&batchv1.Job{}).
+		Owns(&batchv1.CronJob{}).
 		Owns(&corev1.Service{}).
 		Owns(&corev1.PersistentVolumeClaim{}).
 		Owns(&policyv1.PodDisruptionBudget{}).
@@ -758,6 +1047,7 @@ func (r *ClusterReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manag
 			handler.EnqueueRequestsFromMapFunc(r.mapNodeToClusters(ctx)),
 			builder.WithPredicates(nodesPredicate),
 		).
+		WithOptions(controller.Options{RateLimiter: r.limits.newRateLimiter()}).
 		Complete(r)
 }
 
@@ -893,7 +1183,20 @@ func (r *ClusterReconciler) mapSecretsToClusters(ctx context.Context) handler.Ma
 			return nil
 		}
 		// build requests for cluster referring the secret
-		return filterClustersUsingSecret(clusters, secret)
+		requests := filterClustersUsingSecret(clusters, secret)
+
+		// The secrets of the credentials namespaces are copied
+		// by the clusters living in the other namespaces
+		if isInCredentialsNamespace(secret) {
+			var allClusters apiv1.ClusterList
+			if err := r.List(ctx, &allClusters); err != nil {
+				log.FromContext(ctx).Error(err, "while getting cluster list")
+				return requests
+			}
+			requests = append(requests, filterClustersCopyingSecret(allClusters, secret)...)
+		}
+
+		return requests
 	}
 }
 
@@ -18,13 +18,18 @@ package controllers
 
 import (
 	"context"
+	"sync"
 	"time"
 
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -60,6 +65,26 @@ var _ = Describe("Filtering cluster", func() {
 		req := filterClustersUsingConfigMap(clusterList, &configMap)
 		Expect(req).ToNot(BeNil())
 	})
+
+	It("copying a secret from the credentials namespace", func() {
+		cluster := apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "tenant-a"},
+			Spec: apiv1.ClusterSpec{
+				CredentialsNamespace: "credentials",
+				Certificates:         &apiv1.CertificatesConfiguration{ServerTLSSecret: "server-tls"},
+			},
+		}
+		clusterList := apiv1.ClusterList{Items: []apiv1.Cluster{cluster}}
+
+		secret := corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "server-tls", Namespace: "credentials"}}
+		Expect(filterClustersCopyingSecret(clusterList, &secret)).To(HaveLen(1))
+
+		secret.Namespace = "other"
+		Expect(filterClustersCopyingSecret(clusterList, &secret)).To(BeEmpty())
+
+		secret = corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "credentials"}}
+		Expect(filterClustersCopyingSecret(clusterList, &secret)).To(BeEmpty())
+	})
 })
 
 var _ = Describe("Updating target primary", func() {
@@ -200,3 +225,109 @@ var _ = Describe("Updating target primary", func() {
 		})
 	})
 })
+
+var _ = Describe("Joining new instances", func() {
+	// newInstancesStatus creates a status list reporting every passed Pod
+	// as a ready instance
+	newInstancesStatus := func(pods []corev1.Pod) postgres.PostgresqlStatusList {
+		var statusList postgres.PostgresqlStatusList
+		for _, pod := range pods {
+			statusList.Items = append(statusList.Items, postgres.PostgresqlStatus{
+				Pod:        pod,
+				IsPodReady: true,
+			})
+		}
+		return statusList
+	}
+
+	It("counts the active instances and the running Jobs only once", func() {
+		cluster := &apiv1.Cluster{}
+		cluster.Name = "cluster-example"
+		pods := []corev1.Pod{
+			{},
+			{},
+		}
+		pods[0].Name = "cluster-example-1"
+		pods[1].Name = "cluster-example-2"
+
+		resources := &managedResources{
+			instances: corev1.PodList{Items: pods},
+			jobs: batchv1.JobList{Items: []batchv1.Job{
+				*specs.JoinReplicaInstance(*cluster, 2),
+				*specs.JoinReplicaInstance(*cluster, 3),
+			}},
+		}
+		Expect(resources.countInstancesAndPendingJoins()).To(Equal(3))
+
+		By("ignoring the Jobs not bound to an instance", func() {
+			unboundJob := batchv1.Job{}
+			unboundJob.Name = "cluster-example-unbound"
+			resources.jobs.Items = append(resources.jobs.Items, unboundJob)
+			Expect(resources.countInstancesAndPendingJoins()).To(Equal(3))
+		})
+
+		By("ignoring the completed Jobs", func() {
+			resources.jobs.Items[1].Status.Succeeded = 1
+			Expect(resources.countInstancesAndPendingJoins()).To(Equal(2))
+		})
+
+		By("ignoring the Pods which are not active", func() {
+			resources.instances.Items[0].Status.Phase = corev1.PodFailed
+			Expect(resources.countInstancesAndPendingJoins()).To(Equal(1))
+		})
+	})
+
+	It("doesn't join a new instance when the status is lagging behind the Pods", func() {
+		ctx := context.Background()
+		namespace := newFakeNamespace()
+		cluster := newFakeCNPGCluster(namespace)
+		pods := generateFakeClusterPodsWithDefaultClient(cluster, true)
+		cluster.Status.Instances = len(pods) - 1
+
+		resources := &managedResources{
+			instances: corev1.PodList{Items: pods},
+		}
+		instancesStatus := newInstancesStatus(pods[:len(pods)-1])
+
+		_, err := clusterReconciler.ReconcilePods(ctx, cluster, resources, instancesStatus)
+		Expect(err).To(Equal(ErrNextLoop))
+
+		var jobs batchv1.JobList
+		Expect(k8sClient.List(ctx, &jobs, client.InNamespace(namespace))).To(Succeed())
+		Expect(jobs.Items).To(BeEmpty())
+	})
+
+	It("never creates more instances than requested when reconciling concurrently", func() {
+		const reconcilers = 10
+
+		ctx := context.Background()
+		namespace := newFakeNamespace()
+		cluster := newFakeCNPGCluster(namespace)
+		pods := generateFakeClusterPodsWithDefaultClient(cluster, true)[:cluster.Spec.Instances-1]
+		cluster.Status.Instances = len(pods)
+		cluster.Status.LatestGeneratedNode = cluster.Spec.Instances
+
+		resources := &managedResources{
+			instances: corev1.PodList{Items: pods},
+		}
+		instancesStatus := newInstancesStatus(pods)
+
+		wg := sync.WaitGroup{}
+		wg.Add(reconcilers)
+		for i := 0; i < reconcilers; i++ {
+			// Each reconciler works on its own copy of the cluster, all of
+			// them sharing the same stale status
+			go func(cluster *apiv1.Cluster) {
+				defer GinkgoRecover()
+				defer wg.Done()
+				_, _ = clusterReconciler.ReconcilePods(ctx, cluster, resources, instancesStatus)
+			}(cluster.DeepCopy())
+		}
+		wg.Wait()
+
+		var jobs batchv1.JobList
+		Expect(k8sClient.List(ctx, &jobs, client.InNamespace(namespace),
+			client.MatchingLabels{utils.ClusterLabelName: cluster.Name})).To(Succeed())
+		Expect(jobs.Items).To(HaveLen(1))
+	})
+})
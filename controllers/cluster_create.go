@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"strings"
 	"time"
 
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
@@ -28,6 +29,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	policyv1 "k8s.io/api/policy/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
+	storagev1 "k8s.io/api/storage/v1"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -37,6 +39,7 @@ import (
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/certs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/conditions"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/reconciler/persistentvolumeclaim"
@@ -48,7 +51,14 @@ import (
 
 // createPostgresClusterObjects ensures that we have the required global objects
 func (r *ClusterReconciler) createPostgresClusterObjects(ctx context.Context, cluster *apiv1.Cluster) error {
-	err := r.setupPostgresPKI(ctx, cluster)
+	// The secrets copied from the credentials namespace may be
+	// needed by the certificates
+	err := r.reconcileMirroredSecrets(ctx, cluster)
+	if err != nil {
+		return err
+	}
+
+	err = r.setupPostgresPKI(ctx, cluster)
 	if err != nil {
 		return err
 	}
@@ -95,6 +105,11 @@ func (r *ClusterReconciler) createPostgresClusterObjects(ctx context.Context, cl
 		return err
 	}
 
+	err = r.reconcileWalMirror(ctx, cluster)
+	if err != nil {
+		return err
+	}
+
 	// TODO: only required to cleanup custom monitoring queries configmaps from older versions (v1.10 and v1.11)
 	// 		 that could have been copied with the source configmap name instead of the new default one.
 	// 		 Should be removed in future releases.
@@ -154,6 +169,11 @@ func (r *ClusterReconciler) reconcilePostgresSecrets(ctx context.Context, cluste
 		return err
 	}
 
+	err = r.reconcileMonitoringSecret(ctx, cluster)
+	if err != nil {
+		return err
+	}
+
 	err = r.reconcilePoolerSecrets(ctx, cluster)
 	if err != nil {
 		return err
@@ -186,6 +206,10 @@ func (r *ClusterReconciler) reconcileSuperuserSecret(ctx context.Context, cluste
 				return err
 			}
 		}
+
+		if err := r.refreshSecretHost(ctx, cluster, cluster.GetSuperuserSecretName()); err != nil {
+			return err
+		}
 	}
 
 	// If we don't have Superuser enabled we make sure the automatically generated secret doesn't exist
@@ -230,10 +254,82 @@ func (r *ClusterReconciler) reconcileAppUserSecret(ctx context.Context, cluster
 				return err
 			}
 		}
+
+		return r.refreshSecretHost(ctx, cluster, cluster.GetApplicationSecretName())
 	}
 	return nil
 }
 
+// reconcileMonitoringSecret creates the secret of the monitoring role when
+// it is enabled, and deletes it when it is disabled. The password is
+// generated only once: changing it in the secret rotates the password
+// of the role
+func (r *ClusterReconciler) reconcileMonitoringSecret(ctx context.Context, cluster *apiv1.Cluster) error {
+	if !cluster.IsMonitoringRoleEnabled() {
+		var secret corev1.Secret
+		err := r.Get(
+			ctx,
+			client.ObjectKey{Namespace: cluster.Namespace, Name: cluster.GetMonitoringSecretName()},
+			&secret)
+		if err != nil {
+			if apierrs.IsNotFound(err) || apierrs.IsForbidden(err) {
+				return nil
+			}
+			return err
+		}
+
+		if _, owned := IsOwnedByCluster(&secret); owned {
+			return r.Delete(ctx, &secret)
+		}
+		return nil
+	}
+
+	monitoringPassword, err := password.Generate(64, 10, 0, false, true)
+	if err != nil {
+		return err
+	}
+	monitoringSecret := specs.CreateSecret(
+		cluster.GetMonitoringSecretName(),
+		cluster.Namespace,
+		cluster.GetServiceReadWriteName(),
+		"*",
+		apiv1.MonitoringUserName,
+		monitoringPassword)
+
+	cluster.SetInheritedDataAndOwnership(&monitoringSecret.ObjectMeta)
+	if err := resources.CreateIfNotFound(ctx, r.Client, monitoringSecret); err != nil {
+		if !apierrs.IsAlreadyExists(err) {
+			return err
+		}
+	}
+
+	return r.refreshSecretHost(ctx, cluster, cluster.GetMonitoringSecretName())
+}
+
+// refreshSecretHost updates the host in the pgpass of a secret generated
+// by the operator, which is the read-write service, after the services
+// have been renamed. The secrets provided by the user are never changed
+func (r *ClusterReconciler) refreshSecretHost(ctx context.Context, cluster *apiv1.Cluster, secretName string) error {
+	var secret corev1.Secret
+	if err := r.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: secretName}, &secret); err != nil {
+		return client.IgnoreNotFound(err)
+	}
+	if _, owned := IsOwnedByCluster(&secret); !owned {
+		return nil
+	}
+
+	host, rest, found := strings.Cut(string(secret.Data["pgpass"]), ":")
+	if !found || host == cluster.GetServiceReadWriteName() {
+		return nil
+	}
+
+	log.FromContext(ctx).Info("Updating the host in the secret after renaming the services",
+		"secret", secretName, "host", cluster.GetServiceReadWriteName())
+	origSecret := secret.DeepCopy()
+	secret.Data["pgpass"] = []byte(cluster.GetServiceReadWriteName() + ":" + rest)
+	return r.Patch(ctx, &secret, client.MergeFrom(origSecret))
+}
+
 func (r *ClusterReconciler) reconcilePoolerSecrets(ctx context.Context, cluster *apiv1.Cluster) error {
 	if cluster.Status.PoolerIntegrations == nil {
 		return nil
@@ -272,38 +368,79 @@ func (r *ClusterReconciler) reconcilePoolerSecrets(ctx context.Context, cluster
 }
 
 func (r *ClusterReconciler) createPostgresServices(ctx context.Context, cluster *apiv1.Cluster) error {
-	anyService := specs.CreateClusterAnyService(*cluster)
-	cluster.SetInheritedDataAndOwnership(&anyService.ObjectMeta)
+	services := []*corev1.Service{
+		specs.CreateClusterAnyService(*cluster),
+		specs.CreateClusterReadService(*cluster),
+		specs.CreateClusterReadOnlyService(*cluster),
+		specs.CreateClusterReadWriteService(*cluster),
+	}
 
-	if err := resources.CreateIfNotFound(ctx, r.Client, anyService); err != nil {
-		if !apierrs.IsAlreadyExists(err) {
+	for _, service := range services {
+		cluster.SetInheritedDataAndOwnership(&service.ObjectMeta)
+		if err := r.createOrPatchService(ctx, service); err != nil {
 			return err
 		}
 	}
 
-	readService := specs.CreateClusterReadService(*cluster)
-	cluster.SetInheritedDataAndOwnership(&readService.ObjectMeta)
+	// The services with the previous names are removed only
+	// once the ones with the new names have been created
+	return r.deleteObsoleteServices(ctx, cluster)
+}
 
-	if err := resources.CreateIfNotFound(ctx, r.Client, readService); err != nil {
-		if !apierrs.IsAlreadyExists(err) {
+// createOrPatchService creates a service generated by the operator, or
+// restores its selector and ports when they have been manually changed,
+// since that would break the routing of the connections
+func (r *ClusterReconciler) createOrPatchService(ctx context.Context, service *corev1.Service) error {
+	var currentService corev1.Service
+	err := r.Get(ctx, client.ObjectKeyFromObject(service), &currentService)
+	if apierrs.IsNotFound(err) {
+		if err := r.Create(ctx, service); err != nil && !apierrs.IsAlreadyExists(err) {
 			return err
 		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("while getting service %s: %w", service.Name, err)
 	}
 
-	readOnlyService := specs.CreateClusterReadOnlyService(*cluster)
-	cluster.SetInheritedDataAndOwnership(&readOnlyService.ObjectMeta)
+	if specs.IsServiceAligned(&currentService, service) {
+		return nil
+	}
 
-	if err := resources.CreateIfNotFound(ctx, r.Client, readOnlyService); err != nil {
-		if !apierrs.IsAlreadyExists(err) {
-			return err
-		}
+	log.FromContext(ctx).Info("Restoring the selector and the ports of the service",
+		"service", service.Name,
+		"selector", currentService.Spec.Selector,
+		"ports", currentService.Spec.Ports)
+	origService := currentService.DeepCopy()
+	specs.AlignService(&currentService, service)
+	if err := r.Patch(ctx, &currentService, client.MergeFrom(origService)); err != nil {
+		return fmt.Errorf("while patching service %s: %w", service.Name, err)
 	}
 
-	readWriteService := specs.CreateClusterReadWriteService(*cluster)
-	cluster.SetInheritedDataAndOwnership(&readWriteService.ObjectMeta)
+	return nil
+}
 
-	if err := resources.CreateIfNotFound(ctx, r.Client, readWriteService); err != nil {
-		if !apierrs.IsAlreadyExists(err) {
+// deleteObsoleteServices removes the read-write, read-only and read services
+// generated by the operator with a naming scheme which is not the current one
+func (r *ClusterReconciler) deleteObsoleteServices(ctx context.Context, cluster *apiv1.Cluster) error {
+	var services corev1.ServiceList
+	if err := r.List(
+		ctx,
+		&services,
+		client.InNamespace(cluster.Namespace),
+		client.MatchingLabels{utils.ClusterLabelName: cluster.Name},
+	); err != nil {
+		return err
+	}
+
+	for idx := range services.Items {
+		service := &services.Items[idx]
+		if !isObsoleteClusterService(cluster, service) {
+			continue
+		}
+
+		log.FromContext(ctx).Info("Deleting the service renamed by the naming scheme", "service", service.Name)
+		if err := r.Delete(ctx, service); err != nil && !apierrs.IsNotFound(err) {
 			return err
 		}
 	}
@@ -311,6 +448,27 @@ func (r *ClusterReconciler) createPostgresServices(ctx context.Context, cluster
 	return nil
 }
 
+// isObsoleteClusterService checks if a service has been generated by the
+// operator for the cluster with a naming scheme which is not the current one
+func isObsoleteClusterService(cluster *apiv1.Cluster, service *corev1.Service) bool {
+	if !metav1.IsControlledBy(service, cluster) ||
+		service.Spec.Selector[utils.ClusterLabelName] != cluster.Name {
+		return false
+	}
+
+	switch service.Name {
+	case cluster.GetServiceAnyName(),
+		cluster.GetServiceReadName(),
+		cluster.GetServiceReadOnlyName(),
+		cluster.GetServiceReadWriteName():
+		return false
+	}
+
+	return strings.HasSuffix(service.Name, apiv1.ServiceReadSuffix) ||
+		strings.HasSuffix(service.Name, apiv1.ServiceReadOnlySuffix) ||
+		strings.HasSuffix(service.Name, apiv1.ServiceReadWriteSuffix)
+}
+
 // createOrPatchOwnedPodDisruptionBudget ensures that we have a PDB requiring to remove one node at a time
 func (r *ClusterReconciler) createOrPatchOwnedPodDisruptionBudget(
 	ctx context.Context,
@@ -403,6 +561,20 @@ func (r *ClusterReconciler) deletePodDisruptionBudget(
 // cluster with the latest cluster specification
 func (r *ClusterReconciler) createOrPatchServiceAccount(ctx context.Context, cluster *apiv1.Cluster) error {
 	var sa corev1.ServiceAccount
+	if cluster.Spec.ServiceAccountName != "" {
+		// The service account is managed by the user, we only
+		// need to check that it exists before creating any Pod
+		err := r.Get(ctx, client.ObjectKey{Name: cluster.Spec.ServiceAccountName, Namespace: cluster.Namespace}, &sa)
+		if apierrs.IsNotFound(err) {
+			r.Recorder.Eventf(cluster, "Warning", "ServiceAccountNotFound",
+				"The service account %s doesn't exist", cluster.Spec.ServiceAccountName)
+		}
+		if err != nil {
+			return fmt.Errorf("while getting service account %s: %w", cluster.Spec.ServiceAccountName, err)
+		}
+		return nil
+	}
+
 	if err := r.Get(ctx, client.ObjectKey{Name: cluster.Name, Namespace: cluster.Namespace}, &sa); err != nil {
 		if !apierrs.IsNotFound(err) {
 			return fmt.Errorf("while getting service account: %w", err)
@@ -832,6 +1004,67 @@ func (r *ClusterReconciler) createOrPatchPodMonitor(ctx context.Context, cluster
 	}
 }
 
+// reconcileWalMirror creates, updates or deletes the CronJob mirroring
+// the WAL archive to the secondary object store
+func (r *ClusterReconciler) reconcileWalMirror(ctx context.Context, cluster *apiv1.Cluster) error {
+	contextLogger := log.FromContext(ctx)
+
+	cronJob := &batchv1.CronJob{}
+	if err := r.Get(
+		ctx,
+		client.ObjectKey{
+			Name:      specs.GetWalMirrorCronJobName(cluster.Name),
+			Namespace: cluster.Namespace,
+		},
+		cronJob,
+	); err != nil {
+		if !apierrs.IsNotFound(err) {
+			return fmt.Errorf("while getting the WAL mirror cronjob: %w", err)
+		}
+		cronJob = nil
+	}
+
+	// Mirror disabled - delete the cronjob, if present
+	if !cluster.Spec.Backup.IsWalMirrorEnabled() {
+		if cronJob == nil {
+			return nil
+		}
+		contextLogger.Info("Deleting the WAL mirror CronJob")
+		if err := r.Delete(ctx, cronJob); err != nil && !apierrs.IsNotFound(err) {
+			return err
+		}
+		return nil
+	}
+
+	newCronJob, err := specs.CreateWalMirrorCronJob(*cluster)
+	if err != nil {
+		return err
+	}
+
+	// Mirror enabled and no cronjob - create it
+	if cronJob == nil {
+		contextLogger.Info("Creating the WAL mirror CronJob")
+		cluster.SetInheritedDataAndOwnership(&newCronJob.ObjectMeta)
+		return r.Create(ctx, newCronJob)
+	}
+
+	// Mirror enabled and cronjob present - update it if needed
+	specHash := newCronJob.Annotations[specs.WalMirrorSpecHashAnnotationName]
+	if cronJob.Annotations[specs.WalMirrorSpecHashAnnotationName] == specHash {
+		return nil
+	}
+
+	origCronJob := cronJob.DeepCopy()
+	cronJob.Spec = newCronJob.Spec
+	if cronJob.Annotations == nil {
+		cronJob.Annotations = make(map[string]string)
+	}
+	cronJob.Annotations[specs.WalMirrorSpecHashAnnotationName] = specHash
+
+	contextLogger.Info("Updating the WAL mirror CronJob")
+	return r.Patch(ctx, cronJob, client.MergeFrom(origCronJob))
+}
+
 // createRole creates the role
 func (r *ClusterReconciler) createRole(ctx context.Context, cluster *apiv1.Cluster, backupOrigin *apiv1.Backup) error {
 	role := specs.CreateRole(*cluster, backupOrigin)
@@ -848,7 +1081,7 @@ func (r *ClusterReconciler) createRole(ctx context.Context, cluster *apiv1.Clust
 
 // createRoleBinding creates the role binding
 func (r *ClusterReconciler) createRoleBinding(ctx context.Context, cluster *apiv1.Cluster) error {
-	roleBinding := specs.CreateRoleBinding(cluster.ObjectMeta)
+	roleBinding := specs.CreateRoleBinding(cluster.ObjectMeta, cluster.GetServiceAccountName())
 	cluster.SetInheritedDataAndOwnership(&roleBinding.ObjectMeta)
 
 	err := r.Create(ctx, &roleBinding)
@@ -870,6 +1103,35 @@ func (r *ClusterReconciler) generateNodeSerial(ctx context.Context, cluster *api
 	return cluster.Status.LatestGeneratedNode, nil
 }
 
+// ensureStorageClassesExist checks that the storage classes requested for
+// the volumes of the cluster exist before creating the PVCs of a new instance,
+// as a PVC requesting a missing storage class would be pending forever
+func (r *ClusterReconciler) ensureStorageClassesExist(ctx context.Context, cluster *apiv1.Cluster) error {
+	storageConfigurations := []*apiv1.StorageConfiguration{&cluster.Spec.StorageConfiguration, cluster.Spec.WalStorage}
+	for idx := range cluster.Spec.Tablespaces {
+		storageConfigurations = append(storageConfigurations, &cluster.Spec.Tablespaces[idx].Storage)
+	}
+
+	for _, storageConfiguration := range storageConfigurations {
+		storageClassName := storageConfiguration.GetStorageClassName()
+		if storageClassName == nil || *storageClassName == "" {
+			continue
+		}
+
+		var storageClass storagev1.StorageClass
+		err := r.Get(ctx, client.ObjectKey{Name: *storageClassName}, &storageClass)
+		if apierrs.IsNotFound(err) {
+			r.Recorder.Eventf(cluster, "Warning", "StorageClassNotFound",
+				"The storage class %s doesn't exist", *storageClassName)
+		}
+		if err != nil {
+			return fmt.Errorf("while getting storage class %s: %w", *storageClassName, err)
+		}
+	}
+
+	return nil
+}
+
 func (r *ClusterReconciler) createPrimaryInstance(
 	ctx context.Context,
 	cluster *apiv1.Cluster,
@@ -896,6 +1158,27 @@ func (r *ClusterReconciler) createPrimaryInstance(
 		return ctrl.Result{}, nil
 	}
 
+	// The cluster to be cloned must be checked before generating the node
+	// serial, so that the primary instance can be created once it is available
+	unavailableReason, err := r.checkPgBaseBackupSourceCluster(ctx, cluster)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if unavailableReason != "" {
+		contextLogger.Info("Waiting for the source cluster to be cloned", "reason", unavailableReason)
+		r.Recorder.Event(cluster, "Warning", "SourceClusterUnavailable", unavailableReason)
+		condition := metav1.Condition{
+			Type:    string(apiv1.ConditionCloneCompleted),
+			Status:  metav1.ConditionFalse,
+			Reason:  string(apiv1.ConditionReasonSourceClusterUnavailable),
+			Message: unavailableReason,
+		}
+		if err := conditions.Update(ctx, r.Client, cluster, &condition); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+	}
+
 	// Generate a new node serial
 	nodeSerial, err := r.generateNodeSerial(ctx, cluster)
 	if err != nil {
@@ -930,8 +1213,13 @@ func (r *ClusterReconciler) createPrimaryInstance(
 		}
 	}
 
+	if err := r.createTablespacePVCs(ctx, cluster, nodeSerial, false); err != nil {
+		return ctrl.Result{RequeueAfter: time.Minute}, err
+	}
+
 	// We are bootstrapping a cluster and in need to create the first node
 	var job *batchv1.Job
+	phase := apiv1.PhaseFirstPrimary
 
 	switch {
 	case cluster.Spec.Bootstrap != nil && cluster.Spec.Bootstrap.Recovery != nil:
@@ -953,6 +1241,7 @@ func (r *ClusterReconciler) createPrimaryInstance(
 
 		r.Recorder.Event(cluster, "Normal", "CreatingInstance", "Primary instance (from backup)")
 		job = specs.CreatePrimaryJobViaRecovery(*cluster, nodeSerial, backup)
+		phase = apiv1.PhaseRestoring
 	case cluster.Spec.Bootstrap != nil && cluster.Spec.Bootstrap.PgBaseBackup != nil:
 		r.Recorder.Event(cluster, "Normal", "CreatingInstance", "Primary instance (from physical backup)")
 		job = specs.CreatePrimaryJobViaPgBaseBackup(*cluster, nodeSerial)
@@ -972,7 +1261,7 @@ func (r *ClusterReconciler) createPrimaryInstance(
 		return ctrl.Result{}, err
 	}
 
-	err = r.RegisterPhase(ctx, cluster, apiv1.PhaseFirstPrimary,
+	err = r.RegisterPhase(ctx, cluster, phase,
 		fmt.Sprintf("Creating primary instance %v", podName))
 	if err != nil {
 		return ctrl.Result{}, err
@@ -1034,10 +1323,13 @@ func (r *ClusterReconciler) getOriginBackup(ctx context.Context, cluster *apiv1.
 	return &backup, nil
 }
 
+// joinReplicaInstance creates a new replica joining the cluster, which is
+// a read replica, never promoted, when readReplica is true
 func (r *ClusterReconciler) joinReplicaInstance(
 	ctx context.Context,
 	nodeSerial int,
 	cluster *apiv1.Cluster,
+	readReplica bool,
 ) (ctrl.Result, error) {
 	contextLogger := log.FromContext(ctx)
 
@@ -1048,7 +1340,8 @@ func (r *ClusterReconciler) joinReplicaInstance(
 
 	contextLogger.Info("Creating new Job",
 		"job", job.Name,
-		"primary", false)
+		"primary", false,
+		"readReplica", readReplica)
 
 	r.Recorder.Eventf(cluster, "Normal", "CreatingInstance",
 		"Creating instance %v-%v", cluster.Name, nodeSerial)
@@ -1089,10 +1382,11 @@ func (r *ClusterReconciler) joinReplicaInstance(
 		ctx,
 		cluster,
 		&persistentvolumeclaim.CreateConfiguration{
-			Status:     persistentvolumeclaim.StatusInitializing,
-			NodeSerial: nodeSerial,
-			Role:       utils.PVCRolePgData,
-			Storage:    cluster.Spec.StorageConfiguration,
+			Status:      persistentvolumeclaim.StatusInitializing,
+			NodeSerial:  nodeSerial,
+			Role:        utils.PVCRolePgData,
+			Storage:     cluster.Spec.StorageConfiguration,
+			ReadReplica: readReplica,
 		},
 	); err != nil {
 		return ctrl.Result{RequeueAfter: time.Minute}, err
@@ -1103,16 +1397,21 @@ func (r *ClusterReconciler) joinReplicaInstance(
 			ctx,
 			cluster,
 			&persistentvolumeclaim.CreateConfiguration{
-				Status:     persistentvolumeclaim.StatusInitializing,
-				NodeSerial: nodeSerial,
-				Role:       utils.PVCRolePgWal,
-				Storage:    *cluster.Spec.WalStorage,
+				Status:      persistentvolumeclaim.StatusInitializing,
+				NodeSerial:  nodeSerial,
+				Role:        utils.PVCRolePgWal,
+				Storage:     *cluster.Spec.WalStorage,
+				ReadReplica: readReplica,
 			},
 		); err != nil {
 			return ctrl.Result{RequeueAfter: time.Minute}, err
 		}
 	}
 
+	if err := r.createTablespacePVCs(ctx, cluster, nodeSerial, readReplica); err != nil {
+		return ctrl.Result{RequeueAfter: time.Minute}, err
+	}
+
 	return ctrl.Result{RequeueAfter: 30 * time.Second}, ErrNextLoop
 }
 
@@ -1144,11 +1443,11 @@ func (r *ClusterReconciler) reconcilePVCs(
 
 	if len(cluster.Status.DanglingPVC) > 0 {
 		if (cluster.IsNodeMaintenanceWindowInProgress() && !cluster.IsReusePVCEnabled()) ||
-			cluster.Spec.Instances <= cluster.Status.Instances {
+			cluster.GetDesiredInstances() <= cluster.Status.Instances {
 			contextLogger.Info(
 				"Detected unneeded PVCs, removing them",
 				"statusInstances", cluster.Status.Instances,
-				"specInstances", cluster.Spec.Instances,
+				"desiredInstances", cluster.GetDesiredInstances(),
 				"maintenanceWindow", cluster.Spec.NodeMaintenanceWindow,
 				"danglingPVCs", cluster.Status.DanglingPVC)
 			return ctrl.Result{RequeueAfter: 1 * time.Second}, r.removeDanglingPVCs(ctx, cluster)
@@ -1179,6 +1478,9 @@ func (r *ClusterReconciler) reconcilePVCs(
 	}
 
 	pod := specs.PodWithExistingStorage(*cluster, nodeSerial)
+	if utils.IsReadReplica(&pvc.ObjectMeta) {
+		pod.Labels[utils.ReadReplicaLabelName] = "true"
+	}
 
 	if configuration.Current.EnableAzurePVCUpdates {
 		for _, pvcName := range cluster.Status.ResizingPVC {
@@ -1274,6 +1576,35 @@ func (r *ClusterReconciler) removeDanglingPVCs(ctx context.Context, cluster *api
 	return nil
 }
 
+// createTablespacePVCs creates the PVCs containing the tablespaces
+// of the instance with the passed serial
+func (r *ClusterReconciler) createTablespacePVCs(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	nodeSerial int,
+	readReplica bool,
+) error {
+	for idx := range cluster.Spec.Tablespaces {
+		tablespace := &cluster.Spec.Tablespaces[idx]
+		if err := r.createPVC(
+			ctx,
+			cluster,
+			&persistentvolumeclaim.CreateConfiguration{
+				Status:      persistentvolumeclaim.StatusInitializing,
+				NodeSerial:  nodeSerial,
+				Role:        utils.PVCRolePgTablespace,
+				Storage:     tablespace.Storage,
+				Tablespace:  tablespace,
+				ReadReplica: readReplica,
+			},
+		); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (r *ClusterReconciler) createPVC(
 	ctx context.Context,
 	cluster *apiv1.Cluster,
@@ -1299,7 +1630,11 @@ func (r *ClusterReconciler) createPVC(
 		)
 	}
 
-	if err = r.Create(ctx, pvc); err != nil && !apierrs.IsAlreadyExists(err) {
+	err = r.Create(ctx, pvc)
+	if apierrs.IsAlreadyExists(err) {
+		return r.checkPVCOwnership(ctx, cluster, pvc.Name)
+	}
+	if err != nil {
 		return fmt.Errorf("unable to create a PVC: %s for this node (nodeSerial: %d): %w",
 			pvc.Name,
 			configuration.NodeSerial,
@@ -1309,3 +1644,63 @@ func (r *ClusterReconciler) createPVC(
 
 	return nil
 }
+
+// checkPVCOwnership refuses to use an already existing PVC when it
+// belongs to a different cluster, as the same storage would be shared
+// between two clusters
+func (r *ClusterReconciler) checkPVCOwnership(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	pvcName string,
+) error {
+	var pvc corev1.PersistentVolumeClaim
+	if err := r.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: pvcName}, &pvc); err != nil {
+		return fmt.Errorf("while checking the ownership of PVC %s: %w", pvcName, err)
+	}
+
+	owner, isOwned := IsOwnedByCluster(&pvc)
+	if !isOwned || owner == cluster.Name {
+		return nil
+	}
+
+	r.Recorder.Eventf(cluster, "Warning", "PVCOwnedByAnotherCluster",
+		"PVC %s is already used by cluster %s", pvcName, owner)
+	return fmt.Errorf("PVC %s is already used by cluster %s, refusing to share it", pvcName, owner)
+}
+
+// checkPgBaseBackupSourceCluster checks whether the cluster to be cloned by
+// the pg_basebackup bootstrap method can be used, returning the reason why
+// it can't otherwise. Clusters bootstrapped from an external cluster are
+// not checked
+func (r *ClusterReconciler) checkPgBaseBackupSourceCluster(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+) (string, error) {
+	if cluster.Spec.Bootstrap == nil || cluster.Spec.Bootstrap.PgBaseBackup == nil ||
+		cluster.Spec.Bootstrap.PgBaseBackup.SourceCluster == nil {
+		return "", nil
+	}
+
+	var source apiv1.Cluster
+	sourceName := cluster.Spec.Bootstrap.PgBaseBackup.SourceCluster.Name
+	err := r.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: sourceName}, &source)
+	if apierrs.IsNotFound(err) {
+		return fmt.Sprintf("The source cluster %s doesn't exist", sourceName), nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if source.Status.CurrentPrimary == "" {
+		return fmt.Sprintf("The source cluster %s has no primary instance", sourceName), nil
+	}
+
+	server, _ := cluster.GetPgBaseBackupSource()
+	if server.SSLCert.Name != source.GetReplicationSecretName() ||
+		server.SSLRootCert.Name != source.GetServerCASecretName() {
+		return fmt.Sprintf("The source cluster %s uses custom certificates, "+
+			"it must be defined in externalClusters to be cloned", sourceName), nil
+	}
+
+	return "", nil
+}
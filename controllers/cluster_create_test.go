@@ -21,10 +21,13 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	policyv1 "k8s.io/api/policy/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -101,6 +104,65 @@ var _ = Describe("cluster_create unit tests", func() {
 		})
 	})
 
+	It("should make sure that createPostgresServices restores a tampered selector", func() {
+		ctx := context.Background()
+		namespace := newFakeNamespace()
+		cluster := newFakeCNPGCluster(namespace)
+		serviceKey := types.NamespacedName{Name: cluster.GetServiceReadWriteName(), Namespace: namespace}
+
+		By("creating the services", func() {
+			err := clusterReconciler.createPostgresServices(ctx, cluster)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		By("changing the selector of the -rw service", func() {
+			var service corev1.Service
+			Expect(k8sClient.Get(ctx, serviceKey, &service)).To(Succeed())
+			service.Spec.Selector = map[string]string{"app": "something-else"}
+			Expect(k8sClient.Update(ctx, &service)).To(Succeed())
+		})
+
+		By("executing createPostgresServices again", func() {
+			err := clusterReconciler.createPostgresServices(ctx, cluster)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		By("making sure that the selector has been restored", func() {
+			var service corev1.Service
+			Expect(k8sClient.Get(ctx, serviceKey, &service)).To(Succeed())
+			Expect(service.Spec.Selector).To(Equal(map[string]string{
+				utils.ClusterLabelName:     cluster.Name,
+				specs.ClusterRoleLabelName: specs.ClusterRoleLabelPrimary,
+			}))
+		})
+	})
+
+	It("should make sure that ensureStorageClassesExist detects the missing storage classes", func() {
+		ctx := context.Background()
+		namespace := newFakeNamespace()
+		cluster := newFakeCNPGCluster(namespace)
+
+		By("accepting the default storage class", func() {
+			Expect(clusterReconciler.ensureStorageClassesExist(ctx, cluster)).To(Succeed())
+		})
+
+		By("accepting an existing storage class", func() {
+			storageClass := &storagev1.StorageClass{
+				ObjectMeta:  metav1.ObjectMeta{Name: "fast-" + namespace},
+				Provisioner: "kubernetes.io/no-provisioner",
+			}
+			Expect(k8sClient.Create(ctx, storageClass)).To(Succeed())
+			cluster.Spec.StorageConfiguration.StorageClass = &storageClass.Name
+			Expect(clusterReconciler.ensureStorageClassesExist(ctx, cluster)).To(Succeed())
+		})
+
+		By("rejecting a missing storage class for the WAL volume", func() {
+			missing := "missing-" + namespace
+			cluster.Spec.WalStorage = &apiv1.StorageConfiguration{StorageClass: &missing}
+			Expect(clusterReconciler.ensureStorageClassesExist(ctx, cluster)).ToNot(Succeed())
+		})
+	})
+
 	It("should make sure that createOrPatchServiceAccount works correctly", func() {
 		ctx := context.Background()
 		namespace := newFakeNamespace()
@@ -273,3 +335,40 @@ var _ = Describe("Set cluster metadata of service account", func() {
 		Expect(sa.Labels).To(BeEquivalentTo(cluster.Spec.ServiceAccountTemplate.Metadata.Labels))
 	})
 })
+
+var _ = Describe("obsolete cluster services", func() {
+	cluster := &apiv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default", UID: "uid"},
+		Spec: apiv1.ClusterSpec{
+			ServiceNaming: &apiv1.ServiceNamingConfiguration{Suffix: "-eu"},
+		},
+	}
+
+	newService := func(name string) *corev1.Service {
+		service := &corev1.Service{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: cluster.Namespace},
+			Spec: corev1.ServiceSpec{
+				Selector: map[string]string{utils.ClusterLabelName: cluster.Name},
+			},
+		}
+		utils.SetAsOwnedBy(&service.ObjectMeta, cluster.ObjectMeta, cluster.TypeMeta)
+		return service
+	}
+
+	It("detects the services using the previous names", func() {
+		Expect(isObsoleteClusterService(cluster, newService("cluster-example-rw"))).To(BeTrue())
+		Expect(isObsoleteClusterService(cluster, newService("cluster-example-ro"))).To(BeTrue())
+		Expect(isObsoleteClusterService(cluster, newService("cluster-example-r"))).To(BeTrue())
+	})
+
+	It("keeps the current services", func() {
+		Expect(isObsoleteClusterService(cluster, newService("cluster-example-eu-rw"))).To(BeFalse())
+		Expect(isObsoleteClusterService(cluster, newService("cluster-example-any"))).To(BeFalse())
+	})
+
+	It("keeps the services not controlled by the cluster", func() {
+		service := newService("cluster-example-rw")
+		service.OwnerReferences = nil
+		Expect(isObsoleteClusterService(cluster, service)).To(BeFalse())
+	})
+})
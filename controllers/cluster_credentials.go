@@ -0,0 +1,210 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/stringset"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// reconcileMirroredSecrets copies the secrets referenced by the credentials
+// of the backup object store and by the certificates from the credentials
+// namespace into the namespace of the cluster, as the instances can only
+// use the secrets of their own namespace, and removes the copies which are
+// not referenced anymore
+func (r *ClusterReconciler) reconcileMirroredSecrets(ctx context.Context, cluster *apiv1.Cluster) error {
+	namespace := cluster.Spec.CredentialsNamespace
+	if namespace != "" && !configuration.Current.IsCredentialsNamespaceAllowed(namespace) {
+		// The webhook already prevents this, but the operator
+		// configuration may have changed in the meantime
+		r.Recorder.Eventf(cluster, "Warning", "CredentialsNamespaceNotAllowed",
+			"Cannot copy the secrets from the %s namespace, which is not allowed "+
+				"by the operator configuration", namespace)
+		return fmt.Errorf("credentials namespace %s not allowed by the operator configuration", namespace)
+	}
+
+	names := specs.GetMirroredSecretNames(*cluster)
+	for _, name := range names {
+		if err := r.reconcileMirroredSecret(ctx, cluster, name); err != nil {
+			return err
+		}
+	}
+
+	return r.deleteStaleMirroredSecrets(ctx, cluster, stringset.From(names))
+}
+
+// reconcileMirroredSecret creates or updates the copy of a secret of the
+// credentials namespace, provided the secret allows the namespace of the
+// cluster. A secret with the same name which has not been created by the
+// operator is never overwritten
+func (r *ClusterReconciler) reconcileMirroredSecret(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	name string,
+) error {
+	contextLogger := log.FromContext(ctx)
+
+	var source corev1.Secret
+	err := r.Get(ctx, client.ObjectKey{Namespace: cluster.Spec.CredentialsNamespace, Name: name}, &source)
+	if err != nil {
+		if apierrs.IsNotFound(err) {
+			r.Recorder.Eventf(cluster, "Warning", "SecretNotFound",
+				"Getting secret %s/%s", cluster.Spec.CredentialsNamespace, name)
+		}
+		return fmt.Errorf("while getting the secret to be copied %s/%s: %w",
+			cluster.Spec.CredentialsNamespace, name, err)
+	}
+
+	newSecret := specs.CreateMirroredSecret(*cluster, &source)
+
+	var secret corev1.Secret
+	err = r.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: name}, &secret)
+
+	// Every secret needs to list the namespaces allowed to copy it,
+	// otherwise any cluster could read the secrets of the other tenants.
+	// The copy is removed as soon as the permission is revoked
+	if !specs.IsSecretMirroringAllowed(&source, cluster.Namespace) {
+		r.Recorder.Eventf(cluster, "Warning", "SecretCopyNotAllowed",
+			"Cannot copy the secret %s/%s, which doesn't allow the %s namespace in the %s annotation",
+			cluster.Spec.CredentialsNamespace, name, cluster.Namespace,
+			specs.MirroredSecretAllowedNamespacesAnnotationName)
+		if err == nil && secret.Annotations[specs.MirroredSecretSourceAnnotationName] ==
+			newSecret.Annotations[specs.MirroredSecretSourceAnnotationName] {
+			contextLogger.Info("Deleting the copy of a secret which is not allowed anymore", "secret", name)
+			if err := r.Delete(ctx, &secret); err != nil && !apierrs.IsNotFound(err) {
+				return err
+			}
+		}
+		return fmt.Errorf("the secret %s/%s can't be copied into the %s namespace",
+			cluster.Spec.CredentialsNamespace, name, cluster.Namespace)
+	}
+
+	if apierrs.IsNotFound(err) {
+		contextLogger.Info("Copying the secret from the credentials namespace",
+			"secret", name, "credentialsNamespace", cluster.Spec.CredentialsNamespace)
+		cluster.SetInheritedDataAndOwnership(&newSecret.ObjectMeta)
+		return r.Create(ctx, newSecret)
+	}
+	if err != nil {
+		return fmt.Errorf("while getting the copy of the secret %s: %w", name, err)
+	}
+
+	sourceReference := newSecret.Annotations[specs.MirroredSecretSourceAnnotationName]
+	if secret.Annotations[specs.MirroredSecretSourceAnnotationName] != sourceReference {
+		r.Recorder.Eventf(cluster, "Warning", "SecretAlreadyExists",
+			"Cannot copy the secret %s, as a secret with the same name already exists", sourceReference)
+		return fmt.Errorf("cannot copy the secret %s: a secret with the same name already exists",
+			sourceReference)
+	}
+
+	if secret.Type == newSecret.Type && reflect.DeepEqual(secret.Data, newSecret.Data) {
+		return nil
+	}
+
+	// The type of a secret is immutable
+	if secret.Type != newSecret.Type {
+		contextLogger.Info("Recreating the copy of the secret, as its type changed", "secret", name)
+		if err := r.Delete(ctx, &secret); err != nil && !apierrs.IsNotFound(err) {
+			return err
+		}
+		cluster.SetInheritedDataAndOwnership(&newSecret.ObjectMeta)
+		return r.Create(ctx, newSecret)
+	}
+
+	contextLogger.Info("Updating the copy of the secret", "secret", name)
+	origSecret := secret.DeepCopy()
+	secret.Data = newSecret.Data
+	return r.Patch(ctx, &secret, client.MergeFrom(origSecret))
+}
+
+// deleteStaleMirroredSecrets deletes the copies of the secrets which are
+// not referenced by the cluster anymore
+func (r *ClusterReconciler) deleteStaleMirroredSecrets(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	names *stringset.Data,
+) error {
+	var secrets corev1.SecretList
+	if err := r.List(
+		ctx,
+		&secrets,
+		client.InNamespace(cluster.Namespace),
+		client.MatchingLabels{utils.ClusterLabelName: cluster.Name},
+	); err != nil {
+		return err
+	}
+
+	for idx := range secrets.Items {
+		secret := &secrets.Items[idx]
+		if _, isCopy := secret.Annotations[specs.MirroredSecretSourceAnnotationName]; !isCopy {
+			continue
+		}
+		if _, owned := IsOwnedByCluster(secret); !owned || names.Has(secret.Name) {
+			continue
+		}
+
+		log.FromContext(ctx).Info("Deleting the copy of a secret which is not used anymore", "secret", secret.Name)
+		if err := r.Delete(ctx, secret); err != nil && !apierrs.IsNotFound(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// isInCredentialsNamespace checks whether an object is stored in one of
+// the namespaces the clusters can copy the secrets from
+func isInCredentialsNamespace(object client.Object) bool {
+	return configuration.Current.IsCredentialsNamespaceAllowed(object.GetNamespace())
+}
+
+// filterClustersCopyingSecret returns a list of reconcile.Request for the
+// clusters copying a secret of a credentials namespace
+func filterClustersCopyingSecret(
+	clusters apiv1.ClusterList,
+	secret *corev1.Secret,
+) (requests []reconcile.Request) {
+	for _, cluster := range clusters.Items {
+		if cluster.Spec.CredentialsNamespace != secret.Namespace {
+			continue
+		}
+		if !stringset.From(specs.GetMirroredSecretNames(cluster)).Has(secret.Name) {
+			continue
+		}
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Name:      cluster.Name,
+				Namespace: cluster.Namespace,
+			},
+		})
+	}
+	return requests
+}
@@ -18,14 +18,21 @@ package controllers
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 )
 
 // deleteDanglingMonitoringQueries deletes the default monitoring configMap and/or secret if no cluster in the namespace
@@ -87,3 +94,251 @@ func (r *ClusterReconciler) deleteDanglingMonitoringQueries(ctx context.Context,
 
 	return nil
 }
+
+// reconcileFinalBackupFinalizer adds the finalizer delaying the deletion of
+// the cluster when the final backup is enabled, and removes it otherwise
+func (r *ClusterReconciler) reconcileFinalBackupFinalizer(ctx context.Context, cluster *apiv1.Cluster) error {
+	enabled := cluster.IsFinalBackupEnabled()
+	if enabled == controllerutil.ContainsFinalizer(cluster, apiv1.FinalBackupFinalizerName) {
+		return nil
+	}
+
+	origCluster := cluster.DeepCopy()
+	if enabled {
+		controllerutil.AddFinalizer(cluster, apiv1.FinalBackupFinalizerName)
+	} else {
+		controllerutil.RemoveFinalizer(cluster, apiv1.FinalBackupFinalizerName)
+	}
+
+	return r.Patch(ctx, cluster, client.MergeFrom(origCluster))
+}
+
+// reconcileFinalBackup takes the final base backup of a cluster being
+// deleted, releasing the finalizer when the backup is done or when
+// the timeout expires
+func (r *ClusterReconciler) reconcileFinalBackup(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+) (ctrl.Result, error) {
+	contextLogger := log.FromContext(ctx)
+
+	if !cluster.IsFinalBackupEnabled() {
+		return ctrl.Result{}, r.releaseFinalBackupFinalizer(ctx, cluster)
+	}
+
+	backupName := getFinalBackupName(cluster)
+	var backup apiv1.Backup
+	err := r.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: backupName}, &backup)
+	if apierrs.IsNotFound(err) {
+		// The backup is not owned by the cluster, as it must
+		// survive the deletion
+		backup = apiv1.Backup{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      backupName,
+				Namespace: cluster.Namespace,
+				Labels: map[string]string{
+					utils.ClusterLabelName: cluster.Name,
+				},
+			},
+			Spec: apiv1.BackupSpec{
+				Cluster: apiv1.LocalObjectReference{Name: cluster.Name},
+			},
+		}
+		if err := r.Create(ctx, &backup); err != nil {
+			return ctrl.Result{}, fmt.Errorf("while creating the final backup: %w", err)
+		}
+
+		contextLogger.Info("Taking the final backup before deleting the cluster", "backupName", backupName)
+		r.Recorder.Eventf(cluster, "Normal", "FinalBackupStarted",
+			"Taking the final backup %v before deleting the cluster", backupName)
+		return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+	}
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("while getting the final backup: %w", err)
+	}
+
+	switch backup.Status.Phase {
+	case apiv1.BackupPhaseCompleted:
+		r.Recorder.Eventf(cluster, "Normal", "FinalBackupCompleted",
+			"Final backup %v completed with backup ID %v", backupName, backup.Status.BackupID)
+		return ctrl.Result{}, r.releaseFinalBackupFinalizer(ctx, cluster)
+
+	case apiv1.BackupPhaseFailed:
+		r.Recorder.Eventf(cluster, "Warning", "FinalBackupFailed",
+			"Final backup %v failed, deleting the cluster anyway: %v", backupName, backup.Status.Error)
+		return ctrl.Result{}, r.releaseFinalBackupFinalizer(ctx, cluster)
+	}
+
+	timeout := cluster.GetFinalBackupTimeout()
+	if time.Since(cluster.DeletionTimestamp.Time) > timeout {
+		r.Recorder.Eventf(cluster, "Warning", "FinalBackupTimeout",
+			"Final backup %v not completed in %v, deleting the cluster anyway", backupName, timeout)
+		return ctrl.Result{}, r.releaseFinalBackupFinalizer(ctx, cluster)
+	}
+
+	contextLogger.Info("Waiting for the final backup before deleting the cluster",
+		"backupName", backupName, "phase", backup.Status.Phase)
+	return ctrl.Result{RequeueAfter: 10 * time.Second}, nil
+}
+
+// releaseFinalBackupFinalizer removes the final backup finalizer, letting
+// Kubernetes complete the deletion of the cluster
+func (r *ClusterReconciler) releaseFinalBackupFinalizer(ctx context.Context, cluster *apiv1.Cluster) error {
+	origCluster := cluster.DeepCopy()
+	controllerutil.RemoveFinalizer(cluster, apiv1.FinalBackupFinalizerName)
+	return r.Patch(ctx, cluster, client.MergeFrom(origCluster))
+}
+
+// getFinalBackupName gets the name of the final backup of a cluster,
+// which depends on the deletion time to be unique among the clusters
+// having the same name
+func getFinalBackupName(cluster *apiv1.Cluster) string {
+	return fmt.Sprintf("%s-final-%s", cluster.Name, cluster.DeletionTimestamp.Format("20060102150405"))
+}
+
+// reconcileOrderlyShutdownFinalizer adds the finalizer delaying the deletion
+// of the cluster when the orderly shutdown is enabled, and removes it otherwise
+func (r *ClusterReconciler) reconcileOrderlyShutdownFinalizer(ctx context.Context, cluster *apiv1.Cluster) error {
+	enabled := cluster.IsOrderlyShutdownEnabled()
+	if enabled == controllerutil.ContainsFinalizer(cluster, apiv1.OrderlyShutdownFinalizerName) {
+		return nil
+	}
+
+	origCluster := cluster.DeepCopy()
+	if enabled {
+		controllerutil.AddFinalizer(cluster, apiv1.OrderlyShutdownFinalizerName)
+	} else {
+		controllerutil.RemoveFinalizer(cluster, apiv1.OrderlyShutdownFinalizerName)
+	}
+
+	return r.Patch(ctx, cluster, client.MergeFrom(origCluster))
+}
+
+// reconcileOrderlyShutdown shuts the instances of a cluster being deleted
+// down, deleting the replicas first and the primary instance when every
+// replica is gone, so that the primary instance writes its shutdown
+// checkpoint last. The primary instance is shut down anyway when a replica
+// doesn't stop within its timeout, and the finalizer is released when
+// every instance is gone, or when the primary instance doesn't stop
+// within the timeout
+func (r *ClusterReconciler) reconcileOrderlyShutdown(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+) (ctrl.Result, error) {
+	contextLogger := log.FromContext(ctx)
+
+	if !cluster.IsOrderlyShutdownEnabled() {
+		return ctrl.Result{}, r.releaseOrderlyShutdownFinalizer(ctx, cluster)
+	}
+
+	instances, err := r.getManagedInstances(ctx, cluster)
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("while getting the instances to be shut down: %w", err)
+	}
+
+	instancesToShutDown := getInstancesToShutDown(instances.Items)
+	if len(instancesToShutDown) == 0 {
+		contextLogger.Info("Every instance has been shut down, deleting the cluster")
+		r.Recorder.Event(cluster, "Normal", "OrderlyShutdownCompleted",
+			"Every instance has been shut down, the primary last")
+		return ctrl.Result{}, r.releaseOrderlyShutdownFinalizer(ctx, cluster)
+	}
+
+	// A replica which can't be shut down, i.e. because its node is
+	// unreachable, must not hold the shutdown of the primary instance
+	replicasTimeout := cluster.GetReplicasShutdownTimeout()
+	stuckReplica := getStuckReplicaShutdown(instancesToShutDown, replicasTimeout, time.Now())
+	if stuckReplica != "" {
+		if !hasPrimaryInstance(instances.Items) {
+			r.Recorder.Eventf(cluster, "Warning", "OrderlyShutdownTimeout",
+				"Replica %v not shut down in %v, deleting the cluster anyway", stuckReplica, replicasTimeout)
+			return ctrl.Result{}, r.releaseOrderlyShutdownFinalizer(ctx, cluster)
+		}
+		instancesToShutDown = instances.Items
+	}
+
+	for idx := range instancesToShutDown {
+		pod := &instancesToShutDown[idx]
+		if !pod.DeletionTimestamp.IsZero() {
+			if !specs.IsPodPrimary(*pod) {
+				continue
+			}
+
+			timeout := cluster.GetPrimaryShutdownTimeout()
+			if time.Since(pod.DeletionTimestamp.Time) > timeout {
+				r.Recorder.Eventf(cluster, "Warning", "OrderlyShutdownTimeout",
+					"Primary instance %v not shut down in %v, deleting the cluster anyway", pod.Name, timeout)
+				return ctrl.Result{}, r.releaseOrderlyShutdownFinalizer(ctx, cluster)
+			}
+			continue
+		}
+
+		if specs.IsPodPrimary(*pod) && stuckReplica != "" {
+			r.Recorder.Eventf(cluster, "Warning", "OrderlyShutdownTimeout",
+				"Replica %v not shut down in %v, shutting down the primary instance anyway",
+				stuckReplica, replicasTimeout)
+		}
+
+		contextLogger.Info("Shutting down the instance before deleting the cluster",
+			"pod", pod.Name, "primary", specs.IsPodPrimary(*pod))
+		if err := r.Delete(ctx, pod); err != nil && !apierrs.IsNotFound(err) {
+			return ctrl.Result{}, fmt.Errorf("while shutting down the instance %s: %w", pod.Name, err)
+		}
+	}
+
+	return ctrl.Result{RequeueAfter: time.Second}, nil
+}
+
+// getInstancesToShutDown gets the instances to be shut down next when the
+// cluster is deleted: the replicas while any of them is still running,
+// and then the primary instance
+func getInstancesToShutDown(instances []corev1.Pod) []corev1.Pod {
+	var replicas, primaries []corev1.Pod
+	for _, pod := range instances {
+		if specs.IsPodPrimary(pod) {
+			primaries = append(primaries, pod)
+		} else {
+			replicas = append(replicas, pod)
+		}
+	}
+
+	if len(replicas) > 0 {
+		return replicas
+	}
+	return primaries
+}
+
+// getStuckReplicaShutdown gets the name of a replica which has been
+// shutting down for longer than the timeout, if any
+func getStuckReplicaShutdown(instances []corev1.Pod, timeout time.Duration, now time.Time) string {
+	for _, pod := range instances {
+		if specs.IsPodPrimary(pod) || pod.DeletionTimestamp.IsZero() {
+			continue
+		}
+
+		if now.Sub(pod.DeletionTimestamp.Time) > timeout {
+			return pod.Name
+		}
+	}
+
+	return ""
+}
+
+// hasPrimaryInstance checks if the primary instance is among the passed ones
+func hasPrimaryInstance(instances []corev1.Pod) bool {
+	for _, pod := range instances {
+		if specs.IsPodPrimary(pod) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// releaseOrderlyShutdownFinalizer removes the orderly shutdown finalizer,
+// letting Kubernetes complete the deletion of the cluster
+func (r *ClusterReconciler) releaseOrderlyShutdownFinalizer(ctx context.Context, cluster *apiv1.Cluster) error {
+	origCluster := cluster.DeepCopy()
+	controllerutil.RemoveFinalizer(cluster, apiv1.OrderlyShutdownFinalizerName)
+	return r.Patch(ctx, cluster, client.MergeFrom(origCluster))
+}
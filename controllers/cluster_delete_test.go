@@ -27,6 +27,7 @@ import (
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -135,3 +136,81 @@ var _ = Describe("ensures that deleteDanglingMonitoringQueries works correctly",
 		})
 	})
 })
+
+var _ = Describe("final backup", func() {
+	It("is named after the cluster and its deletion time", func() {
+		deletionTime := metav1.NewTime(time.Date(2023, 2, 1, 10, 30, 0, 0, time.UTC))
+		cluster := &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "cluster-example",
+				DeletionTimestamp: &deletionTime,
+			},
+		}
+		Expect(getFinalBackupName(cluster)).To(Equal("cluster-example-final-20230201103000"))
+	})
+})
+
+var _ = Describe("orderly shutdown", func() {
+	newInstance := func(name, role string) corev1.Pod {
+		return corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   name,
+				Labels: map[string]string{specs.ClusterRoleLabelName: role},
+			},
+		}
+	}
+
+	It("shuts the replicas down before the primary instance", func() {
+		instances := []corev1.Pod{
+			newInstance("cluster-example-1", specs.ClusterRoleLabelPrimary),
+			newInstance("cluster-example-2", specs.ClusterRoleLabelReplica),
+			newInstance("cluster-example-3", specs.ClusterRoleLabelReplica),
+		}
+
+		var shutdownOrder []string
+		for len(instances) > 0 {
+			step := getInstancesToShutDown(instances)
+			Expect(step).ToNot(BeEmpty())
+
+			stepNames := make(map[string]bool, len(step))
+			for _, pod := range step {
+				shutdownOrder = append(shutdownOrder, pod.Name)
+				stepNames[pod.Name] = true
+			}
+
+			var remaining []corev1.Pod
+			for _, pod := range instances {
+				if !stepNames[pod.Name] {
+					remaining = append(remaining, pod)
+				}
+			}
+			instances = remaining
+		}
+
+		Expect(shutdownOrder).To(Equal([]string{"cluster-example-2", "cluster-example-3", "cluster-example-1"}))
+	})
+
+	It("has nothing to shut down when every instance is gone", func() {
+		Expect(getInstancesToShutDown(nil)).To(BeEmpty())
+	})
+
+	It("detects the replicas which are not shut down within the timeout", func() {
+		now := time.Date(2023, 3, 1, 12, 0, 0, 0, time.UTC)
+		deletedAt := func(pod corev1.Pod, when time.Time) corev1.Pod {
+			pod.DeletionTimestamp = &metav1.Time{Time: when}
+			return pod
+		}
+
+		instances := []corev1.Pod{
+			deletedAt(newInstance("cluster-example-1", specs.ClusterRoleLabelPrimary), now.Add(-time.Hour)),
+			deletedAt(newInstance("cluster-example-2", specs.ClusterRoleLabelReplica), now.Add(-time.Minute)),
+			newInstance("cluster-example-3", specs.ClusterRoleLabelReplica),
+		}
+		Expect(getStuckReplicaShutdown(instances, 5*time.Minute, now)).To(BeEmpty())
+		Expect(hasPrimaryInstance(instances)).To(BeTrue())
+
+		instances[1] = deletedAt(instances[1], now.Add(-10*time.Minute))
+		Expect(getStuckReplicaShutdown(instances, 5*time.Minute, now)).To(Equal("cluster-example-2"))
+		Expect(hasPrimaryInstance(instances[1:])).To(BeFalse())
+	})
+})
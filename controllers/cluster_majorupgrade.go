@@ -0,0 +1,534 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/versions"
+)
+
+// reconcileMajorUpgrade upgrades the data directory of the cluster to the
+// PostgreSQL major version of the requested image. The instances are
+// stopped, the data directory of the primary is upgraded via pg_upgrade
+// in a job and, only when it succeeded, the storage of the replicas is
+// dropped so that they are cloned again from the upgraded primary.
+// It returns a non-nil result when the reconciliation loop should stop
+func (r *ClusterReconciler) reconcileMajorUpgrade(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	resources *managedResources,
+) (*ctrl.Result, error) {
+	contextLogger := log.FromContext(ctx)
+
+	requestedMajorVersion, err := cluster.GetPostgresqlMajorVersion()
+	if err != nil {
+		// We can't detect the major version of the image, i.e. when
+		// using the "latest" tag, so there's nothing we can do
+		contextLogger.Debug("Cannot detect the major version of the image", "error", err)
+		_, err := r.reconcileIncompatibleImage(ctx, cluster, 0)
+		return nil, err
+	}
+
+	if cluster.Status.CurrentPrimary == "" || cluster.Status.PGDataImageInfo == nil {
+		return nil, r.updatePGDataImageInfo(ctx, cluster, getPGDataImage(cluster, resources))
+	}
+
+	// PostgreSQL can't start on a data directory created by a newer major
+	// version: rolling out the image would break every instance, so we
+	// wait for the user to restore a compatible one
+	incompatible, err := r.reconcileIncompatibleImage(ctx, cluster, requestedMajorVersion)
+	if err != nil || incompatible {
+		return &ctrl.Result{}, err
+	}
+
+	upgradeJob := getMajorUpgradeJob(resources.jobs.Items)
+
+	if requestedMajorVersion <= cluster.Status.PGDataImageInfo.MajorVersion {
+		if upgradeJob != nil {
+			if getFinishedJobCondition(upgradeJob) == "" {
+				contextLogger.Info("Waiting for the major upgrade job to finish", "job", upgradeJob.Name)
+				return &ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+			}
+
+			// The image of a failed upgrade has been reverted
+			// by the user, let's remove the job to resume the
+			// normal operations with the original data
+			contextLogger.Info("Deleting the job of the major upgrade", "job", upgradeJob.Name)
+			if err := r.deleteJobWithPods(ctx, upgradeJob); err != nil {
+				return nil, err
+			}
+			return &ctrl.Result{RequeueAfter: 1 * time.Second}, nil
+		}
+
+		if len(resources.instances.Items) == 0 || !allInstancesRunImage(resources, cluster.GetImageName()) {
+			return nil, nil
+		}
+		return nil, r.updatePGDataImageInfo(ctx, cluster, cluster.GetImageName())
+	}
+
+	if upgradeJob != nil {
+		return r.handleMajorUpgradeJob(ctx, cluster, resources, upgradeJob)
+	}
+
+	if cluster.Status.Phase != apiv1.PhaseMajorUpgrade {
+		// The instances are still running, and we can verify that the
+		// upgrade is possible before stopping them
+		instancesStatus := r.instanceStatusClient.getStatusFromInstances(ctx, resources.instances)
+		if reason := checkMajorUpgradePrerequisites(cluster, instancesStatus); reason != "" {
+			return r.refuseMajorUpgrade(ctx, cluster, reason)
+		}
+
+		r.Recorder.Eventf(cluster, "Normal", "MajorUpgrade",
+			"Upgrading the data directory from PostgreSQL %d to %d",
+			cluster.Status.PGDataImageInfo.MajorVersion, requestedMajorVersion)
+		if err := r.RegisterPhase(ctx, cluster, apiv1.PhaseMajorUpgrade,
+			fmt.Sprintf("Upgrading from %s to %s",
+				cluster.Status.PGDataImageInfo.Image, cluster.GetImageName())); err != nil {
+			return nil, err
+		}
+	}
+
+	// pg_upgrade requires the instances to be shut down
+	if len(resources.instances.Items) > 0 {
+		return r.stopInstancesForMajorUpgrade(ctx, resources)
+	}
+
+	return r.createMajorUpgradeJob(ctx, cluster, resources)
+}
+
+// checkMajorUpgradePrerequisites checks if the data directory of the primary
+// instance can be upgraded to a new PostgreSQL major version, returning the
+// reason why it can't or an empty string. The upgrade needs to be enabled
+// in the cluster and, as pg_upgrade copies the data files, the volume of
+// the data directory needs as much free space as the one already in use
+func checkMajorUpgradePrerequisites(
+	cluster *apiv1.Cluster,
+	instancesStatus postgres.PostgresqlStatusList,
+) string {
+	if !cluster.Spec.EnableMajorUpgrade {
+		return fmt.Sprintf("The upgrade of the data directory to a new PostgreSQL major version "+
+			"is not enabled: set enableMajorUpgrade, or restore the image %s",
+			cluster.Status.PGDataImageInfo.Image)
+	}
+
+	for _, status := range instancesStatus.Items {
+		if status.Pod.Name != cluster.Status.CurrentPrimary {
+			continue
+		}
+
+		if status.Error != nil {
+			return fmt.Sprintf("Cannot get the status of the primary instance %s "+
+				"to check the free space of its data directory: %v",
+				cluster.Status.CurrentPrimary, status.Error)
+		}
+
+		usage := status.PgDataVolumeUsage
+		if usage == nil {
+			return fmt.Sprintf("The primary instance %s is not reporting the usage "+
+				"of the volume of its data directory", cluster.Status.CurrentPrimary)
+		}
+
+		if usage.AvailableBytes < usage.UsedBytes {
+			return fmt.Sprintf("Not enough free space in the volume of the data directory of "+
+				"the primary instance %s, which pg_upgrade copies: %d bytes are needed and %d "+
+				"are available. Expand the storage, or restore the image %s",
+				cluster.Status.CurrentPrimary, usage.UsedBytes, usage.AvailableBytes,
+				cluster.Status.PGDataImageInfo.Image)
+		}
+
+		return ""
+	}
+
+	return fmt.Sprintf("The primary instance %s is not running, and the free space "+
+		"of its data directory can't be checked", cluster.Status.CurrentPrimary)
+}
+
+// refuseMajorUpgrade reports why the data directory can't be upgraded to
+// the PostgreSQL major version of the requested image. The instances are
+// left running with the previous image until the user takes action
+func (r *ClusterReconciler) refuseMajorUpgrade(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	reason string,
+) (*ctrl.Result, error) {
+	if cluster.Status.Phase != apiv1.PhaseWaitingForUser || cluster.Status.PhaseReason != reason {
+		log.FromContext(ctx).Warning("Cannot upgrade the PostgreSQL major version", "reason", reason)
+		r.Recorder.Event(cluster, "Warning", "MajorUpgradeRefused", reason)
+		if err := r.RegisterPhase(ctx, cluster, apiv1.PhaseWaitingForUser, reason); err != nil {
+			return nil, err
+		}
+	}
+
+	return &ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+}
+
+// stopInstancesForMajorUpgrade deletes the pods of the instances, as
+// pg_upgrade requires the data directory not to be in use
+func (r *ClusterReconciler) stopInstancesForMajorUpgrade(
+	ctx context.Context,
+	resources *managedResources,
+) (*ctrl.Result, error) {
+	contextLogger := log.FromContext(ctx)
+
+	for idx := range resources.instances.Items {
+		pod := &resources.instances.Items[idx]
+		if !pod.DeletionTimestamp.IsZero() {
+			continue
+		}
+
+		contextLogger.Info("Stopping the instance for the major upgrade", "pod", pod.Name)
+		if err := r.Delete(ctx, pod); err != nil && !apierrs.IsNotFound(err) {
+			return nil, err
+		}
+	}
+
+	contextLogger.Info("Waiting for the instances to be stopped before the major upgrade",
+		"instances", len(resources.instances.Items))
+	return &ctrl.Result{RequeueAfter: 1 * time.Second}, nil
+}
+
+// reconcileIncompatibleImage updates the condition reporting whether the
+// image of the cluster is running an older PostgreSQL major version than
+// the one of the data directory, moving the cluster to the related phase.
+// It returns true when the image must not be rolled out
+func (r *ClusterReconciler) reconcileIncompatibleImage(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	requestedMajorVersion int,
+) (bool, error) {
+	origCluster := cluster.DeepCopy()
+	wasIncompatible := meta.IsStatusConditionTrue(cluster.Status.Conditions,
+		string(apiv1.ConditionIncompatibleImage))
+
+	if !setIncompatibleImageCondition(cluster, requestedMajorVersion) {
+		if reflect.DeepEqual(origCluster.Status.Conditions, cluster.Status.Conditions) {
+			return false, nil
+		}
+		return false, r.Status().Patch(ctx, cluster, client.MergeFrom(origCluster))
+	}
+
+	reason := fmt.Sprintf("The image %s can't run the data directory created by PostgreSQL %d: "+
+		"restore the image %s, or use the major upgrade to move to a newer major version",
+		cluster.GetImageName(), cluster.Status.PGDataImageInfo.MajorVersion,
+		cluster.Status.PGDataImageInfo.Image)
+	if !wasIncompatible {
+		log.FromContext(ctx).Warning("Refusing to roll out an image with an older PostgreSQL major version",
+			"image", cluster.GetImageName(),
+			"pgDataImage", cluster.Status.PGDataImageInfo.Image)
+		r.Recorder.Event(cluster, "Warning", "IncompatibleImage", reason)
+	}
+
+	return true, r.RegisterPhase(ctx, cluster, apiv1.PhaseIncompatibleImage, reason)
+}
+
+// setIncompatibleImageCondition sets the condition reporting whether the
+// requested PostgreSQL major version is older than the one recorded for
+// the data directory, returning true when it is. A zero major version
+// means that it can't be detected from the image. The condition is only
+// reported as satisfied once it has been raised
+func setIncompatibleImageCondition(cluster *apiv1.Cluster, requestedMajorVersion int) bool {
+	imageInfo := cluster.Status.PGDataImageInfo
+	if imageInfo == nil || requestedMajorVersion == 0 || requestedMajorVersion >= imageInfo.MajorVersion {
+		if meta.FindStatusCondition(cluster.Status.Conditions, string(apiv1.ConditionIncompatibleImage)) != nil {
+			meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
+				Type:    string(apiv1.ConditionIncompatibleImage),
+				Status:  metav1.ConditionFalse,
+				Reason:  string(apiv1.ConditionReasonImageCompatible),
+				Message: "The PostgreSQL major version of the image can run the data directory",
+			})
+		}
+		return false
+	}
+
+	meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
+		Type:   string(apiv1.ConditionIncompatibleImage),
+		Status: metav1.ConditionTrue,
+		Reason: string(apiv1.ConditionReasonMajorVersionDowngradeDetected),
+		Message: fmt.Sprintf("The image %s is running PostgreSQL %d, while the data directory has been "+
+			"created by PostgreSQL %d with the image %s. Downgrading the major version is not possible, "+
+			"and the image won't be rolled out: restore the previous image, or change it to a newer "+
+			"major version to upgrade the data directory via pg_upgrade",
+			cluster.GetImageName(), requestedMajorVersion, imageInfo.MajorVersion, imageInfo.Image),
+	})
+	return true
+}
+
+// createMajorUpgradeJob creates the job upgrading the data directory
+// of the primary instance
+func (r *ClusterReconciler) createMajorUpgradeJob(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	resources *managedResources,
+) (*ctrl.Result, error) {
+	contextLogger := log.FromContext(ctx)
+
+	primaryPVC := getPVCByName(resources.pvcs.Items, cluster.Status.CurrentPrimary)
+	if primaryPVC == nil {
+		return nil, fmt.Errorf("cannot find the PVC of the primary instance %s", cluster.Status.CurrentPrimary)
+	}
+
+	nodeSerial, err := specs.GetNodeSerial(primaryPVC.ObjectMeta)
+	if err != nil {
+		return nil, err
+	}
+
+	job := specs.CreateMajorUpgradeJob(*cluster, nodeSerial, cluster.Status.PGDataImageInfo.Image)
+	if err := ctrl.SetControllerReference(cluster, job, r.Scheme); err != nil {
+		contextLogger.Error(err, "Unable to set the owner reference for the major upgrade job")
+		return nil, err
+	}
+
+	utils.SetOperatorVersion(&job.ObjectMeta, versions.Version)
+	utils.InheritAnnotations(&job.ObjectMeta, cluster.Annotations,
+		cluster.GetFixedInheritedAnnotations(), configuration.Current)
+	utils.InheritAnnotations(&job.Spec.Template.ObjectMeta, cluster.Annotations,
+		cluster.GetFixedInheritedAnnotations(), configuration.Current)
+	utils.InheritLabels(&job.ObjectMeta, cluster.Labels,
+		cluster.GetFixedInheritedLabels(), configuration.Current)
+	utils.InheritLabels(&job.Spec.Template.ObjectMeta, cluster.Labels,
+		cluster.GetFixedInheritedLabels(), configuration.Current)
+
+	contextLogger.Info("Creating the major upgrade job", "name", job.Name)
+	if err := r.Create(ctx, job); err != nil {
+		if apierrs.IsAlreadyExists(err) {
+			// This Job was already created, maybe the cache is stale.
+			return &ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+		}
+
+		contextLogger.Error(err, "Unable to create the major upgrade job", "job", job)
+		return nil, err
+	}
+
+	return &ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+}
+
+// handleMajorUpgradeJob waits for the major upgrade job to finish. When it
+// succeeded, the storage of the replicas is dropped and the new major
+// version is recorded. When it failed, the original data directory has been
+// preserved and we wait for the user to revert the image or to retry
+func (r *ClusterReconciler) handleMajorUpgradeJob(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	resources *managedResources,
+	job *batchv1.Job,
+) (*ctrl.Result, error) {
+	contextLogger := log.FromContext(ctx)
+
+	switch getFinishedJobCondition(job) {
+	case batchv1.JobComplete:
+		contextLogger.Info("Major upgrade completed, dropping the storage of the replicas")
+		if err := r.deleteReplicasStorage(ctx, cluster, resources); err != nil {
+			return nil, err
+		}
+
+		if err := r.deleteJobWithPods(ctx, job); err != nil {
+			return nil, err
+		}
+
+		previousMajorVersion := cluster.Status.PGDataImageInfo.MajorVersion
+		if err := r.updatePGDataImageInfo(ctx, cluster, cluster.GetImageName()); err != nil {
+			return nil, err
+		}
+
+		r.Recorder.Eventf(cluster, "Normal", "MajorUpgradeCompleted",
+			"Upgraded the data directory from PostgreSQL %d to %d",
+			previousMajorVersion, cluster.Status.PGDataImageInfo.MajorVersion)
+		return &ctrl.Result{RequeueAfter: 1 * time.Second}, nil
+
+	case batchv1.JobFailed:
+		if cluster.Status.Phase != apiv1.PhaseMajorUpgradeFailed {
+			reason := fmt.Sprintf("The data directory has been preserved: restore the image %s "+
+				"to resume the cluster, or delete the job %s to retry the upgrade",
+				cluster.Status.PGDataImageInfo.Image, job.Name)
+			r.Recorder.Eventf(cluster, "Warning", "MajorUpgradeFailed",
+				"Failed to upgrade the data directory to PostgreSQL %s: %s",
+				cluster.GetImageName(), reason)
+			if err := r.RegisterPhase(ctx, cluster, apiv1.PhaseMajorUpgradeFailed, reason); err != nil {
+				return nil, err
+			}
+		}
+
+		// We wait for the user to either revert the image or delete the job
+		return &ctrl.Result{}, nil
+
+	default:
+		contextLogger.Debug("Waiting for the major upgrade job to finish", "job", job.Name)
+		return &ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+	}
+}
+
+// deleteReplicasStorage deletes the PVCs and the jobs of every instance but
+// the primary, as their data directories can't be used with the new major
+// version. The replicas will be cloned again from the upgraded primary
+func (r *ClusterReconciler) deleteReplicasStorage(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	resources *managedResources,
+) error {
+	contextLogger := log.FromContext(ctx)
+
+	for idx := range resources.pvcs.Items {
+		pvc := &resources.pvcs.Items[idx]
+		instanceName, ok := pvc.Labels[utils.InstanceNameLabelName]
+		if !ok || instanceName == cluster.Status.CurrentPrimary {
+			continue
+		}
+
+		contextLogger.Info("Deleting the PVC of a replica", "pvc", pvc.Name)
+		if err := r.Delete(ctx, pvc); err != nil && !apierrs.IsNotFound(err) {
+			return fmt.Errorf("deleting the PVC %s of a replica: %w", pvc.Name, err)
+		}
+	}
+
+	for idx := range resources.jobs.Items {
+		job := &resources.jobs.Items[idx]
+		instanceName, ok := job.Labels[utils.InstanceNameLabelName]
+		if !ok || instanceName == cluster.Status.CurrentPrimary {
+			continue
+		}
+
+		if err := r.deleteJobWithPods(ctx, job); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// deleteJobWithPods deletes a job together with its pods
+func (r *ClusterReconciler) deleteJobWithPods(ctx context.Context, job *batchv1.Job) error {
+	foreground := metav1.DeletePropagationForeground
+	err := r.Delete(ctx, job, &client.DeleteOptions{PropagationPolicy: &foreground})
+	if err != nil && !apierrs.IsNotFound(err) {
+		return fmt.Errorf("deleting the job %s: %w", job.Name, err)
+	}
+
+	return nil
+}
+
+// updatePGDataImageInfo records in the status the image, and the related
+// major version, which has been used to create the data directory
+func (r *ClusterReconciler) updatePGDataImageInfo(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	image string,
+) error {
+	majorVersion, err := postgres.GetPostgresMajorVersionFromTag(utils.GetImageTag(image))
+	if err != nil {
+		log.FromContext(ctx).Debug("Cannot detect the major version of the image",
+			"image", image, "error", err)
+		return nil
+	}
+
+	imageInfo := &apiv1.ImageInfo{
+		Image:        image,
+		MajorVersion: majorVersion,
+	}
+	if cluster.Status.PGDataImageInfo != nil && *cluster.Status.PGDataImageInfo == *imageInfo {
+		return nil
+	}
+
+	origCluster := cluster.DeepCopy()
+	cluster.Status.PGDataImageInfo = imageInfo
+	return r.Status().Patch(ctx, cluster, client.MergeFrom(origCluster))
+}
+
+// getPGDataImage gets the image which has been used to create the data
+// directory. That's the image of the primary instance when it exists,
+// as the cluster may be already requesting a new one
+func getPGDataImage(cluster *apiv1.Cluster, resources *managedResources) string {
+	for idx := range resources.instances.Items {
+		pod := resources.instances.Items[idx]
+		if pod.Name != cluster.Status.CurrentPrimary {
+			continue
+		}
+
+		if image, err := specs.GetPostgresImageName(pod); err == nil {
+			return image
+		}
+	}
+
+	return cluster.GetImageName()
+}
+
+// allInstancesRunImage checks if every instance is running the passed image
+func allInstancesRunImage(resources *managedResources, image string) bool {
+	for idx := range resources.instances.Items {
+		podImage, err := specs.GetPostgresImageName(resources.instances.Items[idx])
+		if err != nil || podImage != image {
+			return false
+		}
+	}
+
+	return true
+}
+
+// getMajorUpgradeJob gets the major upgrade job from a list of jobs
+func getMajorUpgradeJob(jobs []batchv1.Job) *batchv1.Job {
+	for idx := range jobs {
+		if jobs[idx].Labels[utils.JobRoleLabelName] == specs.MajorUpgradeJobRole {
+			return &jobs[idx]
+		}
+	}
+
+	return nil
+}
+
+// getFinishedJobCondition gets the condition marking the job as finished,
+// or an empty string if the job is still running
+func getFinishedJobCondition(job *batchv1.Job) batchv1.JobConditionType {
+	for _, condition := range job.Status.Conditions {
+		if condition.Status != corev1.ConditionTrue {
+			continue
+		}
+
+		if condition.Type == batchv1.JobComplete || condition.Type == batchv1.JobFailed {
+			return condition.Type
+		}
+	}
+
+	return ""
+}
+
+// getPVCByName gets a PVC from a list given its name
+func getPVCByName(pvcs []corev1.PersistentVolumeClaim, name string) *corev1.PersistentVolumeClaim {
+	for idx := range pvcs {
+		if pvcs[idx].Name == name {
+			return &pvcs[idx]
+		}
+	}
+
+	return nil
+}
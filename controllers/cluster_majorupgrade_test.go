@@ -0,0 +1,272 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("major upgrade", func() {
+	newInstance := func(name, image string) corev1.Pod {
+		return corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{
+					{Name: specs.PostgresContainerName, Image: image},
+				},
+			},
+		}
+	}
+
+	cluster := &apiv1.Cluster{
+		Spec: apiv1.ClusterSpec{
+			ImageName: "postgres:15.1",
+		},
+		Status: apiv1.ClusterStatus{
+			CurrentPrimary: "cluster-example-1",
+		},
+	}
+
+	It("gets the image of the data directory from the primary instance", func() {
+		resources := &managedResources{
+			instances: corev1.PodList{Items: []corev1.Pod{
+				newInstance("cluster-example-1", "postgres:14.6"),
+				newInstance("cluster-example-2", "postgres:15.1"),
+			}},
+		}
+		Expect(getPGDataImage(cluster, resources)).To(Equal("postgres:14.6"))
+	})
+
+	It("gets the image of the data directory from the cluster without instances", func() {
+		Expect(getPGDataImage(cluster, &managedResources{})).To(Equal("postgres:15.1"))
+	})
+
+	It("checks if every instance is running an image", func() {
+		resources := &managedResources{
+			instances: corev1.PodList{Items: []corev1.Pod{
+				newInstance("cluster-example-1", "postgres:15.1"),
+				newInstance("cluster-example-2", "postgres:14.6"),
+			}},
+		}
+		Expect(allInstancesRunImage(resources, "postgres:15.1")).To(BeFalse())
+
+		resources.instances.Items[1].Spec.Containers[0].Image = "postgres:15.1"
+		Expect(allInstancesRunImage(resources, "postgres:15.1")).To(BeTrue())
+	})
+
+	It("finds the major upgrade job", func() {
+		jobs := []batchv1.Job{
+			{ObjectMeta: metav1.ObjectMeta{
+				Name:   "cluster-example-1-initdb",
+				Labels: map[string]string{utils.JobRoleLabelName: "initdb"},
+			}},
+			{ObjectMeta: metav1.ObjectMeta{
+				Name:   "cluster-example-1-major-upgrade",
+				Labels: map[string]string{utils.JobRoleLabelName: specs.MajorUpgradeJobRole},
+			}},
+		}
+		Expect(getMajorUpgradeJob(jobs).Name).To(Equal("cluster-example-1-major-upgrade"))
+		Expect(getMajorUpgradeJob(jobs[:1])).To(BeNil())
+	})
+
+	It("detects when a job is finished", func() {
+		job := &batchv1.Job{}
+		Expect(getFinishedJobCondition(job)).To(BeEmpty())
+
+		job.Status.Conditions = []batchv1.JobCondition{
+			{Type: batchv1.JobFailed, Status: corev1.ConditionTrue},
+		}
+		Expect(getFinishedJobCondition(job)).To(Equal(batchv1.JobFailed))
+
+		job.Status.Conditions = []batchv1.JobCondition{
+			{Type: batchv1.JobComplete, Status: corev1.ConditionTrue},
+		}
+		Expect(getFinishedJobCondition(job)).To(Equal(batchv1.JobComplete))
+	})
+
+	It("refuses to downgrade the major version of the data directory", func() {
+		downgradedCluster := cluster.DeepCopy()
+		downgradedCluster.Spec.ImageName = "postgres:14.6"
+		downgradedCluster.Status.PGDataImageInfo = &apiv1.ImageInfo{
+			Image:        "postgres:15.1",
+			MajorVersion: 15,
+		}
+
+		Expect(setIncompatibleImageCondition(downgradedCluster, 14)).To(BeTrue())
+		condition := meta.FindStatusCondition(downgradedCluster.Status.Conditions,
+			string(apiv1.ConditionIncompatibleImage))
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Reason).To(Equal(string(apiv1.ConditionReasonMajorVersionDowngradeDetected)))
+		Expect(condition.Message).To(ContainSubstring("postgres:15.1"))
+
+		By("restoring the original image", func() {
+			downgradedCluster.Spec.ImageName = "postgres:15.1"
+			Expect(setIncompatibleImageCondition(downgradedCluster, 15)).To(BeFalse())
+			condition := meta.FindStatusCondition(downgradedCluster.Status.Conditions,
+				string(apiv1.ConditionIncompatibleImage))
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal(string(apiv1.ConditionReasonImageCompatible)))
+		})
+	})
+
+	It("accepts the same or a newer major version without raising the condition", func() {
+		upgradedCluster := cluster.DeepCopy()
+		upgradedCluster.Status.PGDataImageInfo = &apiv1.ImageInfo{
+			Image:        "postgres:14.6",
+			MajorVersion: 14,
+		}
+
+		Expect(setIncompatibleImageCondition(upgradedCluster, 14)).To(BeFalse())
+		Expect(setIncompatibleImageCondition(upgradedCluster, 15)).To(BeFalse())
+		Expect(setIncompatibleImageCondition(upgradedCluster, 0)).To(BeFalse())
+		Expect(upgradedCluster.Status.Conditions).To(BeEmpty())
+	})
+})
+
+var _ = Describe("major upgrade prerequisites", func() {
+	var cluster *apiv1.Cluster
+	var instancesStatus postgres.PostgresqlStatusList
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				ImageName:          "postgres:15.1",
+				EnableMajorUpgrade: true,
+			},
+			Status: apiv1.ClusterStatus{
+				CurrentPrimary: "cluster-example-1",
+				PGDataImageInfo: &apiv1.ImageInfo{
+					Image:        "postgres:14.6",
+					MajorVersion: 14,
+				},
+			},
+		}
+		instancesStatus = postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				{
+					Pod: corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-1"}},
+					PgDataVolumeUsage: &postgres.VolumeUsage{
+						UsedBytes:      1024,
+						AvailableBytes: 4096,
+					},
+				},
+				{
+					Pod: corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-2"}},
+				},
+			},
+		}
+	})
+
+	It("allows the upgrade when enabled and with enough free space", func() {
+		Expect(checkMajorUpgradePrerequisites(cluster, instancesStatus)).To(BeEmpty())
+	})
+
+	It("refuses the upgrade when it's not enabled", func() {
+		cluster.Spec.EnableMajorUpgrade = false
+		Expect(checkMajorUpgradePrerequisites(cluster, instancesStatus)).To(ContainSubstring("enableMajorUpgrade"))
+	})
+
+	It("refuses the upgrade when the data directory can't be copied", func() {
+		instancesStatus.Items[0].PgDataVolumeUsage.AvailableBytes = 512
+		Expect(checkMajorUpgradePrerequisites(cluster, instancesStatus)).To(ContainSubstring("free space"))
+	})
+
+	It("refuses the upgrade when the usage of the volume is unknown", func() {
+		instancesStatus.Items[0].PgDataVolumeUsage = nil
+		Expect(checkMajorUpgradePrerequisites(cluster, instancesStatus)).ToNot(BeEmpty())
+
+		instancesStatus.Items[0].Error = errors.New("connection refused")
+		Expect(checkMajorUpgradePrerequisites(cluster, instancesStatus)).To(ContainSubstring("connection refused"))
+
+		Expect(checkMajorUpgradePrerequisites(cluster, postgres.PostgresqlStatusList{})).
+			To(ContainSubstring("not running"))
+	})
+})
+
+var _ = Describe("major upgrade of a cluster", func() {
+	It("stops every instance before the upgrade", func() {
+		ctx := context.TODO()
+		namespace := newFakeNamespace()
+		cluster := newFakeCNPGCluster(namespace)
+		instances := generateFakeClusterPods(clusterReconciler.Client, cluster, true)
+
+		resources := &managedResources{
+			instances: corev1.PodList{Items: instances},
+		}
+		result, err := clusterReconciler.stopInstancesForMajorUpgrade(ctx, resources)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result).ToNot(BeNil())
+		Expect(result.RequeueAfter).ToNot(BeZero())
+
+		for idx := range instances {
+			var pod corev1.Pod
+			err := k8sClient.Get(ctx, client.ObjectKeyFromObject(&instances[idx]), &pod)
+			if err == nil {
+				Expect(pod.DeletionTimestamp).ToNot(BeNil())
+				continue
+			}
+			Expect(apierrs.IsNotFound(err)).To(BeTrue())
+		}
+	})
+
+	It("creates the job upgrading the data directory of the primary", func() {
+		ctx := context.TODO()
+		namespace := newFakeNamespace()
+		cluster := newFakeCNPGCluster(namespace, func(cluster *apiv1.Cluster) {
+			cluster.Spec.ImageName = "postgres:15.1"
+			cluster.Spec.EnableMajorUpgrade = true
+		})
+		cluster.Status.CurrentPrimary = cluster.Name + "-1"
+		cluster.Status.PGDataImageInfo = &apiv1.ImageInfo{
+			Image:        "postgres:14.6",
+			MajorVersion: 14,
+		}
+		pvcs := generateFakePVC(clusterReconciler.Client, cluster)
+
+		resources := &managedResources{
+			pvcs: corev1.PersistentVolumeClaimList{Items: pvcs},
+		}
+		result, err := clusterReconciler.createMajorUpgradeJob(ctx, cluster, resources)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result).ToNot(BeNil())
+
+		var jobs batchv1.JobList
+		Expect(k8sClient.List(ctx, &jobs, client.InNamespace(namespace))).To(Succeed())
+		upgradeJob := getMajorUpgradeJob(jobs.Items)
+		Expect(upgradeJob).ToNot(BeNil())
+		Expect(upgradeJob.Labels[utils.InstanceNameLabelName]).To(Equal(cluster.Status.CurrentPrimary))
+		Expect(upgradeJob.OwnerReferences).To(HaveLen(1))
+		Expect(upgradeJob.OwnerReferences[0].Name).To(Equal(cluster.Name))
+	})
+})
@@ -0,0 +1,142 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils/hash"
+)
+
+// mountedObjectContent is the content of a mounted secret or
+// config map, as used to compute the hash of the mounted objects
+type mountedObjectContent struct {
+	Kind string
+	Name string
+	Data map[string][]byte
+}
+
+// computeMountedObjectsHash computes the hash of the content of the mounted
+// secrets and config maps whose changes require the instances to be
+// restarted. A missing object is hashed as an empty one, so that its
+// creation is detected too
+func (r *ClusterReconciler) computeMountedObjectsHash(ctx context.Context, cluster *apiv1.Cluster) (string, error) {
+	var contents []mountedObjectContent
+	for _, reference := range cluster.Spec.MountedObjects {
+		if !reference.IsRestartRequired() {
+			continue
+		}
+
+		data, err := r.getMountedObjectData(ctx, cluster.Namespace, reference)
+		if err != nil {
+			return "", err
+		}
+		contents = append(contents, mountedObjectContent{
+			Kind: reference.Kind,
+			Name: reference.Name,
+			Data: data,
+		})
+	}
+
+	if len(contents) == 0 {
+		return "", nil
+	}
+
+	return hash.ComputeHash(contents)
+}
+
+// getMountedObjectData gets the content of a mounted secret or config map
+func (r *ClusterReconciler) getMountedObjectData(
+	ctx context.Context,
+	namespace string,
+	reference apiv1.MountedObjectReference,
+) (map[string][]byte, error) {
+	key := client.ObjectKey{Namespace: namespace, Name: reference.Name}
+
+	switch reference.Kind {
+	case "Secret":
+		var secret corev1.Secret
+		if err := r.Get(ctx, key, &secret); err != nil {
+			if apierrs.IsNotFound(err) {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("while getting mounted secret %s: %w", reference.Name, err)
+		}
+		return secret.Data, nil
+
+	case "ConfigMap":
+		var configMap corev1.ConfigMap
+		if err := r.Get(ctx, key, &configMap); err != nil {
+			if apierrs.IsNotFound(err) {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("while getting mounted config map %s: %w", reference.Name, err)
+		}
+		data := make(map[string][]byte, len(configMap.Data)+len(configMap.BinaryData))
+		for key, value := range configMap.Data {
+			data[key] = []byte(value)
+		}
+		for key, value := range configMap.BinaryData {
+			data[key] = value
+		}
+		return data, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported kind for mounted object %s: %s", reference.Name, reference.Kind)
+	}
+}
+
+// ensureMountedObjectsHashAnnotation sets the hash of the mounted objects
+// on the Pods created before they were tracked, as they are running with
+// the current content. Without that, their changes would never be detected
+func (r *ClusterReconciler) ensureMountedObjectsHashAnnotation(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	resources *managedResources,
+) error {
+	if cluster.Status.MountedObjectsHash == "" {
+		return nil
+	}
+
+	for idx := range resources.instances.Items {
+		pod := &resources.instances.Items[idx]
+		if _, ok := pod.Annotations[utils.PodMountedObjectsHashAnnotationName]; ok {
+			continue
+		}
+
+		log.FromContext(ctx).Info("Setting the hash of the mounted objects on the instance",
+			"pod", pod.Name)
+		origPod := pod.DeepCopy()
+		if pod.Annotations == nil {
+			pod.Annotations = make(map[string]string)
+		}
+		pod.Annotations[utils.PodMountedObjectsHashAnnotationName] = cluster.Status.MountedObjectsHash
+		if err := r.Patch(ctx, pod, client.MergeFrom(origPod)); err != nil {
+			return fmt.Errorf("while setting the hash of the mounted objects on %s: %w", pod.Name, err)
+		}
+	}
+
+	return nil
+}
@@ -22,18 +22,31 @@ import (
 	"fmt"
 
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/utils/strings/slices"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/certs"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 )
 
 // setupPostgresPKI create all the PKI infrastructure that PostgreSQL need to work
 // if using ssl=on
 func (r *ClusterReconciler) setupPostgresPKI(ctx context.Context, cluster *apiv1.Cluster) error {
+	// The server certificate may be issued by cert-manager
+	if cluster.GetServerIssuerRef() != nil {
+		if err := r.ensureServerCertificateFromIssuer(ctx, cluster); err != nil {
+			return err
+		}
+	}
+
 	// This is the CA of cluster
 	serverCaSecret, err := r.ensureServerCASecret(ctx, cluster)
 	if err != nil {
@@ -131,7 +144,7 @@ func (r *ClusterReconciler) ensureClientCASecret(ctx context.Context, cluster *a
 func (r *ClusterReconciler) ensureServerCASecret(ctx context.Context, cluster *apiv1.Cluster) (*v1.Secret, error) {
 	// If not specified, use default amd renew/generate
 	certificates := cluster.Spec.Certificates
-	if certificates == nil || certificates.ServerCASecret == "" {
+	if certificates == nil || (certificates.ServerCASecret == "" && certificates.ServerIssuerRef == nil) {
 		return r.ensureCASecret(ctx, cluster, cluster.GetServerCASecretName())
 	}
 
@@ -151,7 +164,7 @@ func (r *ClusterReconciler) ensureServerCASecret(ctx context.Context, cluster *a
 	}
 
 	// validate also ca.key if needed
-	if cluster.Spec.Certificates.ServerTLSSecret == "" {
+	if cluster.Spec.Certificates.ServerTLSSecret == "" && cluster.Spec.Certificates.ServerIssuerRef == nil {
 		_, err = certs.ParseCASecret(&secret)
 		if err != nil {
 			r.Recorder.Event(cluster, "Warning", "InvalidCASecret",
@@ -257,7 +270,8 @@ func (r *ClusterReconciler) ensureServerLeafCertificate(
 	opts *x509.VerifyOptions,
 ) error {
 	// If not specified generate/renew
-	if cluster.Spec.Certificates == nil || cluster.Spec.Certificates.ServerTLSSecret == "" {
+	certificates := cluster.Spec.Certificates
+	if certificates == nil || (certificates.ServerTLSSecret == "" && certificates.ServerIssuerRef == nil) {
 		return r.ensureLeafCertificate(ctx, cluster, secretName, commonName, caSecret, usage, altDNSNames, nil)
 	}
 
@@ -270,6 +284,96 @@ func (r *ClusterReconciler) ensureServerLeafCertificate(
 	return validateLeafCertificate(caSecret, &serverSecret, opts)
 }
 
+// ensureServerCertificateFromIssuer ensures that the cert-manager Certificate
+// requesting the server certificate exists and matches the cluster, after
+// having checked that the referenced issuer exists. The renewal of the
+// certificate is handled by cert-manager
+func (r *ClusterReconciler) ensureServerCertificateFromIssuer(ctx context.Context, cluster *apiv1.Cluster) error {
+	contextLogger := log.FromContext(ctx)
+	issuerRef := cluster.GetServerIssuerRef()
+
+	issuerKey := client.ObjectKey{Name: issuerRef.Name}
+	if issuerRef.GetKind() == apiv1.CertificateIssuerKind {
+		issuerKey.Namespace = cluster.Namespace
+	}
+	if err := r.Get(ctx, issuerKey, specs.NewCertManagerIssuer(*issuerRef)); err != nil {
+		if apierrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+			r.Recorder.Eventf(cluster, "Warning", "IssuerNotFound",
+				"Getting cert-manager %s %s", issuerRef.GetKind(), issuerRef.Name)
+		}
+		return fmt.Errorf("while getting the server certificate issuer %s %s: %w",
+			issuerRef.GetKind(), issuerRef.Name, err)
+	}
+
+	expectedCertificate := specs.CreateServerCertificate(*cluster)
+	var certificate unstructured.Unstructured
+	certificate.SetGroupVersionKind(expectedCertificate.GroupVersionKind())
+	err := r.Get(ctx, client.ObjectKeyFromObject(expectedCertificate), &certificate)
+	switch {
+	case apierrors.IsNotFound(err):
+		contextLogger.Info("Creating the cert-manager certificate for the server",
+			"name", expectedCertificate.GetName(), "issuer", issuerRef.Name)
+		if err := r.Create(ctx, expectedCertificate); err != nil {
+			return fmt.Errorf("while creating the server certificate: %w", err)
+		}
+	case err != nil:
+		return fmt.Errorf("while getting the server certificate: %w", err)
+	default:
+		if err := r.updateServerCertificate(ctx, &certificate, expectedCertificate); err != nil {
+			return err
+		}
+	}
+
+	// The rest of the PKI requires the certificate to be issued
+	var secret v1.Secret
+	err = r.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: cluster.GetServerTLSSecretName()}, &secret)
+	if apierrors.IsNotFound(err) {
+		return fmt.Errorf("waiting for cert-manager to issue the server certificate in secret %s",
+			cluster.GetServerTLSSecretName())
+	}
+
+	return err
+}
+
+// updateServerCertificate updates the fields of the server Certificate
+// which are managed by the operator, leaving the others untouched
+func (r *ClusterReconciler) updateServerCertificate(
+	ctx context.Context,
+	certificate *unstructured.Unstructured,
+	expectedCertificate *unstructured.Unstructured,
+) error {
+	spec, _, err := unstructured.NestedMap(certificate.Object, "spec")
+	if err != nil {
+		return fmt.Errorf("while reading the server certificate: %w", err)
+	}
+	if spec == nil {
+		spec = make(map[string]interface{})
+	}
+
+	expectedSpec, _, _ := unstructured.NestedMap(expectedCertificate.Object, "spec")
+	changed := false
+	for key, value := range expectedSpec {
+		if !equality.Semantic.DeepEqual(spec[key], value) {
+			spec[key] = value
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+
+	log.FromContext(ctx).Info("Updating the cert-manager certificate for the server",
+		"name", certificate.GetName())
+	if err := unstructured.SetNestedMap(certificate.Object, spec, "spec"); err != nil {
+		return err
+	}
+	if err := r.Update(ctx, certificate); err != nil {
+		return fmt.Errorf("while updating the server certificate: %w", err)
+	}
+
+	return nil
+}
+
 func validateLeafCertificate(caSecret *v1.Secret, serverSecret *v1.Secret, opts *x509.VerifyOptions) error {
 	publicKey, ok := caSecret.Data[certs.CACertKey]
 	if !ok {
@@ -300,6 +404,9 @@ func (r *ClusterReconciler) ensureLeafCertificate(
 	var secret v1.Secret
 	err := r.Get(ctx, secretName, &secret)
 	if err == nil {
+		if isCertificateRotationRequested(cluster, &secret) || isMissingAltDNSNames(cluster, &secret, altDNSNames) {
+			return r.rotateCertificate(ctx, cluster, caSecret, &secret, commonName, usage, altDNSNames)
+		}
 		return r.renewAndUpdateCertificate(ctx, caSecret, &secret)
 	}
 
@@ -318,6 +425,83 @@ func (r *ClusterReconciler) ensureLeafCertificate(
 	return r.Create(ctx, serverSecret)
 }
 
+// isCertificateRotationRequested checks if the user asked to reissue the
+// certificate contained in the passed secret, which is never done for
+// the secrets the operator didn't generate
+func isCertificateRotationRequested(cluster *apiv1.Cluster, secret *v1.Secret) bool {
+	requested := cluster.Annotations[specs.ClusterCertificatesRotationAnnotationName]
+	if requested == "" || !metav1.IsControlledBy(secret, cluster) {
+		return false
+	}
+
+	return secret.Annotations[specs.ClusterCertificatesRotationAnnotationName] != requested
+}
+
+// isMissingAltDNSNames checks if the certificate contained in the passed
+// secret doesn't include some of the required names, as it happens after
+// the services have been renamed. This is never checked for the secrets
+// the operator didn't generate
+func isMissingAltDNSNames(cluster *apiv1.Cluster, secret *v1.Secret, altDNSNames []string) bool {
+	if len(altDNSNames) == 0 || !metav1.IsControlledBy(secret, cluster) {
+		return false
+	}
+
+	pair, err := certs.ParseServerSecret(secret)
+	if err != nil {
+		return false
+	}
+	certificate, err := pair.ParseCertificate()
+	if err != nil {
+		return false
+	}
+
+	for _, name := range altDNSNames {
+		if !slices.Contains(certificate.DNSNames, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// rotateCertificate replaces the certificate contained in the passed secret
+// with a new one, having a new private key. The CA doesn't change, so the
+// connections using the previous certificate keep working until the instances
+// load the new one
+func (r *ClusterReconciler) rotateCertificate(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	caSecret *v1.Secret,
+	secret *v1.Secret,
+	commonName string,
+	usage certs.CertType,
+	altDNSNames []string,
+) error {
+	newSecret, err := generateCertificateFromCA(
+		caSecret, commonName, usage, altDNSNames, client.ObjectKeyFromObject(secret))
+	if err != nil {
+		return err
+	}
+
+	origSecret := secret.DeepCopy()
+	for key, value := range newSecret.Data {
+		secret.Data[key] = value
+	}
+	if secret.Annotations == nil {
+		secret.Annotations = make(map[string]string)
+	}
+	secret.Annotations[specs.ClusterCertificatesRotationAnnotationName] =
+		cluster.Annotations[specs.ClusterCertificatesRotationAnnotationName]
+
+	if err := r.Patch(ctx, secret, client.MergeFrom(origSecret)); err != nil {
+		return err
+	}
+
+	log.FromContext(ctx).Info("Rotated certificate", "secret", secret.Name)
+	r.Recorder.Eventf(cluster, "Normal", "CertificateRotated",
+		"Reissued the certificate in secret %s", secret.Name)
+	return nil
+}
+
 // generateCertificateFromCA create a certificate secret using the provided CA secret
 func generateCertificateFromCA(
 	caSecret *v1.Secret,
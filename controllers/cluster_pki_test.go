@@ -0,0 +1,79 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("certificate rotation", func() {
+	var cluster *apiv1.Cluster
+	var secret *corev1.Secret
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: apiv1.GroupVersion.String(),
+				Kind:       apiv1.ClusterKind,
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster-example",
+				Namespace: "default",
+				UID:       "cluster-example-uid",
+			},
+		}
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster-example-server",
+				Namespace: "default",
+			},
+		}
+		utils.SetAsOwnedBy(&secret.ObjectMeta, cluster.ObjectMeta, cluster.TypeMeta)
+	})
+
+	It("is not requested without the annotation", func() {
+		Expect(isCertificateRotationRequested(cluster, secret)).To(BeFalse())
+	})
+
+	It("is requested when the annotation changes", func() {
+		cluster.Annotations = map[string]string{
+			specs.ClusterCertificatesRotationAnnotationName: "2023-01-01T00:00:00Z",
+		}
+		Expect(isCertificateRotationRequested(cluster, secret)).To(BeTrue())
+
+		secret.Annotations = map[string]string{
+			specs.ClusterCertificatesRotationAnnotationName: "2023-01-01T00:00:00Z",
+		}
+		Expect(isCertificateRotationRequested(cluster, secret)).To(BeFalse())
+	})
+
+	It("is never requested for secrets not owned by the cluster", func() {
+		cluster.Annotations = map[string]string{
+			specs.ClusterCertificatesRotationAnnotationName: "2023-01-01T00:00:00Z",
+		}
+		secret.OwnerReferences = nil
+		Expect(isCertificateRotationRequested(cluster, secret)).To(BeFalse())
+	})
+})
@@ -18,6 +18,7 @@ package controllers
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
@@ -36,7 +37,7 @@ var (
 	isUsefulClusterSecret = func(object client.Object) bool {
 		return isOwnedByClusterOrSatisfiesPredicate(object, func(object client.Object) bool {
 			_, ok := object.(*corev1.Secret)
-			return ok && hasReloadLabelSet(object)
+			return ok && (hasReloadLabelSet(object) || isInCredentialsNamespace(object))
 		})
 	}
 
@@ -70,6 +71,15 @@ var (
 		},
 	}
 
+	// podsPredicate discards the Pod updates changing only the LSN annotation,
+	// which is refreshed by the reconciliation loop itself and would
+	// otherwise trigger a new reconciliation on every write activity
+	podsPredicate = predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return !isOnlyLSNAnnotationChanged(e.ObjectOld, e.ObjectNew)
+		},
+	}
+
 	nodesPredicate = predicate.Funcs{
 		UpdateFunc: func(e event.UpdateEvent) bool {
 			oldNode, oldOk := e.ObjectOld.(*corev1.Node)
@@ -100,3 +110,27 @@ func hasReloadLabelSet(obj client.Object) bool {
 	_, hasLabel := obj.GetLabels()[specs.WatchedLabelName]
 	return hasLabel
 }
+
+// isOnlyLSNAnnotationChanged checks if two versions of a Pod differ only
+// for the annotation containing the latest LSN of the instance
+func isOnlyLSNAnnotationChanged(oldObject, newObject client.Object) bool {
+	oldPod, oldOk := oldObject.(*corev1.Pod)
+	newPod, newOk := newObject.(*corev1.Pod)
+	if !oldOk || !newOk {
+		return false
+	}
+
+	if oldPod.Annotations[specs.ClusterLSNAnnotationName] == newPod.Annotations[specs.ClusterLSNAnnotationName] {
+		return false
+	}
+
+	oldPod = oldPod.DeepCopy()
+	newPod = newPod.DeepCopy()
+	for _, pod := range []*corev1.Pod{oldPod, newPod} {
+		delete(pod.Annotations, specs.ClusterLSNAnnotationName)
+		pod.ResourceVersion = ""
+		pod.ManagedFields = nil
+	}
+
+	return equality.Semantic.DeepEqual(oldPod, newPod)
+}
@@ -0,0 +1,56 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("cluster_predicates unit tests", func() {
+	oldPod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "cluster-example-1",
+			ResourceVersion: "1",
+			Labels:          map[string]string{specs.ClusterTimelineLabelName: "1"},
+			Annotations:     map[string]string{specs.ClusterLSNAnnotationName: "0/3000000"},
+		},
+	}
+
+	It("detects updates changing only the LSN annotation", func() {
+		newPod := oldPod.DeepCopy()
+		newPod.ResourceVersion = "2"
+		newPod.Annotations[specs.ClusterLSNAnnotationName] = "0/4000000"
+		Expect(isOnlyLSNAnnotationChanged(oldPod, newPod)).To(BeTrue())
+	})
+
+	It("considers updates changing anything else", func() {
+		newPod := oldPod.DeepCopy()
+		newPod.Annotations[specs.ClusterLSNAnnotationName] = "0/4000000"
+		newPod.Labels[specs.ClusterTimelineLabelName] = "2"
+		Expect(isOnlyLSNAnnotationChanged(oldPod, newPod)).To(BeFalse())
+
+		newPod = oldPod.DeepCopy()
+		newPod.Status.Phase = corev1.PodRunning
+		Expect(isOnlyLSNAnnotationChanged(oldPod, newPod)).To(BeFalse())
+	})
+})
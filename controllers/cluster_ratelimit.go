@@ -0,0 +1,164 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/ratelimiter"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+)
+
+// ReconcileLimits controls how fast the clusters are reconciled, to avoid
+// overloading the API server and the object stores when the operator
+// manages a large number of clusters
+type ReconcileLimits struct {
+	// The maximum delay applied to the first reconciliation of each
+	// cluster existing when the operator starts. Zero disables it
+	StartupJitter time.Duration
+
+	// The number of reconciliations per second allowed after a failure
+	// or a requeue, with the Burst allowed over it
+	QPS   float64
+	Burst int
+
+	// The delay of the exponential backoff applied after the first
+	// failure, and the maximum one, applied to the reconciliations
+	// of a failing cluster
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+
+	// The delay of the reconciliations failed because of a transient
+	// error, like a conflict or a timeout, which are retried without
+	// being logged as errors nor increasing the exponential backoff.
+	// Zero handles them like any other error
+	TransientErrorBackoff time.Duration
+}
+
+const (
+	// reconcileBaseBackoff is the default delay of the exponential
+	// backoff applied after the first failure
+	reconcileBaseBackoff = 5 * time.Millisecond
+
+	// transientErrorBackoffJitter is the maximum factor by which the delay
+	// of the reconciliations failed because of a transient error is
+	// increased, so that the clusters affected by the same outage are not
+	// retried all at once
+	transientErrorBackoffJitter = 0.1
+)
+
+// newRateLimiter creates the rate limiter of the workqueue of the cluster
+// controller, or nil to use the default one
+func (limits ReconcileLimits) newRateLimiter() ratelimiter.RateLimiter {
+	if limits.QPS <= 0 || limits.Burst <= 0 || limits.MaxBackoff <= 0 {
+		return nil
+	}
+
+	baseBackoff := limits.BaseBackoff
+	if baseBackoff <= 0 {
+		baseBackoff = reconcileBaseBackoff
+	}
+
+	return workqueue.NewMaxOfRateLimiter(
+		workqueue.NewItemExponentialFailureRateLimiter(baseBackoff, limits.MaxBackoff),
+		&workqueue.BucketRateLimiter{Limiter: rate.NewLimiter(rate.Limit(limits.QPS), limits.Burst)},
+	)
+}
+
+// transientErrorDelay gets after how long a reconciliation failed with the
+// passed error should be retried, or zero if the error is not transient or
+// the transient errors are handled like any other one
+func (limits ReconcileLimits) transientErrorDelay(err error) time.Duration {
+	if limits.TransientErrorBackoff <= 0 || !isTransientError(err) {
+		return 0
+	}
+
+	return wait.Jitter(limits.TransientErrorBackoff, transientErrorBackoffJitter)
+}
+
+// isTransientError checks if an error is expected to go away by itself,
+// like the conflicts and the timeouts of the API server or of the network
+// calls to the instances and the object stores, rather than being caused
+// by the configuration
+func isTransientError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	if apierrs.IsConflict(err) ||
+		apierrs.IsServerTimeout(err) ||
+		apierrs.IsTimeout(err) ||
+		apierrs.IsTooManyRequests(err) ||
+		apierrs.IsServiceUnavailable(err) ||
+		errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// startupJitter spreads over a time window the first reconciliation of
+// the clusters existing when the operator starts, which would otherwise
+// happen all at once
+type startupJitter struct {
+	window    time.Duration
+	startTime time.Time
+	seen      sync.Map
+}
+
+func newStartupJitter(window time.Duration) *startupJitter {
+	return &startupJitter{
+		window:    window,
+		startTime: time.Now(),
+	}
+}
+
+// delay gets how long the reconciliation of a cluster should be postponed,
+// which is a random time within the window for its first reconciliation
+// and zero for the following ones
+func (jitter *startupJitter) delay(cluster *apiv1.Cluster) time.Duration {
+	if jitter == nil || jitter.window <= 0 || !cluster.CreationTimestamp.Time.Before(jitter.startTime) {
+		return 0
+	}
+
+	key := types.NamespacedName{Namespace: cluster.Namespace, Name: cluster.Name}
+	if _, seen := jitter.seen.LoadOrStore(key, struct{}{}); seen {
+		return 0
+	}
+
+	return time.Duration(rand.Int63n(int64(jitter.window))) // #nosec
+}
+
+// forget removes a deleted cluster from the ones already reconciled
+func (jitter *startupJitter) forget(key types.NamespacedName) {
+	if jitter == nil {
+		return
+	}
+	jitter.seen.Delete(key)
+}
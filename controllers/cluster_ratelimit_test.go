@@ -0,0 +1,130 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("startup jitter", func() {
+	newCluster := func(creationTime time.Time) *apiv1.Cluster {
+		return &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "cluster-example",
+				Namespace:         "default",
+				CreationTimestamp: metav1.NewTime(creationTime),
+			},
+		}
+	}
+
+	It("postpones only the first reconciliation of the existing clusters", func() {
+		jitter := newStartupJitter(time.Minute)
+		cluster := newCluster(jitter.startTime.Add(-time.Hour))
+
+		delay := jitter.delay(cluster)
+		Expect(delay).To(BeNumerically(">=", 0))
+		Expect(delay).To(BeNumerically("<", time.Minute))
+		Expect(jitter.delay(cluster)).To(BeZero())
+
+		jitter.forget(types.NamespacedName{Namespace: "default", Name: "cluster-example"})
+		Expect(jitter.delay(cluster)).To(BeNumerically("<", time.Minute))
+	})
+
+	It("doesn't postpone the clusters created after the operator started", func() {
+		jitter := newStartupJitter(time.Minute)
+		Expect(jitter.delay(newCluster(jitter.startTime.Add(time.Second)))).To(BeZero())
+	})
+
+	It("can be disabled", func() {
+		var jitter *startupJitter
+		Expect(jitter.delay(newCluster(time.Now().Add(-time.Hour)))).To(BeZero())
+		Expect(newStartupJitter(0).delay(newCluster(time.Now().Add(-time.Hour)))).To(BeZero())
+	})
+})
+
+var _ = Describe("reconcile rate limiter", func() {
+	It("uses the default rate limiter when the limits are not set", func() {
+		Expect(ReconcileLimits{}.newRateLimiter()).To(BeNil())
+	})
+
+	It("creates a rate limiter when the limits are set", func() {
+		limits := ReconcileLimits{QPS: 5, Burst: 10, MaxBackoff: time.Minute}
+		Expect(limits.newRateLimiter()).ToNot(BeNil())
+	})
+
+	It("starts the exponential backoff from the configured delay", func() {
+		limits := ReconcileLimits{QPS: 5, Burst: 10, MaxBackoff: time.Minute}
+		Expect(limits.newRateLimiter().When("default")).To(Equal(reconcileBaseBackoff))
+
+		limits.BaseBackoff = time.Second
+		rateLimiter := limits.newRateLimiter()
+		Expect(rateLimiter.When("configured")).To(Equal(time.Second))
+		Expect(rateLimiter.When("configured")).To(Equal(2 * time.Second))
+	})
+})
+
+var _ = Describe("transient errors", func() {
+	clusterResource := schema.GroupResource{Group: "postgresql.cnpg.io", Resource: "clusters"}
+
+	DescribeTable("are recognized",
+		func(err error, transient bool) {
+			Expect(isTransientError(err)).To(Equal(transient))
+		},
+		Entry("no error", nil, false),
+		Entry("a conflict", apierrs.NewConflict(clusterResource, "cluster-example", errors.New("conflict")), true),
+		Entry("a wrapped conflict",
+			fmt.Errorf("cannot update the cluster: %w",
+				apierrs.NewConflict(clusterResource, "cluster-example", errors.New("conflict"))),
+			true),
+		Entry("a server timeout", apierrs.NewServerTimeout(clusterResource, "get", 1), true),
+		Entry("a timeout", apierrs.NewTimeoutError("timeout", 1), true),
+		Entry("too many requests", apierrs.NewTooManyRequests("slow down", 1), true),
+		Entry("an unavailable service", apierrs.NewServiceUnavailable("unavailable"), true),
+		Entry("an expired context", fmt.Errorf("while checking the backup: %w", context.DeadlineExceeded), true),
+		Entry("a network timeout", &net.DNSError{Err: "timeout", IsTimeout: true}, true),
+		Entry("a network error", &net.DNSError{Err: "no such host"}, false),
+		Entry("an invalid object", apierrs.NewBadRequest("invalid"), false),
+		Entry("a generic error", errors.New("missing secret"), false),
+	)
+
+	It("are retried after the configured delay", func() {
+		conflict := apierrs.NewConflict(clusterResource, "cluster-example", errors.New("conflict"))
+
+		Expect(ReconcileLimits{}.transientErrorDelay(conflict)).To(BeZero())
+
+		limits := ReconcileLimits{TransientErrorBackoff: 10 * time.Second}
+		Expect(limits.transientErrorDelay(conflict)).To(And(
+			BeNumerically(">=", 10*time.Second),
+			BeNumerically("<=", 11*time.Second),
+		))
+		Expect(limits.transientErrorDelay(errors.New("missing secret"))).To(BeZero())
+	})
+})
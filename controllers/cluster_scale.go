@@ -41,8 +41,13 @@ func (r *ClusterReconciler) scaleDownCluster(
 ) error {
 	contextLogger := log.FromContext(ctx)
 
-	if cluster.Spec.MaxSyncReplicas > 0 && cluster.Spec.Instances < (cluster.Spec.MaxSyncReplicas+1) {
-		cluster.Spec.Instances = cluster.Status.Instances
+	// Read replicas in excess are removed first, the other instances
+	// are only removed when they exceed `.spec.instances`
+	scalingReadReplicas := len(cluster.Status.ReadReplicas) > cluster.GetReadReplicas()
+
+	if !scalingReadReplicas && cluster.Spec.MaxSyncReplicas > 0 &&
+		cluster.Spec.Instances < (cluster.Spec.MaxSyncReplicas+1) {
+		cluster.Spec.Instances = cluster.Status.Instances - len(cluster.Status.ReadReplicas)
 		if err := r.Update(ctx, cluster); err != nil {
 			return err
 		}
@@ -55,7 +60,7 @@ func (r *ClusterReconciler) scaleDownCluster(
 	}
 
 	// Is there one pod to be deleted?
-	sacrificialInstance := getSacrificialInstance(resources.instances.Items)
+	sacrificialInstance := getSacrificialInstance(filterReadReplicas(resources.instances.Items, scalingReadReplicas))
 	if sacrificialInstance == nil {
 		contextLogger.Info("There are no instances to be sacrificed. Wait for the next sync loop")
 		return nil
@@ -66,18 +71,31 @@ func (r *ClusterReconciler) scaleDownCluster(
 
 	contextLogger.Info("Too many nodes for cluster, deleting an instance",
 		"pod", sacrificialInstance.Name)
-	if err := r.Delete(ctx, sacrificialInstance); err != nil {
+	return r.deleteInstance(ctx, cluster, resources, sacrificialInstance)
+}
+
+// deleteInstance deletes the Pod of an instance together with its PVCs and
+// the Jobs which were working against them
+func (r *ClusterReconciler) deleteInstance(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	resources *managedResources,
+	instance *v1.Pod,
+) error {
+	contextLogger := log.FromContext(ctx)
+
+	if err := r.Delete(ctx, instance); err != nil {
 		// Ignore if NotFound, otherwise report the error
 		if !apierrs.IsNotFound(err) {
-			return fmt.Errorf("cannot kill the Pod to scale down: %w", err)
+			return fmt.Errorf("cannot delete the Pod: %w", err)
 		}
 	}
 
 	// Let's drop the PVC too
 	pvc := v1.PersistentVolumeClaim{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      sacrificialInstance.Name,
-			Namespace: sacrificialInstance.Namespace,
+			Name:      instance.Name,
+			Namespace: instance.Namespace,
 		},
 	}
 
@@ -85,31 +103,48 @@ func (r *ClusterReconciler) scaleDownCluster(
 	if err := r.Delete(ctx, &pvc); err != nil {
 		// Ignore if NotFound, otherwise report the error
 		if !apierrs.IsNotFound(err) {
-			return fmt.Errorf("scaling down node (pgdata pvc) %v: %w", sacrificialInstance.Name, err)
+			return fmt.Errorf("deleting instance (pgdata pvc) %v: %w", instance.Name, err)
 		}
 	}
 
 	if cluster.ShouldCreateWalArchiveVolume() {
 		// Let's drop the WAL PVC too
-		pvcWalName := persistentvolumeclaim.GetName(cluster, sacrificialInstance.Name, utils.PVCRolePgWal)
+		pvcWalName := persistentvolumeclaim.GetName(cluster, instance.Name, utils.PVCRolePgWal)
 		pvcWal := v1.PersistentVolumeClaim{
 			ObjectMeta: metav1.ObjectMeta{
 				Name:      pvcWalName,
-				Namespace: sacrificialInstance.Namespace,
+				Namespace: instance.Namespace,
 			},
 		}
 		contextLogger.Info("Deleting WAL PVC", "pvc", pvcWal.Name)
 		if err := r.Delete(ctx, &pvcWal); err != nil {
 			// Ignore if NotFound, otherwise report the error
 			if !apierrs.IsNotFound(err) {
-				return fmt.Errorf("scaling down node (wal pvc) %v: %w", sacrificialInstance.Name, err)
+				return fmt.Errorf("deleting instance (wal pvc) %v: %w", instance.Name, err)
+			}
+		}
+	}
+
+	// And the PVCs containing the tablespaces
+	for _, tablespace := range cluster.Spec.Tablespaces {
+		pvcTablespace := v1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      persistentvolumeclaim.GetTablespaceName(instance.Name, tablespace),
+				Namespace: instance.Namespace,
+			},
+		}
+		contextLogger.Info("Deleting tablespace PVC", "pvc", pvcTablespace.Name)
+		if err := r.Delete(ctx, &pvcTablespace); err != nil {
+			// Ignore if NotFound, otherwise report the error
+			if !apierrs.IsNotFound(err) {
+				return fmt.Errorf("deleting instance (tablespace pvc) %v: %w", instance.Name, err)
 			}
 		}
 	}
 
 	// And now also the Job
 	for idx := range resources.jobs.Items {
-		if strings.HasPrefix(resources.jobs.Items[idx].Name, sacrificialInstance.Name+"-") {
+		if strings.HasPrefix(resources.jobs.Items[idx].Name, instance.Name+"-") {
 			// This job was working against the PVC of this Pod,
 			// let's remove it
 			foreground := metav1.DeletePropagationForeground
@@ -122,7 +157,7 @@ func (r *ClusterReconciler) scaleDownCluster(
 			); err != nil {
 				// Ignore if NotFound, otherwise report the error
 				if !apierrs.IsNotFound(err) {
-					return fmt.Errorf("scaling down node (job) %v: %w", sacrificialInstance.Name, err)
+					return fmt.Errorf("deleting instance (job) %v: %w", instance.Name, err)
 				}
 			}
 		}
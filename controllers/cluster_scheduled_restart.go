@@ -0,0 +1,137 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/robfig/cron"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+)
+
+// reconcileScheduledRestart requests a rolling restart of the instances
+// when the time of the scheduled restart has come, by setting the same
+// annotation used by the `restart` command of the plugin. The restart is
+// skipped when a rolling update is already in progress. It returns the
+// time to wait before the next scheduled restart
+func (r *ClusterReconciler) reconcileScheduledRestart(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	instancesStatus postgres.PostgresqlStatusList,
+) (time.Duration, error) {
+	contextLogger := log.FromContext(ctx)
+
+	if !cluster.IsScheduledRestartEnabled() {
+		if cluster.Status.NextScheduledRestart == "" {
+			return 0, nil
+		}
+		cluster.Status.NextScheduledRestart = ""
+		return 0, r.Status().Update(ctx, cluster)
+	}
+
+	now := time.Now().UTC()
+	schedule, scheduledTime, err := getScheduledRestartTime(cluster, now)
+	if err != nil {
+		return 0, err
+	}
+
+	if now.Before(scheduledTime) {
+		nextScheduledRestart := scheduledTime.Format(time.RFC3339)
+		if cluster.Status.NextScheduledRestart != nextScheduledRestart {
+			contextLogger.Info("Next scheduled restart", "next", nextScheduledRestart)
+			cluster.Status.NextScheduledRestart = nextScheduledRestart
+			if err := r.Status().Update(ctx, cluster); err != nil {
+				return 0, err
+			}
+		}
+		return scheduledTime.Sub(now), nil
+	}
+
+	if reason := getRolloutInProgressReason(cluster, instancesStatus); reason != "" {
+		contextLogger.Info("Skipping the scheduled restart, a rolling update is already in progress",
+			"scheduledTime", scheduledTime, "reason", reason)
+		r.Recorder.Eventf(cluster, "Normal", "ScheduledRestartSkipped",
+			"Skipped the restart scheduled at %s, a rolling update is already in progress: %s",
+			scheduledTime.Format(time.RFC3339), reason)
+	} else {
+		contextLogger.Info("Requesting the scheduled restart of the instances", "scheduledTime", scheduledTime)
+		restartedAt := now.Format(time.RFC3339)
+		origCluster := cluster.DeepCopy()
+		if cluster.Annotations == nil {
+			cluster.Annotations = make(map[string]string)
+		}
+		cluster.Annotations[specs.ClusterRestartAnnotationName] = restartedAt
+		if err := r.Patch(ctx, cluster, client.MergeFrom(origCluster)); err != nil {
+			return 0, fmt.Errorf("while requesting the scheduled restart: %w", err)
+		}
+		r.Recorder.Eventf(cluster, "Normal", "ScheduledRestart",
+			"Requested the restart scheduled at %s", scheduledTime.Format(time.RFC3339))
+		cluster.Status.LastScheduledRestart = restartedAt
+	}
+
+	nextTime := schedule.Next(now)
+	cluster.Status.NextScheduledRestart = nextTime.Format(time.RFC3339)
+	if err := r.Status().Update(ctx, cluster); err != nil {
+		return 0, err
+	}
+
+	return nextTime.Sub(now), nil
+}
+
+// getScheduledRestartTime gets the schedule of the restarts and the time of
+// the next one, which is the one recorded in the status unless it is not
+// part of the current schedule, i.e. because the schedule has been changed
+func getScheduledRestartTime(cluster *apiv1.Cluster, now time.Time) (cron.Schedule, time.Time, error) {
+	schedule, err := cron.ParseStandard(cluster.Spec.ScheduledRestart.Schedule)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("invalid schedule of the restarts %q: %w",
+			cluster.Spec.ScheduledRestart.Schedule, err)
+	}
+
+	if cluster.Status.NextScheduledRestart != "" {
+		recorded, err := time.Parse(time.RFC3339, cluster.Status.NextScheduledRestart)
+		if err == nil && schedule.Next(recorded.Add(-time.Second)).Equal(recorded) {
+			return schedule, recorded, nil
+		}
+	}
+
+	return schedule, schedule.Next(now), nil
+}
+
+// getRolloutInProgressReason gets the reason why a rolling update of the
+// instances is in progress, or an empty string if there is none
+func getRolloutInProgressReason(cluster *apiv1.Cluster, instancesStatus postgres.PostgresqlStatusList) string {
+	switch cluster.Status.Phase {
+	case apiv1.PhaseUpgrade, apiv1.PhaseWaitingForUser, apiv1.PhaseOnlineUpgrading:
+		return cluster.Status.Phase
+	}
+
+	for _, item := range instancesStatus.Items {
+		if needsRollout, _, reason := IsPodNeedingRollout(item, cluster); needsRollout {
+			return fmt.Sprintf("%s (%s)", item.Pod.Name, reason)
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,80 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"time"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("scheduled restart", func() {
+	// 2023-03-06 is a Monday
+	now := time.Date(2023, time.March, 6, 12, 0, 0, 0, time.UTC)
+	cluster := apiv1.Cluster{
+		Spec: apiv1.ClusterSpec{
+			ImageName:        "postgres:13.0",
+			ScheduledRestart: &apiv1.ScheduledRestartConfiguration{Schedule: "0 3 * * *"},
+		},
+	}
+
+	It("computes the next restart from the schedule", func() {
+		_, scheduledTime, err := getScheduledRestartTime(&cluster, now)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(scheduledTime).To(Equal(time.Date(2023, time.March, 7, 3, 0, 0, 0, time.UTC)))
+	})
+
+	It("keeps the next restart recorded in the status", func() {
+		recordedCluster := cluster.DeepCopy()
+		recordedCluster.Status.NextScheduledRestart = "2023-03-06T03:00:00Z"
+		_, scheduledTime, err := getScheduledRestartTime(recordedCluster, now)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(scheduledTime).To(Equal(time.Date(2023, time.March, 6, 3, 0, 0, 0, time.UTC)))
+	})
+
+	It("discards the recorded restart when the schedule is changed", func() {
+		changedCluster := cluster.DeepCopy()
+		changedCluster.Spec.ScheduledRestart.Schedule = "30 4 * * *"
+		changedCluster.Status.NextScheduledRestart = "2023-03-06T03:00:00Z"
+		_, scheduledTime, err := getScheduledRestartTime(changedCluster, now)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(scheduledTime).To(Equal(time.Date(2023, time.March, 7, 4, 30, 0, 0, time.UTC)))
+	})
+
+	It("detects the rolling updates in progress", func() {
+		pod := specs.PodWithExistingStorage(cluster, 1)
+		statuses := postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				{Pod: *pod, IsPodReady: true, ExecutableHash: "test_hash"},
+			},
+		}
+		Expect(getRolloutInProgressReason(&cluster, statuses)).To(BeEmpty())
+
+		statuses.Items[0].PendingRestart = true
+		Expect(getRolloutInProgressReason(&cluster, statuses)).To(ContainSubstring(pod.Name))
+
+		upgradingCluster := cluster.DeepCopy()
+		upgradingCluster.Status.Phase = apiv1.PhaseUpgrade
+		Expect(getRolloutInProgressReason(upgradingCluster, postgres.PostgresqlStatusList{})).
+			To(Equal(apiv1.PhaseUpgrade))
+	})
+})
@@ -24,28 +24,36 @@ import (
 	"net/http"
 	"reflect"
 	"sort"
+	"strings"
 	"time"
 
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/utils/strings/slices"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/certs"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/executablehash"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/url"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/reconciler/persistentvolumeclaim"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/stringset"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/versions"
 )
 
+// resourceUsageRefreshInterval is the minimum interval between two
+// updates of the resource usage of an instance in the cluster status
+const resourceUsageRefreshInterval = time.Minute
+
 // StatusRequestRetry is the default backoff used to query the instance manager
 // for the status of each PostgreSQL instance.
 var StatusRequestRetry = wait.Backoff{
@@ -72,6 +80,29 @@ func (resources *managedResources) countRunningJobs() int {
 	return jobCount - completeJobs
 }
 
+// Count the instances which are either active or being created by a
+// Job which is still running, considering each instance only once and
+// ignoring the Jobs which are not related to an instance
+func (resources *managedResources) countInstancesAndPendingJoins() int {
+	instances := stringset.New()
+	for idx := range resources.instances.Items {
+		if utils.IsPodActive(resources.instances.Items[idx]) {
+			instances.Put(resources.instances.Items[idx].Name)
+		}
+	}
+
+	for _, job := range resources.jobs.Items {
+		// Jobs not bound to an instance, like the WAL mirroring ones,
+		// are not creating any new Pod
+		instanceName := job.Labels[utils.InstanceNameLabelName]
+		if instanceName != "" && !utils.JobHasOneCompletion(job) {
+			instances.Put(instanceName)
+		}
+	}
+
+	return instances.Len()
+}
+
 // Check if every managed Pod is active and will be schedules
 func (resources *managedResources) allInstancesAreActive() bool {
 	for idx := range resources.instances.Items {
@@ -275,6 +306,7 @@ func (r *ClusterReconciler) updateResourceStatus(
 	newInstances := len(filteredPods)
 	cluster.Status.Instances = newInstances
 	cluster.Status.ReadyInstances = utils.CountReadyPods(filteredPods)
+	cluster.Status.ReadReplicas = getReadReplicaNames(filteredPods)
 
 	// Count jobs
 	newJobs := int32(len(resources.jobs.Items))
@@ -361,6 +393,14 @@ func (r *ClusterReconciler) updateResourceStatus(
 		return err
 	}
 
+	cluster.Status.MountedObjectsHash, err = r.computeMountedObjectsHash(ctx, cluster)
+	if err != nil {
+		return err
+	}
+
+	// The history lists are shortened when the retained length is reduced
+	cluster.TruncateStatusHistory()
+
 	if !reflect.DeepEqual(existingClusterStatus, cluster.Status) {
 		return r.Status().Update(ctx, cluster)
 	}
@@ -612,6 +652,14 @@ func (r *ClusterReconciler) refreshSecretResourceVersions(ctx context.Context, c
 	}
 	versions.ApplicationSecretVersion = version
 
+	if cluster.IsMonitoringRoleEnabled() {
+		version, err = r.getSecretResourceVersion(ctx, cluster, cluster.GetMonitoringSecretName())
+		if err != nil {
+			return err
+		}
+		versions.MonitoringSecretVersion = version
+	}
+
 	certificates := cluster.Status.Certificates
 
 	// Reset the content of the unused CASecretVersion field
@@ -714,6 +762,298 @@ func (r *ClusterReconciler) setPrimaryInstance(
 	return r.Status().Update(ctx, cluster)
 }
 
+// setConfigurationDriftCondition sets the condition reporting which
+// instances override the parameters managed by the operator
+func setConfigurationDriftCondition(cluster *apiv1.Cluster, statuses postgres.PostgresqlStatusList) {
+	if len(statuses.Items) == 0 {
+		return
+	}
+
+	var drifts []string
+	for _, item := range statuses.Items {
+		if len(item.ConfigurationDrift) > 0 {
+			drifts = append(drifts, fmt.Sprintf("%s (%s)",
+				item.Pod.Name, strings.Join(item.ConfigurationDrift, ", ")))
+		}
+	}
+
+	condition := metav1.Condition{
+		Type:    string(apiv1.ConditionConfigurationDrift),
+		Status:  metav1.ConditionFalse,
+		Reason:  string(apiv1.ConditionReasonConfigurationAligned),
+		Message: "No instance overrides the parameters managed by the operator",
+	}
+	if len(drifts) > 0 {
+		condition = metav1.Condition{
+			Type:   string(apiv1.ConditionConfigurationDrift),
+			Status: metav1.ConditionTrue,
+			Reason: string(apiv1.ConditionReasonConfigurationDriftDetected),
+			Message: "Parameters managed by the operator are overridden in postgresql.auto.conf: " +
+				strings.Join(drifts, "; "),
+		}
+	}
+
+	meta.SetStatusCondition(&cluster.Status.Conditions, condition)
+}
+
+// setWALArchivingBacklogCondition sets the condition reporting which
+// instances have more WAL files waiting to be archived than the threshold,
+// which usually means the object store is not reachable or too slow
+func setWALArchivingBacklogCondition(
+	cluster *apiv1.Cluster,
+	statuses postgres.PostgresqlStatusList,
+	threshold int,
+) {
+	if len(statuses.Items) == 0 {
+		return
+	}
+
+	var backlogs []string
+	for _, item := range statuses.Items {
+		if item.ReadyWALFiles > threshold {
+			backlogs = append(backlogs, fmt.Sprintf("%s (%d)", item.Pod.Name, item.ReadyWALFiles))
+		}
+	}
+
+	condition := metav1.Condition{
+		Type:    string(apiv1.ConditionWALArchivingBacklog),
+		Status:  metav1.ConditionFalse,
+		Reason:  string(apiv1.ConditionReasonWALArchivingCaughtUp),
+		Message: fmt.Sprintf("No instance has more than %d WAL files waiting to be archived", threshold),
+	}
+	if len(backlogs) > 0 {
+		condition = metav1.Condition{
+			Type:   string(apiv1.ConditionWALArchivingBacklog),
+			Status: metav1.ConditionTrue,
+			Reason: string(apiv1.ConditionReasonWALArchivingBacklogDetected),
+			Message: fmt.Sprintf("More than %d WAL files are waiting to be archived: %s",
+				threshold, strings.Join(backlogs, ", ")),
+		}
+	}
+
+	meta.SetStatusCondition(&cluster.Status.Conditions, condition)
+}
+
+// setMajorVersionMismatchCondition sets the condition reporting whether
+// the instances are running different PostgreSQL major versions, i.e.
+// after a failed major upgrade. Replication across major versions is not
+// possible, and the reconciliation of the cluster is halted until the
+// instances are aligned
+func setMajorVersionMismatchCondition(cluster *apiv1.Cluster, statuses postgres.PostgresqlStatusList) {
+	var instances []string
+	majorVersions := make(map[int]bool)
+	primaryMajorVersion := 0
+	for _, item := range statuses.Items {
+		if item.Error != nil || item.PostgresMajorVersion == 0 {
+			continue
+		}
+
+		majorVersions[item.PostgresMajorVersion] = true
+		instances = append(instances, fmt.Sprintf("%s (%d)", item.Pod.Name, item.PostgresMajorVersion))
+		if item.IsPrimary {
+			primaryMajorVersion = item.PostgresMajorVersion
+		}
+	}
+
+	if len(majorVersions) == 0 {
+		return
+	}
+
+	condition := metav1.Condition{
+		Type:    string(apiv1.ConditionMajorVersionMismatch),
+		Status:  metav1.ConditionFalse,
+		Reason:  string(apiv1.ConditionReasonMajorVersionsAligned),
+		Message: "Every instance is running the same PostgreSQL major version",
+	}
+	if len(majorVersions) > 1 {
+		targetVersion := "the major version of the primary"
+		if primaryMajorVersion != 0 {
+			targetVersion = fmt.Sprintf("the major version of the primary (%d)", primaryMajorVersion)
+		}
+		condition = metav1.Condition{
+			Type:   string(apiv1.ConditionMajorVersionMismatch),
+			Status: metav1.ConditionTrue,
+			Reason: string(apiv1.ConditionReasonMajorVersionMismatchDetected),
+			Message: fmt.Sprintf("The instances are running different PostgreSQL major versions: %s. "+
+				"The reconciliation is halted, as replication across major versions is not possible. "+
+				"To recover, set the image of the cluster to %s, then delete the Pods and the PVCs "+
+				"of the instances running a different version for them to be recreated as replicas",
+				strings.Join(instances, ", "), targetVersion),
+		}
+	}
+
+	meta.SetStatusCondition(&cluster.Status.Conditions, condition)
+}
+
+// setReplicasUnavailableCondition sets the condition reporting whether the
+// primary is healthy while none of the replicas is ready, e.g. when the
+// nodes hosting them are lost. The primary keeps serving writes, and the
+// message describes how the synchronous replication is affected
+func setReplicasUnavailableCondition(cluster *apiv1.Cluster, statuses postgres.PostgresqlStatusList) {
+	if len(statuses.Items) == 0 {
+		return
+	}
+
+	condition := metav1.Condition{
+		Type:    string(apiv1.ConditionReplicasUnavailable),
+		Status:  metav1.ConditionFalse,
+		Reason:  string(apiv1.ConditionReasonReplicasAvailable),
+		Message: "At least one replica is ready, or the cluster has no replicas",
+	}
+	if isPrimaryHealthyWithoutReplicas(cluster, statuses) {
+		syncReplication := "No failover is possible until a replica is back"
+		switch {
+		case cluster.Spec.MinSyncReplicas > 0 &&
+			cluster.GetReplicasUnavailablePolicy() == apiv1.ReplicasUnavailablePolicyEnforceSync:
+			syncReplication = fmt.Sprintf("%s, and the commits wait for %d synchronous standbys "+
+				"as required by the %s policy", syncReplication, cluster.Spec.MinSyncReplicas,
+				apiv1.ReplicasUnavailablePolicyEnforceSync)
+		case cluster.Spec.MaxSyncReplicas > 0:
+			syncReplication = fmt.Sprintf("%s, and the synchronous replication is suspended "+
+				"to keep accepting writes", syncReplication)
+		}
+		condition = metav1.Condition{
+			Type:   string(apiv1.ConditionReplicasUnavailable),
+			Status: metav1.ConditionTrue,
+			Reason: string(apiv1.ConditionReasonAllReplicasUnavailable),
+			Message: fmt.Sprintf("The primary %s is healthy, but none of the replicas is ready. %s",
+				cluster.Status.CurrentPrimary, syncReplication),
+		}
+	}
+
+	meta.SetStatusCondition(&cluster.Status.Conditions, condition)
+}
+
+// setReadOnlyCondition sets the condition reporting whether the primary
+// is rejecting the writes because the cluster has been set to read-only,
+// and whether the primary still has to apply a change of the read-only mode
+func setReadOnlyCondition(cluster *apiv1.Cluster, statuses postgres.PostgresqlStatusList) {
+	var primary *postgres.PostgresqlStatus
+	for idx := range statuses.Items {
+		if statuses.Items[idx].Error == nil && statuses.Items[idx].IsPrimary {
+			primary = &statuses.Items[idx]
+			break
+		}
+	}
+	if primary == nil {
+		return
+	}
+
+	var condition metav1.Condition
+	switch {
+	case cluster.IsReadOnly() && primary.IsReadOnlyDefault:
+		condition = metav1.Condition{
+			Type:    string(apiv1.ConditionReadOnly),
+			Status:  metav1.ConditionTrue,
+			Reason:  string(apiv1.ConditionReasonReadOnlyEnabled),
+			Message: fmt.Sprintf("The primary %s is rejecting the writes", primary.Pod.Name),
+		}
+	case cluster.IsReadOnly():
+		condition = metav1.Condition{
+			Type:   string(apiv1.ConditionReadOnly),
+			Status: metav1.ConditionFalse,
+			Reason: string(apiv1.ConditionReasonReadOnlyRequested),
+			Message: fmt.Sprintf("Waiting for the primary %s to apply the read-only mode",
+				primary.Pod.Name),
+		}
+	case primary.IsReadOnlyDefault && isReadOnlyDefaultParameterSet(cluster):
+		condition = metav1.Condition{
+			Type:   string(apiv1.ConditionReadOnly),
+			Status: metav1.ConditionTrue,
+			Reason: string(apiv1.ConditionReasonReadOnlyEnabled),
+			Message: fmt.Sprintf("The primary %s is rejecting the writes, as configured "+
+				"by the default_transaction_read_only parameter", primary.Pod.Name),
+		}
+	case primary.IsReadOnlyDefault:
+		condition = metav1.Condition{
+			Type:   string(apiv1.ConditionReadOnly),
+			Status: metav1.ConditionTrue,
+			Reason: string(apiv1.ConditionReasonReadWriteRequested),
+			Message: fmt.Sprintf("Waiting for the primary %s to accept the writes again",
+				primary.Pod.Name),
+		}
+	default:
+		condition = metav1.Condition{
+			Type:    string(apiv1.ConditionReadOnly),
+			Status:  metav1.ConditionFalse,
+			Reason:  string(apiv1.ConditionReasonReadWrite),
+			Message: fmt.Sprintf("The primary %s is accepting the writes", primary.Pod.Name),
+		}
+	}
+
+	meta.SetStatusCondition(&cluster.Status.Conditions, condition)
+}
+
+// isReadOnlyDefaultParameterSet checks if the read-only default is
+// directly set through the PostgreSQL parameters of the cluster
+func isReadOnlyDefaultParameterSet(cluster *apiv1.Cluster) bool {
+	_, ok := cluster.Spec.PostgresConfiguration.Parameters["default_transaction_read_only"]
+	return ok
+}
+
+// setWALKeepSizeCondition sets the condition reporting whether the WAL
+// files kept for the standbys take more than half of the volume storing
+// the WAL files, leaving little room for the ones waiting to be archived
+// or retained by the replication slots
+func setWALKeepSizeCondition(cluster *apiv1.Cluster) {
+	walKeepSize := cluster.Spec.WalKeepSize
+	walVolumeSize := cluster.GetWalVolumeSize()
+	if walKeepSize == nil || walVolumeSize == nil {
+		meta.RemoveStatusCondition(&cluster.Status.Conditions, string(apiv1.ConditionWALKeepSizeAtRisk))
+		return
+	}
+
+	condition := metav1.Condition{
+		Type:   string(apiv1.ConditionWALKeepSizeAtRisk),
+		Status: metav1.ConditionFalse,
+		Reason: string(apiv1.ConditionReasonWALKeepSizeFits),
+		Message: fmt.Sprintf("The WAL files kept for the standbys (%s) take at most half "+
+			"of the WAL volume (%s)", walKeepSize.String(), walVolumeSize.String()),
+	}
+	if walKeepSize.Value()*2 > walVolumeSize.Value() {
+		condition = metav1.Condition{
+			Type:   string(apiv1.ConditionWALKeepSizeAtRisk),
+			Status: metav1.ConditionTrue,
+			Reason: string(apiv1.ConditionReasonWALKeepSizeTooLarge),
+			Message: fmt.Sprintf("The WAL files kept for the standbys (%s) take more than half "+
+				"of the WAL volume (%s) and may fill it", walKeepSize.String(), walVolumeSize.String()),
+		}
+	}
+
+	meta.SetStatusCondition(&cluster.Status.Conditions, condition)
+}
+
+// setQuorumAtRiskCondition sets the condition reporting whether the
+// number of instances gives a clear majority to the quorum of the
+// synchronous replication. The webhook rejects an even number only when
+// the operator is configured to enforce it, so this is where the users
+// are told about it otherwise
+func setQuorumAtRiskCondition(cluster *apiv1.Cluster) {
+	if !cluster.IsQuorumInstancesCheckApplicable() {
+		meta.RemoveStatusCondition(&cluster.Status.Conditions, string(apiv1.ConditionQuorumAtRisk))
+		return
+	}
+
+	condition := metav1.Condition{
+		Type:   string(apiv1.ConditionQuorumAtRisk),
+		Status: metav1.ConditionFalse,
+		Reason: string(apiv1.ConditionReasonOddInstances),
+		Message: fmt.Sprintf("The %d instances give a clear majority to the quorum",
+			cluster.Spec.Instances),
+	}
+	if cluster.Spec.Instances%2 == 0 {
+		condition = metav1.Condition{
+			Type:   string(apiv1.ConditionQuorumAtRisk),
+			Status: metav1.ConditionTrue,
+			Reason: string(apiv1.ConditionReasonEvenInstances),
+			Message: fmt.Sprintf("An even number of instances doesn't give a clear quorum with "+
+				"synchronous replication, %d instances are recommended", cluster.Spec.Instances+1),
+		}
+	}
+
+	meta.SetStatusCondition(&cluster.Status.Conditions, condition)
+}
+
 // RegisterPhase update phase in the status cluster with the
 // proper reason
 func (r *ClusterReconciler) RegisterPhase(ctx context.Context,
@@ -766,14 +1106,65 @@ func (r *ClusterReconciler) updateClusterStatusThatRequiresInstancesState(
 	existingClusterStatus := cluster.Status
 	cluster.Status.InstancesReportedState = make(map[apiv1.PodName]apiv1.InstanceReportedState, len(statuses.Items))
 
+	var primaryTimeline int
+	for _, item := range statuses.Items {
+		if item.IsPrimary && item.Error == nil {
+			primaryTimeline = item.TimeLineID
+		}
+	}
+
 	// we extract the instances reported state
+	now := time.Now()
+	syncStates := getReplicasSyncState(statuses)
 	for _, item := range statuses.Items {
-		cluster.Status.InstancesReportedState[apiv1.PodName(item.Pod.Name)] = apiv1.InstanceReportedState{
-			IsPrimary:  item.IsPrimary,
-			TimeLineID: item.TimeLineID,
+		podName := apiv1.PodName(item.Pod.Name)
+		previousState := existingClusterStatus.InstancesReportedState[podName]
+		checksumFailuresBaseline := refreshChecksumFailuresBaseline(previousState.ChecksumFailuresBaseline, item)
+		if checksumFailuresBaseline != nil {
+			item.ChecksumFailuresBaseline = *checksumFailuresBaseline
+		}
+		cluster.Status.InstancesReportedState[podName] = apiv1.InstanceReportedState{
+			IsPrimary:          item.IsPrimary,
+			TimeLineID:         item.TimeLineID,
+			BarmanCloudVersion: item.BarmanCloudVersion,
+			LastResyncMethod:   refreshLastResyncMethod(previousState.LastResyncMethod, item),
+			PendingWALFiles:    item.ReadyWALFiles,
+			ResourceUsage: refreshInstanceResourceUsage(
+				previousState.ResourceUsage,
+				item.ResourceUsage,
+				now),
+			TimelineDivergedSince: refreshTimelineDivergedSince(
+				previousState.TimelineDivergedSince,
+				item,
+				primaryTimeline,
+				now),
+			ChecksumFailuresBaseline: checksumFailuresBaseline,
+			CorruptedSince: refreshCorruptedSince(
+				previousState.CorruptedSince,
+				item,
+				now),
+			SyncState: syncStates[item.Pod.Name],
+		}
+		if previousState.CorruptedSince == "" &&
+			cluster.Status.InstancesReportedState[podName].CorruptedSince != "" {
+			message := fmt.Sprintf("Replica %v reported %v new checksum failures, removing it from the services",
+				item.Pod.Name, item.ChecksumFailures-item.ChecksumFailuresBaseline)
+			if cluster.ShouldRebuildCorruptedReplicas() {
+				message += fmt.Sprintf(" and rebuilding it in %v", cluster.GetCorruptedReplicaRebuildGracePeriod())
+			}
+			r.Recorder.Event(cluster, "Warning", "CorruptedReplica", message)
 		}
 	}
 
+	// the instances which don't exist anymore can't be resynchronized
+	var resynchronizingInstances []string
+	for _, instanceName := range cluster.Status.ResynchronizingInstances {
+		if slices.Contains(cluster.Status.InstanceNames, instanceName) {
+			resynchronizingInstances = append(resynchronizingInstances, instanceName)
+		}
+	}
+	cluster.Status.ResynchronizingInstances = resynchronizingInstances
+
 	// we update any relevant cluster status that depends on the primary instance
 	for _, item := range statuses.Items {
 		// we refresh the last known timeline on the status root.
@@ -781,14 +1172,86 @@ func (r *ClusterReconciler) updateClusterStatusThatRequiresInstancesState(
 		if item.IsPrimary && item.TimeLineID != 0 {
 			cluster.Status.TimelineID = item.TimeLineID
 		}
+		if item.IsPrimary && item.InitDBSettings != nil {
+			cluster.Status.InitDBSettings = &apiv1.InitDBSettings{
+				DataChecksums:  item.InitDBSettings.DataChecksums,
+				Encoding:       item.InitDBSettings.Encoding,
+				LocaleCollate:  item.InitDBSettings.LocaleCollate,
+				LocaleCType:    item.InitDBSettings.LocaleCType,
+				WalSegmentSize: item.InitDBSettings.WalSegmentSize,
+			}
+		}
 	}
 
+	setConfigurationDriftCondition(cluster, statuses)
+	setWALArchivingBacklogCondition(cluster, statuses, configuration.Current.GetPendingWALArchiveThreshold())
+	setMajorVersionMismatchCondition(cluster, statuses)
+	setReplicasUnavailableCondition(cluster, statuses)
+	setReadOnlyCondition(cluster, statuses)
+	setWALKeepSizeCondition(cluster)
+	setQuorumAtRiskCondition(cluster)
+	setPrimaryTransitionCompletedCondition(cluster)
+	setFailoverCooldownCondition(cluster, time.Now())
+
 	if !reflect.DeepEqual(existingClusterStatus, cluster.Status) {
 		return r.Status().Update(ctx, cluster)
 	}
 	return nil
 }
 
+// refreshInstanceResourceUsage gets the resource usage to be stored in the
+// status for an instance. The previous one is kept until it's older than
+// resourceUsageRefreshInterval, as every update of the status triggers
+// a new reconciliation loop, and the usage changes at every request
+func refreshInstanceResourceUsage(
+	previous *apiv1.InstanceResourceUsage,
+	reported *postgres.ResourceUsage,
+	now time.Time,
+) *apiv1.InstanceResourceUsage {
+	if reported == nil {
+		return previous
+	}
+
+	if previous != nil {
+		lastUpdateTime, err := time.Parse(time.RFC3339, previous.LastUpdateTime)
+		if err == nil && now.Sub(lastUpdateTime) < resourceUsageRefreshInterval {
+			return previous
+		}
+	}
+
+	// The memory is rounded to MiB, which is enough for
+	// the tuning and keeps the value readable
+	const mebibyte = 1024 * 1024
+	memory := reported.MemoryBytes / mebibyte * mebibyte
+	usage := &apiv1.InstanceResourceUsage{
+		Memory:         resource.NewQuantity(memory, resource.BinarySI).String(),
+		LastUpdateTime: now.Format(time.RFC3339),
+	}
+	if reported.CPUMillicores != nil {
+		usage.CPU = resource.NewMilliQuantity(*reported.CPUMillicores, resource.DecimalSI).String()
+	}
+
+	return usage
+}
+
+// getReplicasSyncState gets the synchronous state of every replica streaming
+// from the primary, as reported by its pg_stat_replication view, keyed by the
+// name of the replica Pod, which is used as application name
+func getReplicasSyncState(statuses postgres.PostgresqlStatusList) map[string]string {
+	result := make(map[string]string)
+	for _, item := range statuses.Items {
+		if !item.IsPrimary || item.Error != nil {
+			continue
+		}
+
+		for _, replication := range item.ReplicationInfo {
+			result[replication.ApplicationName] = replication.SyncState
+		}
+	}
+
+	return result
+}
+
 // rawInstanceStatusRequest retrieves the status of PostgreSQL pods via an HTTP request with GET method.
 func rawInstanceStatusRequest(
 	ctx context.Context,
@@ -862,3 +1325,13 @@ func getPodsTopology(
 
 	return apiv1.Topology{SuccessfullyExtracted: true, Instances: data}
 }
+
+// refreshLastResyncMethod gets the method used the last time an instance
+// was resynchronized with the new primary. The instance manager keeps it
+// only in memory, so the previous value is kept when it isn't reported
+func refreshLastResyncMethod(previous string, status postgres.PostgresqlStatus) string {
+	if status.LastResyncMethod != "" {
+		return status.LastResyncMethod
+	}
+	return previous
+}
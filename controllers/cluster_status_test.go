@@ -18,15 +18,22 @@ package controllers
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/utils/pointer"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 
 	v1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/certs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -162,3 +169,379 @@ var _ = Describe("cluster_status unit tests", func() {
 		})
 	})
 })
+
+var _ = Describe("configuration drift condition", func() {
+	It("reports the instances overriding the parameters managed by the operator", func() {
+		cluster := &v1.Cluster{}
+		statuses := postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				{Pod: corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-1"}}},
+				{
+					Pod:                corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-2"}},
+					ConfigurationDrift: []string{"max_connections", "work_mem"},
+				},
+			},
+		}
+
+		setConfigurationDriftCondition(cluster, statuses)
+		condition := meta.FindStatusCondition(cluster.Status.Conditions, string(v1.ConditionConfigurationDrift))
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Message).To(ContainSubstring("cluster-example-2 (max_connections, work_mem)"))
+		Expect(condition.Message).ToNot(ContainSubstring("cluster-example-1"))
+
+		statuses.Items[1].ConfigurationDrift = nil
+		setConfigurationDriftCondition(cluster, statuses)
+		condition = meta.FindStatusCondition(cluster.Status.Conditions, string(v1.ConditionConfigurationDrift))
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+	})
+})
+
+var _ = Describe("major version mismatch condition", func() {
+	It("reports the instances running different PostgreSQL major versions", func() {
+		cluster := &v1.Cluster{}
+		statuses := postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				{
+					Pod:                  corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-1"}},
+					IsPrimary:            true,
+					PostgresMajorVersion: 15,
+				},
+				{
+					Pod:                  corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-2"}},
+					PostgresMajorVersion: 16,
+				},
+			},
+		}
+
+		setMajorVersionMismatchCondition(cluster, statuses)
+		condition := meta.FindStatusCondition(cluster.Status.Conditions, string(v1.ConditionMajorVersionMismatch))
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Message).To(ContainSubstring("cluster-example-1 (15), cluster-example-2 (16)"))
+		Expect(condition.Message).To(ContainSubstring("the major version of the primary (15)"))
+
+		statuses.Items[1].PostgresMajorVersion = 15
+		setMajorVersionMismatchCondition(cluster, statuses)
+		condition = meta.FindStatusCondition(cluster.Status.Conditions, string(v1.ConditionMajorVersionMismatch))
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+	})
+
+	It("ignores the instances not reporting their major version", func() {
+		cluster := &v1.Cluster{}
+		statuses := postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				{Pod: corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-1"}}},
+				{Pod: corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-2"}}},
+			},
+		}
+
+		setMajorVersionMismatchCondition(cluster, statuses)
+		Expect(cluster.Status.Conditions).To(BeEmpty())
+	})
+})
+
+var _ = Describe("WAL archiving backlog condition", func() {
+	It("reports the instances with too many WAL files waiting to be archived", func() {
+		cluster := &v1.Cluster{}
+		statuses := postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				{
+					Pod:           corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-1"}},
+					ReadyWALFiles: 10,
+				},
+				{
+					Pod:           corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-2"}},
+					ReadyWALFiles: 200,
+				},
+			},
+		}
+
+		setWALArchivingBacklogCondition(cluster, statuses, 100)
+		condition := meta.FindStatusCondition(cluster.Status.Conditions, string(v1.ConditionWALArchivingBacklog))
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Message).To(ContainSubstring("cluster-example-2 (200)"))
+		Expect(condition.Message).ToNot(ContainSubstring("cluster-example-1"))
+
+		statuses.Items[1].ReadyWALFiles = 100
+		setWALArchivingBacklogCondition(cluster, statuses, 100)
+		condition = meta.FindStatusCondition(cluster.Status.Conditions, string(v1.ConditionWALArchivingBacklog))
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+	})
+})
+
+var _ = Describe("instance resource usage", func() {
+	now := time.Date(2023, 6, 1, 10, 0, 0, 0, time.UTC)
+
+	It("formats the usage reported by the instance", func() {
+		usage := refreshInstanceResourceUsage(nil, &postgres.ResourceUsage{
+			CPUMillicores: pointer.Int64(250),
+			MemoryBytes:   512*1024*1024 + 1234,
+		}, now)
+		Expect(usage).To(Equal(&v1.InstanceResourceUsage{
+			CPU:            "250m",
+			Memory:         "512Mi",
+			LastUpdateTime: now.Format(time.RFC3339),
+		}))
+	})
+
+	It("doesn't report the CPU usage until it is available", func() {
+		usage := refreshInstanceResourceUsage(nil, &postgres.ResourceUsage{MemoryBytes: 1024 * 1024}, now)
+		Expect(usage.CPU).To(BeEmpty())
+		Expect(usage.Memory).To(Equal("1Mi"))
+	})
+
+	It("keeps the previous usage until the refresh interval elapses", func() {
+		previous := &v1.InstanceResourceUsage{
+			CPU:            "100m",
+			Memory:         "256Mi",
+			LastUpdateTime: now.Add(-30 * time.Second).Format(time.RFC3339),
+		}
+		reported := &postgres.ResourceUsage{CPUMillicores: pointer.Int64(2000), MemoryBytes: 1024 * 1024 * 1024}
+
+		Expect(refreshInstanceResourceUsage(previous, reported, now)).To(BeIdenticalTo(previous))
+		Expect(refreshInstanceResourceUsage(previous, nil, now)).To(BeIdenticalTo(previous))
+
+		usage := refreshInstanceResourceUsage(previous, reported, now.Add(time.Minute))
+		Expect(usage.CPU).To(Equal("2"))
+		Expect(usage.Memory).To(Equal("1Gi"))
+	})
+})
+
+var _ = Describe("replicas synchronous state", func() {
+	It("reads the synchronous state of the replicas from the primary", func() {
+		statuses := postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				{
+					Pod:       corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-1"}},
+					IsPrimary: true,
+					ReplicationInfo: postgres.PgStatReplicationList{
+						{ApplicationName: "cluster-example-2", SyncState: "sync"},
+						{ApplicationName: "cluster-example-3", SyncState: "potential"},
+					},
+				},
+				{Pod: corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-2"}}},
+				{Pod: corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-3"}}},
+				{Pod: corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-4"}}},
+			},
+		}
+
+		syncStates := getReplicasSyncState(statuses)
+		Expect(syncStates).To(Equal(map[string]string{
+			"cluster-example-2": "sync",
+			"cluster-example-3": "potential",
+		}))
+		Expect(syncStates["cluster-example-1"]).To(BeEmpty())
+		Expect(syncStates["cluster-example-4"]).To(BeEmpty())
+	})
+
+	It("is empty when the primary status is not available", func() {
+		statuses := postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				{
+					Pod:       corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-1"}},
+					IsPrimary: true,
+					Error:     fmt.Errorf("connection refused"),
+					ReplicationInfo: postgres.PgStatReplicationList{
+						{ApplicationName: "cluster-example-2", SyncState: "sync"},
+					},
+				},
+			},
+		}
+
+		Expect(getReplicasSyncState(statuses)).To(BeEmpty())
+	})
+})
+
+var _ = Describe("replicas unavailable condition", func() {
+	var cluster *v1.Cluster
+	var statuses postgres.PostgresqlStatusList
+
+	BeforeEach(func() {
+		cluster = &v1.Cluster{
+			Spec: v1.ClusterSpec{
+				Instances:       3,
+				MinSyncReplicas: 1,
+				MaxSyncReplicas: 1,
+			},
+			Status: v1.ClusterStatus{
+				CurrentPrimary: "cluster-example-1",
+				TargetPrimary:  "cluster-example-1",
+			},
+		}
+		statuses = postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				{
+					Pod:        corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-1"}},
+					IsPrimary:  true,
+					IsPodReady: true,
+				},
+				{
+					Pod:   corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-2"}},
+					Error: fmt.Errorf("connection refused"),
+				},
+				{
+					Pod:   corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-3"}},
+					Error: fmt.Errorf("connection refused"),
+				},
+			},
+		}
+	})
+
+	It("reports the primary as degraded when every replica is down", func() {
+		setReplicasUnavailableCondition(cluster, statuses)
+		condition := meta.FindStatusCondition(cluster.Status.Conditions, string(v1.ConditionReplicasUnavailable))
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Reason).To(Equal(string(v1.ConditionReasonAllReplicasUnavailable)))
+		Expect(condition.Message).To(ContainSubstring("synchronous replication is suspended"))
+	})
+
+	It("reports the commits waiting for the standbys with the enforceSync policy", func() {
+		cluster.Spec.ReplicasUnavailablePolicy = v1.ReplicasUnavailablePolicyEnforceSync
+		setReplicasUnavailableCondition(cluster, statuses)
+		condition := meta.FindStatusCondition(cluster.Status.Conditions, string(v1.ConditionReplicasUnavailable))
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Message).To(ContainSubstring("the commits wait for 1 synchronous standbys"))
+	})
+
+	It("clears the condition when a replica is back", func() {
+		setReplicasUnavailableCondition(cluster, statuses)
+
+		statuses.Items[1].Error = nil
+		statuses.Items[1].IsPodReady = true
+		setReplicasUnavailableCondition(cluster, statuses)
+		condition := meta.FindStatusCondition(cluster.Status.Conditions, string(v1.ConditionReplicasUnavailable))
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal(string(v1.ConditionReasonReplicasAvailable)))
+	})
+})
+
+var _ = Describe("read-only condition", func() {
+	var (
+		cluster  *v1.Cluster
+		statuses postgres.PostgresqlStatusList
+	)
+
+	BeforeEach(func() {
+		cluster = &v1.Cluster{}
+		statuses = postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				{
+					Pod:       corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-1"}},
+					IsPrimary: true,
+				},
+				{
+					Pod: corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-2"}},
+				},
+			},
+		}
+	})
+
+	It("reports a primary accepting the writes", func() {
+		setReadOnlyCondition(cluster, statuses)
+		condition := meta.FindStatusCondition(cluster.Status.Conditions, string(v1.ConditionReadOnly))
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal(string(v1.ConditionReasonReadWrite)))
+	})
+
+	It("waits for the primary to apply the read-only mode", func() {
+		cluster.Spec.ReadOnly = &v1.ReadOnlyConfiguration{Enabled: true}
+		setReadOnlyCondition(cluster, statuses)
+		condition := meta.FindStatusCondition(cluster.Status.Conditions, string(v1.ConditionReadOnly))
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal(string(v1.ConditionReasonReadOnlyRequested)))
+
+		statuses.Items[0].IsReadOnlyDefault = true
+		setReadOnlyCondition(cluster, statuses)
+		condition = meta.FindStatusCondition(cluster.Status.Conditions, string(v1.ConditionReadOnly))
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Reason).To(Equal(string(v1.ConditionReasonReadOnlyEnabled)))
+	})
+
+	It("waits for the primary to accept the writes again", func() {
+		statuses.Items[0].IsReadOnlyDefault = true
+		setReadOnlyCondition(cluster, statuses)
+		condition := meta.FindStatusCondition(cluster.Status.Conditions, string(v1.ConditionReadOnly))
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Reason).To(Equal(string(v1.ConditionReasonReadWriteRequested)))
+	})
+
+	It("reports a primary made read-only by the PostgreSQL parameters", func() {
+		cluster.Spec.PostgresConfiguration.Parameters = map[string]string{
+			"default_transaction_read_only": "on",
+		}
+		statuses.Items[0].IsReadOnlyDefault = true
+		setReadOnlyCondition(cluster, statuses)
+		condition := meta.FindStatusCondition(cluster.Status.Conditions, string(v1.ConditionReadOnly))
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Reason).To(Equal(string(v1.ConditionReasonReadOnlyEnabled)))
+	})
+
+	It("doesn't change the condition without a reachable primary", func() {
+		statuses.Items[0].Error = fmt.Errorf("connection refused")
+		setReadOnlyCondition(cluster, statuses)
+		Expect(meta.FindStatusCondition(cluster.Status.Conditions, string(v1.ConditionReadOnly))).To(BeNil())
+	})
+})
+
+var _ = Describe("WAL keep size condition", func() {
+	It("reports when the WAL files kept for the standbys may fill the WAL volume", func() {
+		walKeepSize := resource.MustParse("6Gi")
+		cluster := &v1.Cluster{
+			Spec: v1.ClusterSpec{
+				WalKeepSize:          &walKeepSize,
+				StorageConfiguration: v1.StorageConfiguration{Size: "10Gi"},
+			},
+		}
+
+		setWALKeepSizeCondition(cluster)
+		condition := meta.FindStatusCondition(cluster.Status.Conditions, string(v1.ConditionWALKeepSizeAtRisk))
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+
+		cluster.Spec.WalStorage = &v1.StorageConfiguration{Size: "20Gi"}
+		setWALKeepSizeCondition(cluster)
+		condition = meta.FindStatusCondition(cluster.Status.Conditions, string(v1.ConditionWALKeepSizeAtRisk))
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+
+		cluster.Spec.WalKeepSize = nil
+		setWALKeepSizeCondition(cluster)
+		Expect(meta.FindStatusCondition(cluster.Status.Conditions, string(v1.ConditionWALKeepSizeAtRisk))).To(BeNil())
+	})
+})
+
+var _ = Describe("quorum at risk condition", func() {
+	It("reports an even number of instances with synchronous replication", func() {
+		cluster := &v1.Cluster{
+			Spec: v1.ClusterSpec{Instances: 4, MaxSyncReplicas: 1},
+		}
+
+		setQuorumAtRiskCondition(cluster)
+		condition := meta.FindStatusCondition(cluster.Status.Conditions, string(v1.ConditionQuorumAtRisk))
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+
+		cluster.Spec.Instances = 5
+		setQuorumAtRiskCondition(cluster)
+		condition = meta.FindStatusCondition(cluster.Status.Conditions, string(v1.ConditionQuorumAtRisk))
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+
+		cluster.Spec.MaxSyncReplicas = 0
+		setQuorumAtRiskCondition(cluster)
+		Expect(meta.FindStatusCondition(cluster.Status.Conditions, string(v1.ConditionQuorumAtRisk))).To(BeNil())
+	})
+})
+
+var _ = Describe("last resync method", func() {
+	It("keeps the previous method when the instance doesn't report it", func() {
+		Expect(refreshLastResyncMethod("", postgres.PostgresqlStatus{})).To(BeEmpty())
+		Expect(refreshLastResyncMethod("pg_rewind", postgres.PostgresqlStatus{})).To(Equal("pg_rewind"))
+		Expect(refreshLastResyncMethod("pg_rewind", postgres.PostgresqlStatus{LastResyncMethod: "pg_basebackup"})).
+			To(Equal("pg_basebackup"))
+	})
+})
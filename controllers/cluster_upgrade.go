@@ -24,9 +24,13 @@ import (
 	"net/http"
 	neturl "net/url"
 	"reflect"
+	"strings"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
@@ -124,6 +128,89 @@ func (r *ClusterReconciler) rolloutDueToCondition(
 	return r.updatePrimaryPod(ctx, cluster, podList, primaryPostgresqlStatus.Pod, inPlacePossible, reason)
 }
 
+// deferRolloutToMaintenanceWindow checks whether the instances needing a
+// rolling update should wait for the next maintenance window, updating the
+// PendingMaintenance condition accordingly. It returns the time to wait
+// before the window opens, or zero when the rolling update can proceed
+func (r *ClusterReconciler) deferRolloutToMaintenanceWindow(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	instancesStatus postgres.PostgresqlStatusList,
+) (time.Duration, error) {
+	existingClusterStatus := cluster.Status.DeepCopy()
+
+	wait, condition, err := getPendingMaintenanceCondition(cluster, instancesStatus, time.Now())
+	if err != nil {
+		return 0, err
+	}
+	if condition == nil {
+		meta.RemoveStatusCondition(&cluster.Status.Conditions, string(apiv1.ConditionPendingMaintenance))
+	} else {
+		meta.SetStatusCondition(&cluster.Status.Conditions, *condition)
+	}
+
+	if !reflect.DeepEqual(existingClusterStatus.Conditions, cluster.Status.Conditions) {
+		if err := r.Status().Update(ctx, cluster); err != nil {
+			return 0, err
+		}
+	}
+
+	return wait, nil
+}
+
+// getPendingMaintenanceCondition computes the PendingMaintenance condition
+// of a cluster at the given time, and the time to wait before the next
+// maintenance window opens when a rolling update needs to be deferred.
+// No condition is returned when the cluster has no maintenance window
+func getPendingMaintenanceCondition(
+	cluster *apiv1.Cluster,
+	instancesStatus postgres.PostgresqlStatusList,
+	now time.Time,
+) (time.Duration, *metav1.Condition, error) {
+	window := cluster.Spec.MaintenanceWindow
+	if window == nil {
+		return 0, nil, nil
+	}
+
+	var pending []string
+	for _, item := range instancesStatus.Items {
+		if needsRollout, _, reason := IsPodNeedingRollout(item, cluster); needsRollout {
+			pending = append(pending, fmt.Sprintf("%s (%s)", item.Pod.Name, reason))
+		}
+	}
+
+	noPendingMaintenance := &metav1.Condition{
+		Type:    string(apiv1.ConditionPendingMaintenance),
+		Status:  metav1.ConditionFalse,
+		Reason:  string(apiv1.ConditionReasonNoPendingMaintenance),
+		Message: "No rolling update is waiting for the maintenance window",
+	}
+	if len(pending) == 0 || utils.IsUrgentRolloutRequested(&cluster.ObjectMeta) {
+		return 0, noPendingMaintenance, nil
+	}
+
+	isOpen, err := window.IsOpen(now)
+	if err != nil {
+		return 0, nil, err
+	}
+	if isOpen {
+		return 0, noPendingMaintenance, nil
+	}
+
+	nextOpening, err := window.GetNextOpening(now)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return nextOpening.Sub(now), &metav1.Condition{
+		Type:   string(apiv1.ConditionPendingMaintenance),
+		Status: metav1.ConditionTrue,
+		Reason: string(apiv1.ConditionReasonRolloutDeferred),
+		Message: fmt.Sprintf("Rolling update deferred to the maintenance window starting at %s: %s",
+			nextOpening.Format(time.RFC3339), strings.Join(pending, "; ")),
+	}, nil
+}
+
 func (r *ClusterReconciler) updatePrimaryPod(
 	ctx context.Context,
 	cluster *apiv1.Cluster,
@@ -169,8 +256,10 @@ func (r *ClusterReconciler) updatePrimaryPod(
 		return err == nil, err
 	}
 
-	// if the cluster has more than one instance, we should trigger a switchover before upgrading
-	if cluster.Status.Instances > 1 && len(podList.Items) > 1 {
+	// if the cluster has more than one instance, we should trigger a switchover before upgrading.
+	// Read replicas are sorted after the other instances and are never promoted: when
+	// the first replica is one of them, the primary is upgraded as if it were alone
+	if cluster.Status.Instances > 1 && len(podList.Items) > 1 && !podList.Items[1].IsReadReplica() {
 		// If this is not a replica cluster, podList.Items[1] is the first replica,
 		// as the pod list is sorted in the same order we use for switchover / failover.
 		// This may not be true for replica clusters, where every instance is a replica
@@ -272,6 +361,11 @@ func IsPodNeedingRollout(status postgres.PostgresqlStatus, cluster *apiv1.Cluste
 		return true, false, reason
 	}
 
+	// Check if there is a change in the volume containing the object store credentials
+	if needsUpdate, reason := isPodNeedingUpdateOfCredentialsVolume(cluster, status.Pod); needsUpdate {
+		return true, false, reason
+	}
+
 	// check if the pod requires an image upgrade
 	oldImage, newImage, err := isPodNeedingUpgradedImage(cluster, status.Pod)
 	if err != nil {
@@ -308,6 +402,23 @@ func IsPodNeedingRollout(status postgres.PostgresqlStatus, cluster *apiv1.Cluste
 		return true, false, reason
 	}
 
+	// Check if the sidecars, or their volumes, have been changed
+	if restartRequired, reason := isPodNeedingUpdatedSidecars(*cluster, status.Pod); restartRequired {
+		return true, false, reason
+	}
+
+	// Check if there is a change in the configuration managed by the operator.
+	// The Pod needs to be recreated to get the new hash annotation
+	if restartRequired, reason := isPodNeedingUpdatedConfiguration(*cluster, status.Pod); restartRequired {
+		return true, false, reason
+	}
+
+	// Check if the content of the mounted secrets and config maps which
+	// can't be reloaded has been changed
+	if restartRequired, reason := isPodNeedingUpdatedMountedObjects(*cluster, status.Pod); restartRequired {
+		return true, false, reason
+	}
+
 	// Detect changes in the postgres container configuration
 	for _, container := range status.Pod.Spec.Containers {
 		// we go to the next array element if it isn't the postgres container
@@ -358,6 +469,26 @@ func getProjectedVolumeConfigurationFromPod(pod corev1.Pod) *corev1.ProjectedVol
 	return nil
 }
 
+// isPodNeedingUpdateOfCredentialsVolume checks whether the CSI volume
+// containing the credentials of the object store has been changed
+func isPodNeedingUpdateOfCredentialsVolume(cluster *apiv1.Cluster, pod corev1.Pod) (needsUpdate bool, reason string) {
+	var currentCredentialsVolume *corev1.CSIVolumeSource
+	for _, volume := range pod.Spec.Volumes {
+		if volume.Name == specs.ObjectStoreCredentialsVolumeName {
+			currentCredentialsVolume = volume.CSI
+		}
+	}
+
+	desiredCredentialsVolume := cluster.GetObjectStoreCredentialsVolume()
+	if reflect.DeepEqual(currentCredentialsVolume, desiredCredentialsVolume) {
+		return false, ""
+	}
+
+	return true, fmt.Sprintf("object store credentials volume changed, old: %+v, new: %+v",
+		currentCredentialsVolume,
+		desiredCredentialsVolume)
+}
+
 // isPodNeedingUpgradedImage checks whether an image in a pod has to be changed
 func isPodNeedingUpgradedImage(
 	cluster *apiv1.Cluster,
@@ -459,6 +590,59 @@ func isPodNeedingUpdatedEnvironment(cluster apiv1.Cluster, pod corev1.Pod) (bool
 	return false, ""
 }
 
+// isPodNeedingUpdatedSidecars checks whether the Pod is running with
+// outdated sidecars. The Pods created before the hash annotation was
+// introduced need to be recreated only when the cluster has sidecars
+func isPodNeedingUpdatedSidecars(cluster apiv1.Cluster, pod corev1.Pod) (bool, string) {
+	podSidecarsHash, hasPodSidecarsHash := pod.Annotations[utils.PodSidecarsHashAnnotationName]
+	if !hasPodSidecarsHash {
+		if len(cluster.Spec.Sidecars) == 0 && len(cluster.Spec.SidecarVolumes) == 0 {
+			return false, ""
+		}
+		return true, "sidecars added"
+	}
+
+	if podSidecarsHash != specs.ComputeSidecarsHash(cluster) {
+		return true, "sidecars configuration hash changed"
+	}
+
+	return false, ""
+}
+
+// isPodNeedingUpdatedConfiguration checks whether the Pod is running with
+// an outdated configuration, when the cluster requires the instances to be
+// restarted on configuration changes. The Pods created before the hash
+// annotation was introduced are never restarted
+func isPodNeedingUpdatedConfiguration(cluster apiv1.Cluster, pod corev1.Pod) (bool, string) {
+	if !cluster.Spec.RestartOnConfigurationChange {
+		return false, ""
+	}
+
+	podConfigHash, hasPodConfigHash := pod.Annotations[utils.PodConfigHashAnnotationName]
+	if !hasPodConfigHash || podConfigHash == specs.ComputeConfigurationHash(cluster) {
+		return false, ""
+	}
+
+	return true, "configuration hash changed"
+}
+
+// isPodNeedingUpdatedMountedObjects checks whether the Pod has been created
+// with an outdated content of the mounted secrets and config maps whose
+// changes require a restart. The Pods without the hash annotation get it
+// without being restarted, as they are running with the current content
+func isPodNeedingUpdatedMountedObjects(cluster apiv1.Cluster, pod corev1.Pod) (bool, string) {
+	if cluster.Status.MountedObjectsHash == "" {
+		return false, ""
+	}
+
+	podHash, hasPodHash := pod.Annotations[utils.PodMountedObjectsHashAnnotationName]
+	if !hasPodHash || podHash == cluster.Status.MountedObjectsHash {
+		return false, ""
+	}
+
+	return true, "mounted secrets or config maps changed"
+}
+
 // upgradePod updates an instance to a newer image version
 func (r *ClusterReconciler) upgradePod(ctx context.Context, cluster *apiv1.Cluster, pod *corev1.Pod) error {
 	log.FromContext(ctx).Info("Deleting old Pod",
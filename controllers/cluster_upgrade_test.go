@@ -17,11 +17,15 @@ limitations under the License.
 package controllers
 
 import (
+	"time"
+
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -109,4 +113,193 @@ var _ = Describe("Pod upgrade", func() {
 			Expect(needRollout).To(BeTrue())
 		})
 	})
+
+	When("the instances are restarted on configuration changes", func() {
+		cluster := cluster.DeepCopy()
+		cluster.Spec.RestartOnConfigurationChange = true
+		cluster.Spec.PostgresConfiguration.PgHBA = []string{"host all all 10.0.0.0/8 md5"}
+
+		It("doesn't restart the Pods running with the same configuration", func() {
+			pod := specs.PodWithExistingStorage(*cluster, 1)
+			needRollout, _ := isPodNeedingUpdatedConfiguration(*cluster, *pod)
+			Expect(needRollout).To(BeFalse())
+
+			sameCluster := cluster.DeepCopy()
+			sameCluster.Spec.PostgresConfiguration.PgHBA = []string{"host all all 10.0.0.0/8 md5"}
+			needRollout, _ = isPodNeedingUpdatedConfiguration(*sameCluster, *pod)
+			Expect(needRollout).To(BeFalse())
+		})
+
+		It("restarts the Pods running with an outdated configuration", func() {
+			pod := specs.PodWithExistingStorage(*cluster, 1)
+
+			changedCluster := cluster.DeepCopy()
+			changedCluster.Spec.PostgresConfiguration.PgHBA = []string{"host all all 10.0.0.0/8 scram-sha-256"}
+			needRollout, reason := isPodNeedingUpdatedConfiguration(*changedCluster, *pod)
+			Expect(needRollout).To(BeTrue())
+			Expect(reason).To(Equal("configuration hash changed"))
+
+			changedCluster.Spec.RestartOnConfigurationChange = false
+			needRollout, _ = isPodNeedingUpdatedConfiguration(*changedCluster, *pod)
+			Expect(needRollout).To(BeFalse())
+		})
+
+		It("doesn't restart the Pods without the configuration hash", func() {
+			pod := specs.PodWithExistingStorage(*cluster, 1)
+			delete(pod.Annotations, utils.PodConfigHashAnnotationName)
+
+			changedCluster := cluster.DeepCopy()
+			changedCluster.Spec.PostgresConfiguration.Parameters = map[string]string{"work_mem": "8MB"}
+			needRollout, _ := isPodNeedingUpdatedConfiguration(*changedCluster, *pod)
+			Expect(needRollout).To(BeFalse())
+		})
+	})
+
+	When("the mounted objects are changed", func() {
+		cluster := cluster.DeepCopy()
+		cluster.Status.MountedObjectsHash = "hash-1"
+
+		It("doesn't restart the Pods running with the same content", func() {
+			pod := specs.PodWithExistingStorage(*cluster, 1)
+			Expect(pod.Annotations).To(HaveKeyWithValue(utils.PodMountedObjectsHashAnnotationName, "hash-1"))
+			needRollout, _ := isPodNeedingUpdatedMountedObjects(*cluster, *pod)
+			Expect(needRollout).To(BeFalse())
+		})
+
+		It("restarts the Pods running with an outdated content", func() {
+			pod := specs.PodWithExistingStorage(*cluster, 1)
+
+			changedCluster := cluster.DeepCopy()
+			changedCluster.Status.MountedObjectsHash = "hash-2"
+			needRollout, reason := isPodNeedingUpdatedMountedObjects(*changedCluster, *pod)
+			Expect(needRollout).To(BeTrue())
+			Expect(reason).To(Equal("mounted secrets or config maps changed"))
+
+			changedCluster.Status.MountedObjectsHash = ""
+			needRollout, _ = isPodNeedingUpdatedMountedObjects(*changedCluster, *pod)
+			Expect(needRollout).To(BeFalse())
+		})
+
+		It("doesn't restart the Pods without the mounted objects hash", func() {
+			pod := specs.PodWithExistingStorage(apiv1.Cluster{}, 1)
+			Expect(pod.Annotations).ToNot(HaveKey(utils.PodMountedObjectsHashAnnotationName))
+			needRollout, _ := isPodNeedingUpdatedMountedObjects(*cluster, *pod)
+			Expect(needRollout).To(BeFalse())
+		})
+	})
+
+	When("the sidecars are changed", func() {
+		cluster := cluster.DeepCopy()
+		cluster.Spec.Sidecars = []corev1.Container{{Name: "log-agent", Image: "fluent-bit:2.1"}}
+
+		It("doesn't restart the Pods running with the same sidecars", func() {
+			pod := specs.PodWithExistingStorage(*cluster, 1)
+			needRollout, _ := isPodNeedingUpdatedSidecars(*cluster, *pod)
+			Expect(needRollout).To(BeFalse())
+		})
+
+		It("restarts the Pods running with outdated sidecars", func() {
+			pod := specs.PodWithExistingStorage(*cluster, 1)
+
+			changedCluster := cluster.DeepCopy()
+			changedCluster.Spec.Sidecars[0].Image = "fluent-bit:2.2"
+			needRollout, reason := isPodNeedingUpdatedSidecars(*changedCluster, *pod)
+			Expect(needRollout).To(BeTrue())
+			Expect(reason).To(Equal("sidecars configuration hash changed"))
+		})
+
+		It("restarts the Pods without the sidecars hash only when sidecars are added", func() {
+			pod := specs.PodWithExistingStorage(apiv1.Cluster{}, 1)
+			delete(pod.Annotations, utils.PodSidecarsHashAnnotationName)
+
+			needRollout, _ := isPodNeedingUpdatedSidecars(apiv1.Cluster{}, *pod)
+			Expect(needRollout).To(BeFalse())
+
+			needRollout, _ = isPodNeedingUpdatedSidecars(*cluster, *pod)
+			Expect(needRollout).To(BeTrue())
+		})
+	})
+
+	When("the object store credentials volume is changed", func() {
+		cluster := cluster.DeepCopy()
+		cluster.Spec.Backup = &apiv1.BackupConfiguration{
+			BarmanObjectStore: &apiv1.BarmanObjectStoreConfiguration{
+				BarmanCredentials: apiv1.BarmanCredentials{AWS: &apiv1.S3Credentials{}},
+				CredentialsVolume: &corev1.CSIVolumeSource{
+					Driver:           "secrets-store.csi.k8s.io",
+					VolumeAttributes: map[string]string{"secretProviderClass": "backup-credentials"},
+				},
+			},
+		}
+
+		It("doesn't restart the Pods mounting the same volume", func() {
+			pod := specs.PodWithExistingStorage(*cluster, 1)
+			needRollout, _ := isPodNeedingUpdateOfCredentialsVolume(cluster, *pod)
+			Expect(needRollout).To(BeFalse())
+		})
+
+		It("restarts the Pods mounting a different volume, or none", func() {
+			pod := specs.PodWithExistingStorage(*cluster, 1)
+
+			changedCluster := cluster.DeepCopy()
+			changedCluster.Spec.Backup.BarmanObjectStore.CredentialsVolume.VolumeAttributes["secretProviderClass"] =
+				"new-backup-credentials"
+			needRollout, _ := isPodNeedingUpdateOfCredentialsVolume(changedCluster, *pod)
+			Expect(needRollout).To(BeTrue())
+
+			pod = specs.PodWithExistingStorage(apiv1.Cluster{}, 1)
+			needRollout, _ = isPodNeedingUpdateOfCredentialsVolume(cluster, *pod)
+			Expect(needRollout).To(BeTrue())
+		})
+	})
+})
+
+var _ = Describe("maintenance window", func() {
+	// 2023-03-06 is a Monday
+	now := time.Date(2023, time.March, 6, 12, 0, 0, 0, time.UTC)
+	cluster := apiv1.Cluster{
+		Spec: apiv1.ClusterSpec{
+			ImageName: "postgres:13.0",
+			MaintenanceWindow: &apiv1.MaintenanceWindowConfiguration{
+				Windows: []apiv1.MaintenanceTimeRange{{Start: "02:00", End: "04:00"}},
+			},
+		},
+	}
+	pod := specs.PodWithExistingStorage(cluster, 1)
+	statuses := postgres.PostgresqlStatusList{
+		Items: []postgres.PostgresqlStatus{
+			{Pod: *pod, IsPodReady: true, ExecutableHash: "test_hash", PendingRestart: true},
+		},
+	}
+
+	It("defers the rolling updates outside of the maintenance window", func() {
+		wait, condition, err := getPendingMaintenanceCondition(&cluster, statuses, now)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(wait).To(Equal(14 * time.Hour))
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Message).To(ContainSubstring(pod.Name))
+	})
+
+	It("doesn't defer the rolling updates inside of the maintenance window", func() {
+		wait, condition, err := getPendingMaintenanceCondition(&cluster, statuses, now.Add(-9*time.Hour))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(wait).To(BeZero())
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+	})
+
+	It("doesn't defer urgent rolling updates", func() {
+		urgentCluster := cluster.DeepCopy()
+		urgentCluster.Annotations = map[string]string{"cnpg.io/urgentRollout": "enabled"}
+		wait, condition, err := getPendingMaintenanceCondition(urgentCluster, statuses, now)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(wait).To(BeZero())
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+	})
+
+	It("doesn't set the condition without a maintenance window", func() {
+		wait, condition, err := getPendingMaintenanceCondition(&apiv1.Cluster{}, statuses, now)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(wait).To(BeZero())
+		Expect(condition).To(BeNil())
+	})
 })
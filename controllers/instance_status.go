@@ -19,26 +19,37 @@ package controllers
 import (
 	"context"
 	"errors"
+	"fmt"
 	"net"
 	"net/http"
 	"sort"
+	"sync"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/util/retry"
 
+	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 )
 
+// maxParallelStatusRequests is the maximum number of instances whose
+// status is requested at the same time
+const maxParallelStatusRequests = 10
+
 type instanceStatusClient struct {
 	*http.Client
+
+	// requestTimeout bounds the time spent collecting the status of a
+	// single instance, retries included
+	requestTimeout time.Duration
 }
 
 func newInstanceStatusClient() *instanceStatusClient {
-	const connectionTimeout = 2 * time.Second
-	const requestTimeout = 30 * time.Second
+	connectionTimeout := configuration.Current.GetInstanceStatusConnectionTimeout()
+	requestTimeout := configuration.Current.GetInstanceStatusRequestTimeout()
 
 	// We want a connection timeout to prevent waiting for the default
 	// TCP connection timeout (30 seconds) on lost SYN packets
@@ -51,22 +62,39 @@ func newInstanceStatusClient() *instanceStatusClient {
 		Timeout: requestTimeout,
 	}
 
-	return &instanceStatusClient{timeoutClient}
+	return &instanceStatusClient{
+		Client:         timeoutClient,
+		requestTimeout: requestTimeout,
+	}
 }
 
 // extractInstancesStatus extracts the status of the underlying PostgreSQL instance from
 // the requested Pod, via the instance manager. In case of failure, errors are passed
-// in the result list
+// in the result list. The instances are queried in parallel, and the items of
+// the result list have the same order of the passed Pods
 func (r *instanceStatusClient) extractInstancesStatus(
 	ctx context.Context,
 	activePods []corev1.Pod,
 ) postgres.PostgresqlStatusList {
-	var result postgres.PostgresqlStatusList
+	result := postgres.PostgresqlStatusList{
+		Items: make([]postgres.PostgresqlStatus, len(activePods)),
+	}
 
+	semaphore := make(chan struct{}, maxParallelStatusRequests)
+	var waitGroup sync.WaitGroup
 	for idx := range activePods {
-		instanceStatus := r.getReplicaStatusFromPodViaHTTP(ctx, activePods[idx])
-		result.Items = append(result.Items, instanceStatus)
+		waitGroup.Add(1)
+		go func(podIndex int) {
+			defer waitGroup.Done()
+
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			result.Items[podIndex] = r.getReplicaStatusFromPodViaHTTP(ctx, activePods[podIndex])
+		}(idx)
 	}
+
+	waitGroup.Wait()
 	return result
 }
 
@@ -76,6 +104,14 @@ func (r *instanceStatusClient) getReplicaStatusFromPodViaHTTP(
 	ctx context.Context,
 	pod corev1.Pod,
 ) (result postgres.PostgresqlStatus) {
+	// A slow instance must not delay the collection of the
+	// status of the other ones for longer than the request timeout
+	if r.requestTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.requestTimeout)
+		defer cancel()
+	}
+
 	isErrorRetryable := func(err error) bool {
 		contextLog := log.FromContext(ctx)
 
@@ -105,6 +141,13 @@ func (r *instanceStatusClient) getReplicaStatusFromPodViaHTTP(
 		return result.Error
 	})
 
+	// A timeout means the instance is unresponsive, and we report it
+	// as such to make the reason of its unhealthy state clear
+	var netError net.Error
+	if errors.As(result.Error, &netError) && netError.Timeout() {
+		result.Error = fmt.Errorf("instance status request timed out: %w", result.Error)
+	}
+
 	result.AddPod(pod)
 
 	return result
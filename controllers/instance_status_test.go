@@ -0,0 +1,53 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("instance status collection", func() {
+	It("keeps the order of the pods when querying them in parallel", func() {
+		pods := make([]corev1.Pod, 2*maxParallelStatusRequests)
+		for idx := range pods {
+			pods[idx] = corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: fmt.Sprintf("cluster-example-%v", idx+1),
+				},
+			}
+		}
+
+		// A canceled context makes every request fail without
+		// reaching the network
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		status := newInstanceStatusClient().extractInstancesStatus(ctx, pods)
+		Expect(status.Items).To(HaveLen(len(pods)))
+		for idx := range pods {
+			Expect(status.Items[idx].Pod.Name).To(Equal(pods[idx].Name))
+			Expect(status.Items[idx].Error).To(HaveOccurred())
+		}
+	})
+})
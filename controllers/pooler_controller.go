@@ -33,6 +33,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
@@ -133,6 +134,11 @@ func (r *PoolerReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manage
 			handler.EnqueueRequestsFromMapFunc(r.mapSecretToPooler(ctx)),
 			builder.WithPredicates(secretsPoolerPredicate),
 		).
+		Watches(
+			&source.Kind{Type: &apiv1.Cluster{}},
+			handler.EnqueueRequestsFromMapFunc(r.mapClusterToPoolers(ctx)),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{}),
+		).
 		Complete(r)
 }
 
@@ -186,6 +192,36 @@ func (r *PoolerReconciler) mapSecretToPooler(ctx context.Context) handler.MapFun
 	}
 }
 
+// mapClusterToPoolers returns a function mapping cluster events to the poolers
+// pointing to them, given the name of the cluster services can change
+func (r *PoolerReconciler) mapClusterToPoolers(ctx context.Context) handler.MapFunc {
+	return func(obj client.Object) (result []reconcile.Request) {
+		cluster, ok := obj.(*apiv1.Cluster)
+		if !ok {
+			return nil
+		}
+
+		var poolers apiv1.PoolerList
+		if err := r.List(ctx, &poolers,
+			client.InNamespace(cluster.Namespace),
+			client.MatchingFields{poolerClusterKey: cluster.Name},
+		); err != nil {
+			log.FromContext(ctx).Error(err, "while getting pooler list for cluster",
+				"namespace", cluster.Namespace, "cluster", cluster.Name)
+			return nil
+		}
+
+		result = make([]reconcile.Request, len(poolers.Items))
+		for idx, pooler := range poolers.Items {
+			result[idx] = reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: pooler.Name, Namespace: pooler.Namespace},
+			}
+		}
+
+		return
+	}
+}
+
 // getPoolersUsingSecret get a list of poolers which are using the passed secret
 func getPoolersUsingSecret(poolers apiv1.PoolerList, secret *corev1.Secret) (requests []types.NamespacedName) {
 	for _, pooler := range poolers.Items {
@@ -31,7 +31,6 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs/pgbouncer"
-	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils/hash"
 )
 
 // updateOwnedObjects ensure that we have the required objects
@@ -87,7 +86,7 @@ func (r *PoolerReconciler) updateDeployment(
 
 	case resources.Deployment != nil:
 		currentVersion := resources.Deployment.Annotations[pgbouncer.PgbouncerPoolerSpecHash]
-		updatedVersion, err := hash.ComputeHash(pooler.Spec)
+		updatedVersion, err := pgbouncer.DeploymentHash(pooler, resources.Cluster)
 		if err != nil {
 			return err
 		}
@@ -19,9 +19,16 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strconv"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	"k8s.io/utils/strings/slices"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
@@ -32,6 +39,10 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 )
 
+// divergedTimelineGracePeriod is how long a replica can be stuck on a
+// timeline older than the primary one before being resynchronized
+const divergedTimelineGracePeriod = 5 * time.Minute
+
 // ErrWalReceiversRunning is raised when a new primary server can't be elected
 // because there is a WAL receiver running in our Pod list
 var ErrWalReceiversRunning = fmt.Errorf("wal receivers are still running")
@@ -40,6 +51,19 @@ var ErrWalReceiversRunning = fmt.Errorf("wal receivers are still running")
 // elapsed yet
 var ErrWaitingOnFailOverDelay = fmt.Errorf("current primary isn't healthy, waiting for the delay before triggering a failover") //nolint: lll
 
+// ErrWaitingForManualFailover is raised when the primary server is not healthy,
+// but a new one can't be elected because the automatic failover is disabled
+var ErrWaitingForManualFailover = fmt.Errorf("current primary isn't healthy and the automatic failover is disabled")
+
+// ErrWaitingOnFailoverCooldown is raised when the primary server is not healthy,
+// but a new one can't be elected because the last promotion happened less than
+// .spec.failoverCooldown seconds ago
+var ErrWaitingOnFailoverCooldown = fmt.Errorf("current primary isn't healthy, but the last promotion is too recent to trigger a failover") //nolint: lll
+
+// ErrNoFailoverCandidate is raised when the primary server is not healthy,
+// but every replica that could be elected applies the changes with a delay
+var ErrNoFailoverCandidate = fmt.Errorf("current primary isn't healthy and only delayed replicas are available")
+
 // updateTargetPrimaryFromPods sets the name of the target primary from the Pods status if needed
 // this function will return the name of the new primary selected for promotion
 func (r *ClusterReconciler) updateTargetPrimaryFromPods(
@@ -72,6 +96,15 @@ func (r *ClusterReconciler) updateTargetPrimaryFromPods(
 		}
 	}
 
+	// Then check if the data of the current primary is corrupted and
+	// switch over to a healthy replica, which will allow the former
+	// primary to be rebuilt
+	if primary := status.Items[0]; !cluster.IsReplica() && primary.IsPrimary && primary.IsCorrupted() &&
+		primary.Pod.Name == cluster.Status.CurrentPrimary &&
+		cluster.Status.TargetPrimary == cluster.Status.CurrentPrimary {
+		return r.setPrimaryAwayFromCorruption(ctx, cluster, status, &primary)
+	}
+
 	// Second step: check if the first element of the sorted list is the primary
 	if cluster.IsReplica() {
 		return r.updateTargetPrimaryFromPodsReplicaCluster(ctx, cluster, status, resources)
@@ -80,6 +113,63 @@ func (r *ClusterReconciler) updateTargetPrimaryFromPods(
 	return r.updateTargetPrimaryFromPodsPrimaryCluster(ctx, cluster, status, resources)
 }
 
+// setPrimaryAwayFromCorruption switches over from a primary reporting
+// corrupted data to the most advanced healthy replica, when the rebuild of
+// the corrupted instances has been requested. The primary keeps running
+// when there is no replica to be promoted
+func (r *ClusterReconciler) setPrimaryAwayFromCorruption(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	status postgres.PostgresqlStatusList,
+	primaryPod *postgres.PostgresqlStatus,
+) (string, error) {
+	contextLogger := log.FromContext(ctx)
+
+	if !cluster.ShouldRebuildCorruptedReplicas() {
+		contextLogger.Warning("Current primary data is corrupted and the rebuild of the corrupted instances "+
+			"is not enabled", "currentPrimary", primaryPod.Pod.Name,
+			"checksumFailures", primaryPod.ChecksumFailures)
+		r.Recorder.Eventf(cluster, "Warning", "CorruptedPrimary",
+			"Current primary %v reported %v checksum failures",
+			primaryPod.Pod.Name, primaryPod.ChecksumFailures)
+		return "", nil
+	}
+
+	if cluster.Spec.DisableAutomaticFailover {
+		contextLogger.Warning("Current primary data is corrupted and the automatic failover is disabled",
+			"currentPrimary", primaryPod.Pod.Name,
+			"checksumFailures", primaryPod.ChecksumFailures)
+		return "", nil
+	}
+
+	for _, candidate := range status.Items[1:] {
+		if !utils.IsPodReady(candidate.Pod) || !candidate.IsPromotable() || candidate.Error != nil {
+			continue
+		}
+
+		contextLogger.Info("Current primary data is corrupted, triggering a switchover",
+			"currentPrimary", primaryPod.Pod.Name,
+			"checksumFailures", primaryPod.ChecksumFailures,
+			"targetPrimary", candidate.Pod.Name)
+		status.LogStatus(ctx)
+		r.Recorder.Eventf(cluster, "Warning", "SwitchingOver",
+			"Current primary %v reported %v checksum failures, switching over to %v",
+			primaryPod.Pod.Name, primaryPod.ChecksumFailures, candidate.Pod.Name)
+		if err := r.RegisterPhase(ctx, cluster, apiv1.PhaseSwitchover,
+			fmt.Sprintf("Switching over to %v, because the primary instance data is corrupted",
+				candidate.Pod.Name)); err != nil {
+			return "", err
+		}
+		return candidate.Pod.Name, r.setPrimaryInstance(ctx, cluster, candidate.Pod.Name)
+	}
+
+	contextLogger.Warning("Current primary data is corrupted, but there are no valid candidates",
+		"currentPrimary", primaryPod.Pod.Name,
+		"checksumFailures", primaryPod.ChecksumFailures)
+	status.LogStatus(ctx)
+	return "", nil
+}
+
 // updateTargetPrimaryFromPodsPrimaryCluster sets the name of the target primary from the Pods status if needed
 // this function will return the name of the new primary selected for promotion
 func (r *ClusterReconciler) updateTargetPrimaryFromPodsPrimaryCluster(
@@ -103,6 +193,31 @@ func (r *ClusterReconciler) updateTargetPrimaryFromPodsPrimaryCluster(
 		return "", nil
 	}
 
+	// The user chose to promote the new primary manually, so we never
+	// elect one, and we don't change the target of a promotion in progress
+	if cluster.Spec.DisableAutomaticFailover {
+		if cluster.Status.TargetPrimary == cluster.Status.CurrentPrimary {
+			if err := r.requireManualFailover(ctx, cluster); err != nil {
+				return "", err
+			}
+		}
+		return "", ErrWaitingForManualFailover
+	}
+
+	// The most advanced replica is a delayed one or a read replica, meaning
+	// that no other replica is available. Promoting a delayed replica would
+	// discard the changes it didn't apply yet, and read replicas are never
+	// promoted, so we wait for another replica to be back
+	if !status.Items[0].IsPromotable() {
+		return "", ErrNoFailoverCandidate
+	}
+
+	if cluster.Status.TargetPrimary == cluster.Status.CurrentPrimary {
+		if err := enforceFailoverCooldown(ctx, cluster, time.Now()); err != nil {
+			return "", err
+		}
+	}
+
 	if err := r.enforceFailoverDelay(ctx, cluster); err != nil {
 		return "", err
 	}
@@ -137,12 +252,13 @@ func (r *ClusterReconciler) updateTargetPrimaryFromPodsPrimaryCluster(
 	// This may be tha last step of a failover if target primary is set to apiv1.PendingFailoverMarker
 	// or change the target primary if the current one is not valid anymore.
 	if cluster.Status.TargetPrimary == apiv1.PendingFailoverMarker {
-		contextLogger.Info("Failing over", "newPrimary", status.Items[0].Pod.Name)
+		contextLogger.Info("Failing over", "newPrimary", status.Items[0].Pod.Name,
+			"failoverPriority", status.Items[0].GetFailoverPriority())
 		status.LogStatus(ctx)
 		contextLogger.Debug("Cluster status before failover", "instances", resources.instances)
 		r.Recorder.Eventf(cluster, "Normal", "FailoverTarget",
-			"Failing over from %v to %v",
-			cluster.Status.CurrentPrimary, status.Items[0].Pod.Name)
+			"Failing over from %v to %v (failover priority %v)",
+			cluster.Status.CurrentPrimary, status.Items[0].Pod.Name, status.Items[0].GetFailoverPriority())
 		if err := r.RegisterPhase(ctx, cluster, apiv1.PhaseFailOver,
 			fmt.Sprintf("Failing over from %v to %v", cluster.Status.CurrentPrimary, status.Items[0].Pod.Name)); err != nil {
 			return "", err
@@ -153,8 +269,8 @@ func (r *ClusterReconciler) updateTargetPrimaryFromPodsPrimaryCluster(
 		status.LogStatus(ctx)
 		contextLogger.Debug("Cluster status before switching target", "instances", resources.instances)
 		r.Recorder.Eventf(cluster, "Normal", "FailingOver",
-			"Target primary isn't healthy, switching target from %v to %v",
-			cluster.Status.TargetPrimary, status.Items[0].Pod.Name)
+			"Target primary isn't healthy, switching target from %v to %v (failover priority %v)",
+			cluster.Status.TargetPrimary, status.Items[0].Pod.Name, status.Items[0].GetFailoverPriority())
 		if err := r.RegisterPhase(ctx, cluster, apiv1.PhaseSwitchover,
 			fmt.Sprintf("Switching over to %v", status.Items[0].Pod.Name)); err != nil {
 			return "", err
@@ -195,13 +311,13 @@ func (r *ClusterReconciler) setPrimaryOnSchedulableNode(
 	// schedulable instance, wait, because something is in progress
 	if !hasFailedPods &&
 		// e.g an instance is being joined
-		(cluster.Spec.Instances != cluster.Status.ReadyInstances ||
+		(cluster.GetDesiredInstances() != cluster.Status.ReadyInstances ||
 			// e.g. we want all instances to be moved to a schedulable node before triggering the switchover
-			len(podsOnOtherNodes.Items) < cluster.Spec.Instances-1) {
+			len(podsOnOtherNodes.Items) < cluster.GetDesiredInstances()-1) {
 		contextLogger.Info("Current primary is running on unschedulable node and something is already in progress",
 			"currentPrimary", primaryPod,
 			"podsOnOtherNodes", len(podsOnOtherNodes.Items),
-			"instances", cluster.Spec.Instances,
+			"instances", cluster.GetDesiredInstances(),
 			"readyInstances", cluster.Status.ReadyInstances,
 			"primaryNode", primaryPod.Node)
 		return "", nil
@@ -220,7 +336,7 @@ func (r *ClusterReconciler) setPrimaryOnSchedulableNode(
 			continue
 		}
 
-		if !utils.IsPodReady(candidate.Pod) {
+		if !utils.IsPodReady(candidate.Pod) || !candidate.IsPromotable() {
 			continue
 		}
 
@@ -272,6 +388,16 @@ func (r *ClusterReconciler) updateTargetPrimaryFromPodsReplicaCluster(
 		}
 	}
 
+	if !status.Items[0].IsPromotable() {
+		return "", ErrNoFailoverCandidate
+	}
+
+	if cluster.Status.TargetPrimary == cluster.Status.CurrentPrimary {
+		if err := enforceFailoverCooldown(ctx, cluster, time.Now()); err != nil {
+			return "", err
+		}
+	}
+
 	if err := r.enforceFailoverDelay(ctx, cluster); err != nil {
 		return "", err
 	}
@@ -289,8 +415,8 @@ func (r *ClusterReconciler) updateTargetPrimaryFromPodsReplicaCluster(
 	status.LogStatus(ctx)
 	contextLogger.Debug("Cluster status before failover", "instances", resources.instances)
 	r.Recorder.Eventf(cluster, "Normal", "FailingOver",
-		"Current target primary isn't healthy, failing over from %v to %v",
-		cluster.Status.TargetPrimary, status.Items[0].Pod.Name)
+		"Current target primary isn't healthy, failing over from %v to %v (failover priority %v)",
+		cluster.Status.TargetPrimary, status.Items[0].Pod.Name, status.Items[0].GetFailoverPriority())
 	if err := r.RegisterPhase(ctx, cluster, apiv1.PhaseFailOver,
 		fmt.Sprintf("Failing over to %v", status.Items[0].Pod.Name)); err != nil {
 		return "", err
@@ -342,6 +468,170 @@ func (r *ClusterReconciler) enforceFailoverDelay(ctx context.Context, cluster *a
 	return nil
 }
 
+// enforceFailoverCooldown prevents a new failover from being triggered
+// while the primary promoted last is still in its cooldown. Failing over
+// again so quickly is usually a cascade, i.e. while the nodes are lost one
+// after the other, and would make the incident worse. Manual promotions
+// are not affected, as they set the target primary directly
+func enforceFailoverCooldown(ctx context.Context, cluster *apiv1.Cluster, now time.Time) error {
+	remaining := getFailoverCooldownRemaining(cluster, now)
+	if remaining == 0 {
+		return nil
+	}
+
+	log.FromContext(ctx).Info("Current primary isn't healthy, but it has been promoted too recently "+
+		"to trigger another failover",
+		"currentPrimary", cluster.Status.CurrentPrimary,
+		"remainingCooldown", remaining.Round(time.Second).String())
+	return ErrWaitingOnFailoverCooldown
+}
+
+// getFailoverCooldownRemaining gets for how long the automatic failovers
+// are still suspended after the last promotion recorded in the status.
+// Zero is returned when the cooldown is disabled or expired
+func getFailoverCooldownRemaining(cluster *apiv1.Cluster, now time.Time) time.Duration {
+	promotedAt, ok := getLastPromotionTime(cluster)
+	if !ok || cluster.GetFailoverCooldown() == 0 {
+		return 0
+	}
+
+	remaining := promotedAt.Add(cluster.GetFailoverCooldown()).Sub(now)
+	if remaining < 0 {
+		return 0
+	}
+	return remaining
+}
+
+// getLastPromotionTime gets when the current primary has been promoted,
+// from the history of the changes of the primary instance
+func getLastPromotionTime(cluster *apiv1.Cluster) (time.Time, bool) {
+	if len(cluster.Status.PrimaryHistory) == 0 {
+		return time.Time{}, false
+	}
+
+	lastChange := cluster.Status.PrimaryHistory[len(cluster.Status.PrimaryHistory)-1]
+	promotedAt, err := time.Parse(metav1.RFC3339Micro, lastChange.Timestamp)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return promotedAt, true
+}
+
+// setFailoverCooldownCondition sets the condition reporting whether the
+// automatic failovers are suspended after the last promotion, and until
+// when. The condition is only reported as expired once it has been raised
+func setFailoverCooldownCondition(cluster *apiv1.Cluster, now time.Time) {
+	if getFailoverCooldownRemaining(cluster, now) == 0 {
+		if !meta.IsStatusConditionTrue(cluster.Status.Conditions, string(apiv1.ConditionFailoverCooldown)) {
+			return
+		}
+
+		meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
+			Type:    string(apiv1.ConditionFailoverCooldown),
+			Status:  metav1.ConditionFalse,
+			Reason:  string(apiv1.ConditionReasonFailoverCooldownExpired),
+			Message: "The automatic failovers are allowed",
+		})
+		return
+	}
+
+	promotedAt, _ := getLastPromotionTime(cluster)
+	meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
+		Type:   string(apiv1.ConditionFailoverCooldown),
+		Status: metav1.ConditionTrue,
+		Reason: string(apiv1.ConditionReasonFailoverCooldownActive),
+		Message: fmt.Sprintf("The automatic failovers are suspended until %s, %d seconds after "+
+			"the promotion of %v. Manual promotions are still allowed",
+			promotedAt.Add(cluster.GetFailoverCooldown()).Format(time.RFC3339),
+			cluster.Spec.FailoverCooldown, cluster.Status.CurrentPrimary),
+	})
+}
+
+// isPrimaryHealthyWithoutReplicas checks whether the current primary of a
+// cluster having replicas is healthy, while none of the replicas is ready
+func isPrimaryHealthyWithoutReplicas(cluster *apiv1.Cluster, status postgres.PostgresqlStatusList) bool {
+	if cluster.IsReplica() || cluster.GetDesiredInstances() < 2 ||
+		cluster.Status.CurrentPrimary != cluster.Status.TargetPrimary {
+		return false
+	}
+
+	primaryHealthy := false
+	for _, item := range status.Items {
+		isHealthy := item.Error == nil && item.IsPodReady
+		if item.Pod.Name == cluster.Status.CurrentPrimary {
+			primaryHealthy = isHealthy && item.IsPrimary
+			continue
+		}
+		if isHealthy {
+			return false
+		}
+	}
+
+	return primaryHealthy
+}
+
+// isCurrentPrimaryMissing checks whether the pod of the current primary
+// doesn't exist anymore, i.e. because it has been removed together with
+// its node or because it is being recreated
+func isCurrentPrimaryMissing(cluster *apiv1.Cluster, resources *managedResources) bool {
+	if cluster.Status.CurrentPrimary == "" {
+		return false
+	}
+
+	for idx := range resources.instances.Items {
+		if resources.instances.Items[idx].Name == cluster.Status.CurrentPrimary {
+			return false
+		}
+	}
+
+	return true
+}
+
+// reportMissingPrimary reports that the pod of the current primary
+// doesn't exist anymore, and that a new primary will be elected
+func (r *ClusterReconciler) reportMissingPrimary(ctx context.Context, cluster *apiv1.Cluster) error {
+	if meta.IsStatusConditionFalse(cluster.Status.Conditions, string(apiv1.ConditionPrimaryAvailable)) {
+		return nil
+	}
+
+	message := fmt.Sprintf("The pod of the current primary %v doesn't exist anymore, "+
+		"a new primary will be elected", cluster.Status.CurrentPrimary)
+	log.FromContext(ctx).Info("The pod of the current primary doesn't exist anymore",
+		"currentPrimary", cluster.Status.CurrentPrimary)
+	r.Recorder.Event(cluster, "Warning", "PrimaryPodMissing", message)
+
+	meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
+		Type:    string(apiv1.ConditionPrimaryAvailable),
+		Status:  metav1.ConditionFalse,
+		Reason:  string(apiv1.ConditionReasonPrimaryPodMissing),
+		Message: message,
+	})
+
+	return r.Status().Update(ctx, cluster)
+}
+
+// requireManualFailover reports that the current primary is not healthy
+// and that a replica needs to be promoted by the user
+func (r *ClusterReconciler) requireManualFailover(ctx context.Context, cluster *apiv1.Cluster) error {
+	message := fmt.Sprintf("Current primary %v isn't healthy and the automatic failover is disabled, "+
+		"a replica needs to be manually promoted", cluster.Status.CurrentPrimary)
+
+	if !meta.IsStatusConditionFalse(cluster.Status.Conditions, string(apiv1.ConditionPrimaryAvailable)) {
+		log.FromContext(ctx).Info("Current primary isn't healthy, waiting for a manual failover",
+			"currentPrimary", cluster.Status.CurrentPrimary)
+		r.Recorder.Event(cluster, "Warning", "ManualFailoverRequired", message)
+	}
+
+	meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
+		Type:    string(apiv1.ConditionPrimaryAvailable),
+		Status:  metav1.ConditionFalse,
+		Reason:  string(apiv1.ConditionReasonManualFailoverRequired),
+		Message: message,
+	})
+
+	return r.RegisterPhase(ctx, cluster, apiv1.PhaseWaitingForUser, message)
+}
+
 // updateClusterAnnotationsOnPods we check if we need to add or modify existing annotations specified in the cluster but
 // not existing in the pods. We do not support the case of removed annotations from the cluster resource.
 func (r *ClusterReconciler) updateClusterAnnotationsOnPods(
@@ -423,17 +713,21 @@ func (r *ClusterReconciler) updateClusterLabelsOnPods(
 	return nil
 }
 
-// Make sure that only the currentPrimary has the label forward write traffic to him
+// Make sure that only the currentPrimary has the label forward write traffic to him.
+// The primary label is not set until the current primary is accepting read-write
+// connections, so that the -rw service never points to an instance which is still
+// being promoted. In this case, the returned flag is true
 func (r *ClusterReconciler) updateRoleLabelsOnPods(
 	ctx context.Context,
 	cluster *apiv1.Cluster,
 	pods corev1.PodList,
-) error {
+	instancesStatus postgres.PostgresqlStatusList,
+) (primaryLabelDeferred bool, err error) {
 	contextLogger := log.FromContext(ctx)
 
 	// No current primary, no work to do
 	if cluster.Status.CurrentPrimary == "" {
-		return nil
+		return false, nil
 	}
 
 	primaryFound := false
@@ -452,13 +746,23 @@ func (r *ClusterReconciler) updateRoleLabelsOnPods(
 		case pod.Name == cluster.Status.CurrentPrimary:
 			primaryFound = true
 
-			if !hasRole || podRole != specs.ClusterRoleLabelPrimary {
-				contextLogger.Info("Setting primary label", "pod", pod.Name)
-				patch := client.MergeFrom(pod.DeepCopy())
-				pod.Labels[specs.ClusterRoleLabelName] = specs.ClusterRoleLabelPrimary
-				if err := r.Patch(ctx, pod, patch); err != nil {
-					return err
-				}
+			if hasRole && podRole == specs.ClusterRoleLabelPrimary {
+				continue
+			}
+
+			// The designated primary of a replica cluster is always in recovery
+			if !cluster.IsReplica() && instancesStatus.IsRefusingReadWrite(pod.Name) {
+				contextLogger.Info("Waiting for the primary to accept read-write connections "+
+					"before setting the primary label", "pod", pod.Name)
+				primaryLabelDeferred = true
+				continue
+			}
+
+			contextLogger.Info("Setting primary label", "pod", pod.Name)
+			patch := client.MergeFrom(pod.DeepCopy())
+			pod.Labels[specs.ClusterRoleLabelName] = specs.ClusterRoleLabelPrimary
+			if err := r.Patch(ctx, pod, patch); err != nil {
+				return false, err
 			}
 
 		default:
@@ -467,7 +771,7 @@ func (r *ClusterReconciler) updateRoleLabelsOnPods(
 				patch := client.MergeFrom(pod.DeepCopy())
 				pod.Labels[specs.ClusterRoleLabelName] = specs.ClusterRoleLabelReplica
 				if err := r.Patch(ctx, pod, patch); err != nil {
-					return err
+					return false, err
 				}
 			}
 		}
@@ -477,9 +781,401 @@ func (r *ClusterReconciler) updateRoleLabelsOnPods(
 		contextLogger.Info("No primary instance found for this cluster")
 	}
 
+	return primaryLabelDeferred, nil
+}
+
+// lsnAnnotationRefreshBytes is how much the LSN of an instance needs to
+// move before its annotation is refreshed. The LSN of a busy instance
+// changes at every reconciliation loop, and patching the Pods every time
+// would flood the API server
+const lsnAnnotationRefreshBytes = 16 * 1024 * 1024
+
+// isLSNAnnotationOutdated checks whether the LSN annotation of a Pod
+// needs to be refreshed with the latest LSN reported by the instance
+func isLSNAnnotationOutdated(annotated string, latest postgres.LSN) bool {
+	if annotated == string(latest) {
+		return false
+	}
+
+	annotatedPosition, err := postgres.LSN(annotated).Parse()
+	if err != nil {
+		return true
+	}
+	latestPosition, err := latest.Parse()
+	if err != nil {
+		return false
+	}
+
+	distance := latestPosition - annotatedPosition
+	if distance < 0 {
+		distance = -distance
+	}
+	return distance >= lsnAnnotationRefreshBytes
+}
+
+// updateTimelineOnPods labels every Pod with the timeline its instance is
+// on and annotates it with the latest reported LSN, so that an instance
+// stuck on an old timeline after a failover can be easily spotted
+func (r *ClusterReconciler) updateTimelineOnPods(
+	ctx context.Context,
+	instancesStatus postgres.PostgresqlStatusList,
+) error {
+	contextLogger := log.FromContext(ctx)
+
+	for idx := range instancesStatus.Items {
+		status := instancesStatus.Items[idx]
+		if status.Error != nil || status.TimeLineID == 0 {
+			continue
+		}
+
+		pod := status.Pod.DeepCopy()
+		timeline := strconv.Itoa(status.TimeLineID)
+		lsn := string(status.GetLatestLSN())
+		timelineChanged := pod.Labels[specs.ClusterTimelineLabelName] != timeline
+		lsnOutdated := isLSNAnnotationOutdated(pod.Annotations[specs.ClusterLSNAnnotationName], status.GetLatestLSN())
+		if !timelineChanged && !lsnOutdated {
+			continue
+		}
+
+		patch := client.MergeFrom(pod.DeepCopy())
+		if pod.Labels == nil {
+			pod.Labels = make(map[string]string)
+		}
+		if pod.Annotations == nil {
+			pod.Annotations = make(map[string]string)
+		}
+		if timelineChanged {
+			contextLogger.Info("Setting timeline label", "pod", pod.Name, "timeline", timeline)
+		}
+		pod.Labels[specs.ClusterTimelineLabelName] = timeline
+		pod.Annotations[specs.ClusterLSNAnnotationName] = lsn
+		if err := r.Patch(ctx, pod, patch); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// isTimelineDiverged checks if a replica is stuck on a timeline older than
+// the primary one. The timeline reported by a replica is only updated at
+// restartpoints, so a replica which is still streaming is considered able
+// to follow the new timeline
+func isTimelineDiverged(status postgres.PostgresqlStatus, primaryTimeline int) bool {
+	return !status.IsPrimary &&
+		status.Error == nil &&
+		!status.IsWalReceiverActive &&
+		status.TimeLineID != 0 &&
+		status.TimeLineID < primaryTimeline
+}
+
+// refreshTimelineDivergedSince gets since when a replica has been found
+// on a diverged timeline, to be stored in the status. An empty string is
+// returned when the timeline of the replica is fine
+func refreshTimelineDivergedSince(
+	previous string,
+	status postgres.PostgresqlStatus,
+	primaryTimeline int,
+	now time.Time,
+) string {
+	if !isTimelineDiverged(status, primaryTimeline) {
+		return ""
+	}
+	if previous != "" {
+		return previous
+	}
+	return now.Format(time.RFC3339)
+}
+
+// getDivergedReplicasToRestart gets the replicas which have been stuck on a
+// diverged timeline for longer than divergedTimelineGracePeriod, and need
+// to be restarted to be resynchronized. When some replicas are still within
+// the grace period, the time to wait for it to expire is returned too
+func getDivergedReplicasToRestart(cluster *apiv1.Cluster, now time.Time) ([]string, time.Duration) {
+	var toRestart []string
+	var requeueAfter time.Duration
+	for podName, state := range cluster.Status.InstancesReportedState {
+		instanceName := string(podName)
+		if state.TimelineDivergedSince == "" ||
+			instanceName == cluster.Status.CurrentPrimary ||
+			instanceName == cluster.Status.TargetPrimary ||
+			slices.Contains(cluster.Status.ResynchronizingInstances, instanceName) {
+			continue
+		}
+
+		divergedSince, err := time.Parse(time.RFC3339, state.TimelineDivergedSince)
+		if err != nil {
+			continue
+		}
+
+		remaining := divergedTimelineGracePeriod - now.Sub(divergedSince)
+		if remaining <= 0 {
+			toRestart = append(toRestart, instanceName)
+			continue
+		}
+		if requeueAfter == 0 || remaining < requeueAfter {
+			requeueAfter = remaining
+		}
+	}
+
+	sort.Strings(toRestart)
+	return toRestart, requeueAfter
+}
+
+// restartDivergedReplicas marks the passed replicas to be resynchronized
+// and deletes their Pods. The instance manager of the recreated Pods runs
+// pg_rewind, or clones the primary when the data directory can't be
+// rewound, before starting PostgreSQL
+func (r *ClusterReconciler) restartDivergedReplicas(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	resources *managedResources,
+	instanceNames []string,
+) error {
+	contextLogger := log.FromContext(ctx)
+
+	origCluster := cluster.DeepCopy()
+	cluster.Status.ResynchronizingInstances = append(cluster.Status.ResynchronizingInstances, instanceNames...)
+	if err := r.Status().Patch(ctx, cluster, client.MergeFrom(origCluster)); err != nil {
+		return err
+	}
+
+	for idx := range resources.instances.Items {
+		pod := &resources.instances.Items[idx]
+		if !slices.Contains(instanceNames, pod.Name) {
+			continue
+		}
+
+		state := cluster.Status.InstancesReportedState[apiv1.PodName(pod.Name)]
+		contextLogger.Warning("Restarting replica stuck on a diverged timeline",
+			"pod", pod.Name,
+			"timeline", state.TimeLineID,
+			"primaryTimeline", cluster.Status.TimelineID,
+			"divergedSince", state.TimelineDivergedSince)
+		if err := r.Delete(ctx, pod); err != nil && !apierrs.IsNotFound(err) {
+			return err
+		}
+		r.Recorder.Eventf(cluster, "Warning", "ReplicaTimelineDiverged",
+			"Replica %v is stuck on timeline %v while the primary is on timeline %v, "+
+				"restarting it to be resynchronized",
+			pod.Name, state.TimeLineID, cluster.Status.TimelineID)
+	}
+
+	return nil
+}
+
+// refreshChecksumFailuresBaseline gets the number of checksum failures of
+// an instance which are not considered a sign of corruption, to be stored
+// in the status. The failures reported when the instance is first seen may
+// have been detected, and handled, long before, so they are taken as the
+// baseline. The baseline follows the counter when the statistics of the
+// instance are reset, which is the way to acknowledge the new failures.
+// The previous value is kept when the status can't be read
+func refreshChecksumFailuresBaseline(previous *int64, status postgres.PostgresqlStatus) *int64 {
+	if status.Error != nil {
+		return previous
+	}
+	if previous == nil || status.ChecksumFailures < *previous {
+		return pointer.Int64(status.ChecksumFailures)
+	}
+	return previous
+}
+
+// setChecksumFailuresBaselines copies the checksum failures baselines
+// recorded in the cluster status into the status of the instances, which
+// is then sorted again, as the corrupted instances are never promotable
+func setChecksumFailuresBaselines(cluster *apiv1.Cluster, status *postgres.PostgresqlStatusList) {
+	for idx := range status.Items {
+		state := cluster.Status.InstancesReportedState[apiv1.PodName(status.Items[idx].Pod.Name)]
+		if state.ChecksumFailuresBaseline != nil {
+			status.Items[idx].ChecksumFailuresBaseline = *state.ChecksumFailuresBaseline
+		}
+	}
+	sort.Sort(status)
+}
+
+// refreshCorruptedSince gets since when a replica has been found with
+// corrupted data, to be stored in the status. An empty string is returned
+// when the replica doesn't report new checksum failures. The previous value
+// is kept when the status can't be read, as a corrupted replica may not be
+// able to report it anymore
+func refreshCorruptedSince(
+	previous string,
+	status postgres.PostgresqlStatus,
+	now time.Time,
+) string {
+	if status.Error != nil {
+		return previous
+	}
+	if status.IsPrimary || !status.IsCorrupted() {
+		return ""
+	}
+	if previous != "" {
+		return previous
+	}
+	return now.Format(time.RFC3339)
+}
+
+// getCorruptedReplicasToRebuild gets the replicas which have been reporting
+// corrupted data for longer than the grace period, and need to be rebuilt
+// from the primary. Nothing is rebuilt unless requested in the cluster
+// spec. When some replicas are still within the grace period, the time to
+// wait for it to expire is returned too
+func getCorruptedReplicasToRebuild(cluster *apiv1.Cluster, now time.Time) ([]string, time.Duration) {
+	if !cluster.ShouldRebuildCorruptedReplicas() {
+		return nil, 0
+	}
+
+	gracePeriod := cluster.GetCorruptedReplicaRebuildGracePeriod()
+	var toRebuild []string
+	var requeueAfter time.Duration
+	for podName, state := range cluster.Status.InstancesReportedState {
+		instanceName := string(podName)
+		if state.CorruptedSince == "" ||
+			instanceName == cluster.Status.CurrentPrimary ||
+			instanceName == cluster.Status.TargetPrimary {
+			continue
+		}
+
+		corruptedSince, err := time.Parse(time.RFC3339, state.CorruptedSince)
+		if err != nil {
+			continue
+		}
+
+		remaining := gracePeriod - now.Sub(corruptedSince)
+		if remaining <= 0 {
+			toRebuild = append(toRebuild, instanceName)
+			continue
+		}
+		if requeueAfter == 0 || remaining < requeueAfter {
+			requeueAfter = remaining
+		}
+	}
+
+	sort.Strings(toRebuild)
+	return toRebuild, requeueAfter
+}
+
+// rebuildCorruptedReplicas deletes the Pods and the PVCs of the passed
+// replicas, which can't be trusted anymore. The missing instances are
+// then recreated by cloning the primary
+func (r *ClusterReconciler) rebuildCorruptedReplicas(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	resources *managedResources,
+	instanceNames []string,
+) error {
+	contextLogger := log.FromContext(ctx)
+
+	for idx := range resources.instances.Items {
+		pod := &resources.instances.Items[idx]
+		if !slices.Contains(instanceNames, pod.Name) {
+			continue
+		}
+
+		state := cluster.Status.InstancesReportedState[apiv1.PodName(pod.Name)]
+		contextLogger.Warning("Rebuilding replica with corrupted data",
+			"pod", pod.Name,
+			"corruptedSince", state.CorruptedSince)
+		r.Recorder.Eventf(cluster, "Warning", "RebuildingCorruptedReplica",
+			"Replica %v reported checksum failures since %v, deleting it to be rebuilt from the primary",
+			pod.Name, state.CorruptedSince)
+		if err := r.deleteInstance(ctx, cluster, resources, pod); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// getStuckPrimaryTransitionDuration gets for how long the switchover or
+// the failover in progress has been running, when it exceeds the timeout
+// after which it is reported as stuck. Zero is returned otherwise
+func getStuckPrimaryTransitionDuration(cluster *apiv1.Cluster, now time.Time) time.Duration {
+	targetPrimaryTime, err := time.Parse(metav1.RFC3339Micro, cluster.Status.TargetPrimaryTimestamp)
+	if err != nil {
+		return 0
+	}
+
+	elapsed := now.Sub(targetPrimaryTime)
+	if elapsed < cluster.GetPrimaryTransitionStuckTimeout() {
+		return 0
+	}
+	return elapsed
+}
+
+// reconcileStuckPrimaryTransition reports a switchover or a failover which
+// has been taking longer than the configured timeout, through a condition
+// and an event, and restarts the target primary when requested
+func (r *ClusterReconciler) reconcileStuckPrimaryTransition(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	resources *managedResources,
+) error {
+	contextLogger := log.FromContext(ctx)
+
+	elapsed := getStuckPrimaryTransitionDuration(cluster, time.Now())
+	if elapsed == 0 ||
+		meta.IsStatusConditionTrue(cluster.Status.Conditions, string(apiv1.ConditionPrimaryTransitionStuck)) {
+		return nil
+	}
+
+	message := fmt.Sprintf("The switchover or failover from %v to %v has been stuck for %d minutes",
+		cluster.Status.CurrentPrimary, cluster.Status.TargetPrimary, int(elapsed.Minutes()))
+	contextLogger.Warning(message,
+		"currentPrimary", cluster.Status.CurrentPrimary,
+		"targetPrimary", cluster.Status.TargetPrimary,
+		"targetPrimaryTimestamp", cluster.Status.TargetPrimaryTimestamp)
+	r.Recorder.Event(cluster, "Warning", "PrimaryTransitionStuck", message)
+
+	origCluster := cluster.DeepCopy()
+	meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
+		Type:    string(apiv1.ConditionPrimaryTransitionStuck),
+		Status:  metav1.ConditionTrue,
+		Reason:  string(apiv1.ConditionReasonPrimaryTransitionTimedOut),
+		Message: message,
+	})
+	if err := r.Status().Patch(ctx, cluster, client.MergeFrom(origCluster)); err != nil {
+		return err
+	}
+
+	if !cluster.ShouldRestartStuckTargetPrimary() {
+		return nil
+	}
+
+	for idx := range resources.instances.Items {
+		pod := &resources.instances.Items[idx]
+		if pod.Name != cluster.Status.TargetPrimary {
+			continue
+		}
+
+		contextLogger.Info("Restarting the target primary to retry the promotion", "pod", pod.Name)
+		r.Recorder.Eventf(cluster, "Normal", "RestartingTargetPrimary",
+			"Restarting the target primary %v to retry the promotion", pod.Name)
+		if err := r.Delete(ctx, pod); err != nil && !apierrs.IsNotFound(err) {
+			return err
+		}
+	}
+
 	return nil
 }
 
+// setPrimaryTransitionCompletedCondition reports that the switchover or the
+// failover which was reported as stuck has completed. The condition is only
+// set once a switchover or a failover has been stuck
+func setPrimaryTransitionCompletedCondition(cluster *apiv1.Cluster) {
+	if meta.FindStatusCondition(cluster.Status.Conditions, string(apiv1.ConditionPrimaryTransitionStuck)) == nil {
+		return
+	}
+
+	meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
+		Type:    string(apiv1.ConditionPrimaryTransitionStuck),
+		Status:  metav1.ConditionFalse,
+		Reason:  string(apiv1.ConditionReasonPrimaryTransitionCompleted),
+		Message: fmt.Sprintf("The primary %v has been promoted", cluster.Status.CurrentPrimary),
+	})
+}
+
 // updateOperatorLabelsOnInstances ensures that the instances have the correct labels
 func (r *ClusterReconciler) updateOperatorLabelsOnInstances(
 	ctx context.Context,
@@ -543,3 +1239,27 @@ func getSacrificialInstance(podList []corev1.Pod) *corev1.Pod {
 	}
 	return &podList[resultIdx]
 }
+
+// filterReadReplicas gets the Pods of the read replicas when readReplicas is
+// true, and the Pods of the instances which can be promoted otherwise
+func filterReadReplicas(podList []corev1.Pod, readReplicas bool) []corev1.Pod {
+	result := make([]corev1.Pod, 0, len(podList))
+	for _, pod := range podList {
+		if utils.IsReadReplica(&pod.ObjectMeta) == readReplicas {
+			result = append(result, pod)
+		}
+	}
+
+	return result
+}
+
+// getReadReplicaNames gets the sorted names of the read replicas
+func getReadReplicaNames(podList []corev1.Pod) []string {
+	var result []string
+	for _, pod := range filterReadReplicas(podList, true) {
+		result = append(result, pod.Name)
+	}
+	sort.Strings(result)
+
+	return result
+}
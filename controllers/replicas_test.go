@@ -17,11 +17,21 @@ limitations under the License.
 package controllers
 
 import (
+	"context"
+	"fmt"
+	"time"
+
 	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -123,6 +133,22 @@ var _ = Describe("Sacrificial Pod detection", func() {
 		Expect(result).ToNot(BeNil())
 		Expect(result.Name).To(Equal("car-2"))
 	})
+
+	It("chooses among the read replicas or among the other instances", func() {
+		readReplica := car1.DeepCopy()
+		readReplica.Labels = map[string]string{utils.ReadReplicaLabelName: "true"}
+		podList := []corev1.Pod{*readReplica, car2}
+
+		result := getSacrificialInstance(filterReadReplicas(podList, true))
+		Expect(result).ToNot(BeNil())
+		Expect(result.Name).To(Equal("car-1"))
+
+		result = getSacrificialInstance(filterReadReplicas(podList, false))
+		Expect(result).ToNot(BeNil())
+		Expect(result.Name).To(Equal("car-2"))
+
+		Expect(getReadReplicaNames(podList)).To(Equal([]string{"car-1"}))
+	})
 })
 
 var _ = Describe("Check pods not on primary node", func() {
@@ -150,3 +176,627 @@ var _ = Describe("Check pods not on primary node", func() {
 		Expect(GetPodsNotOnPrimaryNode(statusList2, &statusList2.Items[0]).Items).ToNot(BeEmpty())
 	})
 })
+
+var _ = Describe("Primary pod vanished", func() {
+	It("detects when the pod of the current primary doesn't exist anymore", func() {
+		cluster := &apiv1.Cluster{
+			Status: apiv1.ClusterStatus{CurrentPrimary: "cluster-example-1"},
+		}
+		resources := &managedResources{
+			instances: corev1.PodList{Items: []corev1.Pod{
+				{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-2"}},
+			}},
+		}
+		Expect(isCurrentPrimaryMissing(cluster, resources)).To(BeTrue())
+
+		resources.instances.Items = append(resources.instances.Items,
+			corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-1"}})
+		Expect(isCurrentPrimaryMissing(cluster, resources)).To(BeFalse())
+
+		cluster.Status.CurrentPrimary = ""
+		Expect(isCurrentPrimaryMissing(cluster, resources)).To(BeFalse())
+	})
+
+	It("waits for the failover delay, as the pod of the primary may be recreated", func() {
+		ctx := context.TODO()
+		namespace := newFakeNamespace()
+		cluster := newFakeCNPGCluster(namespace, func(cluster *apiv1.Cluster) {
+			cluster.Spec.FailoverDelay = 3600
+		})
+		pods := generateFakeClusterPodsWithDefaultClient(cluster, true)
+
+		By("removing the pod of the current primary")
+		cluster.Status.CurrentPrimary = pods[0].Name
+		cluster.Status.TargetPrimary = pods[0].Name
+		Expect(k8sClient.Delete(ctx, &pods[0])).To(Succeed())
+		resources := &managedResources{
+			instances: corev1.PodList{Items: pods[1:]},
+		}
+		status := postgres.PostgresqlStatusList{Items: []postgres.PostgresqlStatus{
+			{Pod: pods[1], IsPodReady: true},
+			{Pod: pods[2], IsPodReady: true},
+		}}
+
+		By("reporting the missing primary")
+		Expect(clusterReconciler.reportMissingPrimary(ctx, cluster)).To(Succeed())
+		condition := meta.FindStatusCondition(cluster.Status.Conditions, string(apiv1.ConditionPrimaryAvailable))
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal(string(apiv1.ConditionReasonPrimaryPodMissing)))
+
+		By("waiting for the failover delay before electing a new primary")
+		selectedPrimary, err := clusterReconciler.updateTargetPrimaryFromPods(ctx, cluster, status, resources)
+		Expect(err).To(Equal(ErrWaitingOnFailOverDelay))
+		Expect(selectedPrimary).To(BeEmpty())
+		Expect(cluster.Status.TargetPrimary).To(Equal(pods[0].Name))
+		Expect(cluster.Status.CurrentPrimaryFailingSinceTimestamp).ToNot(BeEmpty())
+
+		By("recreating the pod of the current primary")
+		resources.instances.Items = pods
+		Expect(isCurrentPrimaryMissing(cluster, resources)).To(BeFalse())
+	})
+
+	It("elects a new primary straight away without a failover delay", func() {
+		ctx := context.TODO()
+		namespace := newFakeNamespace()
+		cluster := newFakeCNPGCluster(namespace)
+		pods := generateFakeClusterPodsWithDefaultClient(cluster, true)
+
+		cluster.Status.CurrentPrimary = pods[0].Name
+		cluster.Status.TargetPrimary = pods[0].Name
+		Expect(k8sClient.Delete(ctx, &pods[0])).To(Succeed())
+		resources := &managedResources{
+			instances: corev1.PodList{Items: pods[1:]},
+		}
+		status := postgres.PostgresqlStatusList{Items: []postgres.PostgresqlStatus{
+			{Pod: pods[1], IsPodReady: true},
+			{Pod: pods[2], IsPodReady: true},
+		}}
+
+		selectedPrimary, err := clusterReconciler.updateTargetPrimaryFromPods(ctx, cluster, status, resources)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(selectedPrimary).To(Equal(pods[1].Name))
+		Expect(cluster.Status.TargetPrimary).To(Equal(pods[1].Name))
+	})
+})
+
+var _ = Describe("Primary healthy with every replica down", func() {
+	var cluster *apiv1.Cluster
+	var status postgres.PostgresqlStatusList
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{Instances: 3},
+			Status: apiv1.ClusterStatus{
+				CurrentPrimary: "cluster-example-1",
+				TargetPrimary:  "cluster-example-1",
+			},
+		}
+		status = postgres.PostgresqlStatusList{Items: []postgres.PostgresqlStatus{
+			{
+				Pod:        corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-1"}},
+				IsPrimary:  true,
+				IsPodReady: true,
+			},
+			{
+				Pod:   corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-2"}},
+				Error: fmt.Errorf("connection refused"),
+			},
+			{
+				Pod: corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-3"}},
+			},
+		}}
+	})
+
+	It("detects when none of the replicas is ready", func() {
+		Expect(isPrimaryHealthyWithoutReplicas(cluster, status)).To(BeTrue())
+
+		By("having the pods of the replicas missing")
+		status.Items = status.Items[:1]
+		Expect(isPrimaryHealthyWithoutReplicas(cluster, status)).To(BeTrue())
+	})
+
+	It("doesn't apply when a replica is ready", func() {
+		status.Items[2].IsPodReady = true
+		Expect(isPrimaryHealthyWithoutReplicas(cluster, status)).To(BeFalse())
+	})
+
+	It("doesn't apply when the primary isn't healthy", func() {
+		status.Items[0].IsPodReady = false
+		Expect(isPrimaryHealthyWithoutReplicas(cluster, status)).To(BeFalse())
+	})
+
+	It("doesn't apply to clusters without replicas or changing primary", func() {
+		cluster.Spec.Instances = 1
+		Expect(isPrimaryHealthyWithoutReplicas(cluster, status)).To(BeFalse())
+
+		cluster.Spec.Instances = 3
+		cluster.Status.TargetPrimary = apiv1.PendingFailoverMarker
+		Expect(isPrimaryHealthyWithoutReplicas(cluster, status)).To(BeFalse())
+	})
+
+	It("keeps the current primary", func() {
+		selectedPrimary, err := clusterReconciler.updateTargetPrimaryFromPods(
+			context.TODO(), cluster, status, &managedResources{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(selectedPrimary).To(BeEmpty())
+		Expect(cluster.Status.TargetPrimary).To(Equal("cluster-example-1"))
+	})
+})
+
+var _ = Describe("Replicas stuck on a diverged timeline", func() {
+	now := time.Date(2023, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	It("detects a replica which is not streaming on an old timeline", func() {
+		stuck := postgres.PostgresqlStatus{TimeLineID: 1}
+		Expect(isTimelineDiverged(stuck, 2)).To(BeTrue())
+
+		streaming := postgres.PostgresqlStatus{TimeLineID: 1, IsWalReceiverActive: true}
+		Expect(isTimelineDiverged(streaming, 2)).To(BeFalse())
+
+		aligned := postgres.PostgresqlStatus{TimeLineID: 2}
+		Expect(isTimelineDiverged(aligned, 2)).To(BeFalse())
+
+		primary := postgres.PostgresqlStatus{TimeLineID: 1, IsPrimary: true}
+		Expect(isTimelineDiverged(primary, 2)).To(BeFalse())
+	})
+
+	It("keeps track of since when the replica is stuck", func() {
+		stuck := postgres.PostgresqlStatus{TimeLineID: 1}
+		divergedSince := refreshTimelineDivergedSince("", stuck, 2, now)
+		Expect(divergedSince).To(Equal(now.Format(time.RFC3339)))
+		Expect(refreshTimelineDivergedSince(divergedSince, stuck, 2, now.Add(time.Minute))).
+			To(Equal(divergedSince))
+
+		recovered := postgres.PostgresqlStatus{TimeLineID: 1, IsWalReceiverActive: true}
+		Expect(refreshTimelineDivergedSince(divergedSince, recovered, 2, now)).To(BeEmpty())
+	})
+
+	It("restarts the replicas only after the grace period", func() {
+		cluster := &apiv1.Cluster{
+			Status: apiv1.ClusterStatus{
+				CurrentPrimary: "cluster-example-2",
+				TargetPrimary:  "cluster-example-2",
+				InstancesReportedState: map[apiv1.PodName]apiv1.InstanceReportedState{
+					"cluster-example-1": {
+						TimeLineID:            1,
+						TimelineDivergedSince: now.Add(-10 * time.Minute).Format(time.RFC3339),
+					},
+					"cluster-example-2": {IsPrimary: true, TimeLineID: 2},
+					"cluster-example-3": {
+						TimeLineID:            1,
+						TimelineDivergedSince: now.Add(-2 * time.Minute).Format(time.RFC3339),
+					},
+				},
+			},
+		}
+
+		toRestart, requeueAfter := getDivergedReplicasToRestart(cluster, now)
+		Expect(toRestart).To(Equal([]string{"cluster-example-1"}))
+		Expect(requeueAfter).To(Equal(3 * time.Minute))
+
+		cluster.Status.ResynchronizingInstances = []string{"cluster-example-1"}
+		toRestart, _ = getDivergedReplicasToRestart(cluster, now)
+		Expect(toRestart).To(BeEmpty())
+	})
+
+	It("triggers the resynchronization of a replica after a timeline mismatch", func() {
+		ctx := context.TODO()
+		namespace := newFakeNamespace()
+		cluster := newFakeCNPGCluster(namespace)
+		pods := generateFakeClusterPodsWithDefaultClient(cluster, true)
+		cluster.Status.CurrentPrimary = pods[1].Name
+		cluster.Status.TargetPrimary = pods[1].Name
+		cluster.Status.InstanceNames = []string{pods[0].Name, pods[1].Name, pods[2].Name}
+
+		By("simulating a replica stuck on the previous timeline", func() {
+			status := postgres.PostgresqlStatusList{Items: []postgres.PostgresqlStatus{
+				{Pod: pods[0], TimeLineID: 1},
+				{Pod: pods[1], TimeLineID: 2, IsPrimary: true},
+				{Pod: pods[2], TimeLineID: 2, IsWalReceiverActive: true},
+			}}
+			Expect(clusterReconciler.updateClusterStatusThatRequiresInstancesState(ctx, cluster, status)).
+				To(Succeed())
+			Expect(cluster.Status.InstancesReportedState[apiv1.PodName(pods[0].Name)].TimelineDivergedSince).
+				ToNot(BeEmpty())
+			Expect(cluster.Status.InstancesReportedState[apiv1.PodName(pods[2].Name)].TimelineDivergedSince).
+				To(BeEmpty())
+		})
+
+		By("waiting for the grace period to expire", func() {
+			toRestart, requeueAfter := getDivergedReplicasToRestart(cluster, time.Now())
+			Expect(toRestart).To(BeEmpty())
+			Expect(requeueAfter).To(BeNumerically(">", 0))
+
+			toRestart, _ = getDivergedReplicasToRestart(cluster, time.Now().Add(divergedTimelineGracePeriod))
+			Expect(toRestart).To(Equal([]string{pods[0].Name}))
+		})
+
+		By("restarting the stuck replica", func() {
+			resources := &managedResources{instances: corev1.PodList{Items: pods}}
+			Expect(clusterReconciler.restartDivergedReplicas(ctx, cluster, resources, []string{pods[0].Name})).
+				To(Succeed())
+
+			var updatedCluster apiv1.Cluster
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(cluster), &updatedCluster)).To(Succeed())
+			Expect(updatedCluster.Status.ResynchronizingInstances).To(Equal([]string{pods[0].Name}))
+
+			var pod corev1.Pod
+			err := k8sClient.Get(ctx, client.ObjectKeyFromObject(&pods[0]), &pod)
+			if err == nil {
+				// envtest has no kubelet, the pod is only marked for deletion
+				Expect(pod.DeletionTimestamp).ToNot(BeNil())
+			} else {
+				Expect(apierrs.IsNotFound(err)).To(BeTrue())
+			}
+		})
+	})
+})
+
+var _ = Describe("Replicas with corrupted data", func() {
+	now := time.Date(2023, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	It("keeps track of since when the replica is corrupted", func() {
+		corrupted := postgres.PostgresqlStatus{ChecksumFailures: 3}
+		corruptedSince := refreshCorruptedSince("", corrupted, now)
+		Expect(corruptedSince).To(Equal(now.Format(time.RFC3339)))
+		Expect(refreshCorruptedSince(corruptedSince, corrupted, now.Add(time.Minute))).
+			To(Equal(corruptedSince))
+
+		unreachable := postgres.PostgresqlStatus{Error: fmt.Errorf("connection refused")}
+		Expect(refreshCorruptedSince(corruptedSince, unreachable, now)).To(Equal(corruptedSince))
+
+		Expect(refreshCorruptedSince("", postgres.PostgresqlStatus{}, now)).To(BeEmpty())
+
+		primary := postgres.PostgresqlStatus{ChecksumFailures: 3, IsPrimary: true}
+		Expect(refreshCorruptedSince("", primary, now)).To(BeEmpty())
+	})
+
+	It("takes the checksum failures reported when first seen as the baseline", func() {
+		baseline := refreshChecksumFailuresBaseline(nil, postgres.PostgresqlStatus{ChecksumFailures: 3})
+		Expect(baseline).To(HaveValue(BeEquivalentTo(3)))
+
+		// new failures don't move the baseline
+		Expect(refreshChecksumFailuresBaseline(baseline, postgres.PostgresqlStatus{ChecksumFailures: 5})).
+			To(HaveValue(BeEquivalentTo(3)))
+
+		// the baseline follows the counter when the statistics are reset
+		Expect(refreshChecksumFailuresBaseline(baseline, postgres.PostgresqlStatus{})).
+			To(HaveValue(BeEquivalentTo(0)))
+
+		unreachable := postgres.PostgresqlStatus{Error: fmt.Errorf("connection refused")}
+		Expect(refreshChecksumFailuresBaseline(nil, unreachable)).To(BeNil())
+		Expect(refreshChecksumFailuresBaseline(baseline, unreachable)).To(Equal(baseline))
+	})
+
+	It("doesn't consider the instances corrupted because of stale checksum failures", func() {
+		cluster := &apiv1.Cluster{
+			Status: apiv1.ClusterStatus{
+				InstancesReportedState: map[apiv1.PodName]apiv1.InstanceReportedState{
+					"cluster-example-1": {IsPrimary: true, ChecksumFailuresBaseline: pointer.Int64(2)},
+					"cluster-example-2": {ChecksumFailuresBaseline: pointer.Int64(0)},
+					"cluster-example-3": {ChecksumFailuresBaseline: pointer.Int64(4)},
+				},
+			},
+		}
+		status := postgres.PostgresqlStatusList{Items: []postgres.PostgresqlStatus{
+			{
+				Pod:              corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-1"}},
+				IsPrimary:        true,
+				ChecksumFailures: 2,
+			},
+			{
+				Pod:              corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-2"}},
+				ReceivedLsn:      "0/6000000",
+				ChecksumFailures: 1,
+			},
+			{
+				Pod:              corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-3"}},
+				ReceivedLsn:      "0/5000000",
+				ChecksumFailures: 4,
+			},
+		}}
+
+		setChecksumFailuresBaselines(cluster, &status)
+		Expect(status.Items[0].IsCorrupted()).To(BeFalse())
+		Expect(status.Items[1].Pod.Name).To(Equal("cluster-example-3"))
+		Expect(status.Items[1].IsCorrupted()).To(BeFalse())
+		Expect(status.Items[2].Pod.Name).To(Equal("cluster-example-2"))
+		Expect(status.Items[2].IsCorrupted()).To(BeTrue())
+	})
+
+	It("doesn't rebuild the replicas unless requested", func() {
+		cluster := &apiv1.Cluster{
+			Status: apiv1.ClusterStatus{
+				CurrentPrimary: "cluster-example-2",
+				TargetPrimary:  "cluster-example-2",
+				InstancesReportedState: map[apiv1.PodName]apiv1.InstanceReportedState{
+					"cluster-example-1": {
+						CorruptedSince: now.Add(-15 * time.Minute).Format(time.RFC3339),
+					},
+					"cluster-example-2": {IsPrimary: true},
+				},
+			},
+		}
+
+		toRebuild, requeueAfter := getCorruptedReplicasToRebuild(cluster, now)
+		Expect(toRebuild).To(BeEmpty())
+		Expect(requeueAfter).To(BeZero())
+		Expect(cluster.IsInstanceCordoned("cluster-example-1")).To(BeTrue())
+
+		cluster.Spec.CorruptedReplicas = &apiv1.CorruptedReplicasConfiguration{
+			Rebuild:            true,
+			RebuildGracePeriod: 1200,
+		}
+		toRebuild, requeueAfter = getCorruptedReplicasToRebuild(cluster, now)
+		Expect(toRebuild).To(BeEmpty())
+		Expect(requeueAfter).To(Equal(5 * time.Minute))
+	})
+
+	It("rebuilds the replicas only after the grace period", func() {
+		cluster := &apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				CorruptedReplicas: &apiv1.CorruptedReplicasConfiguration{Rebuild: true},
+			},
+			Status: apiv1.ClusterStatus{
+				CurrentPrimary: "cluster-example-2",
+				TargetPrimary:  "cluster-example-2",
+				InstancesReportedState: map[apiv1.PodName]apiv1.InstanceReportedState{
+					"cluster-example-1": {
+						CorruptedSince: now.Add(-15 * time.Minute).Format(time.RFC3339),
+					},
+					"cluster-example-2": {IsPrimary: true},
+					"cluster-example-3": {
+						CorruptedSince: now.Add(-4 * time.Minute).Format(time.RFC3339),
+					},
+				},
+			},
+		}
+
+		toRebuild, requeueAfter := getCorruptedReplicasToRebuild(cluster, now)
+		Expect(toRebuild).To(Equal([]string{"cluster-example-1"}))
+		Expect(requeueAfter).To(Equal(6 * time.Minute))
+
+		Expect(cluster.IsInstanceCordoned("cluster-example-1")).To(BeTrue())
+		Expect(cluster.IsInstanceCordoned("cluster-example-2")).To(BeFalse())
+	})
+
+	It("never rebuilds the primary", func() {
+		cluster := &apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				CorruptedReplicas: &apiv1.CorruptedReplicasConfiguration{Rebuild: true},
+			},
+			Status: apiv1.ClusterStatus{
+				CurrentPrimary: "cluster-example-1",
+				TargetPrimary:  "cluster-example-3",
+				InstancesReportedState: map[apiv1.PodName]apiv1.InstanceReportedState{
+					"cluster-example-1": {
+						CorruptedSince: now.Add(-15 * time.Minute).Format(time.RFC3339),
+					},
+					"cluster-example-3": {
+						CorruptedSince: now.Add(-15 * time.Minute).Format(time.RFC3339),
+					},
+				},
+			},
+		}
+
+		toRebuild, _ := getCorruptedReplicasToRebuild(cluster, now)
+		Expect(toRebuild).To(BeEmpty())
+		Expect(cluster.IsInstanceCordoned("cluster-example-1")).To(BeFalse())
+	})
+
+	It("switches over from a primary with corrupted data only when requested", func() {
+		ctx := context.TODO()
+		namespace := newFakeNamespace()
+		cluster := newFakeCNPGCluster(namespace)
+		pods := generateFakeClusterPodsWithDefaultClient(cluster, true)
+		cluster.Status.CurrentPrimary = pods[0].Name
+		cluster.Status.TargetPrimary = pods[0].Name
+
+		status := postgres.PostgresqlStatusList{Items: []postgres.PostgresqlStatus{
+			{Pod: pods[0], IsPrimary: true, ChecksumFailures: 1},
+			{Pod: pods[1], ReceivedLsn: "0/6000000"},
+			{Pod: pods[2], ReceivedLsn: "0/5000000"},
+		}}
+		for idx := range status.Items {
+			status.Items[idx].Pod.Status.Conditions = []corev1.PodCondition{
+				{Type: corev1.PodReady, Status: corev1.ConditionTrue},
+			}
+		}
+
+		newPrimary, err := clusterReconciler.setPrimaryAwayFromCorruption(ctx, cluster, status, &status.Items[0])
+		Expect(err).ToNot(HaveOccurred())
+		Expect(newPrimary).To(BeEmpty())
+		Expect(cluster.Status.TargetPrimary).To(Equal(pods[0].Name))
+
+		cluster.Spec.CorruptedReplicas = &apiv1.CorruptedReplicasConfiguration{Rebuild: true}
+		newPrimary, err = clusterReconciler.setPrimaryAwayFromCorruption(ctx, cluster, status, &status.Items[0])
+		Expect(err).ToNot(HaveOccurred())
+		Expect(newPrimary).To(Equal(pods[1].Name))
+		Expect(cluster.Status.TargetPrimary).To(Equal(pods[1].Name))
+	})
+})
+
+var _ = Describe("Stuck primary transitions", func() {
+	now := time.Date(2023, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	It("detects a switchover taking longer than the timeout", func() {
+		cluster := &apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				PrimaryTransition: &apiv1.PrimaryTransitionConfiguration{StuckTimeout: 300},
+			},
+			Status: apiv1.ClusterStatus{
+				CurrentPrimary:         "cluster-example-1",
+				TargetPrimary:          "cluster-example-2",
+				TargetPrimaryTimestamp: now.Add(-4 * time.Minute).Format(metav1.RFC3339Micro),
+			},
+		}
+		Expect(getStuckPrimaryTransitionDuration(cluster, now)).To(BeZero())
+
+		cluster.Status.TargetPrimaryTimestamp = now.Add(-7 * time.Minute).Format(metav1.RFC3339Micro)
+		Expect(getStuckPrimaryTransitionDuration(cluster, now)).To(Equal(7 * time.Minute))
+
+		cluster.Status.TargetPrimaryTimestamp = ""
+		Expect(getStuckPrimaryTransitionDuration(cluster, now)).To(BeZero())
+	})
+
+	It("reports the completion only of a transition which was stuck", func() {
+		cluster := &apiv1.Cluster{Status: apiv1.ClusterStatus{CurrentPrimary: "cluster-example-2"}}
+		setPrimaryTransitionCompletedCondition(cluster)
+		Expect(cluster.Status.Conditions).To(BeEmpty())
+
+		meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
+			Type:   string(apiv1.ConditionPrimaryTransitionStuck),
+			Status: metav1.ConditionTrue,
+			Reason: string(apiv1.ConditionReasonPrimaryTransitionTimedOut),
+		})
+		setPrimaryTransitionCompletedCondition(cluster)
+		Expect(meta.IsStatusConditionFalse(cluster.Status.Conditions,
+			string(apiv1.ConditionPrimaryTransitionStuck))).To(BeTrue())
+	})
+})
+
+var _ = Describe("Failover cooldown", func() {
+	now := time.Date(2023, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	It("computes the remaining cooldown from the last promotion", func() {
+		cluster := &apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{FailoverCooldown: 300},
+			Status: apiv1.ClusterStatus{
+				CurrentPrimary: "cluster-example-2",
+				PrimaryHistory: []apiv1.PrimaryChange{
+					{Timestamp: now.Add(-1 * time.Hour).Format(metav1.RFC3339Micro)},
+					{Timestamp: now.Add(-2 * time.Minute).Format(metav1.RFC3339Micro)},
+				},
+			},
+		}
+		Expect(getFailoverCooldownRemaining(cluster, now)).To(Equal(3 * time.Minute))
+
+		cluster.Spec.FailoverCooldown = 60
+		Expect(getFailoverCooldownRemaining(cluster, now)).To(BeZero())
+
+		cluster.Spec.FailoverCooldown = 0
+		Expect(getFailoverCooldownRemaining(cluster, now)).To(BeZero())
+
+		cluster.Spec.FailoverCooldown = 300
+		cluster.Status.PrimaryHistory = nil
+		Expect(getFailoverCooldownRemaining(cluster, now)).To(BeZero())
+	})
+
+	It("doesn't fail over during the cooldown", func() {
+		ctx := context.TODO()
+		cluster := &apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{FailoverCooldown: 300},
+			Status: apiv1.ClusterStatus{
+				CurrentPrimary: "cluster-example-2",
+				TargetPrimary:  "cluster-example-2",
+				PrimaryHistory: []apiv1.PrimaryChange{
+					{Timestamp: now.Add(-1 * time.Minute).Format(metav1.RFC3339Micro)},
+				},
+			},
+		}
+		Expect(enforceFailoverCooldown(ctx, cluster, now)).To(MatchError(ErrWaitingOnFailoverCooldown))
+		Expect(enforceFailoverCooldown(ctx, cluster, now.Add(5*time.Minute))).To(Succeed())
+	})
+
+	It("reports the cooldown in a condition", func() {
+		cluster := &apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{FailoverCooldown: 300},
+			Status: apiv1.ClusterStatus{
+				CurrentPrimary: "cluster-example-2",
+			},
+		}
+		setFailoverCooldownCondition(cluster, now)
+		Expect(cluster.Status.Conditions).To(BeEmpty())
+
+		cluster.Status.PrimaryHistory = []apiv1.PrimaryChange{
+			{Timestamp: now.Add(-1 * time.Minute).Format(metav1.RFC3339Micro)},
+		}
+		setFailoverCooldownCondition(cluster, now)
+		condition := meta.FindStatusCondition(cluster.Status.Conditions, string(apiv1.ConditionFailoverCooldown))
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Reason).To(Equal(string(apiv1.ConditionReasonFailoverCooldownActive)))
+		Expect(condition.Message).To(ContainSubstring(
+			now.Add(4 * time.Minute).Format(time.RFC3339)))
+
+		setFailoverCooldownCondition(cluster, now.Add(5*time.Minute))
+		Expect(meta.IsStatusConditionFalse(cluster.Status.Conditions,
+			string(apiv1.ConditionFailoverCooldown))).To(BeTrue())
+	})
+})
+
+var _ = Describe("Role labels of the instances", func() {
+	It("sets the primary label only once the primary accepts read-write connections", func() {
+		ctx := context.TODO()
+		namespace := newFakeNamespace()
+		cluster := newFakeCNPGCluster(namespace)
+		pods := generateFakeClusterPods(clusterReconciler.Client, cluster, true)
+		cluster.Status.CurrentPrimary = pods[1].Name
+
+		isAcceptingReadWrite := false
+		instancesStatus := postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				{Pod: pods[1], IsPrimary: true, IsAcceptingReadWrite: &isAcceptingReadWrite},
+			},
+		}
+
+		By("waiting while the new primary is still being promoted")
+		primaryLabelDeferred, err := clusterReconciler.updateRoleLabelsOnPods(
+			ctx, cluster, corev1.PodList{Items: pods}, instancesStatus)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(primaryLabelDeferred).To(BeTrue())
+
+		var pod corev1.Pod
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(&pods[1]), &pod)).To(Succeed())
+		Expect(pod.Labels[specs.ClusterRoleLabelName]).ToNot(Equal(specs.ClusterRoleLabelPrimary))
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(&pods[0]), &pod)).To(Succeed())
+		Expect(pod.Labels[specs.ClusterRoleLabelName]).To(Equal(specs.ClusterRoleLabelReplica))
+
+		By("labeling the new primary once it accepts read-write connections")
+		isAcceptingReadWrite = true
+		primaryLabelDeferred, err = clusterReconciler.updateRoleLabelsOnPods(
+			ctx, cluster, corev1.PodList{Items: pods}, instancesStatus)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(primaryLabelDeferred).To(BeFalse())
+
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(&pods[1]), &pod)).To(Succeed())
+		Expect(pod.Labels[specs.ClusterRoleLabelName]).To(Equal(specs.ClusterRoleLabelPrimary))
+	})
+
+	It("doesn't wait for the designated primary of a replica cluster", func() {
+		ctx := context.TODO()
+		namespace := newFakeNamespace()
+		cluster := newFakeCNPGCluster(namespace, func(cluster *apiv1.Cluster) {
+			cluster.Spec.ReplicaCluster = &apiv1.ReplicaClusterConfiguration{Enabled: true, Source: "origin"}
+		})
+		pods := generateFakeClusterPods(clusterReconciler.Client, cluster, true)
+		cluster.Status.CurrentPrimary = pods[0].Name
+
+		isAcceptingReadWrite := false
+		instancesStatus := postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				{Pod: pods[0], IsAcceptingReadWrite: &isAcceptingReadWrite},
+			},
+		}
+
+		primaryLabelDeferred, err := clusterReconciler.updateRoleLabelsOnPods(
+			ctx, cluster, corev1.PodList{Items: pods}, instancesStatus)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(primaryLabelDeferred).To(BeFalse())
+	})
+})
+
+var _ = Describe("LSN annotation of the instances", func() {
+	It("is refreshed when missing or malformed", func() {
+		Expect(isLSNAnnotationOutdated("", "0/3000060")).To(BeTrue())
+		Expect(isLSNAnnotationOutdated("wrong", "0/3000060")).To(BeTrue())
+	})
+
+	It("is not refreshed until the LSN moved enough", func() {
+		Expect(isLSNAnnotationOutdated("0/3000060", "0/3000060")).To(BeFalse())
+		Expect(isLSNAnnotationOutdated("0/3000060", "0/3FFFFFF")).To(BeFalse())
+		Expect(isLSNAnnotationOutdated("0/3000060", "0/4000060")).To(BeTrue())
+		Expect(isLSNAnnotationOutdated("0/5000060", "0/3000060")).To(BeTrue())
+	})
+})
@@ -0,0 +1,341 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/robfig/cron"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+)
+
+const maintenanceJobOwnerKey = ".metadata.controller.scheduledMaintenance"
+
+// ScheduledMaintenanceReconciler reconciles a ScheduledMaintenance object
+type ScheduledMaintenanceReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+}
+
+// +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=scheduledmaintenances,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=scheduledmaintenances/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+
+// Reconcile is the main reconciler logic
+func (r *ScheduledMaintenanceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	contextLogger, ctx := log.SetupLogger(ctx)
+
+	contextLogger.Debug(fmt.Sprintf("reconciling object %#q", req.NamespacedName))
+
+	defer func() {
+		contextLogger.Debug(fmt.Sprintf("object %#q has been reconciled", req.NamespacedName))
+	}()
+
+	var scheduledMaintenance apiv1.ScheduledMaintenance
+	if err := r.Get(ctx, req.NamespacedName, &scheduledMaintenance); err != nil {
+		if apierrs.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	// Let's record the outcome of the executions we have already started,
+	// and check if one of them is still running
+	childJobs, err := r.getChildJobs(ctx, scheduledMaintenance)
+	if err != nil {
+		contextLogger.Error(err,
+			"Cannot extract the list of created jobs")
+		return ctrl.Result{}, err
+	}
+
+	if err := r.updateLastResult(ctx, &scheduledMaintenance, childJobs); err != nil {
+		if apierrs.IsConflict(err) {
+			// Retry later, the cache is stale
+			contextLogger.Debug("Conflict while updating the scheduled maintenance", "error", err)
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	if scheduledMaintenance.IsSuspended() {
+		contextLogger.Info("Skipping as maintenance is suspended")
+		return ctrl.Result{}, nil
+	}
+
+	// We never run two executions of the same scheduled
+	// maintenance at the same time
+	for i := range childJobs {
+		if getMaintenanceJobResult(&childJobs[i]) == apiv1.MaintenanceResultRunning {
+			contextLogger.Info(
+				"The system is already running this scheduled maintenance, retrying in 60 seconds",
+				"jobName", childJobs[i].Name)
+			return ctrl.Result{RequeueAfter: time.Minute}, nil
+		}
+	}
+
+	return r.reconcileSchedule(ctx, &scheduledMaintenance)
+}
+
+// reconcileSchedule starts a new execution of the scheduled maintenance
+// if its time has come
+func (r *ScheduledMaintenanceReconciler) reconcileSchedule(
+	ctx context.Context,
+	scheduledMaintenance *apiv1.ScheduledMaintenance,
+) (ctrl.Result, error) {
+	contextLogger := log.FromContext(ctx)
+
+	schedule, err := cron.Parse(scheduledMaintenance.GetSchedule())
+	if err != nil {
+		contextLogger.Info("Detected an invalid cron schedule",
+			"schedule", scheduledMaintenance.GetSchedule())
+		return ctrl.Result{}, err
+	}
+
+	now := time.Now()
+
+	if scheduledMaintenance.Status.LastCheckTime == nil {
+		// This is the first time we check this schedule,
+		// let's wait until the first job will be actually
+		// scheduled
+		scheduledMaintenance.Status.LastCheckTime = &metav1.Time{
+			Time: now,
+		}
+		if err := r.Status().Update(ctx, scheduledMaintenance); err != nil {
+			if apierrs.IsConflict(err) {
+				// Retry later, the cache is stale
+				contextLogger.Debug("Conflict while reconciling the scheduled maintenance", "error", err)
+				return ctrl.Result{}, nil
+			}
+			return ctrl.Result{}, err
+		}
+
+		nextTime := schedule.Next(now)
+		contextLogger.Info("Next maintenance schedule", "next", nextTime)
+		r.Recorder.Eventf(scheduledMaintenance, "Normal", "MaintenanceSchedule",
+			"Scheduled first maintenance by %v", nextTime)
+		return ctrl.Result{RequeueAfter: nextTime.Sub(now)}, nil
+	}
+
+	// Let's check if we are supposed to start a new execution
+	nextTime := schedule.Next(scheduledMaintenance.Status.LastCheckTime.Time)
+	contextLogger.Info("Next maintenance schedule", "next", nextTime)
+
+	if now.Before(nextTime) {
+		return ctrl.Result{RequeueAfter: nextTime.Sub(now)}, nil
+	}
+
+	var cluster apiv1.Cluster
+	if err := r.Get(
+		ctx,
+		types.NamespacedName{Name: scheduledMaintenance.Spec.Cluster.Name, Namespace: scheduledMaintenance.Namespace},
+		&cluster,
+	); err != nil {
+		if apierrs.IsNotFound(err) {
+			r.Recorder.Eventf(scheduledMaintenance, "Warning", "ClusterNotFound",
+				"Unknown cluster %v, will retry in 30 seconds", scheduledMaintenance.Spec.Cluster.Name)
+			return ctrl.Result{RequeueAfter: 30 * time.Second}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	// The statements are executed with the superuser account, and
+	// we cannot connect when it's disabled
+	if !cluster.GetEnableSuperuserAccess() {
+		r.Recorder.Event(scheduledMaintenance, "Warning", "SuperuserAccessDisabled",
+			"Cannot run the scheduled maintenance, the superuser access is disabled in the cluster")
+		return ctrl.Result{RequeueAfter: schedule.Next(now).Sub(now)}, nil
+	}
+
+	return r.createJob(ctx, &cluster, scheduledMaintenance, nextTime, now, schedule)
+}
+
+// createJob creates the job running a scheduled maintenance, updating
+// the ScheduledMaintenance accordingly
+func (r *ScheduledMaintenanceReconciler) createJob(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	scheduledMaintenance *apiv1.ScheduledMaintenance,
+	maintenanceTime time.Time,
+	now time.Time,
+	schedule cron.Schedule,
+) (ctrl.Result, error) {
+	contextLogger := log.FromContext(ctx)
+
+	// Let's have deterministic names to avoid creating the job two
+	// times
+	name := fmt.Sprintf("%s-%d", scheduledMaintenance.Name, maintenanceTime.Unix())
+	job := specs.CreateScheduledMaintenanceJob(*cluster, *scheduledMaintenance, name)
+
+	contextLogger.Info("Creating maintenance job",
+		"jobName", job.Name,
+		"host", specs.GetMaintenanceHost(*cluster, *scheduledMaintenance))
+	if err := r.Create(ctx, job); err != nil && !apierrs.IsAlreadyExists(err) {
+		contextLogger.Error(err, "Error while creating maintenance job", "jobName", job.Name)
+		r.Recorder.Event(scheduledMaintenance, "Warning", "MaintenanceCreation",
+			"Error while creating maintenance job")
+		return ctrl.Result{}, err
+	}
+
+	scheduledMaintenance.Status.LastCheckTime = &metav1.Time{
+		Time: now,
+	}
+	scheduledMaintenance.Status.LastScheduleTime = &metav1.Time{
+		Time: maintenanceTime,
+	}
+	nextMaintenanceTime := schedule.Next(now)
+	scheduledMaintenance.Status.NextScheduleTime = &metav1.Time{
+		Time: nextMaintenanceTime,
+	}
+	scheduledMaintenance.Status.LastJobName = job.Name
+	scheduledMaintenance.Status.LastResult = apiv1.MaintenanceResultRunning
+
+	if err := r.Status().Update(ctx, scheduledMaintenance); err != nil {
+		if apierrs.IsConflict(err) {
+			// Retry later, the cache is stale
+			contextLogger.Debug("Conflict while updating scheduled maintenance", "error", err)
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	r.Recorder.Eventf(scheduledMaintenance, "Normal", "MaintenanceSchedule",
+		"Next maintenance scheduled by %v", nextMaintenanceTime)
+	return ctrl.Result{RequeueAfter: nextMaintenanceTime.Sub(now)}, nil
+}
+
+// updateLastResult records in the status the outcome of the latest execution
+func (r *ScheduledMaintenanceReconciler) updateLastResult(
+	ctx context.Context,
+	scheduledMaintenance *apiv1.ScheduledMaintenance,
+	childJobs []batchv1.Job,
+) error {
+	lastJobName := scheduledMaintenance.Status.LastJobName
+	if lastJobName == "" || scheduledMaintenance.Status.LastResult != apiv1.MaintenanceResultRunning {
+		return nil
+	}
+
+	for i := range childJobs {
+		if childJobs[i].Name != lastJobName {
+			continue
+		}
+
+		result := getMaintenanceJobResult(&childJobs[i])
+		if result == apiv1.MaintenanceResultRunning {
+			return nil
+		}
+
+		scheduledMaintenance.Status.LastResult = result
+		if err := r.Status().Update(ctx, scheduledMaintenance); err != nil {
+			return err
+		}
+
+		if result == apiv1.MaintenanceResultFailed {
+			r.Recorder.Eventf(scheduledMaintenance, "Warning", "MaintenanceFailed",
+				"Maintenance job %v failed", lastJobName)
+		} else {
+			r.Recorder.Eventf(scheduledMaintenance, "Normal", "MaintenanceCompleted",
+				"Maintenance job %v completed", lastJobName)
+		}
+		return nil
+	}
+
+	return nil
+}
+
+// getMaintenanceJobResult gets the outcome of a maintenance job
+func getMaintenanceJobResult(job *batchv1.Job) apiv1.MaintenanceResult {
+	for _, condition := range job.Status.Conditions {
+		if condition.Status != corev1.ConditionTrue {
+			continue
+		}
+
+		switch condition.Type {
+		case batchv1.JobComplete:
+			return apiv1.MaintenanceResultSucceeded
+		case batchv1.JobFailed:
+			return apiv1.MaintenanceResultFailed
+		}
+	}
+
+	return apiv1.MaintenanceResultRunning
+}
+
+// getChildJobs gets all the jobs created by a certain scheduled maintenance
+func (r *ScheduledMaintenanceReconciler) getChildJobs(
+	ctx context.Context,
+	scheduledMaintenance apiv1.ScheduledMaintenance,
+) ([]batchv1.Job, error) {
+	var childJobs batchv1.JobList
+
+	if err := r.List(ctx, &childJobs,
+		client.InNamespace(scheduledMaintenance.Namespace),
+		client.MatchingFields{maintenanceJobOwnerKey: scheduledMaintenance.Name},
+	); err != nil {
+		return nil, fmt.Errorf("unable to list child jobs resource: %w", err)
+	}
+
+	return childJobs.Items, nil
+}
+
+// SetupWithManager install this controller in the controller manager
+func (r *ScheduledMaintenanceReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager) error {
+	// Create a new indexed field on jobs. This field will be used to easily
+	// find all the jobs created by this controller
+	if err := mgr.GetFieldIndexer().IndexField(
+		ctx,
+		&batchv1.Job{},
+		maintenanceJobOwnerKey, func(rawObj client.Object) []string {
+			job := rawObj.(*batchv1.Job)
+			owner := metav1.GetControllerOf(job)
+			if owner == nil {
+				return nil
+			}
+
+			if owner.Kind != apiv1.ScheduledMaintenanceKind {
+				return nil
+			}
+
+			if owner.APIVersion != apiGVString {
+				return nil
+			}
+
+			return []string{owner.Name}
+		}); err != nil {
+		return err
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&apiv1.ScheduledMaintenance{}).
+		Owns(&batchv1.Job{}).
+		Complete(r)
+}
@@ -0,0 +1,184 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package checkobjectstore implement the check-object-store command,
+// verifying that the object store of a cluster can be reached with the
+// configured credentials before any WAL file needs to be archived
+package checkobjectstore
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/walarchive"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/archiver"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/credentials"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/stringset"
+)
+
+// NewCmd creates the new cobra command
+func NewCmd() *cobra.Command {
+	var clusterName string
+	var namespace string
+	var objectStore string
+
+	cmd := cobra.Command{
+		Use:           "check-object-store",
+		SilenceErrors: true,
+		Args:          cobra.NoArgs,
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			const logErrorMessage = "failed to run check-object-store command"
+
+			contextLog := log.WithName("check-object-store")
+			ctx := log.IntoContext(cobraCmd.Context(), contextLog)
+
+			typedClient, err := management.NewControllerRuntimeClient()
+			if err != nil {
+				contextLog.Error(err, "creating controller-runtine client")
+				return err
+			}
+
+			err = run(ctx, typedClient, client.ObjectKey{Namespace: namespace, Name: clusterName}, objectStore)
+			if err != nil {
+				contextLog.Error(err, logErrorMessage)
+				fmt.Printf("The object store check failed: %v\n", err)
+				return err
+			}
+
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&clusterName, "cluster-name", os.Getenv("CLUSTER_NAME"), "The name of the "+
+		"cluster whose object store is checked")
+	cmd.Flags().StringVar(&namespace, "namespace", os.Getenv("NAMESPACE"), "The namespace of "+
+		"the cluster")
+	cmd.Flags().StringVar(&objectStore, "object-store", "", "The barmanObjectStore section to be "+
+		"checked, in YAML or JSON format, instead of the one of the cluster")
+
+	return &cmd
+}
+
+func run(ctx context.Context, typedClient client.Client, clusterKey client.ObjectKey, objectStore string) error {
+	contextLog := log.FromContext(ctx)
+
+	var cluster apiv1.Cluster
+	if err := typedClient.Get(ctx, clusterKey, &cluster); err != nil {
+		return fmt.Errorf("failed to get cluster: %w", err)
+	}
+
+	if objectStore != "" {
+		barmanConfiguration, err := decodeObjectStore(objectStore)
+		if err != nil {
+			return err
+		}
+		if err := validateObjectStore(&cluster, barmanConfiguration); err != nil {
+			return err
+		}
+		if cluster.Spec.Backup == nil {
+			cluster.Spec.Backup = &apiv1.BackupConfiguration{}
+		}
+		cluster.Spec.Backup.BarmanObjectStore = barmanConfiguration
+	}
+
+	if cluster.Spec.Backup == nil || cluster.Spec.Backup.BarmanObjectStore == nil {
+		return fmt.Errorf("the cluster has no object store to check")
+	}
+
+	barmanConfiguration := cluster.Spec.Backup.BarmanObjectStore
+	serverName := cluster.GetBackupServerName()
+
+	// The environment is assembled in the same way it's done
+	// for the WAL archiving
+	env, err := credentials.EnvSetBackupCloudCredentials(
+		ctx, typedClient, cluster.Namespace, barmanConfiguration, os.Environ())
+	if apierrs.IsForbidden(err) {
+		return fmt.Errorf("the instances are not allowed to read the secrets containing the credentials, "+
+			"which must be referenced by the cluster: %w", err)
+	}
+	if err != nil {
+		return fmt.Errorf("while getting the credentials of the object store: %w", err)
+	}
+
+	options, err := walarchive.BarmanCloudWalArchiveOptions(barmanConfiguration, cluster.Name)
+	if err != nil {
+		return fmt.Errorf("while getting the options of barman-cloud-wal-archive: %w", err)
+	}
+
+	walArchiver, err := archiver.New(ctx, &cluster, env, walarchive.SpoolDirectory, os.Getenv("PGDATA"))
+	if err != nil {
+		return fmt.Errorf("while creating the archiver: %w", err)
+	}
+
+	if err := walArchiver.TestConnectivity(ctx, options); err != nil {
+		return fmt.Errorf("%w, please check the destination path, the endpoint and the credentials", err)
+	}
+
+	contextLog.Info("Object store reachable",
+		"destinationPath", barmanConfiguration.DestinationPath,
+		"serverName", serverName)
+	fmt.Printf("The object store %s is reachable with the configured credentials, using server name %s\n",
+		barmanConfiguration.DestinationPath, serverName)
+
+	return nil
+}
+
+// decodeObjectStore decodes a barmanObjectStore section, in YAML or in
+// JSON format, rejecting the unknown fields as they are likely typos
+func decodeObjectStore(objectStore string) (*apiv1.BarmanObjectStoreConfiguration, error) {
+	var barmanConfiguration apiv1.BarmanObjectStoreConfiguration
+	if err := yaml.UnmarshalStrict([]byte(objectStore), &barmanConfiguration); err != nil {
+		return nil, fmt.Errorf("while decoding the object store: %w", err)
+	}
+
+	return &barmanConfiguration, nil
+}
+
+// validateObjectStore validates an object store as if it was the backup
+// one of the cluster, only reporting the errors it introduces
+func validateObjectStore(cluster *apiv1.Cluster, barmanConfiguration *apiv1.BarmanObjectStoreConfiguration) error {
+	existingErrors := stringset.New()
+	for _, fieldErr := range cluster.Validate() {
+		existingErrors.Put(fieldErr.Error())
+	}
+
+	candidate := cluster.DeepCopy()
+	if candidate.Spec.Backup == nil {
+		candidate.Spec.Backup = &apiv1.BackupConfiguration{}
+	}
+	candidate.Spec.Backup.BarmanObjectStore = barmanConfiguration
+
+	var errs []string
+	for _, fieldErr := range candidate.Validate() {
+		if !existingErrors.Has(fieldErr.Error()) {
+			errs = append(errs, fieldErr.Error())
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid object store: %s", strings.Join(errs, "; "))
+	}
+
+	return nil
+}
@@ -0,0 +1,95 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package checkobjectstore
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("object store decoding", func() {
+	It("decodes a YAML object store", func() {
+		objectStore, err := decodeObjectStore(`
+destinationPath: s3://backups/
+s3Credentials:
+  inheritFromIAMRole: true
+`)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(objectStore.DestinationPath).To(Equal("s3://backups/"))
+		Expect(objectStore.AWS.InheritFromIAMRole).To(BeTrue())
+	})
+
+	It("decodes a JSON object store", func() {
+		objectStore, err := decodeObjectStore(
+			`{"destinationPath":"s3://backups/","s3Credentials":{"inheritFromIAMRole":true}}`)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(objectStore.DestinationPath).To(Equal("s3://backups/"))
+	})
+
+	It("rejects the unknown fields", func() {
+		_, err := decodeObjectStore(`
+destinationPath: s3://backups/
+s3Credential:
+  inheritFromIAMRole: true
+`)
+		Expect(err).To(MatchError(ContainSubstring("s3Credential")))
+	})
+})
+
+var _ = Describe("object store validation", func() {
+	newCluster := func() *apiv1.Cluster {
+		return &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+			Spec: apiv1.ClusterSpec{
+				Instances: 3,
+			},
+		}
+	}
+
+	It("accepts a valid object store", func() {
+		Expect(validateObjectStore(newCluster(), &apiv1.BarmanObjectStoreConfiguration{
+			DestinationPath: "s3://backups/",
+			BarmanCredentials: apiv1.BarmanCredentials{
+				AWS: &apiv1.S3Credentials{InheritFromIAMRole: true},
+			},
+		})).To(Succeed())
+	})
+
+	It("rejects an object store without credentials", func() {
+		err := validateObjectStore(newCluster(), &apiv1.BarmanObjectStoreConfiguration{
+			DestinationPath: "s3://backups/",
+		})
+		Expect(err).To(MatchError(ContainSubstring("missing credentials")))
+	})
+
+	It("ignores the errors not caused by the object store", func() {
+		cluster := newCluster()
+		cluster.Spec.ImageName = "postgres:not-a-version"
+		Expect(cluster.Validate()).ToNot(BeEmpty())
+
+		Expect(validateObjectStore(cluster, &apiv1.BarmanObjectStoreConfiguration{
+			DestinationPath: "s3://backups/",
+			BarmanCredentials: apiv1.BarmanCredentials{
+				AWS: &apiv1.S3Credentials{InheritFromIAMRole: true},
+			},
+		})).To(Succeed())
+	})
+})
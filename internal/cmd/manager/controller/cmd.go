@@ -20,6 +20,8 @@ import (
 	"time"
 
 	"github.com/spf13/cobra"
+
+	"github.com/cloudnative-pg/cloudnative-pg/controllers"
 )
 
 // NewCmd create a new cobra command
@@ -28,10 +30,18 @@ func NewCmd() *cobra.Command {
 	var leaderElectionEnable bool
 	var configMapName string
 	var secretName string
+	var watchNamespaces string
 	var port int
 	var pprofHTTPServer bool
 	var leaderLeaseDuration int
 	var leaderRenewDeadline int
+	var leaderRetryPeriod int
+	var reconcileStartupJitter int
+	var reconcileQPS float64
+	var reconcileBurst int
+	var reconcileBaseBackoff int
+	var reconcileMaxBackoff int
+	var reconcileTransientErrorBackoff int
 
 	cmd := cobra.Command{
 		Use: "controller [flags]",
@@ -40,10 +50,20 @@ func NewCmd() *cobra.Command {
 				metricsAddr,
 				configMapName,
 				secretName,
+				watchNamespaces,
 				leaderElectionConfiguration{
 					enable:        leaderElectionEnable,
 					leaseDuration: time.Duration(leaderLeaseDuration) * time.Second,
 					renewDeadline: time.Duration(leaderRenewDeadline) * time.Second,
+					retryPeriod:   time.Duration(leaderRetryPeriod) * time.Second,
+				},
+				controllers.ReconcileLimits{
+					StartupJitter:         time.Duration(reconcileStartupJitter) * time.Second,
+					QPS:                   reconcileQPS,
+					Burst:                 reconcileBurst,
+					BaseBackoff:           time.Duration(reconcileBaseBackoff) * time.Millisecond,
+					MaxBackoff:            time.Duration(reconcileMaxBackoff) * time.Second,
+					TransientErrorBackoff: time.Duration(reconcileTransientErrorBackoff) * time.Second,
 				},
 				pprofHTTPServer,
 				port,
@@ -59,12 +79,35 @@ func NewCmd() *cobra.Command {
 	cmd.Flags().IntVar(&leaderLeaseDuration, "leader-lease-duration", 15,
 		"the leader lease duration expressed in seconds")
 	cmd.Flags().IntVar(&leaderRenewDeadline, "leader-renew-deadline", 10,
-		"the leader renew deadline expressed in seconds")
+		"the leader renew deadline expressed in seconds, must be lower than the lease duration")
+	cmd.Flags().IntVar(&leaderRetryPeriod, "leader-retry-period", 2,
+		"the interval between the attempts to acquire or renew the leadership, expressed in seconds. "+
+			"The renew deadline must be greater than 1.2 times this value")
+
+	cmd.Flags().IntVar(&reconcileStartupJitter, "reconcile-startup-jitter", 0,
+		"the maximum random delay, expressed in seconds, applied to the first reconciliation of "+
+			"each cluster existing when the operator starts. Zero disables it")
+	cmd.Flags().Float64Var(&reconcileQPS, "reconcile-qps", 10,
+		"the number of cluster reconciliations per second allowed after a failure or a requeue")
+	cmd.Flags().IntVar(&reconcileBurst, "reconcile-burst", 100,
+		"the number of cluster reconciliations allowed over reconcile-qps in a burst")
+	cmd.Flags().IntVar(&reconcileBaseBackoff, "reconcile-base-backoff", 5,
+		"the delay, expressed in milliseconds, of the exponential backoff applied after the first failure "+
+			"of a cluster")
+	cmd.Flags().IntVar(&reconcileMaxBackoff, "reconcile-max-backoff", 1000,
+		"the maximum delay, expressed in seconds, of the exponential backoff applied to a failing cluster")
+	cmd.Flags().IntVar(&reconcileTransientErrorBackoff, "reconcile-transient-error-backoff", 0,
+		"the delay, expressed in seconds, after which a cluster failed because of a transient error, "+
+			"like a conflict or a timeout, is reconciled again, without logging it as an error nor "+
+			"increasing the exponential backoff. Zero handles them like any other error")
 
 	cmd.Flags().StringVar(&configMapName, "config-map-name", "", "The name of the ConfigMap containing "+
 		"the operator configuration")
 	cmd.Flags().StringVar(&secretName, "secret-name", "", "The name of the Secret containing "+
 		"the operator configuration. Values are merged with the ConfigMap's one, overwriting them if already defined")
+	cmd.Flags().StringVar(&watchNamespaces, "watch-namespaces", "", "Comma separated list of the namespaces "+
+		"watched by the operator. Takes precedence over the WATCH_NAMESPACE configuration option, "+
+		"and defaults to all namespaces")
 	cmd.Flags().IntVar(&port, "webhook-port", 9443, "The port the controller should be listening on."+
 		" If modified, take care to update the service pointing to it")
 	cmd.Flags().BoolVar(
@@ -19,26 +19,34 @@ package controller
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"errors"
 	"fmt"
 	"net/http"
 	"net/http/pprof"
+	"path/filepath"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/controllers"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/inventory"
 	schemeBuilder "github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/certs"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/webserver"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/multicache"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/stringset"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/versions"
 	// +kubebuilder:scaffold:imports
@@ -75,11 +83,54 @@ const (
 
 )
 
+const (
+	// readinessProbePath is the path of the readiness probe endpoint,
+	// served by the webhook server
+	readinessProbePath = "/readyz"
+
+	// livenessProbePath is the path of the liveness probe endpoint,
+	// served by the webhook server
+	livenessProbePath = "/healthz"
+
+	// cacheSyncProbeTimeout is the maximum time the readiness probe waits
+	// for the informer caches to be synchronized
+	cacheSyncProbeTimeout = time.Second
+)
+
 // leaderElectionConfiguration contains the leader parameters that will be passed to controllerruntime.Options.
 type leaderElectionConfiguration struct {
 	enable        bool
 	leaseDuration time.Duration
 	renewDeadline time.Duration
+	retryPeriod   time.Duration
+}
+
+// leaderElectionJitterFactor is the jitter factor applied by client-go
+// to the retry period of the leader election
+const leaderElectionJitterFactor = 1.2
+
+// validate checks the relationship between the leader election timings,
+// which is the same enforced by client-go when the leader election starts
+func (config leaderElectionConfiguration) validate() error {
+	if !config.enable {
+		return nil
+	}
+
+	if config.retryPeriod <= 0 {
+		return fmt.Errorf("the leader retry period must be greater than zero")
+	}
+
+	if config.leaseDuration <= config.renewDeadline {
+		return fmt.Errorf("the leader lease duration (%v) must be greater than the renew deadline (%v)",
+			config.leaseDuration, config.renewDeadline)
+	}
+
+	if float64(config.renewDeadline) <= leaderElectionJitterFactor*float64(config.retryPeriod) {
+		return fmt.Errorf("the leader renew deadline (%v) must be greater than %v times the retry period (%v)",
+			config.renewDeadline, leaderElectionJitterFactor, config.retryPeriod)
+	}
+
+	return nil
 }
 
 // RunController is the main procedure of the operator, and is used as the
@@ -91,8 +142,10 @@ type leaderElectionConfiguration struct {
 func RunController(
 	metricsAddr,
 	configMapName,
-	secretName string,
+	secretName,
+	watchNamespaces string,
 	leaderConfig leaderElectionConfiguration,
+	reconcileLimits controllers.ReconcileLimits,
 	pprofDebug bool,
 	port int,
 ) error {
@@ -102,6 +155,11 @@ func RunController(
 		"version", versions.Version,
 		"build", versions.Info)
 
+	if err := leaderConfig.validate(); err != nil {
+		setupLog.Error(err, "invalid leader election configuration")
+		return err
+	}
+
 	if pprofDebug {
 		startPprofDebugServer(ctx)
 	}
@@ -113,6 +171,7 @@ func RunController(
 		LeaderElection:     leaderConfig.enable,
 		LeaseDuration:      &leaderConfig.leaseDuration,
 		RenewDeadline:      &leaderConfig.renewDeadline,
+		RetryPeriod:        &leaderConfig.retryPeriod,
 		LeaderElectionID:   LeaderElectionID,
 		CertDir:            defaultWebhookCertDir,
 		// LeaderElectionReleaseOnCancel defines if the leader should step down voluntarily
@@ -128,54 +187,72 @@ func RunController(
 		LeaderElectionReleaseOnCancel: true,
 	}
 
-	if configuration.Current.WatchNamespace != "" {
-		namespaces := configuration.Current.WatchedNamespaces()
-		managerOptions.NewCache = multicache.DelegatingMultiNamespacedCacheBuilder(
-			namespaces,
-			configuration.Current.OperatorNamespace)
-		setupLog.Info("Listening for changes", "watchNamespaces", namespaces)
-	} else {
-		setupLog.Info("Listening for changes on all namespaces")
-	}
-
 	if configuration.Current.WebhookCertDir != "" {
 		// If OLM will generate certificates for us, let's just
 		// use those
 		managerOptions.CertDir = configuration.Current.WebhookCertDir
 	}
 
-	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), managerOptions)
-	if err != nil {
-		setupLog.Error(err, "unable to start manager")
-		return err
-	}
-
-	if configuration.Current.WebhookCertDir != "" {
-		// Use certificate names compatible with OLM
-		mgr.GetWebhookServer().CertName = "apiserver.crt"
-		mgr.GetWebhookServer().KeyName = "apiserver.key"
-	} else {
-		mgr.GetWebhookServer().CertName = "tls.crt"
-		mgr.GetWebhookServer().KeyName = "tls.key"
-	}
+	restConfig := ctrl.GetConfigOrDie()
 
 	// kubeClient is the kubernetes client set with
 	// support for the apiextensions that is used
 	// during the initialization of the operator
 	// kubeClient client.Client
-	kubeClient, err := client.New(mgr.GetConfig(), client.Options{Scheme: scheme})
+	kubeClient, err := client.New(restConfig, client.Options{Scheme: scheme})
 	if err != nil {
 		setupLog.Error(err, "unable to create Kubernetes client")
 		return err
 	}
 
+	// The configuration is loaded before creating the manager, as
+	// the watched namespaces define the scope of its cache
 	err = loadConfiguration(ctx, kubeClient, configMapName, secretName)
 	if err != nil {
 		return err
 	}
 
+	if err := configuration.Current.Validate(); err != nil {
+		setupLog.Error(err, "invalid operator configuration")
+		return err
+	}
+
+	// The command line flag takes precedence over the operator configuration
+	if watchNamespaces != "" {
+		configuration.Current.WatchNamespace = watchNamespaces
+	}
+
 	setupLog.Info("Operator configuration loaded", "configuration", configuration.Current)
 
+	if configuration.Current.WatchNamespace != "" {
+		// The secrets of the credentials namespaces are copied
+		// by the clusters, so they need to be watched too
+		namespaces := stringset.From(append(
+			configuration.Current.WatchedNamespaces(),
+			configuration.Current.GetCredentialsNamespaces()...)).ToList()
+		managerOptions.NewCache = multicache.DelegatingMultiNamespacedCacheBuilder(
+			namespaces,
+			configuration.Current.OperatorNamespace)
+		setupLog.Info("Listening for changes", "watchNamespaces", namespaces)
+	} else {
+		setupLog.Info("Listening for changes on all namespaces")
+	}
+
+	mgr, err := ctrl.NewManager(restConfig, managerOptions)
+	if err != nil {
+		setupLog.Error(err, "unable to start manager")
+		return err
+	}
+
+	if configuration.Current.WebhookCertDir != "" {
+		// Use certificate names compatible with OLM
+		mgr.GetWebhookServer().CertName = "apiserver.crt"
+		mgr.GetWebhookServer().KeyName = "apiserver.key"
+	} else {
+		mgr.GetWebhookServer().CertName = "tls.crt"
+		mgr.GetWebhookServer().KeyName = "tls.key"
+	}
+
 	discoveryClient, err := utils.GetDiscoveryClient()
 	if err != nil {
 		return err
@@ -208,7 +285,8 @@ func RunController(
 		return err
 	}
 
-	if err = controllers.NewClusterReconciler(mgr, discoveryClient).SetupWithManager(ctx, mgr); err != nil {
+	clusterReconciler := controllers.NewClusterReconciler(mgr, discoveryClient, reconcileLimits)
+	if err = clusterReconciler.SetupWithManager(ctx, mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Cluster")
 		return err
 	}
@@ -227,6 +305,15 @@ func RunController(
 		return err
 	}
 
+	if err = (&controllers.ScheduledMaintenanceReconciler{
+		Client:   mgr.GetClient(),
+		Scheme:   mgr.GetScheme(),
+		Recorder: mgr.GetEventRecorderFor("cloudnative-pg-scheduledmaintenance"),
+	}).SetupWithManager(ctx, mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ScheduledMaintenance")
+		return err
+	}
+
 	if err = (&controllers.PoolerReconciler{
 		Client:   mgr.GetClient(),
 		Scheme:   mgr.GetScheme(),
@@ -240,6 +327,8 @@ func RunController(
 		setupLog.Error(err, "unable to create webhook", "webhook", "Cluster", "version", "v1")
 		return err
 	}
+	apiv1.SetupClusterScaleWebhookWithManager(mgr)
+	apiv1.SetupClusterStorageWebhookWithManager(mgr)
 
 	if err = (&apiv1.Backup{}).SetupWebhookWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create webhook", "webhook", "Backup", "version", "v1")
@@ -251,12 +340,17 @@ func RunController(
 		return err
 	}
 
+	if err = (&apiv1.ScheduledMaintenance{}).SetupWebhookWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create webhook", "webhook", "ScheduledMaintenance", "version", "v1")
+		return err
+	}
+
 	if err = (&apiv1.Pooler{}).SetupWebhookWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create webhook", "webhook", "Pooler", "version", "v1")
 		return err
 	}
 
-	// Setup the handler used by the readiness and liveliness probe.
+	// Setup the handlers used by the readiness and liveness probes.
 	//
 	// Unfortunately the readiness of the probe is not sufficient for the operator to be
 	// working correctly. The probe may be positive even when:
@@ -267,7 +361,14 @@ func RunController(
 	//
 	// 2. the webhook service and/or the CNI are being updated, e.g. when a POD is
 	//    deleted. In that case we could get a "Connection refused" error message.
-	mgr.GetWebhookServer().WebhookMux.HandleFunc("/readyz", readinessProbeHandler)
+	setupProbeHandlers(mgr.GetWebhookServer(), mgr.GetCache())
+
+	// The inventory API is served by the webhook server too, sharing its
+	// certificate, and reads the clusters from the cache of the manager
+	if configuration.Current.EnableInventoryAPI {
+		inventory.Register(mgr.GetWebhookServer().WebhookMux,
+			inventory.NewHandler(mgr.GetClient(), inventory.NewKubernetesAuthorizer(mgr.GetClient())))
+	}
 
 	// +kubebuilder:scaffold:builder
 
@@ -325,9 +426,85 @@ func loadConfiguration(
 	return nil
 }
 
-// readinessProbeHandler is used to implement the readiness probe handler
-func readinessProbeHandler(w http.ResponseWriter, _r *http.Request) {
-	_, _ = fmt.Fprint(w, "OK")
+// setupProbeHandlers registers the readiness and liveness probe handlers
+// on the webhook server mux. Serving them from the webhook server ensures
+// that the probes fail when the webhook server is not accepting
+// TLS connections.
+//
+// The liveness probe only checks that the process is able to answer,
+// while the readiness probe also requires the informer caches to be
+// synchronized and the webhook certificate to be valid, so that the
+// webhook service doesn't route any request to an operator that is
+// not yet able to serve it
+func setupProbeHandlers(server *webhook.Server, informerCache cache.Cache) {
+	registerProbeHandler(server.WebhookMux, readinessProbePath, &healthz.Handler{
+		Checks: map[string]healthz.Checker{
+			"informer-cache":      newCacheSyncChecker(informerCache),
+			"webhook-certificate": newCertificateChecker(server.CertDir, server.CertName, server.KeyName),
+		},
+	})
+	registerProbeHandler(server.WebhookMux, livenessProbePath, newLivenessProbeHandler())
+}
+
+// newLivenessProbeHandler creates the handler of the liveness probe, which
+// only requires the process to be able to answer
+func newLivenessProbeHandler() http.Handler {
+	return &healthz.Handler{
+		Checks: map[string]healthz.Checker{
+			"ping": healthz.Ping,
+		},
+	}
+}
+
+// registerProbeHandler registers an health handler on the passed path,
+// allowing every single check to be invoked as a sub-path
+func registerProbeHandler(mux *http.ServeMux, probePath string, handler http.Handler) {
+	mux.Handle(probePath, http.StripPrefix(probePath, handler))
+	mux.Handle(probePath+"/", http.StripPrefix(probePath, handler))
+}
+
+// newCacheSyncChecker creates a checker that fails until every informer
+// cache used by the manager has been synchronized
+func newCacheSyncChecker(informerCache cache.Cache) healthz.Checker {
+	return func(req *http.Request) error {
+		ctx, cancel := context.WithTimeout(req.Context(), cacheSyncProbeTimeout)
+		defer cancel()
+
+		if !informerCache.WaitForCacheSync(ctx) {
+			return fmt.Errorf("informer caches are not synchronized yet")
+		}
+
+		return nil
+	}
+}
+
+// newCertificateChecker creates a checker that fails when the webhook
+// server certificate is missing, can't be loaded or is not valid at
+// the time of the check
+func newCertificateChecker(certDir, certName, keyName string) healthz.Checker {
+	return func(_ *http.Request) error {
+		keyPair, err := tls.LoadX509KeyPair(filepath.Join(certDir, certName), filepath.Join(certDir, keyName))
+		if err != nil {
+			return fmt.Errorf("while loading the webhook certificate: %w", err)
+		}
+
+		if len(keyPair.Certificate) == 0 {
+			return fmt.Errorf("the webhook certificate is empty")
+		}
+
+		certificate, err := x509.ParseCertificate(keyPair.Certificate[0])
+		if err != nil {
+			return fmt.Errorf("while parsing the webhook certificate: %w", err)
+		}
+
+		now := time.Now()
+		if now.Before(certificate.NotBefore) || now.After(certificate.NotAfter) {
+			return fmt.Errorf("the webhook certificate is valid only between %v and %v",
+				certificate.NotBefore, certificate.NotAfter)
+		}
+
+		return nil
+	}
 }
 
 // ensurePKI ensures that we have the required PKI infrastructure to make
@@ -342,6 +519,18 @@ func ensurePKI(
 		return nil
 	}
 
+	webhookFailurePolicy, err := configuration.Current.GetWebhookFailurePolicy()
+	if err != nil {
+		setupLog.Error(err, "unable to setup PKI infrastructure")
+		return err
+	}
+
+	webhookNamespaceSelector, err := configuration.Current.GetWebhookNamespaceSelector()
+	if err != nil {
+		setupLog.Error(err, "unable to setup PKI infrastructure")
+		return err
+	}
+
 	// We need to self-manage required PKI infrastructure and install the certificates into
 	// the webhooks configuration
 	pkiConfig := certs.PublicKeyInfrastructure{
@@ -358,8 +547,10 @@ func ensurePKI(
 			"scheduledbackups.postgresql.cnpg.io",
 		},
 		OperatorDeploymentLabelSelector: "app.kubernetes.io/name=cloudnative-pg",
+		WebhookFailurePolicy:            webhookFailurePolicy,
+		WebhookNamespaceSelector:        webhookNamespaceSelector,
 	}
-	err := pkiConfig.Setup(ctx, kubeClient)
+	err = pkiConfig.Setup(ctx, kubeClient)
 	if err != nil {
 		setupLog.Error(err, "unable to setup PKI infrastructure")
 	}
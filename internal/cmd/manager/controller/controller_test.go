@@ -0,0 +1,114 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/certs"
+)
+
+var _ = Describe("leader election configuration", func() {
+	defaultConfig := leaderElectionConfiguration{
+		enable:        true,
+		leaseDuration: 15 * time.Second,
+		renewDeadline: 10 * time.Second,
+		retryPeriod:   2 * time.Second,
+	}
+
+	It("accepts the default values", func() {
+		Expect(defaultConfig.validate()).To(Succeed())
+	})
+
+	It("requires the lease duration to be greater than the renew deadline", func() {
+		config := defaultConfig
+		config.leaseDuration = config.renewDeadline
+		Expect(config.validate()).ToNot(Succeed())
+	})
+
+	It("requires the renew deadline to be greater than the jittered retry period", func() {
+		config := defaultConfig
+		config.retryPeriod = 9 * time.Second
+		Expect(config.validate()).ToNot(Succeed())
+	})
+
+	It("requires a positive retry period", func() {
+		config := defaultConfig
+		config.retryPeriod = 0
+		Expect(config.validate()).ToNot(Succeed())
+	})
+
+	It("doesn't check the values when the leader election is disabled", func() {
+		config := defaultConfig
+		config.enable = false
+		config.leaseDuration = 0
+		Expect(config.validate()).To(Succeed())
+	})
+})
+
+var _ = Describe("webhook certificate readiness check", func() {
+	var certDir string
+
+	BeforeEach(func() {
+		certDir = GinkgoT().TempDir()
+	})
+
+	writeCertificate := func(pair *certs.KeyPair) {
+		Expect(os.WriteFile(filepath.Join(certDir, "tls.crt"), pair.Certificate, 0o600)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(certDir, "tls.key"), pair.Private, 0o600)).To(Succeed())
+	}
+
+	It("fails when the certificate is missing", func() {
+		checker := newCertificateChecker(certDir, "tls.crt", "tls.key")
+		Expect(checker(nil)).ToNot(Succeed())
+	})
+
+	It("succeeds with a valid certificate", func() {
+		ca, err := certs.CreateRootCA("root", "unit-test")
+		Expect(err).ToNot(HaveOccurred())
+		pair, err := ca.CreateAndSignPair("cnpg-webhook-service", certs.CertTypeServer, nil)
+		Expect(err).ToNot(HaveOccurred())
+		writeCertificate(pair)
+
+		checker := newCertificateChecker(certDir, "tls.crt", "tls.key")
+		Expect(checker(nil)).To(Succeed())
+	})
+})
+
+var _ = Describe("probe handlers", func() {
+	It("serves the aggregated checks and every single check", func() {
+		mux := http.NewServeMux()
+		registerProbeHandler(mux, livenessProbePath, newLivenessProbeHandler())
+
+		for _, probePath := range []string{"/healthz", "/healthz/", "/healthz/ping"} {
+			recorder := httptest.NewRecorder()
+			mux.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, probePath, nil))
+			Expect(recorder.Code).To(Equal(http.StatusOK), probePath)
+		}
+
+		recorder := httptest.NewRecorder()
+		mux.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/healthz/unknown", nil))
+		Expect(recorder.Code).To(Equal(http.StatusNotFound))
+	})
+})
@@ -0,0 +1,89 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package checkpoint implement the "instance checkpoint" subcommand of the
+// operator, which forces a checkpoint and a WAL switch on the primary
+package checkpoint
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/url"
+)
+
+// NewCmd create the "instance checkpoint" subcommand
+func NewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "checkpoint",
+		Short: "Issue a CHECKPOINT and switch the WAL file on the primary instance",
+		Long: "Issue a CHECKPOINT and switch the WAL file on the primary instance running " +
+			"in this Pod, so that the current WAL file is archived. The LSN and the name " +
+			"of the switched WAL file are printed in JSON format",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return checkpointSubCommand()
+		},
+	}
+
+	return cmd
+}
+
+func checkpointSubCommand() error {
+	checkpointURL := url.Local(url.PathPgCheckpoint, url.LocalPort)
+	resp, err := http.Get(checkpointURL) // nolint:gosec
+	if err != nil {
+		log.Error(err, "Error while requesting a checkpoint")
+		return err
+	}
+
+	defer func() {
+		err = resp.Body.Close()
+		if err != nil {
+			log.Error(err, "Can't close the connection",
+				"checkpointURL", checkpointURL,
+				"statusCode", resp.StatusCode,
+			)
+		}
+	}()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		log.Error(err, "Error while reading the checkpoint response body",
+			"checkpointURL", checkpointURL,
+			"statusCode", resp.StatusCode,
+		)
+		return err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		log.Info(
+			"Error while requesting a checkpoint",
+			"checkpointURL", checkpointURL,
+			"statusCode", resp.StatusCode,
+			"body", string(body),
+		)
+		return fmt.Errorf("invalid status code: %v", resp.StatusCode)
+	}
+
+	_, err = fmt.Fprintln(os.Stdout, string(body))
+	return err
+}
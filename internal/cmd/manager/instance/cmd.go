@@ -22,12 +22,15 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/instance/checkpoint"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/instance/initdb"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/instance/join"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/instance/pgbasebackup"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/instance/prestop"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/instance/restore"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/instance/run"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/instance/status"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/instance/upgrade"
 )
 
 // NewCmd creates the "instance" command
@@ -46,6 +49,9 @@ func NewCmd() *cobra.Command {
 	cmd.AddCommand(status.NewCmd())
 	cmd.AddCommand(pgbasebackup.NewCmd())
 	cmd.AddCommand(restore.NewCmd())
+	cmd.AddCommand(prestop.NewCmd())
+	cmd.AddCommand(checkpoint.NewCmd())
+	cmd.AddCommand(upgrade.NewCmd())
 
 	return cmd
 }
@@ -19,9 +19,13 @@ package join
 
 import (
 	"context"
+	"fmt"
 	"os"
 
 	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/reference"
 	ctrl "sigs.k8s.io/controller-runtime/pkg/client"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
@@ -123,11 +127,74 @@ func joinSubCommand(ctx context.Context, instance *postgres.Instance, info postg
 
 	reconciler.RefreshSecrets(ctx, &cluster)
 
-	err = info.Join(&cluster)
+	method, err := join(ctx, client, info, &cluster)
 	if err != nil {
 		log.Error(err, "Error joining node")
 		return err
 	}
 
+	recordJoinEvent(ctx, client, &cluster, info.PodName, method)
 	return nil
 }
+
+// join creates the data directory of the new replica with the method
+// chosen in the cluster, returning the method actually used
+func join(
+	ctx context.Context,
+	client ctrl.Client,
+	info postgres.InitInfo,
+	cluster *apiv1.Cluster,
+) (apiv1.ReplicaJoinMethod, error) {
+	if cluster.GetReplicaJoinMethod() == apiv1.ReplicaJoinMethodObjectStore {
+		joined, err := info.JoinFromObjectStore(ctx, client, cluster)
+		if err != nil {
+			return "", err
+		}
+		if joined {
+			return apiv1.ReplicaJoinMethodObjectStore, nil
+		}
+
+		log.Info("No base backup available in the object store, falling back to pg_basebackup")
+	}
+
+	return apiv1.ReplicaJoinMethodPgBaseBackup, info.Join(cluster)
+}
+
+// recordJoinEvent reports on the cluster which method was used to create
+// the data directory of the new replica. The event is created directly,
+// as an event recorder would send it asynchronously, and this process
+// is about to terminate
+func recordJoinEvent(
+	ctx context.Context,
+	client ctrl.Client,
+	cluster *apiv1.Cluster,
+	podName string,
+	method apiv1.ReplicaJoinMethod,
+) {
+	log.Info("Replica joined the cluster", "method", method)
+
+	clusterReference, err := reference.GetReference(management.Scheme, cluster)
+	if err != nil {
+		log.Error(err, "Error while getting the reference to the cluster")
+		return
+	}
+
+	now := metav1.Now()
+	event := &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: cluster.Name + "-",
+			Namespace:    cluster.Namespace,
+		},
+		InvolvedObject: *clusterReference,
+		Reason:         "ReplicaJoined",
+		Message:        fmt.Sprintf("Instance %v joined the cluster via %v", podName, method),
+		Type:           corev1.EventTypeNormal,
+		Source:         corev1.EventSource{Component: "instance-manager"},
+		FirstTimestamp: now,
+		LastTimestamp:  now,
+		Count:          1,
+	}
+	if err := client.Create(ctx, event); err != nil {
+		log.Error(err, "Error while recording the join event")
+	}
+}
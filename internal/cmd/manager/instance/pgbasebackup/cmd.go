@@ -23,10 +23,12 @@ import (
 	"os"
 
 	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ctrl "sigs.k8s.io/controller-runtime/pkg/client"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/management/istio"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/conditions"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/external"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
@@ -106,7 +108,7 @@ func (env *CloneInfo) bootstrapUsingPgbasebackup(ctx context.Context) error {
 		env.info.ApplicationDatabase = cluster.GetApplicationDatabaseName()
 	}
 
-	server, ok := cluster.ExternalCluster(cluster.Spec.Bootstrap.PgBaseBackup.Source)
+	server, ok := cluster.GetPgBaseBackupSource()
 	if !ok {
 		return fmt.Errorf("missing external cluster")
 	}
@@ -114,6 +116,8 @@ func (env *CloneInfo) bootstrapUsingPgbasebackup(ctx context.Context) error {
 	connectionString, pgpass, err := external.ConfigureConnectionToServer(
 		ctx, env.client, env.info.Namespace, &server)
 	if err != nil {
+		env.updateCloneCondition(ctx, &cluster, apiv1.ConditionReasonCloneFailed,
+			fmt.Sprintf("Cannot configure the connection to %s: %v", server.Name, err))
 		return err
 	}
 
@@ -125,10 +129,20 @@ func (env *CloneInfo) bootstrapUsingPgbasebackup(ctx context.Context) error {
 			return err
 		}
 	}
-	err = postgres.ClonePgData(connectionString, env.info.PgData, env.info.PgWal)
+	env.updateCloneCondition(ctx, &cluster, apiv1.ConditionReasonCloneInProgress,
+		fmt.Sprintf("Cloning %s", server.Name))
+	err = postgres.ClonePgDataWithProgress(connectionString, env.info.PgData, env.info.PgWal,
+		func(percentage int) {
+			env.updateCloneCondition(ctx, &cluster, apiv1.ConditionReasonCloneInProgress,
+				fmt.Sprintf("Cloning %s: %d%% completed", server.Name, percentage))
+		})
 	if err != nil {
+		env.updateCloneCondition(ctx, &cluster, apiv1.ConditionReasonCloneFailed,
+			fmt.Sprintf("Cannot clone %s: %v", server.Name, err))
 		return err
 	}
+	env.updateCloneCondition(ctx, &cluster, apiv1.ConditionReasonCloneSucceeded,
+		fmt.Sprintf("The data directory has been cloned from %s", server.Name))
 
 	if cluster.IsReplica() {
 		// TODO: Using a replication slot on replica cluster is not supported (yet?)
@@ -139,6 +153,31 @@ func (env *CloneInfo) bootstrapUsingPgbasebackup(ctx context.Context) error {
 	return env.configureInstanceAsNewPrimary(&cluster)
 }
 
+// updateCloneCondition reports the progress of the clone in the
+// CloneCompleted condition of the cluster. Failing to update the
+// condition doesn't stop the clone, so errors are only logged
+func (env *CloneInfo) updateCloneCondition(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	reason apiv1.ConditionReason,
+	message string,
+) {
+	status := metav1.ConditionFalse
+	if reason == apiv1.ConditionReasonCloneSucceeded {
+		status = metav1.ConditionTrue
+	}
+
+	condition := metav1.Condition{
+		Type:    string(apiv1.ConditionCloneCompleted),
+		Status:  status,
+		Reason:  string(reason),
+		Message: message,
+	}
+	if err := conditions.Update(ctx, env.client, cluster, &condition); err != nil {
+		log.Error(err, "Error while updating the clone condition", "reason", reason)
+	}
+}
+
 // configureInstanceAsNewPrimary sets up this instance as a new primary server, using
 // the configuration created by the user and setting up the global objects as needed
 func (env *CloneInfo) configureInstanceAsNewPrimary(cluster *apiv1.Cluster) error {
@@ -158,5 +197,5 @@ func (env *CloneInfo) configureInstanceAsNewPrimary(cluster *apiv1.Cluster) erro
 	// In the future, when we will support recovering WALs in the
 	// designated primary from an object store, we'll need to use
 	// the environment variables of the recovery object store.
-	return env.info.ConfigureInstanceAfterRestore(cluster, nil)
+	return env.info.ConfigureInstanceAfterRestore(cluster, nil, nil)
 }
@@ -0,0 +1,125 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package upgrade implements the "instance upgrade" subcommand of the
+// operator, which upgrades the data directory of the primary instance
+// to a new PostgreSQL major version
+package upgrade
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	ctrl "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/istio"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+)
+
+// NewCmd creates the "instance upgrade" subcommand
+func NewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "upgrade",
+		Short: "Upgrade the data directory to a new PostgreSQL major version",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return fmt.Errorf("missing subcommand")
+		},
+	}
+
+	cmd.AddCommand(newPrepareCmd())
+	cmd.AddCommand(newExecuteCmd())
+
+	return cmd
+}
+
+// newPrepareCmd creates the "instance upgrade prepare" subcommand, which
+// runs in the image of the previous major version
+func newPrepareCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "prepare [destination]",
+		Short: "Copy the PostgreSQL binaries of this image to be used by pg_upgrade",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			destination := postgres.OldBinariesDirectory
+			if len(args) > 0 {
+				destination = args[0]
+			}
+
+			if err := postgres.CopyBinariesForMajorUpgrade(destination); err != nil {
+				log.Error(err, "Error while copying the PostgreSQL binaries")
+				return err
+			}
+
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+// newExecuteCmd creates the "instance upgrade execute" subcommand, which
+// runs in the image of the new major version
+func newExecuteCmd() *cobra.Command {
+	var clusterName string
+	var namespace string
+	var pgData string
+	var pgWal string
+	var source string
+
+	cmd := &cobra.Command{
+		Use:           "execute [flags]",
+		Short:         "Upgrade the data directory via pg_upgrade",
+		SilenceErrors: true,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return management.WaitKubernetesAPIServer(cmd.Context(), ctrl.ObjectKey{
+				Name:      clusterName,
+				Namespace: namespace,
+			})
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			info := postgres.InitInfo{
+				ClusterName: clusterName,
+				Namespace:   namespace,
+				PgData:      pgData,
+				PgWal:       pgWal,
+			}
+
+			if err := info.MajorUpgrade(cmd.Context(), source); err != nil {
+				log.Error(err, "Error while upgrading the data directory")
+				return err
+			}
+
+			return nil
+		},
+		PostRunE: func(cmd *cobra.Command, args []string) error {
+			return istio.TryInvokeQuitEndpoint(cmd.Context())
+		},
+	}
+
+	cmd.Flags().StringVar(&clusterName, "cluster-name", os.Getenv("CLUSTER_NAME"), "The name of the "+
+		"current cluster in k8s")
+	cmd.Flags().StringVar(&namespace, "namespace", os.Getenv("NAMESPACE"), "The namespace of "+
+		"the cluster and the Pod in k8s")
+	cmd.Flags().StringVar(&pgData, "pg-data", os.Getenv("PGDATA"), "The PGDATA to be upgraded")
+	cmd.Flags().StringVar(&pgWal, "pg-wal", "", "the PGWAL to be used")
+	cmd.Flags().StringVar(&source, "old-binaries", postgres.OldBinariesDirectory, "The directory "+
+		"containing the binaries of the previous PostgreSQL major version")
+
+	return cmd
+}
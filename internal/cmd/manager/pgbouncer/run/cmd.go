@@ -42,6 +42,7 @@ import (
 func NewCmd() *cobra.Command {
 	var (
 		poolerNamespacedName types.NamespacedName
+		serverHost           string
 
 		errorMissingPoolerNamespacedName = fmt.Errorf("missing pooler name or namespace")
 	)
@@ -49,6 +50,7 @@ func NewCmd() *cobra.Command {
 	const (
 		poolerNameEnvVar      = "POOLER_NAME"
 		poolerNamespaceEnvVar = "NAMESPACE"
+		serverHostEnvVar      = "POOLER_SERVER_HOST"
 	)
 
 	cmd := &cobra.Command{
@@ -64,7 +66,7 @@ func NewCmd() *cobra.Command {
 			return nil
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if err := runSubCommand(cmd.Context(), poolerNamespacedName); err != nil {
+			if err := runSubCommand(cmd.Context(), poolerNamespacedName, serverHost); err != nil {
 				log.Error(err, "Error while running manager")
 				return err
 			}
@@ -84,11 +86,17 @@ func NewCmd() *cobra.Command {
 		os.Getenv(poolerNamespaceEnvVar),
 		"The namespace of the cluster and of the Pod in k8s. "+
 			"Defaults to the value of the NAMESPACE environment variable")
+	cmd.Flags().StringVar(
+		&serverHost,
+		"server-host",
+		os.Getenv(serverHostEnvVar),
+		"The name of the cluster service PgBouncer connects to, when empty the default one is used. "+
+			"Defaults to the value of the POOLER_SERVER_HOST environment variable")
 
 	return cmd
 }
 
-func runSubCommand(ctx context.Context, poolerNamespacedName types.NamespacedName) error {
+func runSubCommand(ctx context.Context, poolerNamespacedName types.NamespacedName, serverHost string) error {
 	var err error
 
 	log.Info("Starting CloudNativePG PgBouncer Instance Manager",
@@ -99,7 +107,7 @@ func runSubCommand(ctx context.Context, poolerNamespacedName types.NamespacedNam
 		return fmt.Errorf("while starting the web server: %w", err)
 	}
 
-	reconciler, err := controller.NewPgBouncerReconciler(poolerNamespacedName)
+	reconciler, err := controller.NewPgBouncerReconciler(poolerNamespacedName, serverHost)
 	if err != nil {
 		return fmt.Errorf("while initializing the new reconciler: %w", err)
 	}
@@ -0,0 +1,121 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package purgeobjectstore implement the purge-object-store command, deleting
+// the backups and the WAL files of a cluster from its object store
+package purgeobjectstore
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/credentials"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// NewCmd creates the new cobra command
+func NewCmd() *cobra.Command {
+	var clusterName string
+	var namespace string
+
+	cmd := cobra.Command{
+		Use:           "purge-object-store",
+		SilenceErrors: true,
+		Args:          cobra.NoArgs,
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			const logErrorMessage = "failed to run purge-object-store command"
+
+			contextLog := log.WithName("purge-object-store")
+			ctx := log.IntoContext(cobraCmd.Context(), contextLog)
+
+			typedClient, err := management.NewControllerRuntimeClient()
+			if err != nil {
+				contextLog.Error(err, "creating controller-runtine client")
+				return err
+			}
+
+			err = run(ctx, typedClient, client.ObjectKey{Namespace: namespace, Name: clusterName})
+			if err != nil {
+				contextLog.Error(err, logErrorMessage)
+				return err
+			}
+
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&clusterName, "cluster-name", os.Getenv("CLUSTER_NAME"), "The name of the "+
+		"cluster whose object store is purged")
+	cmd.Flags().StringVar(&namespace, "namespace", os.Getenv("NAMESPACE"), "The namespace of "+
+		"the cluster")
+
+	return &cmd
+}
+
+func run(ctx context.Context, typedClient client.Client, clusterKey client.ObjectKey) error {
+	contextLog := log.FromContext(ctx)
+
+	var cluster apiv1.Cluster
+	if err := typedClient.Get(ctx, clusterKey, &cluster); err != nil {
+		return fmt.Errorf("failed to get cluster: %w", err)
+	}
+
+	if cluster.Spec.Backup == nil || cluster.Spec.Backup.BarmanObjectStore == nil {
+		contextLog.Info("The cluster has no object store, nothing to purge")
+		return nil
+	}
+
+	barmanConfiguration := cluster.Spec.Backup.BarmanObjectStore
+	serverName := cluster.GetBackupServerName()
+
+	env, err := credentials.EnvSetBackupCloudCredentials(
+		ctx, typedClient, cluster.Namespace, barmanConfiguration, os.Environ())
+	if err != nil {
+		return fmt.Errorf("while getting the credentials of the object store: %w", err)
+	}
+
+	backupList, err := barman.GetBackupList(barmanConfiguration, serverName, env)
+	if err != nil {
+		return fmt.Errorf("while getting the backup list: %w", err)
+	}
+
+	// The backups are deleted starting from the oldest one, so that
+	// barman-cloud can remove the WAL files each of them needs
+	for _, backup := range backupList.List {
+		contextLog.Info("Deleting backup",
+			"backupID", backup.ID,
+			"destinationPath", barmanConfiguration.DestinationPath,
+			"serverName", serverName)
+		if err := barman.DeleteBackup(barmanConfiguration, serverName, backup.ID, env); err != nil {
+			return fmt.Errorf("while deleting backup %s: %w", backup.ID, err)
+		}
+	}
+
+	contextLog.Info("Object store purged",
+		"destinationPath", barmanConfiguration.DestinationPath,
+		"serverName", serverName,
+		"deletedBackups", len(backupList.List))
+	fmt.Printf("Deleted %d backups of server %s from %s\n",
+		len(backupList.List), serverName, barmanConfiguration.DestinationPath)
+
+	return nil
+}
@@ -0,0 +1,114 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package walarchive
+
+import (
+	"context"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/cache"
+	cacheClient "github.com/cloudnative-pg/cloudnative-pg/internal/management/cache/client"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/conditions"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/customarchive"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/walg"
+)
+
+// archiveWithCustomCommand archives the WAL file requested by PostgreSQL
+// with the custom command configured in the cluster
+func archiveWithCustomCommand(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	client client.Client,
+	pgData string,
+	walName string,
+) error {
+	return archiveWithBackend(ctx, cluster, client, walName, "custom command", func(env []string) error {
+		return customarchive.Archive(ctx, cluster.Spec.Backup.CustomWalArchive, env, pgData, walName)
+	})
+}
+
+// archiveWithWalG archives the WAL file requested by PostgreSQL with
+// wal-g, in the storage configured in the cluster
+func archiveWithWalG(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	client client.Client,
+	pgData string,
+	walName string,
+) error {
+	return archiveWithBackend(ctx, cluster, client, walName, walg.WalG, func(env []string) error {
+		return walg.WalPush(ctx, env, pgData, walName)
+	})
+}
+
+// archiveWithBackend archives the WAL file requested by PostgreSQL with
+// a backend other than barman, passing it the environment prepared by
+// the instance manager, and updates the ContinuousArchiving condition
+// with the outcome
+func archiveWithBackend(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	client client.Client,
+	walName string,
+	backendName string,
+	archive func(env []string) error,
+) error {
+	contextLog := log.FromContext(ctx)
+
+	env, err := cacheClient.GetEnv(cache.WALArchiveKey)
+	if err != nil {
+		return fmt.Errorf("failed to get envs: %w", err)
+	}
+
+	if err := archive(env); err != nil {
+		condition := metav1.Condition{
+			Type:    string(apiv1.ConditionContinuousArchiving),
+			Status:  metav1.ConditionFalse,
+			Reason:  string(apiv1.ConditionReasonContinuousArchivingFailing),
+			Message: err.Error(),
+		}
+		if errCond := conditions.Update(ctx, client, cluster, &condition); errCond != nil {
+			contextLog.Error(errCond, "Error changing wal archiving condition (wal archiving failed)",
+				"backend", backendName)
+		}
+		return err
+	}
+
+	contextLog.Info("Archived WAL file",
+		"backend", backendName,
+		"walName", walName,
+		"currentPrimary", cluster.Status.CurrentPrimary,
+		"targetPrimary", cluster.Status.TargetPrimary)
+
+	condition := metav1.Condition{
+		Type:    string(apiv1.ConditionContinuousArchiving),
+		Status:  metav1.ConditionTrue,
+		Reason:  string(apiv1.ConditionReasonContinuousArchivingSuccess),
+		Message: "Continuous archiving is working",
+	}
+	if errCond := conditions.Update(ctx, client, cluster, &condition); errCond != nil {
+		contextLog.Error(errCond, "Error while updating wal archiving condition (wal archiving succeeded)",
+			"backend", backendName)
+	}
+
+	return nil
+}
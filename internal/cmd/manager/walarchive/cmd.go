@@ -29,7 +29,10 @@ import (
 	"time"
 
 	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/reference"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
@@ -49,6 +52,10 @@ const (
 	// SpoolDirectory is the directory where we spool the WAL files that
 	// were pre-archived in parallel
 	SpoolDirectory = postgres.ScratchDataDirectory + "/wal-archive-spool"
+
+	// ArchiveSlotsDirectory is the directory containing the lock files
+	// limiting the WAL files being archived at the same time in the Pod
+	ArchiveSlotsDirectory = postgres.ScratchDataDirectory + "/wal-archive-slots"
 )
 
 // NewCmd creates the new cobra command
@@ -106,7 +113,8 @@ func run(ctx context.Context, podName, pgData string, args []string, client clie
 		return fmt.Errorf("failed to get cluster: %w", err)
 	}
 
-	if cluster.Spec.Backup == nil || cluster.Spec.Backup.BarmanObjectStore == nil {
+	if cluster.Spec.Backup == nil || (cluster.Spec.Backup.BarmanObjectStore == nil &&
+		!cluster.Spec.Backup.IsCustomWalArchiveEnabled() && !cluster.Spec.Backup.IsWalGEnabled()) {
 		// Backup not configured, skipping WAL
 		contextLog.Info("Backup not configured, skip WAL archiving",
 			"walName", walName,
@@ -129,6 +137,17 @@ func run(ctx context.Context, podName, pgData string, args []string, client clie
 		}
 	}
 
+	if utils.IsWalArchivingSuspended(&cluster.ObjectMeta) {
+		return skipSuspendedWAL(ctx, cluster, client, pgData, walName)
+	}
+
+	switch {
+	case cluster.Spec.Backup.IsCustomWalArchiveEnabled():
+		return archiveWithCustomCommand(ctx, cluster, client, pgData, walName)
+	case cluster.Spec.Backup.IsWalGEnabled():
+		return archiveWithWalG(ctx, cluster, client, pgData, walName)
+	}
+
 	maxParallel := 1
 	if cluster.Spec.Backup.BarmanObjectStore.Wal != nil {
 		maxParallel = cluster.Spec.Backup.BarmanObjectStore.Wal.MaxParallel
@@ -146,6 +165,9 @@ func run(ctx context.Context, podName, pgData string, args []string, client clie
 	if walArchiver, err = archiver.New(ctx, cluster, env, SpoolDirectory, pgData); err != nil {
 		return fmt.Errorf("while creating the archiver: %w", err)
 	}
+	if wal := cluster.Spec.Backup.BarmanObjectStore.Wal; wal != nil {
+		walArchiver.LimitConcurrency(ArchiveSlotsDirectory, wal.MaxConcurrentArchives)
+	}
 
 	// Step 1: check if this WAL file has not been already archived
 	var isDeletedFromSpool bool
@@ -171,7 +193,12 @@ func run(ctx context.Context, podName, pgData string, args []string, client clie
 		}
 	}
 
-	options, err := barmanCloudWalArchiveOptions(cluster, cluster.Name)
+	// Step 5: in staging mode the WAL file is archived in the background
+	if cluster.Spec.Backup.BarmanObjectStore.Wal.IsStagingEnabled() {
+		return stageWAL(ctx, cluster, pgData, walName)
+	}
+
+	options, err := BarmanCloudWalArchiveOptions(cluster.Spec.Backup.BarmanObjectStore, cluster.Name)
 	if err != nil {
 		log.Error(err, "while getting barman-cloud-wal-archive options")
 		condition := metav1.Condition{
@@ -186,7 +213,7 @@ func run(ctx context.Context, podName, pgData string, args []string, client clie
 		return err
 	}
 
-	// Step 5: archive the WAL files in parallel
+	// Step 6: archive the WAL files in parallel
 	uploadStartTime := time.Now()
 	walStatus := walArchiver.ArchiveList(ctx, walFilesList, options)
 	if len(walStatus) > 1 {
@@ -198,6 +225,15 @@ func run(ctx context.Context, podName, pgData string, args []string, client clie
 			"totalTime", time.Since(startTime))
 	}
 
+	// We return only the first error to PostgreSQL, because the first error
+	// is the one raised by the file that PostgreSQL has requested to archive.
+	// The other errors are related to WAL files that were pre-archived as
+	// a performance optimization and are just logged
+	if walStatus[0].Err != nil {
+		checkObjectStoreConnectivity(ctx, cluster, walArchiver, client, options)
+		return walStatus[0].Err
+	}
+
 	// Update the condition if needed.
 	condition := metav1.Condition{
 		Type:    string(apiv1.ConditionContinuousArchiving),
@@ -208,12 +244,175 @@ func run(ctx context.Context, podName, pgData string, args []string, client clie
 	if errCond := conditions.Update(ctx, client, cluster, &condition); errCond != nil {
 		log.Error(errCond, "Error while updating wal archiving condition (wal archiving succeeded)")
 	}
-	// We return only the first error to PostgreSQL, because the first error
-	// is the one raised by the file that PostgreSQL has requested to archive.
-	// The other errors are related to WAL files that were pre-archived as
-	// a performance optimization and are just logged
 
-	return walStatus[0].Err
+	return nil
+}
+
+// stageWAL copies the WAL file requested by PostgreSQL in the staging
+// directory, from where the instance manager archives it in the
+// background. The archival fails, and PostgreSQL will retry it, when
+// too many WAL files are waiting in the staging directory
+func stageWAL(ctx context.Context, cluster *apiv1.Cluster, pgData string, walName string) error {
+	contextLog := log.FromContext(ctx)
+
+	stagingDirectory, err := archiver.GetStagingDirectory(pgData)
+	if err != nil {
+		return err
+	}
+
+	pendingFiles, err := archiver.CountStagedWALs(stagingDirectory)
+	if err != nil {
+		return fmt.Errorf("while counting the staged WAL files: %w", err)
+	}
+	maxPendingFiles := cluster.Spec.Backup.BarmanObjectStore.Wal.Staging.GetMaxPendingFiles()
+	if pendingFiles >= maxPendingFiles {
+		return fmt.Errorf("the WAL staging directory is full (%d files waiting to be archived), "+
+			"PostgreSQL will retry archiving %v", pendingFiles, walName)
+	}
+
+	walPath := walName
+	if !filepath.IsAbs(walPath) {
+		walPath = filepath.Join(pgData, walPath)
+	}
+	if err := archiver.StageWAL(stagingDirectory, walPath); err != nil {
+		return err
+	}
+
+	contextLog.Info("Staged WAL file",
+		"walName", walName,
+		"pendingFiles", pendingFiles+1,
+		"currentPrimary", cluster.Status.CurrentPrimary,
+		"targetPrimary", cluster.Status.TargetPrimary)
+	return nil
+}
+
+// checkObjectStoreConnectivity is invoked when the WAL file requested by
+// PostgreSQL could not be archived, and tells a misconfigured or unreachable
+// object store apart from a transient failure. In the former case the
+// ContinuousArchiving condition is set straight away and an event is raised
+// on the cluster, while transient failures are left to the retries of
+// PostgreSQL
+func checkObjectStoreConnectivity(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	walArchiver *archiver.WALArchiver,
+	client client.Client,
+	options []string,
+) {
+	contextLog := log.FromContext(ctx)
+
+	// The problem has already been reported, and the condition will
+	// be cleared by the first successful archival
+	currentCondition := meta.FindStatusCondition(
+		cluster.Status.Conditions, string(apiv1.ConditionContinuousArchiving))
+	if currentCondition != nil &&
+		currentCondition.Reason == string(apiv1.ConditionReasonObjectStoreUnreachable) {
+		return
+	}
+
+	err := walArchiver.TestConnectivity(ctx, options)
+	if err == nil {
+		contextLog.Info("The object store is reachable, the WAL archiving failure is transient")
+		return
+	}
+
+	condition := metav1.Condition{
+		Type:    string(apiv1.ConditionContinuousArchiving),
+		Status:  metav1.ConditionFalse,
+		Reason:  string(apiv1.ConditionReasonObjectStoreUnreachable),
+		Message: err.Error(),
+	}
+	if errCond := conditions.Update(ctx, client, cluster, &condition); errCond != nil {
+		contextLog.Error(errCond, "Error changing wal archiving condition (object store unreachable)")
+	}
+
+	recordWarningEvent(ctx, client, cluster, apiv1.ConditionReasonObjectStoreUnreachable,
+		fmt.Sprintf("WAL archiving failed and the object store %v can't be reached: %v",
+			cluster.Spec.Backup.BarmanObjectStore.DestinationPath, err))
+}
+
+// skipSuspendedWAL is invoked instead of archiving a WAL file while the
+// WAL archiving is suspended. The WAL file is recorded, to be archived
+// again once the WAL archiving is resumed, and PostgreSQL is told that
+// the archival succeeded. The ContinuousArchiving condition is set, and
+// an event is raised on the cluster, when the suspension starts
+func skipSuspendedWAL(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	client client.Client,
+	pgData string,
+	walName string,
+) error {
+	contextLog := log.FromContext(ctx)
+
+	if err := archiver.RecordSuspendedWAL(pgData, walName); err != nil {
+		return fmt.Errorf("while recording the WAL file skipped by the suspended archiving: %w", err)
+	}
+
+	contextLog.Warning("WAL archiving is suspended, skipping WAL file",
+		"walName", walName,
+		"currentPrimary", cluster.Status.CurrentPrimary,
+		"targetPrimary", cluster.Status.TargetPrimary)
+
+	// The suspension has already been reported
+	currentCondition := meta.FindStatusCondition(
+		cluster.Status.Conditions, string(apiv1.ConditionContinuousArchiving))
+	if currentCondition != nil &&
+		currentCondition.Reason == string(apiv1.ConditionReasonContinuousArchivingSuspended) {
+		return nil
+	}
+
+	const message = "WAL archiving is suspended by the user, the WAL files are not being archived"
+	condition := metav1.Condition{
+		Type:    string(apiv1.ConditionContinuousArchiving),
+		Status:  metav1.ConditionFalse,
+		Reason:  string(apiv1.ConditionReasonContinuousArchivingSuspended),
+		Message: message,
+	}
+	if errCond := conditions.Update(ctx, client, cluster, &condition); errCond != nil {
+		contextLog.Error(errCond, "Error changing wal archiving condition (wal archiving suspended)")
+	}
+
+	recordWarningEvent(ctx, client, cluster, apiv1.ConditionReasonContinuousArchivingSuspended, message)
+	return nil
+}
+
+// recordWarningEvent reports a problem with the WAL archiving on the
+// cluster. The event is created directly, as an event recorder would
+// send it asynchronously, and this process is about to terminate
+func recordWarningEvent(
+	ctx context.Context,
+	client client.Client,
+	cluster *apiv1.Cluster,
+	reason apiv1.ConditionReason,
+	message string,
+) {
+	contextLog := log.FromContext(ctx)
+
+	clusterReference, err := reference.GetReference(management.Scheme, cluster)
+	if err != nil {
+		contextLog.Error(err, "Error while getting the reference to the cluster")
+		return
+	}
+
+	now := metav1.Now()
+	event := &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: cluster.Name + "-",
+			Namespace:    cluster.Namespace,
+		},
+		InvolvedObject: *clusterReference,
+		Reason:         string(reason),
+		Message:        message,
+		Type:           corev1.EventTypeWarning,
+		Source:         corev1.EventSource{Component: "instance-manager"},
+		FirstTimestamp: now,
+		LastTimestamp:  now,
+		Count:          1,
+	}
+	if err := client.Create(ctx, event); err != nil {
+		contextLog.Error(err, "Error while recording the event", "reason", reason)
+	}
 }
 
 // gatherWALFilesToArchive reads from the archived status the list of WAL files
@@ -289,26 +488,33 @@ func gatherWALFilesToArchive(ctx context.Context, requestedWALFile string, paral
 	return walList
 }
 
-func barmanCloudWalArchiveOptions(
-	cluster *apiv1.Cluster,
+// BarmanCloudWalArchiveOptions gets the options of barman-cloud-wal-archive
+// needed to archive the WAL files of a cluster in an object store
+func BarmanCloudWalArchiveOptions(
+	configuration *apiv1.BarmanObjectStoreConfiguration,
 	clusterName string,
 ) ([]string, error) {
 	capabilities, err := barmanCapabilities.CurrentCapabilities()
 	if err != nil {
 		return nil, err
 	}
-	configuration := cluster.Spec.Backup.BarmanObjectStore
 
 	var options []string
 	if configuration.Wal != nil {
 		if configuration.Wal.Compression == apiv1.CompressionTypeSnappy && !capabilities.HasSnappy {
 			return nil, fmt.Errorf("snappy compression is not supported in Barman %v", capabilities.Version)
 		}
+		if configuration.Wal.Compression == apiv1.CompressionTypeZstd && !capabilities.HasZstd {
+			return nil, fmt.Errorf("zstd compression is not supported in Barman %v", capabilities.Version)
+		}
 		if len(configuration.Wal.Compression) != 0 {
 			options = append(
 				options,
 				fmt.Sprintf("--%v", configuration.Wal.Compression))
 		}
+		if configuration.Wal.Compression == apiv1.CompressionTypeZstd {
+			options = append(options, barman.ZstdCompressionOptions(configuration.Wal.Zstd, capabilities)...)
+		}
 		if len(configuration.Wal.Encryption) != 0 {
 			options = append(
 				options,
@@ -323,20 +529,9 @@ func barmanCloudWalArchiveOptions(
 			configuration.EndpointURL)
 	}
 
-	if len(configuration.Tags) > 0 {
-		tags, err := utils.MapToBarmanTagsFormat("--tags", configuration.Tags)
-		if err != nil {
-			return nil, err
-		}
-		options = append(options, tags...)
-	}
-
-	if len(configuration.HistoryTags) > 0 {
-		historyTags, err := utils.MapToBarmanTagsFormat("--history-tags", configuration.HistoryTags)
-		if err != nil {
-			return nil, err
-		}
-		options = append(options, historyTags...)
+	options, err = barman.AppendTagsOptions(options, configuration, true)
+	if err != nil {
+		return nil, err
 	}
 
 	options, err = barman.AppendCloudProviderOptionsFromConfiguration(options, configuration)
@@ -0,0 +1,203 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package walarchive
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/cache"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/conditions"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/archiver"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// stagingArchiveInterval is how often the staging directory
+// is checked for WAL files to be archived
+const stagingArchiveInterval = 5 * time.Second
+
+// A StagingArchiver is a runner archiving the WAL files which have been
+// copied in the staging directory by the archive_command. It runs on every
+// instance, so that a former primary archives the WAL files it staged
+type StagingArchiver struct {
+	pgData string
+	client client.Client
+}
+
+// NewStagingArchiver creates a new StagingArchiver
+func NewStagingArchiver(pgData string, client client.Client) *StagingArchiver {
+	return &StagingArchiver{
+		pgData: pgData,
+		client: client,
+	}
+}
+
+// Start starts archiving the staged WAL files
+func (sa *StagingArchiver) Start(ctx context.Context) error {
+	contextLog := log.FromContext(ctx).WithName("StagingArchiver")
+	ctx = log.IntoContext(ctx, contextLog)
+
+	ticker := time.NewTicker(stagingArchiveInterval)
+	defer func() {
+		ticker.Stop()
+		contextLog.Info("Terminated staged WAL archiving loop")
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		if err := sa.archiveStagedWALs(ctx); err != nil {
+			contextLog.Warning("Failed archiving the staged WAL files, will retry", "err", err)
+		}
+	}
+}
+
+// archiveStagedWALs archives the WAL files waiting in the staging
+// directory, in batches of up to maxParallel files. Each WAL file is
+// removed from the staging directory once it has been archived
+func (sa *StagingArchiver) archiveStagedWALs(ctx context.Context) error {
+	cachedCluster, err := cache.LoadCluster()
+	if errors.Is(err, cache.ErrCacheMiss) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if cachedCluster.Spec.Backup == nil || cachedCluster.Spec.Backup.BarmanObjectStore == nil ||
+		utils.IsWalArchivingSuspended(&cachedCluster.ObjectMeta) {
+		return nil
+	}
+
+	stagingDirectory, err := archiver.GetStagingDirectory(sa.pgData)
+	if err != nil {
+		return err
+	}
+	walNames, err := archiver.ListStagedWALs(stagingDirectory)
+	if err != nil || len(walNames) == 0 {
+		return err
+	}
+
+	// The condition updates change the cluster, which must
+	// not be done on the cached copy
+	cluster := cachedCluster.DeepCopy()
+	env, err := cache.LoadEnv(cache.WALArchiveKey)
+	if err != nil {
+		return fmt.Errorf("failed to get envs: %w", err)
+	}
+
+	walArchiver, err := archiver.New(ctx, cluster, env, SpoolDirectory, sa.pgData)
+	if err != nil {
+		return fmt.Errorf("while creating the archiver: %w", err)
+	}
+	if wal := cluster.Spec.Backup.BarmanObjectStore.Wal; wal != nil {
+		walArchiver.LimitConcurrency(ArchiveSlotsDirectory, wal.MaxConcurrentArchives)
+	}
+
+	options, err := BarmanCloudWalArchiveOptions(cluster.Spec.Backup.BarmanObjectStore, cluster.Name)
+	if err != nil {
+		return fmt.Errorf("while getting barman-cloud-wal-archive options: %w", err)
+	}
+
+	maxParallel := 1
+	if wal := cluster.Spec.Backup.BarmanObjectStore.Wal; wal != nil && wal.MaxParallel > 1 {
+		maxParallel = wal.MaxParallel
+	}
+
+	for start := 0; start < len(walNames); start += maxParallel {
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		end := start + maxParallel
+		if end > len(walNames) {
+			end = len(walNames)
+		}
+		if err := archiveStagedBatch(ctx, walArchiver, stagingDirectory, walNames[start:end], options); err != nil {
+			checkObjectStoreConnectivity(ctx, cluster, walArchiver, sa.client, options)
+			return err
+		}
+	}
+
+	condition := metav1.Condition{
+		Type:    string(apiv1.ConditionContinuousArchiving),
+		Status:  metav1.ConditionTrue,
+		Reason:  string(apiv1.ConditionReasonContinuousArchivingSuccess),
+		Message: "Continuous archiving is working",
+	}
+	if errCond := conditions.Update(ctx, sa.client, cluster, &condition); errCond != nil {
+		log.FromContext(ctx).Error(errCond,
+			"Error while updating wal archiving condition (wal archiving succeeded)")
+	}
+
+	return nil
+}
+
+// archiveStagedBatch archives a batch of staged WAL files in parallel,
+// returning the first error encountered
+func archiveStagedBatch(
+	ctx context.Context,
+	walArchiver *archiver.WALArchiver,
+	stagingDirectory string,
+	walNames []string,
+	options []string,
+) error {
+	contextLog := log.FromContext(ctx)
+	errs := make([]error, len(walNames))
+
+	var waitGroup sync.WaitGroup
+	for idx := range walNames {
+		waitGroup.Add(1)
+		go func(walIndex int) {
+			defer waitGroup.Done()
+
+			walName := walNames[walIndex]
+			startTime := time.Now()
+			if err := walArchiver.Archive(ctx, filepath.Join(stagingDirectory, walName), options); err != nil {
+				errs[walIndex] = err
+				return
+			}
+			errs[walIndex] = archiver.RemoveStagedWAL(stagingDirectory, walName)
+
+			contextLog.Info("Archived staged WAL file",
+				"walName", walName,
+				"startTime", startTime,
+				"elapsedWalTime", time.Since(startTime))
+		}(idx)
+	}
+	waitGroup.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
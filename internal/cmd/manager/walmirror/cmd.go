@@ -0,0 +1,397 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package walmirror implement the wal-mirror command, copying the WAL
+// files archived by a cluster to the secondary object store
+package walmirror
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/walarchive"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/walrestore"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/archiver"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/credentials"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/restorer"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+const (
+	// workDirectory is the directory where the WAL files are
+	// downloaded before being uploaded to the secondary object store
+	workDirectory = postgres.ScratchDataDirectory + "/wal-mirror"
+
+	// statusUpdateInterval is the number of WAL files copied between
+	// two updates of the progress reported in the cluster status
+	statusUpdateInterval = 32
+)
+
+// NewCmd creates the new cobra command
+func NewCmd() *cobra.Command {
+	var clusterName string
+	var namespace string
+
+	cmd := cobra.Command{
+		Use:           "wal-mirror",
+		SilenceErrors: true,
+		Args:          cobra.NoArgs,
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			const logErrorMessage = "failed to run wal-mirror command"
+
+			contextLog := log.WithName("wal-mirror")
+			ctx := log.IntoContext(cobraCmd.Context(), contextLog)
+
+			typedClient, err := management.NewControllerRuntimeClient()
+			if err != nil {
+				contextLog.Error(err, "creating controller-runtine client")
+				return err
+			}
+
+			err = run(ctx, typedClient, client.ObjectKey{Namespace: namespace, Name: clusterName})
+			if err != nil {
+				contextLog.Error(err, logErrorMessage)
+				return err
+			}
+
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&clusterName, "cluster-name", os.Getenv("CLUSTER_NAME"), "The name of the "+
+		"cluster whose WAL archive is mirrored")
+	cmd.Flags().StringVar(&namespace, "namespace", os.Getenv("NAMESPACE"), "The namespace of "+
+		"the cluster")
+
+	return &cmd
+}
+
+// walMirror copies the WAL files from the object store of a cluster
+// to the secondary one
+type walMirror struct {
+	sourceEnv      []string
+	restorer       *restorer.WALRestorer
+	restoreOptions []string
+	archiver       *archiver.WALArchiver
+	archiveOptions []string
+	segmentSize    *int64
+}
+
+func run(ctx context.Context, typedClient client.Client, clusterKey client.ObjectKey) error {
+	contextLog := log.FromContext(ctx)
+
+	var cluster apiv1.Cluster
+	if err := typedClient.Get(ctx, clusterKey, &cluster); err != nil {
+		return fmt.Errorf("failed to get cluster: %w", err)
+	}
+
+	if !cluster.Spec.Backup.IsWalMirrorEnabled() {
+		contextLog.Info("The mirroring of the WAL archive is not configured, skipping")
+		return nil
+	}
+
+	mirror, err := newWALMirror(ctx, typedClient, &cluster)
+	if err != nil {
+		return err
+	}
+
+	var lastMirroredWAL string
+	if cluster.Status.WalMirror != nil {
+		lastMirroredWAL = cluster.Status.WalMirror.LastMirroredWAL
+	}
+
+	firstBackupWAL, err := getFirstBackupWAL(&cluster, mirror.sourceEnv)
+	if err != nil {
+		return err
+	}
+
+	walName, err := nextWALToMirror(lastMirroredWAL, firstBackupWAL, mirror.segmentSize)
+	if err != nil {
+		return err
+	}
+	if walName == "" {
+		contextLog.Info("No base backup in the object store yet, nothing to mirror")
+		return nil
+	}
+
+	reportProgress := func(lastMirroredWAL string, mirrorErr error) error {
+		return updateMirrorStatus(ctx, typedClient, &cluster, lastMirroredWAL, mirrorErr)
+	}
+
+	return mirror.mirrorFrom(ctx, walName, reportProgress)
+}
+
+// newWALMirror prepares the credentials and the options needed to
+// restore the WAL files from the object store of the cluster and
+// to archive them in the secondary one
+func newWALMirror(ctx context.Context, typedClient client.Client, cluster *apiv1.Cluster) (*walMirror, error) {
+	sourceObjectStore := cluster.Spec.Backup.BarmanObjectStore
+	mirrorObjectStore := &cluster.Spec.Backup.WalMirror.BarmanObjectStore
+
+	// The WAL files are restored from the object store of the cluster
+	// and archived in the secondary one, so their endpoint CAs are
+	// stored where barman-cloud expects them for restore and archive
+	if err := writeEndpointCA(ctx, typedClient, cluster.Namespace, sourceObjectStore.EndpointCA,
+		postgres.BarmanRestoreEndpointCACertificateLocation); err != nil {
+		return nil, err
+	}
+	if err := writeEndpointCA(ctx, typedClient, cluster.Namespace, mirrorObjectStore.EndpointCA,
+		postgres.BarmanBackupEndpointCACertificateLocation); err != nil {
+		return nil, err
+	}
+
+	sourceEnv, err := credentials.EnvSetRestoreCloudCredentials(
+		ctx, typedClient, cluster.Namespace, sourceObjectStore, os.Environ())
+	if err != nil {
+		return nil, fmt.Errorf("while getting the credentials of the object store: %w", err)
+	}
+	mirrorEnv, err := credentials.EnvSetBackupCloudCredentials(
+		ctx, typedClient, cluster.Namespace, mirrorObjectStore, os.Environ())
+	if err != nil {
+		return nil, fmt.Errorf("while getting the credentials of the secondary object store: %w", err)
+	}
+
+	restoreOptions, err := walrestore.BarmanCloudWalRestoreOptions(sourceObjectStore, cluster.Name)
+	if err != nil {
+		return nil, fmt.Errorf("while getting barman-cloud-wal-restore options: %w", err)
+	}
+	archiveOptions, err := walarchive.BarmanCloudWalArchiveOptions(mirrorObjectStore, cluster.Name)
+	if err != nil {
+		return nil, fmt.Errorf("while getting barman-cloud-wal-archive options: %w", err)
+	}
+
+	walRestorer, err := restorer.New(ctx, cluster, sourceEnv, filepath.Join(workDirectory, "restore-spool"))
+	if err != nil {
+		return nil, err
+	}
+	walArchiver, err := archiver.New(ctx, cluster, mirrorEnv, filepath.Join(workDirectory, "archive-spool"),
+		workDirectory)
+	if err != nil {
+		return nil, err
+	}
+
+	var segmentSize *int64
+	if cluster.Status.InitDBSettings != nil && cluster.Status.InitDBSettings.WalSegmentSize != 0 {
+		size := int64(cluster.Status.InitDBSettings.WalSegmentSize) * 1024 * 1024
+		segmentSize = &size
+	}
+
+	return &walMirror{
+		sourceEnv:      sourceEnv,
+		restorer:       walRestorer,
+		restoreOptions: restoreOptions,
+		archiver:       walArchiver,
+		archiveOptions: archiveOptions,
+		segmentSize:    segmentSize,
+	}, nil
+}
+
+// mirrorFrom copies the WAL files starting from the passed one, until
+// the first WAL file which has not been archived yet. The timeline
+// changes are followed through the history files. The progress is
+// periodically reported, so that a failed execution can be resumed
+func (mirror *walMirror) mirrorFrom(
+	ctx context.Context,
+	walName string,
+	reportProgress func(lastMirroredWAL string, mirrorErr error) error,
+) error {
+	contextLog := log.FromContext(ctx)
+
+	var lastMirroredWAL string
+	var mirroredCount int
+	for {
+		err := mirror.copyFile(ctx, walName)
+		if errors.Is(err, restorer.ErrWALNotFound) {
+			// The WAL file may be missing because a new timeline has
+			// been started, which requires the history file to be
+			// copied too
+			walName, err = mirror.followTimelineChange(ctx, walName)
+			if errors.Is(err, restorer.ErrWALNotFound) {
+				break
+			}
+			if err == nil {
+				continue
+			}
+		}
+		if err != nil {
+			if reportErr := reportProgress(lastMirroredWAL, err); reportErr != nil {
+				contextLog.Error(reportErr, "while reporting the progress of the WAL mirroring")
+			}
+			return err
+		}
+
+		lastMirroredWAL = walName
+		mirroredCount++
+		if mirroredCount%statusUpdateInterval == 0 {
+			if err := reportProgress(lastMirroredWAL, nil); err != nil {
+				return err
+			}
+		}
+
+		segment, err := postgres.SegmentFromName(walName)
+		if err != nil {
+			return err
+		}
+		walName = segment.NextSegments(2, nil, mirror.segmentSize)[1].Name()
+	}
+
+	contextLog.Info("Mirrored the WAL archive",
+		"walFilesCount", mirroredCount,
+		"lastMirroredWAL", lastMirroredWAL)
+	return reportProgress(lastMirroredWAL, nil)
+}
+
+// followTimelineChange copies the history file of the timeline following
+// the one of the passed WAL file, returning the name of the same WAL
+// segment in the new timeline
+func (mirror *walMirror) followTimelineChange(ctx context.Context, walName string) (string, error) {
+	segment, err := postgres.SegmentFromName(walName)
+	if err != nil {
+		return "", err
+	}
+
+	segment.Tli++
+	historyFileName := fmt.Sprintf("%08X.history", segment.Tli)
+	if err := mirror.copyFile(ctx, historyFileName); err != nil {
+		return "", err
+	}
+
+	return segment.Name(), nil
+}
+
+// copyFile downloads a file from the WAL archive of the cluster and
+// uploads it to the secondary object store
+func (mirror *walMirror) copyFile(ctx context.Context, fileName string) error {
+	filePath := filepath.Join(workDirectory, fileName)
+	if err := mirror.restorer.Restore(fileName, filePath, mirror.restoreOptions); err != nil {
+		return err
+	}
+	if err := mirror.archiver.Archive(ctx, filePath, mirror.archiveOptions); err != nil {
+		return err
+	}
+	return fileutils.RemoveFile(filePath)
+}
+
+// getFirstBackupWAL gets the first WAL file of the oldest base backup
+// of the cluster, or an empty string if there's no base backup yet
+func getFirstBackupWAL(cluster *apiv1.Cluster, env []string) (string, error) {
+	objectStore := cluster.Spec.Backup.BarmanObjectStore
+	serverName := cluster.Name
+	if objectStore.ServerName != "" {
+		serverName = objectStore.ServerName
+	}
+
+	backupList, err := barman.GetBackupList(objectStore, serverName, env)
+	if err != nil {
+		return "", fmt.Errorf("while getting the backup list: %w", err)
+	}
+
+	for _, backup := range backupList.List {
+		if backup.BeginWal != "" && !backup.EndTime.IsZero() {
+			return backup.BeginWal, nil
+		}
+	}
+
+	return "", nil
+}
+
+// nextWALToMirror gets the first WAL file to be copied, which is the one
+// following the last copied WAL file. The WAL files preceding the oldest
+// base backup are useless for a recovery and may have been removed by
+// the retention policy, so they are skipped
+func nextWALToMirror(lastMirroredWAL, firstBackupWAL string, segmentSize *int64) (string, error) {
+	if firstBackupWAL == "" {
+		return "", nil
+	}
+	if lastMirroredWAL == "" {
+		return firstBackupWAL, nil
+	}
+
+	segment, err := postgres.SegmentFromName(lastMirroredWAL)
+	if err != nil {
+		return "", err
+	}
+
+	nextWAL := segment.NextSegments(2, nil, segmentSize)[1].Name()
+	if nextWAL < firstBackupWAL {
+		return firstBackupWAL, nil
+	}
+
+	return nextWAL, nil
+}
+
+// writeEndpointCA writes the CA bundle of an object store endpoint
+// where barman-cloud expects it
+func writeEndpointCA(
+	ctx context.Context,
+	typedClient client.Client,
+	namespace string,
+	selector *apiv1.SecretKeySelector,
+	destination string,
+) error {
+	if selector == nil {
+		return nil
+	}
+
+	var secret corev1.Secret
+	if err := typedClient.Get(ctx, client.ObjectKey{Namespace: namespace, Name: selector.Name}, &secret); err != nil {
+		return fmt.Errorf("while getting the endpoint CA: %w", err)
+	}
+
+	if err := fileutils.EnsureParentDirectoryExist(destination); err != nil {
+		return err
+	}
+	_, err := fileutils.WriteFileAtomic(destination, secret.Data[selector.Key], 0o600)
+	return err
+}
+
+// updateMirrorStatus reports the progress of the mirroring in the
+// status of the cluster
+func updateMirrorStatus(
+	ctx context.Context,
+	typedClient client.Client,
+	cluster *apiv1.Cluster,
+	lastMirroredWAL string,
+	mirrorErr error,
+) error {
+	origCluster := cluster.DeepCopy()
+	if cluster.Status.WalMirror == nil {
+		cluster.Status.WalMirror = &apiv1.WalMirrorStatus{}
+	}
+
+	if lastMirroredWAL != "" {
+		cluster.Status.WalMirror.LastMirroredWAL = lastMirroredWAL
+		cluster.Status.WalMirror.LastMirrorTime = utils.GetCurrentTimestamp()
+	}
+	cluster.Status.WalMirror.LastError = ""
+	if mirrorErr != nil {
+		cluster.Status.WalMirror.LastError = mirrorErr.Error()
+	}
+
+	return typedClient.Status().Patch(ctx, cluster, client.MergeFrom(origCluster))
+}
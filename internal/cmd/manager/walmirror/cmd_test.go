@@ -0,0 +1,55 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package walmirror
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Choosing the first WAL file to mirror", func() {
+	It("doesn't mirror anything without a base backup", func() {
+		walName, err := nextWALToMirror("", "", nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(walName).To(BeEmpty())
+	})
+
+	It("starts from the oldest base backup the first time", func() {
+		walName, err := nextWALToMirror("", "000000010000000000000004", nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(walName).To(Equal("000000010000000000000004"))
+	})
+
+	It("resumes from the WAL file following the last mirrored one", func() {
+		walName, err := nextWALToMirror("000000010000000000000009", "000000010000000000000004", nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(walName).To(Equal("00000001000000000000000A"))
+	})
+
+	It("honors the size of the WAL segments", func() {
+		segmentSize := int64(1 << 30)
+		walName, err := nextWALToMirror("000000010000000000000003", "000000010000000000000000", &segmentSize)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(walName).To(Equal("000000010000000100000000"))
+	})
+
+	It("skips the WAL files preceding the oldest base backup", func() {
+		walName, err := nextWALToMirror("000000010000000000000002", "000000020000000000000010", nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(walName).To(Equal("000000020000000000000010"))
+	})
+})
@@ -33,6 +33,8 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/restorer"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	postgresManagement "github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/walg"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 )
 
@@ -72,7 +74,7 @@ func NewCmd() *cobra.Command {
 			}
 
 			switch {
-			case errors.Is(err, restorer.ErrWALNotFound):
+			case errors.Is(err, restorer.ErrWALNotFound), errors.Is(err, walg.ErrWALNotFound):
 				// Nothing to log here. The failure has already been logged.
 			case errors.Is(err, ErrNoBackupConfigured):
 				contextLog.Info("tried restoring WALs, but no backup was configured")
@@ -111,6 +113,10 @@ func run(ctx context.Context, podName string, args []string) error {
 		return fmt.Errorf("failed to get cluster: %w", err)
 	}
 
+	if walGConfiguration := GetWalGRecoverConfiguration(cluster, podName); walGConfiguration != nil {
+		return restoreWithWalG(ctx, cluster, walName, destinationPath)
+	}
+
 	recoverClusterName, recoverEnv, barmanConfiguration, err := GetRecoverConfiguration(cluster, podName)
 	if errors.Is(err, ErrNoBackupConfigured) {
 		// Backup not configured, skipping WAL
@@ -125,7 +131,7 @@ func run(ctx context.Context, podName string, args []string) error {
 		return fmt.Errorf("while getting recover configuration: %w", err)
 	}
 
-	options, err := barmanCloudWalRestoreOptions(
+	options, err := BarmanCloudWalRestoreOptions(
 		barmanConfiguration, recoverClusterName)
 	if err != nil {
 		return fmt.Errorf("while getting barman-cloud-wal-restore options: %w", err)
@@ -167,13 +173,16 @@ func run(ctx context.Context, podName string, args []string) error {
 
 	// Step 3: gather the WAL files names to restore. If the required file isn't a regular WAL, we download it directly.
 	var walFilesList []string
-	maxParallel := 1
-	if barmanConfiguration.Wal != nil && barmanConfiguration.Wal.MaxParallel > 1 {
-		maxParallel = barmanConfiguration.Wal.MaxParallel
-	}
+	maxParallel := barmanConfiguration.Wal.GetRestoreMaxParallel()
 	if postgres.IsWALFile(walName) {
-		// If this is a regular WAL file, we try to prefetch
-		if walFilesList, err = gatherWALFilesToRestore(walName, maxParallel); err != nil {
+		// If this is a regular WAL file, we try to prefetch. The names of the
+		// following WAL files depend on the segment size, which we only need
+		// to detect when prefetching
+		var segmentSize *int64
+		if maxParallel > 1 {
+			segmentSize = getWALSegmentSize(ctx)
+		}
+		if walFilesList, err = gatherWALFilesToRestore(walName, maxParallel, segmentSize); err != nil {
 			return fmt.Errorf("while generating the list of WAL files to restore: %w", err)
 		}
 	} else {
@@ -271,6 +280,46 @@ func mergeEnv(env []string, incomingEnv []string) {
 	}
 }
 
+// restoreWithWalG restores the WAL file requested by PostgreSQL with
+// wal-g, using the environment prepared by the instance manager
+func restoreWithWalG(ctx context.Context, cluster *apiv1.Cluster, walName, destinationPath string) error {
+	env, err := cacheClient.GetEnv(cache.WALRestoreKey)
+	if err != nil {
+		return fmt.Errorf("failed to get envs: %w", err)
+	}
+
+	if err := walg.WalFetch(ctx, env, walName, destinationPath); err != nil {
+		return err
+	}
+
+	log.FromContext(ctx).Info("Restored WAL file",
+		"backend", walg.WalG,
+		"walName", walName,
+		"currentPrimary", cluster.Status.CurrentPrimary,
+		"targetPrimary", cluster.Status.TargetPrimary)
+	return nil
+}
+
+// GetWalGRecoverConfiguration gets the wal-g configuration of the storage
+// the WAL files are restored from, if they have been archived by wal-g.
+// The designated primary of a replica cluster restores them from the
+// source cluster, the other instances from the storage of the cluster
+func GetWalGRecoverConfiguration(cluster *apiv1.Cluster, podName string) *apiv1.WalGConfiguration {
+	if cluster.IsReplica() && cluster.Status.CurrentPrimary == podName {
+		externalCluster, found := cluster.ExternalCluster(cluster.Spec.ReplicaCluster.Source)
+		if !found {
+			return nil
+		}
+		return externalCluster.WalG
+	}
+
+	if cluster.Spec.Backup.IsWalGEnabled() {
+		return cluster.Spec.Backup.WalG
+	}
+
+	return nil
+}
+
 // GetRecoverConfiguration get the appropriate recover Configuration for a given cluster
 func GetRecoverConfiguration(
 	cluster *apiv1.Cluster,
@@ -317,9 +366,24 @@ func GetRecoverConfiguration(
 	return "", nil, nil, ErrNoBackupConfigured
 }
 
+// getWALSegmentSize gets the size of the WAL segments of the local data
+// directory, which is needed to compute the names of the WAL files
+// following the requested one. When it can't be detected, nil is
+// returned and the default size is assumed
+func getWALSegmentSize(ctx context.Context) *int64 {
+	size, err := postgresManagement.GetWALSegmentSizeThroughPgControldata(os.Getenv("PGDATA"))
+	if err != nil {
+		log.FromContext(ctx).Info("cannot detect the WAL segment size, assuming the default one",
+			"error", err)
+		return nil
+	}
+
+	return &size
+}
+
 // gatherWALFilesToRestore files a list of possible WAL files to restore, always
 // including as the first one the requested WAL file
-func gatherWALFilesToRestore(walName string, parallel int) (walList []string, err error) {
+func gatherWALFilesToRestore(walName string, parallel int, segmentSize *int64) (walList []string, err error) {
 	var segment postgres.Segment
 
 	segment, err = postgres.SegmentFromName(walName)
@@ -330,9 +394,9 @@ func gatherWALFilesToRestore(walName string, parallel int) (walList []string, er
 		// Let's just avoid prefetching in this case
 		return []string{walName}, nil
 	}
-	// NextSegments would accept postgresVersion and segmentSize,
-	// but we do not have this info here, so we pass nil.
-	segmentList := segment.NextSegments(parallel, nil, nil)
+	// NextSegments would accept postgresVersion, but it only matters
+	// for versions we don't support, so we pass nil.
+	segmentList := segment.NextSegments(parallel, nil, segmentSize)
 	walList = make([]string, len(segmentList))
 	for idx := range segmentList {
 		walList[idx] = segmentList[idx].Name()
@@ -341,7 +405,9 @@ func gatherWALFilesToRestore(walName string, parallel int) (walList []string, er
 	return walList, err
 }
 
-func barmanCloudWalRestoreOptions(
+// BarmanCloudWalRestoreOptions gets the options of barman-cloud-wal-restore
+// needed to restore the WAL files of a cluster from an object store
+func BarmanCloudWalRestoreOptions(
 	configuration *apiv1.BarmanObjectStoreConfiguration,
 	clusterName string,
 ) ([]string, error) {
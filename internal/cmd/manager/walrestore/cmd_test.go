@@ -108,3 +108,62 @@ var _ = Describe("Function isStreamingAvailable", func() {
 		Expect(isStreamingAvailable(&cluster, "primaryPod")).To(BeTrue())
 	})
 })
+
+var _ = Describe("Function gatherWALFilesToRestore", func() {
+	It("assumes the default segment size when it's unknown", func() {
+		Expect(gatherWALFilesToRestore("0000000100000001000000FF", 3, nil)).To(Equal([]string{
+			"0000000100000001000000FF",
+			"000000010000000200000000",
+			"000000010000000200000001",
+		}))
+	})
+
+	It("uses the passed segment size to compute the following WAL files", func() {
+		segmentSize := int64(64 * 1024 * 1024)
+		Expect(gatherWALFilesToRestore("00000001000000010000003F", 3, &segmentSize)).To(Equal([]string{
+			"00000001000000010000003F",
+			"000000010000000200000000",
+			"000000010000000200000001",
+		}))
+	})
+
+	It("doesn't prefetch files which are not regular WAL files", func() {
+		Expect(gatherWALFilesToRestore("00000002.history", 3, nil)).To(Equal([]string{
+			"00000002.history",
+		}))
+	})
+})
+
+var _ = Describe("Function GetWalGRecoverConfiguration", func() {
+	walG := &apiv1.WalGConfiguration{DestinationPath: "s3://bucket/source"}
+
+	It("uses the storage of the source cluster in the designated primary", func() {
+		cluster := apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				ReplicaCluster: &apiv1.ReplicaClusterConfiguration{Enabled: true, Source: "source"},
+				ExternalClusters: []apiv1.ExternalCluster{
+					{Name: "source", WalG: walG},
+				},
+			},
+			Status: apiv1.ClusterStatus{CurrentPrimary: "primaryPod"},
+		}
+		Expect(GetWalGRecoverConfiguration(&cluster, "primaryPod")).To(BeIdenticalTo(walG))
+		Expect(GetWalGRecoverConfiguration(&cluster, "replicaPod")).To(BeNil())
+	})
+
+	It("uses the storage of the cluster when its WAL files are archived by wal-g", func() {
+		cluster := apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				Backup: &apiv1.BackupConfiguration{
+					WalArchiveBackend: apiv1.WalArchiveBackendWalG,
+					WalG:              walG,
+				},
+			},
+			Status: apiv1.ClusterStatus{CurrentPrimary: "primaryPod"},
+		}
+		Expect(GetWalGRecoverConfiguration(&cluster, "replicaPod")).To(BeIdenticalTo(walG))
+
+		cluster.Spec.Backup.WalArchiveBackend = apiv1.WalArchiveBackendBarman
+		Expect(GetWalGRecoverConfiguration(&cluster, "replicaPod")).To(BeNil())
+	})
+})
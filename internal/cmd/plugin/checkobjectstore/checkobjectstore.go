@@ -0,0 +1,105 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package checkobjectstore implements the check-object-store subcommand,
+// verifying the configuration of an object store against the live one
+package checkobjectstore
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// CheckObjectStore implements the check-object-store subcommand, running
+// the check in the primary instance, where the credentials to access the
+// object store are available
+func CheckObjectStore(ctx context.Context, clusterName, fileName string) error {
+	command := []string{"/controller/manager", "check-object-store"}
+	if fileName != "" {
+		objectStore, err := readObjectStore(fileName)
+		if err != nil {
+			return err
+		}
+		command = append(command, "--object-store", objectStore)
+	}
+
+	var cluster apiv1.Cluster
+	err := plugin.Client.Get(ctx, client.ObjectKey{Namespace: plugin.Namespace, Name: clusterName}, &cluster)
+	if err != nil {
+		return fmt.Errorf("cluster %s not found in namespace %s", clusterName, plugin.Namespace)
+	}
+
+	if cluster.Status.CurrentPrimary == "" {
+		return fmt.Errorf("cluster %s has no primary instance to check the object store from", cluster.Name)
+	}
+
+	var pod corev1.Pod
+	err = plugin.Client.Get(ctx, client.ObjectKey{Namespace: plugin.Namespace, Name: cluster.Status.CurrentPrimary},
+		&pod)
+	if err != nil {
+		return fmt.Errorf("while getting the primary instance %s: %w", cluster.Status.CurrentPrimary, err)
+	}
+
+	stdout, _, err := utils.ExecCommand(
+		ctx,
+		kubernetes.NewForConfigOrDie(plugin.Config),
+		plugin.Config,
+		pod,
+		specs.PostgresContainerName,
+		nil,
+		command...)
+
+	// The outcome of the check is reported on the standard output, while
+	// the logs of the instance manager, which are written on the standard
+	// error, are only useful when the command couldn't be run at all
+	if stdout == "" && err != nil {
+		return fmt.Errorf("while checking the object store: %w", err)
+	}
+
+	fmt.Print(stdout)
+	if err != nil {
+		return fmt.Errorf("the object store of cluster %s can't be used", cluster.Name)
+	}
+
+	return nil
+}
+
+// readObjectStore reads a barmanObjectStore section from a file, in YAML
+// or JSON format, converting it to the JSON passed to the instance manager
+func readObjectStore(fileName string) (string, error) {
+	content, err := os.ReadFile(fileName) // #nosec
+	if err != nil {
+		return "", fmt.Errorf("while reading %s: %w", fileName, err)
+	}
+
+	objectStore, err := yaml.YAMLToJSON(content)
+	if err != nil {
+		return "", fmt.Errorf("while decoding %s: %w", fileName, err)
+	}
+
+	return string(objectStore), nil
+}
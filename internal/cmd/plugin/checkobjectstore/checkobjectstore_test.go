@@ -0,0 +1,58 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package checkobjectstore
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("object store file", func() {
+	var tempDir string
+
+	BeforeEach(func() {
+		tempDir = GinkgoT().TempDir()
+	})
+
+	It("is converted to JSON", func() {
+		fileName := filepath.Join(tempDir, "object-store.yaml")
+		Expect(os.WriteFile(fileName, []byte(`
+destinationPath: s3://backups/
+s3Credentials:
+  inheritFromIAMRole: true
+`), 0o600)).To(Succeed())
+
+		Expect(readObjectStore(fileName)).To(MatchJSON(
+			`{"destinationPath":"s3://backups/","s3Credentials":{"inheritFromIAMRole":true}}`))
+	})
+
+	It("must exist", func() {
+		_, err := readObjectStore(filepath.Join(tempDir, "missing.yaml"))
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("must be valid YAML", func() {
+		fileName := filepath.Join(tempDir, "object-store.yaml")
+		Expect(os.WriteFile(fileName, []byte("destinationPath: [s3://backups/"), 0o600)).To(Succeed())
+
+		_, err := readObjectStore(fileName)
+		Expect(err).To(MatchError(ContainSubstring("while decoding")))
+	})
+})
@@ -0,0 +1,47 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package checkobjectstore
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// NewCmd creates the new "check-object-store" subcommand
+func NewCmd() *cobra.Command {
+	checkObjectStoreCmd := &cobra.Command{
+		Use:   "check-object-store [cluster]",
+		Short: "Check that the object store of a cluster can be reached with its credentials",
+		Long: `This command checks, from the primary instance of the cluster named [cluster],
+that its object store can be reached with the configured credentials, in the
+same way it's done before archiving the WAL files. With --file, the
+barmanObjectStore section contained in the given file is validated and
+checked instead of the one of the cluster.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clusterName := args[0]
+			fileName, _ := cmd.Flags().GetString("file")
+
+			return CheckObjectStore(cmd.Context(), clusterName, fileName)
+		},
+	}
+
+	checkObjectStoreCmd.Flags().StringP(
+		"file", "f", "",
+		"A file containing the barmanObjectStore section to be checked, in YAML or JSON format")
+
+	return checkObjectStoreCmd
+}
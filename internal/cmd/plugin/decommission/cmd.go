@@ -0,0 +1,49 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package decommission
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// NewCmd creates the new "decommission" subcommand
+func NewCmd() *cobra.Command {
+	decommissionCmd := &cobra.Command{
+		Use:   "decommission [cluster]",
+		Short: "Delete a cluster, optionally purging its backups from the object store",
+		Long: `This command deletes the cluster named [cluster]. With --purge-object-store,
+the backups and the WAL files of the cluster are deleted from its object store
+before deleting the cluster, unless other clusters use the same destination
+and server name. The command requires --confirm, as none of this can be undone.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clusterName := args[0]
+			confirm, _ := cmd.Flags().GetBool("confirm")
+			purgeObjectStore, _ := cmd.Flags().GetBool("purge-object-store")
+
+			return Decommission(cmd.Context(), clusterName, confirm, purgeObjectStore)
+		},
+	}
+
+	decommissionCmd.Flags().Bool(
+		"confirm", false,
+		"Confirm that the cluster, and its backups when requested, are to be deleted")
+	decommissionCmd.Flags().Bool(
+		"purge-object-store", false,
+		"Delete the backups and the WAL files of the cluster from its object store")
+
+	return decommissionCmd
+}
@@ -0,0 +1,197 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+// Package decommission implements a command to delete a cluster
+// and to purge its backups from the object store
+package decommission
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// objectStoreLocation is the place where a cluster stores, or
+// reads, its backups and WAL files
+type objectStoreLocation struct {
+	destinationPath string
+	serverName      string
+}
+
+// newObjectStoreLocation creates a location ignoring the trailing
+// slash of the destination path
+func newObjectStoreLocation(destinationPath, serverName string) objectStoreLocation {
+	return objectStoreLocation{
+		destinationPath: strings.TrimSuffix(destinationPath, "/"),
+		serverName:      serverName,
+	}
+}
+
+// Decommission implements the decommission subcommand
+func Decommission(ctx context.Context, clusterName string, confirm, purgeObjectStore bool) error {
+	if !confirm {
+		return fmt.Errorf("decommissioning cluster %s cannot be undone, "+
+			"please run this command again with --confirm", clusterName)
+	}
+
+	var cluster apiv1.Cluster
+	err := plugin.Client.Get(ctx, client.ObjectKey{Namespace: plugin.Namespace, Name: clusterName}, &cluster)
+	if err != nil {
+		return fmt.Errorf("cluster %s not found in namespace %s", clusterName, plugin.Namespace)
+	}
+
+	if purgeObjectStore {
+		if err := purge(ctx, &cluster); err != nil {
+			return err
+		}
+	}
+
+	if err := plugin.Client.Delete(ctx, &cluster); err != nil && !apierrs.IsNotFound(err) {
+		return fmt.Errorf("while deleting cluster %s: %w", clusterName, err)
+	}
+
+	fmt.Printf("Cluster %s has been deleted\n", clusterName)
+	return nil
+}
+
+// purge deletes the backups and the WAL files of the cluster from its
+// object store, running barman-cloud in the primary instance, where the
+// credentials to access the object store are available
+func purge(ctx context.Context, cluster *apiv1.Cluster) error {
+	if cluster.Spec.Backup == nil || cluster.Spec.Backup.BarmanObjectStore == nil {
+		return fmt.Errorf("cluster %s has no object store to purge", cluster.Name)
+	}
+
+	clusters, err := listClusters(ctx)
+	if err != nil {
+		return err
+	}
+
+	if users := getObjectStoreUsers(cluster, clusters); len(users) > 0 {
+		return fmt.Errorf("refusing to purge server %s in %s, which is used by other clusters: %s",
+			cluster.GetBackupServerName(),
+			cluster.Spec.Backup.BarmanObjectStore.DestinationPath,
+			strings.Join(users, ", "))
+	}
+
+	if cluster.Status.CurrentPrimary == "" {
+		return fmt.Errorf("cluster %s has no primary instance to purge the object store from", cluster.Name)
+	}
+
+	var pod corev1.Pod
+	err = plugin.Client.Get(ctx, client.ObjectKey{Namespace: plugin.Namespace, Name: cluster.Status.CurrentPrimary},
+		&pod)
+	if err != nil {
+		return fmt.Errorf("while getting the primary instance %s: %w", cluster.Status.CurrentPrimary, err)
+	}
+
+	stdout, _, err := utils.ExecCommand(
+		ctx,
+		kubernetes.NewForConfigOrDie(plugin.Config),
+		plugin.Config,
+		pod,
+		specs.PostgresContainerName,
+		nil,
+		"/controller/manager", "purge-object-store")
+	if err != nil {
+		return fmt.Errorf("while purging the object store: %w", err)
+	}
+
+	fmt.Print(stdout)
+	return nil
+}
+
+// listClusters gets the clusters of every namespace, falling back to
+// the current namespace when the user is not allowed to list them all
+func listClusters(ctx context.Context) ([]apiv1.Cluster, error) {
+	var clusterList apiv1.ClusterList
+	err := plugin.Client.List(ctx, &clusterList)
+	if apierrs.IsForbidden(err) {
+		fmt.Printf("Warning: cannot list the clusters of every namespace, "+
+			"only the ones in namespace %s are checked\n", plugin.Namespace)
+		err = plugin.Client.List(ctx, &clusterList, client.InNamespace(plugin.Namespace))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("while listing the clusters: %w", err)
+	}
+
+	return clusterList.Items, nil
+}
+
+// getObjectStoreUsers gets the names of the other clusters using the
+// object store location of the passed cluster, to store their backups or
+// WAL files, or as the source of a recovery or of a replica cluster
+func getObjectStoreUsers(cluster *apiv1.Cluster, clusters []apiv1.Cluster) []string {
+	location := newObjectStoreLocation(
+		cluster.Spec.Backup.BarmanObjectStore.DestinationPath,
+		cluster.GetBackupServerName())
+
+	var users []string
+	for idx := range clusters {
+		other := &clusters[idx]
+		if other.Namespace == cluster.Namespace && other.Name == cluster.Name {
+			continue
+		}
+
+		for _, otherLocation := range getObjectStoreLocations(other) {
+			if otherLocation == location {
+				users = append(users, fmt.Sprintf("%s/%s", other.Namespace, other.Name))
+				break
+			}
+		}
+	}
+
+	return users
+}
+
+// getObjectStoreLocations gets every object store location a cluster
+// writes to or reads from
+func getObjectStoreLocations(cluster *apiv1.Cluster) []objectStoreLocation {
+	var locations []objectStoreLocation
+	if backup := cluster.Spec.Backup; backup != nil && backup.BarmanObjectStore != nil {
+		locations = append(locations, newObjectStoreLocation(
+			backup.BarmanObjectStore.DestinationPath,
+			cluster.GetBackupServerName()))
+	}
+
+	if cluster.Spec.Backup.IsWalMirrorEnabled() {
+		mirrorObjectStore := cluster.Spec.Backup.WalMirror.BarmanObjectStore
+		serverName := mirrorObjectStore.ServerName
+		if serverName == "" {
+			serverName = cluster.Name
+		}
+		locations = append(locations, newObjectStoreLocation(mirrorObjectStore.DestinationPath, serverName))
+	}
+
+	for _, externalCluster := range cluster.Spec.ExternalClusters {
+		if externalCluster.BarmanObjectStore != nil {
+			locations = append(locations, newObjectStoreLocation(
+				externalCluster.BarmanObjectStore.DestinationPath,
+				externalCluster.GetServerName()))
+		}
+	}
+
+	return locations
+}
@@ -0,0 +1,87 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package decommission
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("cluster decommission", func() {
+	newCluster := func(namespace, name, destinationPath string) apiv1.Cluster {
+		return apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+			Spec: apiv1.ClusterSpec{
+				Backup: &apiv1.BackupConfiguration{
+					BarmanObjectStore: &apiv1.BarmanObjectStoreConfiguration{
+						DestinationPath: destinationPath,
+					},
+				},
+			},
+		}
+	}
+
+	It("requires a confirmation", func() {
+		err := Decommission(context.Background(), "cluster-example", false, true)
+		Expect(err).To(MatchError(ContainSubstring("--confirm")))
+	})
+
+	It("purges a destination shared only by the server name", func() {
+		cluster := newCluster("default", "cluster-example", "s3://backups/")
+		others := []apiv1.Cluster{
+			cluster,
+			newCluster("default", "another-cluster", "s3://backups"),
+			newCluster("other", "cluster-example-2", "s3://backups"),
+		}
+		Expect(getObjectStoreUsers(&cluster, others)).To(BeEmpty())
+	})
+
+	It("refuses to purge the backups other clusters store in the same place", func() {
+		cluster := newCluster("default", "cluster-example", "s3://backups/")
+		other := newCluster("other", "cluster-example", "s3://backups")
+		Expect(getObjectStoreUsers(&cluster, []apiv1.Cluster{cluster, other})).To(
+			Equal([]string{"other/cluster-example"}))
+	})
+
+	It("refuses to purge the backups other clusters recover from", func() {
+		cluster := newCluster("default", "cluster-example", "s3://backups/")
+		restored := newCluster("default", "cluster-restore", "s3://restored-backups/")
+		restored.Spec.ExternalClusters = []apiv1.ExternalCluster{
+			{
+				Name: "cluster-example",
+				BarmanObjectStore: &apiv1.BarmanObjectStoreConfiguration{
+					DestinationPath: "s3://backups/",
+				},
+			},
+		}
+		Expect(getObjectStoreUsers(&cluster, []apiv1.Cluster{restored})).To(
+			Equal([]string{"default/cluster-restore"}))
+	})
+
+	It("honors the server name set in the object store configuration", func() {
+		cluster := newCluster("default", "cluster-example", "s3://backups/")
+		cluster.Spec.Backup.BarmanObjectStore.ServerName = "old-cluster"
+		other := newCluster("default", "old-cluster", "s3://backups/")
+		Expect(getObjectStoreUsers(&cluster, []apiv1.Cluster{other})).To(
+			Equal([]string{"default/old-cluster"}))
+	})
+})
@@ -0,0 +1,57 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package export
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
+)
+
+// NewCmd creates the new "export" subcommand
+func NewCmd() *cobra.Command {
+	exportCmd := &cobra.Command{
+		Use:   "export [cluster]",
+		Short: "Export the configuration of a cluster as a manifest that can be applied again",
+		Long: `This command prints the manifest of the cluster named [cluster], without
+its status and the metadata managed by Kubernetes and by the operator.
+With --recovery, the manifest bootstraps a new cluster restoring the
+backups of the exported one from its object store, as needed to recreate
+it in another Kubernetes cluster. The new cluster archives in the same
+object store with a different server name.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			clusterName := args[0]
+			recovery, _ := cmd.Flags().GetBool("recovery")
+			operatorNamespace, _ := cmd.Flags().GetString("operator-namespace")
+			output, _ := cmd.Flags().GetString("output")
+
+			return Export(cmd.Context(), clusterName, recovery, operatorNamespace, plugin.OutputFormat(output))
+		},
+	}
+
+	exportCmd.Flags().Bool(
+		"recovery", false,
+		"Bootstrap the exported cluster via recovery from the object store of the original one")
+	exportCmd.Flags().String(
+		"operator-namespace", "cnpg-system",
+		"The namespace of the operator, whose configuration defines the object stores referenced by name")
+	exportCmd.Flags().StringP(
+		"output", "o", plugin.OutputFormatYAML, "Output format. One of json|yaml")
+
+	return exportCmd
+}
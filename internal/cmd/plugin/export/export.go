@@ -0,0 +1,205 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package export implements the kubectl-cnpg export command
+package export
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// operatorConfigMapName is the name of the ConfigMap holding the
+// configuration of the operator, where the shared object stores are defined
+const operatorConfigMapName = "cnpg-controller-manager-config"
+
+// recoveredServerNameSuffix is appended to the server name of the original
+// cluster, so that the recovered one doesn't archive in the same location
+const recoveredServerNameSuffix = "-recovered"
+
+// runtimeAnnotations are the annotations describing the runtime state of
+// a cluster, which must not be carried over to the exported manifest
+var runtimeAnnotations = []string{
+	corev1.LastAppliedConfigAnnotation,
+	utils.FencedInstanceAnnotation,
+	utils.ReconciliationLoopAnnotationName,
+	utils.OperatorVersionAnnotationName,
+	utils.DefaultedFieldsAnnotationName,
+}
+
+// Export prints the manifest of a cluster in the requested format
+func Export(
+	ctx context.Context,
+	clusterName string,
+	recovery bool,
+	operatorNamespace string,
+	format plugin.OutputFormat,
+) error {
+	var cluster apiv1.Cluster
+	err := plugin.Client.Get(ctx, client.ObjectKey{Namespace: plugin.Namespace, Name: clusterName}, &cluster)
+	if err != nil {
+		return fmt.Errorf("while getting cluster %s: %w", clusterName, err)
+	}
+
+	exported := cleanCluster(&cluster)
+	if recovery {
+		objectStore, err := getObjectStore(ctx, &cluster, operatorNamespace)
+		if err != nil {
+			return err
+		}
+
+		bootstrapFromObjectStore(exported, &cluster, objectStore)
+		fmt.Fprintf(os.Stderr, "Warning: the exported cluster archives in %s with the %q server name, "+
+			"make sure no other cluster is using it before applying the manifest\n",
+			exported.Spec.Backup.BarmanObjectStore.DestinationPath,
+			exported.Spec.Backup.BarmanObjectStore.ServerName)
+	}
+
+	switch format {
+	case plugin.OutputFormatJSON, plugin.OutputFormatYAML:
+		return plugin.Print(exported, format, os.Stdout)
+	default:
+		return fmt.Errorf("output: %s is not supported by the export CLI", format)
+	}
+}
+
+// cleanCluster creates a copy of the passed cluster without its status and
+// without the metadata set by Kubernetes and by the operator. The namespace
+// is removed too, so that the manifest can be applied in any namespace
+func cleanCluster(cluster *apiv1.Cluster) *apiv1.Cluster {
+	exported := &apiv1.Cluster{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: apiv1.GroupVersion.String(),
+			Kind:       apiv1.ClusterKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        cluster.Name,
+			Labels:      cluster.Labels,
+			Annotations: make(map[string]string, len(cluster.Annotations)),
+		},
+		Spec: *cluster.Spec.DeepCopy(),
+	}
+
+	for key, value := range cluster.Annotations {
+		exported.Annotations[key] = value
+	}
+	for _, annotation := range runtimeAnnotations {
+		delete(exported.Annotations, annotation)
+	}
+	if len(exported.Annotations) == 0 {
+		exported.Annotations = nil
+	}
+
+	return exported
+}
+
+// getObjectStore gets the object store where the passed cluster is
+// archiving its WALs and storing its base backups. An object store
+// referenced by name is looked up in the configuration of the operator
+// running in the passed namespace, and merged with the one of the cluster
+func getObjectStore(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	operatorNamespace string,
+) (*apiv1.BarmanObjectStoreConfiguration, error) {
+	if cluster.Spec.Backup == nil ||
+		(cluster.Spec.Backup.BarmanObjectStore == nil && cluster.Spec.Backup.ObjectStoreName == "") {
+		return nil, fmt.Errorf("cluster %s has no object store configured, it can't be recovered", cluster.Name)
+	}
+
+	if cluster.Spec.Backup.ObjectStoreName == "" {
+		return cluster.Spec.Backup.BarmanObjectStore, nil
+	}
+
+	var configMap corev1.ConfigMap
+	err := plugin.Client.Get(
+		ctx,
+		client.ObjectKey{Namespace: operatorNamespace, Name: operatorConfigMapName},
+		&configMap)
+	if err != nil && !apierrs.IsNotFound(err) {
+		return nil, fmt.Errorf("while getting the operator configuration: %w", err)
+	}
+
+	operatorConfiguration := configuration.NewConfiguration()
+	operatorConfiguration.ReadConfigMap(configMap.Data)
+
+	var objectStore apiv1.BarmanObjectStoreConfiguration
+	if err := operatorConfiguration.GetObjectStore(cluster.Spec.Backup.ObjectStoreName, &objectStore); err != nil {
+		return nil, fmt.Errorf("while getting the object store of cluster %s: %w", cluster.Name, err)
+	}
+
+	return objectStore.MergeWith(cluster.Spec.Backup.BarmanObjectStore), nil
+}
+
+// bootstrapFromObjectStore changes the exported cluster to be bootstrapped
+// via recovery from the passed object store, where the original cluster is
+// archiving its WALs and storing its base backups. The exported cluster
+// keeps the name of the original one, so it is given a different server
+// name to avoid archiving in the same location
+func bootstrapFromObjectStore(
+	exported *apiv1.Cluster,
+	original *apiv1.Cluster,
+	originalObjectStore *apiv1.BarmanObjectStoreConfiguration,
+) {
+	objectStore := originalObjectStore.DeepCopy()
+	if objectStore.ServerName == "" {
+		objectStore.ServerName = original.Name
+	}
+
+	exported.Spec.Backup.ObjectStoreName = ""
+	exported.Spec.Backup.BarmanObjectStore = objectStore.DeepCopy()
+	exported.Spec.Backup.BarmanObjectStore.ServerName = objectStore.ServerName + recoveredServerNameSuffix
+
+	sourceName := original.Name + "-origin"
+	exported.Spec.ExternalClusters = append(exported.Spec.ExternalClusters, apiv1.ExternalCluster{
+		Name:              sourceName,
+		BarmanObjectStore: objectStore,
+	})
+
+	recovery := &apiv1.BootstrapRecovery{
+		Source: sourceName,
+	}
+	if bootstrap := original.Spec.Bootstrap; bootstrap != nil {
+		switch {
+		case bootstrap.InitDB != nil:
+			recovery.Database = bootstrap.InitDB.Database
+			recovery.Owner = bootstrap.InitDB.Owner
+			recovery.Secret = bootstrap.InitDB.Secret
+		case bootstrap.Recovery != nil:
+			recovery.Database = bootstrap.Recovery.Database
+			recovery.Owner = bootstrap.Recovery.Owner
+			recovery.Secret = bootstrap.Recovery.Secret
+		case bootstrap.PgBaseBackup != nil:
+			recovery.Database = bootstrap.PgBaseBackup.Database
+			recovery.Owner = bootstrap.PgBaseBackup.Owner
+			recovery.Secret = bootstrap.PgBaseBackup.Secret
+		}
+	}
+	exported.Spec.Bootstrap = &apiv1.BootstrapConfiguration{
+		Recovery: recovery,
+	}
+}
@@ -0,0 +1,135 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package export
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("cluster export", func() {
+	var cluster *apiv1.Cluster
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            "cluster-example",
+				Namespace:       "default",
+				UID:             "4e4f5f4a-6c1c-4d0e-9b1a-2b2d6c2f1e3a",
+				ResourceVersion: "1234",
+				Generation:      3,
+				Labels:          map[string]string{"app": "test"},
+				Annotations: map[string]string{
+					corev1.LastAppliedConfigAnnotation:  "{}",
+					utils.DefaultedFieldsAnnotationName: "spec.imageName",
+					"example.com/owner":                 "team-a",
+				},
+			},
+			Spec: apiv1.ClusterSpec{
+				Instances: 3,
+				Bootstrap: &apiv1.BootstrapConfiguration{
+					InitDB: &apiv1.BootstrapInitDB{
+						Database: "app",
+						Owner:    "app",
+					},
+				},
+				Backup: &apiv1.BackupConfiguration{
+					BarmanObjectStore: &apiv1.BarmanObjectStoreConfiguration{
+						DestinationPath: "s3://backups/",
+					},
+				},
+			},
+			Status: apiv1.ClusterStatus{
+				CurrentPrimary: "cluster-example-1",
+			},
+		}
+	})
+
+	It("removes the status and the runtime metadata", func() {
+		exported := cleanCluster(cluster)
+		Expect(exported.APIVersion).To(Equal("postgresql.cnpg.io/v1"))
+		Expect(exported.Kind).To(Equal("Cluster"))
+		Expect(exported.Name).To(Equal(cluster.Name))
+		Expect(exported.Namespace).To(BeEmpty())
+		Expect(exported.UID).To(BeEmpty())
+		Expect(exported.ResourceVersion).To(BeEmpty())
+		Expect(exported.Generation).To(BeZero())
+		Expect(exported.Labels).To(Equal(cluster.Labels))
+		Expect(exported.Annotations).To(Equal(map[string]string{"example.com/owner": "team-a"}))
+		Expect(exported.Status).To(Equal(apiv1.ClusterStatus{}))
+		Expect(exported.Spec).To(Equal(cluster.Spec))
+	})
+
+	It("bootstraps the exported cluster from the object store", func() {
+		exported := cleanCluster(cluster)
+		bootstrapFromObjectStore(exported, cluster, cluster.Spec.Backup.BarmanObjectStore)
+
+		Expect(exported.Spec.ExternalClusters).To(HaveLen(1))
+		source := exported.Spec.ExternalClusters[0]
+		Expect(source.BarmanObjectStore.DestinationPath).To(Equal("s3://backups/"))
+		Expect(source.BarmanObjectStore.ServerName).To(Equal(cluster.Name))
+
+		Expect(exported.Spec.Bootstrap.InitDB).To(BeNil())
+		Expect(exported.Spec.Bootstrap.Recovery.Source).To(Equal(source.Name))
+		Expect(exported.Spec.Bootstrap.Recovery.Database).To(Equal("app"))
+		Expect(exported.Spec.Bootstrap.Recovery.Owner).To(Equal("app"))
+
+		// the exported cluster doesn't archive in the location of the original one
+		Expect(exported.Spec.Backup.BarmanObjectStore.DestinationPath).To(Equal("s3://backups/"))
+		Expect(exported.Spec.Backup.BarmanObjectStore.ServerName).To(Equal("cluster-example-recovered"))
+
+		// the original cluster is untouched
+		Expect(cluster.Spec.Backup.BarmanObjectStore.ServerName).To(BeEmpty())
+		Expect(cluster.Spec.Bootstrap.InitDB).ToNot(BeNil())
+	})
+
+	It("replaces the object store referenced by name with the resolved one", func() {
+		cluster.Spec.Backup = &apiv1.BackupConfiguration{ObjectStoreName: "shared"}
+		objectStore := &apiv1.BarmanObjectStoreConfiguration{
+			DestinationPath: "s3://shared-backups/",
+			ServerName:      "cluster-example-v2",
+		}
+
+		exported := cleanCluster(cluster)
+		bootstrapFromObjectStore(exported, cluster, objectStore)
+
+		Expect(exported.Spec.ExternalClusters[0].BarmanObjectStore.ServerName).To(Equal("cluster-example-v2"))
+		Expect(exported.Spec.Backup.ObjectStoreName).To(BeEmpty())
+		Expect(exported.Spec.Backup.BarmanObjectStore.DestinationPath).To(Equal("s3://shared-backups/"))
+		Expect(exported.Spec.Backup.BarmanObjectStore.ServerName).To(Equal("cluster-example-v2-recovered"))
+	})
+
+	It("refuses to bootstrap from the object store when there is none", func() {
+		cluster.Spec.Backup = nil
+		_, err := getObjectStore(context.TODO(), cluster, "cnpg-system")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("uses the object store of the cluster when it is not referenced by name", func() {
+		objectStore, err := getObjectStore(context.TODO(), cluster, "cnpg-system")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(objectStore).To(Equal(cluster.Spec.Backup.BarmanObjectStore))
+	})
+})
@@ -25,6 +25,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/utils/strings/slices"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
@@ -81,6 +82,12 @@ func (off *offCommand) execute() error {
 		return err
 	}
 
+	// Ensure the primary instance has been cleanly shut down where
+	// the hibernation recorded it
+	if err := off.ensureCleanShutdownStep(pvcGroup, clusterFromPVC); err != nil {
+		return err
+	}
+
 	// And recreate it into the Kubernetes cluster
 	if err := off.createClusterWithoutRuntimeDataStep(clusterFromPVC); err != nil {
 		return err
@@ -135,6 +142,33 @@ func (off *offCommand) ensurePVCsArePartOfAPVCGroupStep(pvcs []corev1.Persistent
 	return nil
 }
 
+// ensureCleanShutdownStep checks that the pg_controldata output stored in
+// the PVCs matches the clean shutdown of the primary instance recorded by
+// the hibernation. The clusters hibernated without recording it are not checked
+func (off *offCommand) ensureCleanShutdownStep(
+	pvcs []corev1.PersistentVolumeClaim,
+	clusterFromPVC apiv1.Cluster,
+) error {
+	expectedLSN := clusterFromPVC.Status.CleanShutdownLSN
+	if expectedLSN == "" {
+		return nil
+	}
+
+	for _, pvc := range pvcs {
+		lsn, err := getCleanShutdownLSN(pvc.Annotations[utils.HibernatePgControlDataAnnotationName])
+		if err != nil {
+			return fmt.Errorf("cannot verify the hibernated data in pvc %s: %w", pvc.Name, err)
+		}
+		if lsn != expectedLSN {
+			return fmt.Errorf("the hibernated data in pvc %s has been shut down at LSN %s, while %s was expected",
+				pvc.Name, lsn, expectedLSN)
+		}
+	}
+
+	off.printAdvancement(fmt.Sprintf("hibernated data verified, shut down at LSN %s", expectedLSN))
+	return nil
+}
+
 // createClusterWithoutRuntimeDataStep recreate the original cluster back into Kubernetes
 func (off *offCommand) createClusterWithoutRuntimeDataStep(clusterFromPVC apiv1.Cluster) error {
 	cluster := clusterFromPVC.DeepCopy()
@@ -154,7 +188,17 @@ func (off *offCommand) createClusterWithoutRuntimeDataStep(clusterFromPVC apiv1.
 	delete(cluster.Annotations, utils.FencedInstanceAnnotation)
 
 	// create cluster
-	return plugin.Client.Create(off.ctx, cluster)
+	if err := plugin.Client.Create(off.ctx, cluster); err != nil {
+		return err
+	}
+
+	// keep the clean shutdown LSN, as a reference for the woken up cluster
+	if clusterFromPVC.Status.CleanShutdownLSN == "" {
+		return nil
+	}
+	origCluster := cluster.DeepCopy()
+	cluster.Status.CleanShutdownLSN = clusterFromPVC.Status.CleanShutdownLSN
+	return plugin.Client.Status().Patch(off.ctx, cluster, client.MergeFrom(origCluster))
 }
 
 // ensureAnnotationsExists returns an error if the passed PVC is annotated with all the
@@ -19,6 +19,7 @@ package hibernate
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strconv"
 	"time"
@@ -120,15 +121,31 @@ func (on *onCommand) execute() error {
 
 	on.printAdvancement("hibernation process starting...")
 
+	// The replicas are shut down first, so that the primary instance
+	// writes its shutdown checkpoint last
+	defer on.rollbackFenceClusterIfNeeded()
+	if err := on.fenceReplicasStep(); err != nil {
+		on.shouldRollback = true
+		return err
+	}
+
+	on.printAdvancement("waiting for the replicas to be fenced")
+
+	if err := on.waitInstancesToBeFencedStep(on.getReplicaInstances()); err != nil {
+		on.shouldRollback = true
+		return err
+	}
+
+	on.printAdvancement("replicas are now fenced, fencing the primary instance")
+
 	if err := on.fenceClusterStep(); err != nil {
 		on.shouldRollback = true
 		return err
 	}
-	defer on.rollbackFenceClusterIfNeeded()
 
-	on.printAdvancement("waiting for the cluster to be fenced")
+	on.printAdvancement("waiting for the primary instance to be fenced")
 
-	if err := on.waitInstancesToBeFencedStep(); err != nil {
+	if err := on.waitPrimaryInstanceToBeFencedStep(); err != nil {
 		on.shouldRollback = true
 		return err
 	}
@@ -174,6 +191,42 @@ func (on *onCommand) checkPreconditionsStep() error {
 	return nil
 }
 
+// fenceReplicasStep applies the fencing annotation to the replicas, which
+// are shut down before the primary instance
+func (on *onCommand) fenceReplicasStep() error {
+	contextLogger := log.FromContext(on.ctx)
+
+	for _, instance := range on.getReplicaInstances() {
+		contextLogger.Debug("applying the fencing annotation for the replica", "instance", instance.Name)
+		err := fence.ApplyFenceFunc(
+			on.ctx,
+			plugin.Client,
+			on.cluster.Name,
+			plugin.Namespace,
+			instance.Name,
+			utils.AddFencedInstance,
+		)
+		if err != nil && !errors.Is(err, utils.ErrorServerAlreadyFenced) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// getReplicaInstances gets the instances to be shut down before the
+// primary instance
+func (on *onCommand) getReplicaInstances() []corev1.Pod {
+	replicas := make([]corev1.Pod, 0, len(on.managedInstances))
+	for _, instance := range on.managedInstances {
+		if instance.Name != on.primaryInstance.Name {
+			replicas = append(replicas, instance)
+		}
+	}
+
+	return replicas
+}
+
 func (on *onCommand) fenceClusterStep() error {
 	contextLogger := log.FromContext(on.ctx)
 
@@ -216,9 +269,9 @@ func (on *onCommand) rollbackFenceClusterIfNeeded() {
 	}
 }
 
-// waitInstancesToBeFenced waits for all instances to be shut down
-func (on *onCommand) waitInstancesToBeFencedStep() error {
-	for _, instance := range on.managedInstances {
+// waitInstancesToBeFencedStep waits for the passed instances to be shut down
+func (on *onCommand) waitInstancesToBeFencedStep(instances []corev1.Pod) error {
+	for _, instance := range instances {
 		if err := retry.OnError(hibernationBackoff, pkgres.RetryAlways, func() error {
 			running, err := resources.IsInstanceRunning(on.ctx, instance)
 			if err != nil {
@@ -236,8 +289,30 @@ func (on *onCommand) waitInstancesToBeFencedStep() error {
 	return nil
 }
 
+// waitPrimaryInstanceToBeFencedStep waits for the smart shutdown of the
+// primary instance, within the timeout set in the cluster
+func (on *onCommand) waitPrimaryInstanceToBeFencedStep() error {
+	contextLogger := log.FromContext(on.ctx)
+
+	timeout := on.cluster.GetPrimaryShutdownTimeout()
+	err := wait.PollImmediateWithContext(on.ctx, 5*time.Second, timeout, func(ctx context.Context) (bool, error) {
+		running, err := resources.IsInstanceRunning(ctx, on.primaryInstance)
+		if err != nil {
+			contextLogger.Debug("error checking the primary instance status", "error", err)
+			return false, nil
+		}
+		return !running, nil
+	})
+	if err != nil {
+		return fmt.Errorf("primary instance not shut down in %v (%v): %w", timeout, on.primaryInstance.Name, err)
+	}
+
+	return nil
+}
+
 // annotatePVCStep stores the pg_controldata output
-// into an annotation of the primary PVC
+// into an annotation of the primary PVC, after having recorded the
+// location of the shutdown checkpoint in the status of the cluster
 func (on *onCommand) annotatePVCStep() error {
 	controlData, err := getPGControlData(on.ctx, on.primaryInstance)
 	if err != nil {
@@ -245,6 +320,15 @@ func (on *onCommand) annotatePVCStep() error {
 	}
 	on.printAdvancement("primary pg_controldata output fetched")
 
+	cleanShutdownLSN, err := getCleanShutdownLSN(controlData)
+	if err != nil {
+		return err
+	}
+	if err := on.recordCleanShutdownLSN(cleanShutdownLSN); err != nil {
+		return fmt.Errorf("could not record the clean shutdown LSN: %w", err)
+	}
+	on.printAdvancement(fmt.Sprintf("primary instance cleanly shut down at LSN %s", cleanShutdownLSN))
+
 	on.printAdvancement("annotating the PVC with the cluster manifest")
 	if err := annotatePVCs(on.ctx, on.pvcs, on.cluster, controlData); err != nil {
 		return fmt.Errorf("could not annotate PVCs: %w", err)
@@ -253,6 +337,15 @@ func (on *onCommand) annotatePVCStep() error {
 	return nil
 }
 
+// recordCleanShutdownLSN stores the location of the shutdown checkpoint
+// of the primary instance in the status of the cluster, which is part of
+// the manifest stored in the PVC annotations
+func (on *onCommand) recordCleanShutdownLSN(lsn string) error {
+	origCluster := on.cluster.DeepCopy()
+	on.cluster.Status.CleanShutdownLSN = lsn
+	return plugin.Client.Status().Patch(on.ctx, on.cluster, client.MergeFrom(origCluster))
+}
+
 func (on *onCommand) rollBackAnnotationsIfNeeded() {
 	if !on.shouldRollback {
 		return
@@ -20,6 +20,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -61,3 +62,32 @@ func getClusterFromPVCAnnotation(pvc corev1.PersistentVolumeClaim) (apiv1.Cluste
 	}
 	return clusterFromPVC, nil
 }
+
+// getCleanShutdownLSN gets the location of the shutdown checkpoint from
+// the pg_controldata output of the primary instance, ensuring it has
+// been cleanly shut down
+func getCleanShutdownLSN(pgControlData string) (string, error) {
+	var state, lsn string
+	for _, line := range strings.Split(pgControlData, "\n") {
+		key, value, found := strings.Cut(line, ":")
+		if !found {
+			continue
+		}
+
+		switch strings.TrimSpace(key) {
+		case "Database cluster state":
+			state = strings.TrimSpace(value)
+		case "Latest checkpoint location":
+			lsn = strings.TrimSpace(value)
+		}
+	}
+
+	if state != "shut down" {
+		return "", fmt.Errorf("the primary instance has not been cleanly shut down, its state is %q", state)
+	}
+	if lsn == "" {
+		return "", fmt.Errorf("latest checkpoint location not found in pg_controldata output")
+	}
+
+	return lsn, nil
+}
@@ -0,0 +1,48 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package hibernate
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("clean shutdown LSN", func() {
+	const pgControlData = `pg_control version number:            1300
+Catalog version number:               202209061
+Database system identifier:           7205596216245129234
+Database cluster state:               shut down
+pg_control last modified:             Wed 01 Mar 2023 12:00:00 PM UTC
+Latest checkpoint location:           0/5000028
+Latest checkpoint's REDO location:    0/5000028
+Latest checkpoint's TimeLineID:       1
+`
+
+	It("is read from the pg_controldata output", func() {
+		Expect(getCleanShutdownLSN(pgControlData)).To(Equal("0/5000028"))
+	})
+
+	It("requires the primary instance to be cleanly shut down", func() {
+		_, err := getCleanShutdownLSN(
+			"Database cluster state:               in production\n" +
+				"Latest checkpoint location:           0/5000028\n")
+		Expect(err).To(HaveOccurred())
+
+		_, err = getCleanShutdownLSN("Database cluster state:               shut down\n")
+		Expect(err).To(HaveOccurred())
+	})
+})
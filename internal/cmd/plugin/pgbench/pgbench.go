@@ -140,7 +140,7 @@ func (cmd *pgBenchCommand) buildJob(cluster apiv1.Cluster) *batchv1.Job {
 						{
 							Name:  "wait-for-cnpg",
 							Image: clusterImageName,
-							Env:   cmd.buildEnvVariables(),
+							Env:   cmd.buildEnvVariables(cluster),
 							Command: []string{
 								"sh",
 								"-c",
@@ -150,7 +150,7 @@ func (cmd *pgBenchCommand) buildJob(cluster apiv1.Cluster) *batchv1.Job {
 						{
 							Name:  "pgbench-init",
 							Image: clusterImageName,
-							Env:   cmd.buildEnvVariables(),
+							Env:   cmd.buildEnvVariables(cluster),
 							Command: []string{
 								"pgbench",
 							},
@@ -166,7 +166,7 @@ func (cmd *pgBenchCommand) buildJob(cluster apiv1.Cluster) *batchv1.Job {
 							Name:            "pgbench",
 							Image:           clusterImageName,
 							ImagePullPolicy: corev1.PullAlways,
-							Env:             cmd.buildEnvVariables(),
+							Env:             cmd.buildEnvVariables(cluster),
 							Command:         []string{pgBenchKeyWord},
 							Args:            cmd.pgBenchCommandArgs,
 						},
@@ -177,9 +177,9 @@ func (cmd *pgBenchCommand) buildJob(cluster apiv1.Cluster) *batchv1.Job {
 	}
 }
 
-func (cmd *pgBenchCommand) buildEnvVariables() []corev1.EnvVar {
+func (cmd *pgBenchCommand) buildEnvVariables(cluster apiv1.Cluster) []corev1.EnvVar {
 	clusterName := cmd.clusterName
-	pgHost := fmt.Sprintf("%v%v", clusterName, apiv1.ServiceReadWriteSuffix)
+	pgHost := cluster.GetServiceReadWriteName()
 	appSecreteName := fmt.Sprintf("%v-%v", clusterName, "app")
 
 	envVar := []corev1.EnvVar{
@@ -53,6 +53,10 @@ func Promote(ctx context.Context, clusterName string, serverName string) error {
 		return fmt.Errorf("new primary node %s not found in namespace %s", serverName, plugin.Namespace)
 	}
 
+	if utils.IsReadReplica(&pod.ObjectMeta) {
+		return fmt.Errorf("%s is a read replica and cannot be promoted", serverName)
+	}
+
 	// The Pod exists, let's update status fields
 	cluster.Status.TargetPrimary = serverName
 	cluster.Status.TargetPrimaryTimestamp = utils.GetCurrentTimestamp()
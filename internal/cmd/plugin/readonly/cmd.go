@@ -0,0 +1,51 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package readonly
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// NewCmd creates the new "read-only" command
+func NewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "read-only",
+		Short: `Makes a cluster reject or accept the writes`,
+		Long: `Makes the whole cluster reject the writes, without restarting the instances, ` +
+			`which keep serving reads. Unlike fencing and hibernation, every instance stays up`,
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "on [cluster]",
+		Short: `Makes the cluster reject the writes`,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return setReadOnly(cmd.Context(), args[0], true)
+		},
+	})
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "off [cluster]",
+		Short: `Makes the cluster accept the writes again`,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return setReadOnly(cmd.Context(), args[0], false)
+		},
+	})
+
+	return cmd
+}
@@ -0,0 +1,64 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package readonly
+
+import (
+	"context"
+	"fmt"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
+)
+
+// setReadOnly enables or disables the read-only mode of a cluster
+func setReadOnly(ctx context.Context, clusterName string, enabled bool) error {
+	var cluster apiv1.Cluster
+	err := plugin.Client.Get(ctx, client.ObjectKey{Namespace: plugin.Namespace, Name: clusterName}, &cluster)
+	if err != nil {
+		return fmt.Errorf("cluster %s not found in namespace %s", clusterName, plugin.Namespace)
+	}
+
+	if cluster.IsReadOnly() == enabled {
+		fmt.Printf("%s is already %s\n", clusterName, getModeDescription(enabled))
+		return nil
+	}
+
+	updatedCluster := cluster.DeepCopy()
+	if updatedCluster.Spec.ReadOnly == nil {
+		updatedCluster.Spec.ReadOnly = &apiv1.ReadOnlyConfiguration{}
+	}
+	updatedCluster.Spec.ReadOnly.Enabled = enabled
+
+	if err := plugin.Client.Patch(ctx, updatedCluster, client.MergeFrom(&cluster)); err != nil {
+		return err
+	}
+
+	fmt.Printf("%s set to %s, check the ReadOnly condition for the primary to apply it\n",
+		clusterName, getModeDescription(enabled))
+	return nil
+}
+
+// getModeDescription describes the read-only mode
+func getModeDescription(enabled bool) string {
+	if enabled {
+		return "read-only"
+	}
+
+	return "read-write"
+}
@@ -0,0 +1,42 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rotatecertificates
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+)
+
+// NewCmd creates the new "rotate-certificates" command
+func NewCmd() *cobra.Command {
+	rotateCmd := &cobra.Command{
+		Use:   "rotate-certificates [clusterName]",
+		Short: `Reissue the certificates of the cluster`,
+		Long: `Forces the operator to reissue the server, client and replication certificates
+it generated for the cluster, which are then reloaded by every instance.
+User-provided certificates are not touched.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := context.Background()
+			clusterName := args[0]
+			return Rotate(ctx, clusterName)
+		},
+	}
+
+	return rotateCmd
+}
@@ -54,6 +54,10 @@ type PostgresqlStatus struct {
 
 	// PrimaryPod contains the primary Pod
 	PrimaryPod corev1.Pod
+
+	// Summary is a stable view of the status of the instances, which is
+	// only populated in the machine-readable output
+	Summary *InstancesSummary `json:"summary,omitempty"`
 }
 
 func (fullStatus *PostgresqlStatus) getReplicationSlotList() postgres.PgReplicationSlotList {
@@ -76,6 +80,16 @@ func (fullStatus *PostgresqlStatus) getPrintableReplicationSlotInfo(instanceName
 	return nil
 }
 
+// getInstanceNameFromApplicationName gets the name of the instance
+// using the passed application_name to stream from the primary
+func (fullStatus *PostgresqlStatus) getInstanceNameFromApplicationName(applicationName string) string {
+	if fullStatus.Cluster.Spec.ReplicationConnection == nil {
+		return applicationName
+	}
+
+	return strings.TrimPrefix(applicationName, fullStatus.Cluster.Spec.ReplicationConnection.ApplicationNamePrefix)
+}
+
 func getPrintableIntegerPointer(i *int) string {
 	if i == nil {
 		return "NULL"
@@ -90,13 +104,10 @@ func Status(ctx context.Context, clusterName string, verbose bool, format plugin
 		return err
 	}
 
-	err = plugin.Print(status, format, os.Stdout)
-	if err != nil {
-		return err
-	}
-
 	if format != plugin.OutputFormatText {
-		return nil
+		sort.Sort(status.InstanceStatus)
+		status.Summary = status.getInstancesSummary()
+		return plugin.Print(status, format, os.Stdout)
 	}
 
 	status.printBasicInfo()
@@ -111,6 +122,7 @@ func Status(ctx context.Context, clusterName string, verbose bool, format plugin
 	status.printBackupStatus()
 	status.printReplicaStatus(verbose)
 	status.printUnmanagedReplicationSlotStatus()
+	status.printDelayedReplicasStatus()
 	status.printInstancesStatus()
 
 	if nonFatalError != nil {
@@ -195,12 +207,27 @@ func (fullStatus *PostgresqlStatus) printBasicInfo() {
 		summary.AddLine("Primary instance:", primaryInstance)
 	}
 	summary.AddLine("Status:", fullStatus.getStatus(isPrimaryFenced, cluster))
-	if cluster.Spec.Instances == cluster.Status.Instances {
-		summary.AddLine("Instances:", aurora.Green(cluster.Spec.Instances))
+	if progress := cluster.Status.RecoveryProgress; progress != nil {
+		summary.AddLine("Recovery progress:", fmt.Sprintf(
+			"%s (WAL File: %s - Target: %s)",
+			progress.ReplayedLSN,
+			progress.CurrentWAL,
+			progress.Target,
+		))
+	}
+	if cluster.GetDesiredInstances() == cluster.Status.Instances {
+		summary.AddLine("Instances:", aurora.Green(cluster.GetDesiredInstances()))
 	} else {
-		summary.AddLine("Instances:", aurora.Red(cluster.Spec.Instances))
+		summary.AddLine("Instances:", aurora.Red(cluster.GetDesiredInstances()))
 	}
-	if cluster.Spec.Instances == cluster.Status.ReadyInstances {
+	if readReplicas := cluster.GetReadReplicas(); readReplicas > 0 {
+		if readReplicas == len(cluster.Status.ReadReplicas) {
+			summary.AddLine("Read replicas:", aurora.Green(readReplicas))
+		} else {
+			summary.AddLine("Read replicas:", aurora.Red(readReplicas))
+		}
+	}
+	if cluster.GetDesiredInstances() == cluster.Status.ReadyInstances {
 		summary.AddLine("Ready instances:", aurora.Green(cluster.Status.ReadyInstances))
 	} else {
 		summary.AddLine("Ready instances:", aurora.Red(cluster.Status.ReadyInstances))
@@ -241,7 +268,7 @@ func (fullStatus *PostgresqlStatus) getStatus(isPrimaryFenced bool, cluster *api
 	}
 
 	switch cluster.Status.Phase {
-	case apiv1.PhaseHealthy, apiv1.PhaseFirstPrimary, apiv1.PhaseCreatingReplica:
+	case apiv1.PhaseHealthy, apiv1.PhaseFirstPrimary, apiv1.PhaseRestoring, apiv1.PhaseCreatingReplica:
 		return fmt.Sprintf("%v %v", aurora.Green(cluster.Status.Phase), cluster.Status.PhaseReason)
 	case apiv1.PhaseUpgrade, apiv1.PhaseWaitingForUser:
 		return fmt.Sprintf("%v %v", aurora.Yellow(cluster.Status.Phase), cluster.Status.PhaseReason)
@@ -320,6 +347,11 @@ func (fullStatus *PostgresqlStatus) printBackupStatus() {
 		status.AddLine("Last Failed WAL:", primaryInstanceStatus.LastFailedWAL,
 			" @ ", primaryInstanceStatus.LastFailedWALTime)
 	}
+	if primaryInstanceStatus.BarmanCloudVersion == "" {
+		status.AddLine("Barman Cloud version:", aurora.Red("Not installed"))
+	} else {
+		status.AddLine("Barman Cloud version:", primaryInstanceStatus.BarmanCloudVersion)
+	}
 
 	status.Print()
 	fmt.Println()
@@ -489,7 +521,7 @@ func (fullStatus *PostgresqlStatus) printReplicaStatus(verbose bool) {
 			replication.SyncState,
 			replication.SyncPriority,
 		}
-		addReplicationSlotsColumns(replication.ApplicationName, &columns)
+		addReplicationSlotsColumns(fullStatus.getInstanceNameFromApplicationName(replication.ApplicationName), &columns)
 		status.AddLine(columns...)
 	}
 	status.Print()
@@ -650,6 +682,10 @@ func getReplicaRole(instance postgres.PostgresqlStatus, fullStatus *PostgresqlSt
 		return "Standby (paused)"
 	}
 
+	if instance.IsReadReplica() {
+		return "Standby (read replica)"
+	}
+
 	primaryInstanceStatus := fullStatus.tryGetPrimaryInstance()
 	if primaryInstanceStatus == nil {
 		return "Unknown"
@@ -657,7 +693,8 @@ func getReplicaRole(instance postgres.PostgresqlStatus, fullStatus *PostgresqlSt
 
 	for _, state := range primaryInstanceStatus.ReplicationInfo {
 		// todo: handle others states other than 'streaming'
-		if !(state.ApplicationName == instance.Pod.Name && state.State == "streaming") {
+		applicationName := fullStatus.Cluster.GetReplicationApplicationName(instance.Pod.Name)
+		if !(state.ApplicationName == applicationName && state.State == "streaming") {
 			continue
 		}
 		switch state.SyncState {
@@ -749,3 +786,40 @@ func (fullStatus *PostgresqlStatus) printUnmanagedReplicationSlotStatus() {
 	status.Print()
 	fmt.Println()
 }
+
+func (fullStatus *PostgresqlStatus) printDelayedReplicasStatus() {
+	if fullStatus.Cluster.Spec.DelayedReplicas == nil {
+		return
+	}
+
+	status := tabby.New()
+	fmt.Println(aurora.Green("Delayed replicas"))
+	status.AddHeader(
+		"Name",
+		"Apply Delay",
+		"Received LSN",
+		"Replay LSN",
+		"Last Replayed Transaction",
+	)
+
+	for _, instance := range fullStatus.InstanceStatus.Items {
+		if instance.Error != nil || !instance.IsDelayedReplica() {
+			continue
+		}
+
+		lastReplayTimestamp := instance.LastReplayTimestamp
+		if lastReplayTimestamp == "" {
+			lastReplayTimestamp = "-"
+		}
+
+		status.AddLine(
+			instance.Pod.Name,
+			time.Duration(instance.MinApplyDelay)*time.Millisecond,
+			instance.ReceivedLsn,
+			instance.ReplayLsn,
+			lastReplayTimestamp,
+		)
+	}
+	status.Print()
+	fmt.Println()
+}
@@ -0,0 +1,154 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// InstancesSummarySchemaVersion is the version of the schema of
+// InstancesSummary. It is increased at every change which is not
+// backward compatible, like removing or renaming a field
+const InstancesSummarySchemaVersion = "v1"
+
+// InstanceRole is the role of an instance in the summary
+type InstanceRole string
+
+const (
+	// InstanceRolePrimary is the primary instance of the cluster
+	InstanceRolePrimary InstanceRole = "primary"
+
+	// InstanceRoleDesignatedPrimary is the instance replicating from the
+	// source cluster in a replica cluster
+	InstanceRoleDesignatedPrimary InstanceRole = "designated-primary"
+
+	// InstanceRoleReplica is a standby instance
+	InstanceRoleReplica InstanceRole = "replica"
+)
+
+// InstancesSummary is a stable, machine-readable view of the
+// status of the instances of a cluster
+type InstancesSummary struct {
+	// The version of this schema
+	SchemaVersion string `json:"schemaVersion"`
+
+	// The status of every instance, sorted like in the human-readable output
+	Instances []InstanceSummary `json:"instances"`
+}
+
+// InstanceSummary is the status of a single instance
+type InstanceSummary struct {
+	// The name of the instance Pod
+	Name string `json:"name"`
+
+	// The node where the instance is running
+	Node string `json:"node,omitempty"`
+
+	// The role of the instance, empty if the status couldn't be extracted
+	Role InstanceRole `json:"role,omitempty"`
+
+	// Whether the instance Pod is ready
+	Ready bool `json:"ready"`
+
+	// The current timeline of the instance
+	Timeline int `json:"timeline,omitempty"`
+
+	// The current LSN of a primary or the replayed LSN of a replica
+	CurrentLSN postgres.LSN `json:"currentLsn,omitempty"`
+
+	// The LSN received by a replica
+	ReceivedLSN postgres.LSN `json:"receivedLsn,omitempty"`
+
+	// The bytes of WAL a replica has still to replay to reach the primary
+	LagBytes *int64 `json:"lagBytes,omitempty"`
+
+	// The replay lag of a replica, as reported by the primary
+	ReplayLag string `json:"replayLag,omitempty"`
+
+	// The synchronous state of a replica, as reported by the primary
+	SyncState string `json:"syncState,omitempty"`
+
+	// The error raised while extracting the status of the instance
+	Error string `json:"error,omitempty"`
+}
+
+// getInstancesSummary builds the machine-readable summary
+// of the status of the instances
+func (fullStatus *PostgresqlStatus) getInstancesSummary() *InstancesSummary {
+	summary := &InstancesSummary{
+		SchemaVersion: InstancesSummarySchemaVersion,
+		Instances:     make([]InstanceSummary, 0, len(fullStatus.InstanceStatus.Items)),
+	}
+
+	primary := fullStatus.tryGetPrimaryInstance()
+	for _, instance := range fullStatus.InstanceStatus.Items {
+		item := InstanceSummary{
+			Name:  instance.Pod.Name,
+			Node:  instance.Pod.Spec.NodeName,
+			Ready: utils.IsPodReady(instance.Pod),
+		}
+		if instance.Error != nil {
+			item.Error = instance.Error.Error()
+			summary.Instances = append(summary.Instances, item)
+			continue
+		}
+
+		item.Timeline = instance.TimeLineID
+		item.CurrentLSN = getCurrentLSN(instance)
+		switch {
+		case instance.IsPrimary:
+			item.Role = InstanceRolePrimary
+		case fullStatus.isReplicaClusterDesignatedPrimary(instance):
+			item.Role = InstanceRoleDesignatedPrimary
+		default:
+			item.Role = InstanceRoleReplica
+			item.ReceivedLSN = instance.ReceivedLsn
+			fillReplicaLag(&item, primary, fullStatus.Cluster.GetReplicationApplicationName(item.Name))
+		}
+
+		summary.Instances = append(summary.Instances, item)
+	}
+
+	return summary
+}
+
+// fillReplicaLag sets the lag of a replica using the status of the primary,
+// where the replica is known by the passed application_name
+func fillReplicaLag(item *InstanceSummary, primary *postgres.PostgresqlStatus, applicationName string) {
+	if primary == nil || !primary.IsPrimary {
+		return
+	}
+
+	primaryLSN, primaryErr := primary.CurrentLsn.Parse()
+	replicaLSN, replicaErr := item.CurrentLSN.Parse()
+	if primaryErr == nil && replicaErr == nil {
+		lag := primaryLSN - replicaLSN
+		if lag < 0 {
+			lag = 0
+		}
+		item.LagBytes = &lag
+	}
+
+	for _, replication := range primary.ReplicationInfo {
+		if replication.ApplicationName == applicationName {
+			item.ReplayLag = replication.ReplayLag
+			item.SyncState = replication.SyncState
+			break
+		}
+	}
+}
@@ -0,0 +1,113 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package status
+
+import (
+	"encoding/json"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("instances summary", func() {
+	newPod := func(name string) corev1.Pod {
+		return corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec:       corev1.PodSpec{NodeName: "node-" + name},
+		}
+	}
+
+	fullStatus := &PostgresqlStatus{
+		Cluster: &apiv1.Cluster{},
+		InstanceStatus: &postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				{
+					Pod:        newPod("cluster-example-1"),
+					IsPrimary:  true,
+					CurrentLsn: "0/6000000",
+					TimeLineID: 2,
+					ReplicationInfo: postgres.PgStatReplicationList{
+						{
+							ApplicationName: "cluster-example-2",
+							ReplayLag:       "00:00:01",
+							SyncState:       "async",
+						},
+					},
+				},
+				{
+					Pod:         newPod("cluster-example-2"),
+					ReceivedLsn: "0/6000000",
+					ReplayLsn:   "0/5000000",
+					TimeLineID:  2,
+				},
+				{
+					Pod:   newPod("cluster-example-3"),
+					Error: fmt.Errorf("connection refused"),
+				},
+			},
+		},
+	}
+
+	It("describes the primary and the replicas", func() {
+		summary := fullStatus.getInstancesSummary()
+		Expect(summary.SchemaVersion).To(Equal(InstancesSummarySchemaVersion))
+		Expect(summary.Instances).To(HaveLen(3))
+
+		primary := summary.Instances[0]
+		Expect(primary.Role).To(Equal(InstanceRolePrimary))
+		Expect(primary.CurrentLSN).To(BeEquivalentTo("0/6000000"))
+		Expect(primary.Timeline).To(Equal(2))
+		Expect(primary.LagBytes).To(BeNil())
+
+		replica := summary.Instances[1]
+		Expect(replica.Role).To(Equal(InstanceRoleReplica))
+		Expect(replica.Node).To(Equal("node-cluster-example-2"))
+		Expect(replica.CurrentLSN).To(BeEquivalentTo("0/5000000"))
+		Expect(replica.ReceivedLSN).To(BeEquivalentTo("0/6000000"))
+		Expect(replica.LagBytes).ToNot(BeNil())
+		Expect(*replica.LagBytes).To(BeEquivalentTo(0x1000000))
+		Expect(replica.ReplayLag).To(Equal("00:00:01"))
+		Expect(replica.SyncState).To(Equal("async"))
+
+		failing := summary.Instances[2]
+		Expect(failing.Role).To(BeEmpty())
+		Expect(failing.Error).To(Equal("connection refused"))
+	})
+
+	It("uses stable field names", func() {
+		data, err := json.Marshal(fullStatus.getInstancesSummary())
+		Expect(err).ToNot(HaveOccurred())
+
+		var decoded map[string]interface{}
+		Expect(json.Unmarshal(data, &decoded)).To(Succeed())
+		Expect(decoded).To(HaveKeyWithValue("schemaVersion", "v1"))
+
+		instances := decoded["instances"].([]interface{})
+		Expect(instances[1]).To(HaveKeyWithValue("role", "replica"))
+		Expect(instances[1]).To(HaveKeyWithValue("ready", false))
+		Expect(instances[1]).To(HaveKeyWithValue("timeline", BeEquivalentTo(2)))
+		Expect(instances[1]).To(HaveKeyWithValue("currentLsn", "0/5000000"))
+		Expect(instances[1]).To(HaveKeyWithValue("lagBytes", BeEquivalentTo(0x1000000)))
+	})
+})
@@ -19,8 +19,19 @@ limitations under the License.
 package configuration
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
 	"path"
+	"regexp"
 	"strings"
+	"time"
+
+	"golang.org/x/exp/slices"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
 
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/configparser"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
@@ -29,9 +40,37 @@ import (
 
 var configurationLog = log.WithName("configuration")
 
+// imageRegistryRegex matches a registry host, with an optional port,
+// followed by an optional path, like "registry.example.com:5000/mirror"
+var imageRegistryRegex = regexp.MustCompile(
+	`^[a-zA-Z0-9]([a-zA-Z0-9.-]*[a-zA-Z0-9])?(:[0-9]+)?(/[a-z0-9]+([._-][a-z0-9]+)*)*$`)
+
+// ErrObjectStoreNotFound is returned when a cluster references an object
+// store which is not defined in the operator configuration
+var ErrObjectStoreNotFound = errors.New("object store is not defined in the operator configuration")
+
 // DefaultOperatorPullSecretName is implicitly copied into newly created clusters.
 const DefaultOperatorPullSecretName = "cnpg-pull-secret" // #nosec
 
+const (
+	// DefaultInstanceStatusConnectionTimeout is the default connection
+	// timeout, in seconds, used when requesting the status of an instance
+	DefaultInstanceStatusConnectionTimeout = 2
+
+	// DefaultInstanceStatusRequestTimeout is the default timeout, in
+	// seconds, of a request for the status of an instance
+	DefaultInstanceStatusRequestTimeout = 30
+
+	// DefaultStorageSize is the default size of the PGDATA volume of the
+	// clusters not specifying it
+	DefaultStorageSize = "1Gi"
+
+	// DefaultPendingWALArchiveThreshold is the default number of WAL files
+	// waiting to be archived beyond which the archiving is reported as
+	// falling behind
+	DefaultPendingWALArchiveThreshold = 128
+)
+
 // Data is the struct containing the configuration of the operator.
 // Usually the operator code will use the "Current" configuration.
 type Data struct {
@@ -62,6 +101,15 @@ type Data struct {
 	// used by default for new clusters
 	PostgresImageName string `json:"postgresImageName" env:"POSTGRES_IMAGE_NAME"`
 
+	// ImageRegistry is the registry, optionally followed by a path, that
+	// replaces the one of the default PostgreSQL image, to pull it from a
+	// mirror
+	ImageRegistry string `json:"imageRegistry" env:"IMAGE_REGISTRY"`
+
+	// ImagePullPolicy is the pull policy used by the clusters not
+	// specifying their own
+	ImagePullPolicy string `json:"imagePullPolicy" env:"IMAGE_PULL_POLICY"`
+
 	// InheritedAnnotations is a list of annotations that every resource could inherit from
 	// the owning Cluster
 	InheritedAnnotations []string `json:"inheritedAnnotations" env:"INHERITED_ANNOTATIONS"`
@@ -77,6 +125,11 @@ type Data struct {
 	// EnableAzurePVCUpdates enables the live update of PVC in Azure environment
 	EnableAzurePVCUpdates bool `json:"enableAzurePVCUpdates" env:"ENABLE_AZURE_PVC_UPDATES"`
 
+	// EnforceQuorumInstances makes the webhook reject the clusters using
+	// synchronous replication with an even number of instances, instead of
+	// just logging an advisory message
+	EnforceQuorumInstances bool `json:"enforceQuorumInstances" env:"ENFORCE_QUORUM_INSTANCES"`
+
 	// MonitoringQueriesConfigmap is the name of the configmap in the operator namespace which contain
 	// the monitoring queries. The queries will be read from the data key: "queries".
 	MonitoringQueriesConfigmap string `json:"monitoringQueriesConfigmap" env:"MONITORING_QUERIES_CONFIGMAP"`
@@ -84,6 +137,52 @@ type Data struct {
 	// MonitoringQueriesSecret is the name of the secret in the operator namespace which contain
 	// the monitoring queries. The queries will be read from the data key: "queries".
 	MonitoringQueriesSecret string `json:"monitoringQueriesSecret" env:"MONITORING_QUERIES_SECRET"`
+
+	// ObjectStores is a YAML map of object store configurations, indexed by
+	// name, which clusters can use as the base for their backup configuration
+	ObjectStores string `json:"objectStores" env:"OBJECT_STORES"`
+
+	// CredentialsNamespaces is the comma separated list of the namespaces
+	// clusters are allowed to copy the secrets of the object store
+	// credentials and of the certificates from
+	CredentialsNamespaces string `json:"credentialsNamespaces" env:"CREDENTIALS_NAMESPACES"`
+
+	// InstanceStatusConnectionTimeout is the time, in seconds, the operator
+	// waits for a connection to the instance manager when collecting the
+	// status of an instance
+	InstanceStatusConnectionTimeout int `json:"instanceStatusConnectionTimeout" env:"INSTANCE_STATUS_CONNECTION_TIMEOUT"` //nolint
+
+	// InstanceStatusRequestTimeout is the time, in seconds, the operator
+	// waits for the status of an instance. When it expires the queries
+	// running inside the instance are canceled, and the instance is
+	// considered unhealthy
+	InstanceStatusRequestTimeout int `json:"instanceStatusRequestTimeout" env:"INSTANCE_STATUS_REQUEST_TIMEOUT"`
+
+	// WebhookFailurePolicy is the failure policy, `Fail` or `Ignore`, the
+	// operator sets in its validating and mutating webhook configurations.
+	// When empty, the one defined in the installation manifest is kept
+	WebhookFailurePolicy string `json:"webhookFailurePolicy" env:"WEBHOOK_FAILURE_POLICY"`
+
+	// WebhookNamespaceSelector is a label selector, in the same format
+	// accepted by kubectl, the operator sets as namespace selector in
+	// its validating and mutating webhook configurations. When empty,
+	// the one defined in the installation manifest is kept
+	WebhookNamespaceSelector string `json:"webhookNamespaceSelector" env:"WEBHOOK_NAMESPACE_SELECTOR"`
+
+	// DefaultStorageSize is the size of the PGDATA volume the defaulting
+	// webhook sets in the clusters that specify it neither directly nor
+	// in the PVC template
+	DefaultStorageSize string `json:"defaultStorageSize" env:"DEFAULT_STORAGE_SIZE"`
+
+	// PendingWALArchiveThreshold is the number of WAL files waiting to be
+	// archived in an instance beyond which the `WALArchivingBacklog`
+	// condition of the cluster is set
+	PendingWALArchiveThreshold int `json:"pendingWALArchiveThreshold" env:"PENDING_WAL_ARCHIVE_THRESHOLD"`
+
+	// EnableInventoryAPI enables the read-only HTTP API exposing the
+	// health and the backup status of the managed clusters on the
+	// webhook server
+	EnableInventoryAPI bool `json:"enableInventoryAPI" env:"ENABLE_INVENTORY_API"`
 }
 
 // Current is the configuration used by the operator
@@ -95,6 +194,11 @@ func newDefaultConfig() *Data {
 		OperatorPullSecretName: DefaultOperatorPullSecretName,
 		OperatorImageName:      versions.DefaultOperatorImageName,
 		PostgresImageName:      versions.DefaultImageName,
+		DefaultStorageSize:     DefaultStorageSize,
+
+		InstanceStatusConnectionTimeout: DefaultInstanceStatusConnectionTimeout,
+		InstanceStatusRequestTimeout:    DefaultInstanceStatusRequestTimeout,
+		PendingWALArchiveThreshold:      DefaultPendingWALArchiveThreshold,
 	}
 }
 
@@ -123,6 +227,127 @@ func (config *Data) IsLabelInherited(name string) bool {
 	return evaluateGlobPatterns(config.InheritedLabels, name)
 }
 
+// GetInstanceStatusConnectionTimeout gets the connection timeout used
+// when requesting the status of an instance, ignoring invalid values
+func (config *Data) GetInstanceStatusConnectionTimeout() time.Duration {
+	if config.InstanceStatusConnectionTimeout <= 0 {
+		return DefaultInstanceStatusConnectionTimeout * time.Second
+	}
+	return time.Duration(config.InstanceStatusConnectionTimeout) * time.Second
+}
+
+// GetInstanceStatusRequestTimeout gets the timeout of a request for the
+// status of an instance, ignoring invalid values
+func (config *Data) GetInstanceStatusRequestTimeout() time.Duration {
+	if config.InstanceStatusRequestTimeout <= 0 {
+		return DefaultInstanceStatusRequestTimeout * time.Second
+	}
+	return time.Duration(config.InstanceStatusRequestTimeout) * time.Second
+}
+
+// GetPendingWALArchiveThreshold gets the number of WAL files waiting to be
+// archived beyond which the archiving is falling behind, ignoring invalid values
+func (config *Data) GetPendingWALArchiveThreshold() int {
+	if config.PendingWALArchiveThreshold <= 0 {
+		return DefaultPendingWALArchiveThreshold
+	}
+	return config.PendingWALArchiveThreshold
+}
+
+// Validate checks the values of the configuration that can't be fixed
+// by falling back to the default ones
+func (config *Data) Validate() error {
+	if config.ImageRegistry != "" && !imageRegistryRegex.MatchString(config.ImageRegistry) {
+		return fmt.Errorf("invalid image registry %q, it must be a registry host, "+
+			"with an optional port and path, like registry.example.com:5000/mirror", config.ImageRegistry)
+	}
+
+	switch corev1.PullPolicy(config.ImagePullPolicy) {
+	case corev1.PullAlways, corev1.PullNever, corev1.PullIfNotPresent, "":
+	default:
+		return fmt.Errorf("invalid image pull policy %q, the allowed values are %q, %q and %q",
+			config.ImagePullPolicy, corev1.PullAlways, corev1.PullNever, corev1.PullIfNotPresent)
+	}
+
+	return nil
+}
+
+// GetPostgresImageName gets the name of the PostgreSQL image used by
+// default, pulled from the configured registry if any
+func (config *Data) GetPostgresImageName() string {
+	if config.ImageRegistry == "" {
+		return config.PostgresImageName
+	}
+
+	return replaceImageRegistry(config.PostgresImageName, config.ImageRegistry)
+}
+
+// replaceImageRegistry replaces the registry of an image with the passed
+// one. Images without a registry are considered as coming from Docker Hub,
+// where the official images are stored under the "library" path
+func replaceImageRegistry(imageName string, registry string) string {
+	repository := imageName
+	if firstComponent, rest, found := strings.Cut(imageName, "/"); !found {
+		repository = "library/" + imageName
+	} else if strings.ContainsAny(firstComponent, ".:") || firstComponent == "localhost" {
+		repository = rest
+	}
+
+	return strings.TrimSuffix(registry, "/") + "/" + repository
+}
+
+// GetWebhookFailurePolicy gets the failure policy to be set in the
+// webhook configurations, or nil if it is not configured
+func (config *Data) GetWebhookFailurePolicy() (*admissionregistrationv1.FailurePolicyType, error) {
+	if config.WebhookFailurePolicy == "" {
+		return nil, nil
+	}
+
+	failurePolicy := admissionregistrationv1.FailurePolicyType(config.WebhookFailurePolicy)
+	switch failurePolicy {
+	case admissionregistrationv1.Fail, admissionregistrationv1.Ignore:
+		return &failurePolicy, nil
+	default:
+		return nil, fmt.Errorf("invalid webhook failure policy %q, the allowed values are %q and %q",
+			config.WebhookFailurePolicy, admissionregistrationv1.Fail, admissionregistrationv1.Ignore)
+	}
+}
+
+// GetWebhookNamespaceSelector gets the namespace selector to be set in the
+// webhook configurations, or nil if it is not configured
+func (config *Data) GetWebhookNamespaceSelector() (*metav1.LabelSelector, error) {
+	if strings.TrimSpace(config.WebhookNamespaceSelector) == "" {
+		return nil, nil
+	}
+
+	selector, err := metav1.ParseToLabelSelector(config.WebhookNamespaceSelector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid webhook namespace selector %q: %w", config.WebhookNamespaceSelector, err)
+	}
+
+	return selector, nil
+}
+
+// GetObjectStore decodes into the passed target the object store with
+// the passed name, among the ones defined in the operator configuration
+func (config *Data) GetObjectStore(name string, target interface{}) error {
+	var objectStores map[string]json.RawMessage
+	if err := yaml.Unmarshal([]byte(config.ObjectStores), &objectStores); err != nil {
+		return fmt.Errorf("while parsing the object stores of the operator configuration: %w", err)
+	}
+
+	objectStore, ok := objectStores[name]
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrObjectStoreNotFound, name)
+	}
+
+	if err := json.Unmarshal(objectStore, target); err != nil {
+		return fmt.Errorf("while parsing the object store %q of the operator configuration: %w", name, err)
+	}
+
+	return nil
+}
+
 // WatchedNamespaces get the list of additional watched namespaces.
 // The result is a list of namespaces specified in the WATCHED_NAMESPACE where
 // each namespace is separated by comma
@@ -130,6 +355,18 @@ func (config *Data) WatchedNamespaces() []string {
 	return cleanNamespaceList(config.WatchNamespace)
 }
 
+// GetCredentialsNamespaces gets the list of the namespaces clusters are
+// allowed to copy the secrets from, separated by comma in CREDENTIALS_NAMESPACES
+func (config *Data) GetCredentialsNamespaces() []string {
+	return cleanNamespaceList(config.CredentialsNamespaces)
+}
+
+// IsCredentialsNamespaceAllowed checks if clusters are allowed to copy
+// the secrets from the passed namespace
+func (config *Data) IsCredentialsNamespaceAllowed(namespace string) bool {
+	return slices.Contains(config.GetCredentialsNamespaces(), namespace)
+}
+
 func cleanNamespaceList(namespaces string) (result []string) {
 	unfilteredList := strings.Split(namespaces, ",")
 	result = make([]string, 0, len(unfilteredList))
@@ -17,6 +17,11 @@ limitations under the License.
 package configuration
 
 import (
+	"time"
+
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 )
@@ -127,4 +132,137 @@ var _ = Describe("Annotation and label inheritance", func() {
 			}))
 		})
 	})
+
+	Context("credentials namespaces", func() {
+		It("allows only the configured namespaces", func() {
+			config := Data{
+				CredentialsNamespaces: " credentials, ,shared-credentials",
+			}
+			Expect(config.GetCredentialsNamespaces()).To(Equal([]string{"credentials", "shared-credentials"}))
+			Expect(config.IsCredentialsNamespaceAllowed("credentials")).To(BeTrue())
+			Expect(config.IsCredentialsNamespaceAllowed("kube-system")).To(BeFalse())
+		})
+
+		It("allows no namespace by default", func() {
+			config := Data{}
+			Expect(config.GetCredentialsNamespaces()).To(BeEmpty())
+			Expect(config.IsCredentialsNamespaceAllowed("")).To(BeFalse())
+		})
+	})
+
+	Context("instance status timeouts", func() {
+		It("uses the configured values", func() {
+			config := Data{
+				InstanceStatusConnectionTimeout: 5,
+				InstanceStatusRequestTimeout:    10,
+			}
+			Expect(config.GetInstanceStatusConnectionTimeout()).To(Equal(5 * time.Second))
+			Expect(config.GetInstanceStatusRequestTimeout()).To(Equal(10 * time.Second))
+		})
+
+		It("falls back to the defaults when the values are invalid", func() {
+			config := Data{
+				InstanceStatusConnectionTimeout: 0,
+				InstanceStatusRequestTimeout:    -1,
+			}
+			Expect(config.GetInstanceStatusConnectionTimeout()).To(Equal(2 * time.Second))
+			Expect(config.GetInstanceStatusRequestTimeout()).To(Equal(30 * time.Second))
+		})
+	})
+
+	Context("webhook settings", func() {
+		It("doesn't override the webhook settings when not configured", func() {
+			config := Data{}
+			Expect(config.GetWebhookFailurePolicy()).To(BeNil())
+			Expect(config.GetWebhookNamespaceSelector()).To(BeNil())
+		})
+
+		It("parses the configured failure policy", func() {
+			config := Data{WebhookFailurePolicy: "Ignore"}
+			failurePolicy, err := config.GetWebhookFailurePolicy()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(*failurePolicy).To(Equal(admissionregistrationv1.Ignore))
+		})
+
+		It("rejects an invalid failure policy", func() {
+			config := Data{WebhookFailurePolicy: "ignore"}
+			_, err := config.GetWebhookFailurePolicy()
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("parses the configured namespace selector", func() {
+			config := Data{WebhookNamespaceSelector: "kubernetes.io/metadata.name notin (kube-system,kube-public)"}
+			selector, err := config.GetWebhookNamespaceSelector()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(selector.MatchExpressions).To(HaveLen(1))
+			Expect(selector.MatchExpressions[0].Key).To(Equal("kubernetes.io/metadata.name"))
+			Expect(selector.MatchExpressions[0].Operator).To(Equal(metav1.LabelSelectorOpNotIn))
+			Expect(selector.MatchExpressions[0].Values).To(ConsistOf("kube-system", "kube-public"))
+		})
+
+		It("rejects an invalid namespace selector", func() {
+			config := Data{WebhookNamespaceSelector: "name in (a"}
+			_, err := config.GetWebhookNamespaceSelector()
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Context("object stores", func() {
+		config := Data{ObjectStores: `
+shared:
+  destinationPath: s3://backups/
+  wal:
+    compression: gzip
+`}
+
+		It("decodes the object store with the passed name", func() {
+			var objectStore map[string]interface{}
+			Expect(config.GetObjectStore("shared", &objectStore)).To(Succeed())
+			Expect(objectStore).To(HaveKeyWithValue("destinationPath", "s3://backups/"))
+		})
+
+		It("complains when the object store is not defined", func() {
+			var objectStore map[string]interface{}
+			Expect(config.GetObjectStore("unknown", &objectStore)).To(MatchError(ErrObjectStoreNotFound))
+			Expect((&Data{}).GetObjectStore("shared", &objectStore)).To(MatchError(ErrObjectStoreNotFound))
+		})
+
+		It("complains when the object stores can't be parsed", func() {
+			var objectStore map[string]interface{}
+			err := (&Data{ObjectStores: "- shared"}).GetObjectStore("shared", &objectStore)
+			Expect(err).To(HaveOccurred())
+			Expect(err).ToNot(MatchError(ErrObjectStoreNotFound))
+		})
+	})
+
+	Context("image registry and pull policy", func() {
+		It("uses the default image when no registry is configured", func() {
+			config := Data{PostgresImageName: "ghcr.io/cloudnative-pg/postgresql:15.2"}
+			Expect(config.GetPostgresImageName()).To(Equal("ghcr.io/cloudnative-pg/postgresql:15.2"))
+		})
+
+		It("replaces the registry of the default image", func() {
+			config := Data{
+				PostgresImageName: "ghcr.io/cloudnative-pg/postgresql:15.2",
+				ImageRegistry:     "mirror.example.com:5000/ghcr/",
+			}
+			Expect(config.GetPostgresImageName()).To(Equal("mirror.example.com:5000/ghcr/cloudnative-pg/postgresql:15.2"))
+		})
+
+		It("handles images coming from Docker Hub", func() {
+			Expect(replaceImageRegistry("postgres:15", "mirror.example.com")).
+				To(Equal("mirror.example.com/library/postgres:15"))
+			Expect(replaceImageRegistry("example/postgres:15", "mirror.example.com")).
+				To(Equal("mirror.example.com/example/postgres:15"))
+		})
+
+		It("validates the registry and the pull policy", func() {
+			Expect((&Data{}).Validate()).To(Succeed())
+			Expect((&Data{ImageRegistry: "mirror.example.com:5000/ghcr", ImagePullPolicy: "Always"}).Validate()).
+				To(Succeed())
+			Expect((&Data{ImageRegistry: "https://mirror.example.com"}).Validate()).ToNot(Succeed())
+			Expect((&Data{ImageRegistry: "mirror.example.com/postgresql:15"}).Validate()).ToNot(Succeed())
+			Expect((&Data{ImagePullPolicy: "always"}).Validate()).ToNot(Succeed())
+		})
+	})
 })
@@ -0,0 +1,89 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package inventory
+
+import (
+	"context"
+	"fmt"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+)
+
+// +kubebuilder:rbac:groups=authentication.k8s.io,resources=tokenreviews,verbs=create
+// +kubebuilder:rbac:groups=authorization.k8s.io,resources=subjectaccessreviews,verbs=create
+
+// Authorizer checks whether a bearer token allows reading the inventory
+// of the clusters of a namespace, or of every namespace when empty
+type Authorizer interface {
+	Authorize(ctx context.Context, token, namespace string) (bool, error)
+}
+
+// KubernetesAuthorizer authenticates the bearer tokens via the TokenReview
+// API, and authorizes them via the SubjectAccessReview API, as the
+// Kubernetes API server would do. Reading the inventory requires the
+// permission to list the clusters
+type KubernetesAuthorizer struct {
+	client client.Client
+}
+
+// NewKubernetesAuthorizer creates a new KubernetesAuthorizer
+func NewKubernetesAuthorizer(cli client.Client) *KubernetesAuthorizer {
+	return &KubernetesAuthorizer{client: cli}
+}
+
+// Authorize implements the Authorizer interface
+func (authorizer *KubernetesAuthorizer) Authorize(ctx context.Context, token, namespace string) (bool, error) {
+	tokenReview := &authenticationv1.TokenReview{
+		Spec: authenticationv1.TokenReviewSpec{Token: token},
+	}
+	if err := authorizer.client.Create(ctx, tokenReview); err != nil {
+		return false, fmt.Errorf("while reviewing the token: %w", err)
+	}
+	if !tokenReview.Status.Authenticated {
+		return false, nil
+	}
+
+	userInfo := tokenReview.Status.User
+	extra := make(map[string]authorizationv1.ExtraValue, len(userInfo.Extra))
+	for key, value := range userInfo.Extra {
+		extra[key] = authorizationv1.ExtraValue(value)
+	}
+
+	accessReview := &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			User:   userInfo.Username,
+			UID:    userInfo.UID,
+			Groups: userInfo.Groups,
+			Extra:  extra,
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace: namespace,
+				Verb:      "list",
+				Group:     apiv1.GroupVersion.Group,
+				Resource:  "clusters",
+			},
+		},
+	}
+	if err := authorizer.client.Create(ctx, accessReview); err != nil {
+		return false, fmt.Errorf("while reviewing the access of %s: %w", userInfo.Username, err)
+	}
+
+	return accessReview.Status.Allowed, nil
+}
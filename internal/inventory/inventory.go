@@ -0,0 +1,270 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package inventory implements the read-only HTTP API exposing the
+// clusters managed by the operator, together with their health and
+// backup status, for the dashboards which would otherwise need to
+// query the Kubernetes API server for every cluster
+package inventory
+
+import (
+	"sort"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+)
+
+const (
+	// RolePrimary is the role of the primary instance of a cluster
+	RolePrimary = "primary"
+
+	// RoleReplica is the role of the other instances of a cluster
+	RoleReplica = "replica"
+
+	// instanceStatusUnknown is the status of an instance which has not
+	// been reported in the status of the cluster yet
+	instanceStatusUnknown = "unknown"
+)
+
+// ClusterList is the list of the clusters managed by the operator
+type ClusterList struct {
+	Items []Cluster `json:"items"`
+}
+
+// Cluster is the summary of a cluster managed by the operator
+type Cluster struct {
+	// The name of the cluster
+	Name string `json:"name"`
+
+	// The namespace of the cluster
+	Namespace string `json:"namespace"`
+
+	// The phase of the cluster, and why it has been entered
+	Phase       string `json:"phase,omitempty"`
+	PhaseReason string `json:"phaseReason,omitempty"`
+
+	// Whether the cluster has the requested instances, and the primary
+	// instance is ready
+	Ready bool `json:"ready"`
+
+	// The PostgreSQL image of the cluster
+	Image string `json:"image,omitempty"`
+
+	// The current primary instance, and the one being promoted during a
+	// switchover or a failover
+	CurrentPrimary string `json:"currentPrimary,omitempty"`
+	TargetPrimary  string `json:"targetPrimary,omitempty"`
+
+	// The number of requested and ready instances, read replicas included
+	Instances      int `json:"instances"`
+	ReadyInstances int `json:"readyInstances"`
+
+	// The health of every instance
+	InstancesHealth []Instance `json:"instancesHealth,omitempty"`
+
+	// The backup status of the cluster
+	Backup Backup `json:"backup"`
+}
+
+// Instance is the health of an instance of a cluster
+type Instance struct {
+	// The name of the instance
+	Name string `json:"name"`
+
+	// The role of the instance, `primary` or `replica`
+	Role string `json:"role"`
+
+	// The status of the Pod of the instance, among `healthy`,
+	// `replicating`, `failed` and `unknown`
+	Status string `json:"status"`
+
+	// The timeline the instance is on
+	TimelineID int `json:"timelineID,omitempty"`
+
+	// The number of WAL files waiting to be archived
+	PendingWALFiles int `json:"pendingWALFiles,omitempty"`
+}
+
+// Backup is the backup status of a cluster
+type Backup struct {
+	// Whether the backups are configured for the cluster
+	Configured bool `json:"configured"`
+
+	// The status of the `ContinuousArchiving` condition, empty when
+	// it has not been reported yet
+	ContinuousArchiving metav1.ConditionStatus `json:"continuousArchiving,omitempty"`
+
+	// The first point in time the cluster can be recovered to
+	FirstRecoverabilityPoint string `json:"firstRecoverabilityPoint,omitempty"`
+
+	// The last backup which completed successfully
+	LastSuccessfulBackup *BackupReference `json:"lastSuccessfulBackup,omitempty"`
+
+	// The last backup which failed
+	LastFailedBackup *BackupReference `json:"lastFailedBackup,omitempty"`
+}
+
+// BackupReference describes a Backup object of a cluster
+type BackupReference struct {
+	// The name of the Backup object
+	Name string `json:"name"`
+
+	// When the backup has been started and stopped
+	StartedAt *metav1.Time `json:"startedAt,omitempty"`
+	StoppedAt *metav1.Time `json:"stoppedAt,omitempty"`
+
+	// The error of a failed backup
+	Error string `json:"error,omitempty"`
+}
+
+// NewClusterList creates the summaries of a list of clusters, given
+// the backups of their namespaces
+func NewClusterList(clusters []apiv1.Cluster, backups []apiv1.Backup) ClusterList {
+	result := ClusterList{Items: make([]Cluster, 0, len(clusters))}
+	for idx := range clusters {
+		result.Items = append(result.Items, NewCluster(&clusters[idx], backups))
+	}
+
+	sort.Slice(result.Items, func(i, j int) bool {
+		if result.Items[i].Namespace != result.Items[j].Namespace {
+			return result.Items[i].Namespace < result.Items[j].Namespace
+		}
+		return result.Items[i].Name < result.Items[j].Name
+	})
+
+	return result
+}
+
+// NewCluster creates the summary of a cluster, given the backups of its
+// namespace. The backups of the other clusters are ignored
+func NewCluster(cluster *apiv1.Cluster, backups []apiv1.Backup) Cluster {
+	return Cluster{
+		Name:        cluster.Name,
+		Namespace:   cluster.Namespace,
+		Phase:       cluster.Status.Phase,
+		PhaseReason: cluster.Status.PhaseReason,
+		Ready: meta.IsStatusConditionTrue(cluster.Status.Conditions,
+			string(apiv1.ConditionClusterReady)),
+		Image:           cluster.GetImageName(),
+		CurrentPrimary:  cluster.Status.CurrentPrimary,
+		TargetPrimary:   cluster.Status.TargetPrimary,
+		Instances:       cluster.GetDesiredInstances(),
+		ReadyInstances:  cluster.Status.ReadyInstances,
+		InstancesHealth: newInstances(cluster),
+		Backup:          newBackup(cluster, backups),
+	}
+}
+
+// newInstances creates the health of the instances of a cluster from
+// its status
+func newInstances(cluster *apiv1.Cluster) []Instance {
+	podStatuses := make(map[string]string)
+	for podStatus, names := range cluster.Status.InstancesStatus {
+		for _, name := range names {
+			podStatuses[name] = string(podStatus)
+		}
+	}
+
+	instances := make([]Instance, 0, len(cluster.Status.InstanceNames))
+	for _, name := range cluster.Status.InstanceNames {
+		instance := Instance{
+			Name:   name,
+			Role:   RoleReplica,
+			Status: instanceStatusUnknown,
+		}
+		if podStatus, ok := podStatuses[name]; ok {
+			instance.Status = podStatus
+		}
+
+		reportedState, ok := cluster.Status.InstancesReportedState[apiv1.PodName(name)]
+		if ok {
+			instance.TimelineID = reportedState.TimeLineID
+			instance.PendingWALFiles = reportedState.PendingWALFiles
+		}
+		if name == cluster.Status.CurrentPrimary || (ok && reportedState.IsPrimary) {
+			instance.Role = RolePrimary
+		}
+
+		instances = append(instances, instance)
+	}
+
+	return instances
+}
+
+// newBackup creates the backup status of a cluster from its status, and
+// from the most recent backups which completed and which failed
+func newBackup(cluster *apiv1.Cluster, backups []apiv1.Backup) Backup {
+	result := Backup{
+		Configured:               cluster.Spec.Backup != nil,
+		FirstRecoverabilityPoint: cluster.Status.FirstRecoverabilityPoint,
+	}
+	if condition := meta.FindStatusCondition(cluster.Status.Conditions,
+		string(apiv1.ConditionContinuousArchiving)); condition != nil {
+		result.ContinuousArchiving = condition.Status
+	}
+
+	var lastCompleted, lastFailed *apiv1.Backup
+	for idx := range backups {
+		backup := &backups[idx]
+		if backup.Namespace != cluster.Namespace || backup.Spec.Cluster.Name != cluster.Name {
+			continue
+		}
+
+		switch backup.Status.Phase {
+		case apiv1.BackupPhaseCompleted:
+			if lastCompleted == nil || getBackupTime(lastCompleted).Before(getBackupTime(backup)) {
+				lastCompleted = backup
+			}
+		case apiv1.BackupPhaseFailed:
+			if lastFailed == nil || getBackupTime(lastFailed).Before(getBackupTime(backup)) {
+				lastFailed = backup
+			}
+		}
+	}
+
+	result.LastSuccessfulBackup = newBackupReference(lastCompleted)
+	result.LastFailedBackup = newBackupReference(lastFailed)
+	return result
+}
+
+// newBackupReference creates the description of a Backup object
+func newBackupReference(backup *apiv1.Backup) *BackupReference {
+	if backup == nil {
+		return nil
+	}
+
+	return &BackupReference{
+		Name:      backup.Name,
+		StartedAt: backup.Status.StartedAt,
+		StoppedAt: backup.Status.StoppedAt,
+		Error:     backup.Status.Error,
+	}
+}
+
+// getBackupTime gets when a backup finished, falling back to when it
+// started or has been created for the backups failed before starting
+func getBackupTime(backup *apiv1.Backup) *metav1.Time {
+	switch {
+	case backup.Status.StoppedAt != nil:
+		return backup.Status.StoppedAt
+	case backup.Status.StartedAt != nil:
+		return backup.Status.StartedAt
+	default:
+		return &backup.CreationTimestamp
+	}
+}
@@ -0,0 +1,122 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package inventory
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func newTestBackup(name, clusterName string, phase apiv1.BackupPhase, stoppedAt time.Time) apiv1.Backup {
+	stopped := metav1.NewTime(stoppedAt)
+	return apiv1.Backup{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec: apiv1.BackupSpec{
+			Cluster: apiv1.LocalObjectReference{Name: clusterName},
+		},
+		Status: apiv1.BackupStatus{
+			Phase:     phase,
+			StoppedAt: &stopped,
+		},
+	}
+}
+
+var _ = Describe("cluster inventory", func() {
+	now := time.Date(2023, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	cluster := &apiv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+		Spec: apiv1.ClusterSpec{
+			Instances: 2,
+			Backup:    &apiv1.BackupConfiguration{},
+		},
+		Status: apiv1.ClusterStatus{
+			Phase:          apiv1.PhaseHealthy,
+			ReadyInstances: 1,
+			CurrentPrimary: "cluster-example-1",
+			TargetPrimary:  "cluster-example-1",
+			InstanceNames:  []string{"cluster-example-1", "cluster-example-2"},
+			InstancesStatus: map[utils.PodStatus][]string{
+				utils.PodHealthy: {"cluster-example-1"},
+			},
+			InstancesReportedState: map[apiv1.PodName]apiv1.InstanceReportedState{
+				"cluster-example-1": {IsPrimary: true, TimeLineID: 2},
+			},
+			Conditions: []metav1.Condition{
+				{Type: string(apiv1.ConditionClusterReady), Status: metav1.ConditionFalse},
+				{Type: string(apiv1.ConditionContinuousArchiving), Status: metav1.ConditionTrue},
+			},
+		},
+	}
+
+	It("reports the health of the instances", func() {
+		summary := NewCluster(cluster, nil)
+		Expect(summary.Name).To(Equal("cluster-example"))
+		Expect(summary.Phase).To(Equal(apiv1.PhaseHealthy))
+		Expect(summary.Ready).To(BeFalse())
+		Expect(summary.Instances).To(Equal(2))
+		Expect(summary.ReadyInstances).To(Equal(1))
+		Expect(summary.InstancesHealth).To(Equal([]Instance{
+			{Name: "cluster-example-1", Role: RolePrimary, Status: string(utils.PodHealthy), TimelineID: 2},
+			{Name: "cluster-example-2", Role: RoleReplica, Status: instanceStatusUnknown},
+		}))
+	})
+
+	It("counts the read replicas among the requested instances", func() {
+		withReadReplicas := cluster.DeepCopy()
+		withReadReplicas.Spec.ReadReplicas = &apiv1.ReadReplicasConfiguration{Instances: 1}
+		Expect(NewCluster(withReadReplicas, nil).Instances).To(Equal(3))
+	})
+
+	It("reports the most recent backups of the cluster", func() {
+		backups := []apiv1.Backup{
+			newTestBackup("old", "cluster-example", apiv1.BackupPhaseCompleted, now.Add(-2*time.Hour)),
+			newTestBackup("new", "cluster-example", apiv1.BackupPhaseCompleted, now.Add(-time.Hour)),
+			newTestBackup("failed", "cluster-example", apiv1.BackupPhaseFailed, now),
+			newTestBackup("other", "another-cluster", apiv1.BackupPhaseCompleted, now),
+		}
+
+		backup := NewCluster(cluster, backups).Backup
+		Expect(backup.Configured).To(BeTrue())
+		Expect(backup.ContinuousArchiving).To(Equal(metav1.ConditionTrue))
+		Expect(backup.LastSuccessfulBackup).ToNot(BeNil())
+		Expect(backup.LastSuccessfulBackup.Name).To(Equal("new"))
+		Expect(backup.LastFailedBackup).ToNot(BeNil())
+		Expect(backup.LastFailedBackup.Name).To(Equal("failed"))
+	})
+
+	It("sorts the clusters by namespace and name", func() {
+		clusters := []apiv1.Cluster{
+			{ObjectMeta: metav1.ObjectMeta{Name: "b", Namespace: "default"}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "a", Namespace: "prod"}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "a", Namespace: "default"}},
+		}
+
+		list := NewClusterList(clusters, nil)
+		Expect(list.Items).To(HaveLen(3))
+		Expect(list.Items[0].Namespace + "/" + list.Items[0].Name).To(Equal("default/a"))
+		Expect(list.Items[1].Namespace + "/" + list.Items[1].Name).To(Equal("default/b"))
+		Expect(list.Items[2].Namespace + "/" + list.Items[2].Name).To(Equal("prod/a"))
+	})
+})
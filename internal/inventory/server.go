@@ -0,0 +1,170 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package inventory
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// BasePath is the path under which the inventory API is served:
+//
+//   - `clusters` lists the clusters of every namespace, or of the
+//     one passed in the `namespace` query parameter
+//   - `namespaces/<namespace>/clusters` lists the clusters of a namespace
+//   - `namespaces/<namespace>/clusters/<name>` gets a cluster
+const BasePath = "/inventory/v1/"
+
+// Handler serves the inventory API, reading the clusters and the
+// backups from the cache of the operator, without loading the
+// Kubernetes API server
+type Handler struct {
+	reader     client.Reader
+	authorizer Authorizer
+}
+
+// NewHandler creates a new Handler
+func NewHandler(reader client.Reader, authorizer Authorizer) *Handler {
+	return &Handler{
+		reader:     reader,
+		authorizer: authorizer,
+	}
+}
+
+// Register registers the handler of the inventory API on a mux
+func Register(mux *http.ServeMux, handler http.Handler) {
+	mux.Handle(BasePath, handler)
+}
+
+// ServeHTTP implements the http.Handler interface
+func (handler *Handler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		http.Error(w, "only GET requests are supported", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var namespace, name string
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(req.URL.Path, BasePath), "/"), "/")
+	switch {
+	case len(parts) == 1 && parts[0] == "clusters":
+		namespace = req.URL.Query().Get("namespace")
+	case len(parts) == 3 && parts[0] == "namespaces" && parts[2] == "clusters":
+		namespace = parts[1]
+	case len(parts) == 4 && parts[0] == "namespaces" && parts[2] == "clusters":
+		namespace, name = parts[1], parts[3]
+	default:
+		http.NotFound(w, req)
+		return
+	}
+
+	if !handler.authorize(w, req, namespace) {
+		return
+	}
+
+	if name == "" {
+		handler.listClusters(w, req, namespace)
+		return
+	}
+	handler.getCluster(w, req, namespace, name)
+}
+
+// authorize checks the bearer token of a request, writing the error
+// response when the request is not allowed
+func (handler *Handler) authorize(w http.ResponseWriter, req *http.Request, namespace string) bool {
+	token, found := strings.CutPrefix(req.Header.Get("Authorization"), "Bearer ")
+	if !found || token == "" {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		http.Error(w, "a bearer token is required", http.StatusUnauthorized)
+		return false
+	}
+
+	allowed, err := handler.authorizer.Authorize(req.Context(), token, namespace)
+	if err != nil {
+		log.FromContext(req.Context()).Error(err, "while authorizing an inventory request")
+		http.Error(w, "cannot authorize the request", http.StatusInternalServerError)
+		return false
+	}
+	if !allowed {
+		http.Error(w, "the token is not allowed to list the clusters", http.StatusForbidden)
+		return false
+	}
+
+	return true
+}
+
+// listClusters writes the summaries of the clusters of a namespace,
+// or of every namespace when empty
+func (handler *Handler) listClusters(w http.ResponseWriter, req *http.Request, namespace string) {
+	var clusters apiv1.ClusterList
+	if err := handler.reader.List(req.Context(), &clusters, client.InNamespace(namespace)); err != nil {
+		handler.writeError(w, req, err)
+		return
+	}
+
+	var backups apiv1.BackupList
+	if err := handler.reader.List(req.Context(), &backups, client.InNamespace(namespace)); err != nil {
+		handler.writeError(w, req, err)
+		return
+	}
+
+	writeJSON(w, req, NewClusterList(clusters.Items, backups.Items))
+}
+
+// getCluster writes the summary of a cluster
+func (handler *Handler) getCluster(w http.ResponseWriter, req *http.Request, namespace, name string) {
+	var cluster apiv1.Cluster
+	if err := handler.reader.Get(req.Context(), client.ObjectKey{Namespace: namespace, Name: name},
+		&cluster); err != nil {
+		handler.writeError(w, req, err)
+		return
+	}
+
+	var backups apiv1.BackupList
+	if err := handler.reader.List(req.Context(), &backups, client.InNamespace(namespace)); err != nil {
+		handler.writeError(w, req, err)
+		return
+	}
+
+	writeJSON(w, req, NewCluster(&cluster, backups.Items))
+}
+
+// writeError writes the response of a request whose objects can't be read
+func (handler *Handler) writeError(w http.ResponseWriter, req *http.Request, err error) {
+	if apierrs.IsNotFound(err) {
+		http.NotFound(w, req)
+		return
+	}
+
+	log.FromContext(req.Context()).Error(err, "while reading the inventory")
+	http.Error(w, "cannot read the inventory", http.StatusInternalServerError)
+}
+
+// writeJSON writes a JSON response
+func writeJSON(w http.ResponseWriter, req *http.Request, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		log.FromContext(req.Context()).Error(err, "while writing the inventory")
+	}
+}
@@ -0,0 +1,117 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package inventory
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// fakeAuthorizer allows the "allowed" token to read every namespace,
+// and the "namespaced" token to only read the default namespace
+type fakeAuthorizer struct{}
+
+func (fakeAuthorizer) Authorize(_ context.Context, token, namespace string) (bool, error) {
+	return token == "allowed" || (token == "namespaced" && namespace == "default"), nil
+}
+
+var _ = Describe("inventory API", func() {
+	var mux *http.ServeMux
+
+	BeforeEach(func() {
+		fakeClient := fake.NewClientBuilder().
+			WithScheme(scheme.BuildWithAllKnownScheme()).
+			WithObjects(
+				&apiv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"}},
+				&apiv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "prod"}},
+			).
+			Build()
+
+		mux = http.NewServeMux()
+		Register(mux, NewHandler(fakeClient, fakeAuthorizer{}))
+	})
+
+	request := func(method, path, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		recorder := httptest.NewRecorder()
+		mux.ServeHTTP(recorder, req)
+		return recorder
+	}
+
+	It("lists the clusters of every namespace", func() {
+		response := request(http.MethodGet, BasePath+"clusters", "allowed")
+		Expect(response.Code).To(Equal(http.StatusOK))
+		Expect(response.Header().Get("Content-Type")).To(Equal("application/json"))
+
+		var list ClusterList
+		Expect(json.Unmarshal(response.Body.Bytes(), &list)).To(Succeed())
+		Expect(list.Items).To(HaveLen(2))
+	})
+
+	It("lists the clusters of a namespace", func() {
+		for _, path := range []string{"clusters?namespace=prod", "namespaces/prod/clusters"} {
+			response := request(http.MethodGet, BasePath+path, "allowed")
+			Expect(response.Code).To(Equal(http.StatusOK))
+
+			var list ClusterList
+			Expect(json.Unmarshal(response.Body.Bytes(), &list)).To(Succeed())
+			Expect(list.Items).To(HaveLen(1))
+			Expect(list.Items[0].Namespace).To(Equal("prod"))
+		}
+	})
+
+	It("gets a cluster", func() {
+		response := request(http.MethodGet, BasePath+"namespaces/default/clusters/cluster-example", "allowed")
+		Expect(response.Code).To(Equal(http.StatusOK))
+
+		var cluster Cluster
+		Expect(json.Unmarshal(response.Body.Bytes(), &cluster)).To(Succeed())
+		Expect(cluster.Name).To(Equal("cluster-example"))
+		Expect(cluster.Namespace).To(Equal("default"))
+
+		response = request(http.MethodGet, BasePath+"namespaces/default/clusters/missing", "allowed")
+		Expect(response.Code).To(Equal(http.StatusNotFound))
+	})
+
+	It("requires an authorized bearer token", func() {
+		Expect(request(http.MethodGet, BasePath+"clusters", "").Code).To(Equal(http.StatusUnauthorized))
+		Expect(request(http.MethodGet, BasePath+"clusters", "denied").Code).To(Equal(http.StatusForbidden))
+		Expect(request(http.MethodGet, BasePath+"clusters", "namespaced").Code).To(Equal(http.StatusForbidden))
+		Expect(request(http.MethodGet, BasePath+"namespaces/default/clusters", "namespaced").Code).
+			To(Equal(http.StatusOK))
+	})
+
+	It("only serves the read requests of the known paths", func() {
+		Expect(request(http.MethodPost, BasePath+"clusters", "allowed").Code).
+			To(Equal(http.StatusMethodNotAllowed))
+		Expect(request(http.MethodGet, BasePath+"backups", "allowed").Code).To(Equal(http.StatusNotFound))
+	})
+})
@@ -0,0 +1,161 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
+// reconcileAutovacuumTables applies the autovacuum storage parameters
+// declared for single tables. Storage parameters are only changed on
+// the primary, and reach the replicas through the streaming replication
+func (r *InstanceReconciler) reconcileAutovacuumTables(ctx context.Context, cluster *apiv1.Cluster) error {
+	autovacuum := cluster.Spec.PostgresConfiguration.Autovacuum
+	if autovacuum == nil || len(autovacuum.Tables) == 0 {
+		return nil
+	}
+
+	ok, err := r.instance.IsPrimary()
+	if err != nil {
+		return fmt.Errorf("unable to check if instance is primary: %w", err)
+	}
+	if !ok {
+		return nil
+	}
+
+	var errs []error
+	for _, table := range autovacuum.Tables {
+		db, err := r.instance.ConnectionPool().Connection(table.DBName)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("could not connect to database %s: %w", table.DBName, err))
+			continue
+		}
+
+		if err := applyAutovacuumStorageParameters(ctx, db, table); err != nil {
+			errs = append(errs, fmt.Errorf("while applying the autovacuum settings of table %s in database %s: %w",
+				table.Name, table.DBName, err))
+		}
+	}
+
+	if errs != nil {
+		return fmt.Errorf("got errors while applying the autovacuum settings of the tables: %v", errs)
+	}
+
+	return nil
+}
+
+// applyAutovacuumStorageParameters sets the autovacuum storage parameters
+// of a table which are different from the declared ones, and resets the
+// ones which are not declared
+func applyAutovacuumStorageParameters(
+	ctx context.Context,
+	db *sql.DB,
+	table apiv1.AutovacuumTableConfiguration,
+) error {
+	schemaName, tableName := "public", table.Name
+	if before, after, found := strings.Cut(table.Name, "."); found {
+		schemaName, tableName = before, after
+	}
+
+	current, err := getAutovacuumStorageParameters(ctx, db, schemaName, tableName)
+	if err != nil {
+		return err
+	}
+
+	var set, reset []string
+	for name, value := range table.Parameters {
+		if currentValue, ok := current[name]; !ok || currentValue != value {
+			set = append(set, fmt.Sprintf("%s = %s", name, value))
+		}
+	}
+	for name := range current {
+		if _, ok := table.Parameters[name]; !ok {
+			reset = append(reset, name)
+		}
+	}
+	if len(set) == 0 && len(reset) == 0 {
+		return nil
+	}
+	sort.Strings(set)
+	sort.Strings(reset)
+
+	identifier := pgx.Identifier{schemaName, tableName}.Sanitize()
+	var actions []string
+	if len(set) > 0 {
+		actions = append(actions, fmt.Sprintf("SET (%s)", strings.Join(set, ", ")))
+	}
+	if len(reset) > 0 {
+		actions = append(actions, fmt.Sprintf("RESET (%s)", strings.Join(reset, ", ")))
+	}
+
+	log.FromContext(ctx).Info("Updating the autovacuum storage parameters of table",
+		"dbname", table.DBName, "table", identifier, "set", set, "reset", reset)
+	_, err = db.ExecContext(ctx, fmt.Sprintf("ALTER TABLE %s %s", identifier, strings.Join(actions, ", ")))
+	return err
+}
+
+// getAutovacuumStorageParameters reads the autovacuum storage parameters
+// of a table and of its TOAST table, the latter with the `toast.` prefix
+func getAutovacuumStorageParameters(
+	ctx context.Context,
+	db *sql.DB,
+	schemaName, tableName string,
+) (map[string]string, error) {
+	rows, err := db.QueryContext(ctx,
+		"SELECT option FROM pg_catalog.pg_class c "+
+			"JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace, "+
+			"LATERAL unnest(c.reloptions) AS option "+
+			"WHERE n.nspname = $1 AND c.relname = $2 "+
+			"UNION ALL "+
+			"SELECT 'toast.' || option FROM pg_catalog.pg_class c "+
+			"JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace "+
+			"JOIN pg_catalog.pg_class t ON t.oid = c.reltoastrelid, "+
+			"LATERAL unnest(t.reloptions) AS option "+
+			"WHERE n.nspname = $1 AND c.relname = $2",
+		schemaName, tableName)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	parameters := make(map[string]string)
+	for rows.Next() {
+		var option string
+		if err := rows.Scan(&option); err != nil {
+			return nil, err
+		}
+
+		name, value, _ := strings.Cut(option, "=")
+		if postgres.IsAutovacuumStorageParameter(name) {
+			parameters[name] = value
+		}
+	}
+
+	return parameters, rows.Err()
+}
@@ -0,0 +1,86 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("autovacuum storage parameters of tables", func() {
+	const readParametersQuery = "SELECT option FROM pg_catalog.pg_class"
+
+	var (
+		db   *sql.DB
+		mock sqlmock.Sqlmock
+		ctx  context.Context
+	)
+
+	BeforeEach(func() {
+		var err error
+		db, mock, err = sqlmock.New()
+		Expect(err).ToNot(HaveOccurred())
+		ctx = context.Background()
+	})
+
+	AfterEach(func() {
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	It("sets the changed parameters and resets the undeclared ones", func() {
+		mock.ExpectQuery(readParametersQuery).
+			WithArgs("sales", "orders").
+			WillReturnRows(sqlmock.NewRows([]string{"option"}).
+				AddRow("autovacuum_vacuum_scale_factor=0.2").
+				AddRow("toast.autovacuum_enabled=false").
+				AddRow("fillfactor=70"))
+		mock.ExpectExec(regexp.QuoteMeta(
+			`ALTER TABLE "sales"."orders" SET (autovacuum_analyze_threshold = 100, ` +
+				`autovacuum_vacuum_scale_factor = 0.01), RESET (toast.autovacuum_enabled)`)).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		Expect(applyAutovacuumStorageParameters(ctx, db, apiv1.AutovacuumTableConfiguration{
+			DBName: "app",
+			Name:   "sales.orders",
+			Parameters: map[string]string{
+				"autovacuum_vacuum_scale_factor": "0.01",
+				"autovacuum_analyze_threshold":   "100",
+			},
+		})).To(Succeed())
+	})
+
+	It("does nothing when the parameters match", func() {
+		mock.ExpectQuery(readParametersQuery).
+			WithArgs("public", "orders").
+			WillReturnRows(sqlmock.NewRows([]string{"option"}).
+				AddRow("autovacuum_vacuum_scale_factor=0.01"))
+
+		Expect(applyAutovacuumStorageParameters(ctx, db, apiv1.AutovacuumTableConfiguration{
+			DBName:     "app",
+			Name:       "orders",
+			Parameters: map[string]string{"autovacuum_vacuum_scale_factor": "0.01"},
+		})).To(Succeed())
+	})
+})
@@ -27,7 +27,9 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/walrestore"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/management/cache"
 	barmanCredentials "github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/credentials"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/customarchive"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/walg"
 )
 
 // updateCacheFromCluster will update the internal cache with the cluster
@@ -54,6 +56,20 @@ func (r *InstanceReconciler) updateWALRestoreSettingsCache(
 	ctx context.Context,
 	cluster *apiv1.Cluster,
 ) {
+	if walGConfiguration := walrestore.GetWalGRecoverConfiguration(cluster, r.instance.PodName); walGConfiguration != nil {
+		envRestore, err := walg.EnvSetCredentials(
+			ctx,
+			r.GetClient(),
+			cluster.Namespace,
+			walGConfiguration,
+			os.Environ())
+		if err != nil {
+			log.Error(err, "while getting recover credentials")
+		}
+		cache.Store(cache.WALRestoreKey, envRestore)
+		return
+	}
+
 	_, env, barmanConfiguration, err := walrestore.GetRecoverConfiguration(cluster, r.instance.PodName)
 	if errors.Is(err, walrestore.ErrNoBackupConfigured) {
 		cache.Delete(cache.WALRestoreKey)
@@ -86,18 +102,42 @@ func (r *InstanceReconciler) shouldUpdateWALArchiveSettingsCache(
 	ctx context.Context,
 	cluster *apiv1.Cluster,
 ) (shouldRetry bool) {
-	if cluster.Spec.Backup == nil || cluster.Spec.Backup.BarmanObjectStore == nil {
+	var envArchive []string
+	var err error
+
+	switch {
+	case cluster.Spec.Backup.IsCustomWalArchiveEnabled():
+		// Populate the cache with the environment of the custom command
+		envArchive, err = customarchive.EnvSetCredentials(
+			ctx,
+			r.GetClient(),
+			cluster.Namespace,
+			cluster.Spec.Backup.CustomWalArchive,
+			os.Environ())
+
+	case cluster.Spec.Backup.IsWalGEnabled():
+		// Populate the cache with the environment of wal-g
+		envArchive, err = walg.EnvSetCredentials(
+			ctx,
+			r.GetClient(),
+			cluster.Namespace,
+			cluster.Spec.Backup.WalG,
+			os.Environ())
+
+	case cluster.Spec.Backup != nil && cluster.Spec.Backup.BarmanObjectStore != nil:
+		// Populate the cache with the backup configuration
+		envArchive, err = barmanCredentials.EnvSetBackupCloudCredentials(
+			ctx,
+			r.GetClient(),
+			cluster.Namespace,
+			cluster.Spec.Backup.BarmanObjectStore,
+			os.Environ())
+
+	default:
 		cache.Delete(cache.WALArchiveKey)
 		return false
 	}
 
-	// Populate the cache with the backup configuration
-	envArchive, err := barmanCredentials.EnvSetBackupCloudCredentials(
-		ctx,
-		r.GetClient(),
-		cluster.Namespace,
-		cluster.Spec.Backup.BarmanObjectStore,
-		os.Environ())
 	if apierrors.IsForbidden(err) {
 		log.Info("backup credentials don't yet have access permissions. Will retry reconciliation loop")
 		return true
@@ -0,0 +1,75 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// configurationDriftCheckInterval is how often the instances check if
+// the configuration chosen by the operator has been overridden
+const configurationDriftCheckInterval = time.Minute
+
+// reconcileConfigurationDrift detects the parameters managed by the
+// operator which are overridden in postgresql.auto.conf. Depending on
+// the cluster policy they are reset, or only reported to the operator
+// through the instance status
+func (r *InstanceReconciler) reconcileConfigurationDrift(ctx context.Context, cluster *apiv1.Cluster) error {
+	contextLogger := log.FromContext(ctx)
+
+	drift, err := r.instance.DetectConfigurationDrift(ctx, cluster)
+	if err != nil {
+		return fmt.Errorf("while detecting the configuration drift: %w", err)
+	}
+
+	if len(drift) == 0 || cluster.GetConfigurationDriftPolicy() != apiv1.ConfigurationDriftPolicyEnforce {
+		if len(drift) > 0 {
+			contextLogger.Warning("Detected parameters overridden in postgresql.auto.conf",
+				"parameters", drift)
+		}
+		r.instance.SetConfigurationDrift(drift)
+		return nil
+	}
+
+	contextLogger.Info("Resetting parameters overridden in postgresql.auto.conf",
+		"parameters", drift)
+	if err := r.instance.ResetConfigurationParameters(ctx, drift); err != nil {
+		return err
+	}
+	if err := r.instance.Reload(); err != nil {
+		return fmt.Errorf("while reloading the instance: %w", err)
+	}
+	r.instance.SetConfigurationDrift(nil)
+
+	recorder, err := management.NewEventRecorder()
+	if err != nil {
+		contextLogger.Error(err, "Error while creating the event recorder")
+		return nil
+	}
+	recorder.Eventf(cluster, "Normal", "ConfigurationDriftReverted",
+		"Reset parameters overridden in postgresql.auto.conf on instance %v: %v",
+		r.instance.PodName, strings.Join(drift, ", "))
+
+	return nil
+}
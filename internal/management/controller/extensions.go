@@ -0,0 +1,272 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/lib/pq"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
+// extensionDefinition is the definition of an extension installed
+// in a database, as read from PostgreSQL
+type extensionDefinition struct {
+	version string
+	schema  string
+}
+
+// reconcileDeclaredExtensions creates, updates and drops the extensions
+// declared in the cluster specification, and reports their state in the
+// cluster status. Extensions are only managed on the primary, and are
+// only created once the libraries they need have been loaded
+func (r *InstanceReconciler) reconcileDeclaredExtensions(ctx context.Context, cluster *apiv1.Cluster) error {
+	if len(cluster.Spec.Extensions) == 0 && len(cluster.Status.Extensions) == 0 {
+		return nil
+	}
+
+	ok, err := r.instance.IsPrimary()
+	if err != nil {
+		return fmt.Errorf("unable to check if instance is primary: %w", err)
+	}
+	if !ok {
+		return nil
+	}
+
+	contextLogger := log.FromContext(ctx)
+
+	superUserDB, err := r.instance.GetSuperUserDB()
+	if err != nil {
+		return fmt.Errorf("getting the superuserdb: %w", err)
+	}
+
+	var loadedLibraries string
+	row := superUserDB.QueryRowContext(ctx, fmt.Sprintf("SHOW %s", postgres.SharedPreloadLibraries))
+	if err := row.Scan(&loadedLibraries); err != nil {
+		return fmt.Errorf("while reading the loaded libraries: %w", err)
+	}
+
+	declared := make(map[string]bool)
+	var extensions []apiv1.ExtensionStatus
+	for _, extension := range cluster.Spec.Extensions {
+		missingLibraries := getMissingLibraries(extension.GetSharedPreloadLibraries(), loadedLibraries)
+		for _, dbName := range extension.DBNames {
+			declared[dbName+"/"+extension.Name] = true
+
+			status := apiv1.ExtensionStatus{
+				Name:    extension.Name,
+				DBName:  dbName,
+				Applied: true,
+			}
+			if len(missingLibraries) > 0 {
+				// The libraries have been added to the configuration,
+				// and will be loaded when the instance is restarted
+				status.Applied = false
+				status.Message = fmt.Sprintf("waiting for the instance to be restarted to load %s",
+					strings.Join(missingLibraries, ", "))
+			} else if version, err := r.applyExtension(ctx, dbName, extension); err != nil {
+				contextLogger.Error(err, "while reconciling extension",
+					"extension", extension.Name, "dbname", dbName)
+				status.Applied = false
+				status.Message = err.Error()
+			} else {
+				status.Version = version
+			}
+			extensions = append(extensions, status)
+		}
+	}
+
+	// The extensions which are reported in the status, but are not
+	// declared anymore, have been removed by the user and are dropped
+	for _, status := range cluster.Status.Extensions {
+		if declared[status.DBName+"/"+status.Name] {
+			continue
+		}
+
+		if err := r.dropExtension(ctx, status.DBName, status.Name); err != nil {
+			contextLogger.Error(err, "while dropping extension",
+				"extension", status.Name, "dbname", status.DBName)
+			extensions = append(extensions, apiv1.ExtensionStatus{
+				Name:    status.Name,
+				DBName:  status.DBName,
+				Version: status.Version,
+				Applied: false,
+				Message: err.Error(),
+			})
+		}
+	}
+
+	if reflect.DeepEqual(cluster.Status.Extensions, extensions) {
+		return nil
+	}
+
+	oldCluster := cluster.DeepCopy()
+	cluster.Status.Extensions = extensions
+	return r.client.Status().Patch(ctx, cluster, client.MergeFrom(oldCluster))
+}
+
+// applyExtension creates the passed extension in a database or, if it
+// already exists, alters it to match its configuration, returning the
+// installed version
+func (r *InstanceReconciler) applyExtension(
+	ctx context.Context,
+	dbName string,
+	extension apiv1.ExtensionConfiguration,
+) (string, error) {
+	db, err := r.instance.ConnectionPool().Connection(dbName)
+	if err != nil {
+		return "", fmt.Errorf("while connecting to database %s: %w", dbName, err)
+	}
+
+	current, err := getExtensionDefinition(ctx, db, extension.Name)
+	if err != nil {
+		return "", fmt.Errorf("while reading the extension: %w", err)
+	}
+
+	if err := applyExtensionDefinition(ctx, db, extension, current); err != nil {
+		return "", err
+	}
+
+	installed, err := getExtensionDefinition(ctx, db, extension.Name)
+	if err != nil {
+		return "", fmt.Errorf("while reading the extension: %w", err)
+	}
+	if installed == nil {
+		return "", fmt.Errorf("extension %s not found after having created it", extension.Name)
+	}
+
+	return installed.version, nil
+}
+
+// dropExtension drops an extension, if it exists
+func (r *InstanceReconciler) dropExtension(ctx context.Context, dbName, name string) error {
+	db, err := r.instance.ConnectionPool().Connection(dbName)
+	if err != nil {
+		return fmt.Errorf("while connecting to database %s: %w", dbName, err)
+	}
+
+	log.FromContext(ctx).Info("Dropping extension", "extension", name, "dbname", dbName)
+	_, err = db.ExecContext(ctx, fmt.Sprintf("DROP EXTENSION IF EXISTS %s", pgx.Identifier{name}.Sanitize()))
+	return err
+}
+
+// getExtensionDefinition reads the definition of an extension from
+// the catalog, returning nil if it isn't installed
+func getExtensionDefinition(ctx context.Context, db *sql.DB, name string) (*extensionDefinition, error) {
+	var definition extensionDefinition
+	row := db.QueryRowContext(ctx,
+		"SELECT e.extversion, n.nspname FROM pg_catalog.pg_extension e "+
+			"JOIN pg_catalog.pg_namespace n ON n.oid = e.extnamespace WHERE e.extname = $1", name)
+	err := row.Scan(&definition.version, &definition.schema)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &definition, nil
+}
+
+// applyExtensionDefinition runs the statements needed to go from the
+// current definition of an extension to the configured one. A nil
+// current definition means that the extension isn't installed
+func applyExtensionDefinition(
+	ctx context.Context,
+	db *sql.DB,
+	extension apiv1.ExtensionConfiguration,
+	current *extensionDefinition,
+) error {
+	contextLogger := log.FromContext(ctx)
+	identifier := pgx.Identifier{extension.Name}.Sanitize()
+
+	var statements []string
+	if current == nil {
+		contextLogger.Info("Creating extension", "extension", extension.Name,
+			"version", extension.Version, "schema", extension.Schema)
+		statement := fmt.Sprintf("CREATE EXTENSION %s", identifier)
+		if extension.Schema != "" {
+			statement += fmt.Sprintf(" SCHEMA %s", pgx.Identifier{extension.Schema}.Sanitize())
+		}
+		if extension.Version != "" {
+			statement += fmt.Sprintf(" VERSION %s", pq.QuoteLiteral(extension.Version))
+		}
+		statements = append(statements, statement)
+	} else {
+		if extension.Version != "" && current.version != extension.Version {
+			contextLogger.Info("Updating extension", "extension", extension.Name,
+				"currentVersion", current.version, "version", extension.Version)
+			statements = append(statements, fmt.Sprintf("ALTER EXTENSION %s UPDATE TO %s",
+				identifier, pq.QuoteLiteral(extension.Version)))
+		}
+		if extension.Schema != "" && current.schema != extension.Schema {
+			contextLogger.Info("Moving extension", "extension", extension.Name,
+				"currentSchema", current.schema, "schema", extension.Schema)
+			statements = append(statements, fmt.Sprintf("ALTER EXTENSION %s SET SCHEMA %s",
+				identifier, pgx.Identifier{extension.Schema}.Sanitize()))
+		}
+	}
+
+	if len(statements) == 0 {
+		return nil
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		// This is a no-op when the transaction is committed
+		_ = tx.Rollback()
+	}()
+
+	for _, statement := range statements {
+		if _, err := tx.ExecContext(ctx, statement); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// getMissingLibraries gets the required libraries which are not in the
+// passed value of shared_preload_libraries, as loaded by PostgreSQL
+func getMissingLibraries(required []string, loadedLibraries string) []string {
+	loaded := make(map[string]bool)
+	for _, library := range strings.Split(loadedLibraries, ",") {
+		loaded[strings.Trim(strings.TrimSpace(library), `"`)] = true
+	}
+
+	var missing []string
+	for _, library := range required {
+		if !loaded[library] {
+			missing = append(missing, library)
+		}
+	}
+
+	return missing
+}
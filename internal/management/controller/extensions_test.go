@@ -0,0 +1,112 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("extension libraries", func() {
+	It("detects the libraries which have not been loaded", func() {
+		Expect(getMissingLibraries([]string{"pg_stat_statements", "pg_cron"},
+			`pg_stat_statements, "pgaudit"`)).To(Equal([]string{"pg_cron"}))
+		Expect(getMissingLibraries([]string{"pgaudit"}, `pg_stat_statements, "pgaudit"`)).To(BeEmpty())
+		Expect(getMissingLibraries(nil, "")).To(BeEmpty())
+	})
+})
+
+var _ = Describe("extension reconciliation", func() {
+	var (
+		db   *sql.DB
+		mock sqlmock.Sqlmock
+		ctx  context.Context
+	)
+
+	BeforeEach(func() {
+		var err error
+		db, mock, err = sqlmock.New()
+		Expect(err).ToNot(HaveOccurred())
+		ctx = context.Background()
+	})
+
+	AfterEach(func() {
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	It("reads an extension which isn't installed", func() {
+		mock.ExpectQuery("FROM pg_catalog.pg_extension e").
+			WithArgs("hstore").
+			WillReturnRows(sqlmock.NewRows([]string{"extversion", "nspname"}))
+
+		definition, err := getExtensionDefinition(ctx, db, "hstore")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(definition).To(BeNil())
+	})
+
+	It("reads an installed extension", func() {
+		mock.ExpectQuery("FROM pg_catalog.pg_extension e").
+			WithArgs("hstore").
+			WillReturnRows(sqlmock.NewRows([]string{"extversion", "nspname"}).AddRow("1.8", "public"))
+
+		definition, err := getExtensionDefinition(ctx, db, "hstore")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(definition).To(Equal(&extensionDefinition{version: "1.8", schema: "public"}))
+	})
+
+	It("creates a missing extension", func() {
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(`CREATE EXTENSION "hstore" SCHEMA "ext" VERSION '1.8'`)).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		Expect(applyExtensionDefinition(ctx, db, apiv1.ExtensionConfiguration{
+			Name:    "hstore",
+			Version: "1.8",
+			Schema:  "ext",
+		}, nil)).To(Succeed())
+	})
+
+	It("updates and moves an existing extension", func() {
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(`ALTER EXTENSION "hstore" UPDATE TO '1.8'`)).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(regexp.QuoteMeta(`ALTER EXTENSION "hstore" SET SCHEMA "ext"`)).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		Expect(applyExtensionDefinition(ctx, db, apiv1.ExtensionConfiguration{
+			Name:    "hstore",
+			Version: "1.8",
+			Schema:  "ext",
+		}, &extensionDefinition{version: "1.7", schema: "public"})).To(Succeed())
+	})
+
+	It("leaves alone an extension without a requested version and schema", func() {
+		Expect(applyExtensionDefinition(ctx, db, apiv1.ExtensionConfiguration{
+			Name: "hstore",
+		}, &extensionDefinition{version: "1.7", schema: "public"})).To(Succeed())
+	})
+})
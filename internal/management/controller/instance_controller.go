@@ -119,6 +119,13 @@ func (r *InstanceReconciler) Reconcile(
 		return reconcile.Result{}, err
 	}
 
+	// Takes care of the `.suspended-wal-archive` file inside the PGDATA,
+	// listing the WAL files skipped while the WAL archiving was suspended,
+	// which are archived again once the WAL archiving is resumed.
+	if err := r.reconcileSuspendedWalArchive(ctx, cluster); err != nil {
+		return reconcile.Result{}, err
+	}
+
 	// Refresh the cache
 	requeue := r.updateCacheFromCluster(ctx, cluster)
 
@@ -158,6 +165,10 @@ func (r *InstanceReconciler) Reconcile(
 		return *result, nil
 	}
 
+	// An instance with corrupted data is removed from the services
+	// until the operator rebuilds it
+	r.reconcileCordoning(ctx, cluster)
+
 	if r.instance.IsFenced() || r.instance.MightBeUnavailable() {
 		contextLogger.Info("Instance could be down, will not proceed with the reconciliation loop")
 		return reconcile.Result{}, nil
@@ -213,14 +224,45 @@ func (r *InstanceReconciler) Reconcile(
 		}
 	}
 
+	// The buffer cache is empty after PostgreSQL has been restarted, and
+	// the relations to be prewarmed need to be loaded again
+	if restarted {
+		r.prewarmDone.Store(false)
+	}
+	r.reconcilePrewarm(ctx, cluster)
+
 	if err = r.refreshCredentialsFromSecret(ctx, cluster); err != nil {
 		return reconcile.Result{}, fmt.Errorf("while updating database owner password: %w", err)
 	}
 
+	if err = r.reconcileMonitoringCredentials(ctx, cluster); err != nil {
+		return reconcile.Result{}, fmt.Errorf("while reading the credentials of the monitoring role: %w", err)
+	}
+
 	if err := r.reconcileDatabases(ctx, cluster); err != nil {
 		return reconcile.Result{}, fmt.Errorf("cannot reconcile database configurations: %w", err)
 	}
 
+	if err := r.reconcileTablespaces(ctx, cluster); err != nil {
+		return reconcile.Result{}, fmt.Errorf("cannot reconcile tablespaces: %w", err)
+	}
+
+	if err := r.reconcilePublications(ctx, cluster); err != nil {
+		return reconcile.Result{}, fmt.Errorf("cannot reconcile publications: %w", err)
+	}
+
+	if err := r.reconcileDeclaredExtensions(ctx, cluster); err != nil {
+		return reconcile.Result{}, fmt.Errorf("cannot reconcile extensions: %w", err)
+	}
+
+	if err := r.reconcileConfigurationDrift(ctx, cluster); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	if err := r.reconcileAutovacuumTables(ctx, cluster); err != nil {
+		return reconcile.Result{}, fmt.Errorf("cannot reconcile autovacuum settings: %w", err)
+	}
+
 	// Extremely important.
 	// It could happen that current primary is reconciled before all the topology is extracted by the operator.
 	// We should detect that and schedule the instance manager for another run otherwise we will end up having
@@ -234,7 +276,9 @@ func (r *InstanceReconciler) Reconcile(
 		return reconcile.Result{RequeueAfter: 30 * time.Second}, nil
 	}
 
-	return reconcile.Result{}, nil
+	// postgresql.auto.conf can be changed at any time via ALTER SYSTEM,
+	// without any event in Kubernetes
+	return reconcile.Result{RequeueAfter: configurationDriftCheckInterval}, nil
 }
 
 func (r *InstanceReconciler) configureSlotReplicator(cluster *apiv1.Cluster) {
@@ -275,6 +319,12 @@ func (r *InstanceReconciler) refreshConfigurationFiles(
 		return false, err
 	}
 
+	reloadIdent, err := r.instance.RefreshPGIdent(cluster)
+	if err != nil {
+		return false, err
+	}
+	reloadNeeded = reloadNeeded || reloadIdent
+
 	// Reconcile PostgreSQL configuration
 	// This doesn't need the PG connection, but it needs to reload it in case of changes
 	reloadConfig, err := r.instance.RefreshConfigurationFilesFromCluster(cluster)
@@ -310,6 +360,23 @@ func (r *InstanceReconciler) reconcileFencing(cluster *apiv1.Cluster) *reconcile
 	return nil
 }
 
+// reconcileCordoning makes the readiness probe fail while the operator
+// reports the data of this instance as corrupted, removing it from the
+// services until it is rebuilt
+func (r *InstanceReconciler) reconcileCordoning(ctx context.Context, cluster *apiv1.Cluster) {
+	cordoningRequired := cluster.IsInstanceCordoned(r.instance.PodName)
+	if cordoningRequired == r.instance.IsCordoned() {
+		return
+	}
+
+	if cordoningRequired {
+		log.FromContext(ctx).Warning("Instance data is corrupted, removing it from the services")
+	} else {
+		log.FromContext(ctx).Info("Instance data is not reported as corrupted anymore, adding it back to the services")
+	}
+	r.instance.SetCordoned(cordoningRequired)
+}
+
 func handleErrNextLoop(err error) (reconcile.Result, error) {
 	if errors.Is(err, controllers.ErrNextLoop) {
 		return reconcile.Result{RequeueAfter: time.Second}, nil
@@ -328,6 +395,10 @@ func (r *InstanceReconciler) initialize(ctx context.Context, cluster *apiv1.Clus
 		return err
 	}
 
+	if err := r.resyncDivergedReplica(ctx, cluster); err != nil {
+		return err
+	}
+
 	r.instance.SetFencing(cluster.IsInstanceFenced(r.instance.PodName))
 
 	return nil
@@ -413,19 +484,15 @@ func (r *InstanceReconciler) reconcileOldPrimary(
 		return false, err
 	}
 
-	contextLogger.Info("This is an old primary node. Requesting a checkpoint before demotion")
-
-	db, err := r.instance.GetSuperUserDB()
-	if err != nil {
-		contextLogger.Error(err, "Cannot connect to primary server")
-	} else {
-		_, err = db.Exec("CHECKPOINT")
-		if err != nil {
-			contextLogger.Error(err, "Error while requesting a checkpoint")
-		}
+	// A failover is in progress when the target primary is the pending
+	// marker: this instance is not healthy, and we don't want to delay
+	// the election of the new primary
+	if cluster.Status.TargetPrimary != apiv1.PendingFailoverMarker && cluster.IsSwitchoverCheckpointEnabled() {
+		r.checkpointBeforeDemotion(ctx, cluster)
 	}
 
 	contextLogger.Info("This is an old primary node. Shutting it down to get it demoted to a replica")
+	r.recordPrimaryDemotedEvent(ctx, cluster)
 
 	// Here we need to invoke a fast shutdown on the instance, and wait the instance
 	// manager to be stopped.
@@ -442,6 +509,31 @@ func (r *InstanceReconciler) reconcileOldPrimary(
 	return true, nil
 }
 
+// checkpointBeforeDemotion requests a checkpoint on the old primary, so that
+// both the new primary and the demoted instance will have less WAL to replay.
+// The checkpoint is bounded by the configured timeout, and any error is only
+// logged as it must not prevent the switchover from happening.
+func (r *InstanceReconciler) checkpointBeforeDemotion(ctx context.Context, cluster *apiv1.Cluster) {
+	contextLogger := log.FromContext(ctx)
+
+	timeout := cluster.GetSwitchoverCheckpointTimeout()
+	contextLogger.Info("This is an old primary node. Requesting a checkpoint before demotion",
+		"timeout", timeout)
+
+	db, err := r.instance.GetSuperUserDB()
+	if err != nil {
+		contextLogger.Error(err, "Cannot connect to primary server")
+		return
+	}
+
+	checkpointCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	if _, err = db.ExecContext(checkpointCtx, "CHECKPOINT"); err != nil {
+		contextLogger.Error(err, "Error while requesting a checkpoint")
+	}
+}
+
 // IsDBUp checks whether the superuserdb is reachable and returns an error if that's not the case
 func (r *InstanceReconciler) IsDBUp(ctx context.Context) error {
 	contextLogger := log.FromContext(ctx)
@@ -490,7 +582,7 @@ func (r *InstanceReconciler) reconcileDatabases(ctx context.Context, cluster *ap
 			continue
 		}
 		if extensionStatusChanged {
-			if err = r.reconcileExtensions(ctx, db, cluster.Spec.PostgresConfiguration.Parameters); err != nil {
+			if err = r.reconcileExtensions(ctx, db, cluster); err != nil {
 				errors = append(errors,
 					fmt.Errorf("could not reconcile extensions for database %s: %w", databaseName, err))
 			}
@@ -536,10 +628,17 @@ func (r *InstanceReconciler) getAllAccessibleDatabases(
 }
 
 // ReconcileExtensions reconciles the expected extensions for this
-// PostgreSQL instance
+// PostgreSQL instance. The extensions declared in the cluster
+// specification are managed by reconcileDeclaredExtensions instead
 func (r *InstanceReconciler) reconcileExtensions(
-	ctx context.Context, db *sql.DB, userSettings map[string]string,
+	ctx context.Context, db *sql.DB, cluster *apiv1.Cluster,
 ) (err error) {
+	userSettings := cluster.Spec.PostgresConfiguration.Parameters
+	declaredExtensions := make(map[string]bool, len(cluster.Spec.Extensions))
+	for _, extension := range cluster.Spec.Extensions {
+		declaredExtensions[extension.Name] = true
+	}
+
 	tx, err := db.BeginTx(ctx, nil)
 	if err != nil {
 		return err
@@ -555,6 +654,10 @@ func (r *InstanceReconciler) reconcileExtensions(
 	}
 
 	for _, extension := range postgres.ManagedExtensions {
+		if declaredExtensions[extension.Name] {
+			continue
+		}
+
 		extensionIsUsed := extension.IsUsed(userSettings)
 
 		row := tx.QueryRow("SELECT COUNT(*) > 0 FROM pg_extension WHERE extname = $1", extension.Name)
@@ -870,6 +973,7 @@ func (r *InstanceReconciler) reconcileInstance(cluster *apiv1.Cluster) {
 	r.instance.PgCtlTimeoutForPromotion = cluster.GetPgCtlTimeoutForPromotion()
 	r.instance.MaxSwitchoverDelay = cluster.GetMaxSwitchoverDelay()
 	r.instance.MaxStopDelay = cluster.GetMaxStopDelay()
+	r.instance.SetPrimaryServiceName(cluster.GetServiceReadWriteName())
 }
 
 func (r *InstanceReconciler) reconcileCheckWalArchiveFile(cluster *apiv1.Cluster) error {
@@ -884,6 +988,34 @@ func (r *InstanceReconciler) reconcileCheckWalArchiveFile(cluster *apiv1.Cluster
 	return nil
 }
 
+// reconcileSuspendedWalArchive marks the WAL files which have been skipped
+// while the WAL archiving was suspended as ready to be archived, once the
+// WAL archiving is resumed
+func (r *InstanceReconciler) reconcileSuspendedWalArchive(ctx context.Context, cluster *apiv1.Cluster) error {
+	contextLogger := log.FromContext(ctx)
+
+	if pkgUtils.IsWalArchivingSuspended(&cluster.ObjectMeta) {
+		return nil
+	}
+
+	requeued, lost, err := archiver.RequeueSuspendedWALs(r.instance.PgData)
+	if err != nil {
+		return fmt.Errorf("while archiving the WAL files skipped by the suspended archiving: %w", err)
+	}
+
+	if len(requeued) > 0 {
+		contextLogger.Info("WAL archiving resumed, archiving the skipped WAL files",
+			"walFiles", requeued)
+	}
+	if len(lost) > 0 {
+		contextLogger.Warning("WAL archiving resumed, but some skipped WAL files have been "+
+			"already recycled and can't be archived anymore",
+			"walFiles", lost)
+	}
+
+	return nil
+}
+
 // waitForConfigurationReload waits for the db to be up and
 // the new configuration to be reloaded
 func (r *InstanceReconciler) waitForConfigurationReload(ctx context.Context, cluster *apiv1.Cluster) error {
@@ -906,7 +1038,7 @@ func (r *InstanceReconciler) waitForConfigurationReload(ctx context.Context, clu
 		return fmt.Errorf("while waiting for new configuration to be reloaded: %w", err)
 	}
 
-	status, err := r.instance.GetStatus()
+	status, err := r.instance.GetStatus(ctx)
 	if err != nil {
 		return fmt.Errorf("while applying new configuration: %w", err)
 	}
@@ -941,7 +1073,7 @@ func (r *InstanceReconciler) waitForConfigurationReload(ctx context.Context, clu
 	}
 	if phase == apiv1.PhaseApplyingConfiguration &&
 		(cluster.Status.Phase == apiv1.PhaseApplyingConfiguration ||
-			(status.IsPrimary && cluster.Spec.Instances > 1)) {
+			(status.IsPrimary && cluster.GetDesiredInstances() > 1)) {
 		// I'm not the first instance spotting the configuration
 		// change, everything is fine and there is no need to signal
 		// the operator again.
@@ -1077,12 +1209,19 @@ func (r *InstanceReconciler) reconcilePrimary(ctx context.Context, cluster *apiv
 
 	// if the currentPrimary doesn't match the PodName we set the correct value.
 	if cluster.Status.CurrentPrimary != r.instance.PodName {
+		primaryChange := r.newPrimaryChange(ctx, cluster)
+		if primaryChange != nil {
+			cluster.AddPrimaryChange(*primaryChange)
+		}
 		cluster.Status.CurrentPrimary = r.instance.PodName
 		cluster.Status.CurrentPrimaryTimestamp = pkgUtils.GetCurrentTimestamp()
 
 		if err := r.client.Status().Patch(ctx, cluster, client.MergeFrom(oldCluster)); err != nil {
 			return restarted, err
 		}
+		if primaryChange != nil {
+			recordPrimaryPromotedEvent(ctx, cluster, primaryChange)
+		}
 
 		if err := r.instance.DropConnections(); err != nil {
 			return restarted, err
@@ -1137,9 +1276,19 @@ func (r *InstanceReconciler) reconcileDesignatedPrimary(
 	log.FromContext(ctx).Info("Setting myself as the current designated primary")
 
 	oldCluster := cluster.DeepCopy()
+	primaryChange := r.newPrimaryChange(ctx, cluster)
+	if primaryChange != nil {
+		cluster.AddPrimaryChange(*primaryChange)
+	}
 	cluster.Status.CurrentPrimary = r.instance.PodName
 	cluster.Status.CurrentPrimaryTimestamp = pkgUtils.GetCurrentTimestamp()
-	return changed, r.client.Status().Patch(ctx, cluster, client.MergeFrom(oldCluster))
+	if err := r.client.Status().Patch(ctx, cluster, client.MergeFrom(oldCluster)); err != nil {
+		return changed, err
+	}
+	if primaryChange != nil {
+		recordPrimaryPromotedEvent(ctx, cluster, primaryChange)
+	}
+	return changed, nil
 }
 
 // waitForWalReceiverDown wait until the wal receiver is down, and it's used
@@ -1215,9 +1364,109 @@ func (r *InstanceReconciler) refreshCredentialsFromSecret(
 			return err
 		}
 	}
+
+	if cluster.IsMonitoringRoleEnabled() {
+		if err = r.reconcileMonitoringRole(tx); err != nil {
+			return err
+		}
+		err = r.reconcileUser(ctx, apiv1.MonitoringUserName, cluster.GetMonitoringSecretName(), tx)
+		if err != nil {
+			return err
+		}
+	} else {
+		if err = r.disableMonitoringRolePassword(tx); err != nil {
+			return err
+		}
+	}
 	return tx.Commit()
 }
 
+// reconcileMonitoringRole creates the monitoring role when it is
+// missing, and makes it a member of pg_monitor
+func (r *InstanceReconciler) reconcileMonitoringRole(tx *sql.Tx) error {
+	var existsRole bool
+	row := tx.QueryRow("SELECT COUNT(*) > 0 FROM pg_catalog.pg_roles WHERE rolname = $1",
+		apiv1.MonitoringUserName)
+	if err := row.Scan(&existsRole); err != nil {
+		return err
+	}
+	if !existsRole {
+		_, err := tx.Exec(fmt.Sprintf("CREATE ROLE %s WITH LOGIN",
+			pgx.Identifier{apiv1.MonitoringUserName}.Sanitize()))
+		if err != nil {
+			return fmt.Errorf("while creating the monitoring role: %w", err)
+		}
+	}
+
+	var isMember bool
+	row = tx.QueryRow("SELECT pg_catalog.pg_has_role($1, 'pg_monitor', 'MEMBER')",
+		apiv1.MonitoringUserName)
+	if err := row.Scan(&isMember); err != nil {
+		return err
+	}
+	if !isMember {
+		_, err := tx.Exec(fmt.Sprintf("GRANT pg_monitor TO %s",
+			pgx.Identifier{apiv1.MonitoringUserName}.Sanitize()))
+		if err != nil {
+			return fmt.Errorf("while granting pg_monitor to the monitoring role: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// disableMonitoringRolePassword removes the password of the monitoring
+// role, if it exists, so that it cannot log in once the role has been
+// disabled. The role itself is kept, as other objects may depend on it
+func (r *InstanceReconciler) disableMonitoringRolePassword(tx *sql.Tx) error {
+	var hasPassword bool
+	row := tx.QueryRow(
+		"SELECT COUNT(*) > 0 FROM pg_catalog.pg_authid WHERE rolname = $1 AND rolpassword IS NOT NULL",
+		apiv1.MonitoringUserName)
+	if err := row.Scan(&hasPassword); err != nil {
+		return err
+	}
+	if !hasPassword {
+		return nil
+	}
+
+	_, err := tx.Exec(fmt.Sprintf("ALTER ROLE %s WITH PASSWORD NULL",
+		pgx.Identifier{apiv1.MonitoringUserName}.Sanitize()))
+	return err
+}
+
+// reconcileMonitoringCredentials passes the password of the monitoring
+// role to the metrics exporter, which uses it to connect to PostgreSQL.
+// This happens on every instance, as the role is replicated from the
+// primary
+func (r *InstanceReconciler) reconcileMonitoringCredentials(ctx context.Context, cluster *apiv1.Cluster) error {
+	if !cluster.IsMonitoringRoleEnabled() {
+		r.instance.SetMonitoringRolePassword("")
+		return nil
+	}
+
+	var secret corev1.Secret
+	err := r.GetClient().Get(
+		ctx,
+		client.ObjectKey{Namespace: r.instance.Namespace, Name: cluster.GetMonitoringSecretName()},
+		&secret)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			// The operator has not created the secret yet
+			return nil
+		}
+		return err
+	}
+
+	_, password, err := utils.GetUserPasswordFromSecret(&secret)
+	if err != nil {
+		return err
+	}
+
+	r.instance.SetMonitoringRolePassword(password)
+	return nil
+}
+
 func (r *InstanceReconciler) reconcileUser(ctx context.Context, username string, secretName string, tx *sql.Tx) error {
 	var secret corev1.Secret
 	err := r.GetClient().Get(
@@ -1298,7 +1547,7 @@ func (r *InstanceReconciler) shouldRequeueForMissingTopology(cluster *apiv1.Clus
 	}
 
 	topologyStatus := cluster.Status.Topology
-	if !topologyStatus.SuccessfullyExtracted || len(topologyStatus.Instances) != cluster.Spec.Instances {
+	if !topologyStatus.SuccessfullyExtracted || len(topologyStatus.Instances) != cluster.GetDesiredInstances() {
 		log.Info("missing topology information while syncReplicaElectionConstraint are enabled, " +
 			"will requeue to calculate correctly the synchronous names")
 		return true
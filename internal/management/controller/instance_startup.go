@@ -23,17 +23,31 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/util/retry"
+	"k8s.io/utils/strings/slices"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/controllers"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	postgresSpec "github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
 	pkgUtils "github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 )
 
+// resyncMethod is the method used to realign a former primary with the
+// current one
+type resyncMethod string
+
+const (
+	// resyncMethodRewind means the former primary was realigned via pg_rewind
+	resyncMethodRewind resyncMethod = "pg_rewind"
+
+	// resyncMethodBaseBackup means the former primary was recreated via pg_basebackup
+	resyncMethodBaseBackup resyncMethod = "pg_basebackup"
+)
+
 // refreshServerCertificateFiles gets the latest server certificates files from the
 // secrets. Returns true if configuration has been changed
 func (r *InstanceReconciler) refreshServerCertificateFiles(ctx context.Context, cluster *apiv1.Cluster) (bool, error) {
@@ -226,38 +240,138 @@ func (r *InstanceReconciler) verifyPgDataCoherenceForPrimary(
 			return err
 		}
 
-		// pg_rewind could require a clean shutdown of the old primary to
-		// work. Unfortunately, if the old primary is already clean starting
-		// it up may make it advance in respect to the new one.
-		// The only way to check if we really need to start it up before
-		// invoking pg_rewind is to try using pg_rewind and, on failures,
-		// retrying after having started up the instance.
-		err = r.instance.Rewind(pgMajorVersion)
+		method, err := r.resyncFormerPrimary(ctx, cluster, pgMajorVersion)
 		if err != nil {
-			contextLogger.Info(
-				"pg_rewind failed, starting the server to complete the crash recovery",
-				"err", err)
-
-			// pg_rewind requires a clean shutdown of the old primary to work.
-			// The only way to do that is to start the server again
-			// and wait for it to be available again.
-			err = r.instance.CompleteCrashRecovery()
-			if err != nil {
-				return err
-			}
-
-			// Then let's go back to the point of the new primary
-			err = r.instance.Rewind(pgMajorVersion)
-			if err != nil {
-				return err
-			}
+			return err
 		}
+		r.recordResyncEvent(ctx, cluster, method)
 
 		// Now I can demote myself
 		return r.instance.Demote(cluster)
 	}
 }
 
+// resyncFormerPrimary aligns the PGDATA of a former primary with the current
+// one. pg_rewind is tried first, and a full pg_basebackup is used only when
+// the data directory cannot be rewound. The method used is returned.
+func (r *InstanceReconciler) resyncFormerPrimary(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	pgMajorVersion int,
+) (resyncMethod, error) {
+	contextLogger := log.FromContext(ctx)
+
+	// pg_rewind could require a clean shutdown of the old primary to
+	// work. Unfortunately, if the old primary is already clean starting
+	// it up may make it advance in respect to the new one.
+	// The only way to check if we really need to start it up before
+	// invoking pg_rewind is to try using pg_rewind and, on failures,
+	// retrying after having started up the instance.
+	err := r.instance.Rewind(pgMajorVersion)
+	if err == nil {
+		return resyncMethodRewind, nil
+	}
+
+	contextLogger.Info(
+		"pg_rewind failed, starting the server to complete the crash recovery",
+		"err", err)
+
+	// pg_rewind requires a clean shutdown of the old primary to work.
+	// The only way to do that is to start the server again
+	// and wait for it to be available again.
+	err = r.instance.CompleteCrashRecovery()
+	if err != nil {
+		return "", err
+	}
+
+	// Then let's go back to the point of the new primary
+	err = r.instance.Rewind(pgMajorVersion)
+	if err == nil {
+		return resyncMethodRewind, nil
+	}
+
+	// The timelines cannot be reconciled by pg_rewind, the only
+	// way forward is to take a full copy of the new primary
+	contextLogger.Info(
+		"pg_rewind failed after crash recovery, cloning the data directory from the primary",
+		"err", err)
+	if err := r.instance.CloneFromPrimary(cluster); err != nil {
+		return "", err
+	}
+
+	return resyncMethodBaseBackup, nil
+}
+
+// recordResyncEvent reports on the cluster which method was used to
+// resynchronize this former primary, both with an event and in the
+// state reported by the instance
+func (r *InstanceReconciler) recordResyncEvent(ctx context.Context, cluster *apiv1.Cluster, method resyncMethod) {
+	contextLogger := log.FromContext(ctx)
+	contextLogger.Info("Former primary resynchronized", "method", method)
+	r.instance.SetLastResyncMethod(string(method))
+
+	recorder, err := management.NewEventRecorder()
+	if err != nil {
+		contextLogger.Error(err, "Error while creating the event recorder")
+		return
+	}
+
+	recorder.Eventf(cluster, "Normal", "FormerPrimaryResynchronized",
+		"Former primary %v resynchronized with %v", r.instance.PodName, method)
+}
+
+// resyncDivergedReplica realigns the PGDATA of this replica with the
+// current primary when the operator found it stuck on an old timeline,
+// and then removes it from the instances to be resynchronized.
+// Important: this function must be called only when the instance isn't started
+func (r *InstanceReconciler) resyncDivergedReplica(ctx context.Context, cluster *apiv1.Cluster) error {
+	if !slices.Contains(cluster.Status.ResynchronizingInstances, r.instance.PodName) {
+		return nil
+	}
+
+	contextLogger := log.FromContext(ctx)
+	contextLogger.Info("This replica is stuck on a diverged timeline, resynchronizing it",
+		"timeline", cluster.Status.InstancesReportedState[apiv1.PodName(r.instance.PodName)].TimeLineID,
+		"primaryTimeline", cluster.Status.TimelineID)
+
+	if err := r.instance.WaitForPrimaryAvailable(); err != nil {
+		return err
+	}
+
+	tag := pkgUtils.GetImageTag(cluster.GetImageName())
+	pgMajorVersion, err := postgresSpec.GetPostgresMajorVersionFromTag(tag)
+	if err != nil {
+		return err
+	}
+
+	if err := r.instance.CleanUpStalePid(); err != nil {
+		return err
+	}
+
+	method, err := r.resyncFormerPrimary(ctx, cluster, pgMajorVersion)
+	if err != nil {
+		return err
+	}
+	if err := r.instance.Demote(cluster); err != nil {
+		return err
+	}
+
+	contextLogger.Info("Diverged replica resynchronized", "method", method)
+	if recorder, err := management.NewEventRecorder(); err != nil {
+		contextLogger.Error(err, "Error while creating the event recorder")
+	} else {
+		recorder.Eventf(cluster, "Normal", "ReplicaResynchronized",
+			"Replica %v resynchronized with %v", r.instance.PodName, method)
+	}
+
+	oldCluster := cluster.DeepCopy()
+	cluster.Status.ResynchronizingInstances = slices.Filter(nil, cluster.Status.ResynchronizingInstances,
+		func(instanceName string) bool {
+			return instanceName != r.instance.PodName
+		})
+	return r.client.Status().Patch(ctx, cluster, client.MergeFrom(oldCluster))
+}
+
 // ReconcileWalStorage moves the files from PGDATA/pg_wal to the volume attached, if exists, and
 // creates a symlink for it
 func (r *InstanceReconciler) ReconcileWalStorage(ctx context.Context) error {
@@ -302,3 +416,32 @@ func (r *InstanceReconciler) ReconcileWalStorage(ctx context.Context) error {
 
 	return nil
 }
+
+// ReconcileTablespaceStorage creates, in every mounted tablespace volume,
+// the directory containing the tablespace. Replicas need it to exist
+// before replaying the creation of a tablespace
+func (r *InstanceReconciler) ReconcileTablespaceStorage(ctx context.Context) error {
+	contextLogger := log.FromContext(ctx)
+
+	entries, err := os.ReadDir(specs.PgTablespaceVolumePath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		location := specs.GetTablespaceLocation(entry.Name())
+		contextLogger.Debug("Ensuring tablespace location exists", "location", location)
+		if err := ensureTablespaceLocation(location); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
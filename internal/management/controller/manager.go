@@ -45,6 +45,8 @@ type InstanceReconciler struct {
 
 	systemInitialization  *concurrency.Executed
 	firstReconcileDone    atomic.Bool
+	prewarmDone           atomic.Bool
+	prewarmRunning        atomic.Bool
 	metricsServerExporter *metricserver.Exporter
 }
 
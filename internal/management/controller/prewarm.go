@@ -0,0 +1,135 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// errPrewarmNotInstalled is raised when the pg_prewarm extension is not
+// installed in a database of a replica, where it can't be created
+var errPrewarmNotInstalled = errors.New("the pg_prewarm extension is not installed")
+
+// reconcilePrewarm loads the configured relations in the buffer cache
+// once PostgreSQL has been started or promoted. The relations are loaded
+// in the background, not to delay the reconciliation loop
+func (r *InstanceReconciler) reconcilePrewarm(ctx context.Context, cluster *apiv1.Cluster) {
+	prewarm := cluster.Spec.PostgresConfiguration.Prewarm
+	if prewarm == nil || r.prewarmDone.Load() {
+		return
+	}
+
+	// A restart happening while the relations are being loaded will
+	// be handled once the current execution has been completed
+	if !r.prewarmRunning.CompareAndSwap(false, true) {
+		return
+	}
+	r.prewarmDone.Store(true)
+
+	go func() {
+		defer r.prewarmRunning.Store(false)
+		r.prewarmRelations(ctx, prewarm.DeepCopy())
+	}()
+}
+
+// prewarmRelations loads the relations in the buffer cache one at a time,
+// logging the ones which couldn't be loaded
+func (r *InstanceReconciler) prewarmRelations(ctx context.Context, prewarm *apiv1.PrewarmConfiguration) {
+	contextLogger := log.FromContext(ctx)
+
+	isPrimary, err := r.instance.IsPrimary()
+	if err != nil {
+		contextLogger.Error(err, "unable to check if instance is primary, skipping the prewarm")
+		return
+	}
+
+	contextLogger.Info("Loading the relations in the buffer cache", "relations", len(prewarm.Relations))
+	for _, relation := range prewarm.Relations {
+		if ctx.Err() != nil {
+			return
+		}
+
+		db, err := r.instance.ConnectionPool().Connection(relation.DBName)
+		if err != nil {
+			contextLogger.Error(err, "could not connect to database", "dbname", relation.DBName)
+			continue
+		}
+
+		blocks, err := prewarmRelation(ctx, db, relation.Name, prewarm.GetMaxRelationSize(), isPrimary)
+		if err != nil {
+			contextLogger.Error(err, "while loading a relation in the buffer cache",
+				"dbname", relation.DBName, "relation", relation.Name)
+			continue
+		}
+
+		contextLogger.Info("Relation loaded in the buffer cache",
+			"dbname", relation.DBName, "relation", relation.Name, "blocks", blocks)
+	}
+}
+
+// prewarmRelation loads the first blocks of a relation, up to the given
+// size, in the buffer cache, returning the number of loaded blocks. The
+// pg_prewarm extension is created when missing, if the instance is the primary
+func prewarmRelation(
+	ctx context.Context,
+	db *sql.DB,
+	relationName string,
+	maxSize int64,
+	isPrimary bool,
+) (int64, error) {
+	var installed bool
+	row := db.QueryRowContext(ctx, "SELECT COUNT(*) > 0 FROM pg_catalog.pg_extension WHERE extname = 'pg_prewarm'")
+	if err := row.Scan(&installed); err != nil {
+		return 0, err
+	}
+	if !installed {
+		if !isPrimary {
+			return 0, errPrewarmNotInstalled
+		}
+		if _, err := db.ExecContext(ctx, "CREATE EXTENSION IF NOT EXISTS pg_prewarm"); err != nil {
+			return 0, fmt.Errorf("while creating the pg_prewarm extension: %w", err)
+		}
+	}
+
+	var blocks int64
+	row = db.QueryRowContext(ctx,
+		"SELECT LEAST(pg_catalog.pg_relation_size($1::regclass), $2) / "+
+			"pg_catalog.current_setting('block_size')::bigint",
+		relationName, maxSize)
+	if err := row.Scan(&blocks); err != nil {
+		return 0, err
+	}
+	if blocks == 0 {
+		return 0, nil
+	}
+
+	var loaded int64
+	row = db.QueryRowContext(ctx,
+		"SELECT pg_prewarm($1::regclass, 'buffer', 'main', 0, $2)",
+		relationName, blocks-1)
+	if err := row.Scan(&loaded); err != nil {
+		return 0, err
+	}
+
+	return loaded, nil
+}
@@ -0,0 +1,85 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("prewarm of the buffer cache", func() {
+	const (
+		checkExtensionQuery = "SELECT COUNT\\(\\*\\) > 0 FROM pg_catalog.pg_extension"
+		countBlocksQuery    = "SELECT LEAST\\(pg_catalog.pg_relation_size"
+		prewarmQuery        = "SELECT pg_prewarm"
+	)
+
+	var (
+		db   *sql.DB
+		mock sqlmock.Sqlmock
+		ctx  context.Context
+	)
+
+	BeforeEach(func() {
+		var err error
+		db, mock, err = sqlmock.New()
+		Expect(err).ToNot(HaveOccurred())
+		ctx = context.Background()
+	})
+
+	AfterEach(func() {
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	It("loads the relation up to the maximum size", func() {
+		mock.ExpectQuery(checkExtensionQuery).
+			WillReturnRows(sqlmock.NewRows([]string{""}).AddRow(true))
+		mock.ExpectQuery(countBlocksQuery).
+			WithArgs("sales.orders", int64(1<<20)).
+			WillReturnRows(sqlmock.NewRows([]string{""}).AddRow(128))
+		mock.ExpectQuery(prewarmQuery).
+			WithArgs("sales.orders", int64(127)).
+			WillReturnRows(sqlmock.NewRows([]string{""}).AddRow(128))
+
+		Expect(prewarmRelation(ctx, db, "sales.orders", 1<<20, false)).To(BeEquivalentTo(128))
+	})
+
+	It("creates the extension on the primary", func() {
+		mock.ExpectQuery(checkExtensionQuery).
+			WillReturnRows(sqlmock.NewRows([]string{""}).AddRow(false))
+		mock.ExpectExec("CREATE EXTENSION IF NOT EXISTS pg_prewarm").
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectQuery(countBlocksQuery).
+			WithArgs("orders", int64(1<<20)).
+			WillReturnRows(sqlmock.NewRows([]string{""}).AddRow(0))
+
+		Expect(prewarmRelation(ctx, db, "orders", 1<<20, true)).To(BeZero())
+	})
+
+	It("doesn't create the extension on a replica", func() {
+		mock.ExpectQuery(checkExtensionQuery).
+			WillReturnRows(sqlmock.NewRows([]string{""}).AddRow(false))
+
+		_, err := prewarmRelation(ctx, db, "orders", 1<<20, false)
+		Expect(err).To(Equal(errPrewarmNotInstalled))
+	})
+})
@@ -0,0 +1,99 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	pkgUtils "github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// newPrimaryChange describes the promotion of this instance replacing the
+// current primary, or returns nil when there is no former primary, like
+// during the bootstrap of the cluster. The WAL position is reported on a
+// best-effort basis, as it must not prevent the promotion from completing
+func (r *InstanceReconciler) newPrimaryChange(ctx context.Context, cluster *apiv1.Cluster) *apiv1.PrimaryChange {
+	if cluster.Status.CurrentPrimary == "" {
+		return nil
+	}
+
+	reason := apiv1.PrimaryChangeSwitchover
+	if cluster.Status.Phase == apiv1.PhaseFailOver {
+		reason = apiv1.PrimaryChangeFailover
+	}
+
+	change := &apiv1.PrimaryChange{
+		Timestamp:  pkgUtils.GetCurrentTimestamp(),
+		OldPrimary: cluster.Status.CurrentPrimary,
+		NewPrimary: r.instance.PodName,
+		Reason:     reason,
+	}
+
+	lsn, timelineID, err := r.instance.GetWALPosition(ctx)
+	if err != nil {
+		log.FromContext(ctx).Error(err, "while getting the WAL position of the promoted instance")
+		return change
+	}
+	change.LSN = string(lsn)
+	change.TimelineID = timelineID
+
+	return change
+}
+
+// recordPrimaryPromotedEvent reports on the cluster the promotion
+// of this instance, once it has been recorded in the status
+func recordPrimaryPromotedEvent(ctx context.Context, cluster *apiv1.Cluster, change *apiv1.PrimaryChange) {
+	recorder, err := management.NewEventRecorder()
+	if err != nil {
+		log.FromContext(ctx).Error(err, "Error while creating the event recorder")
+		return
+	}
+
+	recorder.Eventf(cluster, "Normal", "PrimaryPromoted",
+		"%v: %v promoted replacing %v at %v (timeline %v, LSN %v)",
+		change.Reason, change.NewPrimary, change.OldPrimary, change.Timestamp, change.TimelineID, change.LSN)
+}
+
+// recordPrimaryDemotedEvent reports on the cluster that this former primary
+// is being shut down to be demoted. The WAL position is reported on a
+// best-effort basis, as the instance may not be healthy during a failover
+func (r *InstanceReconciler) recordPrimaryDemotedEvent(ctx context.Context, cluster *apiv1.Cluster) {
+	contextLogger := log.FromContext(ctx)
+
+	reason := apiv1.PrimaryChangeSwitchover
+	if cluster.Status.TargetPrimary == apiv1.PendingFailoverMarker {
+		reason = apiv1.PrimaryChangeFailover
+	}
+
+	lsn, timelineID, err := r.instance.GetWALPosition(ctx)
+	if err != nil {
+		contextLogger.Error(err, "while getting the WAL position of the former primary")
+	}
+
+	recorder, err := management.NewEventRecorder()
+	if err != nil {
+		contextLogger.Error(err, "Error while creating the event recorder")
+		return
+	}
+
+	recorder.Eventf(cluster, "Normal", "PrimaryDemoted",
+		"%v: %v demoted at %v (timeline %v, LSN %v)",
+		reason, r.instance.PodName, pkgUtils.GetCurrentTimestamp(), timelineID, lsn)
+}
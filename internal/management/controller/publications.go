@@ -0,0 +1,325 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// publicationDefinition is the definition of a publication, either
+// read from PostgreSQL or derived from the cluster specification
+type publicationDefinition struct {
+	allTables bool
+
+	// The published tables, as sorted `schema.table` names
+	tables []string
+
+	// The published kinds of changes, in the order used by PostgreSQL
+	publish []string
+}
+
+// reconcilePublications creates, updates and drops the publications so
+// that they match the cluster specification, and reports their state
+// in the cluster status. Publications are only managed on the primary
+func (r *InstanceReconciler) reconcilePublications(ctx context.Context, cluster *apiv1.Cluster) error {
+	if len(cluster.Spec.Publications) == 0 && len(cluster.Status.Publications) == 0 {
+		return nil
+	}
+
+	ok, err := r.instance.IsPrimary()
+	if err != nil {
+		return fmt.Errorf("unable to check if instance is primary: %w", err)
+	}
+	if !ok {
+		return nil
+	}
+
+	contextLogger := log.FromContext(ctx)
+
+	declared := make(map[string]bool, len(cluster.Spec.Publications))
+	publications := make([]apiv1.PublicationStatus, 0, len(cluster.Spec.Publications))
+	for _, publication := range cluster.Spec.Publications {
+		declared[publication.DBName+"/"+publication.Name] = true
+
+		status := apiv1.PublicationStatus{
+			Name:    publication.Name,
+			DBName:  publication.DBName,
+			Applied: true,
+		}
+		if err := r.applyPublication(ctx, publication); err != nil {
+			contextLogger.Error(err, "while reconciling publication",
+				"publication", publication.Name, "dbname", publication.DBName)
+			status.Applied = false
+			status.Message = err.Error()
+		}
+		publications = append(publications, status)
+	}
+
+	// The publications which are reported in the status, but are not
+	// declared anymore, have been removed by the user and are dropped
+	for _, status := range cluster.Status.Publications {
+		if declared[status.DBName+"/"+status.Name] {
+			continue
+		}
+
+		if err := r.dropPublication(ctx, status.DBName, status.Name); err != nil {
+			contextLogger.Error(err, "while dropping publication",
+				"publication", status.Name, "dbname", status.DBName)
+			publications = append(publications, apiv1.PublicationStatus{
+				Name:    status.Name,
+				DBName:  status.DBName,
+				Applied: false,
+				Message: err.Error(),
+			})
+		}
+	}
+
+	if len(publications) == 0 {
+		publications = nil
+	}
+	if reflect.DeepEqual(cluster.Status.Publications, publications) {
+		return nil
+	}
+
+	oldCluster := cluster.DeepCopy()
+	cluster.Status.Publications = publications
+	return r.client.Status().Patch(ctx, cluster, client.MergeFrom(oldCluster))
+}
+
+// applyPublication creates the passed publication or, if it already
+// exists, alters it to match its configuration
+func (r *InstanceReconciler) applyPublication(
+	ctx context.Context,
+	publication apiv1.PublicationConfiguration,
+) error {
+	db, err := r.instance.ConnectionPool().Connection(publication.DBName)
+	if err != nil {
+		return fmt.Errorf("while connecting to database %s: %w", publication.DBName, err)
+	}
+
+	current, err := getPublicationDefinition(ctx, db, publication.Name)
+	if err != nil {
+		return fmt.Errorf("while reading the publication: %w", err)
+	}
+
+	return applyPublicationDefinition(ctx, db, publication.Name, current, newPublicationDefinition(publication))
+}
+
+// dropPublication drops a publication, if it exists
+func (r *InstanceReconciler) dropPublication(ctx context.Context, dbName, name string) error {
+	db, err := r.instance.ConnectionPool().Connection(dbName)
+	if err != nil {
+		return fmt.Errorf("while connecting to database %s: %w", dbName, err)
+	}
+
+	log.FromContext(ctx).Info("Dropping publication", "publication", name, "dbname", dbName)
+	_, err = db.ExecContext(ctx, fmt.Sprintf("DROP PUBLICATION IF EXISTS %s", pgx.Identifier{name}.Sanitize()))
+	return err
+}
+
+// newPublicationDefinition gets the definition of a publication
+// from its configuration
+func newPublicationDefinition(publication apiv1.PublicationConfiguration) *publicationDefinition {
+	definition := &publicationDefinition{
+		allTables: publication.AllTables,
+	}
+
+	for _, table := range publication.Tables {
+		if !strings.Contains(table, ".") {
+			table = "public." + table
+		}
+		definition.tables = append(definition.tables, table)
+	}
+	sort.Strings(definition.tables)
+
+	published := make(map[apiv1.PublicationOperation]bool)
+	for _, operation := range publication.GetPublish() {
+		published[operation] = true
+	}
+	for _, operation := range []apiv1.PublicationOperation{
+		apiv1.PublicationOperationInsert,
+		apiv1.PublicationOperationUpdate,
+		apiv1.PublicationOperationDelete,
+		apiv1.PublicationOperationTruncate,
+	} {
+		if published[operation] {
+			definition.publish = append(definition.publish, string(operation))
+		}
+	}
+
+	return definition
+}
+
+// getPublicationDefinition reads the definition of a publication from
+// the catalog, returning nil if it doesn't exist
+func getPublicationDefinition(ctx context.Context, db *sql.DB, name string) (*publicationDefinition, error) {
+	var allTables, insert, update, del, truncate bool
+	row := db.QueryRowContext(ctx,
+		"SELECT puballtables, pubinsert, pubupdate, pubdelete, pubtruncate "+
+			"FROM pg_catalog.pg_publication WHERE pubname = $1", name)
+	err := row.Scan(&allTables, &insert, &update, &del, &truncate)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	definition := &publicationDefinition{allTables: allTables}
+	for operation, published := range map[string]bool{
+		"insert":   insert,
+		"update":   update,
+		"delete":   del,
+		"truncate": truncate,
+	} {
+		if published {
+			definition.publish = append(definition.publish, operation)
+		}
+	}
+	sortPublish(definition.publish)
+
+	// The tables of a FOR ALL TABLES publication are every table
+	// in the database, and don't need to be compared
+	if allTables {
+		return definition, nil
+	}
+
+	rows, err := db.QueryContext(ctx,
+		"SELECT schemaname || '.' || tablename FROM pg_catalog.pg_publication_tables "+
+			"WHERE pubname = $1 ORDER BY 1", name)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	for rows.Next() {
+		var table string
+		if err := rows.Scan(&table); err != nil {
+			return nil, err
+		}
+		definition.tables = append(definition.tables, table)
+	}
+
+	return definition, rows.Err()
+}
+
+// sortPublish sorts the kinds of changes in the order used by PostgreSQL
+func sortPublish(publish []string) {
+	order := map[string]int{"insert": 0, "update": 1, "delete": 2, "truncate": 3}
+	sort.Slice(publish, func(i, j int) bool {
+		return order[publish[i]] < order[publish[j]]
+	})
+}
+
+// applyPublicationDefinition runs the statements needed to go from the
+// current definition of a publication to the desired one. A nil current
+// definition means that the publication doesn't exist
+func applyPublicationDefinition(
+	ctx context.Context,
+	db *sql.DB,
+	name string,
+	current, desired *publicationDefinition,
+) error {
+	contextLogger := log.FromContext(ctx)
+	identifier := pgx.Identifier{name}.Sanitize()
+
+	var statements []string
+	switch {
+	case current == nil:
+		contextLogger.Info("Creating publication", "publication", name)
+		statements = append(statements, createPublicationStatement(identifier, desired))
+
+	case current.allTables != desired.allTables:
+		// A publication can't be switched from or to FOR ALL TABLES,
+		// and needs to be created again
+		contextLogger.Info("Recreating publication", "publication", name)
+		statements = append(statements,
+			fmt.Sprintf("DROP PUBLICATION %s", identifier),
+			createPublicationStatement(identifier, desired))
+
+	default:
+		if !desired.allTables && !reflect.DeepEqual(current.tables, desired.tables) {
+			contextLogger.Info("Updating the tables of publication", "publication", name,
+				"tables", desired.tables)
+			statements = append(statements,
+				fmt.Sprintf("ALTER PUBLICATION %s SET TABLE %s", identifier, sanitizeTables(desired.tables)))
+		}
+		if !reflect.DeepEqual(current.publish, desired.publish) {
+			contextLogger.Info("Updating the published changes of publication", "publication", name,
+				"publish", desired.publish)
+			statements = append(statements,
+				fmt.Sprintf("ALTER PUBLICATION %s SET (publish = '%s')",
+					identifier, strings.Join(desired.publish, ", ")))
+		}
+	}
+
+	if len(statements) == 0 {
+		return nil
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		// This is a no-op when the transaction is committed
+		_ = tx.Rollback()
+	}()
+
+	for _, statement := range statements {
+		if _, err := tx.ExecContext(ctx, statement); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// createPublicationStatement gets the statement creating a publication
+func createPublicationStatement(identifier string, definition *publicationDefinition) string {
+	target := "ALL TABLES"
+	if !definition.allTables {
+		target = "TABLE " + sanitizeTables(definition.tables)
+	}
+
+	return fmt.Sprintf("CREATE PUBLICATION %s FOR %s WITH (publish = '%s')",
+		identifier, target, strings.Join(definition.publish, ", "))
+}
+
+// sanitizeTables quotes a list of `schema.table` names
+func sanitizeTables(tables []string) string {
+	sanitized := make([]string, len(tables))
+	for idx, table := range tables {
+		sanitized[idx] = pgx.Identifier(strings.SplitN(table, ".", 2)).Sanitize()
+	}
+
+	return strings.Join(sanitized, ", ")
+}
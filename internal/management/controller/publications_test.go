@@ -0,0 +1,169 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"database/sql"
+	"regexp"
+
+	"github.com/DATA-DOG/go-sqlmock"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("publication definition", func() {
+	It("defaults to every kind of change and the public schema", func() {
+		definition := newPublicationDefinition(apiv1.PublicationConfiguration{
+			Name:   "pub",
+			DBName: "app",
+			Tables: []string{"sales.orders", "customers"},
+		})
+		Expect(definition.allTables).To(BeFalse())
+		Expect(definition.tables).To(Equal([]string{"public.customers", "sales.orders"}))
+		Expect(definition.publish).To(Equal([]string{"insert", "update", "delete", "truncate"}))
+	})
+
+	It("keeps the kinds of changes in the PostgreSQL order", func() {
+		definition := newPublicationDefinition(apiv1.PublicationConfiguration{
+			Name:      "pub",
+			DBName:    "app",
+			AllTables: true,
+			Publish: []apiv1.PublicationOperation{
+				apiv1.PublicationOperationDelete,
+				apiv1.PublicationOperationInsert,
+			},
+		})
+		Expect(definition.allTables).To(BeTrue())
+		Expect(definition.publish).To(Equal([]string{"insert", "delete"}))
+	})
+})
+
+var _ = Describe("publication reconciliation", func() {
+	var (
+		db   *sql.DB
+		mock sqlmock.Sqlmock
+		ctx  context.Context
+	)
+
+	BeforeEach(func() {
+		var err error
+		db, mock, err = sqlmock.New()
+		Expect(err).ToNot(HaveOccurred())
+		ctx = context.Background()
+	})
+
+	AfterEach(func() {
+		Expect(mock.ExpectationsWereMet()).To(Succeed())
+	})
+
+	It("reads a publication which doesn't exist", func() {
+		mock.ExpectQuery("FROM pg_catalog.pg_publication WHERE").
+			WithArgs("pub").
+			WillReturnRows(sqlmock.NewRows(
+				[]string{"puballtables", "pubinsert", "pubupdate", "pubdelete", "pubtruncate"}))
+
+		definition, err := getPublicationDefinition(ctx, db, "pub")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(definition).To(BeNil())
+	})
+
+	It("reads the tables of a publication", func() {
+		mock.ExpectQuery("FROM pg_catalog.pg_publication WHERE").
+			WithArgs("pub").
+			WillReturnRows(sqlmock.NewRows(
+				[]string{"puballtables", "pubinsert", "pubupdate", "pubdelete", "pubtruncate"}).
+				AddRow(false, true, false, true, false))
+		mock.ExpectQuery("FROM pg_catalog.pg_publication_tables").
+			WithArgs("pub").
+			WillReturnRows(sqlmock.NewRows([]string{"table"}).
+				AddRow("public.customers").
+				AddRow("sales.orders"))
+
+		definition, err := getPublicationDefinition(ctx, db, "pub")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(definition).To(Equal(&publicationDefinition{
+			tables:  []string{"public.customers", "sales.orders"},
+			publish: []string{"insert", "delete"},
+		}))
+	})
+
+	It("creates a missing publication", func() {
+		desired := &publicationDefinition{
+			tables:  []string{"public.customers", "sales.orders"},
+			publish: []string{"insert", "update"},
+		}
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(
+			`CREATE PUBLICATION "pub" FOR TABLE "public"."customers", "sales"."orders" ` +
+				`WITH (publish = 'insert, update')`)).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		Expect(applyPublicationDefinition(ctx, db, "pub", nil, desired)).To(Succeed())
+	})
+
+	It("recreates a publication switched to every table", func() {
+		current := &publicationDefinition{
+			tables:  []string{"public.customers"},
+			publish: []string{"insert"},
+		}
+		desired := &publicationDefinition{
+			allTables: true,
+			publish:   []string{"insert"},
+		}
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(`DROP PUBLICATION "pub"`)).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(regexp.QuoteMeta(`CREATE PUBLICATION "pub" FOR ALL TABLES WITH (publish = 'insert')`)).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		Expect(applyPublicationDefinition(ctx, db, "pub", current, desired)).To(Succeed())
+	})
+
+	It("alters the tables and the published changes", func() {
+		current := &publicationDefinition{
+			tables:  []string{"public.customers"},
+			publish: []string{"insert"},
+		}
+		desired := &publicationDefinition{
+			tables:  []string{"public.customers", "public.orders"},
+			publish: []string{"insert", "update"},
+		}
+		mock.ExpectBegin()
+		mock.ExpectExec(regexp.QuoteMeta(`ALTER PUBLICATION "pub" SET TABLE "public"."customers", "public"."orders"`)).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec(regexp.QuoteMeta(`ALTER PUBLICATION "pub" SET (publish = 'insert, update')`)).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
+
+		Expect(applyPublicationDefinition(ctx, db, "pub", current, desired)).To(Succeed())
+	})
+
+	It("does nothing when the publication matches", func() {
+		definition := &publicationDefinition{
+			allTables: true,
+			publish:   []string{"insert", "update", "delete", "truncate"},
+		}
+
+		Expect(applyPublicationDefinition(ctx, db, "pub", definition, definition)).To(Succeed())
+	})
+})
@@ -19,6 +19,7 @@ package controller
 import (
 	"context"
 	"fmt"
+	"time"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/external"
@@ -48,19 +49,50 @@ func (r *InstanceReconciler) refreshReplicaConfiguration(
 	}
 
 	if primary {
-		return false, nil
+		// The primary never inherits the read-only default and the
+		// apply delay of the standbys, and is only read-only when
+		// the whole cluster is
+		return r.writeStandbyOnlySettings(cluster.IsReadOnly(), 0)
 	}
 
+	// The designated primary of a replica cluster is the source of
+	// the other replicas, and never applies the changes with a delay
+	minApplyDelay := cluster.GetMinApplyDelay(r.instance.PodName)
 	if cluster.IsReplica() && cluster.Status.TargetPrimary == r.instance.PodName {
-		return r.writeReplicaConfigurationForDesignatedPrimary(ctx, cluster)
+		changed, err = r.writeReplicaConfigurationForDesignatedPrimary(ctx, cluster)
+		minApplyDelay = 0
+	} else {
+		changed, err = r.writeReplicaConfigurationForReplica(cluster)
+	}
+	if err != nil {
+		return changed, err
+	}
+
+	// The standbys of a read-only cluster are configured like the
+	// primary, so that a promoted one keeps rejecting the writes
+	readOnly := cluster.Spec.ReadOnlyStandbys || cluster.IsReadOnly()
+	standbyOnlyChanged, err := r.writeStandbyOnlySettings(readOnly, minApplyDelay)
+	return changed || standbyOnlyChanged, err
+}
+
+// writeStandbyOnlySettings writes the settings that are applied only
+// to the standby instances
+func (r *InstanceReconciler) writeStandbyOnlySettings(
+	readOnly bool,
+	minApplyDelay time.Duration,
+) (changed bool, err error) {
+	readOnlyChanged, err := postgres.ConfigureReadOnlyDefault(r.instance.PgData, readOnly)
+	if err != nil {
+		return readOnlyChanged, err
 	}
 
-	return r.writeReplicaConfigurationForReplica(cluster)
+	delayChanged, err := postgres.ConfigureMinApplyDelay(r.instance.PgData, minApplyDelay)
+	return readOnlyChanged || delayChanged, err
 }
 
 func (r *InstanceReconciler) writeReplicaConfigurationForReplica(cluster *apiv1.Cluster) (changed bool, err error) {
 	slotName := cluster.GetSlotNameFromInstanceName(r.instance.PodName)
-	return postgres.UpdateReplicaConfiguration(r.instance.PgData, r.instance.GetPrimaryConnInfo(), slotName)
+	return postgres.UpdateReplicaConfiguration(r.instance.PgData, r.instance.GetReplicationConnInfo(cluster), slotName)
 }
 
 func (r *InstanceReconciler) writeReplicaConfigurationForDesignatedPrimary(
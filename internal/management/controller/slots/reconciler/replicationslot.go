@@ -47,7 +47,7 @@ func ReconcileReplicationSlots(
 	}
 
 	if cluster.Status.CurrentPrimary == instanceName || cluster.Status.TargetPrimary == instanceName {
-		return reconcilePrimaryReplicationSlots(ctx, manager, cluster)
+		return reconcilePrimaryReplicationSlots(ctx, instanceName, manager, cluster)
 	}
 
 	return reconcile.Result{}, nil
@@ -55,6 +55,7 @@ func ReconcileReplicationSlots(
 
 func reconcilePrimaryReplicationSlots(
 	ctx context.Context,
+	instanceName string,
 	manager infrastructure.Manager,
 	cluster *apiv1.Cluster,
 ) (reconcile.Result, error) {
@@ -68,13 +69,15 @@ func reconcilePrimaryReplicationSlots(
 
 	expectedSlots := make(map[string]bool)
 
-	// Add every slot that is missing
-	for _, instanceName := range cluster.Status.InstanceNames {
-		if instanceName == cluster.Status.CurrentPrimary {
+	// Add every slot that is missing. During a failover or a switchover the
+	// current primary is still the old one, which will rejoin the cluster as
+	// a standby: we skip the slot for ourselves rather than for it
+	for _, name := range cluster.Status.InstanceNames {
+		if name == instanceName {
 			continue
 		}
 
-		slotName := cluster.GetSlotNameFromInstanceName(instanceName)
+		slotName := cluster.GetSlotNameFromInstanceName(name)
 		expectedSlots[slotName] = true
 
 		if currentSlots.Has(slotName) {
@@ -101,6 +104,7 @@ func reconcilePrimaryReplicationSlots(
 				contextLogger.Trace("Skipping deletion of replication slot because it is active",
 					"slot", slot)
 				needToReschedule = true
+				continue
 			}
 			contextLogger.Trace("Attempt to delete replication slot",
 				"slot", slot)
@@ -108,6 +108,24 @@ var _ = Describe("HA Replication Slots reconciliation in Primary", func() {
 		Expect(fakeSlotManager.replicationSlots).To(HaveLen(2))
 	})
 
+	It("keeps a replication slot for the former primary during a failover", func() {
+		fakeSlotManager := fakeReplicationSlotManager{
+			replicationSlots: map[fakeSlot]bool{
+				{name: slotPrefix + "instance3"}: true,
+			},
+		}
+
+		cluster := makeClusterWithInstanceNames([]string{"instance1", "instance2", "instance3"}, "instance1")
+		cluster.Status.TargetPrimary = "instance2"
+
+		_, err := ReconcileReplicationSlots(context.TODO(), "instance2", fakeSlotManager, &cluster)
+		Expect(err).ShouldNot(HaveOccurred())
+		Expect(fakeSlotManager.replicationSlots[fakeSlot{name: "_cnpg_instance1"}]).To(BeTrue())
+		Expect(fakeSlotManager.replicationSlots[fakeSlot{name: "_cnpg_instance2"}]).To(BeFalse())
+		Expect(fakeSlotManager.replicationSlots[fakeSlot{name: "_cnpg_instance3"}]).To(BeTrue())
+		Expect(fakeSlotManager.replicationSlots).To(HaveLen(2))
+	})
+
 	It("can delete an inactive replication slot that is not in the cluster", func() {
 		fakeSlotManager := fakeReplicationSlotManager{
 			replicationSlots: map[fakeSlot]bool{
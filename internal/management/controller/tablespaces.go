@@ -0,0 +1,94 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/lib/pq"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+)
+
+// reconcileTablespaces creates the tablespaces declared in the cluster
+// which don't exist yet. Tablespaces are only created on the primary,
+// and reach the replicas through the streaming replication
+func (r *InstanceReconciler) reconcileTablespaces(ctx context.Context, cluster *apiv1.Cluster) error {
+	if len(cluster.Spec.Tablespaces) == 0 {
+		return nil
+	}
+
+	ok, err := r.instance.IsPrimary()
+	if err != nil {
+		return fmt.Errorf("unable to check if instance is primary: %w", err)
+	}
+	if !ok {
+		return nil
+	}
+
+	db, err := r.instance.GetSuperUserDB()
+	if err != nil {
+		return fmt.Errorf("getting the superuserdb: %w", err)
+	}
+
+	for _, tablespace := range cluster.Spec.Tablespaces {
+		if err := createTablespaceIfNotExists(ctx, db, tablespace.Name); err != nil {
+			return fmt.Errorf("while creating tablespace %s: %w", tablespace.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// createTablespaceIfNotExists creates a tablespace in its dedicated
+// volume, unless it already exists
+func createTablespaceIfNotExists(ctx context.Context, db *sql.DB, name string) error {
+	contextLogger := log.FromContext(ctx)
+
+	var exists bool
+	row := db.QueryRowContext(ctx,
+		"SELECT EXISTS (SELECT 1 FROM pg_catalog.pg_tablespace WHERE spcname = $1)", name)
+	if err := row.Scan(&exists); err != nil {
+		return err
+	}
+	if exists {
+		return nil
+	}
+
+	location := specs.GetTablespaceLocation(name)
+	if err := ensureTablespaceLocation(location); err != nil {
+		return err
+	}
+
+	contextLogger.Info("Creating tablespace", "name", name, "location", location)
+	_, err := db.ExecContext(ctx, fmt.Sprintf("CREATE TABLESPACE %s LOCATION %s",
+		pgx.Identifier{name}.Sanitize(),
+		pq.QuoteLiteral(location)))
+	return err
+}
+
+// ensureTablespaceLocation creates the directory containing a tablespace,
+// as PostgreSQL requires it to exist
+func ensureTablespaceLocation(location string) error {
+	return os.MkdirAll(location, 0o700)
+}
@@ -46,10 +46,15 @@ type PgBouncerReconciler struct {
 	poolerWatch          watch.Interface
 	instance             PgBouncerInstanceInterface
 	poolerNamespacedName types.NamespacedName
+	serverHost           string
 }
 
-// NewPgBouncerReconciler creates a new pgbouncer reconciler
-func NewPgBouncerReconciler(poolerNamespacedName types.NamespacedName) (*PgBouncerReconciler, error) {
+// NewPgBouncerReconciler creates a new pgbouncer reconciler, connecting
+// PgBouncer to the passed cluster service
+func NewPgBouncerReconciler(
+	poolerNamespacedName types.NamespacedName,
+	serverHost string,
+) (*PgBouncerReconciler, error) {
 	client, err := management.NewControllerRuntimeClient()
 	if err != nil {
 		return nil, err
@@ -59,6 +64,7 @@ func NewPgBouncerReconciler(poolerNamespacedName types.NamespacedName) (*PgBounc
 		client:               client,
 		instance:             NewPgBouncerInstance(),
 		poolerNamespacedName: poolerNamespacedName,
+		serverHost:           serverHost,
 	}, nil
 }
 
@@ -202,7 +208,7 @@ func (r *PgBouncerReconciler) writePgBouncerConfig(ctx context.Context, pooler *
 		return false, fmt.Errorf("while reading secrets: %w", err)
 	}
 
-	if configFiles, err = config.BuildConfigurationFiles(pooler, secrets); err != nil {
+	if configFiles, err = config.BuildConfigurationFiles(pooler, secrets, r.serverHost); err != nil {
 		return false, fmt.Errorf("while generating pgbouncer configuration: %w", err)
 	}
 
@@ -29,6 +29,7 @@ import (
 	v1 "k8s.io/api/core/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/util/retry"
@@ -86,6 +87,14 @@ type PublicKeyInfrastructure struct {
 	// The labelSelector to be used to get the operators deployment,
 	// e.g. "app.kubernetes.io/name=cloudnative-pg"
 	OperatorDeploymentLabelSelector string
+
+	// The failure policy to be set in every webhook of the mutating and
+	// validating webhook configurations. When nil, the existing one is kept
+	WebhookFailurePolicy *admissionregistrationv1.FailurePolicyType
+
+	// The namespace selector to be set in every webhook of the mutating and
+	// validating webhook configurations. When nil, the existing one is kept
+	WebhookNamespaceSelector *metav1.LabelSelector
 }
 
 // RenewLeafCertificate renew a secret containing a server
@@ -408,7 +417,8 @@ func ensureMountedSecretsAreInSync(secret *v1.Secret, certDir string) error {
 }
 
 // injectPublicKeyIntoMutatingWebhook inject the TLS public key into the admitted
-// ones for a certain mutating webhook configuration
+// ones for a certain mutating webhook configuration, together with the
+// configured failure policy and namespace selector
 func (pki PublicKeyInfrastructure) injectPublicKeyIntoMutatingWebhook(
 	ctx context.Context, kubeClient client.Client, tlsSecret *v1.Secret,
 ) error {
@@ -424,6 +434,7 @@ func (pki PublicKeyInfrastructure) injectPublicKeyIntoMutatingWebhook(
 
 	for idx := range config.Webhooks {
 		config.Webhooks[idx].ClientConfig.CABundle = tlsSecret.Data["tls.crt"]
+		pki.applyWebhookSettings(&config.Webhooks[idx].FailurePolicy, &config.Webhooks[idx].NamespaceSelector)
 	}
 
 	if reflect.DeepEqual(oldConfig.Webhooks, config.Webhooks) {
@@ -434,7 +445,8 @@ func (pki PublicKeyInfrastructure) injectPublicKeyIntoMutatingWebhook(
 }
 
 // injectPublicKeyIntoValidatingWebhook inject the TLS public key into the admitted
-// ones for a certain validating webhook configuration
+// ones for a certain validating webhook configuration, together with the
+// configured failure policy and namespace selector
 func (pki PublicKeyInfrastructure) injectPublicKeyIntoValidatingWebhook(
 	ctx context.Context, kubeClient client.Client, tlsSecret *v1.Secret,
 ) error {
@@ -451,6 +463,7 @@ func (pki PublicKeyInfrastructure) injectPublicKeyIntoValidatingWebhook(
 
 	for idx := range config.Webhooks {
 		config.Webhooks[idx].ClientConfig.CABundle = tlsSecret.Data["tls.crt"]
+		pki.applyWebhookSettings(&config.Webhooks[idx].FailurePolicy, &config.Webhooks[idx].NamespaceSelector)
 	}
 
 	if reflect.DeepEqual(oldConfig.Webhooks, config.Webhooks) {
@@ -460,6 +473,22 @@ func (pki PublicKeyInfrastructure) injectPublicKeyIntoValidatingWebhook(
 	return kubeClient.Patch(ctx, config, client.MergeFrom(oldConfig))
 }
 
+// applyWebhookSettings overwrites the failure policy and the namespace
+// selector of a webhook with the configured ones, if any
+func (pki PublicKeyInfrastructure) applyWebhookSettings(
+	failurePolicy **admissionregistrationv1.FailurePolicyType,
+	namespaceSelector **metav1.LabelSelector,
+) {
+	if pki.WebhookFailurePolicy != nil {
+		policy := *pki.WebhookFailurePolicy
+		*failurePolicy = &policy
+	}
+
+	if pki.WebhookNamespaceSelector != nil {
+		*namespaceSelector = pki.WebhookNamespaceSelector.DeepCopy()
+	}
+}
+
 // injectPublicKeyIntoCRD inject the TLS public key into the admitted
 // ones from a certain conversion webhook inside a CRD
 func (pki PublicKeyInfrastructure) injectPublicKeyIntoCRD(
@@ -348,6 +348,72 @@ var _ = Describe("TLS certificates injection", func() {
 	})
 })
 
+var _ = Describe("Webhook settings reconciliation", func() {
+	ca, _ := CreateRootCA("ca-secret-name", operatorNamespaceName)
+	webhookPair, _ := ca.CreateAndSignPair("pki-service.operator-namespace.svc", CertTypeServer, nil)
+
+	failurePolicy := admissionregistrationv1.Ignore
+	namespaceSelector := &metav1.LabelSelector{
+		MatchExpressions: []metav1.LabelSelectorRequirement{
+			{
+				Key:      "kubernetes.io/metadata.name",
+				Operator: metav1.LabelSelectorOpNotIn,
+				Values:   []string{"kube-system"},
+			},
+		},
+	}
+
+	pki := pkiEnvironmentTemplate
+	pki.WebhookFailurePolicy = &failurePolicy
+	pki.WebhookNamespaceSelector = namespaceSelector
+	webhookSecret := webhookPair.GenerateCertificateSecret(pki.OperatorNamespace, pki.SecretName)
+
+	It("sets the configured settings in the mutating webhook configuration", func(ctx SpecContext) {
+		kubeClient := generateFakeClient()
+		mutatingWebhook := mutatingWebhookTemplate.DeepCopy()
+		Expect(kubeClient.Create(ctx, mutatingWebhook)).To(Succeed())
+
+		Expect(pki.injectPublicKeyIntoMutatingWebhook(ctx, kubeClient, webhookSecret)).To(Succeed())
+
+		updatedWebhook := admissionregistrationv1.MutatingWebhookConfiguration{}
+		Expect(kubeClient.Get(ctx, client.ObjectKey{Name: pki.MutatingWebhookConfigurationName}, &updatedWebhook)).
+			To(Succeed())
+		Expect(*updatedWebhook.Webhooks[0].FailurePolicy).To(Equal(admissionregistrationv1.Ignore))
+		Expect(updatedWebhook.Webhooks[0].NamespaceSelector).To(Equal(namespaceSelector))
+	})
+
+	It("sets the configured settings in the validating webhook configuration", func(ctx SpecContext) {
+		kubeClient := generateFakeClient()
+		validatingWebhook := validatingWebhookTemplate.DeepCopy()
+		Expect(kubeClient.Create(ctx, validatingWebhook)).To(Succeed())
+
+		Expect(pki.injectPublicKeyIntoValidatingWebhook(ctx, kubeClient, webhookSecret)).To(Succeed())
+
+		updatedWebhook := admissionregistrationv1.ValidatingWebhookConfiguration{}
+		Expect(kubeClient.Get(ctx, client.ObjectKey{Name: pki.ValidatingWebhookConfigurationName}, &updatedWebhook)).
+			To(Succeed())
+		Expect(*updatedWebhook.Webhooks[0].FailurePolicy).To(Equal(admissionregistrationv1.Ignore))
+		Expect(updatedWebhook.Webhooks[0].NamespaceSelector).To(Equal(namespaceSelector))
+	})
+
+	It("keeps the existing settings when not configured", func(ctx SpecContext) {
+		kubeClient := generateFakeClient()
+		failPolicy := admissionregistrationv1.Fail
+		validatingWebhook := validatingWebhookTemplate.DeepCopy()
+		validatingWebhook.Webhooks[0].FailurePolicy = &failPolicy
+		Expect(kubeClient.Create(ctx, validatingWebhook)).To(Succeed())
+
+		unconfiguredPKI := pkiEnvironmentTemplate
+		Expect(unconfiguredPKI.injectPublicKeyIntoValidatingWebhook(ctx, kubeClient, webhookSecret)).To(Succeed())
+
+		updatedWebhook := admissionregistrationv1.ValidatingWebhookConfiguration{}
+		Expect(kubeClient.Get(ctx, client.ObjectKey{Name: pki.ValidatingWebhookConfigurationName}, &updatedWebhook)).
+			To(Succeed())
+		Expect(*updatedWebhook.Webhooks[0].FailurePolicy).To(Equal(admissionregistrationv1.Fail))
+		Expect(updatedWebhook.Webhooks[0].NamespaceSelector).To(BeNil())
+	})
+})
+
 var _ = Describe("Webhook environment creation", func() {
 	It("should setup the certificates and the webhooks", func(ctx SpecContext) {
 		tempDirName, err := os.MkdirTemp("/tmp", "cert_*")
@@ -0,0 +1,106 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package concurrency
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"golang.org/x/sys/unix"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils"
+)
+
+// fileSemaphorePollInterval is how often a process waiting
+// on a FileSemaphore checks for a free slot
+const fileSemaphorePollInterval = 100 * time.Millisecond
+
+// FileSemaphore is a counting semaphore shared among the processes running
+// in the same Pod, made of a set of lock files in a directory. The lock of a
+// slot is released by the kernel when the process holding it exits, so that
+// a process which crashed can't leak a slot
+type FileSemaphore struct {
+	directory string
+	size      int
+}
+
+// NewFileSemaphore creates a FileSemaphore with the passed number
+// of slots, whose lock files are stored in the passed directory
+func NewFileSemaphore(directory string, size int) *FileSemaphore {
+	return &FileSemaphore{
+		directory: directory,
+		size:      size,
+	}
+}
+
+// TryAcquire acquires a slot of the semaphore without waiting.
+// When a slot is acquired, a function to release it is returned,
+// otherwise the returned function is nil
+func (s *FileSemaphore) TryAcquire() (func() error, error) {
+	if err := fileutils.EnsureDirectoryExists(s.directory); err != nil {
+		return nil, err
+	}
+
+	for idx := 0; idx < s.size; idx++ {
+		slotFile, err := os.OpenFile( // #nosec
+			filepath.Join(s.directory, fmt.Sprintf("slot-%d", idx)),
+			os.O_CREATE|os.O_RDWR,
+			0o600)
+		if err != nil {
+			return nil, err
+		}
+
+		err = unix.Flock(int(slotFile.Fd()), unix.LOCK_EX|unix.LOCK_NB)
+		if errors.Is(err, unix.EWOULDBLOCK) {
+			_ = slotFile.Close()
+			continue
+		}
+		if err != nil {
+			_ = slotFile.Close()
+			return nil, fmt.Errorf("while locking %v: %w", slotFile.Name(), err)
+		}
+
+		return slotFile.Close, nil
+	}
+
+	return nil, nil
+}
+
+// Acquire acquires a slot of the semaphore, waiting for one to be
+// released when all of them are in use. It returns a function to
+// release the slot
+func (s *FileSemaphore) Acquire(ctx context.Context) (func() error, error) {
+	ticker := time.NewTicker(fileSemaphorePollInterval)
+	defer ticker.Stop()
+
+	for {
+		release, err := s.TryAcquire()
+		if err != nil || release != nil {
+			return release, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
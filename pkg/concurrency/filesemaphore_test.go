@@ -0,0 +1,80 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package concurrency
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("FileSemaphore", func() {
+	It("grants at most the configured number of slots", func() {
+		semaphore := NewFileSemaphore(GinkgoT().TempDir(), 2)
+
+		releaseFirst, err := semaphore.TryAcquire()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(releaseFirst).ToNot(BeNil())
+
+		releaseSecond, err := semaphore.TryAcquire()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(releaseSecond).ToNot(BeNil())
+
+		releaseThird, err := semaphore.TryAcquire()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(releaseThird).To(BeNil())
+
+		Expect(releaseFirst()).To(Succeed())
+		releaseThird, err = semaphore.TryAcquire()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(releaseThird).ToNot(BeNil())
+
+		Expect(releaseSecond()).To(Succeed())
+		Expect(releaseThird()).To(Succeed())
+	})
+
+	It("waits for a slot to be released", func() {
+		semaphore := NewFileSemaphore(GinkgoT().TempDir(), 1)
+		release, err := semaphore.TryAcquire()
+		Expect(err).ToNot(HaveOccurred())
+
+		go func() {
+			time.Sleep(200 * time.Millisecond)
+			_ = release()
+		}()
+
+		releaseAgain, err := semaphore.Acquire(context.Background())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(releaseAgain()).To(Succeed())
+	})
+
+	It("stops waiting when the context is cancelled", func() {
+		semaphore := NewFileSemaphore(GinkgoT().TempDir(), 1)
+		release, err := semaphore.TryAcquire()
+		Expect(err).ToNot(HaveOccurred())
+		defer func() {
+			_ = release()
+		}()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+		defer cancel()
+		_, err = semaphore.Acquire(ctx)
+		Expect(err).To(MatchError(context.DeadlineExceeded))
+	})
+})
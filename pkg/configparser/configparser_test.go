@@ -38,6 +38,9 @@ type FakeData struct {
 
 	// EnablePodDebugging enable debugging mode in new generated pods
 	EnablePodDebugging bool `json:"enablePodDebugging" env:"POD_DEBUG"`
+
+	// RequestTimeout is the timeout, in seconds, of a request
+	RequestTimeout int `json:"requestTimeout" env:"REQUEST_TIMEOUT"`
 }
 
 var defaultInheritedAnnotations = []string{
@@ -48,9 +51,16 @@ var defaultInheritedAnnotations = []string{
 
 const oneNamespace = "one-namespace"
 
+const defaultRequestTimeout = 30
+
 // readConfigMap reads the configuration from the environment and the passed in data map
 func (config *FakeData) readConfigMap(data map[string]string, env EnvironmentSource) {
-	ReadConfigMap(config, &FakeData{InheritedAnnotations: defaultInheritedAnnotations}, data, env)
+	ReadConfigMap(
+		config,
+		&FakeData{InheritedAnnotations: defaultInheritedAnnotations, RequestTimeout: defaultRequestTimeout},
+		data,
+		env,
+	)
 }
 
 var _ = Describe("Data test suite", func() {
@@ -90,6 +100,19 @@ var _ = Describe("Data test suite", func() {
 		Expect(config.InheritedAnnotations).To(Equal(defaultInheritedAnnotations))
 		Expect(config.InheritedLabels).To(BeNil())
 	})
+
+	It("loads integer values, skipping the invalid ones", func() {
+		config := &FakeData{}
+		config.readConfigMap(nil, NewFakeEnvironment(nil))
+		Expect(config.RequestTimeout).To(Equal(defaultRequestTimeout))
+
+		config.readConfigMap(map[string]string{"REQUEST_TIMEOUT": "5"}, NewFakeEnvironment(nil))
+		Expect(config.RequestTimeout).To(Equal(5))
+
+		config = &FakeData{}
+		config.readConfigMap(map[string]string{"REQUEST_TIMEOUT": "five"}, NewFakeEnvironment(nil))
+		Expect(config.RequestTimeout).To(BeZero())
+	})
 })
 
 // FakeEnvironment is an EnvironmentSource that fetches data from an internal map
@@ -33,3 +33,21 @@ func CreateFifo(fileName string) error {
 	}
 	return nil
 }
+
+// IsDirectoryWritable checks whether the current user can create files
+// inside the given directory
+func IsDirectoryWritable(directory string) bool {
+	return unix.Access(directory, unix.W_OK|unix.X_OK) == nil
+}
+
+// GetVolumeUsage gets the bytes used in the file system containing the
+// given path, and the ones available to unprivileged users
+func GetVolumeUsage(path string) (usedBytes int64, availableBytes int64, err error) {
+	var stat unix.Statfs_t
+	if err = unix.Statfs(path, &stat); err != nil {
+		return 0, 0, err
+	}
+
+	blockSize := uint64(stat.Bsize)
+	return int64((stat.Blocks - stat.Bfree) * blockSize), int64(stat.Bavail * blockSize), nil
+}
@@ -26,3 +26,13 @@ import "fmt"
 func CreateFifo(fileName string) error {
 	panic(fmt.Sprintf("function CreateFifo() should not be used in Windows"))
 }
+
+// IsDirectoryWritable fakes function for cross-compiling compatibility
+func IsDirectoryWritable(directory string) bool {
+	panic(fmt.Sprintf("function IsDirectoryWritable() should not be used in Windows"))
+}
+
+// GetVolumeUsage fakes function for cross-compiling compatibility
+func GetVolumeUsage(path string) (usedBytes int64, availableBytes int64, err error) {
+	panic(fmt.Sprintf("function GetVolumeUsage() should not be used in Windows"))
+}
@@ -28,6 +28,7 @@ import (
 	"time"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/concurrency"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman"
 	barmanCapabilities "github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/capabilities"
@@ -56,6 +57,10 @@ type WALArchiver struct {
 	env []string
 
 	pgDataDirectory string
+
+	// The semaphore limiting the barman-cloud-wal-archive processes
+	// running at the same time in the Pod, nil when not limited
+	archiveSlots *concurrency.FileSemaphore
 }
 
 // WALArchiverResult contains the result of the archival of one WAL
@@ -98,6 +103,17 @@ func New(
 	return archiver, nil
 }
 
+// LimitConcurrency limits the number of barman-cloud-wal-archive processes
+// running at the same time in the Pod, across every WALArchiver using the
+// same slots directory. A limit lower than 1 means no limit
+func (archiver *WALArchiver) LimitConcurrency(slotsDirectory string, limit int) {
+	if limit < 1 {
+		archiver.archiveSlots = nil
+		return
+	}
+	archiver.archiveSlots = concurrency.NewFileSemaphore(slotsDirectory, limit)
+}
+
 // DeleteFromSpool checks if a WAL file is in the spool and, if it is, remove it
 func (archiver *WALArchiver) DeleteFromSpool(walName string) (hasBeenDeleted bool, err error) {
 	var isContained bool
@@ -128,7 +144,7 @@ func (archiver *WALArchiver) ArchiveList(
 			walStatus := &result[walIndex]
 			walStatus.WalName = walNames[walIndex]
 			walStatus.StartTime = time.Now()
-			walStatus.Err = archiver.Archive(walNames[walIndex], options)
+			walStatus.Err = archiver.Archive(ctx, walNames[walIndex], options)
 			walStatus.EndTime = time.Now()
 			if walStatus.Err == nil && walIndex != 0 {
 				walStatus.Err = archiver.spool.Touch(walNames[walIndex])
@@ -162,11 +178,22 @@ func (archiver *WALArchiver) ArchiveList(
 
 // Archive archives a certain WAL file using barman-cloud-wal-archive.
 // See archiveWALFileList for the meaning of the parameters
-func (archiver *WALArchiver) Archive(walName string, baseOptions []string) error {
+func (archiver *WALArchiver) Archive(ctx context.Context, walName string, baseOptions []string) error {
 	optionsLength := len(baseOptions)
 	if optionsLength >= math.MaxInt-1 {
 		return fmt.Errorf("can't archive wal file %v, options too long", walName)
 	}
+
+	release, err := archiver.acquireArchiveSlot(ctx, walName)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := release(); err != nil {
+			log.FromContext(ctx).Warning("Error while releasing the WAL archive slot",
+				"walName", walName, "err", err)
+		}
+	}()
 	options := make([]string, optionsLength, optionsLength+1)
 	copy(options, baseOptions)
 	options = append(options, walName)
@@ -181,7 +208,7 @@ func (archiver *WALArchiver) Archive(walName string, baseOptions []string) error
 	barmanCloudWalArchiveCmd := exec.Command(barmanCapabilities.BarmanCloudWalArchive, options...) // #nosec G204
 	barmanCloudWalArchiveCmd.Env = archiver.env
 
-	err := execlog.RunStreaming(barmanCloudWalArchiveCmd, barmanCapabilities.BarmanCloudWalArchive)
+	err = execlog.RunStreaming(barmanCloudWalArchiveCmd, barmanCapabilities.BarmanCloudWalArchive)
 	if err != nil {
 		log.Error(err, "Error invoking "+barmanCapabilities.BarmanCloudWalArchive,
 			"walName", walName,
@@ -203,6 +230,67 @@ func (archiver *WALArchiver) Archive(walName string, baseOptions []string) error
 	return nil
 }
 
+// acquireArchiveSlot waits for barman-cloud-wal-archive to be allowed to run,
+// when the number of processes running at the same time is limited. It
+// returns a function releasing the acquired slot
+func (archiver *WALArchiver) acquireArchiveSlot(ctx context.Context, walName string) (func() error, error) {
+	if archiver.archiveSlots == nil {
+		return func() error { return nil }, nil
+	}
+
+	release, err := archiver.archiveSlots.TryAcquire()
+	if err != nil || release != nil {
+		return release, err
+	}
+
+	log.FromContext(ctx).Info("Too many WAL files being archived at the same time, "+
+		"waiting for a free slot",
+		"walName", walName)
+	waitStartTime := time.Now()
+	if release, err = archiver.archiveSlots.Acquire(ctx); err != nil {
+		return nil, fmt.Errorf("while waiting to archive %v: %w", walName, err)
+	}
+	log.FromContext(ctx).Info("WAL archiving resumed",
+		"walName", walName,
+		"waitTime", time.Since(waitStartTime))
+
+	return release, nil
+}
+
+// TestConnectivity checks, via the `--test` option of barman-cloud-wal-archive,
+// that the object store can be reached with the configured credentials.
+// The options are the same ones used to archive the WAL files
+func (archiver *WALArchiver) TestConnectivity(ctx context.Context, baseOptions []string) error {
+	contextLogger := log.FromContext(ctx)
+
+	options := make([]string, 0, len(baseOptions)+1)
+	options = append(options, "--test")
+	options = append(options, baseOptions...)
+
+	contextLogger.Trace("Executing "+barmanCapabilities.BarmanCloudWalArchive,
+		"currentPrimary", archiver.cluster.Status.CurrentPrimary,
+		"targetPrimary", archiver.cluster.Status.TargetPrimary,
+		"options", options,
+	)
+
+	barmanCloudWalArchiveCmd := exec.Command(barmanCapabilities.BarmanCloudWalArchive, options...) // #nosec G204
+	barmanCloudWalArchiveCmd.Env = archiver.env
+
+	err := execlog.RunStreaming(barmanCloudWalArchiveCmd, barmanCapabilities.BarmanCloudWalArchive)
+	if err != nil {
+		contextLogger.Error(err, "Error testing the connectivity to the object store",
+			"currentPrimary", archiver.cluster.Status.CurrentPrimary,
+			"targetPrimary", archiver.cluster.Status.TargetPrimary,
+			"options", options,
+			"exitCode", barmanCloudWalArchiveCmd.ProcessState.ExitCode(),
+		)
+		return fmt.Errorf("object store unreachable, %s --test failed: %w",
+			barmanCapabilities.BarmanCloudWalArchive, err)
+	}
+
+	return nil
+}
+
 // IsCheckWalArchiveFlagFilePresent returns true if the file CheckEmptyWalArchiveFile is present in the PGDATA directory
 func (archiver *WALArchiver) IsCheckWalArchiveFlagFilePresent(ctx context.Context, pgDataDirectory string) bool {
 	contextLogger := log.FromContext(ctx)
@@ -0,0 +1,55 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package archiver
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("WAL archiving concurrency limit", func() {
+	It("doesn't wait when the concurrency is not limited", func() {
+		walArchiver := &WALArchiver{}
+		release, err := walArchiver.acquireArchiveSlot(context.Background(), "000000010000000000000001")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(release()).To(Succeed())
+	})
+
+	It("waits for a free slot when the limit is reached", func() {
+		slotsDirectory := GinkgoT().TempDir()
+		walArchiver := &WALArchiver{}
+		walArchiver.LimitConcurrency(slotsDirectory, 1)
+		otherArchiver := &WALArchiver{}
+		otherArchiver.LimitConcurrency(slotsDirectory, 1)
+
+		release, err := walArchiver.acquireArchiveSlot(context.Background(), "000000010000000000000001")
+		Expect(err).ToNot(HaveOccurred())
+
+		ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+		defer cancel()
+		_, err = otherArchiver.acquireArchiveSlot(ctx, "000000010000000000000002")
+		Expect(err).To(HaveOccurred())
+
+		Expect(release()).To(Succeed())
+		release, err = otherArchiver.acquireArchiveSlot(context.Background(), "000000010000000000000002")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(release()).To(Succeed())
+	})
+})
@@ -0,0 +1,139 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package archiver
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils"
+)
+
+const (
+	// WalStagingDirectoryName is the name of the directory where the WAL
+	// files are staged before being archived in the background
+	WalStagingDirectoryName = "wal-staging"
+
+	// stagingTemporarySuffix is the suffix of the WAL files which are
+	// being copied in the staging directory
+	stagingTemporarySuffix = ".tmp"
+)
+
+// GetStagingDirectory gets the directory where the WAL files are staged.
+// It lives outside the PGDATA, in the volume containing `pg_wal`, so
+// that the staged WAL files survive a restart of the instance and are not
+// included in the base backups
+func GetStagingDirectory(pgDataDirectory string) (string, error) {
+	walDirectory := filepath.Join(pgDataDirectory, "pg_wal")
+	walDirectoryInfo, err := os.Lstat(walDirectory)
+	if err != nil {
+		return "", fmt.Errorf("while checking the WAL directory: %w", err)
+	}
+
+	// When pg_wal is not a link to a dedicated volume, it lives inside
+	// the PGDATA, which is itself a subdirectory of the data volume
+	volumeDirectory := filepath.Dir(filepath.Clean(pgDataDirectory))
+	if walDirectoryInfo.Mode()&os.ModeSymlink != 0 {
+		resolvedWalDirectory, err := filepath.EvalSymlinks(walDirectory)
+		if err != nil {
+			return "", fmt.Errorf("while resolving the WAL directory: %w", err)
+		}
+		volumeDirectory = filepath.Dir(resolvedWalDirectory)
+	}
+
+	return filepath.Join(volumeDirectory, WalStagingDirectoryName), nil
+}
+
+// StageWAL durably copies a WAL file in the staging directory. The WAL
+// file is copied and not linked, given PostgreSQL recycles the WAL
+// segments rewriting them in place. The copy is written in a temporary
+// file which is renamed only once synced, so a staged WAL file is always
+// complete, and staging the same WAL file again just replaces it
+func StageWAL(stagingDirectory string, walPath string) error {
+	if err := fileutils.EnsureDirectoryExists(stagingDirectory); err != nil {
+		return err
+	}
+
+	stagedPath := filepath.Join(stagingDirectory, path.Base(walPath))
+	temporaryPath := stagedPath + stagingTemporarySuffix
+	if err := fileutils.CopyFile(walPath, temporaryPath); err != nil {
+		return fmt.Errorf("while copying %v in the staging directory: %w", walPath, err)
+	}
+
+	if err := os.Rename(temporaryPath, stagedPath); err != nil {
+		return fmt.Errorf("while staging %v: %w", walPath, err)
+	}
+
+	return syncDirectory(stagingDirectory)
+}
+
+// ListStagedWALs gets the names of the WAL files waiting in the
+// staging directory, in the order they should be archived
+func ListStagedWALs(stagingDirectory string) ([]string, error) {
+	entries, err := fileutils.GetDirectoryContent(stagingDirectory)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	walNames := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if strings.HasSuffix(entry, stagingTemporarySuffix) {
+			continue
+		}
+		walNames = append(walNames, entry)
+	}
+	sort.Strings(walNames)
+
+	return walNames, nil
+}
+
+// CountStagedWALs gets the number of WAL files waiting in the
+// staging directory
+func CountStagedWALs(stagingDirectory string) (int, error) {
+	walNames, err := ListStagedWALs(stagingDirectory)
+	return len(walNames), err
+}
+
+// RemoveStagedWAL removes a WAL file from the staging directory,
+// once it has been archived
+func RemoveStagedWAL(stagingDirectory string, walName string) error {
+	return fileutils.RemoveFile(filepath.Join(stagingDirectory, walName))
+}
+
+// syncDirectory flushes the content of a directory, making the
+// files created or renamed inside it durable
+func syncDirectory(directory string) (err error) {
+	dir, err := os.Open(directory) // #nosec
+	if err != nil {
+		return err
+	}
+	defer func() {
+		closeError := dir.Close()
+		if err == nil && closeError != nil {
+			err = closeError
+		}
+	}()
+
+	return dir.Sync()
+}
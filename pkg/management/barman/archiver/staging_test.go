@@ -0,0 +1,85 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package archiver
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("WAL staging directory", func() {
+	var volume, pgData string
+
+	BeforeEach(func() {
+		volume = GinkgoT().TempDir()
+		pgData = filepath.Join(volume, "pgdata")
+		Expect(os.MkdirAll(filepath.Join(pgData, "pg_wal"), 0o700)).To(Succeed())
+	})
+
+	It("is in the data volume, outside the PGDATA", func() {
+		stagingDirectory, err := GetStagingDirectory(pgData)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(stagingDirectory).To(Equal(filepath.Join(volume, WalStagingDirectoryName)))
+	})
+
+	It("is in the WAL volume when pg_wal is a link", func() {
+		walVolume := GinkgoT().TempDir()
+		Expect(os.MkdirAll(filepath.Join(walVolume, "pg_wal"), 0o700)).To(Succeed())
+		Expect(os.Remove(filepath.Join(pgData, "pg_wal"))).To(Succeed())
+		Expect(os.Symlink(filepath.Join(walVolume, "pg_wal"), filepath.Join(pgData, "pg_wal"))).To(Succeed())
+
+		stagingDirectory, err := GetStagingDirectory(pgData)
+		Expect(err).ToNot(HaveOccurred())
+		resolvedWalVolume, err := filepath.EvalSymlinks(walVolume)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(stagingDirectory).To(Equal(filepath.Join(resolvedWalVolume, WalStagingDirectoryName)))
+	})
+
+	It("stages, lists and removes the WAL files", func() {
+		stagingDirectory := filepath.Join(volume, WalStagingDirectoryName)
+		Expect(ListStagedWALs(stagingDirectory)).To(BeEmpty())
+
+		for _, walName := range []string{"000000010000000000000003", "000000010000000000000002"} {
+			walPath := filepath.Join(pgData, "pg_wal", walName)
+			Expect(fileutils.WriteStringToFile(walPath, walName)).To(BeTrue())
+			Expect(StageWAL(stagingDirectory, walPath)).To(Succeed())
+		}
+
+		// Staging again the same WAL file is harmless
+		Expect(StageWAL(stagingDirectory, filepath.Join(pgData, "pg_wal", "000000010000000000000002"))).
+			To(Succeed())
+
+		// Incomplete copies are ignored
+		Expect(fileutils.CreateEmptyFile(
+			filepath.Join(stagingDirectory, "000000010000000000000004"+stagingTemporarySuffix))).To(Succeed())
+
+		Expect(ListStagedWALs(stagingDirectory)).To(Equal([]string{
+			"000000010000000000000002",
+			"000000010000000000000003",
+		}))
+		Expect(fileutils.ReadFile(filepath.Join(stagingDirectory, "000000010000000000000003"))).
+			To(BeEquivalentTo("000000010000000000000003"))
+
+		Expect(RemoveStagedWAL(stagingDirectory, "000000010000000000000002")).To(Succeed())
+		Expect(CountStagedWALs(stagingDirectory)).To(Equal(1))
+	})
+})
@@ -0,0 +1,97 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package archiver
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils"
+)
+
+// SuspendedWalArchiveFile is the name of the file in the PGDATA listing
+// the WAL files which haven't been archived because the WAL archiving
+// was suspended. They will be archived again once the WAL archiving
+// is resumed, if PostgreSQL has not recycled them yet
+const SuspendedWalArchiveFile = ".suspended-wal-archive"
+
+// RecordSuspendedWAL adds a WAL file to the list of the ones which
+// haven't been archived because the WAL archiving is suspended
+func RecordSuspendedWAL(pgDataDirectory string, walName string) (err error) {
+	filePath := filepath.Join(pgDataDirectory, SuspendedWalArchiveFile)
+	stream, err := os.OpenFile(filePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600) // #nosec
+	if err != nil {
+		return err
+	}
+	defer func() {
+		closeError := stream.Close()
+		if err == nil && closeError != nil {
+			err = closeError
+		}
+	}()
+
+	if _, err = stream.WriteString(path.Base(walName) + "\n"); err != nil {
+		return err
+	}
+
+	return stream.Sync()
+}
+
+// RequeueSuspendedWALs asks PostgreSQL to archive again the WAL files
+// which have been skipped while the WAL archiving was suspended, by
+// marking them as ready to be archived. The WAL files that have already
+// been recycled by PostgreSQL can't be archived anymore and are returned
+// as lost. The list of the suspended WAL files is removed afterwards
+func RequeueSuspendedWALs(pgDataDirectory string) (requeued []string, lost []string, err error) {
+	filePath := filepath.Join(pgDataDirectory, SuspendedWalArchiveFile)
+	content, err := fileutils.ReadFile(filePath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	walDirectory := filepath.Join(pgDataDirectory, "pg_wal")
+	statusDirectory := filepath.Join(walDirectory, "archive_status")
+	for _, walName := range strings.Split(string(content), "\n") {
+		if walName == "" {
+			continue
+		}
+
+		exists, err := fileutils.FileExists(filepath.Join(walDirectory, walName))
+		if err != nil {
+			return nil, nil, err
+		}
+		if !exists {
+			lost = append(lost, walName)
+			continue
+		}
+
+		// The ".ready" file is created before removing the ".done" one,
+		// otherwise PostgreSQL may recycle the WAL file in the meantime
+		if err := fileutils.CreateEmptyFile(filepath.Join(statusDirectory, walName+".ready")); err != nil {
+			return nil, nil, fmt.Errorf("while marking %v as ready to be archived: %w", walName, err)
+		}
+		if err := fileutils.RemoveFile(filepath.Join(statusDirectory, walName+".done")); err != nil {
+			return nil, nil, fmt.Errorf("while marking %v as ready to be archived: %w", walName, err)
+		}
+		requeued = append(requeued, walName)
+	}
+
+	return requeued, lost, fileutils.RemoveFile(filePath)
+}
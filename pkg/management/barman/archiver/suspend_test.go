@@ -0,0 +1,65 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package archiver
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Suspended WAL archiving", func() {
+	var pgData string
+
+	BeforeEach(func() {
+		pgData = GinkgoT().TempDir()
+		Expect(os.MkdirAll(filepath.Join(pgData, "pg_wal", "archive_status"), 0o700)).To(Succeed())
+	})
+
+	It("does nothing when no WAL file has been skipped", func() {
+		requeued, lost, err := RequeueSuspendedWALs(pgData)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(requeued).To(BeEmpty())
+		Expect(lost).To(BeEmpty())
+	})
+
+	It("marks the skipped WAL files as ready to be archived", func() {
+		const available = "000000010000000000000002"
+		const recycled = "000000010000000000000003"
+
+		Expect(RecordSuspendedWAL(pgData, "pg_wal/"+available)).To(Succeed())
+		Expect(RecordSuspendedWAL(pgData, "pg_wal/"+recycled)).To(Succeed())
+		Expect(fileutils.CreateEmptyFile(filepath.Join(pgData, "pg_wal", available))).To(Succeed())
+		Expect(fileutils.CreateEmptyFile(
+			filepath.Join(pgData, "pg_wal", "archive_status", available+".done"))).To(Succeed())
+
+		requeued, lost, err := RequeueSuspendedWALs(pgData)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(requeued).To(ConsistOf(available))
+		Expect(lost).To(ConsistOf(recycled))
+
+		Expect(fileutils.FileExists(
+			filepath.Join(pgData, "pg_wal", "archive_status", available+".ready"))).To(BeTrue())
+		Expect(fileutils.FileExists(
+			filepath.Join(pgData, "pg_wal", "archive_status", available+".done"))).To(BeFalse())
+		Expect(fileutils.FileExists(filepath.Join(pgData, SuspendedWalArchiveFile))).To(BeFalse())
+	})
+})
@@ -70,13 +70,49 @@ func DeleteBackupsByPolicy(backupConfig *v1.BackupConfiguration, serverName stri
 		barmanConfiguration.DestinationPath,
 		serverName)
 
+	return runBarmanCloudBackupDelete(options, env)
+}
+
+// DeleteBackup executes a command that deletes the backup with the passed ID,
+// given the Barman object store configuration, the server name and the
+// environment variables. barman-cloud also deletes the WAL files which are
+// not needed anymore by the remaining backups
+func DeleteBackup(
+	barmanConfiguration *v1.BarmanObjectStoreConfiguration,
+	serverName string,
+	backupID string,
+	env []string,
+) error {
+	var options []string
+	if barmanConfiguration.EndpointURL != "" {
+		options = append(options, "--endpoint-url", barmanConfiguration.EndpointURL)
+	}
+
+	options, err := AppendCloudProviderOptionsFromConfiguration(options, barmanConfiguration)
+	if err != nil {
+		return err
+	}
+
+	options = append(
+		options,
+		"--backup-id",
+		backupID,
+		barmanConfiguration.DestinationPath,
+		serverName)
+
+	return runBarmanCloudBackupDelete(options, env)
+}
+
+// runBarmanCloudBackupDelete runs barman-cloud-backup-delete with the
+// passed options and environment variables
+func runBarmanCloudBackupDelete(options []string, env []string) error {
 	var stdoutBuffer bytes.Buffer
 	var stderrBuffer bytes.Buffer
 	cmd := exec.Command(barmanCapabilities.BarmanCloudBackupDelete, options...) // #nosec G204
 	cmd.Env = env
 	cmd.Stdout = &stdoutBuffer
 	cmd.Stderr = &stderrBuffer
-	err = cmd.Run()
+	err := cmd.Run()
 	if err != nil {
 		barmanLog.Error(err,
 			"Error invoking "+barmanCapabilities.BarmanCloudBackupDelete,
@@ -32,5 +32,6 @@ type Capabilities struct {
 	HasSnappy                  bool
 	HasErrorCodesForWALRestore bool
 	HasAzureManagedIdentity    bool
+	HasZstd                    bool
 	Version                    *semver.Version
 }
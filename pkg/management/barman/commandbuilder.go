@@ -18,10 +18,12 @@ package barman
 
 import (
 	"fmt"
+	"strconv"
 
 	v1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	barmanCapabilities "github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/capabilities"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 )
 
 // AppendCloudProviderOptionsFromConfiguration takes an options array and adds the cloud provider specified
@@ -103,3 +105,70 @@ func appendCloudProviderOptions(options []string, credentials v1.BarmanCredentia
 
 	return options, nil
 }
+
+// AppendTagsOptions takes an options array and adds the tags to be attached
+// to the objects written in the object store. The history tags are only
+// supported by barman-cloud-wal-archive, and are added when withHistoryTags
+// is true
+func AppendTagsOptions(
+	options []string,
+	barmanConfiguration *v1.BarmanObjectStoreConfiguration,
+	withHistoryTags bool,
+) ([]string, error) {
+	tags := barmanConfiguration.Tags
+	var historyTags map[string]string
+	if withHistoryTags {
+		historyTags = barmanConfiguration.HistoryTags
+	}
+	if len(tags) == 0 && len(historyTags) == 0 {
+		return options, nil
+	}
+
+	capabilities, err := barmanCapabilities.CurrentCapabilities()
+	if err != nil {
+		return nil, err
+	}
+	if !capabilities.HasTags {
+		return nil, fmt.Errorf("tags are not supported in Barman %v", capabilities.Version)
+	}
+
+	if len(tags) > 0 {
+		tagsOptions, err := utils.MapToBarmanTagsFormat("--tags", tags)
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, tagsOptions...)
+	}
+
+	if len(historyTags) > 0 {
+		historyTagsOptions, err := utils.MapToBarmanTagsFormat("--history-tags", historyTags)
+		if err != nil {
+			return nil, err
+		}
+		options = append(options, historyTagsOptions...)
+	}
+
+	return options, nil
+}
+
+// ZstdCompressionOptions gets the barman-cloud options tuning the
+// zstd compression, shared by the WAL archive and the base backups.
+// Nothing is returned when barman-cloud doesn't support zstd
+func ZstdCompressionOptions(
+	configuration *v1.ZstdCompressionConfiguration,
+	capabilities *barmanCapabilities.Capabilities,
+) []string {
+	if configuration == nil || !capabilities.HasZstd {
+		return nil
+	}
+
+	var options []string
+	if configuration.Level != nil {
+		options = append(
+			options,
+			"--compression-level",
+			strconv.Itoa(*configuration.Level))
+	}
+
+	return options
+}
@@ -0,0 +1,46 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package barman
+
+import (
+	"k8s.io/utils/pointer"
+
+	v1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	barmanCapabilities "github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/capabilities"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("zstd compression options", func() {
+	withZstd := &barmanCapabilities.Capabilities{HasZstd: true}
+
+	It("doesn't add any option without a zstd tuning", func() {
+		Expect(ZstdCompressionOptions(nil, withZstd)).To(BeEmpty())
+		Expect(ZstdCompressionOptions(&v1.ZstdCompressionConfiguration{}, withZstd)).To(BeEmpty())
+	})
+
+	It("sets the compression level", func() {
+		configuration := &v1.ZstdCompressionConfiguration{Level: pointer.Int(6)}
+		Expect(ZstdCompressionOptions(configuration, withZstd)).To(Equal([]string{"--compression-level", "6"}))
+	})
+
+	It("doesn't add any option when barman-cloud doesn't support zstd", func() {
+		configuration := &v1.ZstdCompressionConfiguration{Level: pointer.Int(6)}
+		Expect(ZstdCompressionOptions(configuration, &barmanCapabilities.Capabilities{})).To(BeEmpty())
+	})
+})
@@ -20,6 +20,8 @@ package credentials
 import (
 	"context"
 	"fmt"
+	"path"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -29,6 +31,27 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 )
 
+// These are the environment variables, read from the files having
+// the same name, that can be set through the credentials volume
+var (
+	awsVolumeCredentials = []string{
+		"AWS_ACCESS_KEY_ID",
+		"AWS_SECRET_ACCESS_KEY",
+		"AWS_SESSION_TOKEN",
+		"AWS_DEFAULT_REGION",
+	}
+	azureVolumeCredentials = []string{
+		"AZURE_STORAGE_ACCOUNT",
+		"AZURE_STORAGE_KEY",
+		"AZURE_STORAGE_SAS_TOKEN",
+		"AZURE_STORAGE_CONNECTION_STRING",
+	}
+)
+
+// googleVolumeCredentials is the file of the credentials volume containing
+// the Google Cloud Storage JSON credentials
+const googleVolumeCredentials = "GOOGLE_APPLICATION_CREDENTIALS"
+
 // EnvSetBackupCloudCredentials sets the AWS environment variables needed for backups
 // given the configuration inside the cluster
 func EnvSetBackupCloudCredentials(
@@ -73,6 +96,13 @@ func envSetCloudCredentials(
 	configuration *apiv1.BarmanObjectStoreConfiguration,
 	env []string,
 ) (envs []string, err error) {
+	if configuration.CredentialsVolume != nil {
+		return envSetCredentialsFromVolume(
+			configuration.BarmanCredentials,
+			postgres.ObjectStoreCredentialsDirectory,
+			env)
+	}
+
 	if configuration.BarmanCredentials.AWS != nil {
 		return envSetAWSCredentials(ctx, c, namespace, configuration.BarmanCredentials.AWS, env)
 	}
@@ -239,6 +269,59 @@ func envSetAzureCredentials(
 	return env, nil
 }
 
+// envSetCredentialsFromVolume sets the environment variables of the cloud
+// provider reading them from the files of the credentials volume, mounted in
+// the passed directory. The files are read every time, so that the rotated
+// credentials are used as soon as the CSI driver updates them
+func envSetCredentialsFromVolume(
+	credentials apiv1.BarmanCredentials,
+	directory string,
+	env []string,
+) ([]string, error) {
+	if credentials.Google != nil {
+		credentialsFile := path.Join(directory, googleVolumeCredentials)
+		exists, err := fileutils.FileExists(credentialsFile)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			return nil, fmt.Errorf("missing %s in the credentials volume", googleVolumeCredentials)
+		}
+		return append(env, fmt.Sprintf("%s=%s", googleVolumeCredentials, credentialsFile)), nil
+	}
+
+	var variables []string
+	switch {
+	case credentials.AWS != nil:
+		variables = awsVolumeCredentials
+	case credentials.Azure != nil:
+		variables = azureVolumeCredentials
+	default:
+		return nil, fmt.Errorf("missing the cloud provider of the credentials volume")
+	}
+
+	found := 0
+	for _, variable := range variables {
+		content, err := fileutils.ReadFile(path.Join(directory, variable))
+		if err != nil {
+			return nil, fmt.Errorf("while reading %s from the credentials volume: %w", variable, err)
+		}
+		if content == nil {
+			continue
+		}
+
+		found++
+		env = append(env, fmt.Sprintf("%s=%s", variable, strings.TrimRight(string(content), "\r\n")))
+	}
+
+	if found == 0 {
+		return nil, fmt.Errorf("no credentials found in the credentials volume, expected one of: %s",
+			strings.Join(variables, ", "))
+	}
+
+	return env, nil
+}
+
 func envSetGoogleCredentials(
 	ctx context.Context,
 	c client.Client,
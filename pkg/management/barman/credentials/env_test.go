@@ -0,0 +1,86 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package credentials
+
+import (
+	"os"
+	"path"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("credentials read from a volume", func() {
+	var directory string
+
+	BeforeEach(func() {
+		directory = GinkgoT().TempDir()
+	})
+
+	writeCredential := func(name, value string) {
+		Expect(os.WriteFile(path.Join(directory, name), []byte(value), 0o600)).To(Succeed())
+	}
+
+	It("reads the AWS credentials from the files of the volume", func() {
+		writeCredential("AWS_ACCESS_KEY_ID", "id\n")
+		writeCredential("AWS_SECRET_ACCESS_KEY", "secret")
+		writeCredential("UNRELATED", "value")
+
+		env, err := envSetCredentialsFromVolume(
+			apiv1.BarmanCredentials{AWS: &apiv1.S3Credentials{}}, directory, []string{"PATH=/bin"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(env).To(ConsistOf("PATH=/bin", "AWS_ACCESS_KEY_ID=id", "AWS_SECRET_ACCESS_KEY=secret"))
+	})
+
+	It("reads the rotated credentials", func() {
+		credentials := apiv1.BarmanCredentials{Azure: &apiv1.AzureCredentials{}}
+		writeCredential("AZURE_STORAGE_CONNECTION_STRING", "first")
+		env, err := envSetCredentialsFromVolume(credentials, directory, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(env).To(ConsistOf("AZURE_STORAGE_CONNECTION_STRING=first"))
+
+		writeCredential("AZURE_STORAGE_CONNECTION_STRING", "second")
+		env, err = envSetCredentialsFromVolume(credentials, directory, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(env).To(ConsistOf("AZURE_STORAGE_CONNECTION_STRING=second"))
+	})
+
+	It("points Google Cloud Storage to the credentials file", func() {
+		writeCredential("GOOGLE_APPLICATION_CREDENTIALS", "{}")
+
+		env, err := envSetCredentialsFromVolume(
+			apiv1.BarmanCredentials{Google: &apiv1.GoogleCredentials{}}, directory, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(env).To(ConsistOf("GOOGLE_APPLICATION_CREDENTIALS=" +
+			path.Join(directory, "GOOGLE_APPLICATION_CREDENTIALS")))
+	})
+
+	It("fails when the volume contains no credentials", func() {
+		_, err := envSetCredentialsFromVolume(
+			apiv1.BarmanCredentials{AWS: &apiv1.S3Credentials{}}, directory, nil)
+		Expect(err).To(HaveOccurred())
+
+		_, err = envSetCredentialsFromVolume(
+			apiv1.BarmanCredentials{Google: &apiv1.GoogleCredentials{}}, directory, nil)
+		Expect(err).To(HaveOccurred())
+
+		_, err = envSetCredentialsFromVolume(apiv1.BarmanCredentials{}, directory, nil)
+		Expect(err).To(HaveOccurred())
+	})
+})
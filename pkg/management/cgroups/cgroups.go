@@ -0,0 +1,206 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package cgroups reads the resource usage of the instance container
+// from the statistics of its control group, supporting both cgroup v1
+// and cgroup v2
+package cgroups
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils"
+)
+
+const (
+	// DefaultRoot is where the control group of the container is mounted
+	DefaultRoot = "/sys/fs/cgroup"
+
+	// cpuSampleWindow is the minimum interval the CPU usage is averaged
+	// on, so that close reads don't report a meaningless value
+	cpuSampleWindow = 10 * time.Second
+)
+
+// Usage is the resource usage of the container
+type Usage struct {
+	// The CPU usage in millicores, averaged on the interval between the
+	// two latest samples. It is nil until the first sample window elapses
+	CPUMillicores *int64
+
+	// The memory working set in bytes, that is the used memory
+	// excluding the inactive page cache, as computed by the kubelet
+	MemoryBytes int64
+}
+
+// Reader reads the resource usage of a control group, keeping track of
+// the CPU time consumed at the latest sample to compute the CPU usage
+type Reader struct {
+	root string
+
+	mu                sync.Mutex
+	lastCPUTime       time.Duration
+	lastSampleTime    time.Time
+	lastCPUMillicores *int64
+}
+
+var defaultReader = NewReader(DefaultRoot)
+
+// NewReader creates a Reader for the control group mounted in root
+func NewReader(root string) *Reader {
+	return &Reader{root: root}
+}
+
+// CurrentUsage reads the resource usage of the container
+func CurrentUsage() (*Usage, error) {
+	return defaultReader.Read()
+}
+
+// Read reads the resource usage of the control group
+func (r *Reader) Read() (*Usage, error) {
+	return r.readAt(time.Now())
+}
+
+func (r *Reader) readAt(now time.Time) (*Usage, error) {
+	isV2, err := fileutils.FileExists(filepath.Join(r.root, "cgroup.controllers"))
+	if err != nil {
+		return nil, err
+	}
+
+	var cpuTime time.Duration
+	var usage Usage
+	if isV2 {
+		cpuTime, usage.MemoryBytes, err = r.readV2()
+	} else {
+		cpuTime, usage.MemoryBytes, err = r.readV1()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	elapsed := now.Sub(r.lastSampleTime)
+	switch {
+	case r.lastSampleTime.IsZero() || cpuTime < r.lastCPUTime:
+		// First sample, or the counters have been reset
+		r.lastCPUMillicores = nil
+	case elapsed < cpuSampleWindow:
+		// Too close to the latest sample, keep the current value
+		usage.CPUMillicores = r.lastCPUMillicores
+		return &usage, nil
+	default:
+		millicores := int64(cpuTime-r.lastCPUTime) * 1000 / int64(elapsed)
+		r.lastCPUMillicores = &millicores
+	}
+	r.lastCPUTime = cpuTime
+	r.lastSampleTime = now
+
+	usage.CPUMillicores = r.lastCPUMillicores
+	return &usage, nil
+}
+
+// readV2 reads the CPU time and the memory working set from cgroup v2
+func (r *Reader) readV2() (cpuTime time.Duration, memory int64, err error) {
+	cpuStat, err := readKeyValueFile(filepath.Join(r.root, "cpu.stat"))
+	if err != nil {
+		return 0, 0, err
+	}
+	memoryStat, err := readKeyValueFile(filepath.Join(r.root, "memory.stat"))
+	if err != nil {
+		return 0, 0, err
+	}
+	memoryCurrent, err := readIntFile(filepath.Join(r.root, "memory.current"))
+	if err != nil {
+		return 0, 0, err
+	}
+
+	cpuTime = time.Duration(cpuStat["usage_usec"]) * time.Microsecond
+	return cpuTime, workingSet(memoryCurrent, memoryStat["inactive_file"]), nil
+}
+
+// readV1 reads the CPU time and the memory working set from cgroup v1
+func (r *Reader) readV1() (cpuTime time.Duration, memory int64, err error) {
+	cpuUsage, err := readIntFile(filepath.Join(r.root, "cpuacct", "cpuacct.usage"))
+	if err != nil {
+		return 0, 0, err
+	}
+	memoryStat, err := readKeyValueFile(filepath.Join(r.root, "memory", "memory.stat"))
+	if err != nil {
+		return 0, 0, err
+	}
+	memoryUsage, err := readIntFile(filepath.Join(r.root, "memory", "memory.usage_in_bytes"))
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return time.Duration(cpuUsage), workingSet(memoryUsage, memoryStat["total_inactive_file"]), nil
+}
+
+// workingSet computes the memory working set, excluding
+// the page cache which can be reclaimed
+func workingSet(usage int64, inactiveFile int64) int64 {
+	if inactiveFile > usage {
+		return 0
+	}
+	return usage - inactiveFile
+}
+
+// readIntFile reads a file containing a single integer
+func readIntFile(fileName string) (int64, error) {
+	content, err := fileutils.ReadFile(fileName)
+	if err != nil {
+		return 0, err
+	}
+
+	value, err := strconv.ParseInt(strings.TrimSpace(string(content)), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("while parsing %v: %w", fileName, err)
+	}
+	return value, nil
+}
+
+// readKeyValueFile reads a file made of "key value" lines,
+// like the statistics files of the control groups
+func readKeyValueFile(fileName string) (map[string]int64, error) {
+	content, err := fileutils.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]int64)
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			continue
+		}
+		value, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("while parsing %v: %w", fileName, err)
+		}
+		result[fields[0]] = value
+	}
+
+	return result, scanner.Err()
+}
@@ -0,0 +1,84 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cgroups
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Control group resource usage", func() {
+	writeFile := func(name, content string) {
+		Expect(os.MkdirAll(filepath.Dir(name), 0o700)).To(Succeed())
+		Expect(os.WriteFile(name, []byte(content), 0o600)).To(Succeed())
+	}
+
+	It("reads the usage from cgroup v2", func() {
+		root := GinkgoT().TempDir()
+		writeFile(filepath.Join(root, "cgroup.controllers"), "cpu memory\n")
+		writeFile(filepath.Join(root, "cpu.stat"), "usage_usec 1000000\nuser_usec 800000\n")
+		writeFile(filepath.Join(root, "memory.current"), "104857600\n")
+		writeFile(filepath.Join(root, "memory.stat"), "anon 1024\ninactive_file 20971520\n")
+
+		reader := NewReader(root)
+		start := time.Now()
+		usage, err := reader.readAt(start)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(usage.CPUMillicores).To(BeNil())
+		Expect(usage.MemoryBytes).To(BeEquivalentTo(83886080))
+
+		// Five seconds of CPU time in twenty seconds
+		writeFile(filepath.Join(root, "cpu.stat"), "usage_usec 6000000\nuser_usec 1200000\n")
+		usage, err = reader.readAt(start.Add(20 * time.Second))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(usage.CPUMillicores).ToNot(BeNil())
+		Expect(*usage.CPUMillicores).To(BeEquivalentTo(250))
+
+		// A read inside the sample window reports the same value
+		writeFile(filepath.Join(root, "cpu.stat"), "usage_usec 7000000\nuser_usec 1200000\n")
+		usage, err = reader.readAt(start.Add(21 * time.Second))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(*usage.CPUMillicores).To(BeEquivalentTo(250))
+	})
+
+	It("reads the usage from cgroup v1", func() {
+		root := GinkgoT().TempDir()
+		writeFile(filepath.Join(root, "cpuacct", "cpuacct.usage"), "3000000000\n")
+		writeFile(filepath.Join(root, "memory", "memory.usage_in_bytes"), "52428800\n")
+		writeFile(filepath.Join(root, "memory", "memory.stat"), "cache 0\ntotal_inactive_file 10485760\n")
+
+		reader := NewReader(root)
+		start := time.Now()
+		_, err := reader.readAt(start)
+		Expect(err).ToNot(HaveOccurred())
+
+		writeFile(filepath.Join(root, "cpuacct", "cpuacct.usage"), "5000000000\n")
+		usage, err := reader.readAt(start.Add(10 * time.Second))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(*usage.CPUMillicores).To(BeEquivalentTo(200))
+		Expect(usage.MemoryBytes).To(BeEquivalentTo(41943040))
+	})
+
+	It("fails when the statistics are not available", func() {
+		_, err := NewReader(GinkgoT().TempDir()).Read()
+		Expect(err).To(HaveOccurred())
+	})
+})
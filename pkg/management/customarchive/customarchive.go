@@ -0,0 +1,156 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package customarchive archives the WAL files with the command
+// configured in the cluster, in place of barman-cloud-wal-archive
+package customarchive
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/execlog"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// shellCommand is the shell running the custom commands
+const shellCommand = "/bin/sh"
+
+// commandName is the name used to log the output of the custom commands
+const commandName = "custom-wal-archive"
+
+// EnvSetCredentials adds to the environment the variables of the custom
+// WAL archive command, reading the values of the referenced secrets
+func EnvSetCredentials(
+	ctx context.Context,
+	c client.Client,
+	namespace string,
+	configuration *apiv1.CustomWalArchiveConfiguration,
+	env []string,
+) ([]string, error) {
+	for _, envVar := range configuration.Env {
+		value := envVar.Value
+		if envVar.ValueFrom != nil {
+			if envVar.ValueFrom.SecretKeyRef == nil {
+				return nil, fmt.Errorf("unsupported source for the environment variable %s", envVar.Name)
+			}
+
+			var err error
+			if value, err = extractValueFromSecret(ctx, c, envVar.ValueFrom.SecretKeyRef, namespace); err != nil {
+				return nil, fmt.Errorf("while getting the environment variable %s: %w", envVar.Name, err)
+			}
+		}
+		env = append(env, fmt.Sprintf("%s=%s", envVar.Name, value))
+	}
+
+	return env, nil
+}
+
+// extractValueFromSecret gets the value of a key of a secret, which may be
+// missing only if the selector is optional
+func extractValueFromSecret(
+	ctx context.Context,
+	c client.Client,
+	selector *corev1.SecretKeySelector,
+	namespace string,
+) (string, error) {
+	var secret corev1.Secret
+	err := c.Get(ctx, client.ObjectKey{Namespace: namespace, Name: selector.Name}, &secret)
+	if err != nil {
+		if selector.Optional != nil && *selector.Optional && client.IgnoreNotFound(err) == nil {
+			return "", nil
+		}
+		return "", fmt.Errorf("while getting secret %s: %w", selector.Name, err)
+	}
+
+	value, ok := secret.Data[selector.Key]
+	if !ok {
+		if selector.Optional != nil && *selector.Optional {
+			return "", nil
+		}
+		return "", fmt.Errorf("missing key %s, inside secret %s", selector.Key, selector.Name)
+	}
+
+	return string(value), nil
+}
+
+// ExpandCommand replaces the placeholders of the custom command as the
+// archive_command of PostgreSQL does: `%p` with the path of the WAL
+// file, `%f` with its name and `%%` with a `%` character. Any other
+// character following a `%` is left untouched
+func ExpandCommand(command string, walPath string) string {
+	var result strings.Builder
+	for idx := 0; idx < len(command); idx++ {
+		if command[idx] != '%' || idx+1 == len(command) {
+			result.WriteByte(command[idx])
+			continue
+		}
+
+		switch command[idx+1] {
+		case 'p':
+			result.WriteString(walPath)
+		case 'f':
+			result.WriteString(filepath.Base(walPath))
+		case '%':
+			result.WriteByte('%')
+		default:
+			result.WriteByte(command[idx])
+			continue
+		}
+		idx++
+	}
+
+	return result.String()
+}
+
+// Archive archives a WAL file running the custom command from the PGDATA
+// directory, reporting an error when the command exits with a non-zero
+// status
+func Archive(
+	ctx context.Context,
+	configuration *apiv1.CustomWalArchiveConfiguration,
+	env []string,
+	pgData string,
+	walName string,
+) error {
+	contextLog := log.FromContext(ctx)
+
+	command := ExpandCommand(configuration.Command, walName)
+	contextLog.Trace("Executing the custom WAL archive command",
+		"walName", walName,
+		"command", command)
+
+	cmd := exec.Command(shellCommand, "-c", command) // #nosec G204
+	cmd.Env = env
+	cmd.Dir = pgData
+
+	if err := execlog.RunStreaming(cmd, commandName); err != nil {
+		contextLog.Error(err, "Error invoking the custom WAL archive command",
+			"walName", walName,
+			"command", command,
+			"exitCode", cmd.ProcessState.ExitCode())
+		return fmt.Errorf("unexpected failure invoking the custom WAL archive command: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,120 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package customarchive
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("custom WAL archive command expansion", func() {
+	It("replaces the placeholders of PostgreSQL", func() {
+		Expect(ExpandCommand("wal-g wal-push %p", "pg_wal/000000010000000000000001")).
+			To(Equal("wal-g wal-push pg_wal/000000010000000000000001"))
+		Expect(ExpandCommand("cp %p /archive/%f", "pg_wal/000000010000000000000001")).
+			To(Equal("cp pg_wal/000000010000000000000001 /archive/000000010000000000000001"))
+	})
+
+	It("handles the escaped and the unknown placeholders", func() {
+		Expect(ExpandCommand("echo 100%% %x %", "pg_wal/000000010000000000000001")).
+			To(Equal("echo 100% %x %"))
+	})
+})
+
+var _ = Describe("custom WAL archive environment", func() {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "walg-creds", Namespace: "default"},
+		Data:       map[string][]byte{"ACCESS_KEY_ID": []byte("id")},
+	}
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme.BuildWithAllKnownScheme()).
+		WithObjects(secret).
+		Build()
+
+	secretVar := func(name, key string) corev1.EnvVar {
+		return corev1.EnvVar{
+			Name: name,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "walg-creds"},
+					Key:                  key,
+				},
+			},
+		}
+	}
+
+	It("adds the values and the content of the secrets", func(ctx context.Context) {
+		configuration := &apiv1.CustomWalArchiveConfiguration{
+			Command: "wal-g wal-push %p",
+			Env: []corev1.EnvVar{
+				{Name: "WALG_S3_PREFIX", Value: "s3://bucket/"},
+				secretVar("AWS_ACCESS_KEY_ID", "ACCESS_KEY_ID"),
+			},
+		}
+		env, err := EnvSetCredentials(ctx, fakeClient, "default", configuration, []string{"PATH=/bin"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(env).To(Equal([]string{"PATH=/bin", "WALG_S3_PREFIX=s3://bucket/", "AWS_ACCESS_KEY_ID=id"}))
+	})
+
+	It("fails when a secret key is missing", func(ctx context.Context) {
+		configuration := &apiv1.CustomWalArchiveConfiguration{
+			Command: "wal-g wal-push %p",
+			Env:     []corev1.EnvVar{secretVar("AWS_SECRET_ACCESS_KEY", "SECRET_ACCESS_KEY")},
+		}
+		_, err := EnvSetCredentials(ctx, fakeClient, "default", configuration, nil)
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("custom WAL archive command execution", func() {
+	var pgData string
+
+	BeforeEach(func() {
+		pgData = GinkgoT().TempDir()
+		Expect(os.Mkdir(filepath.Join(pgData, "pg_wal"), 0o700)).To(Succeed())
+		Expect(os.WriteFile(
+			filepath.Join(pgData, "pg_wal", "000000010000000000000001"), []byte("wal"), 0o600)).To(Succeed())
+	})
+
+	It("runs the command from PGDATA with the given environment", func(ctx context.Context) {
+		archiveDirectory := GinkgoT().TempDir()
+		configuration := &apiv1.CustomWalArchiveConfiguration{
+			Command: `cp %p "$ARCHIVE_DIRECTORY/%f"`,
+		}
+		err := Archive(ctx, configuration, []string{"ARCHIVE_DIRECTORY=" + archiveDirectory},
+			pgData, "pg_wal/000000010000000000000001")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(filepath.Join(archiveDirectory, "000000010000000000000001")).To(BeAnExistingFile())
+	})
+
+	It("fails when the command exits with a non-zero status", func(ctx context.Context) {
+		configuration := &apiv1.CustomWalArchiveConfiguration{Command: "exit 1"}
+		err := Archive(ctx, configuration, nil, pgData, "pg_wal/000000010000000000000001")
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -69,7 +69,7 @@ const (
 
 	pgBouncerIniTemplateString = `
 [databases]
-* = host={{.Pooler.Spec.Cluster.Name}}-{{.Pooler.Spec.Type}}
+* = host={{.ServerHost}}
 
 [pgbouncer]
 pool_mode = {{ .Pooler.Spec.PgBouncer.PoolMode }}
@@ -123,8 +123,9 @@ var (
 )
 
 // BuildConfigurationFiles create the config files containing the pgbouncer configuration and
-// the users file
-func BuildConfigurationFiles(pooler *apiv1.Pooler, secrets *Secrets) (ConfigurationFiles, error) {
+// the users file. The serverHost is the name of the cluster service PgBouncer connects to,
+// and defaults to the `<cluster>-<type>` service when empty
+func BuildConfigurationFiles(pooler *apiv1.Pooler, secrets *Secrets, serverHost string) (ConfigurationFiles, error) {
 	files := make(map[string][]byte)
 	var pgbouncerIni bytes.Buffer
 	var pgbouncerUserList bytes.Buffer
@@ -174,14 +175,20 @@ func BuildConfigurationFiles(pooler *apiv1.Pooler, secrets *Secrets) (Configurat
 		parameters["auth_file"] = authFilePath
 	}
 
+	if serverHost == "" {
+		serverHost = fmt.Sprintf("%s-%s", pooler.Spec.Cluster.Name, pooler.Spec.Type)
+	}
+
 	templateData := struct {
 		Pooler            *apiv1.Pooler
+		ServerHost        string
 		AuthQuery         string
 		AuthQueryUser     string
 		AuthQueryPassword string
 		Parameters        string
 	}{
 		Pooler:            pooler,
+		ServerHost:        serverHost,
 		AuthQuery:         pooler.GetAuthQuery(),
 		AuthQueryUser:     authQueryUser,
 		AuthQueryPassword: authQueryPassword,
@@ -41,7 +41,6 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/execlog"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
-	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 
 	// this is needed to correctly open the sql connection with the pgx driver
 	_ "github.com/jackc/pgx/v5/stdlib"
@@ -98,12 +97,20 @@ func getDataConfiguration(
 		return nil, fmt.Errorf("snappy compression is not supported in Barman %v", capabilities.Version)
 	}
 
+	if configuration.Data.Compression == apiv1.CompressionTypeZstd && !capabilities.HasZstd {
+		return nil, fmt.Errorf("zstd compression is not supported in Barman %v", capabilities.Version)
+	}
+
 	if len(configuration.Data.Compression) != 0 {
 		options = append(
 			options,
 			fmt.Sprintf("--%v", configuration.Data.Compression))
 	}
 
+	if configuration.Data.Compression == apiv1.CompressionTypeZstd {
+		options = append(options, barman.ZstdCompressionOptions(configuration.Data.Zstd, capabilities)...)
+	}
+
 	if len(configuration.Data.Encryption) != 0 {
 		options = append(
 			options,
@@ -147,12 +154,9 @@ func (b *BackupCommand) getBarmanCloudBackupOptions(
 		return nil, err
 	}
 
-	if len(configuration.Tags) > 0 {
-		tags, err := utils.MapToBarmanTagsFormat("--tags", configuration.Tags)
-		if err != nil {
-			return nil, err
-		}
-		options = append(options, tags...)
+	options, err = barman.AppendTagsOptions(options, configuration, false)
+	if err != nil {
+		return nil, err
 	}
 
 	if len(configuration.EndpointURL) > 0 {
@@ -232,6 +236,8 @@ func (b *BackupCommand) ensureBarmanCompatibility() error {
 		return err
 	}
 
+	barmanConfiguration := b.Cluster.Spec.Backup.BarmanObjectStore
+	data := barmanConfiguration.Data
 	switch {
 	case postgresVers.Major == 15 && capabilities.Version.Major < 3:
 		return fmt.Errorf(
@@ -239,6 +245,12 @@ func (b *BackupCommand) ensureBarmanCompatibility() error {
 			postgresVers.Major,
 			capabilities.Version.Major,
 		)
+	case data != nil && data.Compression == apiv1.CompressionTypeZstd && !capabilities.HasZstd:
+		// Fail early, so that the backup is marked as failed
+		// instead of stopping after being started
+		return fmt.Errorf("zstd compression is not supported in Barman %v", capabilities.Version)
+	case len(barmanConfiguration.Tags) > 0 && !capabilities.HasTags:
+		return fmt.Errorf("tags are not supported in Barman %v", capabilities.Version)
 	default:
 		return nil
 	}
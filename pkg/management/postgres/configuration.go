@@ -22,6 +22,7 @@ import (
 	"path"
 	"path/filepath"
 	"sort"
+	"time"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/configfile"
@@ -286,13 +287,77 @@ func RemoveArchiveModeFromPostgresAutoConf(pgData string) (changed bool, err err
 	return fileutils.WriteStringToFile(targetFile, updatedContent)
 }
 
+// ConfigureReadOnlyDefault adds or removes the "default_transaction_read_only"
+// option from "postgresql.auto.conf", depending on the readOnly flag
+func ConfigureReadOnlyDefault(pgData string, readOnly bool) (changed bool, err error) {
+	targetFile := path.Join(pgData, "postgresql.auto.conf")
+
+	options := make(map[string]string)
+	if readOnly {
+		options["default_transaction_read_only"] = "on"
+	}
+
+	changed, err = configfile.UpdatePostgresConfigurationFile(
+		targetFile,
+		options,
+		"default_transaction_read_only",
+	)
+	if err != nil {
+		return false, err
+	}
+
+	if changed {
+		log.Info("Updated the read-only default in postgresql.auto.conf file", "readOnly", readOnly)
+	}
+
+	return changed, nil
+}
+
+// ConfigureMinApplyDelay adds or removes the "recovery_min_apply_delay"
+// option from "postgresql.auto.conf", depending on the passed delay
+func ConfigureMinApplyDelay(pgData string, delay time.Duration) (changed bool, err error) {
+	targetFile := path.Join(pgData, "postgresql.auto.conf")
+
+	options := make(map[string]string)
+	if delay > 0 {
+		options["recovery_min_apply_delay"] = fmt.Sprintf("%vs", int64(delay.Seconds()))
+	}
+
+	changed, err = configfile.UpdatePostgresConfigurationFile(
+		targetFile,
+		options,
+		"recovery_min_apply_delay",
+	)
+	if err != nil {
+		return false, err
+	}
+
+	if changed {
+		log.Info("Updated the apply delay in postgresql.auto.conf file", "delay", delay)
+	}
+
+	return changed, nil
+}
+
 // createPostgresqlConfiguration creates the PostgreSQL configuration to be
 // used for this cluster and return it and its sha256 checksum
 func createPostgresqlConfiguration(cluster *apiv1.Cluster) (string, string, error) {
+	configuration, err := getPostgresqlConfiguration(cluster)
+	if err != nil {
+		return "", "", err
+	}
+
+	conf, sha256 := postgres.CreatePostgresqlConfFile(configuration)
+	return conf, sha256, nil
+}
+
+// getPostgresqlConfiguration gets the parameters the operator sets
+// for this cluster
+func getPostgresqlConfiguration(cluster *apiv1.Cluster) (*postgres.PgConfiguration, error) {
 	// Extract the PostgreSQL major version
 	fromVersion, err := cluster.GetPostgresqlVersion()
 	if err != nil {
-		return "", "", err
+		return nil, err
 	}
 
 	info := postgres.ConfigurationInfo{
@@ -301,13 +366,24 @@ func createPostgresqlConfiguration(cluster *apiv1.Cluster) (string, string, erro
 		UserSettings:                     cluster.Spec.PostgresConfiguration.Parameters,
 		IncludingMandatory:               true,
 		IncludingSharedPreloadLibraries:  true,
-		AdditionalSharedPreloadLibraries: cluster.Spec.PostgresConfiguration.AdditionalLibraries,
+		AdditionalSharedPreloadLibraries: cluster.GetAdditionalSharedPreloadLibraries(),
 		IsReplicaCluster:                 cluster.IsReplica(),
+		MaxSlotWalKeepSize:               cluster.Spec.ReplicationSlots.GetMaxSlotWalKeepSize(),
+		WalKeepSize:                      cluster.GetWalKeepSize(),
+		SynchronousCommit:                string(cluster.Spec.SynchronousCommit),
+		ArchiveLibrary:                   cluster.Spec.PostgresConfiguration.ArchiveLibrary,
+		AutovacuumSettings:               cluster.Spec.PostgresConfiguration.Autovacuum.GetParameters(),
+		CheckpointSettings:               cluster.Spec.PostgresConfiguration.Checkpoint.GetParameters(),
 	}
 
 	// Compute the actual number of sync replicas
 	syncReplicas, electable := cluster.GetSyncReplicasData()
 	info.SyncReplicas = syncReplicas
+	for idx, name := range electable {
+		// The standbys are known to the primary by the application_name
+		// they use in primary_conninfo
+		electable[idx] = cluster.GetReplicationApplicationName(name)
+	}
 	info.SyncReplicasElectable = electable
 
 	// Ensure a consistent ordering to avoid spurious configuration changes
@@ -316,6 +392,5 @@ func createPostgresqlConfiguration(cluster *apiv1.Cluster) (string, string, erro
 	// Set cluster name
 	info.ClusterName = cluster.Name
 
-	conf, sha256 := postgres.CreatePostgresqlConfFile(postgres.CreatePostgresqlConfiguration(info))
-	return conf, sha256, nil
+	return postgres.CreatePostgresqlConfiguration(info), nil
 }
@@ -0,0 +1,118 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/stringset"
+)
+
+// operatorAutoConfParameters are the parameters written by the operator
+// itself in postgresql.auto.conf, which are never considered a drift
+var operatorAutoConfParameters = stringset.From([]string{
+	"archive_mode",
+	"default_transaction_read_only",
+	"primary_conninfo",
+	"primary_slot_name",
+	"recovery_min_apply_delay",
+	"recovery_target_timeline",
+	"restore_command",
+})
+
+// DetectConfigurationDrift gets the sorted list of the parameters managed by
+// the operator which are overridden in postgresql.auto.conf, for example
+// via ALTER SYSTEM
+func (instance *Instance) DetectConfigurationDrift(ctx context.Context, cluster *apiv1.Cluster) ([]string, error) {
+	configuration, err := getPostgresqlConfiguration(cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	superUserDB, err := instance.GetSuperUserDB()
+	if err != nil {
+		return nil, err
+	}
+
+	// pg_file_settings reads the configuration files when queried, so
+	// the changes are detected even if the configuration wasn't reloaded
+	rows, err := superUserDB.QueryContext(ctx,
+		"SELECT name FROM pg_catalog.pg_file_settings WHERE sourcefile = $1",
+		path.Join(instance.PgData, "postgresql.auto.conf"))
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var autoConfParameters []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		autoConfParameters = append(autoConfParameters, name)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return getDriftedParameters(configuration.GetConfigurationParameters(), autoConfParameters), nil
+}
+
+// getDriftedParameters gets the sorted list of the parameters found in
+// postgresql.auto.conf which are managed by the operator
+func getDriftedParameters(managedParameters map[string]string, autoConfParameters []string) []string {
+	drifted := stringset.New()
+	for _, name := range autoConfParameters {
+		name = strings.ToLower(name)
+		if _, isManaged := managedParameters[name]; isManaged && !operatorAutoConfParameters.Has(name) {
+			drifted.Put(name)
+		}
+	}
+
+	result := drifted.ToList()
+	sort.Strings(result)
+	return result
+}
+
+// ResetConfigurationParameters removes the passed parameters from
+// postgresql.auto.conf. The configuration needs to be reloaded for
+// the change to be applied
+func (instance *Instance) ResetConfigurationParameters(ctx context.Context, parameters []string) error {
+	superUserDB, err := instance.GetSuperUserDB()
+	if err != nil {
+		return err
+	}
+
+	for _, name := range parameters {
+		if _, err := superUserDB.ExecContext(ctx,
+			fmt.Sprintf("ALTER SYSTEM RESET %s", pgx.Identifier{name}.Sanitize())); err != nil {
+			return fmt.Errorf("while resetting %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
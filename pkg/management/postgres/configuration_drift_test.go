@@ -0,0 +1,43 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("configuration drift detection", func() {
+	managedParameters := map[string]string{
+		"max_connections":          "100",
+		"shared_buffers":           "128MB",
+		"recovery_target_timeline": "latest",
+	}
+
+	It("reports the managed parameters found in postgresql.auto.conf", func() {
+		Expect(getDriftedParameters(managedParameters, []string{"shared_buffers", "Max_Connections"})).
+			To(Equal([]string{"max_connections", "shared_buffers"}))
+	})
+
+	It("ignores the parameters not managed by the operator", func() {
+		Expect(getDriftedParameters(managedParameters, []string{"work_mem"})).To(BeEmpty())
+	})
+
+	It("ignores the parameters written by the operator itself", func() {
+		Expect(getDriftedParameters(managedParameters, []string{"recovery_target_timeline"})).To(BeEmpty())
+	})
+})
@@ -18,6 +18,9 @@ package postgres
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -98,3 +101,67 @@ var _ = Describe("testing the building of the ldap config string", func() {
 			"ldaptls=1 ldapprefix=\"%s\" ldapsuffix=\"%s\"", ldapServer, ldapPort, ldapScheme, ldapPrefix, ldapSuffix)))
 	})
 })
+
+var _ = Describe("settings of the standbys", func() {
+	var pgData string
+
+	BeforeEach(func() {
+		var err error
+		pgData, err = os.MkdirTemp("", "standby-settings-pgdata-")
+		Expect(err).ToNot(HaveOccurred())
+		DeferCleanup(func() {
+			Expect(os.RemoveAll(pgData)).To(Succeed())
+		})
+
+		Expect(os.WriteFile(
+			filepath.Join(pgData, "postgresql.auto.conf"),
+			[]byte("primary_slot_name = '_cnpg_cluster_example_2'\n"),
+			0o600)).To(Succeed())
+	})
+
+	It("adds and removes the read-only default from postgresql.auto.conf", func() {
+		changed, err := ConfigureReadOnlyDefault(pgData, true)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changed).To(BeTrue())
+
+		content, err := os.ReadFile(filepath.Join(pgData, "postgresql.auto.conf")) // #nosec
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(content)).To(ContainSubstring("default_transaction_read_only = 'on'"))
+		Expect(string(content)).To(ContainSubstring("primary_slot_name"))
+
+		changed, err = ConfigureReadOnlyDefault(pgData, true)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changed).To(BeFalse())
+
+		changed, err = ConfigureReadOnlyDefault(pgData, false)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changed).To(BeTrue())
+
+		content, err = os.ReadFile(filepath.Join(pgData, "postgresql.auto.conf")) // #nosec
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(content)).ToNot(ContainSubstring("default_transaction_read_only"))
+		Expect(string(content)).To(ContainSubstring("primary_slot_name"))
+	})
+	It("adds and removes the apply delay from postgresql.auto.conf", func() {
+		changed, err := ConfigureMinApplyDelay(pgData, time.Hour)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changed).To(BeTrue())
+
+		content, err := os.ReadFile(filepath.Join(pgData, "postgresql.auto.conf")) // #nosec
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(content)).To(ContainSubstring("recovery_min_apply_delay = '3600s'"))
+
+		changed, err = ConfigureMinApplyDelay(pgData, time.Hour)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changed).To(BeFalse())
+
+		changed, err = ConfigureMinApplyDelay(pgData, 0)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(changed).To(BeTrue())
+
+		content, err = os.ReadFile(filepath.Join(pgData, "postgresql.auto.conf")) // #nosec
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(content)).ToNot(ContainSubstring("recovery_min_apply_delay"))
+		Expect(string(content)).To(ContainSubstring("primary_slot_name"))
+	})
+})
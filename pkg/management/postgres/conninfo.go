@@ -18,6 +18,7 @@ package postgres
 
 import (
 	"fmt"
+	"strings"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
@@ -38,3 +39,15 @@ func buildPrimaryConnInfo(primaryHostname, applicationName string) string {
 		"sslmode=verify-ca"
 	return primaryConnInfo
 }
+
+// buildReplicationConnInfo builds the connection string the passed instance
+// uses to stream the changes from primaryHostname, applying the replication
+// connection settings of the cluster
+func buildReplicationConnInfo(cluster *apiv1.Cluster, primaryHostname, instanceName string) string {
+	primaryConnInfo := buildPrimaryConnInfo(primaryHostname, cluster.GetReplicationApplicationName(instanceName))
+	if options := cluster.Spec.ReplicationConnection.GetConnectionOptions(); len(options) > 0 {
+		primaryConnInfo += " " + strings.Join(options, " ")
+	}
+
+	return primaryConnInfo
+}
@@ -0,0 +1,52 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("replication connection string", func() {
+	It("uses the instance name as application_name by default", func() {
+		cluster := &apiv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"}}
+		connInfo := buildReplicationConnInfo(cluster, "cluster-example-rw", "cluster-example-2")
+		Expect(connInfo).To(Equal(buildPrimaryConnInfo("cluster-example-rw", "cluster-example-2")))
+	})
+
+	It("applies the replication connection settings of the cluster", func() {
+		cluster := &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"},
+			Spec: apiv1.ClusterSpec{
+				ReplicationConnection: &apiv1.ReplicationConnectionConfiguration{
+					ApplicationNamePrefix: "reporting-",
+					KeepalivesIdle:        30,
+					KeepalivesCount:       3,
+					TCPUserTimeout:        10000,
+				},
+			},
+		}
+		connInfo := buildReplicationConnInfo(cluster, "cluster-example-rw", "cluster-example-2")
+		Expect(connInfo).To(ContainSubstring("application_name=reporting-cluster-example-2 "))
+		Expect(connInfo).To(HaveSuffix(" keepalives_idle=30 keepalives_count=3 tcp_user_timeout=10000"))
+		Expect(connInfo).ToNot(ContainSubstring("keepalives_interval"))
+	})
+})
@@ -17,18 +17,30 @@ limitations under the License.
 package postgres
 
 import (
-	"fmt"
 	"os/user"
-	"path/filepath"
 
-	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils"
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/constants"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 )
 
 // WritePostgresUserMaps creates a pg_ident.conf file containing only one map called "local" that
 // maps the current user to "postgres" user.
 func WritePostgresUserMaps(pgData string) error {
+	_, err := installPostgresUserMaps(pgData, nil)
+	return err
+}
+
+// RefreshPGIdent generates and writes down the pg_ident.conf file, adding
+// the user name maps from the cluster to the "local" one
+func (instance *Instance) RefreshPGIdent(cluster *apiv1.Cluster) (postgresIdentChanged bool, err error) {
+	return installPostgresUserMaps(instance.PgData, cluster.Spec.PostgresConfiguration.PgIdent)
+}
+
+// installPostgresUserMaps writes down the pg_ident.conf file with the
+// "local" map and the passed ones, returning true if it has been changed
+func installPostgresUserMaps(pgData string, userMaps []string) (bool, error) {
 	var username string
 
 	currentUser, err := user.Current()
@@ -39,11 +51,10 @@ func WritePostgresUserMaps(pgData string) error {
 		username = currentUser.Username
 	}
 
-	_, err = fileutils.WriteStringToFile(filepath.Join(pgData, constants.PostgresqlIdentFile),
-		fmt.Sprintf("local %s postgres\n", username))
+	identContent, err := postgres.CreateIdentRules(userMaps, username)
 	if err != nil {
-		return err
+		return false, err
 	}
 
-	return nil
+	return InstallPgDataFileContent(pgData, identContent, constants.PostgresqlIdentFile)
 }
@@ -34,6 +34,7 @@ import (
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils/compatibility"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/archiver"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/execlog"
@@ -107,6 +108,20 @@ func (info InitInfo) VerifyPGData() error {
 		return fmt.Errorf("PGData directories already exist")
 	}
 
+	// The data directory and the WAL directory are created inside their
+	// volumes, or inside their subPath, which need to be writable by the
+	// postgres user
+	directories := []string{info.PgData}
+	if info.PgWal != "" {
+		directories = append(directories, info.PgWal)
+	}
+	for _, directory := range directories {
+		if parent := path.Dir(directory); !compatibility.IsDirectoryWritable(parent) {
+			return fmt.Errorf("the directory %v, where %v is created, is not writable by the current user: "+
+				"please check the permissions of the volume and of its subPath", parent, directory)
+		}
+	}
+
 	return nil
 }
 
@@ -338,7 +353,7 @@ func (info InitInfo) Bootstrap(ctx context.Context) error {
 		Settings:                         postgres.CnpgConfigurationSettings,
 		MajorVersion:                     postgresVersion,
 		UserSettings:                     cluster.Spec.PostgresConfiguration.Parameters,
-		AdditionalSharedPreloadLibraries: cluster.Spec.PostgresConfiguration.AdditionalLibraries,
+		AdditionalSharedPreloadLibraries: cluster.GetAdditionalSharedPreloadLibraries(),
 		IsReplicaCluster:                 cluster.IsReplica(),
 		IncludingSharedPreloadLibraries:  true,
 		PreserveFixedSettingsFromUser:    true,
@@ -352,7 +367,7 @@ func (info InitInfo) Bootstrap(ctx context.Context) error {
 	}
 
 	if postgresVersion >= 120000 {
-		primaryConnInfo := info.GetPrimaryConnInfo()
+		primaryConnInfo := info.GetReplicationConnInfo(cluster)
 		slotName := cluster.GetSlotNameFromInstanceName(info.PodName)
 		_, err = configurePostgresAutoConfFile(info.PgData, primaryConnInfo, slotName)
 		if err != nil {
@@ -26,6 +26,7 @@ import (
 	"os/exec"
 	"path/filepath"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/blang/semver"
@@ -34,6 +35,7 @@ import (
 	"k8s.io/client-go/util/retry"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/configfile"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/execlog"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
@@ -133,6 +135,18 @@ type Instance struct {
 	// Pool of DB connections pointing to primary instance
 	primaryPool *pool.ConnectionPool
 
+	// The name of the read-write service of the cluster, as set by the
+	// naming scheme of the services. Protected by primaryPoolMutex
+	primaryServiceName string
+	primaryPoolMutex   sync.Mutex
+
+	// Pool of DB connections used by the metrics exporter, authenticated
+	// as the monitoring role when it is enabled. Both fields are
+	// protected by monitoringPoolMutex
+	monitoringPool      *pool.ConnectionPool
+	monitoringPassword  string
+	monitoringPoolMutex sync.Mutex
+
 	// The namespace of the k8s object representing this cluster
 	Namespace string
 
@@ -179,8 +193,20 @@ type Instance struct {
 	// fenced entails mightBeUnavailable ( entails as in logical consequence)
 	fenced atomic.Bool
 
+	// cordoned specifies whether the instance has been found with corrupted
+	// data, and must not receive connections from the services
+	cordoned atomic.Bool
+
 	// slotsReplicatorChan is used to send replication slot configuration to the slot replicator
 	slotsReplicatorChan chan *apiv1.ReplicationSlotsConfiguration
+
+	// configurationDrift contains the parameters managed by the operator
+	// which were found overridden in postgresql.auto.conf
+	configurationDrift atomic.Pointer[[]string]
+
+	// lastResyncMethod is the method used the last time this instance,
+	// as a former primary, was resynchronized with the new primary
+	lastResyncMethod atomic.String
 }
 
 // IsFenced checks whether the instance is marked as fenced
@@ -188,6 +214,11 @@ func (instance *Instance) IsFenced() bool {
 	return instance.fenced.Load()
 }
 
+// IsCordoned checks whether the instance is marked as cordoned
+func (instance *Instance) IsCordoned() bool {
+	return instance.cordoned.Load()
+}
+
 // CanCheckReadiness checks whether the instance should be checked for readiness
 func (instance *Instance) CanCheckReadiness() bool {
 	return instance.canCheckReadiness.Load()
@@ -198,6 +229,33 @@ func (instance *Instance) MightBeUnavailable() bool {
 	return instance.mightBeUnavailable.Load()
 }
 
+// GetConfigurationDrift gets the parameters managed by the operator which
+// were found overridden in postgresql.auto.conf by the latest check
+func (instance *Instance) GetConfigurationDrift() []string {
+	if drift := instance.configurationDrift.Load(); drift != nil {
+		return *drift
+	}
+	return nil
+}
+
+// SetConfigurationDrift records the parameters managed by the operator
+// which are overridden in postgresql.auto.conf
+func (instance *Instance) SetConfigurationDrift(parameters []string) {
+	instance.configurationDrift.Store(&parameters)
+}
+
+// GetLastResyncMethod gets the method used the last time this instance
+// was resynchronized with the new primary, if it ever was
+func (instance *Instance) GetLastResyncMethod() string {
+	return instance.lastResyncMethod.Load()
+}
+
+// SetLastResyncMethod records the method used to resynchronize this
+// instance with the new primary
+func (instance *Instance) SetLastResyncMethod(method string) {
+	instance.lastResyncMethod.Store(method)
+}
+
 // SetFencing marks whether the instance is fenced, if enabling, marks also any down to be tolerated
 func (instance *Instance) SetFencing(enabled bool) {
 	instance.fenced.Store(enabled)
@@ -206,6 +264,11 @@ func (instance *Instance) SetFencing(enabled bool) {
 	}
 }
 
+// SetCordoned marks whether the instance is cordoned
+func (instance *Instance) SetCordoned(enabled bool) {
+	instance.cordoned.Store(enabled)
+}
+
 // SetCanCheckReadiness marks whether the instance should be checked for readiness
 func (instance *Instance) SetCanCheckReadiness(enabled bool) {
 	instance.canCheckReadiness.Store(enabled)
@@ -338,6 +401,12 @@ func (instance *Instance) ShutdownConnections() {
 	if instance.primaryPool != nil {
 		instance.primaryPool.ShutdownConnections()
 	}
+
+	instance.monitoringPoolMutex.Lock()
+	defer instance.monitoringPoolMutex.Unlock()
+	if instance.monitoringPool != nil {
+		instance.monitoringPool.ShutdownConnections()
+	}
 }
 
 // Shutdown shuts down a PostgreSQL instance which was previously started
@@ -527,8 +596,11 @@ func (instance *Instance) ConnectionPool() *pool.ConnectionPool {
 	const applicationName = "cnpg-instance-manager"
 	if instance.pool == nil {
 		socketDir := GetSocketDir()
+		// The instance manager needs to write even when the
+		// cluster has been set to read-only
 		dsn := fmt.Sprintf(
-			"host=%s port=%v user=%v sslmode=disable application_name=%v",
+			"host=%s port=%v user=%v sslmode=disable application_name=%v "+
+				"default_transaction_read_only=off",
 			socketDir,
 			GetServerPort(),
 			"postgres",
@@ -543,13 +615,93 @@ func (instance *Instance) ConnectionPool() *pool.ConnectionPool {
 
 // PrimaryConnectionPool gets or initializes the primary connection pool for this instance
 func (instance *Instance) PrimaryConnectionPool() *pool.ConnectionPool {
+	primaryConnInfo := instance.GetPrimaryConnInfo()
+
+	instance.primaryPoolMutex.Lock()
+	defer instance.primaryPoolMutex.Unlock()
 	if instance.primaryPool == nil {
-		instance.primaryPool = pool.NewConnectionPool(instance.GetPrimaryConnInfo())
+		instance.primaryPool = pool.NewConnectionPool(primaryConnInfo)
 	}
 
 	return instance.primaryPool
 }
 
+// MonitoringConnectionPool gets or initializes the connection pool used
+// by the metrics exporter. When the monitoring role is not enabled, the
+// connection pool of the instance manager is used instead
+func (instance *Instance) MonitoringConnectionPool() *pool.ConnectionPool {
+	const applicationName = "cnpg-metrics-exporter"
+
+	instance.monitoringPoolMutex.Lock()
+	defer instance.monitoringPoolMutex.Unlock()
+	if instance.monitoringPassword == "" {
+		return instance.ConnectionPool()
+	}
+
+	if instance.monitoringPool == nil {
+		// The local peer authentication maps only the postgres user,
+		// so the monitoring role authenticates with its password
+		// through the loopback interface
+		dsn := configfile.CreateConnectionString(map[string]string{
+			"host":             "localhost",
+			"port":             strconv.Itoa(GetServerPort()),
+			"user":             apiv1.MonitoringUserName,
+			"password":         instance.monitoringPassword,
+			"sslmode":          "disable",
+			"application_name": applicationName,
+		})
+		instance.monitoringPool = pool.NewConnectionPool(dsn)
+	}
+
+	return instance.monitoringPool
+}
+
+// SetMonitoringRolePassword sets the password of the monitoring role.
+// When it changes, the connections of the metrics exporter are closed,
+// to be opened again with the new password. An empty password makes the
+// metrics exporter use the connections of the instance manager
+func (instance *Instance) SetMonitoringRolePassword(password string) {
+	instance.monitoringPoolMutex.Lock()
+	defer instance.monitoringPoolMutex.Unlock()
+	if instance.monitoringPassword == password {
+		return
+	}
+
+	instance.monitoringPassword = password
+	if instance.monitoringPool != nil {
+		instance.monitoringPool.ShutdownConnections()
+		instance.monitoringPool = nil
+	}
+}
+
+// GetPrimaryServiceName gets the name of the read-write service
+// of the cluster, used to reach the primary
+func (instance *Instance) GetPrimaryServiceName() string {
+	instance.primaryPoolMutex.Lock()
+	defer instance.primaryPoolMutex.Unlock()
+	if instance.primaryServiceName == "" {
+		return instance.ClusterName + apiv1.ServiceReadWriteSuffix
+	}
+	return instance.primaryServiceName
+}
+
+// SetPrimaryServiceName sets the name of the read-write service of the
+// cluster. When it changes, the connections to the primary are closed,
+// to be opened again through the new service
+func (instance *Instance) SetPrimaryServiceName(serviceName string) {
+	instance.primaryPoolMutex.Lock()
+	defer instance.primaryPoolMutex.Unlock()
+	if instance.primaryServiceName == serviceName {
+		return
+	}
+
+	instance.primaryServiceName = serviceName
+	if instance.primaryPool != nil {
+		instance.primaryPool.ShutdownConnections()
+		instance.primaryPool = nil
+	}
+}
+
 // IsPrimary check if the data directory belongs to a primary server or to a
 // secondary one by looking for a "standby.signal" file inside the data
 // directory. IMPORTANT: this method also works when the instance is not
@@ -578,10 +730,33 @@ func (instance *Instance) IsPrimary() (bool, error) {
 func (instance *Instance) Demote(cluster *apiv1.Cluster) error {
 	log.Info("Demoting instance", "pgpdata", instance.PgData)
 	slotName := cluster.GetSlotNameFromInstanceName(instance.PodName)
-	_, err := UpdateReplicaConfiguration(instance.PgData, instance.GetPrimaryConnInfo(), slotName)
+	_, err := UpdateReplicaConfiguration(instance.PgData, instance.GetReplicationConnInfo(cluster), slotName)
 	return err
 }
 
+// CloneFromPrimary discards the content of PGDATA and recreates it with
+// pg_basebackup from the current primary. This is the fallback used to
+// resynchronize a former primary when pg_rewind is not able to do it.
+// Important: this function must be called only when the instance isn't started
+func (instance *Instance) CloneFromPrimary(cluster *apiv1.Cluster) error {
+	log.Info("Recreating PGDATA from the current primary", "pgdata", instance.PgData)
+
+	var walDir string
+	if cluster.ShouldCreateWalArchiveVolume() {
+		walDir = specs.PgWalVolumePgWalPath
+		if err := fileutils.RemoveDirectoryContent(walDir); err != nil {
+			return fmt.Errorf("while cleaning up the WAL directory: %w", err)
+		}
+	}
+
+	if err := fileutils.RemoveDirectoryContent(instance.PgData); err != nil {
+		return fmt.Errorf("while cleaning up PGDATA: %w", err)
+	}
+
+	primaryConnInfo := instance.GetPrimaryConnInfo() + " dbname=postgres connect_timeout=5"
+	return ClonePgData(primaryConnInfo, instance.PgData, walDir)
+}
+
 // WaitForPrimaryAvailable waits until we can connect to the primary
 func (instance *Instance) WaitForPrimaryAvailable() error {
 	primaryConnInfo := instance.GetPrimaryConnInfo() + " dbname=postgres connect_timeout=5"
@@ -952,5 +1127,11 @@ func (instance *Instance) DropConnections() error {
 
 // GetPrimaryConnInfo returns the DSN to reach the primary
 func (instance *Instance) GetPrimaryConnInfo() string {
-	return buildPrimaryConnInfo(instance.ClusterName+"-rw", instance.PodName)
+	return buildPrimaryConnInfo(instance.GetPrimaryServiceName(), instance.PodName)
+}
+
+// GetReplicationConnInfo returns the DSN this instance uses to stream
+// the changes from the primary, as set in `primary_conninfo`
+func (instance *Instance) GetReplicationConnInfo(cluster *apiv1.Cluster) string {
+	return buildReplicationConnInfo(cluster, cluster.GetServiceReadWriteName(), instance.PodName)
 }
@@ -224,3 +224,28 @@ var _ = Describe("check atomic bool", func() {
 		Expect(unAvailable).To(BeTrue())
 	})
 })
+
+var _ = Describe("monitoring connection pool", func() {
+	It("uses the connection pool of the instance manager without a monitoring password", func() {
+		instance := Instance{}
+		Expect(instance.MonitoringConnectionPool()).To(BeIdenticalTo(instance.ConnectionPool()))
+	})
+
+	It("connects as the monitoring role and reopens the connections when the password changes", func() {
+		instance := Instance{}
+		instance.SetMonitoringRolePassword("first")
+		monitoringPool := instance.MonitoringConnectionPool()
+		Expect(monitoringPool).ToNot(BeIdenticalTo(instance.ConnectionPool()))
+		Expect(monitoringPool.GetDsn("postgres")).To(ContainSubstring("user='monitoring'"))
+		Expect(monitoringPool.GetDsn("postgres")).To(ContainSubstring("password='first'"))
+
+		instance.SetMonitoringRolePassword("first")
+		Expect(instance.MonitoringConnectionPool()).To(BeIdenticalTo(monitoringPool))
+
+		instance.SetMonitoringRolePassword("second")
+		Expect(instance.MonitoringConnectionPool().GetDsn("postgres")).To(ContainSubstring("password='second'"))
+
+		instance.SetMonitoringRolePassword("")
+		Expect(instance.MonitoringConnectionPool()).To(BeIdenticalTo(instance.ConnectionPool()))
+	})
+})
@@ -17,10 +17,20 @@ limitations under the License.
 package postgres
 
 import (
+	"context"
 	"fmt"
+	"io"
+	"os"
 	"os/exec"
+	"regexp"
+	"strconv"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman"
+	barmanCredentials "github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/credentials"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/execlog"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
@@ -29,9 +39,23 @@ import (
 	_ "github.com/jackc/pgx/v5/stdlib"
 )
 
+// cloneProgressRegex matches the progress lines written by pg_basebackup,
+// like "  1024/4096 kB (25%), 0/1 tablespace"
+var cloneProgressRegex = regexp.MustCompile(`(\d+)/(\d+) kB \((\d+)%\)`)
+
 // ClonePgData clones an existing server, given its connection string,
 // to a certain data directory
 func ClonePgData(connectionString, targetPgData, walDir string) error {
+	return ClonePgDataWithProgress(connectionString, targetPgData, walDir, nil)
+}
+
+// ClonePgDataWithProgress clones an existing server like ClonePgData,
+// invoking the given function every time the completion percentage
+// reported by pg_basebackup changes
+func ClonePgDataWithProgress(
+	connectionString, targetPgData, walDir string,
+	onProgress func(percentage int),
+) error {
 	// To initiate streaming replication, the frontend sends the replication parameter
 	// in the startup message. A Boolean value of true (or on, yes, 1) tells the backend
 	// to go into physical replication walsender mode, wherein a small set of replication
@@ -65,8 +89,30 @@ func ClonePgData(connectionString, targetPgData, walDir string) error {
 		options = append(options, "--waldir", walDir)
 	}
 
+	if onProgress == nil {
+		pgBaseBackupCmd := exec.Command(pgBaseBackupName, options...) // #nosec
+		if err = execlog.RunStreaming(pgBaseBackupCmd, pgBaseBackupName); err != nil {
+			return fmt.Errorf("error in pg_basebackup, %w", err)
+		}
+		return nil
+	}
+
+	options = append(options, "--progress")
 	pgBaseBackupCmd := exec.Command(pgBaseBackupName, options...) // #nosec
-	err = execlog.RunStreaming(pgBaseBackupCmd, pgBaseBackupName)
+	logger := log.WithName(pgBaseBackupName)
+	streamingCmd, err := execlog.RunStreamingNoWaitWithWriter(
+		pgBaseBackupCmd,
+		pgBaseBackupName,
+		&execlog.LogWriter{Logger: logger.WithValues(execlog.PipeKey, execlog.StdOut)},
+		&cloneProgressWriter{
+			writer:         &execlog.LogWriter{Logger: logger.WithValues(execlog.PipeKey, execlog.StdErr)},
+			onProgress:     onProgress,
+			lastPercentage: -1,
+		},
+	)
+	if err == nil {
+		err = streamingCmd.Wait()
+	}
 	if err != nil {
 		return fmt.Errorf("error in pg_basebackup, %w", err)
 	}
@@ -74,6 +120,33 @@ func ClonePgData(connectionString, targetPgData, walDir string) error {
 	return nil
 }
 
+// cloneProgressWriter receives the lines written by pg_basebackup on its
+// standard error, detecting the progress ones. As pg_basebackup reports
+// its progress every second, only the lines changing the completion
+// percentage are forwarded to the wrapped writer
+type cloneProgressWriter struct {
+	writer         io.Writer
+	onProgress     func(percentage int)
+	lastPercentage int
+}
+
+// Write implements the io.Writer interface
+func (w *cloneProgressWriter) Write(line []byte) (int, error) {
+	match := cloneProgressRegex.FindSubmatch(line)
+	if match == nil {
+		return w.writer.Write(line)
+	}
+
+	percentage, err := strconv.Atoi(string(match[3]))
+	if err != nil || percentage == w.lastPercentage {
+		return len(line), nil
+	}
+
+	w.lastPercentage = percentage
+	w.onProgress(percentage)
+	return w.writer.Write(line)
+}
+
 // Join creates a new instance joined to an existing PostgreSQL cluster
 func (info InitInfo) Join(cluster *apiv1.Cluster) error {
 	primaryConnInfo := buildPrimaryConnInfo(info.ParentNode, info.PodName) + " dbname=postgres connect_timeout=5"
@@ -84,6 +157,85 @@ func (info InitInfo) Join(cluster *apiv1.Cluster) error {
 	}
 
 	slotName := cluster.GetSlotNameFromInstanceName(info.PodName)
-	_, err = UpdateReplicaConfiguration(info.PgData, info.GetPrimaryConnInfo(), slotName)
+	_, err = UpdateReplicaConfiguration(info.PgData, info.GetReplicationConnInfo(cluster), slotName)
 	return err
 }
+
+// JoinFromObjectStore creates a new instance joined to an existing
+// PostgreSQL cluster, restoring the latest base backup of the cluster
+// from its object store. The instance then catches up with the primary
+// through the WAL archive and the streaming replication.
+// Returns false, without touching the data directory, when the object
+// store contains no base backup
+func (info InitInfo) JoinFromObjectStore(
+	ctx context.Context,
+	typedClient client.Client,
+	cluster *apiv1.Cluster,
+) (bool, error) {
+	if cluster.Spec.Backup == nil || cluster.Spec.Backup.BarmanObjectStore == nil {
+		return false, fmt.Errorf("no object store configured in the backup section")
+	}
+
+	configuration := cluster.Spec.Backup.BarmanObjectStore
+	serverName := configuration.ServerName
+	if serverName == "" {
+		serverName = cluster.Name
+	}
+
+	env, err := barmanCredentials.EnvSetBackupCloudCredentials(
+		ctx,
+		typedClient,
+		cluster.Namespace,
+		configuration,
+		os.Environ())
+	if err != nil {
+		return false, err
+	}
+
+	backupCatalog, err := barman.GetBackupList(configuration, serverName, env)
+	if err != nil {
+		return false, err
+	}
+
+	latestBackup := backupCatalog.LatestBackupInfo()
+	if latestBackup == nil {
+		return false, nil
+	}
+
+	log.Info("Joining the cluster from the object store", "backup", latestBackup)
+
+	backup := &apiv1.Backup{
+		Spec: apiv1.BackupSpec{
+			Cluster: apiv1.LocalObjectReference{
+				Name: cluster.Name,
+			},
+		},
+		Status: apiv1.BackupStatus{
+			BarmanCredentials: configuration.BarmanCredentials,
+			EndpointCA:        configuration.EndpointCA,
+			EndpointURL:       configuration.EndpointURL,
+			DestinationPath:   configuration.DestinationPath,
+			ServerName:        serverName,
+			BackupID:          latestBackup.ID,
+			Phase:             apiv1.BackupPhaseCompleted,
+			StartedAt:         &metav1.Time{Time: latestBackup.BeginTime},
+			StoppedAt:         &metav1.Time{Time: latestBackup.EndTime},
+			BeginWal:          latestBackup.BeginWal,
+			EndWal:            latestBackup.EndWal,
+			BeginLSN:          latestBackup.BeginLSN,
+			EndLSN:            latestBackup.EndLSN,
+		},
+	}
+
+	if err := info.restoreDataDir(backup, env); err != nil {
+		return false, err
+	}
+
+	if _, err := info.restoreCustomWalDir(ctx); err != nil {
+		return false, err
+	}
+
+	slotName := cluster.GetSlotNameFromInstanceName(info.PodName)
+	_, err = UpdateReplicaConfiguration(info.PgData, info.GetReplicationConnInfo(cluster), slotName)
+	return true, err
+}
@@ -0,0 +1,51 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"bytes"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("pg_basebackup progress", func() {
+	It("reports every new completion percentage", func() {
+		var output bytes.Buffer
+		var percentages []int
+		writer := &cloneProgressWriter{
+			writer:         &output,
+			onProgress:     func(percentage int) { percentages = append(percentages, percentage) },
+			lastPercentage: -1,
+		}
+
+		for _, line := range []string{
+			"pg_basebackup: initiating base backup, waiting for checkpoint to complete",
+			"    0/30954 kB (0%), 0/1 tablespace",
+			"    0/30954 kB (0%), 0/1 tablespace",
+			"15477/30954 kB (50%), 0/1 tablespace",
+			"30954/30954 kB (100%), 1/1 tablespace",
+		} {
+			_, err := writer.Write([]byte(line))
+			Expect(err).ToNot(HaveOccurred())
+		}
+
+		Expect(percentages).To(Equal([]int{0, 50, 100}))
+		Expect(output.String()).To(ContainSubstring("initiating base backup"))
+		Expect(bytes.Count(output.Bytes(), []byte("(0%)"))).To(Equal(1))
+	})
+})
@@ -17,6 +17,7 @@ limitations under the License.
 package postgres
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
@@ -30,6 +31,10 @@ import (
 	v1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/executablehash"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils/compatibility"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/capabilities"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/cgroups"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/versions"
@@ -54,6 +59,9 @@ func (instance *Instance) IsServerReady() error {
 	if !instance.CanCheckReadiness() {
 		return fmt.Errorf("instance is not ready yet")
 	}
+	if instance.IsCordoned() {
+		return fmt.Errorf("instance is cordoned, as its data is corrupted")
+	}
 	superUserDB, err := instance.GetSuperUserDB()
 	if err != nil {
 		return err
@@ -62,12 +70,16 @@ func (instance *Instance) IsServerReady() error {
 	return superUserDB.Ping()
 }
 
-// GetStatus Extract the status of this PostgreSQL database
-func (instance *Instance) GetStatus() (result *postgres.PostgresqlStatus, err error) {
+// GetStatus Extract the status of this PostgreSQL database. The queries
+// are bound to the passed context, so a stuck instance doesn't keep the
+// caller waiting after it gave up
+func (instance *Instance) GetStatus(ctx context.Context) (result *postgres.PostgresqlStatus, err error) {
 	result = &postgres.PostgresqlStatus{
 		Pod:                    corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: instance.PodName}},
 		InstanceManagerVersion: versions.Version,
 		MightBeUnavailable:     instance.MightBeUnavailable(),
+		ConfigurationDrift:     instance.GetConfigurationDrift(),
+		LastResyncMethod:       instance.GetLastResyncMethod(),
 	}
 
 	// this deferred function may override the error returned. Take extra care.
@@ -98,7 +110,8 @@ func (instance *Instance) GetStatus() (result *postgres.PostgresqlStatus, err er
 		return result, err
 	}
 
-	row := superUserDB.QueryRow(
+	row := superUserDB.QueryRowContext(
+		ctx,
 		`SELECT
 			(pg_control_system()).system_identifier,
 			-- True if this is a primary instance
@@ -113,19 +126,71 @@ func (instance *Instance) GetStatus() (result *postgres.PostgresqlStatus, err er
 	}
 
 	if result.PendingRestart {
-		err = updateResultForDecrease(instance, superUserDB, result)
+		err = updateResultForDecrease(ctx, instance, superUserDB, result)
 		if err != nil {
 			return result, err
 		}
 	}
 
-	err = instance.fillStatus(result)
+	err = instance.fillStatus(ctx, result)
+	if err != nil {
+		return result, err
+	}
+
+	pgVersion, err := instance.GetPgVersion()
 	if err != nil {
 		return result, err
 	}
+	result.PostgresMajorVersion = int(pgVersion.Major)
+
+	// The checksum failures are only tracked since PostgreSQL 12
+	if pgVersion.Major >= 12 {
+		result.ChecksumFailures, err = instance.GetChecksumFailures(ctx)
+		if err != nil {
+			return result, err
+		}
+	}
+
+	isAcceptingReadWrite := false
+	if result.IsPrimary {
+		isAcceptingReadWrite, err = instance.IsAcceptingReadWriteConnections(ctx)
+		if err != nil {
+			return result, err
+		}
+
+		result.IsReadOnlyDefault, err = instance.IsReadOnlyDefault(ctx)
+		if err != nil {
+			return result, err
+		}
+	}
+	result.IsAcceptingReadWrite = &isAcceptingReadWrite
 
 	result.InstanceArch = runtime.GOARCH
 
+	// The detection is cached, and a failure is not relevant for
+	// the status of PostgreSQL
+	if barmanCapabilities, err := capabilities.CurrentCapabilities(); err == nil && barmanCapabilities.Version != nil {
+		result.BarmanCloudVersion = barmanCapabilities.Version.String()
+	}
+
+	// The resource usage is advisory, and it's not reported
+	// when the statistics of the control group can't be read
+	if usage, err := cgroups.CurrentUsage(); err == nil {
+		result.ResourceUsage = &postgres.ResourceUsage{
+			CPUMillicores: usage.CPUMillicores,
+			MemoryBytes:   usage.MemoryBytes,
+		}
+	}
+
+	// The usage of the volume is advisory as well, and it's not
+	// reported when the statistics of the file system can't be read
+	if usedBytes, availableBytes, err := compatibility.GetVolumeUsage(instance.PgData); err == nil {
+		result.PgDataVolumeUsage = &postgres.VolumeUsage{
+			UsedBytes:      usedBytes,
+			AvailableBytes: availableBytes,
+		}
+	}
+
 	result.ExecutableHash, err = executablehash.Get()
 	if err != nil {
 		return result, err
@@ -140,12 +205,13 @@ func (instance *Instance) GetStatus() (result *postgres.PostgresqlStatus, err er
 // in case of pending restart, by checking whether the restart is due to hot standby
 // sensible parameters being decreased
 func updateResultForDecrease(
+	ctx context.Context,
 	instance *Instance,
 	superUserDB *sql.DB,
 	result *postgres.PostgresqlStatus,
 ) error {
 	// get all the hot standby sensible parameters being decreased
-	decreasedValues, err := instance.GetDecreasedSensibleSettings(superUserDB)
+	decreasedValues, err := instance.GetDecreasedSensibleSettings(ctx, superUserDB)
 	if err != nil {
 		return err
 	}
@@ -184,10 +250,14 @@ func areAllParamsUpdated(decreasedValues map[string]string, pgControldataParams
 // GetDecreasedSensibleSettings tries to get all decreased hot standby sensible parameters from the instance.
 // Returns a map containing all the decreased hot standby sensible parameters with their new value.
 // See https://www.postgresql.org/docs/current/hot-standby.html#HOT-STANDBY-ADMIN for more details.
-func (instance *Instance) GetDecreasedSensibleSettings(superUserDB *sql.DB) (map[string]string, error) {
+func (instance *Instance) GetDecreasedSensibleSettings(
+	ctx context.Context,
+	superUserDB *sql.DB,
+) (map[string]string, error) {
 	// We check whether all parameters with a pending restart from pg_settings
 	// have a decreased value reported as not applied from pg_file_settings.
-	rows, err := superUserDB.Query(
+	rows, err := superUserDB.QueryContext(
+		ctx,
 		`
 SELECT pending_settings.name, coalesce(new_setting,default_setting) as new_setting
 FROM
@@ -246,26 +316,33 @@ WHERE pending_settings.name IN (
 
 // fillStatus extract the current instance information into the PostgresqlStatus
 // structure
-func (instance *Instance) fillStatus(result *postgres.PostgresqlStatus) error {
+func (instance *Instance) fillStatus(ctx context.Context, result *postgres.PostgresqlStatus) error {
 	var err error
 
 	if result.IsPrimary {
-		err = instance.fillStatusFromPrimary(result)
+		err = instance.fillStatusFromPrimary(ctx, result)
 	} else {
-		err = instance.fillStatusFromReplica(result)
+		err = instance.fillStatusFromReplica(ctx, result)
 	}
 	if err != nil {
 		return err
 	}
 
-	if err := instance.fillReplicationSlotsStatus(result); err != nil {
+	if err := instance.fillReplicationSlotsStatus(ctx, result); err != nil {
 		return err
 	}
-	return instance.fillWalStatus(result)
+	if err := instance.fillWalStatus(ctx, result); err != nil {
+		return err
+	}
+
+	// The archive queue is checked on every instance, as the designated
+	// primary of a replica cluster archives the WAL files too
+	result.ReadyWALFiles, _, err = GetWALArchiveCounters()
+	return err
 }
 
 // fillStatusFromPrimary get information for primary servers (including WAL and replication)
-func (instance *Instance) fillStatusFromPrimary(result *postgres.PostgresqlStatus) error {
+func (instance *Instance) fillStatusFromPrimary(ctx context.Context, result *postgres.PostgresqlStatus) error {
 	var err error
 
 	superUserDB, err := instance.GetSuperUserDB()
@@ -273,16 +350,17 @@ func (instance *Instance) fillStatusFromPrimary(result *postgres.PostgresqlStatu
 		return err
 	}
 
-	row := superUserDB.QueryRow(
-		"SELECT " +
-			"COALESCE(last_archived_wal, '') , " +
-			"COALESCE(last_archived_time,'-infinity'), " +
-			"COALESCE(last_failed_wal, ''), " +
-			"COALESCE(last_failed_time, '-infinity'), " +
-			"COALESCE(last_archived_time,'-infinity') > COALESCE(last_failed_time, '-infinity') AS is_archiving," +
-			"pg_walfile_name(pg_current_wal_lsn()) as current_wal, " +
-			"pg_current_wal_lsn(), " +
-			"(SELECT timeline_id FROM pg_control_checkpoint()) as timeline_id " +
+	row := superUserDB.QueryRowContext(
+		ctx,
+		"SELECT "+
+			"COALESCE(last_archived_wal, '') , "+
+			"COALESCE(last_archived_time,'-infinity'), "+
+			"COALESCE(last_failed_wal, ''), "+
+			"COALESCE(last_failed_time, '-infinity'), "+
+			"COALESCE(last_archived_time,'-infinity') > COALESCE(last_failed_time, '-infinity') AS is_archiving,"+
+			"pg_walfile_name(pg_current_wal_lsn()) as current_wal, "+
+			"pg_current_wal_lsn(), "+
+			"(SELECT timeline_id FROM pg_control_checkpoint()) as timeline_id "+
 			"FROM pg_catalog.pg_stat_archiver")
 	err = row.Scan(&result.LastArchivedWAL,
 		&result.LastArchivedWALTime,
@@ -293,11 +371,49 @@ func (instance *Instance) fillStatusFromPrimary(result *postgres.PostgresqlStatu
 		&result.CurrentLsn,
 		&result.TimeLineID,
 	)
+	if err != nil {
+		return err
+	}
 
-	return err
+	return instance.fillInitDBSettings(ctx, result)
+}
+
+// fillInitDBSettings gets the settings chosen by initdb. The locale and the
+// encoding are the ones of `template1`, used by initdb for every database
+func (instance *Instance) fillInitDBSettings(ctx context.Context, result *postgres.PostgresqlStatus) error {
+	superUserDB, err := instance.GetSuperUserDB()
+	if err != nil {
+		return err
+	}
+
+	var settings postgres.InitDBSettings
+	row := superUserDB.QueryRowContext(
+		ctx,
+		`SELECT
+			current_setting('data_checksums') = 'on',
+			pg_encoding_to_char(encoding),
+			datcollate,
+			datctype,
+			-- wal_segment_size is expressed in bytes
+			(SELECT setting::bigint / 1024 / 1024 FROM pg_settings WHERE name = 'wal_segment_size')
+		FROM pg_database
+		WHERE datname = 'template1'`)
+	err = row.Scan(
+		&settings.DataChecksums,
+		&settings.Encoding,
+		&settings.LocaleCollate,
+		&settings.LocaleCType,
+		&settings.WalSegmentSize,
+	)
+	if err != nil {
+		return err
+	}
+
+	result.InitDBSettings = &settings
+	return nil
 }
 
-func (instance *Instance) fillReplicationSlotsStatus(result *postgres.PostgresqlStatus) error {
+func (instance *Instance) fillReplicationSlotsStatus(ctx context.Context, result *postgres.PostgresqlStatus) error {
 	if !result.IsPrimary {
 		return nil
 	}
@@ -312,7 +428,8 @@ func (instance *Instance) fillReplicationSlotsStatus(result *postgres.Postgresql
 		return err
 	}
 
-	rows, err := superUserDB.Query(
+	rows, err := superUserDB.QueryContext(
+		ctx,
 		`SELECT 
     slot_name,
 	coalesce(plugin::text, ''),
@@ -326,6 +443,9 @@ func (instance *Instance) fillReplicationSlotsStatus(result *postgres.Postgresql
 	coalesce(wal_status::text, ''),
 	safe_wal_size
     FROM pg_replication_slots`)
+	if err != nil {
+		return err
+	}
 	defer func() {
 		if closeErr := rows.Close(); closeErr != nil && err == nil {
 			err = closeErr
@@ -361,8 +481,7 @@ func (instance *Instance) fillReplicationSlotsStatus(result *postgres.Postgresql
 }
 
 // fillWalStatus retrieves information about the WAL senders processes
-// and the on-disk WAL archives status
-func (instance *Instance) fillWalStatus(result *postgres.PostgresqlStatus) error {
+func (instance *Instance) fillWalStatus(ctx context.Context, result *postgres.PostgresqlStatus) error {
 	if !result.IsPrimary {
 		return nil
 	}
@@ -373,7 +492,8 @@ func (instance *Instance) fillWalStatus(result *postgres.PostgresqlStatus) error
 	if err != nil {
 		return err
 	}
-	rows, err := superUserDB.Query(
+	rows, err := superUserDB.QueryContext(
+		ctx,
 		`SELECT
 			application_name,
 			coalesce(state, ''),
@@ -388,9 +508,13 @@ func (instance *Instance) fillWalStatus(result *postgres.PostgresqlStatus) error
 			coalesce(sync_priority, 0)
 		FROM pg_catalog.pg_stat_replication
 		WHERE application_name LIKE $1 AND usename = $2`,
-		fmt.Sprintf("%s-%%", instance.ClusterName),
+		// The application_name of the standbys may be prefixed
+		fmt.Sprintf("%%%s-%%", instance.ClusterName),
 		v1.StreamingReplicationUser,
 	)
+	if err != nil {
+		return err
+	}
 	defer func() {
 		if closeErr := rows.Close(); closeErr != nil && err == nil {
 			err = closeErr
@@ -419,20 +543,11 @@ func (instance *Instance) fillWalStatus(result *postgres.PostgresqlStatus) error
 	}
 	result.ReplicationInfo = replicationInfo
 
-	if err = rows.Err(); err != nil {
-		return err
-	}
-
-	result.ReadyWALFiles, _, err = GetWALArchiveCounters()
-	if err != nil {
-		return err
-	}
-
-	return nil
+	return rows.Err()
 }
 
 // fillStatusFromReplica get WAL information for replica servers
-func (instance *Instance) fillStatusFromReplica(result *postgres.PostgresqlStatus) error {
+func (instance *Instance) fillStatusFromReplica(ctx context.Context, result *postgres.PostgresqlStatus) error {
 	superUserDB, err := instance.GetSuperUserDB()
 	if err != nil {
 		return err
@@ -440,13 +555,23 @@ func (instance *Instance) fillStatusFromReplica(result *postgres.PostgresqlStatu
 
 	// pg_last_wal_receive_lsn may be NULL when using non-streaming
 	// replicas
-	row := superUserDB.QueryRow(
-		"SELECT " +
-			"(SELECT timeline_id FROM pg_control_checkpoint()), " +
-			"COALESCE(pg_last_wal_receive_lsn()::varchar, ''), " +
-			"COALESCE(pg_last_wal_replay_lsn()::varchar, ''), " +
-			"pg_is_wal_replay_paused()")
-	if err := row.Scan(&result.TimeLineID, &result.ReceivedLsn, &result.ReplayLsn, &result.ReplayPaused); err != nil {
+	row := superUserDB.QueryRowContext(
+		ctx,
+		"SELECT "+
+			"(SELECT timeline_id FROM pg_control_checkpoint()), "+
+			"COALESCE(pg_last_wal_receive_lsn()::varchar, ''), "+
+			"COALESCE(pg_last_wal_replay_lsn()::varchar, ''), "+
+			"pg_is_wal_replay_paused(), "+
+			"COALESCE(pg_last_xact_replay_timestamp()::text, ''), "+
+			"(SELECT setting::bigint FROM pg_catalog.pg_settings WHERE name = 'recovery_min_apply_delay')")
+	if err := row.Scan(
+		&result.TimeLineID,
+		&result.ReceivedLsn,
+		&result.ReplayLsn,
+		&result.ReplayPaused,
+		&result.LastReplayTimestamp,
+		&result.MinApplyDelay,
+	); err != nil {
 		return err
 	}
 
@@ -485,6 +610,101 @@ func (instance *Instance) IsWALReceiverActive() (bool, error) {
 	return result, nil
 }
 
+// IsAcceptingReadWriteConnections checks whether the instance is accepting
+// read-write connections, by starting a read-write transaction as the
+// superuser. This fails while the instance is in recovery, even when it is
+// being promoted, and is not affected by `default_transaction_read_only`
+func (instance *Instance) IsAcceptingReadWriteConnections(ctx context.Context) (bool, error) {
+	superUserDB, err := instance.GetSuperUserDB()
+	if err != nil {
+		return false, err
+	}
+
+	tx, err := superUserDB.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	var isInRecovery bool
+	if err := tx.QueryRowContext(ctx, "SELECT pg_is_in_recovery()").Scan(&isInRecovery); err != nil {
+		return false, err
+	}
+	if isInRecovery {
+		return false, nil
+	}
+
+	if _, err := tx.ExecContext(ctx, "SET TRANSACTION READ WRITE"); err != nil {
+		log.FromContext(ctx).Info("The instance is not accepting read-write transactions", "err", err)
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// IsReadOnlyDefault checks whether the configuration files set
+// `default_transaction_read_only = on`. The value of the setting can't
+// be read from the current session, as the connections of the instance
+// manager override it
+func (instance *Instance) IsReadOnlyDefault(ctx context.Context) (bool, error) {
+	superUserDB, err := instance.GetSuperUserDB()
+	if err != nil {
+		return false, err
+	}
+
+	var readOnly bool
+	row := superUserDB.QueryRowContext(ctx,
+		"SELECT COALESCE(bool_or(setting::boolean), false) FROM pg_catalog.pg_file_settings "+
+			"WHERE name = 'default_transaction_read_only' AND applied")
+	if err := row.Scan(&readOnly); err != nil {
+		return false, err
+	}
+
+	return readOnly, nil
+}
+
+// GetChecksumFailures gets the number of data page checksum failures
+// detected in every database of the instance
+func (instance *Instance) GetChecksumFailures(ctx context.Context) (int64, error) {
+	superUserDB, err := instance.GetSuperUserDB()
+	if err != nil {
+		return 0, err
+	}
+
+	var checksumFailures int64
+	row := superUserDB.QueryRowContext(ctx,
+		"SELECT COALESCE(sum(checksum_failures), 0) FROM pg_catalog.pg_stat_database")
+	if err := row.Scan(&checksumFailures); err != nil {
+		return 0, err
+	}
+
+	return checksumFailures, nil
+}
+
+// GetWALPosition gets the current WAL position of the instance and the
+// timeline of its latest checkpoint. On a standby, the WAL position is the
+// last replayed LSN
+func (instance *Instance) GetWALPosition(ctx context.Context) (lsn postgres.LSN, timelineID int, err error) {
+	superUserDB, err := instance.GetSuperUserDB()
+	if err != nil {
+		return "", 0, err
+	}
+
+	row := superUserDB.QueryRowContext(
+		ctx,
+		"SELECT "+
+			"COALESCE(CASE WHEN pg_is_in_recovery() THEN pg_last_wal_replay_lsn() "+
+			"ELSE pg_current_wal_lsn() END::text, ''), "+
+			"timeline_id FROM pg_control_checkpoint()")
+	if err := row.Scan(&lsn, &timelineID); err != nil {
+		return "", 0, err
+	}
+
+	return lsn, timelineID, nil
+}
+
 // PgStatWal is a representation of the pg_stat_wal table
 type PgStatWal struct {
 	WalRecords     int64
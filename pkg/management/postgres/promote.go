@@ -33,6 +33,17 @@ func (instance *Instance) PromoteAndWait() error {
 
 	instance.LogPgControldata("promote")
 
+	// The read-only default of the standbys must not survive the promotion
+	readOnlyDefaultRemoved, err := ConfigureReadOnlyDefault(instance.PgData, false)
+	if err != nil {
+		return fmt.Errorf("while removing the read-only default: %w", err)
+	}
+	if readOnlyDefaultRemoved {
+		if err := instance.Reload(); err != nil {
+			return fmt.Errorf("while removing the read-only default: %w", err)
+		}
+	}
+
 	options := []string{
 		"-D",
 		instance.PgData,
@@ -44,7 +55,7 @@ func (instance *Instance) PromoteAndWait() error {
 	log.Info("Promoting instance", "pgctl_options", options)
 
 	pgCtlCmd := exec.Command(pgCtlName, options...) // #nosec
-	err := execlog.RunStreaming(pgCtlCmd, pgCtlName)
+	err = execlog.RunStreaming(pgCtlCmd, pgCtlName)
 	if err != nil {
 		return fmt.Errorf("error promoting the PostgreSQL instance: %w", err)
 	}
@@ -26,6 +26,7 @@ import (
 	"os/exec"
 	"path"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
@@ -49,7 +50,9 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/constants"
 	postgresutils "github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/utils"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/walg"
 	postgresSpec "github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 )
 
 var (
@@ -65,7 +68,12 @@ var (
 		Steps: math.MaxInt32,
 	}
 
+	// recoveryProgressUpdateInterval is the minimum interval between two
+	// updates of the recovery progress in the status of the cluster
+	recoveryProgressUpdateInterval = 30 * time.Second
+
 	enforcedParametersRegex          = regexp.MustCompile(`(?P<PARAM>[a-z_]+) setting:\s+(?P<VALUE>[a-z0-9]+)`)
+	walSegmentSizeRegex              = regexp.MustCompile(`^Bytes per WAL segment:\s+(?P<VALUE>[0-9]+)`)
 	pgControldataSettingsToParamsMap = map[string]string{
 		"max_connections":      "max_connections",
 		"max_wal_senders":      "max_wal_senders",
@@ -99,13 +107,28 @@ func (info InitInfo) Restore(ctx context.Context) error {
 		return err
 	}
 
-	backup, env, err := info.loadBackup(ctx, typedClient, cluster)
-	if err != nil {
-		return err
-	}
+	var env []string
+	var restoreCommand string
+	if walGConfiguration := getWalGRecoverySource(cluster); walGConfiguration != nil {
+		env, err = info.restoreDataDirFromWalG(ctx, typedClient, cluster, walGConfiguration)
+		if err != nil {
+			return err
+		}
+		restoreCommand = walg.RestoreCommand()
+	} else {
+		var backup *apiv1.Backup
+		backup, env, err = info.loadBackup(ctx, typedClient, cluster)
+		if err != nil {
+			return err
+		}
 
-	if err := info.restoreDataDir(backup, env); err != nil {
-		return err
+		if err := info.restoreDataDir(backup, env); err != nil {
+			return err
+		}
+
+		if restoreCommand, err = barmanRestoreCommand(backup); err != nil {
+			return err
+		}
 	}
 
 	if _, err := info.restoreCustomWalDir(ctx); err != nil {
@@ -137,11 +160,35 @@ func (info InitInfo) Restore(ctx context.Context) error {
 		return err
 	}
 
-	if err := info.writeRestoreWalConfig(backup, cluster); err != nil {
+	if err := info.writeRestoreWalConfig(restoreCommand, cluster); err != nil {
 		return err
 	}
 
-	return info.ConfigureInstanceAfterRestore(cluster, env)
+	reportProgress := func(progress *apiv1.RecoveryProgress) {
+		if progress != nil {
+			progress.Target = cluster.Spec.Bootstrap.Recovery.RecoveryTarget.Describe()
+			progress.LastUpdateTime = utils.GetCurrentTimestamp()
+		}
+		updateRecoveryProgress(ctx, typedClient, cluster, progress)
+	}
+
+	return info.ConfigureInstanceAfterRestore(cluster, env, reportProgress)
+}
+
+// updateRecoveryProgress stores the progress of the WAL replay in the
+// status of the cluster. Failing to update the status doesn't stop
+// the recovery, so errors are only logged
+func updateRecoveryProgress(
+	ctx context.Context,
+	cli client.Client,
+	cluster *apiv1.Cluster,
+	progress *apiv1.RecoveryProgress,
+) {
+	existingCluster := cluster.DeepCopy()
+	cluster.Status.RecoveryProgress = progress
+	if err := cli.Status().Patch(ctx, cluster, client.MergeFrom(existingCluster)); err != nil {
+		log.Error(err, "Error while updating the recovery progress")
+	}
 }
 
 // restoreCustomWalDir moves the current pg_wal data to the specified custom wal dir and applies the symlink
@@ -212,6 +259,52 @@ func (info InitInfo) restoreDataDir(backup *apiv1.Backup, env []string) error {
 	return nil
 }
 
+// getWalGRecoverySource gets the wal-g configuration of the external
+// cluster the recovery starts from, if its base backups and WAL files
+// have been archived by wal-g
+func getWalGRecoverySource(cluster *apiv1.Cluster) *apiv1.WalGConfiguration {
+	if cluster.Spec.Bootstrap.Recovery.Backup != nil {
+		return nil
+	}
+
+	server, found := cluster.ExternalCluster(cluster.Spec.Bootstrap.Recovery.Source)
+	if !found {
+		return nil
+	}
+
+	return server.WalG
+}
+
+// restoreDataDirFromWalG restores PGDATA from a base backup taken by
+// wal-g, returning the environment needed to restore the WAL files
+func (info InitInfo) restoreDataDirFromWalG(
+	ctx context.Context,
+	typedClient client.Client,
+	cluster *apiv1.Cluster,
+	configuration *apiv1.WalGConfiguration,
+) ([]string, error) {
+	log.Info("Recovering from external cluster with wal-g",
+		"sourceName", cluster.Spec.Bootstrap.Recovery.Source)
+
+	env, err := walg.EnvSetCredentials(ctx, typedClient, cluster.Namespace, configuration, os.Environ())
+	if err != nil {
+		return nil, err
+	}
+
+	var backupName string
+	if cluster.Spec.Bootstrap.Recovery.RecoveryTarget != nil {
+		backupName = cluster.Spec.Bootstrap.Recovery.RecoveryTarget.BackupID
+	}
+
+	if err := walg.BackupFetch(ctx, env, info.PgData, backupName); err != nil {
+		log.Error(err, "Can't restore backup")
+		return nil, err
+	}
+
+	log.Info("Restore completed")
+	return env, nil
+}
+
 // loadCluster loads the cluster definition from the API server
 func (info InitInfo) loadCluster(ctx context.Context, typedClient client.Client) (*apiv1.Cluster, error) {
 	var cluster apiv1.Cluster
@@ -353,17 +446,9 @@ func (info InitInfo) loadBackupFromReference(
 	return &backup, env, nil
 }
 
-// writeRestoreWalConfig writes a `custom.conf` allowing PostgreSQL
-// to complete the WAL recovery from the object storage and then start
-// as a new primary
-func (info InitInfo) writeRestoreWalConfig(backup *apiv1.Backup, cluster *apiv1.Cluster) error {
-	// Ensure restore_command is used to correctly recover WALs
-	// from the object storage
-	major, err := postgresutils.GetMajorVersion(info.PgData)
-	if err != nil {
-		return fmt.Errorf("cannot detect major version: %w", err)
-	}
-
+// barmanRestoreCommand gets the restore_command making PostgreSQL
+// restore the WAL files from the object storage of the backup
+func barmanRestoreCommand(backup *apiv1.Backup) (string, error) {
 	const barmanCloudWalRestoreName = "barman-cloud-wal-restore"
 
 	cmd := []string{barmanCloudWalRestoreName}
@@ -373,25 +458,43 @@ func (info InitInfo) writeRestoreWalConfig(backup *apiv1.Backup, cluster *apiv1.
 	cmd = append(cmd, backup.Status.DestinationPath)
 	cmd = append(cmd, backup.Status.ServerName)
 
-	cmd, err = barman.AppendCloudProviderOptionsFromBackup(cmd, backup)
+	cmd, err := barman.AppendCloudProviderOptionsFromBackup(cmd, backup)
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	cmd = append(cmd, "%f", "%p")
+	return strings.Join(cmd, " "), nil
+}
+
+// writeRestoreWalConfig writes a `custom.conf` allowing PostgreSQL
+// to complete the WAL recovery from the object storage, through the
+// passed restore_command, and then start as a new primary
+func (info InitInfo) writeRestoreWalConfig(restoreCommand string, cluster *apiv1.Cluster) error {
+	// Ensure restore_command is used to correctly recover WALs
+	// from the object storage
+	major, err := postgresutils.GetMajorVersion(info.PgData)
+	if err != nil {
+		return fmt.Errorf("cannot detect major version: %w", err)
+	}
 
 	recoveryFileContents := fmt.Sprintf(
 		"recovery_target_action = promote\n"+
 			"restore_command = '%s'\n"+
 			"%s",
-		strings.Join(cmd, " "),
+		restoreCommand,
 		cluster.Spec.Bootstrap.Recovery.RecoveryTarget.BuildPostgresOptions())
 
 	log.Info("Generated recovery configuration", "configuration", recoveryFileContents)
 	// Disable archiving
+	disableArchiving := "archive_command = 'cd .'\n"
+	if version, err := cluster.GetPostgresqlVersion(); err == nil && postgresSpec.IsArchiveLibrarySupported(version) {
+		// The archive module would be used instead of the archive_command
+		disableArchiving += "archive_library = ''\n"
+	}
 	err = fileutils.AppendStringToFile(
 		path.Join(info.PgData, constants.PostgresqlCustomConfigurationFile),
-		"archive_command = 'cd .'\n")
+		disableArchiving)
 	if err != nil {
 		return fmt.Errorf("cannot write recovery config: %w", err)
 	}
@@ -445,9 +548,8 @@ func (info InitInfo) writeRestoreWalConfig(backup *apiv1.Backup, cluster *apiv1.
 		0o600)
 }
 
-// GetEnforcedParametersThroughPgControldata will parse the output of pg_controldata in order to get
-// the values of all the hot standby sensible parameters
-func GetEnforcedParametersThroughPgControldata(pgData string) (map[string]string, error) {
+// runPgControldata gets the output of pg_controldata for the passed data directory
+func runPgControldata(pgData string) (string, error) {
 	var stdoutBuffer bytes.Buffer
 	var stderrBuffer bytes.Buffer
 	pgControlDataCmd := exec.Command(pgControlDataName,
@@ -461,13 +563,23 @@ func GetEnforcedParametersThroughPgControldata(pgData string) (map[string]string
 		log.Error(err, "while reading pg_controldata",
 			"stderr", stderrBuffer.String(),
 			"stdout", stdoutBuffer.String())
-		return nil, err
+		return "", err
 	}
 
 	log.Debug("pg_controldata stdout", "stdout", stdoutBuffer.String())
+	return stdoutBuffer.String(), nil
+}
+
+// GetEnforcedParametersThroughPgControldata will parse the output of pg_controldata in order to get
+// the values of all the hot standby sensible parameters
+func GetEnforcedParametersThroughPgControldata(pgData string) (map[string]string, error) {
+	output, err := runPgControldata(pgData)
+	if err != nil {
+		return nil, err
+	}
 
 	enforcedParams := map[string]string{}
-	for _, line := range strings.Split(stdoutBuffer.String(), "\n") {
+	for _, line := range strings.Split(output, "\n") {
 		matches := enforcedParametersRegex.FindStringSubmatch(line)
 		if len(matches) < 3 {
 			continue
@@ -479,6 +591,31 @@ func GetEnforcedParametersThroughPgControldata(pgData string) (map[string]string
 	return enforcedParams, nil
 }
 
+// GetWALSegmentSizeThroughPgControldata gets the size of the WAL segments, in
+// bytes, of the passed data directory, which is chosen when running initdb
+func GetWALSegmentSizeThroughPgControldata(pgData string) (int64, error) {
+	output, err := runPgControldata(pgData)
+	if err != nil {
+		return 0, err
+	}
+
+	return parseWALSegmentSize(output)
+}
+
+// parseWALSegmentSize extracts the size of the WAL segments
+// from the output of pg_controldata
+func parseWALSegmentSize(pgControldataOutput string) (int64, error) {
+	for _, line := range strings.Split(pgControldataOutput, "\n") {
+		matches := walSegmentSizeRegex.FindStringSubmatch(line)
+		if len(matches) < 2 {
+			continue
+		}
+		return strconv.ParseInt(matches[1], 10, 64)
+	}
+
+	return 0, fmt.Errorf("WAL segment size not found in pg_controldata output")
+}
+
 // WriteInitialPostgresqlConf resets the postgresql.conf that there is in the instance using
 // a new bootstrapped instance as reference
 func (info InitInfo) WriteInitialPostgresqlConf(cluster *apiv1.Cluster) error {
@@ -572,8 +709,15 @@ func (info InitInfo) WriteRestoreHbaConf() error {
 // ConfigureInstanceAfterRestore changes the superuser password
 // of the instance to be coherent with the one specified in the
 // cluster. This function also ensures that we can really connect
-// to this cluster using the password in the secrets
-func (info InitInfo) ConfigureInstanceAfterRestore(cluster *apiv1.Cluster, env []string) error {
+// to this cluster using the password in the secrets.
+// When reportProgress is not nil, it is periodically invoked with
+// the progress of the WAL replay, and with nil once the recovery
+// is finished
+func (info InitInfo) ConfigureInstanceAfterRestore(
+	cluster *apiv1.Cluster,
+	env []string,
+	reportProgress func(*apiv1.RecoveryProgress),
+) error {
 	instance := info.GetInstance()
 	instance.Env = env
 
@@ -591,18 +735,22 @@ func (info InitInfo) ConfigureInstanceAfterRestore(cluster *apiv1.Cluster, env [
 		}
 
 		// Wait until we exit from recovery mode
-		err = waitUntilRecoveryFinishes(db)
+		err = waitUntilRecoveryFinishes(db, reportProgress)
 		if err != nil {
 			return fmt.Errorf("while waiting for PostgreSQL to stop recovery mode: %w", err)
 		}
 
+		if reportProgress != nil {
+			reportProgress(nil)
+		}
+
 		return nil
 	}); err != nil {
 		return err
 	}
 
 	if majorVersion >= 12 {
-		primaryConnInfo := info.GetPrimaryConnInfo()
+		primaryConnInfo := info.GetReplicationConnInfo(cluster)
 		slotName := cluster.GetSlotNameFromInstanceName(info.PodName)
 		_, err = configurePostgresAutoConfFile(info.PgData, primaryConnInfo, slotName)
 		if err != nil {
@@ -631,6 +779,12 @@ func (info InitInfo) GetPrimaryConnInfo() string {
 	return buildPrimaryConnInfo(info.ClusterName+"-rw", info.PodName)
 }
 
+// GetReplicationConnInfo returns the DSN the new instance uses to stream
+// the changes from the primary, as set in `primary_conninfo`
+func (info InitInfo) GetReplicationConnInfo(cluster *apiv1.Cluster) string {
+	return buildReplicationConnInfo(cluster, cluster.GetServiceReadWriteName(), info.PodName)
+}
+
 func (info *InitInfo) checkBackupDestination(
 	ctx context.Context,
 	client client.Client,
@@ -676,12 +830,14 @@ func (info *InitInfo) checkBackupDestination(
 
 // waitUntilRecoveryFinishes periodically checks the underlying
 // PostgreSQL connection and returns only when the recovery
-// mode is finished
-func waitUntilRecoveryFinishes(db *sql.DB) error {
+// mode is finished. The progress of the WAL replay is passed
+// to reportProgress, when not nil
+func waitUntilRecoveryFinishes(db *sql.DB, reportProgress func(*apiv1.RecoveryProgress)) error {
 	errorIsRetriable := func(err error) bool {
 		return err == ErrInstanceInRecovery
 	}
 
+	var lastReportTime time.Time
 	return retry.OnError(RetryUntilRecoveryDone, errorIsRetriable, func() error {
 		row := db.QueryRow("SELECT pg_is_in_recovery()")
 
@@ -693,10 +849,55 @@ func waitUntilRecoveryFinishes(db *sql.DB) error {
 		log.Info("Checking if the server is still in recovery",
 			"recovery", status)
 
-		if status {
-			return ErrInstanceInRecovery
+		if !status {
+			return nil
 		}
 
-		return nil
+		if reportProgress != nil && time.Since(lastReportTime) >= recoveryProgressUpdateInterval {
+			progress, err := getRecoveryProgress(db)
+			if err != nil {
+				log.Warning("Cannot read the recovery progress", "err", err)
+			} else {
+				log.Info("Recovery in progress",
+					"currentWAL", progress.CurrentWAL,
+					"replayedLSN", progress.ReplayedLSN)
+				reportProgress(progress)
+				lastReportTime = time.Now()
+			}
+		}
+
+		return ErrInstanceInRecovery
 	})
 }
+
+// getRecoveryProgress reads how far the WAL replay has gone
+// from an instance in recovery mode
+func getRecoveryProgress(db *sql.DB) (*apiv1.RecoveryProgress, error) {
+	row := db.QueryRow(
+		`SELECT COALESCE(pg_last_wal_replay_lsn()::text, ''),
+			(SELECT timeline_id FROM pg_control_checkpoint()),
+			(SELECT setting::bigint FROM pg_settings WHERE name = 'wal_segment_size'),
+			COALESCE(to_char(pg_last_xact_replay_timestamp() AT TIME ZONE 'UTC',
+				'YYYY-MM-DD"T"HH24:MI:SS"Z"'), '')`)
+
+	var replayedLSN, replayedTransactionTime string
+	var timeline int32
+	var walSegmentSize int64
+	if err := row.Scan(&replayedLSN, &timeline, &walSegmentSize, &replayedTransactionTime); err != nil {
+		return nil, fmt.Errorf("error while reading the recovery progress: %w", err)
+	}
+
+	progress := &apiv1.RecoveryProgress{
+		ReplayedLSN:             replayedLSN,
+		ReplayedTransactionTime: replayedTransactionTime,
+	}
+	if replayedLSN != "" {
+		segment, err := postgresSpec.SegmentFromLSN(postgresSpec.LSN(replayedLSN), timeline, walSegmentSize)
+		if err != nil {
+			return nil, err
+		}
+		progress.CurrentWAL = segment.Name()
+	}
+
+	return progress, nil
+}
@@ -148,3 +148,18 @@ var _ = Describe("testing restore InitInfo methods", func() {
 		Expect(chg).To(BeFalse())
 	})
 })
+
+var _ = Describe("parsing the WAL segment size from pg_controldata", func() {
+	It("finds the WAL segment size", func() {
+		output := "Maximum data alignment:               8\n" +
+			"Database block size:                  8192\n" +
+			"Bytes per WAL segment:                67108864\n" +
+			"Maximum length of identifiers:        64\n"
+		Expect(parseWALSegmentSize(output)).To(BeEquivalentTo(64 * 1024 * 1024))
+	})
+
+	It("fails when the WAL segment size is missing", func() {
+		_, err := parseWALSegmentSize("Database block size:                  8192\n")
+		Expect(err).To(HaveOccurred())
+	})
+})
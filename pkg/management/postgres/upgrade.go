@@ -0,0 +1,335 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/execlog"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	postgresutils "github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/utils"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+)
+
+const (
+	pgUpgradeName = "pg_upgrade"
+	pgConfigName  = "pg_config"
+
+	// OldBinariesDirectory is the directory where the binaries of the
+	// previous PostgreSQL major version are copied before a major upgrade
+	OldBinariesDirectory = postgres.ScratchDataDirectory + "/old"
+
+	// oldBinDirFile is the file, inside OldBinariesDirectory, containing the
+	// original location of the binaries of the previous major version
+	oldBinDirFile = "bindir"
+
+	// oldLibDirFile is the file, inside OldBinariesDirectory, containing the
+	// original location of the shared libraries of the previous major version
+	oldLibDirFile = "libdir"
+
+	// pgUpgradeServerOptions are the options passed to both the old and the
+	// new server started by pg_upgrade. The certificates and the directories
+	// referenced by the configuration of the instance are not available
+	// inside the job, and we don't want to archive the WALs generated
+	// during the upgrade
+	pgUpgradeServerOptions = "-c ssl=off -c archive_mode=off -c logging_collector=off"
+)
+
+// CopyBinariesForMajorUpgrade copies the binaries, the shared libraries,
+// the modules and the shared files of the PostgreSQL installation in the
+// current image into the destination directory, preserving their paths.
+// This allows the PostgreSQL binaries, which are relocatable, to be run
+// from a different image during the major upgrade
+func CopyBinariesForMajorUpgrade(destination string) error {
+	directories, err := getInstallationDirectories("--bindir", "--libdir", "--pkglibdir", "--sharedir")
+	if err != nil {
+		return err
+	}
+
+	if err := fileutils.EnsureDirectoryExists(destination); err != nil {
+		return err
+	}
+
+	for _, directory := range directories {
+		log.Info("Copying the PostgreSQL installation directory",
+			"directory", directory,
+			"destination", destination)
+		copyCmd := exec.Command("cp", "-a", "--parents", directory, destination) // #nosec
+		if err := execlog.RunBuffering(copyCmd, "cp"); err != nil {
+			return fmt.Errorf("while copying %s: %w", directory, err)
+		}
+	}
+
+	if _, err := fileutils.WriteStringToFile(path.Join(destination, oldBinDirFile), directories[0]); err != nil {
+		return err
+	}
+
+	_, err = fileutils.WriteStringToFile(path.Join(destination, oldLibDirFile), directories[1])
+	return err
+}
+
+// getInstallationDirectories gets the directories of the PostgreSQL
+// installation in the current image, in the order of the passed pg_config
+// options
+func getInstallationDirectories(options ...string) ([]string, error) {
+	// We ask pg_config for the directories at once, so that we
+	// get them in the same order we have requested them
+	pgConfigCmd := exec.Command(pgConfigName, options...) // #nosec
+	output, err := pgConfigCmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("while reading the PostgreSQL installation directories: %w", err)
+	}
+
+	directories := strings.Fields(string(output))
+	if len(directories) != len(options) {
+		return nil, fmt.Errorf("unexpected output from %s: %s", pgConfigName, output)
+	}
+
+	return directories, nil
+}
+
+// MajorUpgrade upgrades the data directory to the PostgreSQL major version
+// of the current image via pg_upgrade, using the binaries of the previous
+// major version that have been copied into the source directory.
+// The original data directory is only removed when the upgrade succeeded
+func (info InitInfo) MajorUpgrade(ctx context.Context, source string) error {
+	typedClient, err := management.NewControllerRuntimeClient()
+	if err != nil {
+		return err
+	}
+
+	cluster, err := info.loadCluster(ctx, typedClient)
+	if err != nil {
+		return err
+	}
+
+	oldBinDir, err := getOldDirectory(source, oldBinDirFile)
+	if err != nil {
+		return err
+	}
+
+	oldLibDir, err := getOldDirectory(source, oldLibDirFile)
+	if err != nil {
+		return err
+	}
+
+	newLibDir, err := getInstallationDirectories("--libdir")
+	if err != nil {
+		return err
+	}
+
+	pgUpgradePath, err := exec.LookPath(pgUpgradeName)
+	if err != nil {
+		return fmt.Errorf("while looking for %s: %w", pgUpgradeName, err)
+	}
+
+	if err := info.restoreInterruptedMajorUpgrade(); err != nil {
+		return err
+	}
+
+	targetMajorVersion, err := cluster.GetPostgresqlMajorVersion()
+	if err != nil {
+		return err
+	}
+
+	currentMajorVersion, err := postgresutils.GetMajorVersion(info.PgData)
+	if err != nil {
+		return fmt.Errorf("while reading the major version of the data directory: %w", err)
+	}
+
+	if currentMajorVersion == targetMajorVersion {
+		// A previous attempt has been interrupted after having
+		// replaced the data directory with the upgraded one
+		log.Info("Data directory already upgraded, completing the major upgrade",
+			"pgdata", info.PgData,
+			"majorVersion", currentMajorVersion)
+		return info.completeMajorUpgrade(ctx)
+	}
+
+	newInfo := InitInfo{
+		PgData:        info.PgData + "-new",
+		InitDBOptions: getInitDBOptionsFromSettings(cluster.Status.InitDBSettings),
+	}
+
+	// Remove the leftovers of a previous failed attempt
+	if err := os.RemoveAll(newInfo.PgData); err != nil {
+		return err
+	}
+
+	if err := newInfo.CreateDataDirectory(); err != nil {
+		return err
+	}
+
+	if _, err := newInfo.GetInstance().RefreshConfigurationFilesFromCluster(cluster); err != nil {
+		return err
+	}
+
+	// pg_upgrade writes its logs and scripts in the current directory
+	workingDirectory, err := os.MkdirTemp(postgres.ScratchDataDirectory, "pg_upgrade")
+	if err != nil {
+		return err
+	}
+
+	options := []string{
+		"--old-bindir", oldBinDir,
+		"--new-bindir", filepath.Dir(pgUpgradePath),
+		"--old-datadir", info.PgData,
+		"--new-datadir", newInfo.PgData,
+		"--socketdir", workingDirectory,
+		"--username", "postgres",
+		"--old-options", pgUpgradeServerOptions,
+		"--new-options", pgUpgradeServerOptions,
+	}
+
+	log.Info("Upgrading the PostgreSQL data directory",
+		"pgdata", info.PgData,
+		"options", options)
+
+	pgUpgradeCmd := exec.Command(pgUpgradeName, options...) // #nosec
+	pgUpgradeCmd.Dir = workingDirectory
+	// The binaries of the previous major version fall back to their own
+	// shared libraries when the ones they need are not in the current image
+	pgUpgradeCmd.Env = append(os.Environ(),
+		fmt.Sprintf("LD_LIBRARY_PATH=%s:%s", newLibDir[0], oldLibDir))
+	if err := execlog.RunStreaming(pgUpgradeCmd, pgUpgradeName); err != nil {
+		log.Error(err, "Failed to execute pg_upgrade, keeping the original data directory",
+			"options", options)
+		if cleanupErr := os.RemoveAll(newInfo.PgData); cleanupErr != nil {
+			log.Error(cleanupErr, "Error while removing the upgraded data directory")
+		}
+		return fmt.Errorf("error executing pg_upgrade: %w", err)
+	}
+
+	return info.replaceDataDirectory(ctx, newInfo.PgData)
+}
+
+// replaceDataDirectory replaces the data directory with the upgraded one
+func (info InitInfo) replaceDataDirectory(ctx context.Context, upgradedPgData string) error {
+	if err := os.Rename(info.PgData, info.getReplacedPgData()); err != nil {
+		return err
+	}
+
+	if err := os.Rename(upgradedPgData, info.PgData); err != nil {
+		return err
+	}
+
+	return info.completeMajorUpgrade(ctx)
+}
+
+// completeMajorUpgrade removes the original data directory, which has
+// been replaced by the upgraded one, and moves the WALs of the upgraded
+// data directory to the WAL volume if needed
+func (info InitInfo) completeMajorUpgrade(ctx context.Context) error {
+	if err := os.RemoveAll(info.getReplacedPgData()); err != nil {
+		return err
+	}
+
+	if info.PgWal != "" {
+		pgDataWal := path.Join(info.PgData, "pg_wal")
+		if linkInfo, _ := os.Readlink(pgDataWal); linkInfo != info.PgWal {
+			// The WAL volume still contains the WALs of the original
+			// data directory, which are not needed anymore
+			if err := fileutils.RemoveDirectoryContent(info.PgWal); err != nil {
+				return err
+			}
+		}
+	}
+
+	_, err := info.restoreCustomWalDir(ctx)
+	return err
+}
+
+// getReplacedPgData gets the location where the original data
+// directory is moved when it is replaced by the upgraded one
+func (info InitInfo) getReplacedPgData() string {
+	return info.PgData + "-old"
+}
+
+// restoreInterruptedMajorUpgrade puts back the original data directory
+// if a previous attempt was interrupted while replacing it
+func (info InitInfo) restoreInterruptedMajorUpgrade() error {
+	pgDataExists, err := fileutils.FileExists(info.PgData)
+	if err != nil || pgDataExists {
+		return err
+	}
+
+	oldPgData := info.getReplacedPgData()
+	oldPgDataExists, err := fileutils.FileExists(oldPgData)
+	if err != nil {
+		return err
+	}
+	if !oldPgDataExists {
+		return fmt.Errorf("missing data directory %s", info.PgData)
+	}
+
+	log.Info("Restoring the data directory of an interrupted major upgrade",
+		"pgdata", info.PgData)
+	return os.Rename(oldPgData, info.PgData)
+}
+
+// getOldDirectory gets the location of a directory of the previous major
+// version installation, copied into the source directory, reading its
+// original path from the passed file
+func getOldDirectory(source string, locationFile string) (string, error) {
+	content, err := fileutils.ReadFile(path.Join(source, locationFile))
+	if err != nil {
+		return "", fmt.Errorf("while reading the location of the previous %s: %w", locationFile, err)
+	}
+
+	location := strings.TrimSpace(string(content))
+	if location == "" {
+		return "", fmt.Errorf("missing the location of the previous %s in %s", locationFile, source)
+	}
+
+	return path.Join(source, location), nil
+}
+
+// getInitDBOptionsFromSettings gets the initdb options needed to create a
+// data directory compatible with the one having the passed settings
+func getInitDBOptionsFromSettings(settings *apiv1.InitDBSettings) []string {
+	if settings == nil {
+		return nil
+	}
+
+	var options []string
+	if settings.DataChecksums {
+		options = append(options, "-k")
+	}
+	if settings.Encoding != "" {
+		options = append(options, fmt.Sprintf("--encoding=%s", settings.Encoding))
+	}
+	if settings.LocaleCollate != "" {
+		options = append(options, fmt.Sprintf("--lc-collate=%s", settings.LocaleCollate))
+	}
+	if settings.LocaleCType != "" {
+		options = append(options, fmt.Sprintf("--lc-ctype=%s", settings.LocaleCType))
+	}
+	if settings.WalSegmentSize != 0 {
+		options = append(options, fmt.Sprintf("--wal-segsize=%v", settings.WalSegmentSize))
+	}
+
+	return options
+}
@@ -0,0 +1,69 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"os"
+	"path"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("major upgrade", func() {
+	It("creates a data directory with the settings of the original one", func() {
+		Expect(getInitDBOptionsFromSettings(nil)).To(BeEmpty())
+		Expect(getInitDBOptionsFromSettings(&apiv1.InitDBSettings{
+			DataChecksums:  true,
+			Encoding:       "UTF8",
+			LocaleCollate:  "C",
+			LocaleCType:    "C",
+			WalSegmentSize: 32,
+		})).To(Equal([]string{
+			"-k",
+			"--encoding=UTF8",
+			"--lc-collate=C",
+			"--lc-ctype=C",
+			"--wal-segsize=32",
+		}))
+	})
+
+	It("finds the binaries of the previous major version", func() {
+		source := GinkgoT().TempDir()
+		Expect(os.WriteFile(path.Join(source, oldBinDirFile), []byte("/usr/lib/postgresql/14/bin\n"), 0o600)).
+			To(Succeed())
+
+		oldBinDir, err := getOldDirectory(source, oldBinDirFile)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(oldBinDir).To(Equal(path.Join(source, "usr/lib/postgresql/14/bin")))
+	})
+
+	It("finds the shared libraries of the previous major version", func() {
+		source := GinkgoT().TempDir()
+		Expect(os.WriteFile(path.Join(source, oldLibDirFile), []byte("/usr/lib/x86_64-linux-gnu\n"), 0o600)).
+			To(Succeed())
+
+		oldLibDir, err := getOldDirectory(source, oldLibDirFile)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(oldLibDir).To(Equal(path.Join(source, "usr/lib/x86_64-linux-gnu")))
+
+		_, err = getOldDirectory(source, oldBinDirFile)
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -23,6 +23,7 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -61,6 +62,8 @@ func NewLocalWebServer(instance *postgres.Instance) (*Webserver, error) {
 	serveMux := http.NewServeMux()
 	serveMux.HandleFunc(url.PathCache, endpoints.serveCache)
 	serveMux.HandleFunc(url.PathPgBackup, endpoints.requestBackup)
+	serveMux.HandleFunc(url.PathPgPreStop, endpoints.prepareShutdown)
+	serveMux.HandleFunc(url.PathPgCheckpoint, endpoints.requestCheckpoint)
 
 	server := &http.Server{
 		Addr:              fmt.Sprintf("localhost:%d", url.LocalPort),
@@ -188,3 +191,131 @@ func (ws *localWebserverEndpoints) requestBackup(w http.ResponseWriter, r *http.
 
 	_, _ = fmt.Fprint(w, "OK")
 }
+
+// This function prepares the instance to be shut down, and is invoked
+// by the pre-stop hook of the Pod. The primary requests a checkpoint so
+// that the shutdown checkpoint, which can't be interrupted, completes
+// well within the termination grace period
+func (ws *localWebserverEndpoints) prepareShutdown(w http.ResponseWriter, r *http.Request) {
+	cluster, err := cache.LoadCluster()
+	if err != nil {
+		http.Error(
+			w,
+			fmt.Sprintf("error while loading cached cluster: %v", err.Error()),
+			http.StatusInternalServerError)
+		return
+	}
+
+	isPrimary, err := ws.instance.IsPrimary()
+	if err != nil {
+		http.Error(
+			w,
+			fmt.Sprintf("error while checking the instance role: %v", err.Error()),
+			http.StatusInternalServerError)
+		return
+	}
+
+	// During a switchover the old primary is demoted by the instance
+	// manager, which requests its own checkpoint
+	if !isPrimary || cluster.Status.TargetPrimary != cluster.Status.CurrentPrimary {
+		_, _ = fmt.Fprint(w, "OK")
+		return
+	}
+
+	timeout := time.Duration(cluster.GetShutdownCheckpointTimeout()) * time.Second
+	log.Info("The primary instance is being stopped. Requesting a checkpoint before shutting down",
+		"timeout", timeout)
+
+	db, err := ws.instance.GetSuperUserDB()
+	if err != nil {
+		http.Error(
+			w,
+			fmt.Sprintf("error while connecting to the instance: %v", err.Error()),
+			http.StatusInternalServerError)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+	if _, err = db.ExecContext(ctx, "CHECKPOINT"); err != nil {
+		http.Error(
+			w,
+			fmt.Sprintf("error while requesting a checkpoint: %v", err.Error()),
+			http.StatusInternalServerError)
+		return
+	}
+
+	_, _ = fmt.Fprint(w, "OK")
+}
+
+// CheckpointResult is the outcome of a checkpoint request
+type CheckpointResult struct {
+	// The LSN following the end of the WAL file which has been switched
+	LSN string `json:"lsn"`
+
+	// The name of the WAL file which has been switched, and
+	// can now be archived
+	WALName string `json:"walName"`
+}
+
+// requestCheckpoint issues a CHECKPOINT and switches the WAL file on the
+// primary, so that the current WAL file is archived before a controlled
+// operation. The LSN and the name of the switched WAL file are returned
+func (ws *localWebserverEndpoints) requestCheckpoint(w http.ResponseWriter, r *http.Request) {
+	isPrimary, err := ws.instance.IsPrimary()
+	if err != nil {
+		http.Error(
+			w,
+			fmt.Sprintf("error while checking the instance role: %v", err.Error()),
+			http.StatusInternalServerError)
+		return
+	}
+	if !isPrimary {
+		http.Error(
+			w,
+			"checkpoints can only be requested on the primary instance",
+			http.StatusConflict)
+		return
+	}
+
+	db, err := ws.instance.GetSuperUserDB()
+	if err != nil {
+		http.Error(
+			w,
+			fmt.Sprintf("error while connecting to the instance: %v", err.Error()),
+			http.StatusInternalServerError)
+		return
+	}
+
+	log.Info("Requesting a checkpoint and a WAL switch")
+	if _, err = db.ExecContext(r.Context(), "CHECKPOINT"); err != nil {
+		http.Error(
+			w,
+			fmt.Sprintf("error while requesting a checkpoint: %v", err.Error()),
+			http.StatusInternalServerError)
+		return
+	}
+
+	var result CheckpointResult
+	row := db.QueryRowContext(r.Context(), "SELECT lsn, pg_walfile_name(lsn) FROM pg_switch_wal() AS lsn")
+	if err = row.Scan(&result.LSN, &result.WALName); err != nil {
+		http.Error(
+			w,
+			fmt.Sprintf("error while switching the WAL file: %v", err.Error()),
+			http.StatusInternalServerError)
+		return
+	}
+	log.Info("Checkpoint completed and WAL file switched",
+		"lsn", result.LSN,
+		"walName", result.WALName)
+
+	js, err := json.Marshal(result)
+	if err != nil {
+		http.Error(
+			w,
+			fmt.Sprintf("error while encoding the checkpoint result: %v", err.Error()),
+			http.StatusInternalServerError)
+		return
+	}
+	_, _ = w.Write(js)
+}
@@ -313,7 +313,7 @@ func (e *Exporter) collectPgMetrics(ch chan<- prometheus.Metric) {
 		return
 	}
 
-	db, err := e.instance.GetSuperUserDB()
+	db, err := e.instance.MonitoringConnectionPool().Connection("postgres")
 	if err != nil {
 		log.Error(err, "Error opening connection to PostgreSQL")
 		e.Metrics.Error.Set(1)
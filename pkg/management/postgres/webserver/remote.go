@@ -104,8 +104,10 @@ func (ws *remoteWebserverEndpoints) isServerReady(w http.ResponseWriter, r *http
 
 // This probe is for the instance status, including replication
 func (ws *remoteWebserverEndpoints) pgStatus(w http.ResponseWriter, r *http.Request) {
-	// Extract the status of the current instance
-	status, err := ws.instance.GetStatus()
+	// Extract the status of the current instance. The request context
+	// is canceled when the operator stops waiting for the answer, and
+	// that interrupts the queries that are still running
+	status, err := ws.instance.GetStatus(r.Context())
 	if err != nil {
 		log.Info(
 			"Instance status probe failing",
@@ -43,6 +43,12 @@ const (
 	// PathPgBackup is the URL path for PostgreSQL Backup
 	PathPgBackup string = "/pg/backup"
 
+	// PathPgPreStop is the URL path preparing PostgreSQL to be shut down
+	PathPgPreStop string = "/pg/prestop"
+
+	// PathPgCheckpoint is the URL path requesting a checkpoint and a WAL switch
+	PathPgCheckpoint string = "/pg/checkpoint"
+
 	// PathMetrics is the URL path for Metrics
 	PathMetrics string = "/metrics"
 
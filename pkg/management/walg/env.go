@@ -0,0 +1,108 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package walg
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	barmanCredentials "github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/credentials"
+)
+
+// storagePrefixes maps the scheme of the destination path to the
+// variable wal-g reads the location of the storage from
+var storagePrefixes = map[string]string{
+	"s3://":    "WALG_S3_PREFIX",
+	"gs://":    "WALG_GS_PREFIX",
+	"azure://": "WALG_AZ_PREFIX",
+}
+
+// credentialsAliases maps the variables set for barman-cloud to the
+// ones read by wal-g, when they differ
+var credentialsAliases = map[string]string{
+	"AWS_DEFAULT_REGION": "AWS_REGION",
+	"AZURE_STORAGE_KEY":  "AZURE_STORAGE_ACCESS_KEY",
+}
+
+// EnvSetCredentials sets the environment variables wal-g reads the
+// location of the storage and its credentials from, given the
+// configuration inside the cluster
+func EnvSetCredentials(
+	ctx context.Context,
+	c client.Client,
+	namespace string,
+	configuration *apiv1.WalGConfiguration,
+	env []string,
+) ([]string, error) {
+	storageEnv, err := StorageEnv(configuration)
+	if err != nil {
+		return nil, err
+	}
+
+	env, err = barmanCredentials.EnvSetBackupCloudCredentials(
+		ctx,
+		c,
+		namespace,
+		&apiv1.BarmanObjectStoreConfiguration{
+			DestinationPath:   configuration.DestinationPath,
+			EndpointURL:       configuration.EndpointURL,
+			BarmanCredentials: configuration.BarmanCredentials,
+		},
+		env)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, variable := range env {
+		name, value, _ := strings.Cut(variable, "=")
+		if alias, ok := credentialsAliases[name]; ok {
+			env = append(env, fmt.Sprintf("%s=%s", alias, value))
+		}
+	}
+
+	return append(env, storageEnv...), nil
+}
+
+// StorageEnv gets the environment variables configuring the storage
+// where wal-g archives the WAL files
+func StorageEnv(configuration *apiv1.WalGConfiguration) ([]string, error) {
+	var env []string
+	for scheme, variable := range storagePrefixes {
+		if strings.HasPrefix(configuration.DestinationPath, scheme) {
+			env = append(env, fmt.Sprintf("%s=%s", variable, configuration.DestinationPath))
+		}
+	}
+	if env == nil {
+		return nil, fmt.Errorf("unsupported destination path for wal-g: %s", configuration.DestinationPath)
+	}
+
+	if configuration.EndpointURL != "" {
+		env = append(env,
+			fmt.Sprintf("AWS_ENDPOINT=%s", configuration.EndpointURL),
+			"AWS_S3_FORCE_PATH_STYLE=true")
+	}
+
+	if configuration.Compression != "" {
+		env = append(env, fmt.Sprintf("WALG_COMPRESSION_METHOD=%s", configuration.Compression))
+	}
+
+	return env, nil
+}
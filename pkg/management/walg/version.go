@@ -0,0 +1,98 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package walg
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"regexp"
+
+	"github.com/blang/semver"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// MinimumVersion is the oldest version of wal-g supported by the
+// instance manager
+var MinimumVersion = semver.Version{Major: 1}
+
+// ErrNotInstalled is returned when wal-g is not available in the image
+var ErrNotInstalled = errors.New("wal-g is not installed in the operand image")
+
+// currentVersion stores the version of the local wal-g installation
+var currentVersion *semver.Version
+
+// walgVersionRegex is a regular expression to parse the output of
+// `wal-g --version`
+var walgVersionRegex = regexp.MustCompile(`wal-g version v?([0-9]+\.[0-9]+(\.[0-9]+)?)`)
+
+// Detect gets the version of the local wal-g installation, checking
+// it's supported
+func Detect() (*semver.Version, error) {
+	if _, err := exec.LookPath(WalG); err != nil {
+		return nil, ErrNotInstalled
+	}
+
+	cmd := exec.Command(WalG, "--version") // #nosec G204
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("while checking %s version: %w", WalG, err)
+	}
+
+	version, err := parseVersion(string(out))
+	if err != nil {
+		return nil, err
+	}
+	if version.LT(MinimumVersion) {
+		return nil, fmt.Errorf("wal-g %v is not supported, the minimum version is %v", version, MinimumVersion)
+	}
+
+	log.Debug("Detected wal-g installation", "version", version)
+	return version, nil
+}
+
+// parseVersion extracts the version of wal-g from the output of
+// `wal-g --version`
+func parseVersion(output string) (*semver.Version, error) {
+	matches := walgVersionRegex.FindStringSubmatch(output)
+	if matches == nil {
+		return nil, fmt.Errorf("cannot find the version of %s in %q", WalG, output)
+	}
+
+	version, err := semver.ParseTolerant(matches[1])
+	if err != nil {
+		return nil, fmt.Errorf("while parsing %s version: %w", WalG, err)
+	}
+
+	return &version, nil
+}
+
+// CurrentVersion retrieves the version of the local wal-g installation,
+// retrieving it from the cache if available
+func CurrentVersion() (*semver.Version, error) {
+	if currentVersion == nil {
+		version, err := Detect()
+		if err != nil {
+			log.Error(err, "Failed to detect the wal-g version")
+			return nil, err
+		}
+		currentVersion = version
+	}
+
+	return currentVersion, nil
+}
@@ -0,0 +1,131 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package walg archives and restores the WAL files, and restores the
+// base backups, with wal-g
+package walg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/execlog"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// WalG is the command name for 'wal-g'
+const WalG = "wal-g"
+
+// LatestBackup is the name wal-g gives to the most recent base backup
+const LatestBackup = "LATEST"
+
+// walNotFoundExitCode is the exit code of wal-g wal-fetch when the
+// requested WAL file is not in the storage
+const walNotFoundExitCode = 74
+
+// ErrWALNotFound is returned when the WAL file is not in the storage
+var ErrWALNotFound = errors.New("WAL not found")
+
+// WalPushOptions gets the options of wal-g needed to archive a WAL file
+func WalPushOptions(walPath string) []string {
+	return []string{"wal-push", walPath}
+}
+
+// WalFetchOptions gets the options of wal-g needed to restore a WAL file
+// in the destination path
+func WalFetchOptions(walName string, destinationPath string) []string {
+	return []string{"wal-fetch", walName, destinationPath}
+}
+
+// BackupFetchOptions gets the options of wal-g needed to restore a base
+// backup in the PGDATA directory, defaulting to the latest one
+func BackupFetchOptions(pgData string, backupName string) []string {
+	if backupName == "" {
+		backupName = LatestBackup
+	}
+	return []string{"backup-fetch", pgData, backupName}
+}
+
+// RestoreCommand gets the restore_command making PostgreSQL restore the
+// WAL files with wal-g
+func RestoreCommand() string {
+	return strings.Join(append([]string{WalG}, WalFetchOptions("%f", "%p")...), " ")
+}
+
+// WalPush archives a WAL file, whose path is relative to PGDATA
+func WalPush(ctx context.Context, env []string, pgData string, walName string) error {
+	cmd, err := newCommand(WalPushOptions(walName), env)
+	if err != nil {
+		return err
+	}
+	cmd.Dir = pgData
+
+	return run(ctx, cmd, walName)
+}
+
+// WalFetch restores a WAL file in the destination path, returning
+// ErrWALNotFound when the WAL file is not in the storage
+func WalFetch(ctx context.Context, env []string, walName string, destinationPath string) error {
+	cmd, err := newCommand(WalFetchOptions(walName, destinationPath), env)
+	if err != nil {
+		return err
+	}
+
+	err = run(ctx, cmd, walName)
+	if err != nil && cmd.ProcessState.ExitCode() == walNotFoundExitCode {
+		return ErrWALNotFound
+	}
+	return err
+}
+
+// BackupFetch restores a base backup in the PGDATA directory
+func BackupFetch(ctx context.Context, env []string, pgData string, backupName string) error {
+	cmd, err := newCommand(BackupFetchOptions(pgData, backupName), env)
+	if err != nil {
+		return err
+	}
+
+	log.FromContext(ctx).Info("Starting wal-g backup-fetch", "options", cmd.Args[1:])
+	return run(ctx, cmd, "")
+}
+
+// newCommand creates the wal-g command, after checking the installed
+// version of wal-g is supported
+func newCommand(options []string, env []string) (*exec.Cmd, error) {
+	if _, err := CurrentVersion(); err != nil {
+		return nil, err
+	}
+
+	cmd := exec.Command(WalG, options...) // #nosec G204
+	cmd.Env = env
+	return cmd, nil
+}
+
+// run executes a wal-g command, logging its output
+func run(ctx context.Context, cmd *exec.Cmd, walName string) error {
+	if err := execlog.RunStreaming(cmd, WalG); err != nil {
+		log.FromContext(ctx).Error(err, "Error invoking "+WalG,
+			"walName", walName,
+			"options", cmd.Args[1:],
+			"exitCode", cmd.ProcessState.ExitCode())
+		return fmt.Errorf("unexpected failure invoking %s %s: %w", WalG, cmd.Args[1], err)
+	}
+
+	return nil
+}
@@ -0,0 +1,83 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package walg
+
+import (
+	"github.com/blang/semver"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("wal-g options", func() {
+	It("builds the options of the commands", func() {
+		Expect(WalPushOptions("pg_wal/000000010000000000000001")).
+			To(Equal([]string{"wal-push", "pg_wal/000000010000000000000001"}))
+		Expect(WalFetchOptions("000000010000000000000001", "pg_wal/RECOVERYXLOG")).
+			To(Equal([]string{"wal-fetch", "000000010000000000000001", "pg_wal/RECOVERYXLOG"}))
+		Expect(BackupFetchOptions("/var/lib/postgresql/data/pgdata", "")).
+			To(Equal([]string{"backup-fetch", "/var/lib/postgresql/data/pgdata", "LATEST"}))
+		Expect(RestoreCommand()).To(Equal("wal-g wal-fetch %f %p"))
+	})
+})
+
+var _ = Describe("wal-g storage environment", func() {
+	It("sets the prefix matching the cloud provider", func() {
+		env, err := StorageEnv(&apiv1.WalGConfiguration{DestinationPath: "gs://bucket/cluster"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(env).To(ConsistOf("WALG_GS_PREFIX=gs://bucket/cluster"))
+
+		env, err = StorageEnv(&apiv1.WalGConfiguration{DestinationPath: "azure://container/cluster"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(env).To(ConsistOf("WALG_AZ_PREFIX=azure://container/cluster"))
+	})
+
+	It("sets the endpoint and the compression", func() {
+		env, err := StorageEnv(&apiv1.WalGConfiguration{
+			DestinationPath: "s3://bucket/cluster",
+			EndpointURL:     "https://minio:9000",
+			Compression:     "zstd",
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(env).To(ConsistOf(
+			"WALG_S3_PREFIX=s3://bucket/cluster",
+			"AWS_ENDPOINT=https://minio:9000",
+			"AWS_S3_FORCE_PATH_STYLE=true",
+			"WALG_COMPRESSION_METHOD=zstd",
+		))
+	})
+
+	It("rejects an unknown destination path", func() {
+		_, err := StorageEnv(&apiv1.WalGConfiguration{DestinationPath: "/archive"})
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("wal-g version detection", func() {
+	It("parses the output of wal-g --version", func() {
+		version, err := parseVersion("wal-g version v2.0.1\t1eb88a5\t2022.05.20_10:45:57\tPostgreSQL\n")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(*version).To(Equal(semver.Version{Major: 2, Minor: 0, Patch: 1}))
+	})
+
+	It("complains when the version can't be found", func() {
+		_, err := parseVersion("command not found")
+		Expect(err).To(HaveOccurred())
+	})
+})
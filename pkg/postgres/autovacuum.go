@@ -0,0 +1,127 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// autovacuumParameterKind is the type of the value of an
+// autovacuum parameter
+type autovacuumParameterKind int
+
+const (
+	autovacuumParameterBool autovacuumParameterKind = iota
+	autovacuumParameterInteger
+	autovacuumParameterReal
+)
+
+// autovacuumParameter describes the values accepted by an autovacuum
+// parameter
+type autovacuumParameter struct {
+	kind autovacuumParameterKind
+	min  float64
+	max  float64
+
+	// Whether the parameter can be set on the TOAST table too,
+	// with the `toast.` prefix
+	toast bool
+}
+
+// autovacuumStorageParameters are the autovacuum storage parameters
+// that can be set on a table, with the range of their values
+var autovacuumStorageParameters = map[string]autovacuumParameter{
+	"autovacuum_enabled":                    {autovacuumParameterBool, 0, 0, true},
+	"autovacuum_vacuum_threshold":           {autovacuumParameterInteger, 0, math.MaxInt32, true},
+	"autovacuum_vacuum_scale_factor":        {autovacuumParameterReal, 0, 100, true},
+	"autovacuum_vacuum_insert_threshold":    {autovacuumParameterInteger, -1, math.MaxInt32, true},
+	"autovacuum_vacuum_insert_scale_factor": {autovacuumParameterReal, 0, 100, true},
+	"autovacuum_analyze_threshold":          {autovacuumParameterInteger, 0, math.MaxInt32, false},
+	"autovacuum_analyze_scale_factor":       {autovacuumParameterReal, 0, 100, false},
+	"autovacuum_vacuum_cost_delay":          {autovacuumParameterReal, 0, 100, true},
+	"autovacuum_vacuum_cost_limit":          {autovacuumParameterInteger, 1, 10000, true},
+	"autovacuum_freeze_min_age":             {autovacuumParameterInteger, 0, 1000000000, true},
+	"autovacuum_freeze_max_age":             {autovacuumParameterInteger, 100000, 2000000000, true},
+	"autovacuum_freeze_table_age":           {autovacuumParameterInteger, 0, 2000000000, true},
+	"autovacuum_multixact_freeze_min_age":   {autovacuumParameterInteger, 0, 1000000000, true},
+	"autovacuum_multixact_freeze_max_age":   {autovacuumParameterInteger, 10000, 2000000000, true},
+	"autovacuum_multixact_freeze_table_age": {autovacuumParameterInteger, 0, 2000000000, true},
+	"log_autovacuum_min_duration":           {autovacuumParameterInteger, -1, math.MaxInt32, true},
+}
+
+// IsAutovacuumStorageParameter checks if the passed name is an autovacuum
+// storage parameter of a table, or of its TOAST table
+func IsAutovacuumStorageParameter(name string) bool {
+	_, ok := getAutovacuumStorageParameter(name)
+	return ok
+}
+
+// ValidateAutovacuumStorageParameter checks that the passed value is
+// accepted by an autovacuum storage parameter
+func ValidateAutovacuumStorageParameter(name, value string) error {
+	parameter, ok := getAutovacuumStorageParameter(name)
+	if !ok {
+		return fmt.Errorf("%s is not an autovacuum storage parameter", name)
+	}
+
+	switch parameter.kind {
+	case autovacuumParameterBool:
+		if _, err := strconv.ParseBool(value); err != nil {
+			return fmt.Errorf("%s must be a boolean", name)
+		}
+		return nil
+
+	case autovacuumParameterInteger:
+		number, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return fmt.Errorf("%s must be an integer", name)
+		}
+		return checkAutovacuumParameterRange(name, parameter, float64(number))
+
+	default:
+		number, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("%s must be a number", name)
+		}
+		return checkAutovacuumParameterRange(name, parameter, number)
+	}
+}
+
+// getAutovacuumStorageParameter gets the description of a storage
+// parameter, handling the `toast.` prefix
+func getAutovacuumStorageParameter(name string) (autovacuumParameter, bool) {
+	toastName := strings.TrimPrefix(name, "toast.")
+	parameter, ok := autovacuumStorageParameters[toastName]
+	if !ok || (toastName != name && !parameter.toast) {
+		return autovacuumParameter{}, false
+	}
+
+	return parameter, true
+}
+
+func checkAutovacuumParameterRange(name string, parameter autovacuumParameter, value float64) error {
+	if value < parameter.min || value > parameter.max {
+		return fmt.Errorf("%s must be between %v and %v", name,
+			strconv.FormatFloat(parameter.min, 'f', -1, 64),
+			strconv.FormatFloat(parameter.max, 'f', -1, 64))
+	}
+
+	return nil
+}
@@ -0,0 +1,50 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("autovacuum storage parameters", func() {
+	It("recognizes the parameters of the tables and of their TOAST tables", func() {
+		Expect(IsAutovacuumStorageParameter("autovacuum_vacuum_scale_factor")).To(BeTrue())
+		Expect(IsAutovacuumStorageParameter("toast.autovacuum_vacuum_scale_factor")).To(BeTrue())
+		Expect(IsAutovacuumStorageParameter("toast.autovacuum_analyze_scale_factor")).To(BeFalse())
+		Expect(IsAutovacuumStorageParameter("fillfactor")).To(BeFalse())
+	})
+
+	DescribeTable("validates the values",
+		func(name, value string, valid bool) {
+			err := ValidateAutovacuumStorageParameter(name, value)
+			if valid {
+				Expect(err).ToNot(HaveOccurred())
+			} else {
+				Expect(err).To(HaveOccurred())
+			}
+		},
+		Entry("boolean", "autovacuum_enabled", "false", true),
+		Entry("not a boolean", "autovacuum_enabled", "sometimes", false),
+		Entry("integer in range", "autovacuum_vacuum_threshold", "1000", true),
+		Entry("integer out of range", "autovacuum_vacuum_cost_limit", "0", false),
+		Entry("not an integer", "autovacuum_vacuum_threshold", "0.5", false),
+		Entry("real in range", "toast.autovacuum_vacuum_scale_factor", "0.05", true),
+		Entry("real out of range", "autovacuum_analyze_scale_factor", "101", false),
+		Entry("unknown parameter", "fillfactor", "70", false),
+	)
+})
@@ -69,6 +69,10 @@ host all all all {{.DefaultAuthenticationMethod}}
 	// ProjectedVolumeDirectory is the base directory to store ProjectedVolumeSource
 	ProjectedVolumeDirectory = "/projected"
 
+	// ObjectStoreCredentialsDirectory is the directory where the volume
+	// containing the credentials of the backup object store is mounted
+	ObjectStoreCredentialsDirectory = "/object-store-credentials"
+
 	// ServerCertificateLocation is the location where the server certificate
 	// is stored
 	ServerCertificateLocation = CertificatesDir + "server.crt"
@@ -227,6 +231,27 @@ type ConfigurationInfo struct {
 
 	// Is this a replica cluster?
 	IsReplicaCluster bool
+
+	// The maximum size of WAL files retained by replication slots,
+	// empty if unlimited
+	MaxSlotWalKeepSize string
+
+	// The minimum size of the WAL files kept for the standbys,
+	// empty to use the default
+	WalKeepSize string
+
+	// The synchronous_commit level, empty to use the PostgreSQL default
+	SynchronousCommit string
+
+	// The archive module replacing the archive_command, empty to use
+	// the archive_command
+	ArchiveLibrary string
+
+	// The autovacuum settings, overriding the user settings
+	AutovacuumSettings map[string]string
+
+	// The checkpoint settings, overriding the user settings
+	CheckpointSettings map[string]string
 }
 
 // ManagedExtension defines all the information about a managed extension
@@ -317,6 +342,7 @@ var (
 		// The following parameters need a reload to be applied
 		"archive_cleanup_command":                blockedConfigurationParameter,
 		"archive_command":                        fixedConfigurationParameter,
+		"archive_library":                        fixedConfigurationParameter,
 		"full_page_writes":                       fixedConfigurationParameter,
 		"log_destination":                        blockedConfigurationParameter,
 		"log_directory":                          blockedConfigurationParameter,
@@ -406,6 +432,12 @@ var (
 	}
 )
 
+// IsArchiveLibrarySupported checks whether the given PostgreSQL version
+// supports archiving the WAL files through an archive module
+func IsArchiveLibrarySupported(majorVersion int) bool {
+	return majorVersion >= 150000
+}
+
 // CreateHBARules will create the content of pg_hba.conf file given
 // the rules set by the cluster spec
 func CreateHBARules(hba []string,
@@ -504,6 +536,14 @@ func CreatePostgresqlConfiguration(info ConfigurationInfo) *PgConfiguration {
 		configuration.OverwriteConfig(key, value)
 	}
 
+	for key, value := range info.AutovacuumSettings {
+		configuration.OverwriteConfig(key, value)
+	}
+
+	for key, value := range info.CheckpointSettings {
+		configuration.OverwriteConfig(key, value)
+	}
+
 	// Apply all mandatory settings, on top of defaults and user settings
 	if info.IncludingMandatory {
 		for key, value := range info.Settings.MandatorySettings {
@@ -511,6 +551,14 @@ func CreatePostgresqlConfiguration(info ConfigurationInfo) *PgConfiguration {
 		}
 	}
 
+	// Archive the WAL files through the archive module, a feature
+	// available since PostgreSQL 15. The archive_command needs to be
+	// empty, as PostgreSQL 16 refuses to archive when both are set
+	if info.IncludingMandatory && info.ArchiveLibrary != "" && IsArchiveLibrarySupported(info.MajorVersion) {
+		configuration.OverwriteConfig("archive_library", info.ArchiveLibrary)
+		configuration.OverwriteConfig("archive_command", "")
+	}
+
 	// Apply the correct archive_mode
 	if info.IsReplicaCluster {
 		configuration.OverwriteConfig("archive_mode", "always")
@@ -518,6 +566,22 @@ func CreatePostgresqlConfiguration(info ConfigurationInfo) *PgConfiguration {
 		configuration.OverwriteConfig("archive_mode", "on")
 	}
 
+	// Cap the WAL files retained by replication slots, a feature
+	// available since PostgreSQL 13
+	if info.MaxSlotWalKeepSize != "" && info.MajorVersion >= 130000 {
+		configuration.OverwriteConfig("max_slot_wal_keep_size", info.MaxSlotWalKeepSize)
+	}
+
+	if info.SynchronousCommit != "" {
+		configuration.OverwriteConfig("synchronous_commit", info.SynchronousCommit)
+	}
+
+	// Set the WAL files kept for the standbys, replacing the default,
+	// a feature available since PostgreSQL 13
+	if info.WalKeepSize != "" && info.MajorVersion >= 130000 {
+		configuration.OverwriteConfig("wal_keep_size", info.WalKeepSize)
+	}
+
 	// Apply the list of replicas
 	setReplicasListConfigurations(info, configuration)
 
@@ -145,6 +145,97 @@ var _ = Describe("PostgreSQL configuration creation", func() {
 		})
 	})
 
+	When("the WAL retained by replication slots is limited", func() {
+		It("sets max_slot_wal_keep_size on PostgreSQL 13 and above", func() {
+			info := ConfigurationInfo{
+				Settings:           CnpgConfigurationSettings,
+				MajorVersion:       130000,
+				UserSettings:       settings,
+				IncludingMandatory: true,
+				MaxSlotWalKeepSize: "10GB",
+			}
+			config := CreatePostgresqlConfiguration(info)
+			Expect(config.GetConfig("max_slot_wal_keep_size")).To(Equal("10GB"))
+		})
+
+		It("ignores the limit on older PostgreSQL versions", func() {
+			info := ConfigurationInfo{
+				Settings:           CnpgConfigurationSettings,
+				MajorVersion:       120000,
+				UserSettings:       settings,
+				IncludingMandatory: true,
+				MaxSlotWalKeepSize: "10GB",
+			}
+			config := CreatePostgresqlConfiguration(info)
+			Expect(config.GetConfig("max_slot_wal_keep_size")).To(BeEmpty())
+		})
+	})
+
+	Context("archive library", func() {
+		It("replaces the archive_command with the archive library", func() {
+			info := ConfigurationInfo{
+				Settings:           CnpgConfigurationSettings,
+				MajorVersion:       150000,
+				UserSettings:       settings,
+				IncludingMandatory: true,
+				ArchiveLibrary:     "example_archive",
+			}
+			config := CreatePostgresqlConfiguration(info)
+			Expect(config.GetConfig("archive_library")).To(Equal("example_archive"))
+			Expect(config.GetConfig("archive_command")).To(BeEmpty())
+		})
+
+		It("falls back to the archive_command on older PostgreSQL versions", func() {
+			info := ConfigurationInfo{
+				Settings:           CnpgConfigurationSettings,
+				MajorVersion:       140000,
+				UserSettings:       settings,
+				IncludingMandatory: true,
+				ArchiveLibrary:     "example_archive",
+			}
+			config := CreatePostgresqlConfiguration(info)
+			Expect(config.GetConfigurationParameters()).ToNot(HaveKey("archive_library"))
+			Expect(config.GetConfig("archive_command")).To(ContainSubstring("wal-archive"))
+		})
+	})
+
+	It("sets the synchronous_commit level when requested", func() {
+		info := ConfigurationInfo{
+			Settings:           CnpgConfigurationSettings,
+			MajorVersion:       130000,
+			UserSettings:       settings,
+			IncludingMandatory: true,
+			SynchronousCommit:  "remote_apply",
+		}
+		config := CreatePostgresqlConfiguration(info)
+		Expect(config.GetConfig("synchronous_commit")).To(Equal("remote_apply"))
+	})
+
+	When("the WAL files kept for the standbys are configured", func() {
+		It("replaces the default wal_keep_size", func() {
+			info := ConfigurationInfo{
+				Settings:           CnpgConfigurationSettings,
+				MajorVersion:       130000,
+				UserSettings:       settings,
+				IncludingMandatory: true,
+				WalKeepSize:        "2048MB",
+			}
+			config := CreatePostgresqlConfiguration(info)
+			Expect(config.GetConfig("wal_keep_size")).To(Equal("2048MB"))
+		})
+
+		It("keeps the default when not configured", func() {
+			info := ConfigurationInfo{
+				Settings:           CnpgConfigurationSettings,
+				MajorVersion:       130000,
+				UserSettings:       settings,
+				IncludingMandatory: true,
+			}
+			config := CreatePostgresqlConfiguration(info)
+			Expect(config.GetConfig("wal_keep_size")).To(Equal("512MB"))
+		})
+	})
+
 	It("adds shared_preload_library correctly", func() {
 		info := ConfigurationInfo{
 			Settings:                         CnpgConfigurationSettings,
@@ -321,3 +412,43 @@ var _ = Describe("pgaudit", func() {
 		Expect(libraries).To(ContainElements("pg_stat_statements", "pgaudit"))
 	})
 })
+
+var _ = Describe("autovacuum settings", func() {
+	It("override the user settings", func() {
+		info := ConfigurationInfo{
+			Settings:     CnpgConfigurationSettings,
+			MajorVersion: 150000,
+			UserSettings: map[string]string{
+				"autovacuum_naptime": "30",
+			},
+			AutovacuumSettings: map[string]string{
+				"autovacuum_naptime":     "10",
+				"autovacuum_max_workers": "6",
+			},
+			IncludingMandatory: true,
+		}
+		config := CreatePostgresqlConfiguration(info)
+		Expect(config.GetConfig("autovacuum_naptime")).To(Equal("10"))
+		Expect(config.GetConfig("autovacuum_max_workers")).To(Equal("6"))
+	})
+})
+
+var _ = Describe("checkpoint settings", func() {
+	It("override the user settings", func() {
+		info := ConfigurationInfo{
+			Settings:     CnpgConfigurationSettings,
+			MajorVersion: 150000,
+			UserSettings: map[string]string{
+				"max_wal_size": "1GB",
+			},
+			CheckpointSettings: map[string]string{
+				"max_wal_size":       "4096MB",
+				"checkpoint_timeout": "900",
+			},
+			IncludingMandatory: true,
+		}
+		config := CreatePostgresqlConfiguration(info)
+		Expect(config.GetConfig("max_wal_size")).To(Equal("4096MB"))
+		Expect(config.GetConfig("checkpoint_timeout")).To(Equal("900"))
+	})
+})
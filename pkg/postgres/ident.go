@@ -0,0 +1,137 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+const (
+	// LocalIdentMapName is the name of the user name map used by the
+	// operator to grant local access to the current system user
+	LocalIdentMapName = "local"
+
+	// identTemplateString is the template used to generate the pg_ident.conf
+	// configuration file
+	identTemplateString = `
+# Map the system user to the postgres user for local access
+{{.LocalMapName}} {{.SystemUser}} postgres
+{{ range $rule := .UserRules }}
+{{ $rule -}}
+{{ end }}
+`
+)
+
+// identTemplate is the template used to create the user name maps
+var identTemplate = template.Must(template.New("pg_ident.conf").Parse(identTemplateString))
+
+// CreateIdentRules will create the content of pg_ident.conf file given
+// the system user running PostgreSQL and the maps set by the cluster spec
+func CreateIdentRules(ident []string, systemUser string) (string, error) {
+	var identContent bytes.Buffer
+
+	templateData := struct {
+		LocalMapName string
+		SystemUser   string
+		UserRules    []string
+	}{
+		LocalMapName: LocalIdentMapName,
+		SystemUser:   systemUser,
+		UserRules:    ident,
+	}
+
+	if err := identTemplate.Execute(&identContent, templateData); err != nil {
+		return "", err
+	}
+
+	return identContent.String(), nil
+}
+
+// ValidateIdentRule checks that a line to be added to pg_ident.conf is
+// either a comment or a user name map, made of the map name, the system
+// user name and the PostgreSQL user name. The map used by the operator
+// for the local access can't be extended
+func ValidateIdentRule(rule string) error {
+	rule = strings.TrimSpace(rule)
+	if rule == "" || strings.HasPrefix(rule, "#") {
+		return nil
+	}
+
+	tokens, err := splitIdentRule(rule)
+	if err != nil {
+		return err
+	}
+
+	if len(tokens) != 3 {
+		return fmt.Errorf("expected a map name, a system user name and a PostgreSQL user name, found %d fields",
+			len(tokens))
+	}
+
+	if tokens[0] == LocalIdentMapName {
+		return fmt.Errorf("the %q map is reserved for the operator", LocalIdentMapName)
+	}
+
+	return nil
+}
+
+// splitIdentRule splits a line of pg_ident.conf in its fields, which are
+// separated by white spaces unless they are double-quoted. A trailing
+// comment is ignored
+func splitIdentRule(rule string) ([]string, error) {
+	var tokens []string
+	var current strings.Builder
+	inToken := false
+	inQuotes := false
+
+	for _, char := range rule {
+		switch {
+		case char == '"':
+			inQuotes = !inQuotes
+			inToken = true
+			current.WriteRune(char)
+		case inQuotes:
+			current.WriteRune(char)
+		case char == '#':
+			if inToken {
+				tokens = append(tokens, current.String())
+			}
+			return tokens, nil
+		case char == ' ' || char == '\t':
+			if inToken {
+				tokens = append(tokens, current.String())
+				current.Reset()
+				inToken = false
+			}
+		default:
+			inToken = true
+			current.WriteRune(char)
+		}
+	}
+
+	if inQuotes {
+		return nil, fmt.Errorf("unterminated quoted string")
+	}
+
+	if inToken {
+		tokens = append(tokens, current.String())
+	}
+
+	return tokens, nil
+}
@@ -0,0 +1,64 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("pg_ident.conf generation", func() {
+	It("always contains the local map", func() {
+		content, err := CreateIdentRules(nil, "postgres")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(content).To(ContainSubstring("\nlocal postgres postgres\n"))
+	})
+
+	It("appends the user name maps", func() {
+		content, err := CreateIdentRules([]string{
+			"certmap client.example.com app",
+			"peermap /^(.*)@example\\.com$ \\1",
+		}, "postgres")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(content).To(ContainSubstring("\ncertmap client.example.com app\n"))
+		Expect(content).To(ContainSubstring("\npeermap /^(.*)@example\\.com$ \\1\n"))
+	})
+})
+
+var _ = Describe("pg_ident.conf rule validation", func() {
+	DescribeTable("validates the rules",
+		func(rule string, valid bool) {
+			err := ValidateIdentRule(rule)
+			if valid {
+				Expect(err).ToNot(HaveOccurred())
+			} else {
+				Expect(err).To(HaveOccurred())
+			}
+		},
+		Entry("a map", "certmap client.example.com app", true),
+		Entry("a map with a regular expression", `peermap /^(.*)@example\.com$ \1`, true),
+		Entry("a map with quoted names", `certmap "CN=App Client" "app user"`, true),
+		Entry("a map with a trailing comment", "certmap client app # the application", true),
+		Entry("a comment", "# this is a comment", true),
+		Entry("an empty line", "  ", true),
+		Entry("a map missing the PostgreSQL user", "certmap client", false),
+		Entry("a map with too many fields", "certmap client app other", false),
+		Entry("an include directive", "include other.conf", false),
+		Entry("an unterminated quote", `certmap "client app`, false),
+		Entry("the reserved local map", "local root postgres", false),
+	)
+})
@@ -45,6 +45,17 @@ type PostgresqlStatus struct {
 	// populated when MightBeUnavailable reported a healthy status even if it found an error
 	MightBeUnavailableMaskedError string `json:"mightBeUnavailableMaskedError,omitempty"`
 
+	// True when the instance is accepting read-write connections, i.e. it
+	// is not in recovery and the superuser can start a read-write
+	// transaction. Not populated by the instance managers which don't
+	// support this check
+	IsAcceptingReadWrite *bool `json:"isAcceptingReadWrite,omitempty"`
+
+	// True when the primary is configured with
+	// `default_transaction_read_only = on`, i.e. because the
+	// cluster has been set to read-only
+	IsReadOnlyDefault bool `json:"isReadOnlyDefault,omitempty"`
+
 	// WAL Status
 	// SELECT
 	//		last_archived_wal,
@@ -61,13 +72,49 @@ type PostgresqlStatus struct {
 	IsArchivingWAL      bool   `json:"isArchivingWAL,omitempty"`
 	CurrentWAL          string `json:"currentWAL,omitempty"`
 
-	// Is the number of '.ready' wal files contained in the wal archive folder
+	// Is the number of '.ready' wal files contained in the wal archive folder,
+	// i.e. the WAL files waiting to be archived
 	ReadyWALFiles int `json:"readyWalFiles,omitempty"`
 
 	// The current timeline ID
 	// SELECT timeline_id FROM pg_control_checkpoint()
 	TimeLineID int `json:"timeLineID,omitempty"`
 
+	// The major version of the running PostgreSQL server. Not populated
+	// by the instance managers which don't report it
+	PostgresMajorVersion int `json:"postgresMajorVersion,omitempty"`
+
+	// The delay, in milliseconds, a replica waits before applying the
+	// received changes, from the `recovery_min_apply_delay` setting
+	MinApplyDelay int64 `json:"minApplyDelay,omitempty"`
+
+	// The number of data page checksum failures detected in the instance
+	// since the statistics have been reset, from `pg_stat_database`
+	ChecksumFailures int64 `json:"checksumFailures,omitempty"`
+
+	// The number of checksum failures which were already reported when
+	// the operator first saw the instance, or when its statistics have
+	// been reset. Only the failures exceeding it mark the instance as
+	// corrupted, as the older ones may have been handled long before.
+	//
+	// This field is never populated in the instance manager.
+	ChecksumFailuresBaseline int64 `json:"checksumFailuresBaseline,omitempty"`
+
+	// The commit time of the latest transaction replayed by a replica
+	// SELECT pg_last_xact_replay_timestamp()
+	LastReplayTimestamp string `json:"lastReplayTimestamp,omitempty"`
+
+	// The settings chosen by initdb, only populated on the primary
+	InitDBSettings *InitDBSettings `json:"initDBSettings,omitempty"`
+
+	// The parameters managed by the operator which are overridden
+	// in postgresql.auto.conf
+	ConfigurationDrift []string `json:"configurationDrift,omitempty"`
+
+	// The method used the last time this instance, as a former primary,
+	// was resynchronized with the new primary: pg_rewind or pg_basebackup
+	LastResyncMethod string `json:"lastResyncMethod,omitempty"`
+
 	// This field is set when there is an error while extracting the
 	// status of a Pod
 	Error error `json:"-"`
@@ -88,12 +135,50 @@ type PostgresqlStatus struct {
 	InstanceManagerVersion     string `json:"instanceManagerVersion"`
 	InstanceArch               string `json:"instanceArch"`
 
+	// The version of barman-cloud installed in the instance image
+	BarmanCloudVersion string `json:"barmanCloudVersion,omitempty"`
+
+	// The resource usage of the PostgreSQL container, read from its
+	// control group. Not populated when the statistics are not available
+	ResourceUsage *ResourceUsage `json:"resourceUsage,omitempty"`
+
+	// The usage of the volume containing the data directory. Not
+	// populated when the statistics of the file system are not available
+	PgDataVolumeUsage *VolumeUsage `json:"pgDataVolumeUsage,omitempty"`
+
 	// contains the PgStatReplication rows content.
 	ReplicationInfo PgStatReplicationList `json:"replicationInfo,omitempty"`
 	// contains the PgReplicationSlot rows content.
 	ReplicationSlotsInfo PgReplicationSlotList `json:"replicationSlotsInfo,omitempty"`
 }
 
+// InitDBSettings contains the settings chosen by initdb, which can't
+// be changed after the data directory has been created
+type InitDBSettings struct {
+	DataChecksums  bool   `json:"dataChecksums"`
+	Encoding       string `json:"encoding,omitempty"`
+	LocaleCollate  string `json:"localeCollate,omitempty"`
+	LocaleCType    string `json:"localeCType,omitempty"`
+	WalSegmentSize int    `json:"walSegmentSize,omitempty"`
+}
+
+// ResourceUsage contains the resource usage of the PostgreSQL container
+type ResourceUsage struct {
+	// The CPU usage in millicores, not populated until the instance
+	// manager has collected enough samples
+	CPUMillicores *int64 `json:"cpuMillicores,omitempty"`
+	// The memory working set in bytes
+	MemoryBytes int64 `json:"memoryBytes"`
+}
+
+// VolumeUsage contains the usage of the file system of a volume
+type VolumeUsage struct {
+	// The bytes in use
+	UsedBytes int64 `json:"usedBytes"`
+	// The bytes available to the instance
+	AvailableBytes int64 `json:"availableBytes"`
+}
+
 // PgStatReplication contains the replications of replicas as reported by the primary instance
 type PgStatReplication struct {
 	ApplicationName string `json:"applicationName,omitempty"`
@@ -109,6 +194,37 @@ type PgStatReplication struct {
 	SyncPriority    string `json:"syncPriority,omitempty"`
 }
 
+// IsDelayedReplica checks if this instance is a replica applying
+// the received changes with a delay
+func (status PostgresqlStatus) IsDelayedReplica() bool {
+	return !status.IsPrimary && status.MinApplyDelay > 0
+}
+
+// IsReadReplica checks if this instance is a read replica, as marked
+// in the labels of its Pod
+func (status PostgresqlStatus) IsReadReplica() bool {
+	return utils.IsReadReplica(&status.Pod.ObjectMeta)
+}
+
+// IsCorrupted checks if PostgreSQL detected a new checksum failure while
+// reading the data pages of this instance, i.e. one exceeding the baseline
+func (status PostgresqlStatus) IsCorrupted() bool {
+	return status.ChecksumFailures > status.ChecksumFailuresBaseline
+}
+
+// IsPromotable checks if this instance can be elected as the new primary,
+// which is never the case for delayed replicas, read replicas and
+// instances with corrupted data
+func (status PostgresqlStatus) IsPromotable() bool {
+	return !status.IsDelayedReplica() && !status.IsReadReplica() && !status.IsCorrupted()
+}
+
+// GetFailoverPriority gets the priority of this instance when electing
+// a new primary, as set in the annotations of its Pod
+func (status PostgresqlStatus) GetFailoverPriority() int {
+	return utils.GetFailoverPriority(&status.Pod.ObjectMeta)
+}
+
 // AddPod store the Pod inside the status
 func (status *PostgresqlStatus) AddPod(pod corev1.Pod) {
 	status.Pod = pod
@@ -135,6 +251,20 @@ func (status PostgresqlStatus) IsPostgresqlReady() bool {
 	return status.Error == nil
 }
 
+// GetLatestLSN gets the latest LSN reached by this instance: the current
+// write position for a primary, the replayed one for a replica
+func (status PostgresqlStatus) GetLatestLSN() LSN {
+	if status.IsPrimary {
+		return status.CurrentLsn
+	}
+
+	if status.ReplayLsn != "" {
+		return status.ReplayLsn
+	}
+
+	return status.ReceivedLsn
+}
+
 // PgStatReplicationList is a list of PgStatReplication reported by the primary instance
 type PgStatReplicationList []PgStatReplication
 
@@ -265,11 +395,29 @@ func (list *PostgresqlStatusList) Less(i, j int) bool {
 		return false
 	}
 
+	// Delayed replicas, read replicas and corrupted replicas are never
+	// elected as the new primary, so they go after the other replicas
+	switch {
+	case !list.Items[i].IsPromotable() && list.Items[j].IsPromotable():
+		return false
+
+	case list.Items[i].IsPromotable() && !list.Items[j].IsPromotable():
+		return true
+	}
+
 	// Compare received LSN (bigger LSN orders first)
 	if list.Items[i].ReceivedLsn != list.Items[j].ReceivedLsn {
 		return !list.Items[i].ReceivedLsn.Less(list.Items[j].ReceivedLsn)
 	}
 
+	// Among the replicas that received the same WAL, the one with
+	// the higher failover priority is preferred
+	priorityI := list.Items[i].GetFailoverPriority()
+	priorityJ := list.Items[j].GetFailoverPriority()
+	if priorityI != priorityJ {
+		return priorityI > priorityJ
+	}
+
 	// Compare replay LSN (bigger LSN orders first)
 	if list.Items[i].ReplayLsn != list.Items[j].ReplayLsn {
 		return !list.Items[i].ReplayLsn.Less(list.Items[j].ReplayLsn)
@@ -305,6 +453,19 @@ func (list PostgresqlStatusList) IsPodReporting(podname string) bool {
 	return false
 }
 
+// IsRefusingReadWrite checks whether an instance reported that it is not
+// accepting read-write connections, like a replica which is still being
+// promoted. Instances whose status is unknown are not considered refusing them
+func (list PostgresqlStatusList) IsRefusingReadWrite(podName string) bool {
+	for _, item := range list.Items {
+		if item.Pod.Name == podName {
+			return item.Error == nil && item.IsAcceptingReadWrite != nil && !*item.IsAcceptingReadWrite
+		}
+	}
+
+	return false
+}
+
 // IsComplete checks the PostgreSQL status list for Pods which
 // contain errors. Returns true if everything is green and
 // false otherwise
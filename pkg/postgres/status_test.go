@@ -25,6 +25,8 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 )
@@ -133,6 +135,17 @@ var _ = Describe("PostgreSQL status", func() {
 		Expect(podList.InstancesReportingStatus()).To(BeEquivalentTo(2))
 	})
 
+	It("reports the latest LSN depending on the instance role", func() {
+		primary := PostgresqlStatus{IsPrimary: true, CurrentLsn: "0/5000000", ReplayLsn: "0/4000000"}
+		Expect(primary.GetLatestLSN()).To(BeEquivalentTo("0/5000000"))
+
+		replica := PostgresqlStatus{ReceivedLsn: "0/5000000", ReplayLsn: "0/4000000"}
+		Expect(replica.GetLatestLSN()).To(BeEquivalentTo("0/4000000"))
+
+		replica.ReplayLsn = ""
+		Expect(replica.GetLatestLSN()).To(BeEquivalentTo("0/5000000"))
+	})
+
 	Describe("when sorted", func() {
 		sort.Sort(&list)
 
@@ -163,6 +176,189 @@ var _ = Describe("PostgreSQL status", func() {
 	})
 })
 
+var _ = Describe("PostgreSQL status with delayed replicas", func() {
+	list := PostgresqlStatusList{
+		Items: []PostgresqlStatus{
+			{
+				Pod:           corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "server-3"}},
+				ReceivedLsn:   "1/23",
+				MinApplyDelay: 3600000,
+			},
+			{
+				Pod:         corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "server-2"}},
+				ReceivedLsn: "1/21",
+			},
+			{
+				Pod:           corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "server-1"}},
+				IsPrimary:     true,
+				MinApplyDelay: 3600000,
+			},
+		},
+	}
+
+	It("detects the delayed replicas", func() {
+		Expect(PostgresqlStatus{MinApplyDelay: 1000}.IsDelayedReplica()).To(BeTrue())
+		Expect(PostgresqlStatus{}.IsDelayedReplica()).To(BeFalse())
+		Expect(PostgresqlStatus{IsPrimary: true, MinApplyDelay: 1000}.IsDelayedReplica()).To(BeFalse())
+	})
+
+	Describe("when sorted", func() {
+		sort.Sort(&list)
+
+		It("puts the delayed replicas after the other ones", func() {
+			Expect(list.Items[0].Pod.Name).To(Equal("server-1"))
+			Expect(list.Items[1].Pod.Name).To(Equal("server-2"))
+			Expect(list.Items[2].Pod.Name).To(Equal("server-3"))
+		})
+	})
+})
+
+var _ = Describe("PostgreSQL status with read replicas", func() {
+	readReplicaPod := corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   "server-3",
+			Labels: map[string]string{utils.ReadReplicaLabelName: "true"},
+		},
+	}
+	list := PostgresqlStatusList{
+		Items: []PostgresqlStatus{
+			{
+				Pod:         readReplicaPod,
+				ReceivedLsn: "1/23",
+			},
+			{
+				Pod:         corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "server-2"}},
+				ReceivedLsn: "1/21",
+			},
+			{
+				Pod:       corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "server-1"}},
+				IsPrimary: true,
+			},
+		},
+	}
+
+	It("detects the read replicas, which are never promotable", func() {
+		Expect(PostgresqlStatus{Pod: readReplicaPod}.IsReadReplica()).To(BeTrue())
+		Expect(PostgresqlStatus{Pod: readReplicaPod}.IsPromotable()).To(BeFalse())
+		Expect(PostgresqlStatus{}.IsReadReplica()).To(BeFalse())
+		Expect(PostgresqlStatus{}.IsPromotable()).To(BeTrue())
+		Expect(PostgresqlStatus{MinApplyDelay: 1000}.IsPromotable()).To(BeFalse())
+	})
+
+	Describe("when sorted", func() {
+		sort.Sort(&list)
+
+		It("puts the read replicas after the other ones", func() {
+			Expect(list.Items[0].Pod.Name).To(Equal("server-1"))
+			Expect(list.Items[1].Pod.Name).To(Equal("server-2"))
+			Expect(list.Items[2].Pod.Name).To(Equal("server-3"))
+		})
+	})
+})
+
+var _ = Describe("PostgreSQL status with corrupted replicas", func() {
+	list := PostgresqlStatusList{
+		Items: []PostgresqlStatus{
+			{
+				Pod:              corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "server-3"}},
+				ReceivedLsn:      "1/23",
+				ChecksumFailures: 2,
+			},
+			{
+				Pod:         corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "server-2"}},
+				ReceivedLsn: "1/21",
+			},
+			{
+				Pod:       corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "server-1"}},
+				IsPrimary: true,
+			},
+		},
+	}
+
+	It("detects the corrupted instances, which are never promotable", func() {
+		Expect(PostgresqlStatus{ChecksumFailures: 1}.IsCorrupted()).To(BeTrue())
+		Expect(PostgresqlStatus{ChecksumFailures: 1}.IsPromotable()).To(BeFalse())
+		Expect(PostgresqlStatus{}.IsCorrupted()).To(BeFalse())
+	})
+
+	It("ignores the checksum failures within the baseline", func() {
+		Expect(PostgresqlStatus{ChecksumFailures: 3, ChecksumFailuresBaseline: 3}.IsCorrupted()).To(BeFalse())
+		Expect(PostgresqlStatus{ChecksumFailures: 3, ChecksumFailuresBaseline: 3}.IsPromotable()).To(BeTrue())
+		Expect(PostgresqlStatus{ChecksumFailures: 4, ChecksumFailuresBaseline: 3}.IsCorrupted()).To(BeTrue())
+	})
+
+	Describe("when sorted", func() {
+		sort.Sort(&list)
+
+		It("puts the corrupted replicas after the other ones", func() {
+			Expect(list.Items[0].Pod.Name).To(Equal("server-1"))
+			Expect(list.Items[1].Pod.Name).To(Equal("server-2"))
+			Expect(list.Items[2].Pod.Name).To(Equal("server-3"))
+		})
+	})
+})
+
+var _ = Describe("PostgreSQL status with failover priorities", func() {
+	newReplica := func(name, receivedLsn, priority string) PostgresqlStatus {
+		return PostgresqlStatus{
+			Pod: corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Annotations: map[string]string{utils.FailoverPriorityAnnotationName: priority},
+			}},
+			ReceivedLsn: LSN(receivedLsn),
+		}
+	}
+
+	list := PostgresqlStatusList{
+		Items: []PostgresqlStatus{
+			newReplica("server-1", "1/23", "0"),
+			newReplica("server-2", "1/21", "100"),
+			newReplica("server-3", "1/23", "10"),
+		},
+	}
+
+	Describe("when sorted", func() {
+		sort.Sort(&list)
+
+		It("prefers the higher priority among the replicas that received the same WAL", func() {
+			Expect(list.Items[0].Pod.Name).To(Equal("server-3"))
+			Expect(list.Items[1].Pod.Name).To(Equal("server-1"))
+		})
+
+		It("never prefers a replica that received less WAL", func() {
+			Expect(list.Items[2].Pod.Name).To(Equal("server-2"))
+		})
+	})
+})
+
+var _ = Describe("PostgreSQL status of the read-write connections", func() {
+	accepting := true
+	refusing := false
+	list := PostgresqlStatusList{
+		Items: []PostgresqlStatus{
+			{Pod: corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "server-1"}}, IsAcceptingReadWrite: &accepting},
+			{Pod: corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "server-2"}}, IsAcceptingReadWrite: &refusing},
+			{Pod: corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "server-3"}}},
+			{
+				Pod:                  corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "server-4"}},
+				IsAcceptingReadWrite: &refusing,
+				Error:                fmt.Errorf("cannot connect to PostgreSQL"),
+			},
+		},
+	}
+
+	It("detects the instances refusing read-write connections", func() {
+		Expect(list.IsRefusingReadWrite("server-1")).To(BeFalse())
+		Expect(list.IsRefusingReadWrite("server-2")).To(BeTrue())
+	})
+
+	It("doesn't consider refusing them the instances whose status is unknown", func() {
+		Expect(list.IsRefusingReadWrite("server-3")).To(BeFalse())
+		Expect(list.IsRefusingReadWrite("server-4")).To(BeFalse())
+		Expect(list.IsRefusingReadWrite("server-5")).To(BeFalse())
+	})
+})
+
 var _ = Describe("PostgreSQL status real", func() {
 	f, err := os.Open("testdata/lsn_overflow.json")
 	defer func() {
@@ -144,6 +144,21 @@ func MustSegmentFromName(name string) Segment {
 	return result
 }
 
+// SegmentFromLSN retrieves the segment containing the passed LSN,
+// given the timeline and the size of the WAL segments
+func SegmentFromLSN(lsn LSN, tli int32, walSegmentSize int64) (Segment, error) {
+	position, err := lsn.Parse()
+	if err != nil {
+		return Segment{}, err
+	}
+
+	return Segment{
+		Tli: tli,
+		Log: int32(position >> 32),
+		Seg: int32((position & 0xFFFFFFFF) / walSegmentSize),
+	}, nil
+}
+
 // Name gets the name of the segment
 func (segment Segment) Name() string {
 	return fmt.Sprintf("%08X%08X%08X", segment.Tli, segment.Log, segment.Seg)
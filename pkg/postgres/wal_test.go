@@ -188,6 +188,19 @@ var _ = Describe("Segment name parsing and generation", func() {
 				test.start.Name(), test.size, test.version, test.walSize)
 		}
 	})
+
+	It("can find the segment containing an LSN", func() {
+		segment, err := SegmentFromLSN("1/FE000028", 3, DefaultWALSegmentSize)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(segment.Name()).To(Equal("0000000300000001000000FE"))
+
+		segment, err = SegmentFromLSN("0/3000060", 1, 1024*1024*1024)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(segment.Name()).To(Equal("000000010000000000000000"))
+
+		_, err = SegmentFromLSN("not-an-lsn", 1, DefaultWALSegmentSize)
+		Expect(err).To(HaveOccurred())
+	})
 })
 
 var _ = Describe("WAL files checking", func() {
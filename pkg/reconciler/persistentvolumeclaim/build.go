@@ -17,6 +17,7 @@ limitations under the License.
 package persistentvolumeclaim
 
 import (
+	"fmt"
 	"strconv"
 
 	corev1 "k8s.io/api/core/v1"
@@ -34,6 +35,13 @@ type CreateConfiguration struct {
 	NodeSerial int
 	Role       utils.PVCRole
 	Storage    apiv1.StorageConfiguration
+
+	// Tablespace is the tablespace stored in the PVC, and
+	// is only used when the role is PG_TABLESPACE
+	Tablespace *apiv1.TablespaceConfiguration
+
+	// ReadReplica marks the PVC as belonging to a read replica
+	ReadReplica bool
 }
 
 // Build spec of a PVC, given its name and the storage configuration
@@ -44,6 +52,20 @@ func Build(
 ) (*corev1.PersistentVolumeClaim, error) {
 	instanceName := specs.GetInstanceName(cluster.Name, configuration.NodeSerial)
 	pvcName := GetName(cluster, instanceName, configuration.Role)
+	labels := map[string]string{
+		utils.InstanceNameLabelName: instanceName,
+		utils.PvcRoleLabelName:      string(configuration.Role),
+	}
+	if configuration.Role == utils.PVCRolePgTablespace {
+		if configuration.Tablespace == nil {
+			return nil, fmt.Errorf("missing tablespace for PVC with role %s", configuration.Role)
+		}
+		pvcName = GetTablespaceName(instanceName, *configuration.Tablespace)
+		labels[utils.TablespaceNameLabelName] = configuration.Tablespace.Name
+	}
+	if configuration.ReadReplica {
+		labels[utils.ReadReplicaLabelName] = "true"
+	}
 
 	builder := resources.NewPersistentVolumeClaimBuilder().
 		BeginMetadata().
@@ -52,10 +74,7 @@ func Build(
 			specs.ClusterSerialAnnotationName: strconv.Itoa(configuration.NodeSerial),
 			StatusAnnotationName:              configuration.Status,
 		}).
-		WithLabels(map[string]string{
-			utils.InstanceNameLabelName: instanceName,
-			utils.PvcRoleLabelName:      string(configuration.Role),
-		}).
+		WithLabels(labels).
 		WithClusterInheritance(cluster).
 		EndMetadata().
 		WithSpec(configuration.Storage.PersistentVolumeClaimTemplate).
@@ -19,6 +19,7 @@ package persistentvolumeclaim
 import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
@@ -85,4 +86,49 @@ var _ = Describe("PVC Creation", func() {
 		Expect(err).NotTo(HaveOccurred())
 		Expect(pvc.Spec.Resources.Requests.Storage().String()).To(Equal("2Gi"))
 	})
+
+	It("names and labels the PVCs containing tablespaces", func() {
+		tablespace := apiv1.TablespaceConfiguration{
+			Name: "cold_data",
+			Storage: apiv1.StorageConfiguration{
+				Size: "1Gi",
+			},
+		}
+		cluster := &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"},
+			Spec: apiv1.ClusterSpec{
+				Tablespaces: []apiv1.TablespaceConfiguration{tablespace},
+			},
+		}
+		pvc, err := Build(
+			cluster,
+			&CreateConfiguration{
+				Status:     StatusInitializing,
+				NodeSerial: 1,
+				Role:       utils.PVCRolePgTablespace,
+				Storage:    tablespace.Storage,
+				Tablespace: &tablespace,
+			},
+		)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(pvc.Name).To(Equal("cluster-example-1-tbs-cold-data"))
+		Expect(pvc.Labels[utils.TablespaceNameLabelName]).To(Equal("cold_data"))
+		Expect(getExpectedInstancePVCNames(cluster, "cluster-example-1")).To(ConsistOf(
+			"cluster-example-1", "cluster-example-1-tbs-cold-data"))
+	})
+
+	It("labels the PVCs belonging to a read replica", func() {
+		pvc, err := Build(
+			&apiv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"}},
+			&CreateConfiguration{
+				Status:      StatusInitializing,
+				NodeSerial:  4,
+				Role:        utils.PVCRolePgData,
+				Storage:     apiv1.StorageConfiguration{Size: "1Gi"},
+				ReadReplica: true,
+			},
+		)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(utils.IsReadReplica(&pvc.ObjectMeta)).To(BeTrue())
+	})
 })
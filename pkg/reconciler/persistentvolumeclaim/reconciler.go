@@ -89,14 +89,22 @@ func reconcileResourceRequests(
 }
 
 func getStorageConfiguration(
-	role utils.PVCRole,
+	pvc *corev1.PersistentVolumeClaim,
 	cluster *apiv1.Cluster,
 ) (*apiv1.StorageConfiguration, error) {
+	role := utils.PVCRole(pvc.Labels[utils.PvcRoleLabelName])
 	switch role {
 	case utils.PVCRolePgData:
 		return &cluster.Spec.StorageConfiguration, nil
 	case utils.PVCRolePgWal:
 		return cluster.Spec.WalStorage, nil
+	case utils.PVCRolePgTablespace:
+		tablespaceName := pvc.Labels[utils.TablespaceNameLabelName]
+		tablespace := cluster.GetTablespace(tablespaceName)
+		if tablespace == nil {
+			return nil, fmt.Errorf("unknown tablespace: %s", tablespaceName)
+		}
+		return &tablespace.Storage, nil
 	default:
 		return nil, fmt.Errorf("unknown pvcRole: %s", string(role))
 	}
@@ -109,9 +117,7 @@ func reconcilePVCQuantity(
 	pvc *corev1.PersistentVolumeClaim,
 ) error {
 	contextLogger := log.FromContext(ctx)
-	pvcRole := utils.PVCRole(pvc.Labels[utils.PvcRoleLabelName])
-
-	storageConfiguration, err := getStorageConfiguration(pvcRole, cluster)
+	storageConfiguration, err := getStorageConfiguration(pvc, cluster)
 	if err != nil {
 		contextLogger.Error(err,
 			"encountered an error while trying to obtain the storage configuration",
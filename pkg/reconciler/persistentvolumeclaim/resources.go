@@ -24,7 +24,8 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 )
 
-// GetName builds the name for a given PVC of the instance
+// GetName builds the name for a given PVC of the instance. The name of
+// the PVCs containing tablespaces is built by GetTablespaceName
 func GetName(cluster *apiv1.Cluster, instanceName string, role utils.PVCRole) string {
 	pvcName := instanceName
 	if role == utils.PVCRolePgWal {
@@ -33,6 +34,11 @@ func GetName(cluster *apiv1.Cluster, instanceName string, role utils.PVCRole) st
 	return pvcName
 }
 
+// GetTablespaceName builds the name of the PVC containing a tablespace of the instance
+func GetTablespaceName(instanceName string, tablespace apiv1.TablespaceConfiguration) string {
+	return instanceName + tablespace.GetVolumeSuffix()
+}
+
 // FilterByInstance returns all the corev1.PersistentVolumeClaim that are used inside the podSpec
 func FilterByInstance(
 	pvcs []corev1.PersistentVolumeClaim,
@@ -93,6 +99,10 @@ func getExpectedInstancePVCNames(cluster *apiv1.Cluster, instanceName string) []
 		names = append(names, instanceName+cluster.GetWalArchiveVolumeSuffix())
 	}
 
+	for _, tablespace := range cluster.Spec.Tablespaces {
+		names = append(names, GetTablespaceName(instanceName, tablespace))
+	}
+
 	return names
 }
 
@@ -77,5 +77,28 @@ var _ = Describe("PVC detection", func() {
 			clusterName + "-1",
 		}))
 		Expect(cluster.Status.UnusablePVC).Should(BeEmpty())
+		Expect(cluster.Status.StorageClasses).Should(BeNil())
+	})
+
+	It("reports the storage classes of the PVCs", func() {
+		clusterName := "myCluster"
+		fast, slow := "fast", "slow"
+		pvcs := []corev1.PersistentVolumeClaim{
+			makePVC(clusterName, "1", false),
+			makePVC(clusterName, "2", false),
+			makePVC(clusterName, "3", false),
+			makePVC(clusterName, "4", false),
+		}
+		pvcs[0].Spec.StorageClassName = &slow
+		pvcs[1].Spec.StorageClassName = &fast
+		pvcs[2].Spec.StorageClassName = &slow
+		cluster := &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: clusterName,
+			},
+		}
+		EnrichStatus(context.TODO(), cluster, nil, nil, pvcs)
+
+		Expect(cluster.Status.StorageClasses).Should(Equal([]string{fast, slow}))
 	})
 })
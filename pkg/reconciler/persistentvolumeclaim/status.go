@@ -28,6 +28,7 @@ import (
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/stringset"
 )
 
 // PVCStatus describes the PVC phase
@@ -102,6 +103,7 @@ func EnrichStatus(
 	// First we iterate over all the PVCs building the instances map.
 	// It contains the PVCSs grouped by instance serial
 	instances := make(map[string][]corev1.PersistentVolumeClaim)
+	storageClasses := stringset.New()
 	for _, pvc := range pvcList {
 		// Ignore PVCs that is in the wrong state
 		if pvc.Status.Phase != corev1.ClaimPending &&
@@ -114,6 +116,10 @@ func EnrichStatus(
 			continue
 		}
 
+		if pvc.Spec.StorageClassName != nil && *pvc.Spec.StorageClassName != "" {
+			storageClasses.Put(*pvc.Spec.StorageClassName)
+		}
+
 		// Detect the instance serial number.
 		// If it returns an error the PVC is ill-formed and we ignore it
 		serial, err := specs.GetNodeSerial(pvc.ObjectMeta)
@@ -142,6 +148,14 @@ func EnrichStatus(
 	cluster.Status.DanglingPVC = result.getSorted(dangling)
 	cluster.Status.HealthyPVC = result.getSorted(healthy)
 	cluster.Status.UnusablePVC = result.getSorted(unusable)
+
+	// An empty list is kept nil, as it would be after a round trip
+	// to the API server, to not detect a change in the status
+	cluster.Status.StorageClasses = nil
+	if storageClasses.Len() > 0 {
+		cluster.Status.StorageClasses = storageClasses.ToList()
+		sort.Strings(cluster.Status.StorageClasses)
+	}
 }
 
 func classifyPVC(
@@ -0,0 +1,84 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package specs
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+)
+
+// CertManagerGroupVersion is the API group and version of the
+// cert-manager resources managed by the operator
+var CertManagerGroupVersion = schema.GroupVersion{Group: "cert-manager.io", Version: "v1"}
+
+// NewCertManagerIssuer creates an empty object to retrieve the
+// cert-manager issuer signing the server certificate of a cluster
+func NewCertManagerIssuer(issuerRef apiv1.CertificateIssuerReference) *unstructured.Unstructured {
+	issuer := &unstructured.Unstructured{}
+	issuer.SetGroupVersionKind(CertManagerGroupVersion.WithKind(issuerRef.GetKind()))
+	return issuer
+}
+
+// CreateServerCertificate creates the cert-manager Certificate requesting
+// the server certificate of a cluster to the issuer set in the cluster.
+// The certificate is stored in the server TLS secret of the cluster
+// together with the CA of the issuer
+func CreateServerCertificate(cluster apiv1.Cluster) *unstructured.Unstructured {
+	issuerRef := cluster.GetServerIssuerRef()
+
+	var dnsNames []interface{}
+	for _, name := range cluster.GetClusterAltDNSNames() {
+		dnsNames = append(dnsNames, name)
+	}
+
+	certificate := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"spec": map[string]interface{}{
+				"secretName": cluster.GetServerTLSSecretName(),
+				"commonName": cluster.GetServiceReadWriteName(),
+				"dnsNames":   dnsNames,
+				"usages":     []interface{}{"server auth", "digital signature", "key encipherment"},
+				// The instances reload the certificate when it is renewed
+				"secretTemplate": map[string]interface{}{
+					"labels": map[string]interface{}{
+						WatchedLabelName: "true",
+					},
+				},
+				"issuerRef": map[string]interface{}{
+					"group": CertManagerGroupVersion.Group,
+					"kind":  issuerRef.GetKind(),
+					"name":  issuerRef.Name,
+				},
+			},
+		},
+	}
+
+	meta := metav1.ObjectMeta{}
+	cluster.SetInheritedDataAndOwnership(&meta)
+
+	certificate.SetGroupVersionKind(CertManagerGroupVersion.WithKind("Certificate"))
+	certificate.SetName(cluster.GetServerTLSSecretName())
+	certificate.SetNamespace(cluster.Namespace)
+	certificate.SetLabels(meta.Labels)
+	certificate.SetAnnotations(meta.Annotations)
+	certificate.SetOwnerReferences(meta.OwnerReferences)
+
+	return certificate
+}
@@ -0,0 +1,72 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package specs
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("cert-manager server certificate", func() {
+	cluster := apiv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cluster-example",
+			Namespace: "default",
+		},
+		Spec: apiv1.ClusterSpec{
+			Certificates: &apiv1.CertificatesConfiguration{
+				ServerIssuerRef: &apiv1.CertificateIssuerReference{
+					Name: "ca-issuer",
+					Kind: apiv1.CertificateClusterIssuerKind,
+				},
+			},
+		},
+	}
+
+	It("stores the certificate in the server TLS secret", func() {
+		certificate := CreateServerCertificate(cluster)
+		Expect(certificate.GetKind()).To(Equal("Certificate"))
+		Expect(certificate.GetName()).To(Equal("cluster-example-server"))
+		Expect(certificate.GetNamespace()).To(Equal("default"))
+
+		secretName, _, _ := unstructured.NestedString(certificate.Object, "spec", "secretName")
+		Expect(secretName).To(Equal(cluster.GetServerTLSSecretName()))
+		commonName, _, _ := unstructured.NestedString(certificate.Object, "spec", "commonName")
+		Expect(commonName).To(Equal("cluster-example-rw"))
+		dnsNames, _, _ := unstructured.NestedStringSlice(certificate.Object, "spec", "dnsNames")
+		Expect(dnsNames).To(Equal(cluster.GetClusterAltDNSNames()))
+	})
+
+	It("references the issuer of the cluster", func() {
+		certificate := CreateServerCertificate(cluster)
+		issuerRef, _, _ := unstructured.NestedStringMap(certificate.Object, "spec", "issuerRef")
+		Expect(issuerRef).To(Equal(map[string]string{
+			"group": "cert-manager.io",
+			"kind":  "ClusterIssuer",
+			"name":  "ca-issuer",
+		}))
+	})
+
+	It("uses the server certificate secret as CA secret", func() {
+		Expect(cluster.GetServerCASecretName()).To(Equal(cluster.GetServerTLSSecretName()))
+	})
+})
@@ -33,6 +33,10 @@ const (
 	// postInitApplicationSQLRefsFolder points to the folder of
 	// postInitApplicationSQL files in the primary job with initdb.
 	postInitApplicationSQLRefsFolder = "/etc/post-init-application-sql"
+
+	// MajorUpgradeJobRole is the role of the job upgrading the data
+	// directory of the primary instance to a new PostgreSQL major version
+	MajorUpgradeJobRole = "major-upgrade"
 )
 
 // CreatePrimaryJobViaInitdb creates a new primary instance in a Pod
@@ -198,6 +202,43 @@ func JoinReplicaInstance(cluster apiv1.Cluster, nodeSerial int) *batchv1.Job {
 	return createPrimaryJob(cluster, nodeSerial, "join", initCommand)
 }
 
+// CreateMajorUpgradeJob creates a job upgrading the data directory of the
+// primary instance to the PostgreSQL major version of the cluster image via
+// pg_upgrade. The binaries of the previous major version are copied from the
+// old image by an init container
+func CreateMajorUpgradeJob(cluster apiv1.Cluster, nodeSerial int, oldImage string) *batchv1.Job {
+	upgradeCommand := []string{
+		"/controller/manager",
+		"instance",
+		"upgrade",
+		"execute",
+	}
+
+	upgradeCommand = append(upgradeCommand, buildCommonInitJobFlags(cluster)...)
+
+	job := createPrimaryJob(cluster, nodeSerial, MajorUpgradeJobRole, upgradeCommand)
+
+	prepareContainer := corev1.Container{
+		Name:            "prepare",
+		Image:           oldImage,
+		ImagePullPolicy: cluster.Spec.ImagePullPolicy,
+		Command: []string{
+			"/controller/manager",
+			"instance",
+			"upgrade",
+			"prepare",
+		},
+		VolumeMounts:    createPostgresVolumeMounts(cluster),
+		Resources:       cluster.Spec.Resources,
+		SecurityContext: CreateContainerSecurityContext(),
+	}
+	addManagerLoggingOptions(cluster, &prepareContainer)
+
+	job.Spec.Template.Spec.InitContainers = append(job.Spec.Template.Spec.InitContainers, prepareContainer)
+
+	return job
+}
+
 func buildCommonInitJobFlags(cluster apiv1.Cluster) []string {
 	var flags []string
 
@@ -256,7 +297,7 @@ func createPrimaryJob(cluster apiv1.Cluster, nodeSerial int, role string, initCo
 					SecurityContext:    CreatePodSecurityContext(cluster.GetPostgresUID(), cluster.GetPostgresGID()),
 					Affinity:           CreateAffinitySection(cluster.Name, cluster.Spec.Affinity),
 					Tolerations:        cluster.Spec.Affinity.Tolerations,
-					ServiceAccountName: cluster.Name,
+					ServiceAccountName: cluster.GetServiceAccountName(),
 					RestartPolicy:      corev1.RestartPolicyNever,
 					NodeSelector:       cluster.Spec.Affinity.NodeSelector,
 				},
@@ -165,3 +165,24 @@ var _ = Describe("Job created via InitDB", func() {
 		Expect(job.Spec.Template.Spec.Containers[0].Command).Should(ContainElement(postInitApplicationSQLRefsFolder))
 	})
 })
+
+var _ = Describe("Job created for a major upgrade", func() {
+	It("runs pg_upgrade with the binaries copied from the old image", func() {
+		cluster := apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster-example",
+				Namespace: "default",
+			},
+			Spec: apiv1.ClusterSpec{
+				ImageName: "postgres:15.1",
+			},
+		}
+		job := CreateMajorUpgradeJob(cluster, 1, "postgres:14.6")
+		Expect(job.Name).To(Equal("cluster-example-1-major-upgrade"))
+		Expect(job.Spec.Template.Spec.Containers[0].Image).To(Equal("postgres:15.1"))
+		Expect(job.Spec.Template.Spec.Containers[0].Command).To(ContainElements("upgrade", "execute"))
+		Expect(job.Spec.Template.Spec.InitContainers).To(HaveLen(2))
+		Expect(job.Spec.Template.Spec.InitContainers[1].Image).To(Equal("postgres:14.6"))
+		Expect(job.Spec.Template.Spec.InitContainers[1].Command).To(ContainElements("upgrade", "prepare"))
+	})
+})
@@ -0,0 +1,148 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package specs
+
+import (
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+const (
+	// ParentScheduledMaintenanceLabelName is the label applied to the jobs
+	// created by a scheduled maintenance
+	ParentScheduledMaintenanceLabelName = MetadataNamespace + "/scheduled-maintenance"
+
+	// maintenanceJobRole is the role of the jobs executing a scheduled maintenance
+	maintenanceJobRole = "maintenance"
+)
+
+// GetMaintenanceHost gets the service used to connect to the instance
+// that should execute the statements of a scheduled maintenance
+func GetMaintenanceHost(cluster apiv1.Cluster, maintenance apiv1.ScheduledMaintenance) string {
+	// ReadyInstances includes the primary, so we need at least
+	// another one to have a ready standby
+	if maintenance.GetTarget() == apiv1.MaintenanceTargetPreferStandby && cluster.Status.ReadyInstances > 1 {
+		return cluster.GetServiceReadOnlyName()
+	}
+
+	return cluster.GetServiceReadWriteName()
+}
+
+// CreateScheduledMaintenanceJob creates the job executing the statements
+// of a scheduled maintenance, connecting as superuser through the cluster
+// services
+func CreateScheduledMaintenanceJob(
+	cluster apiv1.Cluster,
+	maintenance apiv1.ScheduledMaintenance,
+	name string,
+) *batchv1.Job {
+	command := []string{"psql", "-v", "ON_ERROR_STOP=1"}
+	for _, statement := range maintenance.Spec.SQL {
+		command = append(command, "-c", statement)
+	}
+
+	superuserSecretName := cluster.GetSuperuserSecretName()
+	env := []corev1.EnvVar{
+		{
+			Name:  "PGHOST",
+			Value: GetMaintenanceHost(cluster, maintenance),
+		},
+		{
+			Name:  "PGDATABASE",
+			Value: maintenance.GetDatabase(),
+		},
+		{
+			Name:  "PGAPPNAME",
+			Value: name,
+		},
+		{
+			Name:  "PGSSLMODE",
+			Value: "require",
+		},
+		{
+			Name: "PGUSER",
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: superuserSecretName},
+					Key:                  corev1.BasicAuthUsernameKey,
+				},
+			},
+		},
+		{
+			Name: "PGPASSWORD",
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: superuserSecretName},
+					Key:                  corev1.BasicAuthPasswordKey,
+				},
+			},
+		},
+	}
+
+	// A failed execution is not retried, the next one
+	// will happen at the following schedule
+	var backoffLimit int32
+
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: maintenance.Namespace,
+			Labels: map[string]string{
+				utils.ClusterLabelName:              cluster.Name,
+				ParentScheduledMaintenanceLabelName: maintenance.Name,
+			},
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: &backoffLimit,
+			Template: corev1.PodTemplateSpec{
+				// The cluster label is not set on the pod, as it would be
+				// selected by the anti-affinity rules of the instances
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						ParentScheduledMaintenanceLabelName: maintenance.Name,
+					},
+				},
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name:            maintenanceJobRole,
+							Image:           cluster.GetImageName(),
+							ImagePullPolicy: cluster.Spec.ImagePullPolicy,
+							Env:             env,
+							Command:         command,
+							SecurityContext: CreateContainerSecurityContext(),
+						},
+					},
+					SecurityContext:    CreatePodSecurityContext(cluster.GetPostgresUID(), cluster.GetPostgresGID()),
+					Tolerations:        cluster.Spec.Affinity.Tolerations,
+					ServiceAccountName: cluster.GetServiceAccountName(),
+					RestartPolicy:      corev1.RestartPolicyNever,
+					NodeSelector:       cluster.Spec.Affinity.NodeSelector,
+				},
+			},
+		},
+	}
+
+	utils.LabelJobRole(&job.ObjectMeta, maintenanceJobRole)
+	utils.SetAsOwnedBy(&job.ObjectMeta, maintenance.ObjectMeta, maintenance.TypeMeta)
+
+	return job
+}
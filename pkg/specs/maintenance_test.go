@@ -0,0 +1,76 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package specs
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Scheduled maintenance jobs", func() {
+	cluster := apiv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cluster-example",
+			Namespace: "default",
+		},
+	}
+
+	maintenance := apiv1.ScheduledMaintenance{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "vacuum",
+			Namespace: "default",
+		},
+		Spec: apiv1.ScheduledMaintenanceSpec{
+			Cluster: apiv1.LocalObjectReference{Name: "cluster-example"},
+			SQL:     []string{"VACUUM ANALYZE", "REINDEX DATABASE postgres"},
+		},
+	}
+
+	It("connects to the primary by default", func() {
+		Expect(GetMaintenanceHost(cluster, maintenance)).To(Equal("cluster-example-rw"))
+	})
+
+	It("connects to a standby when preferred and available", func() {
+		standbyMaintenance := maintenance.DeepCopy()
+		standbyMaintenance.Spec.Target = apiv1.MaintenanceTargetPreferStandby
+
+		readyCluster := cluster.DeepCopy()
+		readyCluster.Status.ReadyInstances = 1
+		Expect(GetMaintenanceHost(*readyCluster, *standbyMaintenance)).To(Equal("cluster-example-rw"))
+
+		readyCluster.Status.ReadyInstances = 3
+		Expect(GetMaintenanceHost(*readyCluster, *standbyMaintenance)).To(Equal("cluster-example-ro"))
+	})
+
+	It("executes every statement stopping at the first error", func() {
+		job := CreateScheduledMaintenanceJob(cluster, maintenance, "vacuum-1")
+		Expect(job.Name).To(Equal("vacuum-1"))
+		Expect(job.Namespace).To(Equal("default"))
+		Expect(*job.Spec.BackoffLimit).To(BeZero())
+		Expect(job.Labels).To(HaveKeyWithValue(ParentScheduledMaintenanceLabelName, "vacuum"))
+		Expect(job.Spec.Template.Spec.Containers).To(HaveLen(1))
+		Expect(job.Spec.Template.Spec.Containers[0].Command).To(Equal([]string{
+			"psql", "-v", "ON_ERROR_STOP=1",
+			"-c", "VACUUM ANALYZE",
+			"-c", "REINDEX DATABASE postgres",
+		}))
+	})
+})
@@ -19,6 +19,8 @@ limitations under the License.
 package pgbouncer
 
 import (
+	"fmt"
+
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -44,6 +46,10 @@ const (
 
 	// DefaultPgbouncerImage is the name of the pgbouncer image used by default
 	DefaultPgbouncerImage = "ghcr.io/cloudnative-pg/pgbouncer:1.18.0"
+
+	// ServerHostEnvVar is the environment variable containing the name of the
+	// cluster service PgBouncer connects to
+	ServerHostEnvVar = "POOLER_SERVER_HOST"
 )
 
 // Deployment create the deployment of pgbouncer, given
@@ -51,7 +57,7 @@ const (
 func Deployment(pooler *apiv1.Pooler,
 	cluster *apiv1.Cluster,
 ) (*appsv1.Deployment, error) {
-	poolerHash, err := hash.ComputeHash(pooler.Spec)
+	poolerHash, err := DeploymentHash(pooler, cluster)
 	if err != nil {
 		return nil, err
 	}
@@ -112,6 +118,7 @@ func Deployment(pooler *apiv1.Pooler,
 		}, true).
 		WithContainerEnv("pgbouncer", corev1.EnvVar{Name: "NAMESPACE", Value: pooler.Namespace}, true).
 		WithContainerEnv("pgbouncer", corev1.EnvVar{Name: "POOLER_NAME", Value: pooler.Name}, true).
+		WithContainerEnv("pgbouncer", corev1.EnvVar{Name: ServerHostEnvVar, Value: ServerHost(pooler, cluster)}, true).
 		WithContainerSecurityContext("pgbouncer", specs.CreateContainerSecurityContext(), true).
 		WithServiceAccountName(pooler.Name, true).
 		WithReadinessProbe("pgbouncer", &corev1.Probe{
@@ -149,3 +156,30 @@ func Deployment(pooler *apiv1.Pooler,
 		},
 	}, nil
 }
+
+// ServerHost gets the name of the cluster service the pooler connects to
+func ServerHost(pooler *apiv1.Pooler, cluster *apiv1.Cluster) string {
+	if pooler.Spec.Type == apiv1.PoolerTypeRO {
+		return cluster.GetServiceReadOnlyName()
+	}
+	return cluster.GetServiceReadWriteName()
+}
+
+// DeploymentHash computes the hash of the pooler deployment, which changes
+// when the pooler specification or the name of the cluster service change.
+// The service name is only included when it has been customized, so that
+// the deployments of the existing poolers are not rolled out
+func DeploymentHash(pooler *apiv1.Pooler, cluster *apiv1.Cluster) (string, error) {
+	serverHost := ServerHost(pooler, cluster)
+	if serverHost == fmt.Sprintf("%s-%s", cluster.Name, pooler.Spec.Type) {
+		return hash.ComputeHash(pooler.Spec)
+	}
+
+	return hash.ComputeHash(struct {
+		Spec       apiv1.PoolerSpec
+		ServerHost string
+	}{
+		Spec:       pooler.Spec,
+		ServerHost: serverHost,
+	})
+}
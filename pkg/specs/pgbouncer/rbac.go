@@ -102,5 +102,5 @@ func Role(pooler *apiv1.Pooler) *v1.Role {
 
 // RoleBinding creates a role binding for a given pooler
 func RoleBinding(pooler *apiv1.Pooler) v1.RoleBinding {
-	return specs.CreateRoleBinding(pooler.ObjectMeta)
+	return specs.CreateRoleBinding(pooler.ObjectMeta, pooler.Name)
 }
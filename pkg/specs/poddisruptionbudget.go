@@ -30,10 +30,10 @@ import (
 func BuildReplicasPodDisruptionBudget(cluster *apiv1.Cluster) *policyv1.PodDisruptionBudget {
 	// We should ensure that in a cluster of n instances,
 	// with n-1 replicas, at least n-2 are always available
-	if cluster == nil || cluster.Spec.Instances < 3 {
+	if cluster == nil || cluster.GetDesiredInstances() < 3 {
 		return nil
 	}
-	minAvailableReplicas := cluster.Spec.Instances - 2
+	minAvailableReplicas := cluster.GetDesiredInstances() - 2
 	allReplicasButOne := intstr.FromInt(minAvailableReplicas)
 
 	return &policyv1.PodDisruptionBudget{
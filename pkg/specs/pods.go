@@ -51,6 +51,19 @@ const (
 	// latest required restart time
 	ClusterReloadAnnotationName = MetadataNamespace + "/reloadedAt"
 
+	// ClusterCertificatesRotationAnnotationName is the name of the annotation
+	// requesting the operator to reissue the certificates it generated.
+	// A new rotation is requested each time its value changes
+	ClusterCertificatesRotationAnnotationName = MetadataNamespace + "/rotateCertificates"
+
+	// ClusterTimelineLabelName is the name of the label containing the
+	// PostgreSQL timeline the instance is currently on
+	ClusterTimelineLabelName = MetadataNamespace + "/timeline"
+
+	// ClusterLSNAnnotationName is the name of the annotation containing the
+	// latest LSN reported by the instance
+	ClusterLSNAnnotationName = MetadataNamespace + "/currentLsn"
+
 	// ClusterRoleLabelName label is applied to Pods to mark primary ones
 	ClusterRoleLabelName = "role"
 
@@ -182,6 +195,19 @@ func createPostgresContainers(cluster apiv1.Cluster, envConfig EnvConfig) []core
 					},
 				},
 			},
+			// The kubelet sends the termination signal only after this hook
+			// completes, and the shutdown of PostgreSQL is handled there
+			Lifecycle: &corev1.Lifecycle{
+				PreStop: &corev1.LifecycleHandler{
+					Exec: &corev1.ExecAction{
+						Command: []string{
+							"/controller/manager",
+							"instance",
+							"prestop",
+						},
+					},
+				},
+			},
 			Command: []string{
 				"/controller/manager",
 				"instance",
@@ -326,10 +352,52 @@ func CreatePodSecurityContext(user, group int64) *corev1.PodSecurityContext {
 	}
 }
 
+// configurationHashSource is the configuration managed by the operator
+// which the instances are running with
+type configurationHashSource struct {
+	Parameters          map[string]string
+	PgHBA               []string
+	AdditionalLibraries []string
+	LDAP                *apiv1.LDAPConfig
+	Certificates        *apiv1.CertificatesConfiguration
+}
+
+// ComputeConfigurationHash computes the hash of the configuration managed
+// by the operator, which is used to detect the instances running with an
+// outdated one
+func ComputeConfigurationHash(cluster apiv1.Cluster) string {
+	hashValue, _ := hash.ComputeHash(configurationHashSource{
+		Parameters:          cluster.Spec.PostgresConfiguration.Parameters,
+		PgHBA:               cluster.Spec.PostgresConfiguration.PgHBA,
+		AdditionalLibraries: cluster.GetAdditionalSharedPreloadLibraries(),
+		LDAP:                cluster.Spec.PostgresConfiguration.LDAP,
+		Certificates:        cluster.Spec.Certificates,
+	})
+	return hashValue
+}
+
+// sidecarsHashSource contains the sidecars of the instances and
+// their volumes, as declared in the cluster
+type sidecarsHashSource struct {
+	Sidecars       []corev1.Container
+	SidecarVolumes []corev1.Volume
+}
+
+// ComputeSidecarsHash computes the hash of the sidecars of the instances
+// and of their volumes, which is used to detect the instances running
+// with outdated ones
+func ComputeSidecarsHash(cluster apiv1.Cluster) string {
+	hashValue, _ := hash.ComputeHash(sidecarsHashSource{
+		Sidecars:       cluster.Spec.Sidecars,
+		SidecarVolumes: cluster.Spec.SidecarVolumes,
+	})
+	return hashValue
+}
+
 // PodWithExistingStorage create a new instance with an existing storage
 func PodWithExistingStorage(cluster apiv1.Cluster, nodeSerial int) *corev1.Pod {
 	podName := GetInstanceName(cluster.Name, nodeSerial)
-	gracePeriod := int64(cluster.GetMaxStopDelay())
+	gracePeriod := cluster.GetTerminationGracePeriod()
 
 	envConfig := CreatePodEnvConfig(cluster, podName)
 
@@ -342,8 +410,10 @@ func PodWithExistingStorage(cluster apiv1.Cluster, nodeSerial int) *corev1.Pod {
 				utils.PodRoleLabelName:      string(utils.PodRoleInstance),
 			},
 			Annotations: map[string]string{
-				ClusterSerialAnnotationName:    strconv.Itoa(nodeSerial),
-				utils.PodEnvHashAnnotationName: envConfig.Hash,
+				ClusterSerialAnnotationName:         strconv.Itoa(nodeSerial),
+				utils.PodEnvHashAnnotationName:      envConfig.Hash,
+				utils.PodConfigHashAnnotationName:   ComputeConfigurationHash(cluster),
+				utils.PodSidecarsHashAnnotationName: ComputeSidecarsHash(cluster),
 			},
 			Name:      podName,
 			Namespace: cluster.Namespace,
@@ -354,17 +424,25 @@ func PodWithExistingStorage(cluster apiv1.Cluster, nodeSerial int) *corev1.Pod {
 			InitContainers: []corev1.Container{
 				createBootstrapContainer(cluster),
 			},
-			Containers:                    createPostgresContainers(cluster, envConfig),
-			Volumes:                       createPostgresVolumes(cluster, podName),
+			Containers: append(
+				createPostgresContainers(cluster, envConfig),
+				cluster.Spec.Sidecars...),
+			Volumes: append(
+				createPostgresVolumes(cluster, podName),
+				cluster.Spec.SidecarVolumes...),
 			SecurityContext:               CreatePodSecurityContext(cluster.GetPostgresUID(), cluster.GetPostgresGID()),
 			Affinity:                      CreateAffinitySection(cluster.Name, cluster.Spec.Affinity),
 			Tolerations:                   cluster.Spec.Affinity.Tolerations,
-			ServiceAccountName:            cluster.Name,
+			ServiceAccountName:            cluster.GetServiceAccountName(),
 			NodeSelector:                  cluster.Spec.Affinity.NodeSelector,
 			TerminationGracePeriodSeconds: &gracePeriod,
 		},
 	}
 
+	if cluster.Status.MountedObjectsHash != "" {
+		pod.Annotations[utils.PodMountedObjectsHashAnnotationName] = cluster.Status.MountedObjectsHash
+	}
+
 	if utils.IsAnnotationAppArmorPresent(cluster.Annotations) {
 		utils.AnnotateAppArmor(&pod.ObjectMeta, cluster.Annotations)
 	}
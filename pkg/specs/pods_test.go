@@ -24,6 +24,7 @@ import (
 
 	v1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -58,6 +59,30 @@ var _ = Describe("The PostgreSQL security context", func() {
 	})
 })
 
+var _ = Describe("Instance shutdown", func() {
+	cluster := v1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cluster-example",
+			Namespace: "default",
+		},
+		Spec: v1.ClusterSpec{
+			MaxStopDelay:              60,
+			ShutdownCheckpointTimeout: 20,
+		},
+	}
+
+	It("includes the shutdown checkpoint in the termination grace period", func() {
+		pod := PodWithExistingStorage(cluster, 1)
+		Expect(*pod.Spec.TerminationGracePeriodSeconds).To(BeEquivalentTo(80))
+	})
+
+	It("prepares the shutdown with a pre-stop hook", func() {
+		pod := PodWithExistingStorage(cluster, 1)
+		Expect(pod.Spec.Containers[0].Lifecycle.PreStop.Exec.Command).To(
+			Equal([]string{"/controller/manager", "instance", "prestop"}))
+	})
+})
+
 var _ = Describe("Create affinity section", func() {
 	clusterName := "cluster-test"
 
@@ -424,3 +449,80 @@ var _ = Describe("EnvConfig", func() {
 		})
 	})
 })
+
+var _ = Describe("Sidecars", func() {
+	cluster := v1.Cluster{
+		Spec: v1.ClusterSpec{
+			Sidecars: []corev1.Container{
+				{
+					Name:         "log-agent",
+					Image:        "fluent-bit:2.1",
+					VolumeMounts: []corev1.VolumeMount{{Name: "agent-config", MountPath: "/etc/agent"}},
+				},
+			},
+			SidecarVolumes: []corev1.Volume{
+				{
+					Name: "agent-config",
+					VolumeSource: corev1.VolumeSource{
+						ConfigMap: &corev1.ConfigMapVolumeSource{
+							LocalObjectReference: corev1.LocalObjectReference{Name: "agent-config"},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	It("are added to the Pods after the postgres container", func() {
+		pod := PodWithExistingStorage(cluster, 1)
+		Expect(pod.Spec.Containers).To(HaveLen(2))
+		Expect(pod.Spec.Containers[0].Name).To(Equal(PostgresContainerName))
+		Expect(pod.Spec.Containers[1].Name).To(Equal("log-agent"))
+		Expect(pod.Spec.Volumes).To(ContainElement(cluster.Spec.SidecarVolumes[0]))
+		Expect(pod.Annotations).To(HaveKeyWithValue(
+			utils.PodSidecarsHashAnnotationName, ComputeSidecarsHash(cluster)))
+	})
+
+	It("have a hash changing with their definition", func() {
+		Expect(ComputeSidecarsHash(*cluster.DeepCopy())).To(Equal(ComputeSidecarsHash(cluster)))
+
+		otherCluster := cluster.DeepCopy()
+		otherCluster.Spec.Sidecars[0].Image = "fluent-bit:2.2"
+		Expect(ComputeSidecarsHash(*otherCluster)).ToNot(Equal(ComputeSidecarsHash(cluster)))
+	})
+})
+
+var _ = Describe("Configuration hash", func() {
+	cluster := v1.Cluster{
+		Spec: v1.ClusterSpec{
+			PostgresConfiguration: v1.PostgresConfiguration{
+				Parameters: map[string]string{"work_mem": "4MB"},
+				PgHBA:      []string{"host all all 10.0.0.0/8 md5"},
+			},
+		},
+	}
+
+	It("is stamped on the Pods", func() {
+		pod := PodWithExistingStorage(cluster, 1)
+		Expect(pod.Annotations).To(HaveKeyWithValue(
+			utils.PodConfigHashAnnotationName, ComputeConfigurationHash(cluster)))
+	})
+
+	It("doesn't change when the configuration doesn't", func() {
+		Expect(ComputeConfigurationHash(*cluster.DeepCopy())).To(Equal(ComputeConfigurationHash(cluster)))
+
+		otherCluster := cluster.DeepCopy()
+		otherCluster.Spec.Instances = 5
+		Expect(ComputeConfigurationHash(*otherCluster)).To(Equal(ComputeConfigurationHash(cluster)))
+	})
+
+	It("changes with the configuration", func() {
+		otherCluster := cluster.DeepCopy()
+		otherCluster.Spec.PostgresConfiguration.Parameters["work_mem"] = "8MB"
+		Expect(ComputeConfigurationHash(*otherCluster)).ToNot(Equal(ComputeConfigurationHash(cluster)))
+
+		otherCluster = cluster.DeepCopy()
+		otherCluster.Spec.Certificates = &v1.CertificatesConfiguration{ServerTLSSecret: "tls"}
+		Expect(ComputeConfigurationHash(*otherCluster)).ToNot(Equal(ComputeConfigurationHash(cluster)))
+	})
+})
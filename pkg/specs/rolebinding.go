@@ -23,7 +23,7 @@ import (
 
 // CreateRoleBinding is the binding between the permissions that PGK can use
 // and the ServiceAccount used by the Pod
-func CreateRoleBinding(objectMeta metav1.ObjectMeta) rbacv1.RoleBinding {
+func CreateRoleBinding(objectMeta metav1.ObjectMeta, serviceAccountName string) rbacv1.RoleBinding {
 	return rbacv1.RoleBinding{
 		ObjectMeta: metav1.ObjectMeta{
 			Namespace: objectMeta.Namespace,
@@ -33,7 +33,7 @@ func CreateRoleBinding(objectMeta metav1.ObjectMeta) rbacv1.RoleBinding {
 			{
 				Kind:      "ServiceAccount",
 				APIGroup:  "",
-				Name:      objectMeta.Name,
+				Name:      serviceAccountName,
 				Namespace: objectMeta.Namespace,
 			},
 		},
@@ -34,8 +34,15 @@ var _ = Describe("Role binding", func() {
 	}
 
 	It("is created with the same name as the cluster", func() {
-		roleBinding := CreateRoleBinding(cluster.ObjectMeta)
+		roleBinding := CreateRoleBinding(cluster.ObjectMeta, cluster.Name)
 		Expect(roleBinding.Name).To(Equal(cluster.Name))
 		Expect(roleBinding.Namespace).To(Equal(cluster.Namespace))
 	})
+
+	It("binds the role to the service account used by the Pods", func() {
+		roleBinding := CreateRoleBinding(cluster.ObjectMeta, "existing-sa")
+		Expect(roleBinding.Subjects).To(HaveLen(1))
+		Expect(roleBinding.Subjects[0].Name).To(Equal("existing-sa"))
+		Expect(roleBinding.RoleRef.Name).To(Equal(cluster.Name))
+	})
 })
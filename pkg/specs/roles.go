@@ -35,6 +35,10 @@ func CreateRole(cluster apiv1.Cluster, backupOrigin *apiv1.Backup) rbacv1.Role {
 		cluster.GetLDAPSecretName(),
 	}
 
+	if cluster.IsMonitoringRoleEnabled() {
+		involvedSecretNames = append(involvedSecretNames, cluster.GetMonitoringSecretName())
+	}
+
 	involvedConfigMapNames := []string{
 		cluster.Name,
 	}
@@ -166,7 +170,16 @@ func CreateRole(cluster apiv1.Cluster, backupOrigin *apiv1.Backup) rbacv1.Role {
 func externalClusterSecrets(cluster apiv1.Cluster) []string {
 	var result []string
 
-	for _, server := range cluster.Spec.ExternalClusters {
+	servers := cluster.Spec.ExternalClusters
+	if cluster.Spec.Bootstrap != nil && cluster.Spec.Bootstrap.PgBaseBackup != nil &&
+		cluster.Spec.Bootstrap.PgBaseBackup.SourceCluster != nil {
+		// The secrets of the source cluster are read while cloning it
+		if server, ok := cluster.GetPgBaseBackupSource(); ok {
+			servers = append(servers[:len(servers):len(servers)], server)
+		}
+	}
+
+	for _, server := range servers {
 		if server.SSLCert != nil {
 			result = append(result,
 				server.SSLCert.Name)
@@ -197,6 +210,9 @@ func externalClusterSecrets(cluster apiv1.Cluster) []string {
 				result = append(result, barmanObjStore.EndpointCA.Name)
 			}
 		}
+		if server.WalG != nil {
+			result = append(result, credentialsSecrets(server.WalG.BarmanCredentials)...)
+		}
 	}
 
 	return result
@@ -225,6 +241,37 @@ func backupSecrets(cluster apiv1.Cluster, backupOrigin *apiv1.Backup) []string {
 			cluster.Spec.Backup.BarmanObjectStore.EndpointCA.Name)
 	}
 
+	// Secrets needed to mirror the WAL archive
+	if cluster.Spec.Backup.IsWalMirrorEnabled() {
+		mirrorObjectStore := &cluster.Spec.Backup.WalMirror.BarmanObjectStore
+		result = append(
+			result,
+			s3CredentialsSecrets(mirrorObjectStore.BarmanCredentials.AWS)...)
+		result = append(
+			result,
+			azureCredentialsSecrets(mirrorObjectStore.BarmanCredentials.Azure)...)
+		result = append(
+			result,
+			googleCredentialsSecrets(mirrorObjectStore.BarmanCredentials.Google)...)
+		if mirrorObjectStore.EndpointCA != nil {
+			result = append(result, mirrorObjectStore.EndpointCA.Name)
+		}
+	}
+
+	// Secrets needed by the custom WAL archive command
+	if cluster.Spec.Backup.IsCustomWalArchiveEnabled() {
+		for _, env := range cluster.Spec.Backup.CustomWalArchive.Env {
+			if env.ValueFrom != nil && env.ValueFrom.SecretKeyRef != nil {
+				result = append(result, env.ValueFrom.SecretKeyRef.Name)
+			}
+		}
+	}
+
+	// Secrets needed by wal-g
+	if cluster.Spec.Backup.IsWalGEnabled() {
+		result = append(result, credentialsSecrets(cluster.Spec.Backup.WalG.BarmanCredentials)...)
+	}
+
 	if backupOrigin != nil {
 		result = append(
 			result,
@@ -240,6 +287,16 @@ func backupSecrets(cluster apiv1.Cluster, backupOrigin *apiv1.Backup) []string {
 	return result
 }
 
+// credentialsSecrets gets the secrets referenced by the credentials of
+// any cloud provider
+func credentialsSecrets(credentials apiv1.BarmanCredentials) []string {
+	var result []string
+	result = append(result, s3CredentialsSecrets(credentials.AWS)...)
+	result = append(result, azureCredentialsSecrets(credentials.Azure)...)
+	result = append(result, googleCredentialsSecrets(credentials.Google)...)
+	return result
+}
+
 func azureCredentialsSecrets(azureCredentials *apiv1.AzureCredentials) []string {
 	var result []string
 
@@ -182,6 +182,33 @@ var _ = Describe("Roles", func() {
 			"testPassword",
 		))
 	})
+
+	It("should contain the secrets of the source cluster to be cloned", func() {
+		cloneCluster := apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "clone", Namespace: "default"},
+			Spec: apiv1.ClusterSpec{
+				Bootstrap: &apiv1.BootstrapConfiguration{
+					PgBaseBackup: &apiv1.BootstrapPgBaseBackup{
+						SourceCluster: &apiv1.LocalObjectReference{Name: "origin"},
+					},
+				},
+			},
+		}
+		role := CreateRole(cloneCluster, nil)
+		Expect(role.Rules[1].ResourceNames).To(ContainElements("origin-replication", "origin-ca"))
+	})
+
+	It("should contain the secret of the monitoring role when it is enabled", func() {
+		monitoredCluster := apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "monitored", Namespace: "default"},
+		}
+		role := CreateRole(monitoredCluster, nil)
+		Expect(role.Rules[1].ResourceNames).ToNot(ContainElement("monitored-monitoring"))
+
+		monitoredCluster.Spec.Monitoring = &apiv1.MonitoringConfiguration{EnableMonitoringRole: true}
+		role = CreateRole(monitoredCluster, nil)
+		Expect(role.Rules[1].ResourceNames).To(ContainElement("monitored-monitoring"))
+	})
 })
 
 var _ = Describe("Secrets", func() {
@@ -219,4 +246,69 @@ var _ = Describe("Secrets", func() {
 		secrets = backupSecrets(cluster, nil)
 		Expect(secrets).To(ConsistOf("test-secret", "test-access", "test-endpoint-ca-name"))
 	})
+
+	It("include the secrets of the custom WAL archive command", func() {
+		customCluster := cluster.DeepCopy()
+		customCluster.Spec = apiv1.ClusterSpec{
+			Backup: &apiv1.BackupConfiguration{
+				WalArchiveBackend: apiv1.WalArchiveBackendCustom,
+				CustomWalArchive: &apiv1.CustomWalArchiveConfiguration{
+					Command: "wal-g wal-push %p",
+					Env: []corev1.EnvVar{
+						{Name: "WALG_S3_PREFIX", Value: "s3://bucket/"},
+						{
+							Name: "AWS_ACCESS_KEY_ID",
+							ValueFrom: &corev1.EnvVarSource{
+								SecretKeyRef: &corev1.SecretKeySelector{
+									LocalObjectReference: corev1.LocalObjectReference{Name: "walg-creds"},
+									Key:                  "ACCESS_KEY_ID",
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+		Expect(backupSecrets(*customCluster, nil)).To(ConsistOf("walg-creds"))
+	})
+
+	It("include the secrets of the wal-g credentials", func() {
+		walGCluster := cluster.DeepCopy()
+		walGCluster.Spec = apiv1.ClusterSpec{
+			Backup: &apiv1.BackupConfiguration{
+				WalArchiveBackend: apiv1.WalArchiveBackendWalG,
+				WalG: &apiv1.WalGConfiguration{
+					DestinationPath: "gs://bucket/",
+					BarmanCredentials: apiv1.BarmanCredentials{
+						Google: &apiv1.GoogleCredentials{
+							ApplicationCredentials: &apiv1.SecretKeySelector{
+								LocalObjectReference: apiv1.LocalObjectReference{Name: "gcs-creds"},
+								Key:                  "gcsCredentials",
+							},
+						},
+					},
+				},
+			},
+			ExternalClusters: []apiv1.ExternalCluster{
+				{
+					Name: "source",
+					WalG: &apiv1.WalGConfiguration{
+						DestinationPath: "s3://bucket/",
+						BarmanCredentials: apiv1.BarmanCredentials{
+							AWS: &apiv1.S3Credentials{
+								AccessKeyIDReference: &apiv1.SecretKeySelector{
+									LocalObjectReference: apiv1.LocalObjectReference{Name: "source-creds"},
+								},
+								SecretAccessKeyReference: &apiv1.SecretKeySelector{
+									LocalObjectReference: apiv1.LocalObjectReference{Name: "source-creds"},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+		Expect(backupSecrets(*walGCluster, nil)).To(ConsistOf("gcs-creds"))
+		Expect(externalClusterSecrets(*walGCluster)).To(ConsistOf("source-creds", "source-creds"))
+	})
 })
@@ -18,13 +18,28 @@ package specs
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/stringset"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 )
 
+// MirroredSecretSourceAnnotationName is the name of the annotation
+// containing the namespace and the name of the secret a secret has been
+// copied from, which marks the secrets managed by the operator
+const MirroredSecretSourceAnnotationName = MetadataNamespace + "/mirroredSecretSource"
+
+// MirroredSecretAllowedNamespacesAnnotationName is the name of the
+// annotation, set on a secret of a credentials namespace, containing the
+// comma-separated list of the namespaces whose clusters can copy it
+const MirroredSecretAllowedNamespacesAnnotationName = MetadataNamespace + "/mirroredSecretAllowedNamespaces"
+
 // CreateSecret create a secret with the PostgreSQL and the owner passwords
 func CreateSecret(
 	name string,
@@ -56,3 +71,64 @@ func CreateSecret(
 		},
 	}
 }
+
+// GetMirroredSecretNames gets the names of the secrets referenced by the
+// credentials of the backup object store and by the certificates, which
+// are copied from the credentials namespace of the cluster, if any
+func GetMirroredSecretNames(cluster apiv1.Cluster) []string {
+	if cluster.Spec.CredentialsNamespace == "" {
+		return nil
+	}
+
+	names := stringset.From(backupSecrets(cluster, nil))
+	if certificates := cluster.Spec.Certificates; certificates != nil {
+		for _, name := range []string{
+			certificates.ServerCASecret,
+			certificates.ServerTLSSecret,
+			certificates.ClientCASecret,
+			certificates.ReplicationTLSSecret,
+		} {
+			if name != "" {
+				names.Put(name)
+			}
+		}
+	}
+
+	result := names.ToList()
+	sort.Strings(result)
+	return result
+}
+
+// CreateMirroredSecret creates the copy of a secret of the credentials
+// namespace in the namespace of the cluster, keeping its name
+func CreateMirroredSecret(cluster apiv1.Cluster, source *corev1.Secret) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      source.Name,
+			Namespace: cluster.Namespace,
+			Labels: map[string]string{
+				utils.ClusterLabelName: cluster.Name,
+				WatchedLabelName:       "true",
+			},
+			Annotations: map[string]string{
+				MirroredSecretSourceAnnotationName: source.Namespace + "/" + source.Name,
+			},
+		},
+		Type: source.Type,
+		Data: source.Data,
+	}
+}
+
+// IsSecretMirroringAllowed checks whether the clusters of a namespace can
+// copy a secret of a credentials namespace, which needs to explicitly list
+// that namespace in its annotations
+func IsSecretMirroringAllowed(source *corev1.Secret, namespace string) bool {
+	allowedNamespaces := source.Annotations[MirroredSecretAllowedNamespacesAnnotationName]
+	for _, allowedNamespace := range strings.Split(allowedNamespaces, ",") {
+		if strings.TrimSpace(allowedNamespace) == namespace {
+			return true
+		}
+	}
+
+	return false
+}
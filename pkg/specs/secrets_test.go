@@ -17,6 +17,12 @@ limitations under the License.
 package specs
 
 import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 )
@@ -31,3 +37,69 @@ var _ = Describe("Secret creation", func() {
 		Expect(secret.StringData["password"]).To(Equal("thispassword"))
 	})
 })
+
+var _ = Describe("Mirrored secrets", func() {
+	cluster := apiv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "tenant-a"},
+		Spec: apiv1.ClusterSpec{
+			CredentialsNamespace: "credentials",
+			Backup: &apiv1.BackupConfiguration{
+				BarmanObjectStore: &apiv1.BarmanObjectStoreConfiguration{
+					BarmanCredentials: apiv1.BarmanCredentials{
+						AWS: &apiv1.S3Credentials{
+							AccessKeyIDReference: &apiv1.SecretKeySelector{
+								LocalObjectReference: apiv1.LocalObjectReference{Name: "aws-creds"},
+								Key:                  "ACCESS_KEY_ID",
+							},
+							SecretAccessKeyReference: &apiv1.SecretKeySelector{
+								LocalObjectReference: apiv1.LocalObjectReference{Name: "aws-creds"},
+								Key:                  "ACCESS_SECRET_KEY",
+							},
+						},
+					},
+				},
+			},
+			Certificates: &apiv1.CertificatesConfiguration{
+				ServerCASecret:  "server-ca",
+				ServerTLSSecret: "server-tls",
+			},
+		},
+	}
+
+	It("lists the secrets of the object store and of the certificates", func() {
+		Expect(GetMirroredSecretNames(cluster)).To(Equal([]string{"aws-creds", "server-ca", "server-tls"}))
+	})
+
+	It("doesn't copy any secret without a credentials namespace", func() {
+		cluster := cluster.DeepCopy()
+		cluster.Spec.CredentialsNamespace = ""
+		Expect(GetMirroredSecretNames(*cluster)).To(BeEmpty())
+	})
+
+	It("copies a secret into the namespace of the cluster", func() {
+		source := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "aws-creds", Namespace: "credentials"},
+			Type:       corev1.SecretTypeOpaque,
+			Data:       map[string][]byte{"ACCESS_KEY_ID": []byte("id")},
+		}
+		secret := CreateMirroredSecret(cluster, source)
+		Expect(secret.Name).To(Equal("aws-creds"))
+		Expect(secret.Namespace).To(Equal("tenant-a"))
+		Expect(secret.Labels[utils.ClusterLabelName]).To(Equal("cluster-example"))
+		Expect(secret.Annotations[MirroredSecretSourceAnnotationName]).To(Equal("credentials/aws-creds"))
+		Expect(secret.Data).To(Equal(source.Data))
+	})
+
+	It("copies a secret only into the namespaces it allows", func() {
+		source := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "aws-creds", Namespace: "credentials"}}
+		Expect(IsSecretMirroringAllowed(source, "tenant-a")).To(BeFalse())
+
+		source.Annotations = map[string]string{
+			MirroredSecretAllowedNamespacesAnnotationName: "tenant-a, tenant-b",
+		}
+		Expect(IsSecretMirroringAllowed(source, "tenant-a")).To(BeTrue())
+		Expect(IsSecretMirroringAllowed(source, "tenant-b")).To(BeTrue())
+		Expect(IsSecretMirroringAllowed(source, "tenant-c")).To(BeFalse())
+		Expect(IsSecretMirroringAllowed(source, "")).To(BeFalse())
+	})
+})
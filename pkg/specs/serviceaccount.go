@@ -25,6 +25,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/stringset"
 )
 
 const (
@@ -33,16 +34,28 @@ const (
 	OperatorManagedSecretsName = "cnpg.io/managedSecrets" // #nosec
 )
 
-// UpdateServiceAccount sets the needed values in the ServiceAccount that will be used in every Pod
+// UpdateServiceAccount sets the needed values in the ServiceAccount that will be used in every Pod.
+// The pull secrets previously added by the operator which are not required anymore are removed,
+// while the ones added by other parties are left untouched
 func UpdateServiceAccount(imagePullSecretsNames []string, serviceAccount *corev1.ServiceAccount) error {
 	if serviceAccount.ImagePullSecrets == nil {
 		serviceAccount.ImagePullSecrets = []corev1.LocalObjectReference{}
 	}
 
+	required := stringset.From(imagePullSecretsNames)
+	previouslyManaged := stringset.From(getManagedSecretsNames(serviceAccount))
+	references := make([]corev1.LocalObjectReference, 0, len(serviceAccount.ImagePullSecrets))
+	for _, existing := range serviceAccount.ImagePullSecrets {
+		if previouslyManaged.Has(existing.Name) && !required.Has(existing.Name) {
+			continue
+		}
+		references = append(references, existing)
+	}
+
 	var newReferences []corev1.LocalObjectReference
 	for _, name := range imagePullSecretsNames {
 		found := false
-		for _, existing := range serviceAccount.ImagePullSecrets {
+		for _, existing := range references {
 			if name == existing.Name {
 				found = true
 				break
@@ -52,7 +65,7 @@ func UpdateServiceAccount(imagePullSecretsNames []string, serviceAccount *corev1
 			newReferences = append(newReferences, corev1.LocalObjectReference{Name: name})
 		}
 	}
-	serviceAccount.ImagePullSecrets = append(serviceAccount.ImagePullSecrets, newReferences...)
+	serviceAccount.ImagePullSecrets = append(references, newReferences...)
 
 	annotationValue, err := CreateManagedSecretsAnnotationValue(imagePullSecretsNames)
 	if err != nil {
@@ -67,6 +80,22 @@ func UpdateServiceAccount(imagePullSecretsNames []string, serviceAccount *corev1
 	return nil
 }
 
+// getManagedSecretsNames gets the names of the pull secrets which have
+// been added by the operator to the passed ServiceAccount
+func getManagedSecretsNames(serviceAccount *corev1.ServiceAccount) []string {
+	value := serviceAccount.Annotations[OperatorManagedSecretsName]
+	if value == "" {
+		return nil
+	}
+
+	var names []string
+	if err := json.Unmarshal([]byte(value), &names); err != nil {
+		return nil
+	}
+
+	return names
+}
+
 // CreateManagedSecretsAnnotationValue creates the value of the annotations that stores
 // the names of the secrets managed by the operator inside a ServiceAccount
 func CreateManagedSecretsAnnotationValue(imagePullSecretsNames []string) (string, error) {
@@ -68,6 +68,28 @@ var _ = Describe("Service accounts", func() {
 		})
 	})
 
+	When("some pull secrets are not required anymore", func() {
+		It("removes only the ones added by the operator", func(ctx SpecContext) {
+			sa := &v1.ServiceAccount{}
+			err := UpdateServiceAccount([]string{"one", "two"}, sa)
+			Expect(err).To(BeNil())
+
+			sa.ImagePullSecrets = append(sa.ImagePullSecrets, v1.LocalObjectReference{
+				Name: "token",
+			})
+
+			err = UpdateServiceAccount([]string{"two", "three"}, sa)
+			Expect(err).To(BeNil())
+			Expect(sa.ImagePullSecrets).To(Equal([]v1.LocalObjectReference{
+				{Name: "two"},
+				{Name: "token"},
+				{Name: "three"},
+			}))
+			Expect(sa.Annotations[OperatorManagedSecretsName]).To(Equal(`["two","three"]`))
+			Expect(IsServiceAccountAligned(ctx, sa, []string{"two", "three"}, emptyMeta)).To(BeTrue())
+		})
+	})
+
 	When("there are custom labels to set on the ServiceAccount", func() {
 		It("can detect if the ServiceAccount is needing a refresh", func(ctx SpecContext) {
 			meta := metav1.ObjectMeta{
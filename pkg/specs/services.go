@@ -17,6 +17,8 @@ limitations under the License.
 package specs
 
 import (
+	"reflect"
+
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -107,3 +109,38 @@ func CreateClusterReadWriteService(cluster apiv1.Cluster) *corev1.Service {
 		},
 	}
 }
+
+// IsServiceAligned checks if the routing of a service, that is its selector
+// and its ports, matches the one of the generated service. The values
+// defaulted by the API server are ignored
+func IsServiceAligned(current *corev1.Service, desired *corev1.Service) bool {
+	if !reflect.DeepEqual(current.Spec.Selector, desired.Spec.Selector) ||
+		current.Spec.PublishNotReadyAddresses != desired.Spec.PublishNotReadyAddresses ||
+		len(current.Spec.Ports) != len(desired.Spec.Ports) {
+		return false
+	}
+
+	for idx := range desired.Spec.Ports {
+		currentPort := current.Spec.Ports[idx]
+		desiredPort := desired.Spec.Ports[idx]
+		if currentPort.Protocol == "" {
+			currentPort.Protocol = corev1.ProtocolTCP
+		}
+		if currentPort.Name != desiredPort.Name ||
+			currentPort.Protocol != desiredPort.Protocol ||
+			currentPort.Port != desiredPort.Port ||
+			currentPort.TargetPort != desiredPort.TargetPort {
+			return false
+		}
+	}
+
+	return true
+}
+
+// AlignService sets the selector and the ports of a service to
+// the ones of the generated service
+func AlignService(current *corev1.Service, desired *corev1.Service) {
+	current.Spec.Selector = desired.Spec.Selector
+	current.Spec.PublishNotReadyAddresses = desired.Spec.PublishNotReadyAddresses
+	current.Spec.Ports = desired.Spec.Ports
+}
@@ -18,6 +18,7 @@ package specs
 
 import (
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
@@ -62,4 +63,22 @@ var _ = Describe("Services specification", func() {
 		Expect(service.Spec.Selector[utils.ClusterLabelName]).To(Equal("clustername"))
 		Expect(service.Spec.Selector[ClusterRoleLabelName]).To(Equal(ClusterRoleLabelPrimary))
 	})
+
+	It("detects the services whose routing has been changed", func() {
+		desired := CreateClusterReadWriteService(postgresql)
+		current := desired.DeepCopy()
+		current.Spec.Ports[0].Protocol = ""
+		current.Spec.ClusterIP = "10.0.0.1"
+		Expect(IsServiceAligned(current, desired)).To(BeTrue())
+
+		current.Spec.Selector[ClusterRoleLabelName] = ClusterRoleLabelReplica
+		Expect(IsServiceAligned(current, desired)).To(BeFalse())
+
+		current = desired.DeepCopy()
+		current.Spec.Ports[0].TargetPort = intstr.FromInt(6432)
+		Expect(IsServiceAligned(current, desired)).To(BeFalse())
+
+		AlignService(current, desired)
+		Expect(IsServiceAligned(current, desired)).To(BeTrue())
+	})
 })
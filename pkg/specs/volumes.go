@@ -18,6 +18,7 @@ package specs
 
 import (
 	"fmt"
+	"path"
 
 	corev1 "k8s.io/api/core/v1"
 
@@ -31,6 +32,25 @@ const PgWalVolumePath = "/var/lib/postgresql/wal"
 // PgWalVolumePgWalPath its the path of pg_wal directory inside the WAL volume when present
 const PgWalVolumePgWalPath = "/var/lib/postgresql/wal/pg_wal"
 
+// ObjectStoreCredentialsVolumeName is the name of the volume containing
+// the credentials of the backup object store
+const ObjectStoreCredentialsVolumeName = "object-store-credentials"
+
+// PgTablespaceVolumePath is the path where the tablespace volumes are mounted
+const PgTablespaceVolumePath = "/var/lib/postgresql/tablespaces"
+
+// GetTablespaceMountPath gets the path where the volume of a tablespace is mounted
+func GetTablespaceMountPath(tablespaceName string) string {
+	return path.Join(PgTablespaceVolumePath, tablespaceName)
+}
+
+// GetTablespaceLocation gets the location of a tablespace. It's a directory
+// inside the volume, as PostgreSQL requires an empty directory owned by
+// the postgres user, and the root of a volume may be neither
+func GetTablespaceLocation(tablespaceName string) string {
+	return path.Join(GetTablespaceMountPath(tablespaceName), "data")
+}
+
 func createPostgresVolumes(cluster apiv1.Cluster, podName string) []corev1.Volume {
 	result := []corev1.Volume{
 		{
@@ -95,9 +115,31 @@ func createPostgresVolumes(cluster apiv1.Cluster, podName string) []corev1.Volum
 			})
 	}
 
+	for _, tablespace := range cluster.Spec.Tablespaces {
+		result = append(result,
+			corev1.Volume{
+				Name: tablespace.GetVolumeName(),
+				VolumeSource: corev1.VolumeSource{
+					PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+						ClaimName: podName + tablespace.GetVolumeSuffix(),
+					},
+				},
+			})
+	}
+
 	if cluster.ShouldCreateProjectedVolume() {
 		result = append(result, createProjectedVolume(cluster))
 	}
+
+	if credentialsVolume := cluster.GetObjectStoreCredentialsVolume(); credentialsVolume != nil {
+		result = append(result,
+			corev1.Volume{
+				Name: ObjectStoreCredentialsVolumeName,
+				VolumeSource: corev1.VolumeSource{
+					CSI: credentialsVolume.DeepCopy(),
+				},
+			})
+	}
 	return result
 }
 
@@ -167,6 +209,7 @@ func createPostgresVolumeMounts(cluster apiv1.Cluster) []corev1.VolumeMount {
 		{
 			Name:      "pgdata",
 			MountPath: "/var/lib/postgresql/data",
+			SubPath:   cluster.Spec.StorageConfiguration.SubPath,
 		},
 		{
 			Name:      "scratch-data",
@@ -205,6 +248,17 @@ func createPostgresVolumeMounts(cluster apiv1.Cluster) []corev1.VolumeMount {
 			corev1.VolumeMount{
 				Name:      "pg-wal",
 				MountPath: PgWalVolumePath,
+				SubPath:   cluster.Spec.WalStorage.SubPath,
+			},
+		)
+	}
+
+	for _, tablespace := range cluster.Spec.Tablespaces {
+		volumeMounts = append(volumeMounts,
+			corev1.VolumeMount{
+				Name:      tablespace.GetVolumeName(),
+				MountPath: GetTablespaceMountPath(tablespace.Name),
+				SubPath:   tablespace.Storage.SubPath,
 			},
 		)
 	}
@@ -218,6 +272,16 @@ func createPostgresVolumeMounts(cluster apiv1.Cluster) []corev1.VolumeMount {
 		)
 	}
 
+	if cluster.GetObjectStoreCredentialsVolume() != nil {
+		volumeMounts = append(volumeMounts,
+			corev1.VolumeMount{
+				Name:      ObjectStoreCredentialsVolumeName,
+				MountPath: postgres.ObjectStoreCredentialsDirectory,
+				ReadOnly:  true,
+			},
+		)
+	}
+
 	return volumeMounts
 }
 
@@ -18,6 +18,7 @@ package specs
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/pointer"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 
@@ -290,3 +291,100 @@ var _ = Describe("test createVolumesAndVolumeMountsForPostInitApplicationSQLRefs
 		}))
 	})
 })
+
+var _ = Describe("tablespace volumes", func() {
+	cluster := apiv1.Cluster{
+		Spec: apiv1.ClusterSpec{
+			Tablespaces: []apiv1.TablespaceConfiguration{
+				{
+					Name:    "cold_data",
+					Storage: apiv1.StorageConfiguration{Size: "1Gi"},
+				},
+			},
+		},
+	}
+
+	It("adds a volume for each tablespace", func() {
+		volumes := createPostgresVolumes(cluster, "cluster-example-1")
+		Expect(volumes).To(ContainElement(corev1.Volume{
+			Name: "tbs-cold-data",
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+					ClaimName: "cluster-example-1-tbs-cold-data",
+				},
+			},
+		}))
+	})
+
+	It("mounts each tablespace volume in a directory named after the tablespace", func() {
+		mounts := createPostgresVolumeMounts(cluster)
+		Expect(mounts).To(ContainElement(corev1.VolumeMount{
+			Name:      "tbs-cold-data",
+			MountPath: "/var/lib/postgresql/tablespaces/cold_data",
+		}))
+		Expect(GetTablespaceLocation("cold_data")).To(Equal("/var/lib/postgresql/tablespaces/cold_data/data"))
+	})
+})
+
+var _ = Describe("volumes with a subPath", func() {
+	It("mounts the subPath of the volumes", func() {
+		cluster := apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				StorageConfiguration: apiv1.StorageConfiguration{Size: "1Gi", SubPath: "pgdata"},
+				WalStorage:           &apiv1.StorageConfiguration{Size: "1Gi", SubPath: "wal"},
+			},
+		}
+		mounts := createPostgresVolumeMounts(cluster)
+		Expect(mounts).To(ContainElement(corev1.VolumeMount{
+			Name:      "pgdata",
+			MountPath: "/var/lib/postgresql/data",
+			SubPath:   "pgdata",
+		}))
+		Expect(mounts).To(ContainElement(corev1.VolumeMount{
+			Name:      "pg-wal",
+			MountPath: PgWalVolumePath,
+			SubPath:   "wal",
+		}))
+	})
+})
+
+var _ = Describe("object store credentials volume", func() {
+	credentialsVolume := &corev1.CSIVolumeSource{
+		Driver:   "secrets-store.csi.k8s.io",
+		ReadOnly: pointer.Bool(true),
+		VolumeAttributes: map[string]string{
+			"secretProviderClass": "backup-credentials",
+		},
+	}
+	cluster := apiv1.Cluster{
+		Spec: apiv1.ClusterSpec{
+			Backup: &apiv1.BackupConfiguration{
+				BarmanObjectStore: &apiv1.BarmanObjectStoreConfiguration{
+					BarmanCredentials: apiv1.BarmanCredentials{AWS: &apiv1.S3Credentials{}},
+					CredentialsVolume: credentialsVolume,
+				},
+			},
+		},
+	}
+
+	It("adds and mounts the CSI volume containing the credentials", func() {
+		Expect(createPostgresVolumes(cluster, "cluster-example-1")).To(ContainElement(corev1.Volume{
+			Name: ObjectStoreCredentialsVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				CSI: credentialsVolume,
+			},
+		}))
+		Expect(createPostgresVolumeMounts(cluster)).To(ContainElement(corev1.VolumeMount{
+			Name:      ObjectStoreCredentialsVolumeName,
+			MountPath: "/object-store-credentials",
+			ReadOnly:  true,
+		}))
+	})
+
+	It("doesn't add the volume when the credentials are read from the secrets", func() {
+		cluster := apiv1.Cluster{}
+		for _, volume := range createPostgresVolumes(cluster, "cluster-example-1") {
+			Expect(volume.Name).ToNot(Equal(ObjectStoreCredentialsVolumeName))
+		}
+	})
+})
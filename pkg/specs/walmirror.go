@@ -0,0 +1,164 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package specs
+
+import (
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils/hash"
+)
+
+const (
+	// WalMirrorJobRole is the role of the jobs mirroring the WAL
+	// archive to the secondary object store
+	WalMirrorJobRole = "wal-mirror"
+
+	// WalMirrorSpecHashAnnotationName is the name of the annotation
+	// containing the hash of the specification of the CronJob mirroring
+	// the WAL archive, used to detect when it needs to be updated
+	WalMirrorSpecHashAnnotationName = MetadataNamespace + "/walMirrorSpecHash"
+)
+
+// GetWalMirrorCronJobName gets the name of the CronJob mirroring
+// the WAL archive of a cluster
+func GetWalMirrorCronJobName(clusterName string) string {
+	return clusterName + "-" + WalMirrorJobRole
+}
+
+// CreateWalMirrorCronJob creates the CronJob periodically copying the
+// WAL files archived by the cluster to the secondary object store. The
+// jobs don't mount the volumes of the instances, and only need the
+// barman-cloud tools shipped with the PostgreSQL image
+func CreateWalMirrorCronJob(cluster apiv1.Cluster) (*batchv1.CronJob, error) {
+	name := GetWalMirrorCronJobName(cluster.Name)
+	mirror := cluster.Spec.Backup.WalMirror
+	envConfig := CreatePodEnvConfig(cluster, name)
+
+	// A failed execution is not retried, the next one will
+	// resume from the last WAL file which has been copied
+	var backoffLimit int32
+	var successfulJobsHistoryLimit int32 = 1
+	var failedJobsHistoryLimit int32 = 1
+	suspend := mirror.Suspend
+
+	volumeMounts := []corev1.VolumeMount{
+		{
+			Name:      "scratch-data",
+			MountPath: postgres.ScratchDataDirectory,
+		},
+	}
+
+	bootstrapContainer := corev1.Container{
+		Name:            BootstrapControllerContainerName,
+		Image:           configuration.Current.OperatorImageName,
+		ImagePullPolicy: cluster.Spec.ImagePullPolicy,
+		Command: []string{
+			"/manager",
+			"bootstrap",
+			"/controller/manager",
+		},
+		VolumeMounts:    volumeMounts,
+		SecurityContext: CreateContainerSecurityContext(),
+	}
+	addManagerLoggingOptions(cluster, &bootstrapContainer)
+
+	mirrorContainer := corev1.Container{
+		Name:            WalMirrorJobRole,
+		Image:           cluster.GetImageName(),
+		ImagePullPolicy: cluster.Spec.ImagePullPolicy,
+		Env:             envConfig.EnvVars,
+		EnvFrom:         envConfig.EnvFrom,
+		Command: []string{
+			"/controller/manager",
+			"wal-mirror",
+		},
+		VolumeMounts:    volumeMounts,
+		SecurityContext: CreateContainerSecurityContext(),
+	}
+	addManagerLoggingOptions(cluster, &mirrorContainer)
+
+	labels := map[string]string{
+		utils.ClusterLabelName: cluster.Name,
+		utils.JobRoleLabelName: WalMirrorJobRole,
+	}
+
+	cronJob := &batchv1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: cluster.Namespace,
+			Labels:    labels,
+		},
+		Spec: batchv1.CronJobSpec{
+			Schedule:                   mirror.GetSchedule(),
+			Suspend:                    &suspend,
+			ConcurrencyPolicy:          batchv1.ForbidConcurrent,
+			SuccessfulJobsHistoryLimit: &successfulJobsHistoryLimit,
+			FailedJobsHistoryLimit:     &failedJobsHistoryLimit,
+			JobTemplate: batchv1.JobTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: labels,
+				},
+				Spec: batchv1.JobSpec{
+					BackoffLimit: &backoffLimit,
+					Template: corev1.PodTemplateSpec{
+						// The cluster label is not set on the pod, as it would be
+						// selected by the anti-affinity rules of the instances
+						ObjectMeta: metav1.ObjectMeta{
+							Labels: map[string]string{
+								utils.JobRoleLabelName: WalMirrorJobRole,
+							},
+						},
+						Spec: corev1.PodSpec{
+							InitContainers: []corev1.Container{bootstrapContainer},
+							Containers:     []corev1.Container{mirrorContainer},
+							Volumes: []corev1.Volume{
+								{
+									Name: "scratch-data",
+									VolumeSource: corev1.VolumeSource{
+										EmptyDir: &corev1.EmptyDirVolumeSource{},
+									},
+								},
+							},
+							SecurityContext: CreatePodSecurityContext(
+								cluster.GetPostgresUID(),
+								cluster.GetPostgresGID()),
+							Tolerations:        cluster.Spec.Affinity.Tolerations,
+							NodeSelector:       cluster.Spec.Affinity.NodeSelector,
+							ServiceAccountName: cluster.GetServiceAccountName(),
+							RestartPolicy:      corev1.RestartPolicyNever,
+						},
+					},
+				},
+			},
+		},
+	}
+	specHash, err := hash.ComputeHash(cronJob.Spec)
+	if err != nil {
+		return nil, err
+	}
+	cronJob.Annotations = map[string]string{
+		WalMirrorSpecHashAnnotationName: specHash,
+	}
+
+	return cronJob, nil
+}
@@ -0,0 +1,79 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package specs
+
+import (
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("WAL mirror CronJob", func() {
+	cluster := apiv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cluster-example",
+			Namespace: "default",
+		},
+		Spec: apiv1.ClusterSpec{
+			Backup: &apiv1.BackupConfiguration{
+				BarmanObjectStore: &apiv1.BarmanObjectStoreConfiguration{
+					DestinationPath: "s3://primary/",
+				},
+				WalMirror: &apiv1.WalMirrorConfiguration{
+					BarmanObjectStore: apiv1.BarmanObjectStoreConfiguration{
+						DestinationPath: "s3://secondary/",
+					},
+					Schedule: "*/10 * * * *",
+				},
+			},
+		},
+	}
+
+	It("runs the wal-mirror command on schedule", func() {
+		cronJob, err := CreateWalMirrorCronJob(cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cronJob.Name).To(Equal("cluster-example-wal-mirror"))
+		Expect(cronJob.Spec.Schedule).To(Equal("*/10 * * * *"))
+		Expect(cronJob.Spec.ConcurrencyPolicy).To(Equal(batchv1.ForbidConcurrent))
+		Expect(*cronJob.Spec.Suspend).To(BeFalse())
+
+		podSpec := cronJob.Spec.JobTemplate.Spec.Template.Spec
+		Expect(podSpec.Containers).To(HaveLen(1))
+		Expect(podSpec.Containers[0].Command).To(ContainElement("wal-mirror"))
+		Expect(podSpec.ServiceAccountName).To(Equal(cluster.Name))
+		Expect(cronJob.Spec.JobTemplate.Spec.Template.Labels).ToNot(HaveKey(utils.ClusterLabelName))
+	})
+
+	It("changes its hash when the configuration changes", func() {
+		cronJob, err := CreateWalMirrorCronJob(cluster)
+		Expect(err).ToNot(HaveOccurred())
+
+		suspendedCluster := cluster.DeepCopy()
+		suspendedCluster.Spec.Backup.WalMirror.Suspend = true
+		suspendedCronJob, err := CreateWalMirrorCronJob(*suspendedCluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(*suspendedCronJob.Spec.Suspend).To(BeTrue())
+
+		Expect(suspendedCronJob.Annotations[WalMirrorSpecHashAnnotationName]).ToNot(
+			Equal(cronJob.Annotations[WalMirrorSpecHashAnnotationName]))
+	})
+})
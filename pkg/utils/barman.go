@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"math"
 	"regexp"
+	"sort"
 
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/cnpgerrors"
 )
@@ -43,7 +44,8 @@ func ParsePolicy(policy string) (string, error) {
 }
 
 // MapToBarmanTagsFormat will transform a map[string]string into the
-// Barman tags format needed
+// Barman tags format needed. The tags are sorted by key, so that the
+// same options are generated every time
 func MapToBarmanTagsFormat(option string, mapTags map[string]string) ([]string, error) {
 	tagsLength := len(mapTags)
 	if tagsLength == 0 {
@@ -53,10 +55,16 @@ func MapToBarmanTagsFormat(option string, mapTags map[string]string) ([]string,
 		return []string{}, fmt.Errorf("could not list barman tags: %w", cnpgerrors.ErrMemoryAllocation)
 	}
 
+	keys := make([]string, 0, tagsLength)
+	for k := range mapTags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
 	tags := make([]string, 0, tagsLength+1)
 	tags = append(tags, option)
-	for k, v := range mapTags {
-		tags = append(tags, fmt.Sprintf("%v,%v", k, v))
+	for _, k := range keys {
+		tags = append(tags, fmt.Sprintf("%v,%v", k, mapTags[k]))
 	}
 
 	return tags, nil
@@ -50,4 +50,11 @@ var _ = Describe("converting map to barman tags format", func() {
 		tags := map[string]string{"retentionDays": "90days"}
 		Expect(MapToBarmanTagsFormat("test", tags)).To(BeEquivalentTo([]string{"test", "retentionDays,90days"}))
 	})
+
+	It("sorts the tags by key", func() {
+		tags := map[string]string{"retentionDays": "90days", "environment": "production", "team": "dba"}
+		Expect(MapToBarmanTagsFormat("test", tags)).To(Equal([]string{
+			"test", "environment,production", "retentionDays,90days", "team,dba",
+		}))
+	})
 })
@@ -20,8 +20,8 @@ import (
 	batchv1 "k8s.io/api/batch/v1"
 )
 
-// jobHasOne Completion check if a certain job is complete
-func jobHasOneCompletion(job batchv1.Job) bool {
+// JobHasOneCompletion check if a certain job is complete
+func JobHasOneCompletion(job batchv1.Job) bool {
 	requestedCompletions := int32(1)
 	if job.Spec.Completions != nil {
 		requestedCompletions = *job.Spec.Completions
@@ -33,7 +33,7 @@ func jobHasOneCompletion(job batchv1.Job) bool {
 func FilterJobsWithOneCompletion(jobList []batchv1.Job) []batchv1.Job {
 	var result []batchv1.Job
 	for _, job := range jobList {
-		if jobHasOneCompletion(job) {
+		if JobHasOneCompletion(job) {
 			result = append(result, job)
 		}
 	}
@@ -45,7 +45,7 @@ func CountJobsWithOneCompletion(jobList []batchv1.Job) int {
 	result := 0
 
 	for _, job := range jobList {
-		if jobHasOneCompletion(job) {
+		if JobHasOneCompletion(job) {
 			result++
 		}
 	}
@@ -18,6 +18,7 @@ package utils
 
 import (
 	"reflect"
+	"strconv"
 	"strings"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -49,12 +50,20 @@ const (
 	// PvcRoleLabelName is the name of the label containing the purpose of the pvc
 	PvcRoleLabelName = "cnpg.io/pvcRole"
 
+	// TablespaceNameLabelName is the name of the label containing the
+	// name of the tablespace stored in a pvc
+	TablespaceNameLabelName = "cnpg.io/tablespaceName"
+
 	// PodRoleLabelName is the name of the label containing the podRole value
 	PodRoleLabelName = "cnpg.io/podRole"
 
 	// InstanceNameLabelName is the name of the label containing the instance name
 	InstanceNameLabelName = "cnpg.io/instanceName"
 
+	// ReadReplicaLabelName is the name of the label marking the Pods and
+	// the PVCs of the read replicas, which are never promoted
+	ReadReplicaLabelName = "cnpg.io/readReplica"
+
 	// OperatorVersionAnnotationName is the name of the annotation containing
 	// the version of the operator that generated a certain object
 	OperatorVersionAnnotationName = "cnpg.io/operatorVersion"
@@ -76,8 +85,44 @@ const (
 	// PodEnvHashAnnotationName is the name of the annotation containing the podEnvHash value
 	PodEnvHashAnnotationName = "cnpg.io/podEnvHash"
 
+	// PodConfigHashAnnotationName is the name of the annotation containing
+	// the hash of the configuration managed by the operator when the Pod
+	// has been created
+	PodConfigHashAnnotationName = "cnpg.io/podConfigHash"
+
+	// PodSidecarsHashAnnotationName is the name of the annotation containing
+	// the hash of the sidecars and of their volumes when the Pod has been created
+	PodSidecarsHashAnnotationName = "cnpg.io/podSidecarsHash"
+
+	// PodMountedObjectsHashAnnotationName is the name of the annotation
+	// containing the hash of the content of the mounted secrets and config
+	// maps requiring a restart, when the Pod has been created
+	PodMountedObjectsHashAnnotationName = "cnpg.io/podMountedObjectsHash"
+
+	// FailoverPriorityAnnotationName is the name of the annotation containing
+	// the priority of an instance when electing the new primary among the
+	// replicas that received the same WAL. Higher values are preferred
+	FailoverPriorityAnnotationName = "cnpg.io/failoverPriority"
+
+	// DefaultedFieldsAnnotationName is the name of the annotation listing
+	// the fields of a cluster specification that have been filled by the
+	// defaulting webhook
+	DefaultedFieldsAnnotationName = "cnpg.io/defaultedFields"
+
 	// skipEmptyWalArchiveCheck turns off the checks that ensure that the WAL archive is empty before writing data
 	skipEmptyWalArchiveCheck = "cnpg.io/skipEmptyWalArchiveCheck"
+
+	// skipQuorumInstancesCheck turns off the check that the number of instances
+	// is odd when synchronous replication is enabled
+	skipQuorumInstancesCheck = "cnpg.io/skipQuorumInstancesCheck"
+
+	// urgentRollout allows the rolling updates of a cluster outside of its
+	// maintenance window
+	urgentRollout = "cnpg.io/urgentRollout"
+
+	// suspendWalArchiving temporarily turns the WAL archiving of a
+	// cluster into a no-op
+	suspendWalArchiving = "cnpg.io/suspendWalArchiving"
 )
 
 type annotationStatus string
@@ -103,6 +148,8 @@ const (
 	PVCRolePgData PVCRole = "PG_DATA"
 	// PVCRolePgWal is a PVC used for storing PG_WAL
 	PVCRolePgWal PVCRole = "PG_WAL"
+	// PVCRolePgTablespace is a PVC used for storing a tablespace
+	PVCRolePgTablespace PVCRole = "PG_TABLESPACE"
 )
 
 // LabelClusterName labels the object with the cluster name
@@ -225,6 +272,41 @@ func IsEmptyWalArchiveCheckEnabled(object *metav1.ObjectMeta) bool {
 	return object.Annotations[skipEmptyWalArchiveCheck] != string(annotationStatusEnabled)
 }
 
+// IsQuorumInstancesCheckEnabled returns a boolean indicating if we should
+// check that the number of instances is suitable for a quorum
+func IsQuorumInstancesCheckEnabled(object *metav1.ObjectMeta) bool {
+	return object.Annotations[skipQuorumInstancesCheck] != string(annotationStatusEnabled)
+}
+
+// IsUrgentRolloutRequested returns a boolean indicating if the rolling
+// updates should be applied without waiting for the maintenance window
+func IsUrgentRolloutRequested(object *metav1.ObjectMeta) bool {
+	return object.Annotations[urgentRollout] == string(annotationStatusEnabled)
+}
+
+// IsWalArchivingSuspended returns a boolean indicating if the WAL
+// files should be skipped instead of being archived
+func IsWalArchivingSuspended(object *metav1.ObjectMeta) bool {
+	return object.Annotations[suspendWalArchiving] == string(annotationStatusEnabled)
+}
+
+// GetFailoverPriority gets the failover priority of an instance from the
+// annotations of its Pod, defaulting to zero when missing or invalid
+func GetFailoverPriority(object *metav1.ObjectMeta) int {
+	priority, err := strconv.Atoi(object.Annotations[FailoverPriorityAnnotationName])
+	if err != nil {
+		return 0
+	}
+
+	return priority
+}
+
+// IsReadReplica checks if the passed object belongs to a read replica,
+// that is an instance which is never promoted to primary
+func IsReadReplica(object *metav1.ObjectMeta) bool {
+	return object.Labels[ReadReplicaLabelName] == "true"
+}
+
 // MergeMap transfers the content of a giver map to a receiver
 func MergeMap(receiver, giver map[string]string) {
 	for key, value := range giver {
@@ -121,3 +121,31 @@ var _ = Describe("Annotate pods management", func() {
 		Expect(pod.ObjectMeta.Annotations[AppArmorAnnotationPrefix+"/apparmor_profile"]).To(Equal("unconfined"))
 	})
 })
+
+var _ = Describe("Failover priority annotation", func() {
+	It("reads the priority of the instance", func() {
+		object := metav1.ObjectMeta{
+			Annotations: map[string]string{FailoverPriorityAnnotationName: "10"},
+		}
+		Expect(GetFailoverPriority(&object)).To(Equal(10))
+	})
+
+	It("defaults to zero when the annotation is missing or invalid", func() {
+		Expect(GetFailoverPriority(&metav1.ObjectMeta{})).To(BeZero())
+
+		object := metav1.ObjectMeta{
+			Annotations: map[string]string{FailoverPriorityAnnotationName: "high"},
+		}
+		Expect(GetFailoverPriority(&object)).To(BeZero())
+	})
+})
+
+var _ = Describe("Read replica label", func() {
+	It("detects the objects belonging to a read replica", func() {
+		object := metav1.ObjectMeta{
+			Labels: map[string]string{ReadReplicaLabelName: "true"},
+		}
+		Expect(IsReadReplica(&object)).To(BeTrue())
+		Expect(IsReadReplica(&metav1.ObjectMeta{})).To(BeFalse())
+	})
+})
@@ -17,6 +17,8 @@ limitations under the License.
 package utils
 
 import (
+	"time"
+
 	corev1 "k8s.io/api/core/v1"
 
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
@@ -55,6 +57,30 @@ func IsPodReady(pod corev1.Pod) bool {
 	return false
 }
 
+// GetPodNotReadyDuration gets the amount of time a Pod has been not ready,
+// which is zero for ready Pods. Pods which never reported their readiness
+// are considered not ready since their creation
+func GetPodNotReadyDuration(pod corev1.Pod, now time.Time) time.Duration {
+	since := pod.CreationTimestamp.Time
+	for _, c := range pod.Status.Conditions {
+		if c.Type != corev1.ContainersReady {
+			continue
+		}
+		if c.Status == corev1.ConditionTrue {
+			return 0
+		}
+		if !c.LastTransitionTime.IsZero() {
+			since = c.LastTransitionTime.Time
+		}
+	}
+
+	if since.IsZero() || now.Before(since) {
+		return 0
+	}
+
+	return now.Sub(since)
+}
+
 // IsPodActive checks if a pod is active, copied from:
 // https://github.com/kubernetes/kubernetes/blob/1bd0077/test/e2e/framework/pod/resource.go#L664
 func IsPodActive(p corev1.Pod) bool {
@@ -17,6 +17,8 @@ limitations under the License.
 package utils
 
 import (
+	"time"
+
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
@@ -157,4 +159,50 @@ var _ = Describe("Pod conditions test suite", func() {
 		}
 		Expect(IsPodEvicted(pod)).To(BeFalse())
 	})
+
+	Describe("Must measure how long a pod has been not ready", func() {
+		now := time.Now()
+
+		It("returns zero for ready pods", func() {
+			pod := corev1.Pod{
+				Status: corev1.PodStatus{
+					Conditions: []corev1.PodCondition{
+						{
+							Type:               corev1.ContainersReady,
+							Status:             corev1.ConditionTrue,
+							LastTransitionTime: metav1.NewTime(now.Add(-time.Hour)),
+						},
+					},
+				},
+			}
+			Expect(GetPodNotReadyDuration(pod, now)).To(BeZero())
+		})
+
+		It("measures from the last readiness transition", func() {
+			pod := corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					CreationTimestamp: metav1.NewTime(now.Add(-time.Hour)),
+				},
+				Status: corev1.PodStatus{
+					Conditions: []corev1.PodCondition{
+						{
+							Type:               corev1.ContainersReady,
+							Status:             corev1.ConditionFalse,
+							LastTransitionTime: metav1.NewTime(now.Add(-10 * time.Second)),
+						},
+					},
+				},
+			}
+			Expect(GetPodNotReadyDuration(pod, now)).To(Equal(10 * time.Second))
+		})
+
+		It("measures from the creation when the readiness is not reported", func() {
+			pod := corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{
+					CreationTimestamp: metav1.NewTime(now.Add(-time.Minute)),
+				},
+			}
+			Expect(GetPodNotReadyDuration(pod, now)).To(Equal(time.Minute))
+		})
+	})
 })